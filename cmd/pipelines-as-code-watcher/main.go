@@ -44,5 +44,5 @@ func main() {
 	}()
 	<-c
 
-	sharedmain.Main("pac-watcher", reconciler.NewController())
+	sharedmain.Main("pac-watcher", reconciler.NewController(), reconciler.NewRepositoryController())
 }
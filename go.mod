@@ -13,6 +13,7 @@ require (
 	github.com/gobwas/glob v0.2.3
 	github.com/google/cel-go v0.13.0
 	github.com/google/go-cmp v0.5.9
+	github.com/google/go-containerregistry v0.13.0
 	github.com/google/go-github/scrape v0.0.0-20230123191529-2561c07393f1
 	github.com/google/go-github/v48 v48.2.0
 	github.com/hako/durafmt v0.0.0-20210608085754-5c1018a4e16b
@@ -74,7 +75,6 @@ require (
 	github.com/golang/groupcache v0.0.0-20210331224755-41bb18bfe9da // indirect
 	github.com/golang/protobuf v1.5.2 // indirect
 	github.com/google/gnostic v0.6.9 // indirect
-	github.com/google/go-containerregistry v0.13.0 // indirect
 	github.com/google/go-github/v49 v49.1.0
 	github.com/google/go-querystring v1.1.0 // indirect
 	github.com/google/gofuzz v1.2.0 // indirect
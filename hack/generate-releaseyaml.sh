@@ -8,6 +8,12 @@ export TARGET_BRANCH=${TARGET_BRANCH:-main}
 export TARGET_NAMESPACE=${TARGET_NAMESPACE:-pipelines-as-code}
 export TARGET_OPENSHIFT=${TARGET_OPENSHIFT:-""}
 export TARGET_PAC_VERSION=${PAC_VERSION:-"devel"}
+# expose the controller with an Ingress on Kubernetes, with a cert-manager
+# Certificate when an issuer is set
+export TARGET_INGRESS_HOST=${TARGET_INGRESS_HOST:-""}
+export TARGET_INGRESS_CLASS=${TARGET_INGRESS_CLASS:-""}
+export TARGET_CERT_MANAGER_ISSUER=${TARGET_CERT_MANAGER_ISSUER:-""}
+export TARGET_CERT_MANAGER_ISSUER_KIND=${TARGET_CERT_MANAGER_ISSUER_KIND:-"ClusterIssuer"}
 
 TMP=$(mktemp /tmp/.mm.XXXXXX)
 clean() { rm -f ${TMP}; }
@@ -34,6 +40,11 @@ else
     fi
 fi
 
+if [[ -z ${TARGET_OPENSHIFT} && -n ${TARGET_INGRESS_HOST} ]];then
+    files="${files} config/kubernetes/10-ingress.yaml"
+    [[ -n ${TARGET_CERT_MANAGER_ISSUER} ]] && files="${files} config/kubernetes/20-certificate.yaml"
+fi
+
 
 for file in ${files};do
     sed -e '/^$/d' -e '/^#/d' ${file} | head -1 | grep -q -- "---" || echo -e "---\n"
@@ -45,8 +56,17 @@ for file in ${files};do
         -e "s/Copyright[ ]*[0-9]{4}/Copyright $(date "+%Y")/" \
         -e "/kind: Namespace$/ { n;n;s/name: .*/name: ${TARGET_NAMESPACE}/;}" \
         -e "s/\"devel\"/\"${TARGET_PAC_VERSION}\"/" \
+        -e "s/INGRESS_HOST/${TARGET_INGRESS_HOST}/g" \
+        -e "s/INGRESS_CLASS/${TARGET_INGRESS_CLASS}/" \
+        -e "s/CERT_MANAGER_ISSUER_KIND/${TARGET_CERT_MANAGER_ISSUER_KIND}/" \
+        -e "s/CERT_MANAGER_ISSUER/${TARGET_CERT_MANAGER_ISSUER}/" \
         ${file} > ${TMP}
 
+    # Remove the ingress class when not set and the tls when there is no
+    # certificate for the ingress
+    [[ -z ${TARGET_INGRESS_CLASS} ]] && sed -i '/ingressClassName:/d' ${TMP}
+    [[ -z ${TARGET_CERT_MANAGER_ISSUER} ]] && sed -i '/^  tls:/,$d' ${TMP}
+
     # Remove openshift stuff apiGroups if we are not targetting openshift...
     [[ -z ${TARGET_OPENSHIFT} ]] && {
         sed -ir '/^[ ]*- apiGroups:.*route.openshift.io/,/verbs.*/d' ${TMP}
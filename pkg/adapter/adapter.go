@@ -37,10 +37,13 @@ func NewEnvConfig() adapter.EnvConfigAccessor {
 }
 
 type listener struct {
-	run    *params.Run
-	kint   kubeinteraction.Interface
-	logger *zap.SugaredLogger
-	event  *info.Event
+	run        *params.Run
+	kint       kubeinteraction.Interface
+	logger     *zap.SugaredLogger
+	event      *info.Event
+	deliveries *deliveryCache
+	debouncer  *pullRequestDebouncer
+	audit      *auditLog
 }
 
 type Response struct {
@@ -53,9 +56,12 @@ var _ adapter.Adapter = (*listener)(nil)
 func New(run *params.Run, k *kubeinteraction.Interaction) adapter.AdapterConstructor {
 	return func(ctx context.Context, processed adapter.EnvConfigAccessor, ceClient cloudevents.Client) adapter.Adapter {
 		return &listener{
-			logger: logging.FromContext(ctx),
-			run:    run,
-			kint:   k,
+			logger:     logging.FromContext(ctx),
+			run:        run,
+			kint:       k,
+			deliveries: newDeliveryCache(),
+			debouncer:  newPullRequestDebouncer(),
+			audit:      newAuditLog(),
 		}
 	}
 }
@@ -137,6 +143,11 @@ func (l listener) handleEvent(ctx context.Context) http.HandlerFunc {
 
 		l.event = info.NewEvent()
 
+		if isGitHubPing(request) {
+			l.handleGitHubPing(ctx, response, request, payload)
+			return
+		}
+
 		// if repository auto configuration is enabled then check if its a valid event
 		if l.run.Info.Pac.AutoConfigureNewGitHubRepo {
 			detected, configuring, err := github.ConfigureRepository(ctx, l.run, request, string(payload), l.logger)
@@ -173,13 +184,23 @@ func (l listener) handleEvent(ctx context.Context) http.HandlerFunc {
 			return
 		}
 
+		// skip the deliveries retried by the provider since the event has
+		// already been processed
+		if l.isDuplicateDelivery(request) {
+			logger.Infof("skipping event: delivery %s has already been received", deliveryID(request.Header))
+			l.writeResponse(response, http.StatusOK, "skipped duplicate delivery")
+			return
+		}
+
 		s := sinker{
-			run:     l.run,
-			vcx:     gitProvider,
-			kint:    l.kint,
-			event:   l.event,
-			logger:  logger,
-			payload: payload,
+			run:       l.run,
+			vcx:       gitProvider,
+			kint:      l.kint,
+			event:     l.event,
+			logger:    logger,
+			payload:   payload,
+			debouncer: l.debouncer,
+			audit:     l.audit,
 		}
 
 		// clone the request to use it further
@@ -189,6 +210,8 @@ func (l listener) handleEvent(ctx context.Context) http.HandlerFunc {
 			err := s.processEvent(ctx, localRequest)
 			if err != nil {
 				logger.Errorf("an error occurred: %v", err)
+				// a retry or a redelivery of the event should be processed
+				l.forgetDelivery(localRequest)
 			}
 		}()
 
@@ -196,6 +219,30 @@ func (l listener) handleEvent(ctx context.Context) http.HandlerFunc {
 	}
 }
 
+// isDuplicateDelivery returns true if the webhook delivery has already been
+// received within the deduplication window of the settings.
+func (l listener) isDuplicateDelivery(request *http.Request) bool {
+	if l.deliveries == nil || l.run.Info.Pac.WebhookDeduplicationWindow <= 0 {
+		return false
+	}
+	id := deliveryID(request.Header)
+	if id == "" {
+		return false
+	}
+	return l.deliveries.isDuplicate(id, l.run.Info.Pac.WebhookDeduplicationWindow, time.Now())
+}
+
+// forgetDelivery forgets the webhook delivery, so it isn't skipped when it is
+// delivered again.
+func (l listener) forgetDelivery(request *http.Request) {
+	if l.deliveries == nil {
+		return
+	}
+	if id := deliveryID(request.Header); id != "" {
+		l.deliveries.forget(id)
+	}
+}
+
 func (l listener) processRes(processEvent bool, provider provider.Interface, logger *zap.SugaredLogger, skipReason string, err error) (provider.Interface, *zap.SugaredLogger, error) {
 	if processEvent {
 		provider.SetLogger(logger)
@@ -223,6 +270,7 @@ func (l listener) detectProvider(req *http.Request, reqBody string) (provider.In
 	}
 
 	gitHub := github.New()
+	gitHub.Run = l.run
 	isGH, processReq, logger, reason, err := gitHub.Detect(req, reqBody, &log)
 	if isGH {
 		return l.processRes(processReq, gitHub, logger, reason, err)
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/google/go-github/v49/github"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
@@ -36,11 +37,13 @@ func TestHandleEvent(t *testing.T) {
 				Pac: &info.PacOpts{
 					Settings: &settings.Settings{
 						AutoConfigureNewGitHubRepo: false,
+						WebhookDeduplicationWindow: time.Minute,
 					},
 				},
 			},
 		},
-		logger: logger,
+		logger:     logger,
+		deliveries: newDeliveryCache(),
 	}
 
 	ts := httptest.NewServer(l.handleEvent(ctx))
@@ -60,6 +63,7 @@ func TestHandleEvent(t *testing.T) {
 		event       []byte
 		eventType   string
 		requestType string
+		deliveryID  string
 		statusCode  int
 	}{
 		{
@@ -87,6 +91,22 @@ func TestHandleEvent(t *testing.T) {
 			event:       event,
 			statusCode:  202,
 		},
+		{
+			name:        "first delivery",
+			requestType: "POST",
+			eventType:   "push",
+			deliveryID:  "72d3162e",
+			event:       event,
+			statusCode:  202,
+		},
+		{
+			name:        "duplicate delivery",
+			requestType: "POST",
+			eventType:   "push",
+			deliveryID:  "72d3162e",
+			event:       event,
+			statusCode:  200,
+		},
 		{
 			name:        "skip event",
 			requestType: "POST",
@@ -110,6 +130,9 @@ func TestHandleEvent(t *testing.T) {
 				t.Fatalf("error creating request: %s", err)
 			}
 			req.Header.Set("X-Github-Event", tt.eventType)
+			if tt.deliveryID != "" {
+				req.Header.Set("X-GitHub-Delivery", tt.deliveryID)
+			}
 
 			resp, err := http.DefaultClient.Do(req)
 			if err != nil {
@@ -0,0 +1,164 @@
+package adapter
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+)
+
+// auditEntry is a line of the audit log, an event processed by the controller
+// and what has been decided for it.
+type auditEntry struct {
+	Time              time.Time `json:"time"`
+	DeliveryID        string    `json:"delivery_id,omitempty"`
+	Provider          string    `json:"provider,omitempty"`
+	EventType         string    `json:"event_type,omitempty"`
+	TriggerTarget     string    `json:"trigger_target,omitempty"`
+	URL               string    `json:"url,omitempty"`
+	SHA               string    `json:"sha,omitempty"`
+	Sender            string    `json:"sender,omitempty"`
+	BaseBranch        string    `json:"base_branch,omitempty"`
+	HeadBranch        string    `json:"head_branch,omitempty"`
+	PullRequestNumber int       `json:"pull_request_number,omitempty"`
+	Repository        string    `json:"repository,omitempty"`
+	Decision          string    `json:"decision"`
+	Reason            string    `json:"reason,omitempty"`
+	PipelineRuns      []string  `json:"pipelineruns,omitempty"`
+}
+
+// auditLog is the append only audit log of the events processed by the
+// controller, a json document per line. The file is rotated when it reaches
+// its max size, the rotated files are numbered from the most recent one, ie:
+// audit.jsonl.1, gzipped when compressing, and the oldest ones beyond the max
+// number of files are removed.
+type auditLog struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+	size int64
+}
+
+func newAuditLog() *auditLog {
+	return &auditLog{}
+}
+
+// write appends the entry to the audit log of the settings, nothing is written
+// when the audit log is disabled.
+func (a *auditLog) write(pacSettings *settings.Settings, entry auditEntry) error {
+	if a == nil || pacSettings == nil || pacSettings.AuditLogPath == "" {
+		return nil
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.open(pacSettings.AuditLogPath); err != nil {
+		return err
+	}
+	maxSize := int64(pacSettings.AuditLogMaxSize) * 1024 * 1024
+	if maxSize > 0 && a.size > 0 && a.size+int64(len(line)) > maxSize {
+		if err := a.rotate(pacSettings.AuditLogMaxFiles, pacSettings.AuditLogCompress); err != nil {
+			return err
+		}
+		if err := a.open(pacSettings.AuditLogPath); err != nil {
+			return err
+		}
+	}
+	n, err := a.file.Write(line)
+	a.size += int64(n)
+	return err
+}
+
+// open opens the audit log file in append mode, the one opened before is
+// closed when the path changed in the settings.
+func (a *auditLog) open(path string) error {
+	if a.file != nil && a.path == path {
+		return nil
+	}
+	a.close()
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("cannot create the directory of the audit log %s: %w", path, err)
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o640)
+	if err != nil {
+		return fmt.Errorf("cannot open the audit log %s: %w", path, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	a.file, a.path, a.size = file, path, info.Size()
+	return nil
+}
+
+func (a *auditLog) close() {
+	if a.file != nil {
+		a.file.Close()
+	}
+	a.file, a.path, a.size = nil, "", 0
+}
+
+// rotatedName is the name of the rotated file of the audit log at a position,
+// 1 being the most recent one.
+func rotatedName(path string, position int, compress bool) string {
+	name := fmt.Sprintf("%s.%d", path, position)
+	if compress {
+		name += ".gz"
+	}
+	return name
+}
+
+// rotate moves the current audit log to the first rotated file, shifting the
+// other rotated files and removing the ones beyond maxFiles.
+func (a *auditLog) rotate(maxFiles int, compress bool) error {
+	path := a.path
+	a.close()
+	if maxFiles < 1 {
+		maxFiles = 1
+	}
+	if err := os.Remove(rotatedName(path, maxFiles, compress)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for i := maxFiles - 1; i >= 1; i-- {
+		if err := os.Rename(rotatedName(path, i, compress), rotatedName(path, i+1, compress)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if !compress {
+		return os.Rename(path, rotatedName(path, 1, false))
+	}
+	if err := gzipFile(path, rotatedName(path, 1, true)); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+func gzipFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o640)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	gz := gzip.NewWriter(out)
+	if _, err := io.Copy(gz, in); err != nil {
+		return err
+	}
+	return gz.Close()
+}
@@ -0,0 +1,87 @@
+package adapter
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"gotest.tools/v3/assert"
+)
+
+// readAuditEntries reads the entries of an audit log file, gunzipping it when
+// it is compressed.
+func readAuditEntries(t *testing.T, path string) []auditEntry {
+	t.Helper()
+	f, err := os.Open(path)
+	assert.NilError(t, err)
+	defer f.Close()
+	var scanner *bufio.Scanner
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		assert.NilError(t, err)
+		scanner = bufio.NewScanner(gz)
+	} else {
+		scanner = bufio.NewScanner(f)
+	}
+	scanner.Buffer(make([]byte, 1024*1024), 2*1024*1024)
+	entries := []auditEntry{}
+	for scanner.Scan() {
+		entry := auditEntry{}
+		assert.NilError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		entries = append(entries, entry)
+	}
+	assert.NilError(t, scanner.Err())
+	return entries
+}
+
+func TestAuditLogDisabled(t *testing.T) {
+	audit := newAuditLog()
+	assert.NilError(t, audit.write(&settings.Settings{}, auditEntry{Decision: "started"}))
+	assert.NilError(t, audit.write(nil, auditEntry{Decision: "started"}))
+	assert.Assert(t, audit.file == nil)
+}
+
+func TestAuditLogWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit", "audit.jsonl")
+	pacSettings := &settings.Settings{AuditLogPath: path, AuditLogMaxSize: 1, AuditLogMaxFiles: 2}
+	audit := newAuditLog()
+	assert.NilError(t, audit.write(pacSettings, auditEntry{SHA: "a", Decision: "started", PipelineRuns: []string{"pr-1"}}))
+	assert.NilError(t, audit.write(pacSettings, auditEntry{SHA: "b", Decision: "no-match"}))
+
+	// appends to the existing file after a restart
+	audit = newAuditLog()
+	assert.NilError(t, audit.write(pacSettings, auditEntry{SHA: "c", Decision: "skipped"}))
+
+	entries := readAuditEntries(t, path)
+	assert.Equal(t, len(entries), 3)
+	assert.Equal(t, entries[0].SHA, "a")
+	assert.DeepEqual(t, entries[0].PipelineRuns, []string{"pr-1"})
+	assert.Equal(t, entries[2].Decision, "skipped")
+}
+
+func TestAuditLogRotate(t *testing.T) {
+	for _, compress := range []bool{false, true} {
+		t.Run(map[bool]string{false: "plain", true: "gzip"}[compress], func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "audit.jsonl")
+			pacSettings := &settings.Settings{AuditLogPath: path, AuditLogMaxSize: 1, AuditLogMaxFiles: 2, AuditLogCompress: compress}
+			audit := newAuditLog()
+			// each entry is bigger than half of the max size, a file holds a
+			// single entry
+			reason := strings.Repeat("x", 600*1024)
+			for _, sha := range []string{"a", "b", "c", "d"} {
+				assert.NilError(t, audit.write(pacSettings, auditEntry{SHA: sha, Decision: "error", Reason: reason}))
+			}
+
+			assert.Equal(t, readAuditEntries(t, path)[0].SHA, "d")
+			assert.Equal(t, readAuditEntries(t, rotatedName(path, 1, compress))[0].SHA, "c")
+			assert.Equal(t, readAuditEntries(t, rotatedName(path, 2, compress))[0].SHA, "b")
+			_, err := os.Stat(rotatedName(path, 3, compress))
+			assert.Assert(t, os.IsNotExist(err))
+		})
+	}
+}
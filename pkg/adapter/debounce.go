@@ -0,0 +1,73 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+)
+
+// pullRequestDebouncer remembers the last commit pushed to the pull requests
+// so the pushes superseded by another one within the debounce window are
+// skipped and only the last commit is run.
+type pullRequestDebouncer struct {
+	mu     sync.Mutex
+	latest map[string]string
+}
+
+func newPullRequestDebouncer() *pullRequestDebouncer {
+	return &pullRequestDebouncer{latest: map[string]string{}}
+}
+
+// push records the sha as the last commit pushed to the pull request.
+func (d *pullRequestDebouncer) push(key, sha string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.latest[key] = sha
+}
+
+// isLatest returns true if the sha is still the last commit pushed to the
+// pull request, the pull request is then forgotten.
+func (d *pullRequestDebouncer) isLatest(key, sha string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	latest, ok := d.latest[key]
+	if !ok {
+		return true
+	}
+	if latest != sha {
+		return false
+	}
+	delete(d.latest, key)
+	return true
+}
+
+// isPullRequestPush returns true if the event is a commit pushed to a pull
+// request, the GitOps comments and the reviews are not.
+func isPullRequestPush(event *info.Event) bool {
+	if event.TriggerTarget != "pull_request" || event.PullRequestNumber == 0 {
+		return false
+	}
+	// "Merge Request" is the event type of the merge requests on GitLab
+	return event.EventType == "pull_request" || event.EventType == "Merge Request"
+}
+
+// isSuperseded waits for the debounce window of the settings when the event is
+// a commit pushed to a pull request and returns true if another commit has
+// been pushed to the same pull request in the meantime.
+func (s *sinker) isSuperseded(ctx context.Context) bool {
+	window := s.run.Info.Pac.PullRequestDebounceWindow
+	if s.debouncer == nil || window <= 0 || !isPullRequestPush(s.event) {
+		return false
+	}
+	key := fmt.Sprintf("%s#%d", s.event.URL, s.event.PullRequestNumber)
+	s.debouncer.push(key, s.event.SHA)
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(window):
+	}
+	return !s.debouncer.isLatest(key, s.event.SHA)
+}
@@ -0,0 +1,84 @@
+package adapter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"gotest.tools/v3/assert"
+)
+
+func TestPullRequestDebouncer(t *testing.T) {
+	d := newPullRequestDebouncer()
+	d.push("repo#1", "sha1")
+	d.push("repo#1", "sha2")
+	d.push("repo#2", "sha3")
+	assert.Assert(t, !d.isLatest("repo#1", "sha1"))
+	assert.Assert(t, d.isLatest("repo#1", "sha2"))
+	assert.Assert(t, d.isLatest("repo#2", "sha3"))
+	assert.Equal(t, len(d.latest), 0)
+	// nothing has been pushed since
+	assert.Assert(t, d.isLatest("repo#1", "sha2"))
+}
+
+func TestIsPullRequestPush(t *testing.T) {
+	tests := []struct {
+		name  string
+		event *info.Event
+		want  bool
+	}{
+		{
+			name:  "pull request",
+			event: &info.Event{TriggerTarget: "pull_request", EventType: "pull_request", PullRequestNumber: 1},
+			want:  true,
+		},
+		{
+			name:  "gitlab merge request",
+			event: &info.Event{TriggerTarget: "pull_request", EventType: "Merge Request", PullRequestNumber: 1},
+			want:  true,
+		},
+		{
+			name:  "retest comment",
+			event: &info.Event{TriggerTarget: "pull_request", EventType: "retest-comment", PullRequestNumber: 1},
+		},
+		{
+			name:  "push",
+			event: &info.Event{TriggerTarget: "push", EventType: "push"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, isPullRequestPush(tt.event), tt.want)
+		})
+	}
+}
+
+func TestIsSuperseded(t *testing.T) {
+	run := &params.Run{Info: info.Info{Pac: &info.PacOpts{Settings: &settings.Settings{
+		PullRequestDebounceWindow: 50 * time.Millisecond,
+	}}}}
+	debouncer := newPullRequestDebouncer()
+	newSinker := func(sha string) *sinker {
+		return &sinker{run: run, debouncer: debouncer, event: &info.Event{
+			TriggerTarget:     "pull_request",
+			EventType:         "pull_request",
+			URL:               "https://github.com/owner/repo",
+			PullRequestNumber: 1,
+			SHA:               sha,
+		}}
+	}
+
+	first := make(chan bool)
+	go func() { first <- newSinker("sha1").isSuperseded(context.Background()) }()
+	// let the first push be recorded before the second one
+	time.Sleep(10 * time.Millisecond)
+	assert.Assert(t, !newSinker("sha2").isSuperseded(context.Background()))
+	assert.Assert(t, <-first)
+
+	// the debounce is disabled
+	run.Info.Pac.PullRequestDebounceWindow = 0
+	assert.Assert(t, !newSinker("sha3").isSuperseded(context.Background()))
+}
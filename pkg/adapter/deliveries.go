@@ -0,0 +1,68 @@
+package adapter
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// deliveryHeaders are the headers the Git providers set to the unique ID of
+// a webhook delivery, the ID stays the same when a delivery is retried or
+// redelivered.
+var deliveryHeaders = []string{
+	"X-GitHub-Delivery",   // GitHub, Gitea also sets it
+	"X-Gitea-Delivery",    // Gitea
+	"X-Gitlab-Event-UUID", // GitLab
+	"X-Request-UUID",      // Bitbucket Cloud
+}
+
+// deliveryID returns the delivery ID of a webhook request prefixed by the
+// header it comes from, or an empty string if the provider didn't set one.
+func deliveryID(header http.Header) string {
+	for _, name := range deliveryHeaders {
+		if id := header.Get(name); id != "" {
+			return name + ":" + id
+		}
+	}
+	return ""
+}
+
+// deliveryCache remembers the webhook deliveries received by the controller
+// to skip the ones delivered again within a window, the Git providers retry a
+// delivery when the controller is slow to respond and the event would then
+// create its PipelineRuns twice.
+type deliveryCache struct {
+	mu   sync.Mutex
+	seen map[string]time.Time
+}
+
+func newDeliveryCache() *deliveryCache {
+	return &deliveryCache{seen: map[string]time.Time{}}
+}
+
+// isDuplicate records the delivery and returns true if it has already been
+// received in the window before now, the delivery is recorded on arrival so
+// the retries received while it is processed are skipped. The deliveries older
+// than the window are forgotten.
+func (d *deliveryCache) isDuplicate(id string, window time.Duration, now time.Time) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for seenID, at := range d.seen {
+		if now.Sub(at) >= window {
+			delete(d.seen, seenID)
+		}
+	}
+	if _, ok := d.seen[id]; ok {
+		return true
+	}
+	d.seen[id] = now
+	return false
+}
+
+// forget drops the delivery, ie: when it could not be processed, so it is
+// processed again when the provider retries it or it is redelivered.
+func (d *deliveryCache) forget(id string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.seen, id)
+}
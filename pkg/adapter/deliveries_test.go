@@ -0,0 +1,56 @@
+package adapter
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestDeliveryID(t *testing.T) {
+	tests := []struct {
+		name   string
+		header http.Header
+		want   string
+	}{
+		{
+			name:   "github",
+			header: http.Header{"X-Github-Delivery": []string{"72d3162e"}},
+			want:   "X-GitHub-Delivery:72d3162e",
+		},
+		{
+			name:   "gitlab",
+			header: http.Header{"X-Gitlab-Event-Uuid": []string{"13792a34"}},
+			want:   "X-Gitlab-Event-UUID:13792a34",
+		},
+		{
+			name:   "request id is not a delivery id",
+			header: http.Header{"X-Request-Id": []string{"f2b1c3"}},
+		},
+		{
+			name:   "no delivery id",
+			header: http.Header{"X-Gitlab-Event": []string{"Push Hook"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, deliveryID(tt.header), tt.want)
+		})
+	}
+}
+
+func TestDeliveryCacheIsDuplicate(t *testing.T) {
+	window := 10 * time.Minute
+	now := time.Now()
+	d := newDeliveryCache()
+	assert.Assert(t, !d.isDuplicate("a", window, now))
+	assert.Assert(t, d.isDuplicate("a", window, now.Add(time.Minute)))
+	assert.Assert(t, !d.isDuplicate("b", window, now.Add(time.Minute)))
+	// forgotten once the window has passed
+	assert.Assert(t, !d.isDuplicate("a", window, now.Add(window)))
+	assert.Equal(t, len(d.seen), 2)
+	// a delivery which could not be processed is not skipped
+	d.forget("b")
+	assert.Assert(t, !d.isDuplicate("b", window, now.Add(window)))
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"crypto/subtle"
 	"fmt"
+	"net"
 	"net/http"
+	"time"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
@@ -29,11 +31,12 @@ func (l *listener) detectIncoming(ctx context.Context, req *http.Request, payloa
 	querySecret := req.URL.Query().Get("secret")
 	pipelineRun := req.URL.Query().Get("pipelinerun")
 	branch := req.URL.Query().Get("branch")
+	sha := req.URL.Query().Get("sha")
 	if req.URL.Path != "/incoming" {
 		return false, nil, nil
 	}
-	if pipelineRun == "" || repository == "" || querySecret == "" || branch == "" {
-		return false, nil, fmt.Errorf("missing query URL argument: pipelinerun, branch, repository, secret: %+v",
+	if pipelineRun == "" || repository == "" || branch == "" {
+		return false, nil, fmt.Errorf("missing query URL argument: pipelinerun, branch, repository: %+v",
 			req.URL.Query())
 	}
 
@@ -54,6 +57,26 @@ func (l *listener) detectIncoming(ctx context.Context, req *http.Request, payloa
 		return false, nil, fmt.Errorf("branch '%s' has not matched any rules in repo incoming webhooks spec: %+v", branch, *repo.Spec.Incomings)
 	}
 
+	var trustedProxies []*net.IPNet
+	if l.run.Info.Pac != nil && l.run.Info.Pac.Settings != nil {
+		trustedProxies = parseTrustedProxies(l.run.Info.Pac.IncomingTrustedProxies)
+	}
+	if err := checkIncomingSource(req, hook, trustedProxies); err != nil {
+		return false, nil, err
+	}
+
+	if !hook.AllowsPipelineRun(pipelineRun) {
+		return false, nil, fmt.Errorf("pipelinerun %s is not allowed by the incoming webhook rule of branch %s", pipelineRun, branch)
+	}
+
+	if err := checkIncomingRevision(sha, branch, hook); err != nil {
+		return false, nil, err
+	}
+
+	if !hook.SignedURLs && querySecret == "" {
+		return false, nil, fmt.Errorf("missing query URL argument: secret")
+	}
+
 	secretOpts := ktypes.GetSecretOpt{
 		Namespace: repo.Namespace,
 		Name:      hook.Secret.Name,
@@ -64,8 +87,14 @@ func (l *listener) detectIncoming(ctx context.Context, req *http.Request, payloa
 		return false, nil, fmt.Errorf("error getting secret referenced in incoming-webhook: %w", err)
 	}
 
-	// TODO: move to somewhere common to share between gitlab and here
-	if !compareSecret(querySecret, secretValue) {
+	// a signed URL is shared rather than the secret, it is checked
+	// instead of the secret which isn't in the URL
+	// TODO: move compareSecret to somewhere common to share between gitlab and here
+	if hook.SignedURLs {
+		if err := checkIncomingSignature(req, secretValue, time.Now()); err != nil {
+			return false, nil, err
+		}
+	} else if !compareSecret(querySecret, secretValue) {
 		return false, nil, fmt.Errorf("secret passed to the webhook is %s which does not match with the incoming webhook secret %s in %s", secretValue, querySecret, hook.Secret.Name)
 	}
 
@@ -91,6 +120,8 @@ func (l *listener) detectIncoming(ctx context.Context, req *http.Request, payloa
 	l.event.TargetPipelineRun = pipelineRun
 	l.event.HeadBranch = branch
 	l.event.BaseBranch = branch
+	// the commit is looked up from the head of the branch when not pinned
+	l.event.SHA = sha
 	l.event.Request.Header = req.Header
 	l.event.Request.Payload = payload
 	l.event.URL = repo.Spec.URL
@@ -0,0 +1,139 @@
+package adapter
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+)
+
+var fullSHARegexp = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// incomingSignature returns the signature of an incoming webhook URL: the hex
+// encoded HMAC-SHA256 with the secret of the repository, branch, PipelineRun
+// and expiration timestamp, followed by the pinned commit if any, separated by
+// new lines.
+func incomingSignature(secret, repository, branch, pipelineRun, expires, sha string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%s\n%s\n%s\n%s", repository, branch, pipelineRun, expires)
+	if sha != "" {
+		fmt.Fprintf(mac, "\n%s", sha)
+	}
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// checkIncomingSignature checks the signature of a signed incoming webhook URL
+// and that it isn't expired.
+func checkIncomingSignature(req *http.Request, secret string, now time.Time) error {
+	query := req.URL.Query()
+	expires, signature := query.Get("expires"), query.Get("signature")
+	if expires == "" || signature == "" {
+		return fmt.Errorf("the incoming webhook only accepts signed URLs, missing query URL argument: expires, signature")
+	}
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid expires query URL argument %s: %w", expires, err)
+	}
+	want := incomingSignature(secret, query.Get("repository"), query.Get("branch"), query.Get("pipelinerun"), expires, query.Get("sha"))
+	if !hmac.Equal([]byte(signature), []byte(want)) {
+		return fmt.Errorf("the signature of the incoming webhook URL does not match")
+	}
+	if now.Unix() > expiresAt {
+		return fmt.Errorf("the incoming webhook URL has expired on %s", time.Unix(expiresAt, 0).UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+// checkIncomingRevision checks the commit pinned with the sha query URL
+// argument is a full commit SHA and that the rule of the incoming webhook
+// allows to pin it.
+func checkIncomingRevision(sha, branch string, hook *v1alpha1.Incoming) error {
+	if sha == "" {
+		return nil
+	}
+	if !hook.AllowRevision {
+		return fmt.Errorf("the incoming webhook rule of branch %s doesn't allow to pin the commit to run on, allow_revision needs to be set", branch)
+	}
+	if !fullSHARegexp.MatchString(sha) {
+		return fmt.Errorf("invalid sha query URL argument %s, it needs to be a full commit SHA", sha)
+	}
+	return nil
+}
+
+// parseTrustedProxies parses the comma separated networks of the trusted
+// proxies, the invalid ones are refused when the settings are validated and
+// are skipped.
+func parseTrustedProxies(value string) []*net.IPNet {
+	networks := []*net.IPNet{}
+	for _, cidr := range strings.Split(value, ",") {
+		if _, network, err := net.ParseCIDR(strings.TrimSpace(cidr)); err == nil {
+			networks = append(networks, network)
+		}
+	}
+	return networks
+}
+
+func containsIP(networks []*net.IPNet, ip net.IP) bool {
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// requestSourceIP returns the address the request comes from: the remote
+// address of the connection, or when it is one of the trusted proxies the
+// last address of the X-Forwarded-For header which is not a trusted proxy.
+// The header is ignored for the other connections, anybody can set it.
+func requestSourceIP(req *http.Request, trustedProxies []*net.IPNet) net.IP {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !containsIP(trustedProxies, ip) {
+		return ip
+	}
+	forwarded := strings.Split(req.Header.Get("X-Forwarded-For"), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		address := strings.TrimSpace(forwarded[i])
+		if address == "" {
+			break
+		}
+		if ip = net.ParseIP(address); ip == nil || !containsIP(trustedProxies, ip) {
+			break
+		}
+	}
+	return ip
+}
+
+// checkIncomingSource checks the request comes from one of the networks the
+// incoming webhook can be called from.
+func checkIncomingSource(req *http.Request, hook *v1alpha1.Incoming, trustedProxies []*net.IPNet) error {
+	if len(hook.SourceCIDRs) == 0 {
+		return nil
+	}
+	ip := requestSourceIP(req, trustedProxies)
+	if ip == nil {
+		return fmt.Errorf("cannot get the source address of the incoming webhook request")
+	}
+	for _, cidr := range hook.SourceCIDRs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return fmt.Errorf("invalid source cidr %s in the incoming webhook: %w", cidr, err)
+		}
+		if network.Contains(ip) {
+			return nil
+		}
+	}
+	return fmt.Errorf("the incoming webhook cannot be called from %s", ip.String())
+}
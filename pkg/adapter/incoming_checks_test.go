@@ -0,0 +1,190 @@
+package adapter
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"gotest.tools/v3/assert"
+)
+
+func TestCheckIncomingSignature(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	signature := incomingSignature("secret", "repo", "main", "deploy", "1700000100", "")
+
+	tests := []struct {
+		name    string
+		query   string
+		wantErr string
+	}{
+		{
+			name:  "valid signature",
+			query: "repository=repo&branch=main&pipelinerun=deploy&expires=1700000100&signature=" + signature,
+		},
+		{
+			name:    "missing signature",
+			query:   "repository=repo&branch=main&pipelinerun=deploy&secret=secret",
+			wantErr: "the incoming webhook only accepts signed URLs, missing query URL argument: expires, signature",
+		},
+		{
+			name:    "signed for another pipelinerun",
+			query:   "repository=repo&branch=main&pipelinerun=release&expires=1700000100&signature=" + signature,
+			wantErr: "the signature of the incoming webhook URL does not match",
+		},
+		{
+			name:    "expiration changed",
+			query:   "repository=repo&branch=main&pipelinerun=deploy&expires=1800000000&signature=" + signature,
+			wantErr: "the signature of the incoming webhook URL does not match",
+		},
+		{
+			name: "expired",
+			query: "repository=repo&branch=main&pipelinerun=deploy&expires=1699999999&signature=" +
+				incomingSignature("secret", "repo", "main", "deploy", "1699999999", ""),
+			wantErr: "the incoming webhook URL has expired on 2023-11-14T22:13:19Z",
+		},
+		{
+			name: "valid signature with a pinned commit",
+			query: "repository=repo&branch=main&pipelinerun=deploy&expires=1700000100&sha=" + testSHA + "&signature=" +
+				incomingSignature("secret", "repo", "main", "deploy", "1700000100", testSHA),
+		},
+		{
+			name:    "commit pinned after the signature",
+			query:   "repository=repo&branch=main&pipelinerun=deploy&expires=1700000100&sha=" + testSHA + "&signature=" + signature,
+			wantErr: "the signature of the incoming webhook URL does not match",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", fmt.Sprintf("http://localhost/incoming?%s", tt.query), strings.NewReader(""))
+			err := checkIncomingSignature(req, "secret", now)
+			if tt.wantErr != "" {
+				assert.Error(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+		})
+	}
+}
+
+const testSHA = "0123456789abcdef0123456789abcdef01234567"
+
+func TestCheckIncomingRevision(t *testing.T) {
+	tests := []struct {
+		name    string
+		sha     string
+		hook    v1alpha1.Incoming
+		wantErr string
+	}{
+		{
+			name: "not pinned",
+		},
+		{
+			name: "pinned",
+			sha:  testSHA,
+			hook: v1alpha1.Incoming{AllowRevision: true},
+		},
+		{
+			name:    "not allowed",
+			sha:     testSHA,
+			wantErr: "the incoming webhook rule of branch main doesn't allow to pin the commit to run on, allow_revision needs to be set",
+		},
+		{
+			name:    "short sha",
+			sha:     "0123456",
+			hook:    v1alpha1.Incoming{AllowRevision: true},
+			wantErr: "invalid sha query URL argument 0123456, it needs to be a full commit SHA",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkIncomingRevision(tt.sha, "main", &tt.hook)
+			if tt.wantErr != "" {
+				assert.Error(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+		})
+	}
+}
+
+func TestCheckIncomingSource(t *testing.T) {
+	tests := []struct {
+		name           string
+		cidrs          []string
+		trustedProxies string
+		remoteAddr     string
+		xff            string
+		wantErr        string
+	}{
+		{
+			name:       "no restriction",
+			remoteAddr: "192.0.2.1:1234",
+		},
+		{
+			name:       "allowed remote address",
+			cidrs:      []string{"10.0.0.0/8", "192.0.2.0/24"},
+			remoteAddr: "192.0.2.1:1234",
+		},
+		{
+			name:           "allowed forwarded address",
+			cidrs:          []string{"10.0.0.0/8"},
+			trustedProxies: "192.0.2.0/24",
+			remoteAddr:     "192.0.2.1:1234",
+			xff:            "203.0.113.5, 10.1.2.3",
+		},
+		{
+			name:           "denied forwarded address",
+			cidrs:          []string{"10.0.0.0/8"},
+			trustedProxies: "10.1.2.3/32",
+			remoteAddr:     "10.1.2.3:1234",
+			xff:            "10.1.2.3, 203.0.113.5",
+			wantErr:        "the incoming webhook cannot be called from 203.0.113.5",
+		},
+		{
+			name:           "forwarded through several trusted proxies",
+			cidrs:          []string{"203.0.113.0/24"},
+			trustedProxies: "10.0.0.0/8, 192.0.2.0/24",
+			remoteAddr:     "192.0.2.1:1234",
+			xff:            "10.9.9.9, 203.0.113.5, 10.1.2.3",
+		},
+		{
+			name:       "forwarded address ignored from an untrusted connection",
+			cidrs:      []string{"10.0.0.0/8"},
+			remoteAddr: "203.0.113.5:1234",
+			xff:        "10.1.2.3",
+			wantErr:    "the incoming webhook cannot be called from 203.0.113.5",
+		},
+		{
+			name:           "forwarded address ignored from a connection not in the trusted proxies",
+			cidrs:          []string{"10.0.0.0/8"},
+			trustedProxies: "192.0.2.0/24",
+			remoteAddr:     "203.0.113.5:1234",
+			xff:            "10.1.2.3",
+			wantErr:        "the incoming webhook cannot be called from 203.0.113.5",
+		},
+		{
+			name:           "trusted proxy without forwarded address",
+			cidrs:          []string{"192.0.2.0/24"},
+			trustedProxies: "192.0.2.0/24",
+			remoteAddr:     "192.0.2.1:1234",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "http://localhost/incoming", strings.NewReader(""))
+			req.RemoteAddr = tt.remoteAddr
+			if tt.xff != "" {
+				req.Header.Set("X-Forwarded-For", tt.xff)
+			}
+			err := checkIncomingSource(req, &v1alpha1.Incoming{SourceCIDRs: tt.cidrs}, parseTrustedProxies(tt.trustedProxies))
+			if tt.wantErr != "" {
+				assert.Error(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-github/v49/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/matcher"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/pipelineascode"
+	ghprovider "github.com/openshift-pipelines/pipelines-as-code/pkg/provider/github"
+)
+
+// pingNotVerified is the answer to every ping which could not be verified, the
+// reason is only logged by the controller so an unauthenticated ping can't tell
+// which Repositories are configured.
+const pingNotVerified = "the ping could not be verified, the reason is in the logs of the controller"
+
+// isGitHubPing returns true if the request is the ping of a GitHub webhook.
+func isGitHubPing(request *http.Request) bool {
+	return request.Header.Get("X-GitHub-Event") == "ping"
+}
+
+// handleGitHubPing answers the ping of the GitHub webhook of a repository
+// after verifying a Repository matches it and the ping is signed with its
+// webhook secret, the response is recorded by GitHub with the delivery so
+// `tkn pac repository ping` can tell where the chain breaks. The pings of the
+// webhooks of organizations and GitHub Apps are only acknowledged. All the
+// failures are answered the same way before the signature is verified.
+func (l listener) handleGitHubPing(ctx context.Context, response http.ResponseWriter, request *http.Request, payload []byte) {
+	ping := &github.PingEvent{}
+	if err := json.Unmarshal(payload, ping); err != nil {
+		l.refusePing(response, "invalid ping: %v", err)
+		return
+	}
+	if ping.GetRepo().GetHTMLURL() == "" || ping.GetInstallation().GetID() != 0 {
+		l.writeResponse(response, http.StatusOK, "pong")
+		return
+	}
+
+	event := info.NewEvent()
+	event.URL = ping.GetRepo().GetHTMLURL()
+	event.Request = &info.Request{Header: request.Header, Payload: payload}
+	repo, err := matcher.MatchEventURLRepo(ctx, l.run, event, "")
+	if err != nil || repo == nil {
+		l.refusePing(response, "no Repository matches the URL %s", event.URL)
+		return
+	}
+
+	gitHub := ghprovider.New()
+	if err := pipelineascode.SecretFromRepository(ctx, l.run, l.kint, gitHub.GetConfig(), event, repo, l.logger); err != nil {
+		l.refusePing(response, "cannot read the secrets of Repository %s/%s: %v", repo.GetNamespace(), repo.GetName(), err)
+		return
+	}
+	if err := gitHub.Validate(ctx, l.run, event); err != nil {
+		l.refusePing(response, "the ping is not signed with the webhook secret of Repository %s/%s: %v", repo.GetNamespace(), repo.GetName(), err)
+		return
+	}
+	l.writeResponse(response, http.StatusOK, fmt.Sprintf("pong from Repository %s/%s", repo.GetNamespace(), repo.GetName()))
+}
+
+// refusePing logs why the ping could not be verified and answers it with the
+// generic message.
+func (l listener) refusePing(response http.ResponseWriter, format string, args ...interface{}) {
+	l.logger.Warnf("refusing the GitHub webhook ping: "+format, args...)
+	l.writeResponse(response, http.StatusUnauthorized, pingNotVerified)
+}
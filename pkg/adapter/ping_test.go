@@ -0,0 +1,108 @@
+package adapter
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-github/v49/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/test/kubernetestint"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/test/logger"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestHandleGitHubPing(t *testing.T) {
+	repoURL := "https://github.com/owner/repo"
+	tests := []struct {
+		name        string
+		ping        github.PingEvent
+		secret      string
+		wantStatus  int
+		wantMessage string
+	}{
+		{
+			name:        "organization webhook",
+			ping:        github.PingEvent{Org: &github.Organization{Login: github.String("owner")}},
+			wantStatus:  http.StatusOK,
+			wantMessage: "pong",
+		},
+		{
+			name:        "no repository",
+			ping:        github.PingEvent{Repo: &github.Repository{HTMLURL: github.String("https://github.com/owner/other")}},
+			wantStatus:  http.StatusUnauthorized,
+			wantMessage: pingNotVerified,
+		},
+		{
+			name:        "wrong webhook secret",
+			ping:        github.PingEvent{Repo: &github.Repository{HTMLURL: github.String(repoURL)}},
+			secret:      "wrong",
+			wantStatus:  http.StatusUnauthorized,
+			wantMessage: pingNotVerified,
+		},
+		{
+			name:        "valid",
+			ping:        github.PingEvent{Repo: &github.Repository{HTMLURL: github.String(repoURL)}},
+			secret:      "webhook-secret",
+			wantStatus:  http.StatusOK,
+			wantMessage: "pong from Repository ns/repo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			repo := &v1alpha1.Repository{
+				ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"},
+				Spec: v1alpha1.RepositorySpec{
+					URL: repoURL,
+					GitProvider: &v1alpha1.GitProvider{
+						Secret:        &v1alpha1.Secret{Name: "token"},
+						WebhookSecret: &v1alpha1.Secret{Name: "webhook"},
+					},
+				},
+			}
+			cs, _ := testclient.SeedTestData(t, ctx, testclient.Data{Repositories: []*v1alpha1.Repository{repo}})
+			logger, _ := logger.GetLogger()
+			l := listener{
+				run: &params.Run{
+					Clients: clients.Clients{PipelineAsCode: cs.PipelineAsCode, Kube: cs.Kube, Log: logger},
+					Info:    info.Info{Pac: &info.PacOpts{Settings: &settings.Settings{}}},
+				},
+				kint: &kubernetestint.KinterfaceTest{GetSecretResult: map[string]string{
+					"token":   "token",
+					"webhook": "webhook-secret",
+				}},
+				logger: logger,
+			}
+
+			payload, err := json.Marshal(tt.ping)
+			assert.NilError(t, err)
+			request := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(payload))
+			request.Header.Set("X-GitHub-Event", "ping")
+			if tt.secret != "" {
+				mac := hmac.New(sha256.New, []byte(tt.secret))
+				mac.Write(payload)
+				request.Header.Set(github.SHA256SignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+			}
+			recorder := httptest.NewRecorder()
+			l.handleGitHubPing(ctx, recorder, request, payload)
+
+			assert.Equal(t, recorder.Code, tt.wantStatus)
+			body := Response{}
+			assert.NilError(t, json.NewDecoder(recorder.Body).Decode(&body))
+			assert.Equal(t, body.Message, tt.wantMessage)
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"net/http"
+	"time"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
@@ -20,8 +21,17 @@ type sinker struct {
 	event   *info.Event
 	logger  *zap.SugaredLogger
 	payload []byte
+	// debouncer skips the commits pushed to a pull request superseded by
+	// another one within the debounce window
+	debouncer *pullRequestDebouncer
+	// audit is the audit log the events and their outcome are written to
+	audit *auditLog
 }
 
+// decisionSuperseded is an event skipped since another commit has been pushed
+// to the pull request within the debounce window.
+const decisionSuperseded = "superseded"
+
 func (s *sinker) processEventPayload(ctx context.Context, request *http.Request) error {
 	var err error
 	s.event, err = s.vcx.ParsePayload(ctx, s.run, request, string(s.payload))
@@ -44,10 +54,54 @@ func (s *sinker) processEventPayload(ctx context.Context, request *http.Request)
 func (s *sinker) processEvent(ctx context.Context, request *http.Request) error {
 	if s.event.EventType != "incoming" {
 		if err := s.processEventPayload(ctx, request); err != nil {
+			s.recordAudit(request, pipelineascode.Outcome{Decision: pipelineascode.DecisionError, Reason: err.Error()})
 			return err
 		}
+		if s.isSuperseded(ctx) {
+			s.logger.Infof("skipping event: another commit has been pushed to the pull request %d within the debounce window", s.event.PullRequestNumber)
+			s.recordAudit(request, pipelineascode.Outcome{Decision: decisionSuperseded})
+			return nil
+		}
 	}
 
 	p := pipelineascode.NewPacs(s.event, s.vcx, s.run, s.kint, s.logger)
-	return p.Run(ctx)
+	err := p.Run(ctx)
+	outcome := p.Outcome()
+	if err != nil {
+		outcome.Decision, outcome.Reason = pipelineascode.DecisionError, err.Error()
+	}
+	s.recordAudit(request, outcome)
+	return err
+}
+
+// recordAudit writes the event and its outcome to the audit log when it is
+// enabled in the settings.
+func (s *sinker) recordAudit(request *http.Request, outcome pipelineascode.Outcome) {
+	if s.audit == nil || s.run.Info.Pac == nil {
+		return
+	}
+	entry := auditEntry{
+		Time:         time.Now().UTC(),
+		DeliveryID:   deliveryID(request.Header),
+		Repository:   outcome.Repository,
+		Decision:     outcome.Decision,
+		Reason:       outcome.Reason,
+		PipelineRuns: outcome.PipelineRuns,
+	}
+	if s.vcx != nil && s.vcx.GetConfig() != nil {
+		entry.Provider = s.vcx.GetConfig().Name
+	}
+	if s.event != nil {
+		entry.EventType = s.event.EventType
+		entry.TriggerTarget = s.event.TriggerTarget
+		entry.URL = s.event.URL
+		entry.SHA = s.event.SHA
+		entry.Sender = s.event.Sender
+		entry.BaseBranch = s.event.BaseBranch
+		entry.HeadBranch = s.event.HeadBranch
+		entry.PullRequestNumber = s.event.PullRequestNumber
+	}
+	if err := s.audit.write(s.run.Info.Pac.Settings, entry); err != nil {
+		s.logger.Errorf("cannot write the event to the audit log: %v", err)
+	}
 }
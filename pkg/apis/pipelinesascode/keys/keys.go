@@ -40,14 +40,55 @@ const (
 	TargetProjectID = pipelinesascode.GroupName + "/target-project-id"
 	OriginalPRName  = pipelinesascode.GroupName + "/original-prname"
 	GitAuthSecret   = pipelinesascode.GroupName + "/git-auth-secret"
-	CheckRunID      = pipelinesascode.GroupName + "/check-run-id"
-	OnEvent         = pipelinesascode.GroupName + "/on-event"
-	OnTargetBranch  = pipelinesascode.GroupName + "/on-target-branch"
-	OnCelExpression = pipelinesascode.GroupName + "/on-cel-expression"
-	TargetNamespace = pipelinesascode.GroupName + "/target-namespace"
-	MaxKeepRuns     = pipelinesascode.GroupName + "/max-keep-runs"
-	LogURL          = pipelinesascode.GroupName + "/log-url"
-	ExecutionOrder  = pipelinesascode.GroupName + "/execution-order"
+	// the ServiceAccount the git-auth secret is attached to while the PipelineRun runs
+	GitAuthServiceAccount = pipelinesascode.GroupName + "/git-auth-service-account"
+	CheckRunID            = pipelinesascode.GroupName + "/check-run-id"
+	OnEvent               = pipelinesascode.GroupName + "/on-event"
+	OnTargetBranch        = pipelinesascode.GroupName + "/on-target-branch"
+	OnCelExpression       = pipelinesascode.GroupName + "/on-cel-expression"
+	TargetNamespace       = pipelinesascode.GroupName + "/target-namespace"
+	MaxKeepRuns           = pipelinesascode.GroupName + "/max-keep-runs"
+	LogURL                = pipelinesascode.GroupName + "/log-url"
+	ExecutionOrder        = pipelinesascode.GroupName + "/execution-order"
+	OverriddenBy          = pipelinesascode.GroupName + "/overridden-by"
+	OverrideReason        = pipelinesascode.GroupName + "/override-reason"
+	CancelInProgress      = pipelinesascode.GroupName + "/cancel-in-progress"
+	MinApprovals          = pipelinesascode.GroupName + "/min-approvals"
+	RunAfter              = pipelinesascode.GroupName + "/run-after"
+	// the names of the created PipelineRuns a PipelineRun waits for, as resolved from its run-after annotation
+	RunAfterPipelineRuns = pipelinesascode.GroupName + "/run-after-pipelineruns"
+	// the name of the Repository schedule which created a PipelineRun and the time it was scheduled at
+	Schedule    = pipelinesascode.GroupName + "/schedule"
+	ScheduledAt = pipelinesascode.GroupName + "/scheduled-at"
+	// the file of the .tekton directory a PipelineRun is defined in and the first and last lines of its definition, ie: 3-42
+	SourceFile  = pipelinesascode.GroupName + "/source-file"
+	SourceLines = pipelinesascode.GroupName + "/source-lines"
+	// the images of the steps pinned to a digest, as a json object of the images to their digests
+	PinnedImages = pipelinesascode.GroupName + "/pinned-images"
+	// the human friendly name of a PipelineRun shown instead of its name on the git provider and in the cli
+	DisplayName = pipelinesascode.GroupName + "/display-name"
+	// the GitHub environment a PipelineRun deploys to and the id of the GitHub deployment created for it
+	Deployment   = pipelinesascode.GroupName + "/deployment"
+	DeploymentID = pipelinesascode.GroupName + "/deployment-id"
+	// the number of times a failed PipelineRun is re-created and the attempt a re-created PipelineRun is, the first one being 1
+	Retries      = pipelinesascode.GroupName + "/retries"
+	RetryAttempt = pipelinesascode.GroupName + "/retry-attempt"
+	// the name of the PipelineRun re-created to retry a failed PipelineRun
+	RetriedBy = pipelinesascode.GroupName + "/retried-by"
+	// the priority set by Pipelines as Code on the PipelineRuns started before the others in the concurrency queue
+	QueuePriority = pipelinesascode.GroupName + "/queue-priority"
+	// the architecture and the node pool of the nodes the pods of a PipelineRun are scheduled on
+	Arch     = pipelinesascode.GroupName + "/arch"
+	NodePool = pipelinesascode.GroupName + "/node-pool"
+	// set to "true" when the pipelineRef of a PipelineRun is a Pipeline installed in its namespace instead of one in the .tekton directory
+	ClusterPipeline = pipelinesascode.GroupName + "/cluster-pipeline"
+	// the version of the controller which created a PipelineRun, the digests of the tasks embedded in it as a json object of the pipeline tasks to their digests, the non sensitive settings it was created with as json and the digest of all the settings
+	ControllerVersion = pipelinesascode.GroupName + "/controller-version"
+	TaskDigests       = pipelinesascode.GroupName + "/task-digests"
+	SettingsSnapshot  = pipelinesascode.GroupName + "/settings"
+	SettingsDigest    = pipelinesascode.GroupName + "/settings-digest"
+	// the label of the secrets created by Pipelines as Code for a Repository, deleted with the Repository
+	GeneratedFor = pipelinesascode.GroupName + "/generated-for"
 	// default is "https://api.github.com" but it can be overridden by X-GitHub-Enterprise-Host header
 	PublicGithubAPIURL = "https://api.github.com"
 	// installationURL give us the Installation ID
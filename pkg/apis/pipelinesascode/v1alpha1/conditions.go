@@ -0,0 +1,75 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+)
+
+// RepositoryConditionInsecureSkipTLSVerify is set when the TLS certificate
+// of the git provider is not verified for this Repository.
+const RepositoryConditionInsecureSkipTLSVerify apis.ConditionType = "InsecureSkipTLSVerify"
+
+// RepositoryConditionTokenScopes reports if the token of the git provider has
+// the scopes needed by Pipelines as Code.
+const RepositoryConditionTokenScopes apis.ConditionType = "TokenScopes"
+
+// RepositoryConditionProviderCapabilities reports the features of Pipelines
+// as Code the git provider of the Repository supports.
+const RepositoryConditionProviderCapabilities apis.ConditionType = "ProviderCapabilities"
+
+// GetCondition returns the condition of the Repository with this type or nil
+// if it is not set.
+func (r *Repository) GetCondition(t apis.ConditionType) *apis.Condition {
+	for i := range r.Conditions {
+		if r.Conditions[i].Type == t {
+			return &r.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// SetCondition sets the condition on the Repository, replacing the one with
+// the same type. The transition time is only updated when the status changes
+// and it returns false if the condition was already set as is.
+func (r *Repository) SetCondition(cond apis.Condition) bool {
+	existing := r.GetCondition(cond.Type)
+	if existing != nil && existing.Status == cond.Status && existing.Reason == cond.Reason &&
+		existing.Message == cond.Message && existing.Severity == cond.Severity {
+		return false
+	}
+	if existing != nil && existing.Status == cond.Status {
+		cond.LastTransitionTime = existing.LastTransitionTime
+	} else {
+		cond.LastTransitionTime = apis.VolatileTime{Inner: metav1.Now()}
+	}
+	if existing != nil {
+		*existing = cond
+		return true
+	}
+	r.Conditions = append(r.Conditions, cond)
+	return true
+}
+
+// ClearCondition removes the condition with this type from the Repository, it
+// returns false if it was not set.
+func (r *Repository) ClearCondition(t apis.ConditionType) bool {
+	for i := range r.Conditions {
+		if r.Conditions[i].Type == t {
+			r.Conditions = append(r.Conditions[:i], r.Conditions[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// InsecureSkipTLSVerify returns true if the Repository opted in to skip the
+// verification of the TLS certificate of the git provider.
+func (r *Repository) InsecureSkipTLSVerify() bool {
+	return r.Spec.GitProvider != nil && r.Spec.GitProvider.InsecureSkipTLSVerify
+}
+
+// StrictAnnotations returns true if the unknown Pipelines as Code annotations
+// of the PipelineRuns are reported as errors for the Repository.
+func (r *Repository) StrictAnnotations() bool {
+	return r.Spec.AnnotationPolicy == AnnotationPolicyStrict
+}
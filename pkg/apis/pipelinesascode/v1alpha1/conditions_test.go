@@ -0,0 +1,41 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/apis"
+)
+
+func TestRepositoryConditions(t *testing.T) {
+	repo := &Repository{}
+	cond := apis.Condition{
+		Type:    RepositoryConditionInsecureSkipTLSVerify,
+		Status:  corev1.ConditionTrue,
+		Reason:  "InsecureSkipTLSVerify",
+		Message: "insecure",
+	}
+	assert.Assert(t, repo.GetCondition(RepositoryConditionInsecureSkipTLSVerify) == nil)
+
+	assert.Assert(t, repo.SetCondition(cond))
+	got := repo.GetCondition(RepositoryConditionInsecureSkipTLSVerify)
+	assert.Assert(t, got != nil)
+	assert.Equal(t, got.Message, "insecure")
+	transition := got.LastTransitionTime
+	assert.Assert(t, !transition.Inner.IsZero())
+
+	// setting the same condition again doesn't change anything
+	assert.Assert(t, !repo.SetCondition(cond))
+
+	// a new message keeps the transition time since the status is the same
+	cond.Message = "still insecure"
+	assert.Assert(t, repo.SetCondition(cond))
+	assert.Equal(t, len(repo.Conditions), 1)
+	assert.Equal(t, repo.Conditions[0].Message, "still insecure")
+	assert.Equal(t, repo.Conditions[0].LastTransitionTime, transition)
+
+	assert.Assert(t, repo.ClearCondition(RepositoryConditionInsecureSkipTLSVerify))
+	assert.Assert(t, !repo.ClearCondition(RepositoryConditionInsecureSkipTLSVerify))
+	assert.Equal(t, len(repo.Conditions), 0)
+}
@@ -0,0 +1,28 @@
+package v1alpha1
+
+import (
+	"strings"
+
+	"github.com/gobwas/glob"
+)
+
+const branchRefPrefix = "refs/heads/"
+
+// EnvironmentForBranch returns the name of the first environment with a
+// pattern matching the branch or an empty string, the invalid patterns never
+// match.
+func (r *Repository) EnvironmentForBranch(branch string) string {
+	branch = strings.TrimPrefix(branch, branchRefPrefix)
+	for _, env := range r.Spec.Environments {
+		for _, pattern := range env.Branches {
+			g, err := glob.Compile(strings.TrimPrefix(pattern, branchRefPrefix))
+			if err != nil {
+				continue
+			}
+			if g.Match(branch) {
+				return env.Name
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,38 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestEnvironmentForBranch(t *testing.T) {
+	repo := &Repository{
+		Spec: RepositorySpec{
+			Environments: []Environment{
+				{Name: "invalid", Branches: []string{"[a-"}},
+				{Name: "staging", Branches: []string{"main"}},
+				{Name: "prod", Branches: []string{"refs/heads/release-*"}},
+				{Name: "fallback", Branches: []string{"*"}},
+			},
+		},
+	}
+	tests := []struct {
+		name   string
+		branch string
+		want   string
+	}{
+		{name: "exact match", branch: "main", want: "staging"},
+		{name: "exact match with ref", branch: "refs/heads/main", want: "staging"},
+		{name: "glob match", branch: "release-1.0", want: "prod"},
+		{name: "glob match with ref", branch: "refs/heads/release-1.0", want: "prod"},
+		{name: "first match wins", branch: "feature", want: "fallback"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, repo.EnvironmentForBranch(tt.branch), tt.want)
+		})
+	}
+
+	assert.Equal(t, (&Repository{}).EnvironmentForBranch("main"), "")
+}
@@ -0,0 +1,124 @@
+package v1alpha1
+
+import (
+	"sort"
+)
+
+// HasRouting returns true if the Repository only handles some of the events
+// of its URL.
+func (r *Repository) HasRouting() bool {
+	return r.Spec.Routing != nil && (len(r.Spec.Routing.Branches) > 0 || len(r.Spec.Routing.EventTypes) > 0)
+}
+
+// RoutingMatches returns true if the routing of the Repository matches an
+// event of one of the types on the target branch, a Repository without a
+// routing matches every event. The criteria the event has no value for, ie:
+// the branch of a ping, are not checked.
+func (r *Repository) RoutingMatches(eventTypes []string, branch string) bool {
+	if !r.HasRouting() {
+		return true
+	}
+	routing := r.Spec.Routing
+	if len(routing.EventTypes) > 0 && len(eventTypes) > 0 {
+		found := false
+		for _, t := range eventTypes {
+			if containsString(routing.EventTypes, t) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(routing.Branches) > 0 && branch != "" && !matchesBranch(routing.Branches, branch) {
+		return false
+	}
+	return true
+}
+
+// RoutingSpecificity is how specific the routing of the Repository is, the
+// number of its criteria set: 0 without a routing, 1 for the branches or the
+// event types, 2 for both.
+func (r *Repository) RoutingSpecificity() int {
+	if r.Spec.Routing == nil {
+		return 0
+	}
+	specificity := 0
+	if len(r.Spec.Routing.Branches) > 0 {
+		specificity++
+	}
+	if len(r.Spec.Routing.EventTypes) > 0 {
+		specificity++
+	}
+	return specificity
+}
+
+// SameRouting returns true if the two Repositories have the same routing,
+// whatever the order of the branches and of the event types.
+func SameRouting(a, b *Repository) bool {
+	if !a.HasRouting() || !b.HasRouting() {
+		return a.HasRouting() == b.HasRouting()
+	}
+	return sameStrings(a.Spec.Routing.Branches, b.Spec.Routing.Branches) &&
+		sameStrings(a.Spec.Routing.EventTypes, b.Spec.Routing.EventTypes)
+}
+
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a = append([]string{}, a...)
+	b = append([]string{}, b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// SortByRouting sorts the Repositories declaring the same URL in the order
+// they are matched against an event: the most specific routing first, then the
+// oldest one, then by namespace and name.
+func SortByRouting(repos []Repository) {
+	sort.SliceStable(repos, func(i, j int) bool {
+		si, sj := repos[i].RoutingSpecificity(), repos[j].RoutingSpecificity()
+		if si != sj {
+			return si > sj
+		}
+		ti, tj := repos[i].GetCreationTimestamp(), repos[j].GetCreationTimestamp()
+		if !ti.Equal(&tj) {
+			return ti.Before(&tj)
+		}
+		if repos[i].GetNamespace() != repos[j].GetNamespace() {
+			return repos[i].GetNamespace() < repos[j].GetNamespace()
+		}
+		return repos[i].GetName() < repos[j].GetName()
+	})
+}
+
+// RoutableRepositories returns the Repositories declaring the same URL an event
+// can be routed to: only the ones in the namespace of the oldest of them, so a
+// Repository created later in another namespace never takes the events over.
+func RoutableRepositories(repos []Repository) []Repository {
+	if len(repos) == 0 {
+		return repos
+	}
+	oldest := 0
+	for i := range repos[1:] {
+		ti, to := repos[i+1].GetCreationTimestamp(), repos[oldest].GetCreationTimestamp()
+		if ti.Before(&to) || (ti.Equal(&to) && repos[i+1].GetNamespace() < repos[oldest].GetNamespace()) {
+			oldest = i + 1
+		}
+	}
+	routable := []Repository{}
+	for _, repo := range repos {
+		if repo.GetNamespace() == repos[oldest].GetNamespace() {
+			routable = append(routable, repo)
+		}
+	}
+	return routable
+}
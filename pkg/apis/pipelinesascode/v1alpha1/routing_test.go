@@ -0,0 +1,91 @@
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRoutingMatches(t *testing.T) {
+	repo := &Repository{
+		Spec: RepositorySpec{
+			Routing: &Routing{Branches: []string{"release-*"}, EventTypes: []string{"push"}},
+		},
+	}
+	tests := []struct {
+		name       string
+		repo       *Repository
+		eventTypes []string
+		branch     string
+		want       bool
+	}{
+		{name: "no routing", repo: &Repository{}, eventTypes: []string{"pull_request"}, branch: "main", want: true},
+		{name: "push to a release branch", repo: repo, eventTypes: []string{"push"}, branch: "refs/heads/release-1.0", want: true},
+		{name: "push to another branch", repo: repo, eventTypes: []string{"push"}, branch: "main", want: false},
+		{name: "pull request on a release branch", repo: repo, eventTypes: []string{"pull_request"}, branch: "release-1.0", want: false},
+		{name: "event without a branch", repo: repo, eventTypes: []string{"push"}, want: true},
+		{name: "event without a type", repo: repo, branch: "release-1.0", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.repo.RoutingMatches(tt.eventTypes, tt.branch), tt.want)
+		})
+	}
+}
+
+func TestSameRouting(t *testing.T) {
+	routed := func(branches, eventTypes []string) *Repository {
+		return &Repository{Spec: RepositorySpec{Routing: &Routing{Branches: branches, EventTypes: eventTypes}}}
+	}
+	assert.Assert(t, SameRouting(&Repository{}, routed(nil, nil)))
+	assert.Assert(t, SameRouting(routed([]string{"a", "b"}, []string{"push"}), routed([]string{"b", "a"}, []string{"push"})))
+	assert.Assert(t, !SameRouting(&Repository{}, routed([]string{"main"}, nil)))
+	assert.Assert(t, !SameRouting(routed([]string{"main"}, nil), routed([]string{"main"}, []string{"push"})))
+}
+
+func TestSortByRouting(t *testing.T) {
+	now := time.Now()
+	repo := func(name string, age time.Duration, routing *Routing) Repository {
+		return Repository{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns", CreationTimestamp: metav1.NewTime(now.Add(-age))},
+			Spec:       RepositorySpec{Routing: routing},
+		}
+	}
+	repos := []Repository{
+		repo("catch-all", time.Hour, nil),
+		repo("newer-branches", time.Minute, &Routing{Branches: []string{"main"}}),
+		repo("older-branches", time.Hour, &Routing{Branches: []string{"release-*"}}),
+		repo("both", time.Minute, &Routing{Branches: []string{"main"}, EventTypes: []string{"push"}}),
+		repo("b-same-age", time.Hour, &Routing{EventTypes: []string{"push"}}),
+	}
+	repos[4].CreationTimestamp = repos[2].CreationTimestamp
+	SortByRouting(repos)
+	names := []string{}
+	for _, r := range repos {
+		names = append(names, r.GetName())
+	}
+	assert.DeepEqual(t, names, []string{"both", "b-same-age", "older-branches", "newer-branches", "catch-all"})
+}
+
+func TestRoutableRepositories(t *testing.T) {
+	now := time.Now()
+	repo := func(ns, name string, age time.Duration, routing *Routing) Repository {
+		return Repository{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns, CreationTimestamp: metav1.NewTime(now.Add(-age))},
+			Spec:       RepositorySpec{Routing: routing},
+		}
+	}
+	repos := []Repository{
+		repo("other", "takeover", time.Minute, &Routing{Branches: []string{"*"}}),
+		repo("ci", "catch-all", time.Hour, nil),
+		repo("ci", "release", time.Second, &Routing{Branches: []string{"release-*"}}),
+	}
+	names := []string{}
+	for _, r := range RoutableRepositories(repos) {
+		names = append(names, r.GetNamespace()+"/"+r.GetName())
+	}
+	assert.DeepEqual(t, names, []string{"ci/catch-all", "ci/release"})
+	assert.Equal(t, len(RoutableRepositories(nil)), 0)
+}
@@ -0,0 +1,47 @@
+package v1alpha1
+
+import (
+	"strings"
+
+	"github.com/gobwas/glob"
+)
+
+// ServiceAccountForEvent returns the ServiceAccount of the first mapping
+// matching one of the types of the event and its target branch or an empty
+// string, the invalid branch patterns never match.
+func (r *Repository) ServiceAccountForEvent(eventTypes []string, branch string) string {
+	for _, mapping := range r.Spec.ServiceAccounts {
+		if mapping.EventType != "" && !containsString(eventTypes, mapping.EventType) {
+			continue
+		}
+		if len(mapping.Branches) == 0 || matchesBranch(mapping.Branches, branch) {
+			return mapping.ServiceAccount
+		}
+	}
+	return ""
+}
+
+// matchesBranch returns true if one of the patterns matches the branch, the
+// invalid patterns never match.
+func matchesBranch(patterns []string, branch string) bool {
+	branch = strings.TrimPrefix(branch, branchRefPrefix)
+	for _, pattern := range patterns {
+		g, err := glob.Compile(strings.TrimPrefix(pattern, branchRefPrefix))
+		if err != nil {
+			continue
+		}
+		if g.Match(branch) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
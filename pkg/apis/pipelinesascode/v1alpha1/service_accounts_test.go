@@ -0,0 +1,39 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestServiceAccountForEvent(t *testing.T) {
+	repo := &Repository{
+		Spec: RepositorySpec{
+			ServiceAccounts: []ServiceAccountMapping{
+				{EventType: "push", Branches: []string{"[a-"}, ServiceAccount: "invalid"},
+				{EventType: "push", Branches: []string{"main", "refs/heads/release-*"}, ServiceAccount: "deploy"},
+				{EventType: "pull_request", ServiceAccount: "restricted"},
+				{ServiceAccount: "default-ci"},
+			},
+		},
+	}
+	tests := []struct {
+		name       string
+		eventTypes []string
+		branch     string
+		want       string
+	}{
+		{name: "push to main", eventTypes: []string{"push"}, branch: "main", want: "deploy"},
+		{name: "push to a release branch", eventTypes: []string{"push"}, branch: "refs/heads/release-1.0", want: "deploy"},
+		{name: "pull request on any branch", eventTypes: []string{"pull_request"}, branch: "main", want: "restricted"},
+		{name: "push to another branch", eventTypes: []string{"push"}, branch: "feature", want: "default-ci"},
+		{name: "one of the types of the event", eventTypes: []string{"incoming", "push"}, branch: "main", want: "deploy"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, repo.ServiceAccountForEvent(tt.eventTypes, tt.branch), tt.want)
+		})
+	}
+
+	assert.Equal(t, (&Repository{}).ServiceAccountForEvent([]string{"push"}, "main"), "")
+}
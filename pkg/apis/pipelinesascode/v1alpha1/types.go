@@ -1,7 +1,9 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 )
 
@@ -15,6 +17,11 @@ type Repository struct {
 
 	Spec   RepositorySpec        `json:"spec"`
 	Status []RepositoryRunStatus `json:"pipelinerun_status,omitempty"`
+
+	// Conditions are the conditions of the Repository itself, ie: a
+	// configuration which needs the attention of the user
+	// +optional
+	Conditions apis.Conditions `json:"conditions,omitempty"`
 }
 
 type RepositoryRunStatus struct {
@@ -24,6 +31,11 @@ type RepositoryRunStatus struct {
 	// +optional
 	PipelineRunName string `json:"pipelineRunName,omitempty"`
 
+	// DisplayName is the display name of the PipelineRun, as set in its
+	// display-name annotation
+	// +optional
+	DisplayName string `json:"display_name,omitempty"`
+
 	// StartTime is the time the PipelineRun is actually started.
 	// +optional
 	StartTime *metav1.Time `json:"startTime,omitempty"`
@@ -58,6 +70,39 @@ type RepositoryRunStatus struct {
 
 	// CollectedTaskInfos is the information about tasks
 	CollectedTaskInfos *map[string]TaskInfos `json:"failure_reason,omitempty"`
+
+	// TaskRuns is the status of the tasks of the PipelineRun, kept after the
+	// PipelineRun is pruned
+	// +optional
+	TaskRuns []TaskRunSummary `json:"taskruns,omitempty"`
+
+	// Results are the results emitted by the PipelineRun
+	// +optional
+	Results []RunResult `json:"results,omitempty"`
+}
+
+// TaskRunSummary is the status of a task of a PipelineRun
+type TaskRunSummary struct {
+	// Name is the name of the task in the pipeline
+	Name string `json:"name"`
+
+	// Reason is the reason of the condition of the TaskRun, ie: Succeeded
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// StartTime is the time the TaskRun started
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is the time the TaskRun completed
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// RunResult is a result emitted by a PipelineRun
+type RunResult struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
 }
 
 type TaskInfos struct {
@@ -74,12 +119,200 @@ type RepositorySpec struct {
 	URL              string       `json:"url"`
 	GitProvider      *GitProvider `json:"git_provider,omitempty"`
 	Incomings        *[]Incoming  `json:"incoming,omitempty"`
+	// Environments maps branches to named environments, the first environment
+	// matching the target branch of the event is exposed to the PipelineRuns.
+	Environments []Environment `json:"environments,omitempty"`
+	// GitAuthSecret overrides the settings of the git-auth secret created
+	// with the git provider token for the PipelineRuns of the Repository.
+	GitAuthSecret *GitAuthSecret `json:"git_auth_secret,omitempty"`
+	// Schedules are the Pipelines the controller runs periodically in the
+	// namespace of the Repository, ie: to clean up after the PipelineRuns.
+	Schedules []Schedule `json:"schedules,omitempty"`
+	// AnnotationPolicy is how the Pipelines as Code annotations of the
+	// PipelineRuns are verified: lenient ignores the unknown annotations,
+	// strict reports them as a validation error of the commit.
+	AnnotationPolicy string `json:"annotation_policy,omitempty"`
+	// Env are the environment variables set in every step of the
+	// PipelineRuns through their pod template, ie: the proxy variables or a
+	// registry mirror. The values can come from a secret or a configmap.
+	Env []corev1.EnvVar `json:"env,omitempty"`
+	// CancelRunsOnDelete cancels the running and queued PipelineRuns of the
+	// Repository when it is deleted.
+	CancelRunsOnDelete bool `json:"cancel_runs_on_delete,omitempty"`
+	// CommentStrategy is how the statuses are commented on the pull
+	// requests: throttled only comments once per PipelineRun, editing the
+	// comment afterwards, and never comments a success.
+	CommentStrategy string `json:"comment_strategy,omitempty"`
+	// GitClone prepends a task cloning the repository at the revision of
+	// the event in a workspace to the PipelineRuns.
+	GitClone *GitClone `json:"git_clone,omitempty"`
+	// StatusSinks are the targets the statuses of the PipelineRuns are
+	// reported to along with the git provider of the Repository, ie: the
+	// mirror of the repository on another git provider during a migration.
+	StatusSinks []StatusSink `json:"status_sinks,omitempty"`
+	// ServiceAccounts maps the event types and the target branches to the
+	// ServiceAccount the PipelineRuns run with, ie: a deploy ServiceAccount
+	// for the pushes to main and a restricted one for the pull requests.
+	ServiceAccounts []ServiceAccountMapping `json:"service_accounts,omitempty"`
+	// Routing scopes the Repository to the events on some branches or of
+	// some types, several Repositories can then declare the same URL.
+	Routing *Routing `json:"routing,omitempty"`
+}
+
+// Routing is the scope of the events a Repository handles when several
+// Repositories declare the same URL, the Repository with the most specific
+// matching routing handles an event.
+type Routing struct {
+	// Branches are the names or globs of the target branches of the events,
+	// ie: main or release-*, refs/heads/ is optional.
+	Branches []string `json:"branches,omitempty"`
+	// EventTypes are the types of the events, pull_request, push, incoming
+	// or a custom event type.
+	EventTypes []string `json:"event_types,omitempty"`
+}
+
+// ServiceAccountMapping sets the ServiceAccount of the PipelineRuns of the
+// events of a type on the matching target branches.
+type ServiceAccountMapping struct {
+	// EventType is pull_request, push, incoming or a custom event type, any
+	// event matches when empty.
+	EventType string `json:"event_type,omitempty"`
+	// Branches are the names or globs of the target branches, ie: main or
+	// release-*, refs/heads/ is optional. Any branch matches when empty.
+	Branches []string `json:"branches,omitempty"`
+	// ServiceAccount is the ServiceAccount the PipelineRuns run with.
+	ServiceAccount string `json:"service_account"`
+}
+
+const (
+	// AnnotationPolicyLenient ignores the unknown Pipelines as Code
+	// annotations, it is the default.
+	AnnotationPolicyLenient = "lenient"
+	// AnnotationPolicyStrict fails the validation of the commit when a
+	// PipelineRun has an unknown Pipelines as Code annotation.
+	AnnotationPolicyStrict = "strict"
+
+	// CommentStrategyThrottled comments at most once per PipelineRun on a
+	// pull request, the later statuses edit the comment, and doesn't comment
+	// the PipelineRuns which succeeded.
+	CommentStrategyThrottled = "throttled"
+)
+
+const (
+	// StatusSinkTypeWebhook posts the statuses as JSON to the URL of the
+	// sink.
+	StatusSinkTypeWebhook = "webhook"
+)
+
+// StatusSink is a target the statuses of the PipelineRuns of the Repository
+// are reported to.
+type StatusSink struct {
+	// Name identifies the sink in the logs and in the events of the
+	// Repository.
+	Name string `json:"name"`
+	// Type is webhook to post the statuses as JSON to the URL, or the git
+	// provider of a mirror of the repository, github, gitlab or gitea, to set
+	// a commit status on the same commit of the mirror.
+	Type string `json:"type"`
+	// URL is the URL the statuses are posted to, or the URL of the mirror.
+	URL string `json:"url"`
+	// APIURL is the API URL of the git provider of the mirror, it is guessed
+	// from the URL when empty.
+	APIURL string `json:"api_url,omitempty"`
+	// Secret is the token of the git provider of the mirror, or the bearer
+	// token sent to the webhook.
+	Secret *Secret `json:"secret,omitempty"`
+	// Template is the description of the statuses reported to the sink, with
+	// the dynamic variables and the variables of the status.
+	Template string `json:"template,omitempty"`
+}
+
+// Schedule runs a Pipeline of the namespace of the Repository on a cron
+// schedule.
+type Schedule struct {
+	// Name identifies the schedule, the PipelineRuns are labelled with it.
+	Name string `json:"name"`
+	// Cron is a five fields cron expression, ie: 0 2 * * * every night at
+	// 2am, evaluated in UTC.
+	Cron string `json:"cron"`
+	// Pipeline is the name of the Pipeline to run.
+	Pipeline string `json:"pipeline"`
+	// Params are the parameters passed to the Pipeline.
+	Params map[string]string `json:"params,omitempty"`
+	// ServiceAccount is the ServiceAccount the PipelineRuns run with.
+	ServiceAccount string `json:"service_account,omitempty"`
+}
+
+// GitAuthSecret is the policy of the secret created with the git provider
+// token for the PipelineRuns, the fields left empty use the settings of the
+// Pipelines as Code configmap.
+type GitAuthSecret struct {
+	// AutoCreate creates the secret for every PipelineRun.
+	AutoCreate *bool `json:"auto_create,omitempty"`
+	// NamePrefix is the prefix of the secret names, a random suffix is added.
+	NamePrefix string `json:"name_prefix,omitempty"`
+	// ServiceAccount is the ServiceAccount the PipelineRuns not setting one
+	// run with, the secret is attached to the ServiceAccount of the
+	// PipelineRun while it runs.
+	ServiceAccount string `json:"service_account,omitempty"`
+	// Mode is how the secret is exposed: workspace for a basic-auth
+	// workspace referenced with {{ git_auth_secret }}, or gitconfig for a
+	// Tekton basic-auth secret written in the .gitconfig of every step.
+	Mode string `json:"mode,omitempty"`
+}
+
+// GitClone is the task cloning the repository prepended to the PipelineRuns
+// of the Repository.
+type GitClone struct {
+	Enabled bool `json:"enabled"`
+	// Workspace is the workspace of the Pipeline the repository is cloned
+	// in, source by default. A volume claim template is bound to it when the
+	// PipelineRun doesn't bind it.
+	Workspace string `json:"workspace,omitempty"`
+	// Image is the image of the step cloning the repository, it needs git
+	// and a shell.
+	Image string `json:"image,omitempty"`
+}
+
+// Environment is a named environment, ie: staging or prod, for the branches
+// matching one of the patterns.
+type Environment struct {
+	Name string `json:"name"`
+	// Branches are the names or globs of the branches, ie: main or
+	// release-*, refs/heads/ is optional.
+	Branches []string `json:"branches"`
 }
 
 type Incoming struct {
 	Type    string   `json:"type"`
 	Secret  Secret   `json:"secret"`
 	Targets []string `json:"targets,omitempty"`
+	// SourceCIDRs are the networks the incoming webhook can be called from,
+	// any address is allowed when empty.
+	SourceCIDRs []string `json:"source_cidrs,omitempty"`
+	// SignedURLs requires the URL to be signed with the secret and not to be
+	// expired, rather than passing the secret itself.
+	SignedURLs bool `json:"signed_urls,omitempty"`
+	// PipelineRuns are the names of the PipelineRuns the incoming webhook
+	// can trigger, any PipelineRun can be triggered when empty.
+	PipelineRuns []string `json:"pipelineruns,omitempty"`
+	// AllowRevision allows the caller to pin the commit the PipelineRun runs
+	// on with the sha query URL argument, rather than the head of the branch.
+	AllowRevision bool `json:"allow_revision,omitempty"`
+}
+
+// AllowsPipelineRun returns true if the incoming webhook can trigger the
+// PipelineRun.
+func (i Incoming) AllowsPipelineRun(name string) bool {
+	if len(i.PipelineRuns) == 0 {
+		return true
+	}
+	for _, allowed := range i.PipelineRuns {
+		if allowed == name {
+			return true
+		}
+	}
+	return false
 }
 
 type GitProvider struct {
@@ -88,6 +321,9 @@ type GitProvider struct {
 	Secret        *Secret `json:"secret,omitempty"`
 	WebhookSecret *Secret `json:"webhook_secret,omitempty"`
 	Type          string  `json:"type,omitempty"`
+	// InsecureSkipTLSVerify disables the verification of the TLS certificate
+	// of the git provider API, this should only be used for testing.
+	InsecureSkipTLSVerify bool `json:"insecure_skip_tls_verify,omitempty"`
 }
 
 type Secret struct {
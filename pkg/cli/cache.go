@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DefaultCacheTTL is how long the lookups on the cluster are cached between
+// the invocations of the CLI when the configuration file doesn't say.
+const DefaultCacheTTL = 5 * time.Minute
+
+// CachePath returns the path of the cache of the CLI, cache.json next to the
+// CLI configuration file.
+func CachePath() string {
+	path := ConfigPath()
+	if path == "" {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(path), "cache.json")
+}
+
+type cacheEntry struct {
+	Value   string    `json:"value"`
+	Expires time.Time `json:"expires"`
+}
+
+// Cache caches the values of the slow lookups on the cluster, ie: the URL of
+// the console, in a file so the next invocations of the CLI don't have to do
+// them again until they expire. A cache which can't be read is empty and the
+// errors writing it are ignored, the lookups are just done again.
+type Cache struct {
+	path    string
+	ttl     time.Duration
+	now     func() time.Time
+	mutex   sync.Mutex
+	entries map[string]cacheEntry
+}
+
+// NewCache returns the cache stored at path, its values expire after ttl. It
+// doesn't cache anything when path is empty or ttl is 0.
+func NewCache(path string, ttl time.Duration) *Cache {
+	return &Cache{path: path, ttl: ttl, now: time.Now}
+}
+
+func (c *Cache) enabled() bool {
+	return c != nil && c.path != "" && c.ttl > 0
+}
+
+// load reads the cache file the first time the cache is used.
+func (c *Cache) load() {
+	if c.entries != nil {
+		return
+	}
+	c.entries = map[string]cacheEntry{}
+	b, err := os.ReadFile(c.path)
+	if err != nil {
+		return
+	}
+	if err := json.Unmarshal(b, &c.entries); err != nil {
+		c.entries = map[string]cacheEntry{}
+	}
+}
+
+// Get returns the value of key if it is cached and has not expired.
+func (c *Cache) Get(key string) (string, bool) {
+	if !c.enabled() {
+		return "", false
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.load()
+	entry, ok := c.entries[key]
+	if !ok || !c.now().Before(entry.Expires) {
+		return "", false
+	}
+	return entry.Value, true
+}
+
+// Set caches the value of key and writes the cache file, without the expired
+// values.
+func (c *Cache) Set(key, value string) {
+	if !c.enabled() {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.load()
+	now := c.now()
+	c.entries[key] = cacheEntry{Value: value, Expires: now.Add(c.ttl)}
+	for k, entry := range c.entries {
+		if !now.Before(entry.Expires) {
+			delete(c.entries, k)
+		}
+	}
+	b, err := json.Marshal(c.entries)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o700); err != nil {
+		return
+	}
+	_ = os.WriteFile(c.path, b, 0o600)
+}
@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tkn-pac", "cache.json")
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := NewCache(path, time.Minute)
+	cache.now = func() time.Time { return now }
+
+	_, ok := cache.Get("console")
+	assert.Assert(t, !ok)
+	cache.Set("console", "console.example.com")
+	value, ok := cache.Get("console")
+	assert.Assert(t, ok)
+	assert.Equal(t, value, "console.example.com")
+
+	// another invocation of the CLI reads the file
+	other := NewCache(path, time.Minute)
+	other.now = func() time.Time { return now.Add(30 * time.Second) }
+	value, ok = other.Get("console")
+	assert.Assert(t, ok)
+	assert.Equal(t, value, "console.example.com")
+
+	other.now = func() time.Time { return now.Add(time.Minute) }
+	_, ok = other.Get("console")
+	assert.Assert(t, !ok, "the value should have expired")
+
+	// a corrupted cache is empty
+	assert.NilError(t, os.WriteFile(path, []byte("{"), 0o600))
+	_, ok = NewCache(path, time.Minute).Get("console")
+	assert.Assert(t, !ok)
+
+	// a disabled cache doesn't write anything
+	disabledPath := filepath.Join(t.TempDir(), "cache.json")
+	disabled := NewCache(disabledPath, 0)
+	disabled.Set("console", "console.example.com")
+	_, ok = disabled.Get("console")
+	assert.Assert(t, !ok)
+	_, err := os.Stat(disabledPath)
+	assert.Assert(t, os.IsNotExist(err))
+}
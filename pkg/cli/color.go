@@ -8,37 +8,119 @@ import (
 	"github.com/mgutz/ansi"
 )
 
+const (
+	ThemeDefault      = "default"
+	ThemeHighContrast = "high-contrast"
+	ThemeMonochrome   = "monochrome"
+)
+
 var (
-	magenta    = ansi.ColorFunc("magenta")
-	cyan       = ansi.ColorFunc("cyan")
-	red        = ansi.ColorFunc("red")
-	redBold    = ansi.ColorFunc("red+b")
-	yellow     = ansi.ColorFunc("yellow")
-	blue       = ansi.ColorFunc("blue")
-	blueBold   = ansi.ColorFunc("blue+b")
-	green      = ansi.ColorFunc("green")
-	greenBold  = ansi.ColorFunc("green+b")
-	gray       = ansi.ColorFunc("black+i")
-	bold       = ansi.ColorFunc("default+b")
-	dimmed     = ansi.ColorFunc("246")
-	underline  = ansi.ColorFunc("default+u")
-	cyanBold   = ansi.ColorFunc("cyan+b")
-	orangeBold = ansi.ColorFunc("208")
-
-	gray256 = func(t string) string {
-		return fmt.Sprintf("\x1b[%d;5;%dm%s\x1b[m", 38, 242, t)
-	}
 	hyperLink = func(title, href string) string {
 		return fmt.Sprintf("\x1b]8;;%s\x1b\\%s\x1b]8;;\x1b\\", href, title)
 	}
+
+	noColor = func(t string) string { return t }
 )
 
+// palette is the set of colors of a theme.
+type palette struct {
+	magenta    func(string) string
+	cyan       func(string) string
+	red        func(string) string
+	redBold    func(string) string
+	yellow     func(string) string
+	blue       func(string) string
+	blueBold   func(string) string
+	green      func(string) string
+	greenBold  func(string) string
+	gray       func(string) string
+	gray256    func(string) string
+	bold       func(string) string
+	dimmed     func(string) string
+	underline  func(string) string
+	cyanBold   func(string) string
+	orangeBold func(string) string
+}
+
+var themes = map[string]*palette{
+	ThemeDefault: {
+		magenta:    ansi.ColorFunc("magenta"),
+		cyan:       ansi.ColorFunc("cyan"),
+		red:        ansi.ColorFunc("red"),
+		redBold:    ansi.ColorFunc("red+b"),
+		yellow:     ansi.ColorFunc("yellow"),
+		blue:       ansi.ColorFunc("blue"),
+		blueBold:   ansi.ColorFunc("blue+b"),
+		green:      ansi.ColorFunc("green"),
+		greenBold:  ansi.ColorFunc("green+b"),
+		gray:       ansi.ColorFunc("black+i"),
+		gray256:    func(t string) string { return fmt.Sprintf("\x1b[%d;5;%dm%s\x1b[m", 38, 242, t) },
+		bold:       ansi.ColorFunc("default+b"),
+		dimmed:     ansi.ColorFunc("246"),
+		underline:  ansi.ColorFunc("default+u"),
+		cyanBold:   ansi.ColorFunc("cyan+b"),
+		orangeBold: ansi.ColorFunc("208"),
+	},
+	// high contrast only use the bold and bright variants of the colors and
+	// never dim the text.
+	ThemeHighContrast: {
+		magenta:    ansi.ColorFunc("magenta+bh"),
+		cyan:       ansi.ColorFunc("cyan+bh"),
+		red:        ansi.ColorFunc("red+bh"),
+		redBold:    ansi.ColorFunc("red+bh"),
+		yellow:     ansi.ColorFunc("yellow+bh"),
+		blue:       ansi.ColorFunc("blue+bh"),
+		blueBold:   ansi.ColorFunc("blue+bh"),
+		green:      ansi.ColorFunc("green+bh"),
+		greenBold:  ansi.ColorFunc("green+bh"),
+		gray:       ansi.ColorFunc("white+h"),
+		gray256:    ansi.ColorFunc("white+h"),
+		bold:       ansi.ColorFunc("default+b"),
+		dimmed:     noColor,
+		underline:  ansi.ColorFunc("default+bu"),
+		cyanBold:   ansi.ColorFunc("cyan+bh"),
+		orangeBold: ansi.ColorFunc("yellow+bh"),
+	},
+	// monochrome doesn't use any color but keep the text styles.
+	ThemeMonochrome: {
+		magenta:    noColor,
+		cyan:       noColor,
+		red:        noColor,
+		redBold:    ansi.ColorFunc("default+b"),
+		yellow:     noColor,
+		blue:       noColor,
+		blueBold:   ansi.ColorFunc("default+b"),
+		green:      noColor,
+		greenBold:  ansi.ColorFunc("default+b"),
+		gray:       noColor,
+		gray256:    noColor,
+		bold:       ansi.ColorFunc("default+b"),
+		dimmed:     noColor,
+		underline:  ansi.ColorFunc("default+u"),
+		cyanBold:   ansi.ColorFunc("default+b"),
+		orangeBold: ansi.ColorFunc("default+b"),
+	},
+}
+
+// ValidTheme returns if the theme is a known theme.
+func ValidTheme(theme string) bool {
+	_, ok := themes[theme]
+	return ok
+}
+
+// Themes returns the names of the known themes.
+func Themes() []string {
+	return []string{ThemeDefault, ThemeHighContrast, ThemeMonochrome}
+}
+
 func EnvColorDisabled() bool {
 	return os.Getenv("NO_COLOR") != "" || os.Getenv("CLICOLOR") == "0"
 }
 
+// EnvColorForced returns if the colors are forced with CLICOLOR_FORCE,
+// NO_COLOR always wins over it (see https://no-color.org).
 func EnvColorForced() bool {
-	return os.Getenv("CLICOLOR_FORCE") != "" && os.Getenv("CLICOLOR_FORCE") != "0"
+	return os.Getenv("NO_COLOR") == "" && os.Getenv("CLICOLOR_FORCE") != "" && os.Getenv("CLICOLOR_FORCE") != "0"
 }
 
 func Is256ColorSupported() bool {
@@ -54,15 +136,27 @@ func Is256ColorSupported() bool {
 }
 
 func NewColorScheme(enabled, is256enabled bool) *ColorScheme {
+	return NewThemedColorScheme(enabled, is256enabled, ThemeDefault)
+}
+
+// NewThemedColorScheme returns a color scheme using the colors of theme,
+// falling back to the default theme if it's unknown.
+func NewThemedColorScheme(enabled, is256enabled bool, theme string) *ColorScheme {
+	p, ok := themes[theme]
+	if !ok {
+		p = themes[ThemeDefault]
+	}
 	return &ColorScheme{
 		enabled:      enabled,
 		is256enabled: is256enabled,
+		palette:      p,
 	}
 }
 
 type ColorScheme struct {
 	enabled      bool
 	is256enabled bool
+	palette      *palette
 }
 
 func (c *ColorScheme) ColorStatus(status string) string {
@@ -85,21 +179,21 @@ func (c *ColorScheme) Orange(t string) string {
 	if !c.enabled {
 		return t
 	}
-	return orangeBold(t)
+	return c.palette.orangeBold(t)
 }
 
 func (c *ColorScheme) Bold(t string) string {
 	if !c.enabled {
 		return t
 	}
-	return bold(t)
+	return c.palette.bold(t)
 }
 
 func (c *ColorScheme) Dimmed(t string) string {
 	if !c.enabled {
 		return t
 	}
-	return dimmed(t)
+	return c.palette.dimmed(t)
 }
 
 func (c *ColorScheme) Boldf(t string, args ...interface{}) string {
@@ -110,14 +204,14 @@ func (c *ColorScheme) Red(t string) string {
 	if !c.enabled {
 		return t
 	}
-	return red(t)
+	return c.palette.red(t)
 }
 
 func (c *ColorScheme) RedBold(t string) string {
 	if !c.enabled {
 		return t
 	}
-	return redBold(t)
+	return c.palette.redBold(t)
 }
 
 func (c *ColorScheme) Bullet() string {
@@ -144,7 +238,7 @@ func (c *ColorScheme) Yellow(t string) string {
 	if !c.enabled {
 		return t
 	}
-	return yellow(t)
+	return c.palette.yellow(t)
 }
 
 func (c *ColorScheme) Yellowf(t string, args ...interface{}) string {
@@ -155,14 +249,14 @@ func (c *ColorScheme) Green(t string) string {
 	if !c.enabled {
 		return t
 	}
-	return green(t)
+	return c.palette.green(t)
 }
 
 func (c *ColorScheme) Underline(t string) string {
 	if !c.enabled {
 		return t
 	}
-	return underline(t)
+	return c.palette.underline(t)
 }
 
 func (c *ColorScheme) Greenf(t string, args ...interface{}) string {
@@ -174,9 +268,9 @@ func (c *ColorScheme) Gray(t string) string {
 		return t
 	}
 	if c.is256enabled {
-		return gray256(t)
+		return c.palette.gray256(t)
 	}
-	return gray(t)
+	return c.palette.gray(t)
 }
 
 func (c *ColorScheme) Grayf(t string, args ...interface{}) string {
@@ -187,7 +281,7 @@ func (c *ColorScheme) Magenta(t string) string {
 	if !c.enabled {
 		return t
 	}
-	return magenta(t)
+	return c.palette.magenta(t)
 }
 
 func (c *ColorScheme) Magentaf(t string, args ...interface{}) string {
@@ -198,7 +292,7 @@ func (c *ColorScheme) Cyan(t string) string {
 	if !c.enabled {
 		return t
 	}
-	return cyan(t)
+	return c.palette.cyan(t)
 }
 
 func (c *ColorScheme) Cyanf(t string, args ...interface{}) string {
@@ -209,21 +303,21 @@ func (c *ColorScheme) CyanBold(t string) string {
 	if !c.enabled {
 		return t
 	}
-	return cyanBold(t)
+	return c.palette.cyanBold(t)
 }
 
 func (c *ColorScheme) Blue(t string) string {
 	if !c.enabled {
 		return t
 	}
-	return blue(t)
+	return c.palette.blue(t)
 }
 
 func (c *ColorScheme) BlueBold(t string) string {
 	if !c.enabled {
 		return t
 	}
-	return blueBold(t)
+	return c.palette.blueBold(t)
 }
 
 func (c *ColorScheme) Bluef(t string, args ...interface{}) string {
@@ -287,11 +381,11 @@ func (c *ColorScheme) GreenBold(s string) string {
 	if !c.enabled {
 		return s
 	}
-	return greenBold(s)
+	return c.palette.greenBold(s)
 }
 
 func (c *ColorScheme) HyperLink(title, href string) string {
-	if !c.enabled {
+	if !c.enabled || href == "" {
 		return title
 	}
 	return hyperLink(title, href)
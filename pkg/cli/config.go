@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli/i18n"
+	"sigs.k8s.io/yaml"
+)
+
+// ConfigEnvVar is the environment variable overriding the path of the CLI
+// configuration file.
+const ConfigEnvVar = "TKN_PAC_CONFIG"
+
+// Config is the CLI configuration file, ie:
+//
+//	theme: high-contrast
+//	locale: fr
+//	plain: true
+//	cache-ttl: 10m
+type Config struct {
+	Theme  string `json:"theme,omitempty"`
+	Locale string `json:"locale,omitempty"`
+	// Plain outputs plain text like the --plain flag.
+	Plain bool `json:"plain,omitempty"`
+	// CacheTTL is how long the lookups on the cluster are cached, 0 disables
+	// the cache.
+	CacheTTL string `json:"cache-ttl,omitempty"`
+}
+
+// GetCacheTTL returns how long the lookups on the cluster are cached,
+// DefaultCacheTTL when it isn't set.
+func (c *Config) GetCacheTTL() time.Duration {
+	if c.CacheTTL == "" {
+		return DefaultCacheTTL
+	}
+	ttl, _ := time.ParseDuration(c.CacheTTL)
+	return ttl
+}
+
+// ConfigPath returns the path of the CLI configuration file, by default
+// tkn-pac/config.yaml in the user configuration directory.
+func ConfigPath() string {
+	if path := os.Getenv(ConfigEnvVar); path != "" {
+		return path
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "tkn-pac", "config.yaml")
+}
+
+// LoadConfig loads the CLI configuration file at path, an empty configuration
+// is returned if it doesn't exist.
+func LoadConfig(path string) (*Config, error) {
+	config := &Config{}
+	if path == "" {
+		return config, nil
+	}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(b, config); err != nil {
+		return nil, fmt.Errorf("cannot parse config file %s: %w", path, err)
+	}
+	if config.Theme != "" && !ValidTheme(config.Theme) {
+		return nil, fmt.Errorf("unknown theme %q in config file %s, valid themes are: %v", config.Theme, path, Themes())
+	}
+	if config.Locale != "" && !i18n.Supported(config.Locale) {
+		return nil, fmt.Errorf("unknown locale %q in config file %s, valid locales are: %v", config.Locale, path, i18n.Locales())
+	}
+	if config.CacheTTL != "" {
+		if ttl, err := time.ParseDuration(config.CacheTTL); err != nil || ttl < 0 {
+			return nil, fmt.Errorf("invalid cache-ttl %q in config file %s, it needs to be a duration like 10m or 0 to disable the cache", config.CacheTTL, path)
+		}
+	}
+	return config, nil
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	config, err := LoadConfig(filepath.Join(dir, "notthere.yaml"))
+	assert.NilError(t, err)
+	assert.Equal(t, config.Theme, "")
+
+	path := filepath.Join(dir, "config.yaml")
+	assert.NilError(t, os.WriteFile(path, []byte("theme: high-contrast\n"), 0o600))
+	config, err = LoadConfig(path)
+	assert.NilError(t, err)
+	assert.Equal(t, config.Theme, ThemeHighContrast)
+
+	assert.NilError(t, os.WriteFile(path, []byte("theme: rainbow\n"), 0o600))
+	_, err = LoadConfig(path)
+	assert.ErrorContains(t, err, `unknown theme "rainbow"`)
+
+	assert.NilError(t, os.WriteFile(path, []byte("locale: fr\n"), 0o600))
+	config, err = LoadConfig(path)
+	assert.NilError(t, err)
+	assert.Equal(t, config.Locale, "fr")
+
+	assert.NilError(t, os.WriteFile(path, []byte("locale: klingon\n"), 0o600))
+	_, err = LoadConfig(path)
+	assert.ErrorContains(t, err, `unknown locale "klingon"`)
+
+	assert.NilError(t, os.WriteFile(path, []byte("cache-ttl: 10m\n"), 0o600))
+	config, err = LoadConfig(path)
+	assert.NilError(t, err)
+	assert.Equal(t, config.GetCacheTTL(), 10*time.Minute)
+	assert.Equal(t, (&Config{}).GetCacheTTL(), DefaultCacheTTL)
+
+	assert.NilError(t, os.WriteFile(path, []byte("cache-ttl: forever\n"), 0o600))
+	_, err = LoadConfig(path)
+	assert.ErrorContains(t, err, `invalid cache-ttl "forever"`)
+
+	t.Setenv(ConfigEnvVar, path)
+	assert.Equal(t, ConfigPath(), path)
+	assert.Equal(t, CachePath(), filepath.Join(dir, "cache.json"))
+}
+
+func TestThemes(t *testing.T) {
+	assert.Equal(t, NewThemedColorScheme(true, false, ThemeMonochrome).Red("hello"), "hello")
+	assert.Equal(t, NewThemedColorScheme(false, false, ThemeHighContrast).Red("hello"), "hello")
+	assert.Assert(t, NewThemedColorScheme(true, false, ThemeHighContrast).Red("hello") != "hello")
+	// unknown themes fallback to the default one
+	assert.Equal(t, NewThemedColorScheme(true, false, "rainbow").Red("hello"), NewColorScheme(true, false).Red("hello"))
+}
+
+func TestNoColorWinsOverForce(t *testing.T) {
+	t.Setenv("CLICOLOR_FORCE", "1")
+	t.Setenv("NO_COLOR", "")
+	assert.Assert(t, EnvColorForced())
+	t.Setenv("NO_COLOR", "1")
+	assert.Assert(t, !EnvColorForced())
+	assert.Assert(t, EnvColorDisabled())
+}
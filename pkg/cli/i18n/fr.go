@@ -0,0 +1,54 @@
+package i18n
+
+// french are the french translations of the messages.
+var french = map[string]string{
+	// generate
+	"Enter the Git event type for triggering the pipeline: ":                       "Entrez le type d'événement Git déclenchant le pipeline : ",
+	"Push to a Branch or a Tag":                                                    "Push sur une branche ou un tag",
+	"Enter the target GIT branch for the Pull Request (default: %s): ":             "Entrez la branche GIT cible de la Pull Request (par défaut : %s) : ",
+	"Enter a target GIT branch or a tag for the push (default: %s)":                "Entrez la branche GIT cible ou le tag du push (par défaut : %s)",
+	"%s Directory %s has been created.\n":                                          "%s Le répertoire %s a été créé.\n",
+	"There is already a file named: %s would you like me to override it?":          "Il existe déjà un fichier nommé %s, voulez-vous l'écraser ?",
+	"%s Not overwriting file, exiting...\n":                                        "%s Le fichier n'est pas écrasé, arrêt...\n",
+	"%s Feel free to use the -f flag if you want to target another file name\n...": "%s Utilisez l'option -f pour choisir un autre nom de fichier\n...",
+	"%s There is already a file named: %s, skipping template generation, feel free to use \"%s pac generate\" command to generate sample template.\n": "%s Il existe déjà un fichier nommé %s, le modèle n'est pas généré, utilisez la commande \"%s pac generate\" pour générer un modèle d'exemple.\n",
+	"%s A basic template has been created in %s, feel free to customize it.\n":                                                                        "%s Un modèle de base a été créé dans %s, n'hésitez pas à le personnaliser.\n",
+	"%s You can test your pipeline by pushing generated template to your git repository\n":                                                            "%s Vous pouvez tester votre pipeline en poussant le modèle généré sur votre dépôt git\n",
+	"Would you like to send a notification when the PipelineRun finishes: ":                                                                           "Voulez-vous envoyer une notification à la fin du PipelineRun : ",
+	"None":  "Aucune",
+	"Email": "E-mail",
+	"%s We have detected your repository using the programming language %s.\n": "%s Nous avons détecté que votre dépôt utilise le langage de programmation %s.\n",
+
+	// list
+	"NAME":      "NOM",
+	"STARTED":   "DÉMARRÉ",
+	"DURATION":  "DURÉE",
+	"SUCCESS":   "SUCCÈS",
+	"STATUS":    "STATUT",
+	"NAMESPACE": "NAMESPACE",
+
+	// describe
+	"Name":                 "Nom",
+	"Namespace":            "Namespace",
+	"No runs has started.": "Aucune exécution n'a démarré.",
+	"Last Run:":            "Dernière exécution :",
+	"Status:":              "Statut :",
+	"Log:":                 "Logs :",
+	"Commit URL:":          "URL du commit :",
+	"PipelineRun:":         "PipelineRun :",
+	"Event:":               "Événement :",
+	"Branch:":              "Branche :",
+	"Commit Title:":        "Titre du commit :",
+	"StartTime:":           "Démarré :",
+	"Duration:":            "Durée :",
+	"Failures:":            "Échecs :",
+	"Tasks:":               "Tâches :",
+	"Results:":             "Résultats :",
+	"Other Runs:":          "Autres exécutions :",
+	"STATUS:":              "STATUT :",
+	"Event":                "Événement",
+	"Branch":               "Branche",
+	"STARTED TIME":         "DÉMARRÉ",
+	"PIPELINERUN":          "PIPELINERUN",
+	"Events:":              "Événements :",
+}
@@ -0,0 +1,92 @@
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// LocaleEnvVar is the environment variable selecting the language of the CLI
+// messages, it wins over the configuration file and the system locale.
+const LocaleEnvVar = "TKN_PAC_LANG"
+
+// DefaultLocale is the language the messages are written in.
+const DefaultLocale = "en"
+
+// catalogs are the translations of the messages by locale, a message is
+// looked up by its english text so an untranslated message is shown as is.
+var catalogs = map[string]map[string]string{
+	"fr": french,
+}
+
+// Locales returns the supported locales.
+func Locales() []string {
+	locales := []string{DefaultLocale}
+	for locale := range catalogs {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Supported returns true if the messages are available in the locale.
+func Supported(locale string) bool {
+	_, ok := catalogs[normalize(locale)]
+	return ok || normalize(locale) == DefaultLocale
+}
+
+// normalize returns the language of a POSIX locale, ie: fr for fr_FR.UTF-8.
+func normalize(locale string) string {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if i := strings.IndexAny(locale, "_-.@"); i >= 0 {
+		locale = locale[:i]
+	}
+	return locale
+}
+
+// DetectLocale returns the locale of the messages: the one of the
+// TKN_PAC_LANG environment variable, the configured one or the one of the
+// system, in this order. The messages are in english when the first locale
+// set is not supported.
+func DetectLocale(configured string) string {
+	for _, locale := range []string{
+		os.Getenv(LocaleEnvVar), configured, os.Getenv("LC_ALL"), os.Getenv("LC_MESSAGES"), os.Getenv("LANG"),
+	} {
+		if locale == "" {
+			continue
+		}
+		if !Supported(locale) {
+			return DefaultLocale
+		}
+		return normalize(locale)
+	}
+	return DefaultLocale
+}
+
+// Printer translates the messages to a locale, a nil Printer leaves them in
+// english.
+type Printer struct {
+	messages map[string]string
+}
+
+// NewPrinter returns a Printer translating to the locale.
+func NewPrinter(locale string) *Printer {
+	return &Printer{messages: catalogs[normalize(locale)]}
+}
+
+// T returns the translation of the message.
+func (p *Printer) T(msg string) string {
+	if p == nil {
+		return msg
+	}
+	if translated, ok := p.messages[msg]; ok {
+		return translated
+	}
+	return msg
+}
+
+// Sprintf formats the translation of the format.
+func (p *Printer) Sprintf(format string, a ...interface{}) string {
+	return fmt.Sprintf(p.T(format), a...)
+}
@@ -0,0 +1,69 @@
+package i18n
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestDetectLocale(t *testing.T) {
+	tests := []struct {
+		name       string
+		env        map[string]string
+		configured string
+		want       string
+	}{
+		{
+			name: "default",
+			want: DefaultLocale,
+		},
+		{
+			name: "system locale",
+			env:  map[string]string{"LANG": "fr_FR.UTF-8"},
+			want: "fr",
+		},
+		{
+			name: "lc_all wins over lang",
+			env:  map[string]string{"LC_ALL": "C", "LANG": "fr_FR.UTF-8"},
+			want: DefaultLocale,
+		},
+		{
+			name:       "configured locale wins over the system one",
+			env:        map[string]string{"LANG": "de_DE.UTF-8"},
+			configured: "fr",
+			want:       "fr",
+		},
+		{
+			name:       "environment variable wins over the configured locale",
+			env:        map[string]string{LocaleEnvVar: "en"},
+			configured: "fr",
+			want:       DefaultLocale,
+		},
+		{
+			name: "unsupported locale",
+			env:  map[string]string{LocaleEnvVar: "ja_JP"},
+			want: DefaultLocale,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{LocaleEnvVar, "LC_ALL", "LC_MESSAGES", "LANG"} {
+				t.Setenv(key, tt.env[key])
+			}
+			assert.Equal(t, DetectLocale(tt.configured), tt.want)
+		})
+	}
+}
+
+func TestPrinter(t *testing.T) {
+	p := NewPrinter("fr_FR")
+	assert.Equal(t, p.T("NAME"), "NOM")
+	assert.Equal(t, p.Sprintf("%s Directory %s has been created.\n", "*", ".tekton"), "* Le répertoire .tekton a été créé.\n")
+	// untranslated messages are left in english
+	assert.Equal(t, p.T("SHA"), "SHA")
+
+	assert.Equal(t, NewPrinter(DefaultLocale).T("NAME"), "NAME")
+	var nilPrinter *Printer
+	assert.Equal(t, nilPrinter.Sprintf("%d runs", 2), "2 runs")
+	assert.DeepEqual(t, Locales(), []string{"en", "fr"})
+}
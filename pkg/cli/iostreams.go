@@ -4,13 +4,17 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"runtime"
+	"time"
 
 	surveyCore "github.com/AlecAivazis/survey/v2/core"
 	"github.com/mattn/go-colorable"
 	"github.com/mattn/go-isatty"
 	"github.com/mgutz/ansi"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli/i18n"
+	"github.com/spf13/cobra"
 )
 
 type IOStreams struct {
@@ -25,14 +29,66 @@ type IOStreams struct {
 	stderrIsTTY              bool
 	stdoutIsTTY              bool
 	is256enabled             bool
+	verbose                  bool
+	quiet                    bool
+	theme                    string
+	locale                   string
+	plain                    bool
+	cacheTTL                 time.Duration
 }
 
 func (s *IOStreams) ColorScheme() *ColorScheme {
-	return NewColorScheme(s.ColorEnabled(), s.ColorSupport256())
+	return NewThemedColorScheme(s.ColorEnabled(), s.ColorSupport256(), s.theme)
+}
+
+// SetTheme sets the color theme used by the color scheme.
+func (s *IOStreams) SetTheme(theme string) {
+	s.theme = theme
+	s.setSurveyColor()
+}
+
+// Printer returns the printer translating the messages to the locale of the
+// user.
+func (s *IOStreams) Printer() *i18n.Printer {
+	return i18n.NewPrinter(s.locale)
+}
+
+// CacheTTL returns how long the lookups on the cluster are cached between the
+// invocations of the CLI.
+func (s *IOStreams) CacheTTL() time.Duration {
+	return s.cacheTTL
+}
+
+// SetLocale sets the locale the messages are translated to.
+func (s *IOStreams) SetLocale(locale string) {
+	s.locale = locale
 }
 
 func (s *IOStreams) ColorEnabled() bool {
-	return s.colorEnabled
+	return s.colorEnabled && !s.plain
+}
+
+// SetPlain sets the plain output, without colors, icons, emojis or box
+// drawing characters, for the screen readers and the log files.
+func (s *IOStreams) SetPlain(plain bool) {
+	if plain && !s.plain {
+		s.Out = &plainWriter{w: s.Out}
+		s.ErrOut = &plainWriter{w: s.ErrOut}
+	}
+	if !plain && s.plain {
+		if w, ok := s.Out.(*plainWriter); ok {
+			s.Out = w.w
+		}
+		if w, ok := s.ErrOut.(*plainWriter); ok {
+			s.ErrOut = w.w
+		}
+	}
+	s.plain = plain
+	s.setSurveyColor()
+}
+
+func (s *IOStreams) IsPlain() bool {
+	return s.plain
 }
 
 func (s *IOStreams) SetColorEnabled(colorEnabled bool) {
@@ -42,7 +98,7 @@ func (s *IOStreams) SetColorEnabled(colorEnabled bool) {
 }
 
 func (s *IOStreams) setSurveyColor() {
-	if !s.colorEnabled {
+	if !s.ColorEnabled() || s.theme == ThemeMonochrome {
 		surveyCore.DisableColor = true
 	} else {
 		// override survey's poor choice of color
@@ -60,6 +116,67 @@ func (s *IOStreams) setSurveyColor() {
 	}
 }
 
+// AddFlags adds the global verbosity flags to the command.
+func (s *IOStreams) AddFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().BoolVarP(&s.verbose, "verbose", "v", false,
+		"Trace the API calls made by the command on stderr")
+	cmd.PersistentFlags().Var(&plainFlag{ioStreams: s}, "plain",
+		"Output plain text without colors, icons or emojis, for screen readers and log files")
+	cmd.PersistentFlags().Lookup("plain").NoOptDefVal = "true"
+}
+
+func (s *IOStreams) SetVerbose(verbose bool) {
+	s.verbose = verbose
+}
+
+func (s *IOStreams) IsVerbose() bool {
+	return s.verbose
+}
+
+// AddQuietFlag adds the quiet flag to the commands outputting resources which
+// honor it.
+func (s *IOStreams) AddQuietFlag(cmd *cobra.Command, usage string) {
+	cmd.Flags().BoolVarP(&s.quiet, "quiet", "q", false, usage)
+}
+
+func (s *IOStreams) SetQuiet(quiet bool) {
+	s.quiet = quiet
+}
+
+func (s *IOStreams) IsQuiet() bool {
+	return s.quiet
+}
+
+// TraceTransport wraps rt to print every API call on ErrOut when the verbose
+// flag has been set.
+func (s *IOStreams) TraceTransport(rt http.RoundTripper) http.RoundTripper {
+	if !s.verbose {
+		return rt
+	}
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	return &traceTransport{ioStreams: s, transport: rt}
+}
+
+type traceTransport struct {
+	ioStreams *IOStreams
+	transport http.RoundTripper
+}
+
+func (t *traceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	cs := t.ioStreams.ColorScheme()
+	start := time.Now()
+	resp, err := t.transport.RoundTrip(req)
+	elapsed := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		fmt.Fprintf(t.ioStreams.ErrOut, "%s %s %s %s (%s)\n", cs.Dimmed("*"), req.Method, req.URL.Redacted(), cs.Red(err.Error()), elapsed)
+		return resp, err
+	}
+	fmt.Fprintf(t.ioStreams.ErrOut, "%s %s %s %s (%s)\n", cs.Dimmed("*"), req.Method, req.URL.Redacted(), resp.Status, elapsed)
+	return resp, nil
+}
+
 func (s *IOStreams) ColorSupport256() bool {
 	return s.is256enabled
 }
@@ -85,6 +202,18 @@ func NewIOStreams() *IOStreams {
 		ios.progressIndicatorEnabled = true
 	}
 
+	config, err := LoadConfig(ConfigPath())
+	if err != nil {
+		fmt.Fprintf(ios.ErrOut, "warning: %s\n", err.Error())
+		config = &Config{}
+	}
+	ios.theme = config.Theme
+	ios.locale = i18n.DetectLocale(config.Locale)
+	if config.Plain {
+		ios.SetPlain(true)
+	}
+	ios.cacheTTL = config.GetCacheTTL()
+
 	ios.setSurveyColor()
 
 	// prevent duplicate isTerminal queries now that we know the answer
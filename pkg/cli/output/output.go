@@ -0,0 +1,116 @@
+// Package output renders the tables and the details of the runs printed by the
+// CLI. It is shared by the commands of tkn pac and can be used by the CLIs
+// built on top of Pipelines as Code to print the runs the same way.
+package output
+
+import (
+	"io"
+	"text/tabwriter"
+	"text/template"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/juju/ansiterm"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// the columns are padded with 3 spaces and the tabs of the cells are 5
+	// characters wide, whatever the width of the terminal.
+	tabWidth = 5
+	padding  = 3
+
+	nonAttributedStr = "---"
+)
+
+// Renderer formats the times, the durations and the statuses of the runs and
+// renders the templates of the commands as tables. A Renderer has no state
+// shared with another one, the commands and their tests can render
+// concurrently.
+type Renderer struct {
+	ColorScheme *cli.ColorScheme
+	// Clock is the clock the relative times are computed from, a fake clock
+	// in the tests.
+	Clock clockwork.Clock
+	// UseRealTime formats the times as RFC3339 instead of relatively to the
+	// clock.
+	UseRealTime bool
+
+	ioStreams *cli.IOStreams
+}
+
+// NewRenderer returns a Renderer writing with the colors and the locale of the
+// IOStreams.
+func NewRenderer(ioStreams *cli.IOStreams, clock clockwork.Clock, useRealTime bool) *Renderer {
+	return &Renderer{
+		ColorScheme: ioStreams.ColorScheme(),
+		Clock:       clock,
+		UseRealTime: useRealTime,
+		ioStreams:   ioStreams,
+	}
+}
+
+// Time formats a time relatively to the clock, ie: "5 minutes ago", or as
+// RFC3339 when the real time is used.
+func (r *Renderer) Time(t *metav1.Time) string {
+	if r.UseRealTime {
+		if t.IsZero() {
+			return nonAttributedStr
+		}
+		return t.Format(time.RFC3339)
+	}
+	return formatting.Age(t, r.Clock)
+}
+
+// Status formats the reason of the status of a run with its color, linked to
+// the logs of the run.
+func (r *Renderer) Status(status v1alpha1.RepositoryRunStatus) string {
+	reason := "UNKNOWN"
+	if len(status.Status.Conditions) > 0 {
+		reason = status.Status.Conditions[0].Reason
+	}
+	return r.ColorScheme.HyperLink(r.ColorScheme.ColorStatus(reason), formatting.LogURL(status))
+}
+
+// T translates a message to the locale of the user.
+func (r *Renderer) T(message string) string {
+	return r.ioStreams.Printer().T(message)
+}
+
+// FuncMap returns the functions the templates of the commands share.
+func (r *Renderer) FuncMap() template.FuncMap {
+	return template.FuncMap{
+		"formatTime":         r.Time,
+		"formatStatusReason": r.Status,
+		"formatDuration":     formatting.PRDuration,
+		"formatTaskDuration": formatting.Duration,
+		"formatEventType":    formatting.CamelCasit,
+		"runName":            formatting.RunName,
+		"sanitizeBranch":     formatting.SanitizeBranch,
+		"shortSHA":           formatting.ShortSHA,
+		"t":                  r.T,
+	}
+}
+
+// NewTabWriter returns the writer aligning the columns separated by tabs, the
+// colors and the hyperlinks are not counted in the width of the columns.
+func NewTabWriter(w io.Writer) *ansiterm.TabWriter {
+	return ansiterm.NewTabWriter(w, 0, tabWidth, padding, ' ', tabwriter.TabIndent)
+}
+
+// Table renders the template with the data as a table on the writer, the
+// functions are added to the ones of FuncMap.
+func (r *Renderer) Table(w io.Writer, name, tmpl string, funcs template.FuncMap, data interface{}) error {
+	t, err := template.New(name).Funcs(r.FuncMap()).Funcs(funcs).Parse(tmpl)
+	if err != nil {
+		return err
+	}
+	tw := NewTabWriter(w)
+	if err := t.Execute(tw, data); err != nil {
+		return err
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,68 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRendererTime(t *testing.T) {
+	clock := clockwork.NewFakeClockAt(time.Date(1984, time.April, 4, 0, 0, 0, 0, time.UTC))
+	started := &metav1.Time{Time: clock.Now().Add(-5 * time.Minute)}
+	tests := []struct {
+		name        string
+		time        *metav1.Time
+		useRealTime bool
+		want        string
+	}{
+		{name: "relative", time: started, want: "5 minutes ago"},
+		{name: "real time", time: started, useRealTime: true, want: "1984-04-03T23:55:00Z"},
+		{name: "no time", time: &metav1.Time{}, want: "---"},
+		{name: "no real time", time: &metav1.Time{}, useRealTime: true, want: "---"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ioStreams, _, _, _ := cli.IOTest()
+			r := NewRenderer(ioStreams, clock, tt.useRealTime)
+			assert.Equal(t, r.Time(tt.time), tt.want)
+		})
+	}
+}
+
+func TestRendererTable(t *testing.T) {
+	ioStreams, _, _, _ := cli.IOTest()
+	r := NewRenderer(ioStreams, clockwork.NewFakeClock(), false)
+	out := &bytes.Buffer{}
+	data := []struct{ Name, Status string }{{"a", "Succeeded"}, {"longer-name", "Failed"}}
+	tmpl := `{{ t "NAME" }}	{{ t "STATUS" }}
+{{- range .Rows }}
+{{ .Name }}	{{ upper .Status }}
+{{- end }}
+`
+	funcs := template.FuncMap{"upper": strings.ToUpper}
+	assert.NilError(t, r.Table(out, "table", tmpl, funcs, struct{ Rows interface{} }{data}))
+	assert.Equal(t, out.String(), "NAME          STATUS\na             SUCCEEDED\nlonger-name   FAILED\n")
+
+	assert.ErrorContains(t, r.Table(out, "table", "{{ unknown }}", nil, nil), `function "unknown" not defined`)
+}
+
+// the colors are not counted in the width of the columns.
+func TestRendererTableColors(t *testing.T) {
+	ioStreams, _, _, _ := cli.IOTest()
+	ioStreams.SetColorEnabled(true)
+	r := NewRenderer(ioStreams, clockwork.NewFakeClock(), false)
+	assert.Assert(t, r.ColorScheme.Bold("a") != "a")
+	out := &bytes.Buffer{}
+	tmpl := `{{ .ColorScheme.Bold "a" }}	b
+longer-name	c
+`
+	assert.NilError(t, r.Table(out, "table", tmpl, nil, r))
+	assert.Equal(t, out.String(), r.ColorScheme.Bold("a")+"             b\nlonger-name   c\n")
+}
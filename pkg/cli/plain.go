@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ansiEscapeRe matches the escape sequences of the colors and of the
+// hyperlinks.
+var ansiEscapeRe = regexp.MustCompile("\x1b\\[[0-9;?]*[ -/]*[@-~]|\x1b\\][^\x07\x1b]*(\x07|\x1b\\\\)")
+
+// plainIcons are the icons having a textual equivalent in the plain output.
+var plainIcons = map[rune]string{
+	'✓': "[ok]",
+	'✔': "[ok]",
+	'ℹ': "[info]",
+	'⚠': "[warning]",
+	'•': "-",
+	'∙': "-",
+}
+
+// plainText returns the text without its colors, hyperlinks, emojis and box
+// drawing characters, the icons having a meaning are replaced by words.
+func plainText(s string) string {
+	s = ansiEscapeRe.ReplaceAllString(s, "")
+	var b strings.Builder
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if word, ok := plainIcons[r]; ok {
+			b.WriteString(word)
+			continue
+		}
+		switch {
+		case r >= 0x2500 && r <= 0x257f:
+			b.WriteString(plainBoxDrawing(r))
+		case isJoiner(r):
+		case isEmoji(r):
+			// the emojis are followed by a space to separate them from the text
+			for i+1 < len(runes) && (isEmoji(runes[i+1]) || isJoiner(runes[i+1]) || runes[i+1] == ' ') {
+				i++
+			}
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func isEmoji(r rune) bool {
+	return (r >= 0x1f000 && r <= 0x1faff) || (r >= 0x2600 && r <= 0x27bf) || (unicode.Is(unicode.So, r) && r > 0x2000)
+}
+
+// isJoiner returns true for the characters changing how the emojis are
+// displayed, the variation selector and the zero width joiner.
+func isJoiner(r rune) bool {
+	return r == 0xfe0f || r == 0x200d
+}
+
+func plainBoxDrawing(r rune) string {
+	switch r {
+	case '─', '━', '╌', '╍', '┄', '┅', '┈', '┉', '═':
+		return "-"
+	case '│', '┃', '╎', '╏', '┆', '┇', '┊', '┋', '║':
+		return "|"
+	}
+	return "+"
+}
+
+// plainWriter writes the plain text of what is written to it.
+type plainWriter struct {
+	w io.Writer
+}
+
+func (p *plainWriter) Write(b []byte) (int, error) {
+	if _, err := io.WriteString(p.w, plainText(string(b))); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// plainFlag sets the plain output when the --plain flag is parsed, before the
+// commands write anything.
+type plainFlag struct {
+	ioStreams *IOStreams
+}
+
+func (f *plainFlag) String() string {
+	if f.ioStreams == nil {
+		return "false"
+	}
+	return strconv.FormatBool(f.ioStreams.plain)
+}
+
+func (f *plainFlag) Set(value string) error {
+	plain, err := strconv.ParseBool(value)
+	if err != nil {
+		return err
+	}
+	f.ioStreams.SetPlain(plain)
+	return nil
+}
+
+func (f *plainFlag) Type() string {
+	return "bool"
+}
@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"gotest.tools/v3/assert"
+)
+
+func TestPlainText(t *testing.T) {
+	cs := NewColorScheme(true, true)
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "colors and hyperlinks",
+			text: fmt.Sprintf("%s %s", cs.Green("Succeeded"), cs.HyperLink("pr-abcde", "https://console/pr")),
+			want: "Succeeded pr-abcde",
+		},
+		{
+			name: "icons",
+			text: fmt.Sprintf("%s done\n%s note\n%s list", cs.SuccessIcon(), cs.InfoIcon(), "•"),
+			want: "[ok] done\n[info] note\n- list",
+		},
+		{
+			name: "emojis",
+			text: "🔑 Secret has been created\n⚠️ self signed certificate\nℹ ️You now need a token",
+			want: "Secret has been created\n[warning] self signed certificate\n[info] You now need a token",
+		},
+		{
+			name: "box drawing",
+			text: "┌──┐\n│ok│\n└──┘",
+			want: "+--+\n|ok|\n+--+",
+		},
+		{
+			name: "accents are kept",
+			text: "Dernière exécution : réussie",
+			want: "Dernière exécution : réussie",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, plainText(tt.text), tt.want)
+		})
+	}
+}
+
+func TestPlainFlag(t *testing.T) {
+	ios, _, out, errOut := IOTest()
+	ios.SetColorEnabled(true)
+	cmd := &cobra.Command{
+		Use: "test",
+		Run: func(cmd *cobra.Command, args []string) {
+			cs := ios.ColorScheme()
+			fmt.Fprintf(ios.Out, "%s %s\n", cs.SuccessIcon(), cs.Bold("created"))
+			fmt.Fprintln(ios.ErrOut, "👀 detected")
+		},
+	}
+	ios.AddFlags(cmd)
+	cmd.SetArgs([]string{"--plain"})
+	assert.NilError(t, cmd.Execute())
+	assert.Assert(t, ios.IsPlain())
+	assert.Assert(t, !ios.ColorEnabled())
+	assert.Equal(t, out.String(), "[ok] created\n")
+	assert.Equal(t, errOut.String(), "detected\n")
+
+	ios.SetPlain(false)
+	fmt.Fprint(ios.Out, "✓")
+	assert.Equal(t, out.String(), "[ok] created\n✓")
+}
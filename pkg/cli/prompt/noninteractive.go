@@ -0,0 +1,102 @@
+package prompt
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/AlecAivazis/survey/v2/core"
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+)
+
+var (
+	assumeYes bool
+
+	// stdinIsTerminal is overridden in tests
+	stdinIsTerminal = func() bool {
+		return isatty.IsTerminal(os.Stdin.Fd()) || isatty.IsCygwinTerminal(os.Stdin.Fd())
+	}
+)
+
+// AddFlags adds the global flags answering the questions with their default
+// values instead of prompting the user, ie: when running in CI.
+func AddFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().BoolVarP(&assumeYes, "assume-yes", "y", false,
+		"Do not prompt and answer every question with its default value")
+	cmd.PersistentFlags().BoolVar(&assumeYes, "non-interactive", false,
+		"Alias of --assume-yes")
+}
+
+func interactive() bool {
+	return !assumeYes && stdinIsTerminal()
+}
+
+// answerDefault writes the default value of the prompt to the response, it
+// fails when stdin is not a terminal and --assume-yes has not been set so
+// we never hang waiting for an answer which will never come.
+func answerDefault(p survey.Prompt, name string, validate survey.Validator, response interface{}) error {
+	message, answer, err := defaultAnswer(p)
+	if !assumeYes {
+		return fmt.Errorf("cannot ask %q: stdin is not a terminal, use --assume-yes to answer with the default values or pass the answer as a flag", message)
+	}
+	if err != nil {
+		return err
+	}
+	if validate != nil {
+		if err := validate(answer); err != nil {
+			return fmt.Errorf("the default answer of %q is not valid: %w, pass the answer as a flag", message, err)
+		}
+	}
+	return core.WriteAnswer(response, name, answer)
+}
+
+func defaultAnswer(p survey.Prompt) (string, interface{}, error) {
+	switch p := p.(type) {
+	case *survey.Input:
+		return p.Message, p.Default, nil
+	case *survey.Confirm:
+		return p.Message, p.Default, nil
+	case *survey.Editor:
+		return p.Message, p.Default, nil
+	case *survey.Select:
+		answer, err := defaultOption(p.Message, p.Options, p.Default)
+		return p.Message, answer, err
+	case *survey.MultiSelect:
+		answers := []core.OptionAnswer{}
+		defaults, _ := p.Default.([]string)
+		for _, d := range defaults {
+			answer, err := defaultOption(p.Message, p.Options, d)
+			if err != nil {
+				return p.Message, nil, err
+			}
+			answers = append(answers, answer)
+		}
+		return p.Message, answers, nil
+	case *survey.Password:
+		return p.Message, nil, fmt.Errorf("%q has no default answer, pass the answer as a flag", p.Message)
+	default:
+		return fmt.Sprintf("%T", p), nil, fmt.Errorf("cannot answer a %T prompt with its default value", p)
+	}
+}
+
+func defaultOption(message string, options []string, def interface{}) (core.OptionAnswer, error) {
+	if len(options) == 0 {
+		return core.OptionAnswer{}, fmt.Errorf("%q has no option to choose from", message)
+	}
+	switch def := def.(type) {
+	case nil:
+		return core.OptionAnswer{Value: options[0], Index: 0}, nil
+	case int:
+		if def >= 0 && def < len(options) {
+			return core.OptionAnswer{Value: options[def], Index: def}, nil
+		}
+	case string:
+		for i, option := range options {
+			if option == def {
+				return core.OptionAnswer{Value: option, Index: i}, nil
+			}
+		}
+	}
+	return core.OptionAnswer{}, fmt.Errorf("default answer %v of %q is not one of the options", def, message)
+}
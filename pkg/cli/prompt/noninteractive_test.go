@@ -0,0 +1,50 @@
+package prompt
+
+import (
+	"testing"
+
+	"github.com/AlecAivazis/survey/v2"
+	"gotest.tools/v3/assert"
+)
+
+func TestNonInteractive(t *testing.T) {
+	origStdinIsTerminal := stdinIsTerminal
+	stdinIsTerminal = func() bool { return false }
+	defer func() {
+		stdinIsTerminal = origStdinIsTerminal
+		assumeYes = false
+	}()
+
+	var name string
+	err := SurveyAskOne(&survey.Input{Message: "Name", Default: "default"}, &name)
+	assert.ErrorContains(t, err, `cannot ask "Name": stdin is not a terminal, use --assume-yes`)
+
+	assumeYes = true
+	assert.NilError(t, SurveyAskOne(&survey.Input{Message: "Name", Default: "default"}, &name))
+	assert.Equal(t, name, "default")
+
+	var confirm bool
+	assert.NilError(t, SurveyAskOne(&survey.Confirm{Message: "Sure?", Default: true}, &confirm))
+	assert.Assert(t, confirm)
+
+	var choice string
+	assert.NilError(t, SurveyAskOne(&survey.Select{Message: "Pick", Options: []string{"a", "b"}}, &choice))
+	assert.Equal(t, choice, "a")
+	assert.NilError(t, SurveyAskOne(&survey.Select{Message: "Pick", Options: []string{"a", "b"}, Default: "b"}, &choice))
+	assert.Equal(t, choice, "b")
+
+	var password string
+	err = SurveyAskOne(&survey.Password{Message: "Token"}, &password)
+	assert.ErrorContains(t, err, `"Token" has no default answer`)
+
+	answers := struct {
+		Name string
+		URL  string
+	}{}
+	err = SurveyAsk([]*survey.Question{
+		{Name: "name", Prompt: &survey.Input{Message: "Name", Default: "default"}},
+		{Name: "url", Prompt: &survey.Input{Message: "URL"}, Validate: survey.Required},
+	}, &answers)
+	assert.ErrorContains(t, err, `the default answer of "URL" is not valid`)
+	assert.Equal(t, answers.Name, "default")
+}
@@ -4,10 +4,21 @@ import "github.com/AlecAivazis/survey/v2"
 
 // SurveyAskOne ask one question to be stubbed later
 var SurveyAskOne = func(p survey.Prompt, response interface{}, opts ...survey.AskOpt) error {
+	if !interactive() {
+		return answerDefault(p, "", nil, response)
+	}
 	return survey.AskOne(p, response, opts...)
 }
 
 // SurveyAsk ask questions to be stubbed later
 var SurveyAsk = func(qs []*survey.Question, response interface{}, opts ...survey.AskOpt) error {
+	if !interactive() {
+		for _, q := range qs {
+			if err := answerDefault(q.Prompt, q.Name, q.Validate, response); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
 	return survey.Ask(qs, response, opts...)
 }
@@ -0,0 +1,102 @@
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/go-github/v49/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	pacv1alpha1 "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/consoleui"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	kstatus "github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction/status"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+)
+
+// resultsRecordsPath lists the records of all the results of a namespace.
+const resultsRecordsPath = "/apis/results.tekton.dev/v1alpha2/parents/%s/results/-/records"
+
+// ResultsClient gets the PipelineRuns archived by Tekton Results through its
+// REST API.
+type ResultsClient struct {
+	HTTP    *http.Client
+	BaseURL string
+	Token   string
+}
+
+type resultsRecords struct {
+	Records []struct {
+		Data struct {
+			Type  string `json:"type"`
+			Value []byte `json:"value"`
+		} `json:"data"`
+	} `json:"records"`
+	NextPageToken string `json:"nextPageToken"`
+}
+
+// RepositoryHistory returns a page of the runs of the repository archived by
+// Tekton Results, the most recent first, and the token of the next page which
+// is empty on the last page.
+func (c *ResultsClient) RepositoryHistory(ctx context.Context, ui consoleui.Interface, repository *pacv1alpha1.Repository, pageSize int, pageToken string) ([]pacv1alpha1.RepositoryRunStatus, string, error) {
+	query := url.Values{}
+	query.Set("filter", fmt.Sprintf(`data_type in ["tekton.dev/v1.PipelineRun", "tekton.dev/v1beta1.PipelineRun"] && data.metadata.labels["%s"] == "%s"`,
+		keys.Repository, repository.GetName()))
+	query.Set("order_by", "create_time desc")
+	if pageSize > 0 {
+		query.Set("page_size", fmt.Sprintf("%d", pageSize))
+	}
+	if pageToken != "" {
+		query.Set("page_token", pageToken)
+	}
+	u := strings.TrimSuffix(c.BaseURL, "/") + fmt.Sprintf(resultsRecordsPath, url.PathEscape(repository.GetNamespace())) + "?" + query.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	res, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("cannot list the records of tekton results: %s", res.Status)
+	}
+	records := resultsRecords{}
+	if err := json.NewDecoder(res.Body).Decode(&records); err != nil {
+		return nil, "", fmt.Errorf("cannot decode the records of tekton results: %w", err)
+	}
+
+	statuses := []pacv1alpha1.RepositoryRunStatus{}
+	for _, record := range records.Records {
+		pr := tektonv1.PipelineRun{}
+		if err := json.Unmarshal(record.Data.Value, &pr); err != nil {
+			return nil, "", fmt.Errorf("cannot decode the %s record of tekton results: %w", record.Data.Type, err)
+		}
+		// a record without a condition hasn't been reconciled, it has nothing to show
+		if len(pr.Status.Conditions) == 0 {
+			continue
+		}
+		statuses = append(statuses, pacv1alpha1.RepositoryRunStatus{
+			Status:          pr.Status.Status,
+			LogURL:          github.String(consoleui.RunLogURL(ui, "", &pr)),
+			PipelineRunName: pr.GetName(),
+			DisplayName:     formatting.PipelineRunDisplayName(&pr),
+			StartTime:       pr.Status.StartTime,
+			CompletionTime:  pr.Status.CompletionTime,
+			SHA:             github.String(pr.GetLabels()[keys.SHA]),
+			SHAURL:          github.String(pr.GetAnnotations()[keys.ShaURL]),
+			Title:           github.String(pr.GetAnnotations()[keys.ShaTitle]),
+			TargetBranch:    github.String(pr.GetLabels()[keys.Branch]),
+			EventType:       github.String(pr.GetLabels()[keys.EventType]),
+			Results:         kstatus.CollectResults(&pr),
+		})
+	}
+	return statuses, records.NextPageToken, nil
+}
@@ -0,0 +1,77 @@
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jonboulle/clockwork"
+	pacv1alpha1 "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/consoleui"
+	tektontest "github.com/openshift-pipelines/pipelines-as-code/pkg/test/tekton"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestResultsRepositoryHistory(t *testing.T) {
+	cw := clockwork.NewFakeClock()
+	labels := map[string]string{
+		"pipelinesascode.tekton.dev/repository": "repo",
+		"pipelinesascode.tekton.dev/sha":        "sha1",
+		"pipelinesascode.tekton.dev/branch":     "main",
+		"pipelinesascode.tekton.dev/event-type": "push",
+	}
+	finished := tektontest.MakePRCompletion(cw, "finished", "ns", "", labels, 30)
+	pending := &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "pending", Namespace: "ns", Labels: labels}}
+
+	record := func(pr *tektonv1.PipelineRun) string {
+		b, err := json.Marshal(pr)
+		assert.NilError(t, err)
+		value, err := json.Marshal(b)
+		assert.NilError(t, err)
+		return fmt.Sprintf(`{"name": "ns/results/uid/records/uid", "data": {"type": "tekton.dev/v1.PipelineRun", "value": %s}}`, value)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/apis/results.tekton.dev/v1alpha2/parents/ns/results/-/records" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		assert.Equal(t, r.Header.Get("Authorization"), "Bearer token")
+		assert.Assert(t, strings.Contains(r.URL.Query().Get("filter"), `data.metadata.labels["pipelinesascode.tekton.dev/repository"] == "repo"`))
+		assert.Equal(t, r.URL.Query().Get("page_size"), "2")
+		if r.URL.Query().Get("page_token") == "next" {
+			fmt.Fprint(w, `{"records": []}`)
+			return
+		}
+		fmt.Fprintf(w, `{"records": [%s, %s], "nextPageToken": "next"}`, record(finished), record(pending))
+	}))
+	defer server.Close()
+
+	client := &ResultsClient{HTTP: server.Client(), BaseURL: server.URL + "/", Token: "token"}
+	repo := &pacv1alpha1.Repository{ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"}}
+
+	statuses, next, err := client.RepositoryHistory(context.Background(), consoleui.FallBackConsole{}, repo, 2, "")
+	assert.NilError(t, err)
+	assert.Equal(t, next, "next")
+	// the pending PipelineRun has no condition to show
+	assert.Equal(t, len(statuses), 1)
+	assert.Equal(t, statuses[0].PipelineRunName, "finished")
+	assert.Equal(t, *statuses[0].SHA, "sha1")
+	assert.Equal(t, *statuses[0].TargetBranch, "main")
+	assert.Equal(t, *statuses[0].EventType, "push")
+
+	statuses, next, err = client.RepositoryHistory(context.Background(), consoleui.FallBackConsole{}, repo, 2, "next")
+	assert.NilError(t, err)
+	assert.Equal(t, next, "")
+	assert.Equal(t, len(statuses), 0)
+
+	client.BaseURL = server.URL + "/unknown"
+	_, _, err = client.RepositoryHistory(context.Background(), consoleui.FallBackConsole{}, repo, 2, "")
+	assert.ErrorContains(t, err, "cannot list the records of tekton results")
+}
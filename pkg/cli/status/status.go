@@ -6,6 +6,8 @@ import (
 
 	"github.com/google/go-github/v49/github"
 	pacv1alpha1 "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/consoleui"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
 	kstatus "github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction/status"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
@@ -43,6 +45,7 @@ func convertPrStatusToRepositoryStatus(ctx context.Context, cs *params.Run, pr t
 		Status:             pr.Status.Status,
 		LogURL:             &logurl,
 		PipelineRunName:    pr.GetName(),
+		DisplayName:        formatting.PipelineRunDisplayName(&pr),
 		CollectedTaskInfos: &failurereasons,
 		StartTime:          pr.Status.StartTime,
 		SHA:                github.String(prSHA),
@@ -50,6 +53,8 @@ func convertPrStatusToRepositoryStatus(ctx context.Context, cs *params.Run, pr t
 		Title:              github.String(pr.GetAnnotations()["pipelinesascode.tekton.dev/sha-title"]),
 		TargetBranch:       github.String(pr.GetLabels()["pipelinesascode.tekton.dev/branch"]),
 		EventType:          github.String(pr.GetLabels()["pipelinesascode.tekton.dev/event-type"]),
+		TaskRuns:           kstatus.CollectTaskRunsSummary(ctx, cs, &pr),
+		Results:            kstatus.CollectResults(&pr),
 	}
 }
 
@@ -66,7 +71,7 @@ func MixLivePRandRepoStatus(ctx context.Context, cs *params.Run, repository pacv
 	for i := range prs.Items {
 		pr := prs.Items[i]
 		repositorystatus = RepositoryRunStatusRemoveSameSHA(repositorystatus, pr.GetLabels()["pipelinesascode.tekton.dev/sha"])
-		logurl := cs.Clients.ConsoleUI.DetailURL(&pr)
+		logurl := consoleui.RunLogURL(cs.Clients.ConsoleUI, "", &pr)
 		repositorystatus = append(repositorystatus, convertPrStatusToRepositoryStatus(ctx, cs, pr, logurl))
 	}
 	return sortrepostatus.RepositorySortRunStatus(repositorystatus)
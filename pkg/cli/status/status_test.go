@@ -0,0 +1,108 @@
+package status
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v49/github"
+	"github.com/jonboulle/clockwork"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	pacv1alpha1 "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/consoleui"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/test/logger"
+	tektontest "github.com/openshift-pipelines/pipelines-as-code/pkg/test/tekton"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	knativeapi "knative.dev/pkg/apis"
+	knativeduckv1 "knative.dev/pkg/apis/duck/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestMixLivePRandRepoStatus(t *testing.T) {
+	ns := "namespace"
+	clock := clockwork.NewFakeClock()
+	repo := &pacv1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: ns},
+		Spec:       pacv1alpha1.RepositorySpec{URL: "https://anurl.com"},
+		Status: []pacv1alpha1.RepositoryRunStatus{
+			{PipelineRunName: "old-same-sha", SHA: github.String("livesha")},
+			{
+				PipelineRunName: "old-other-sha",
+				SHA:             github.String("othersha"),
+				StartTime:       &metav1.Time{Time: clock.Now().Add(-time.Hour)},
+			},
+		},
+	}
+
+	labels := map[string]string{
+		keys.Repository: repo.GetName(),
+		keys.SHA:        "livesha",
+	}
+	pr := tektontest.MakePRCompletion(clock, "live-pr", ns, string(tektonv1.PipelineRunReasonFailed), labels, 10)
+	pr.Status.ChildReferences = []tektonv1.ChildStatusReference{
+		{
+			TypeMeta:         runtime.TypeMeta{Kind: "TaskRun"},
+			Name:             "live-pr-task",
+			PipelineTaskName: "task",
+		},
+	}
+	tr := tektontest.MakeTaskRunCompletion(clock, "live-pr-task", ns, "", nil,
+		tektonv1.TaskRunStatusFields{
+			PodName: "live-pr-task-pod",
+			Steps: []tektonv1.StepState{
+				{
+					Name:      "step",
+					Container: "step-step",
+					ContainerState: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{ExitCode: 1},
+					},
+				},
+			},
+		},
+		knativeduckv1.Conditions{
+			{
+				Type:    knativeapi.ConditionSucceeded,
+				Status:  corev1.ConditionFalse,
+				Reason:  string(tektonv1.PipelineRunReasonFailed),
+				Message: "step has failed",
+			},
+		}, 10)
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "live-pr-task-pod", Namespace: ns},
+	}
+
+	ctx, _ := rtesting.SetupFakeContext(t)
+	tdata := testclient.Data{
+		Repositories: []*pacv1alpha1.Repository{repo},
+		PipelineRuns: []*tektonv1.PipelineRun{pr},
+		TaskRuns:     []*tektonv1.TaskRun{tr},
+		Pods:         []*corev1.Pod{pod},
+	}
+	stdata, _ := testclient.SeedTestData(t, ctx, tdata)
+	log, _ := logger.GetLogger()
+	cs := &params.Run{
+		Clients: clients.Clients{
+			Kube:      stdata.Kube,
+			Tekton:    stdata.Pipeline,
+			Log:       log,
+			ConsoleUI: consoleui.FallBackConsole{},
+		},
+	}
+
+	statuses := MixLivePRandRepoStatus(ctx, cs, *repo)
+	assert.Equal(t, len(statuses), 2)
+	names := []string{}
+	for _, status := range statuses {
+		names = append(names, status.PipelineRunName)
+	}
+	assert.DeepEqual(t, names, []string{"live-pr", "old-other-sha"})
+	infos := *statuses[0].CollectedTaskInfos
+	assert.Equal(t, infos["task"].Message, "step has failed")
+	assert.Equal(t, infos["task"].LogSnippet, "fake logs")
+}
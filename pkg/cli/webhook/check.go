@@ -0,0 +1,348 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/bradleyfalzon/ghinstallation/v2"
+	"github.com/google/go-github/v49/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/bootstrap"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/pipelineascode"
+	"github.com/xanzy/go-gitlab"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// githubWebhookEvents are the events a GitHub webhook needs to send to the
+// controller.
+var githubWebhookEvents = []string{"issue_comment", "pull_request", "push"}
+
+// Finding is a discrepancy between a Repository and its configuration on the
+// git provider, with a suggestion on how to fix it.
+type Finding struct {
+	Problem    string
+	Suggestion string
+}
+
+// CheckOptions are the options to check the configuration of a Repository on
+// its git provider.
+type CheckOptions struct {
+	Run          *params.Run
+	Repository   *v1alpha1.Repository
+	PACNamespace string
+}
+
+// Check verifies the Repository is configured on its git provider: the GitHub
+// App is installed on the repository when the Repository has no git_provider
+// secret, or a webhook sending the events to the controller exists.
+func Check(ctx context.Context, opts *CheckOptions) ([]Finding, error) {
+	repo := opts.Repository
+	providerType := ProviderTypeFromRepository(repo)
+
+	installed, installationNS, err := bootstrap.DetectPacInstallation(ctx, opts.PACNamespace, opts.Run)
+	if !installed {
+		return nil, fmt.Errorf("pipelines as code not installed")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if repo.Spec.GitProvider == nil || repo.Spec.GitProvider.Secret == nil {
+		if providerType != "github" {
+			return []Finding{{
+				Problem:    fmt.Sprintf("repository %s has no git_provider secret and only GitHub supports the GitHub App", repo.GetName()),
+				Suggestion: fmt.Sprintf("configure a webhook with: tkn pac webhook add -n %s %s", repo.GetNamespace(), repo.GetName()),
+			}}, nil
+		}
+		return checkGitHubApp(ctx, opts.Run, repo, installationNS)
+	}
+
+	controllerURL, token, findings, ok := webhookSettings(ctx, opts, installationNS)
+	if !ok {
+		return findings, nil
+	}
+
+	var providerFindings []Finding
+	switch providerType {
+	case "github":
+		var gh *gitHubConfig
+		if gh, err = newGitHubConfig(repo, token); err != nil {
+			return nil, err
+		}
+		providerFindings, err = gh.check(ctx, repo, controllerURL)
+	case "gitlab":
+		gl := &gitLabConfig{personalAccessToken: token, APIURL: repo.Spec.GitProvider.URL}
+		gl.projectID, err = formatting.GetRepoOwnerFromURL(repo.Spec.URL)
+		if err != nil {
+			return nil, err
+		}
+		providerFindings, err = gl.check(repo, controllerURL)
+	default:
+		return nil, fmt.Errorf("checking the webhook of a %s repository is not supported", providerType)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return append(findings, providerFindings...), nil
+}
+
+// webhookSettings returns the controller URL and the token of the git
+// provider of a Repository with a git_provider secret, with the findings when
+// they cannot be read. It returns false when the token cannot be read.
+func webhookSettings(ctx context.Context, opts *CheckOptions, installationNS string) (string, string, []Finding, bool) {
+	repo := opts.Repository
+	controllerURL := ""
+	if pacInfo, err := info.GetPACInfo(ctx, opts.Run, installationNS); err == nil {
+		controllerURL = pacInfo.ControllerURL
+	}
+
+	secret, err := opts.Run.Clients.Kube.CoreV1().Secrets(repo.GetNamespace()).Get(ctx, repo.Spec.GitProvider.Secret.Name, metav1.GetOptions{})
+	if err != nil {
+		return "", "", []Finding{{
+			Problem:    fmt.Sprintf("cannot read the git_provider secret %s: %v", repo.Spec.GitProvider.Secret.Name, err),
+			Suggestion: fmt.Sprintf("create the secret with: tkn pac webhook update-token -n %s %s", repo.GetNamespace(), repo.GetName()),
+		}}, false
+	}
+	secretKey := repo.Spec.GitProvider.Secret.Key
+	if secretKey == "" {
+		secretKey = pipelineascode.DefaultGitProviderSecretKey
+	}
+
+	findings := []Finding{}
+	if controllerURL == "" {
+		findings = append(findings, Finding{
+			Problem:    "the controller URL is not set in the pipelines-as-code-info configmap, the webhook URL cannot be verified",
+			Suggestion: fmt.Sprintf("set the controller-url key of the pipelines-as-code-info configmap in the %s namespace", installationNS),
+		})
+	}
+	return controllerURL, string(secret.Data[secretKey]), findings, true
+}
+
+func newGitHubConfig(repo *v1alpha1.Repository, token string) (*gitHubConfig, error) {
+	gh := &gitHubConfig{personalAccessToken: token, APIURL: repo.Spec.GitProvider.URL}
+	var err error
+	gh.repoOwner, gh.repoName, err = formatting.GetRepoOwnerSplitted(repo.Spec.URL)
+	if err != nil {
+		return nil, err
+	}
+	return gh, nil
+}
+
+// ProviderTypeFromRepository returns the type of the git provider set on the
+// Repository or guessed from its URL.
+func ProviderTypeFromRepository(repo *v1alpha1.Repository) string {
+	if repo.Spec.GitProvider != nil && repo.Spec.GitProvider.Type != "" {
+		return repo.Spec.GitProvider.Type
+	}
+	switch {
+	case strings.Contains(repo.Spec.URL, "github"):
+		return "github"
+	case strings.Contains(repo.Spec.URL, "gitlab"):
+		return "gitlab"
+	case strings.Contains(repo.Spec.URL, "bitbucket.org"):
+		return "bitbucket-cloud"
+	}
+	return "unknown"
+}
+
+// sameURL compares two URLs ignoring their trailing slash.
+func sameURL(a, b string) bool {
+	return strings.TrimSuffix(a, "/") == strings.TrimSuffix(b, "/")
+}
+
+func webhookSuggestion(repo *v1alpha1.Repository) string {
+	return fmt.Sprintf("recreate the webhook with: tkn pac webhook add -n %s %s", repo.GetNamespace(), repo.GetName())
+}
+
+func checkGitHubApp(ctx context.Context, run *params.Run, repo *v1alpha1.Repository, installationNS string) ([]Finding, error) {
+	secret, err := run.Clients.Kube.CoreV1().Secrets(installationNS).Get(ctx, pipelineascode.DefaultPipelinesAscodeSecretName, metav1.GetOptions{})
+	if err != nil {
+		return []Finding{{
+			Problem: fmt.Sprintf("repository %s has no git_provider secret and the GitHub App secret %s cannot be read: %v",
+				repo.GetName(), pipelineascode.DefaultPipelinesAscodeSecretName, err),
+			Suggestion: fmt.Sprintf("configure the GitHub App with tkn pac bootstrap or run the check with access to the %s namespace", installationNS),
+		}}, nil
+	}
+	appID, err := strconv.ParseInt(strings.TrimSpace(string(secret.Data["github-application-id"])), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the github application_id number from secret: %w", err)
+	}
+	itr, err := ghinstallation.NewAppsTransport(http.DefaultTransport, appID, secret.Data["github-private-key"])
+	if err != nil {
+		return nil, err
+	}
+
+	apiURL := ""
+	if repo.Spec.GitProvider != nil {
+		apiURL = repo.Spec.GitProvider.URL
+	}
+	client := github.NewClient(&http.Client{Transport: itr})
+	if apiURL != "" && apiURL != keys.PublicGithubAPIURL {
+		if client, err = github.NewEnterpriseClient(apiURL, "", &http.Client{Transport: itr}); err != nil {
+			return nil, err
+		}
+	}
+	return checkAppInstallation(ctx, client, repo)
+}
+
+// checkAppInstallation verifies the GitHub App the client is authenticated as
+// is installed on the repository.
+func checkAppInstallation(ctx context.Context, client *github.Client, repo *v1alpha1.Repository) ([]Finding, error) {
+	owner, name, err := formatting.GetRepoOwnerSplitted(repo.Spec.URL)
+	if err != nil {
+		return nil, err
+	}
+	installation, _, err := client.Apps.FindRepositoryInstallation(ctx, owner, name)
+	if err != nil {
+		var gherr *github.ErrorResponse
+		if errors.As(err, &gherr) && gherr.Response != nil && gherr.Response.StatusCode == http.StatusNotFound {
+			return []Finding{{
+				Problem:    fmt.Sprintf("the GitHub App is not installed on %s/%s", owner, name),
+				Suggestion: fmt.Sprintf("install the GitHub App on %s/%s from the GitHub App settings page", owner, name),
+			}}, nil
+		}
+		return nil, err
+	}
+	if installation.SuspendedAt != nil {
+		return []Finding{{
+			Problem:    fmt.Sprintf("the GitHub App installation on %s is suspended", owner),
+			Suggestion: fmt.Sprintf("unsuspend the GitHub App installation from the %s settings", owner),
+		}}, nil
+	}
+	return []Finding{}, nil
+}
+
+func (gh *gitHubConfig) check(ctx context.Context, repo *v1alpha1.Repository, controllerURL string) ([]Finding, error) {
+	ghClient, err := gh.newGHClientByToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	hook, finding, err := gh.findHook(ctx, ghClient, repo, controllerURL)
+	if err != nil {
+		return nil, err
+	}
+	if finding != nil {
+		return []Finding{*finding}, nil
+	}
+
+	findings := []Finding{}
+	if !hook.GetActive() {
+		findings = append(findings, Finding{
+			Problem:    fmt.Sprintf("the webhook %d on %s/%s is not active", hook.GetID(), gh.repoOwner, gh.repoName),
+			Suggestion: "activate the webhook from the repository settings",
+		})
+	}
+	missing := []string{}
+	for _, event := range githubWebhookEvents {
+		found := false
+		for _, e := range hook.Events {
+			if e == event || e == "*" {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, event)
+		}
+	}
+	if len(missing) > 0 {
+		findings = append(findings, Finding{
+			Problem:    fmt.Sprintf("the webhook %d on %s/%s does not send the %s events", hook.GetID(), gh.repoOwner, gh.repoName, strings.Join(missing, ", ")),
+			Suggestion: "enable those events in the webhook settings of the repository",
+		})
+	}
+	return findings, nil
+}
+
+// findHook returns the webhook of the repository pointing to the controller
+// URL, or the first one when the controller URL is not known. It returns a
+// finding when there is none.
+func (gh *gitHubConfig) findHook(ctx context.Context, ghClient *github.Client, repo *v1alpha1.Repository, controllerURL string) (*github.Hook, *Finding, error) {
+	hooks := []*github.Hook{}
+	opt := &github.ListOptions{PerPage: 100}
+	for {
+		page, resp, err := ghClient.Repositories.ListHooks(ctx, gh.repoOwner, gh.repoName, opt)
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot list the webhooks of %s/%s: %w", gh.repoOwner, gh.repoName, err)
+		}
+		hooks = append(hooks, page...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	for _, h := range hooks {
+		hookURL, _ := h.Config["url"].(string)
+		if controllerURL == "" || sameURL(hookURL, controllerURL) {
+			return h, nil, nil
+		}
+	}
+	problem := fmt.Sprintf("no webhook on %s/%s", gh.repoOwner, gh.repoName)
+	if controllerURL != "" {
+		problem = fmt.Sprintf("no webhook on %s/%s points to the controller URL %s", gh.repoOwner, gh.repoName, controllerURL)
+	}
+	return nil, &Finding{Problem: problem, Suggestion: webhookSuggestion(repo)}, nil
+}
+
+func (gl *gitLabConfig) check(repo *v1alpha1.Repository, controllerURL string) ([]Finding, error) {
+	glClient, err := gl.newClient()
+	if err != nil {
+		return nil, err
+	}
+
+	hooks := []*gitlab.ProjectHook{}
+	opt := &gitlab.ListProjectHooksOptions{PerPage: 100}
+	for {
+		page, resp, err := glClient.Projects.ListProjectHooks(gl.projectID, opt)
+		if err != nil {
+			return nil, fmt.Errorf("cannot list the webhooks of %s: %w", gl.projectID, err)
+		}
+		hooks = append(hooks, page...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	var hook *gitlab.ProjectHook
+	for _, h := range hooks {
+		if controllerURL == "" || sameURL(h.URL, controllerURL) {
+			hook = h
+			break
+		}
+	}
+	if hook == nil {
+		problem := fmt.Sprintf("no webhook on %s", gl.projectID)
+		if controllerURL != "" {
+			problem = fmt.Sprintf("no webhook on %s points to the controller URL %s", gl.projectID, controllerURL)
+		}
+		return []Finding{{Problem: problem, Suggestion: webhookSuggestion(repo)}}, nil
+	}
+
+	missing := []string{}
+	if !hook.MergeRequestsEvents {
+		missing = append(missing, "merge request")
+	}
+	if !hook.NoteEvents {
+		missing = append(missing, "comments")
+	}
+	if !hook.PushEvents {
+		missing = append(missing, "push")
+	}
+	if len(missing) > 0 {
+		return []Finding{{
+			Problem:    fmt.Sprintf("the webhook %d on %s does not send the %s events", hook.ID, gl.projectID, strings.Join(missing, ", ")),
+			Suggestion: "enable those events in the webhook settings of the project",
+		}}, nil
+	}
+	return []Finding{}, nil
+}
@@ -0,0 +1,197 @@
+package webhook
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	thelp "github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gitlab/test"
+	ghtesthelper "github.com/openshift-pipelines/pipelines-as-code/pkg/test/github"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+var checkRepo = &v1alpha1.Repository{
+	ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"},
+	Spec:       v1alpha1.RepositorySpec{URL: "https://github.com/owner/repo"},
+}
+
+func TestGitHubCheck(t *testing.T) {
+	fakeclient, mux, _, teardown := ghtesthelper.SetupGH()
+	defer teardown()
+
+	mux.HandleFunc("/repos/owner/good/hooks", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `[{"id": 1, "active": true, "events": ["issue_comment", "pull_request", "push"], "config": {"url": "https://other.url"}},
+{"id": 2, "active": true, "events": ["issue_comment", "pull_request", "push"], "config": {"url": "https://controller.url/"}}]`)
+	})
+	mux.HandleFunc("/repos/owner/wrongurl/hooks", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `[{"id": 1, "active": true, "events": ["*"], "config": {"url": "https://other.url"}}]`)
+	})
+	mux.HandleFunc("/repos/owner/inactive/hooks", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `[{"id": 1, "active": false, "events": ["pull_request"], "config": {"url": "https://controller.url"}}]`)
+	})
+
+	tests := []struct {
+		name          string
+		repoName      string
+		controllerURL string
+		wantProblems  []string
+	}{
+		{
+			name:          "webhook pointing to the controller",
+			repoName:      "good",
+			controllerURL: "https://controller.url",
+		},
+		{
+			name:          "webhook pointing to another url",
+			repoName:      "wrongurl",
+			controllerURL: "https://controller.url",
+			wantProblems:  []string{"no webhook on owner/wrongurl points to the controller URL https://controller.url"},
+		},
+		{
+			name:     "any webhook without controller url",
+			repoName: "wrongurl",
+		},
+		{
+			name:          "inactive webhook missing events",
+			repoName:      "inactive",
+			controllerURL: "https://controller.url",
+			wantProblems: []string{
+				"the webhook 1 on owner/inactive is not active",
+				"the webhook 1 on owner/inactive does not send the issue_comment, push events",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			gh := &gitHubConfig{Client: fakeclient, repoOwner: "owner", repoName: tt.repoName}
+			findings, err := gh.check(ctx, checkRepo, tt.controllerURL)
+			assert.NilError(t, err)
+			assert.Equal(t, len(findings), len(tt.wantProblems))
+			for i, problem := range tt.wantProblems {
+				assert.Equal(t, findings[i].Problem, problem)
+				assert.Assert(t, findings[i].Suggestion != "")
+			}
+		})
+	}
+}
+
+func TestCheckAppInstallation(t *testing.T) {
+	fakeclient, mux, _, teardown := ghtesthelper.SetupGH()
+	defer teardown()
+
+	mux.HandleFunc("/repos/owner/installed/installation", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"id": 1}`)
+	})
+	mux.HandleFunc("/repos/owner/suspended/installation", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"id": 1, "suspended_at": "2023-01-01T00:00:00Z"}`)
+	})
+	mux.HandleFunc("/repos/owner/notinstalled/installation", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = fmt.Fprint(w, `{"message": "Not Found"}`)
+	})
+
+	tests := []struct {
+		name        string
+		url         string
+		wantProblem string
+	}{
+		{
+			name: "installed",
+			url:  "https://github.com/owner/installed",
+		},
+		{
+			name:        "suspended",
+			url:         "https://github.com/owner/suspended",
+			wantProblem: "the GitHub App installation on owner is suspended",
+		},
+		{
+			name:        "not installed",
+			url:         "https://github.com/owner/notinstalled",
+			wantProblem: "the GitHub App is not installed on owner/notinstalled",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			repo := &v1alpha1.Repository{Spec: v1alpha1.RepositorySpec{URL: tt.url}}
+			findings, err := checkAppInstallation(ctx, fakeclient, repo)
+			assert.NilError(t, err)
+			if tt.wantProblem == "" {
+				assert.Equal(t, len(findings), 0)
+				return
+			}
+			assert.Equal(t, len(findings), 1)
+			assert.Equal(t, findings[0].Problem, tt.wantProblem)
+		})
+	}
+}
+
+func TestGitLabCheck(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	fakeclient, mux, teardown := thelp.Setup(ctx, t)
+	defer teardown()
+
+	mux.HandleFunc("/projects/11/hooks", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `[{"id": 1, "url": "https://controller.url", "push_events": true, "merge_requests_events": true, "note_events": true}]`)
+	})
+	mux.HandleFunc("/projects/12/hooks", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `[{"id": 2, "url": "https://controller.url", "push_events": true}]`)
+	})
+	mux.HandleFunc("/projects/13/hooks", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `[]`)
+	})
+
+	tests := []struct {
+		name        string
+		projectID   string
+		wantProblem string
+	}{
+		{
+			name:      "webhook pointing to the controller",
+			projectID: "11",
+		},
+		{
+			name:        "webhook missing events",
+			projectID:   "12",
+			wantProblem: "the webhook 2 on 12 does not send the merge request, comments events",
+		},
+		{
+			name:        "no webhook",
+			projectID:   "13",
+			wantProblem: "no webhook on 13 points to the controller URL https://controller.url",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gl := &gitLabConfig{Client: fakeclient, projectID: tt.projectID}
+			findings, err := gl.check(checkRepo, "https://controller.url")
+			assert.NilError(t, err)
+			if tt.wantProblem == "" {
+				assert.Equal(t, len(findings), 0)
+				return
+			}
+			assert.Equal(t, len(findings), 1)
+			assert.Equal(t, findings[0].Problem, tt.wantProblem)
+		})
+	}
+}
+
+func TestProviderTypeFromRepository(t *testing.T) {
+	assert.Equal(t, ProviderTypeFromRepository(checkRepo), "github")
+	assert.Equal(t, ProviderTypeFromRepository(&v1alpha1.Repository{
+		Spec: v1alpha1.RepositorySpec{URL: "https://gitlab.com/group/project"},
+	}), "gitlab")
+	assert.Equal(t, ProviderTypeFromRepository(&v1alpha1.Repository{
+		Spec: v1alpha1.RepositorySpec{
+			URL:         "https://git.example.com/group/project",
+			GitProvider: &v1alpha1.GitProvider{Type: "gitea"},
+		},
+	}), "gitea")
+	assert.Equal(t, ProviderTypeFromRepository(&v1alpha1.Repository{
+		Spec: v1alpha1.RepositorySpec{URL: "https://git.example.com/group/project"},
+	}), "unknown")
+}
@@ -134,6 +134,7 @@ func (gh *gitHubConfig) create(ctx context.Context) error {
 		Events: []string{
 			"issue_comment",
 			"pull_request",
+			"pull_request_review",
 			"push",
 		},
 		Config: map[string]interface{}{
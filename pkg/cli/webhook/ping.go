@@ -0,0 +1,176 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v49/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/bootstrap"
+)
+
+// pongFromRepository is the start of the message the controller answers a
+// ping it verified with.
+const pongFromRepository = "pong from Repository"
+
+// PingOptions are the options to ping the webhook of a Repository.
+type PingOptions struct {
+	CheckOptions
+	// Timeout is how long to wait for GitHub to deliver the ping.
+	Timeout time.Duration
+	// Interval is how often the deliveries of the webhook are looked at.
+	Interval time.Duration
+}
+
+// pingResponse is the body the controller answers the ping with.
+type pingResponse struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+}
+
+// Ping asks GitHub to send a ping to the webhook of the Repository and
+// verifies the controller received it, matched it to the Repository and
+// validated its signature. It returns the message of the controller when the
+// ping went through, or the findings telling where it broke.
+func Ping(ctx context.Context, opts *PingOptions) (string, []Finding, error) {
+	repo := opts.Repository
+	if providerType := ProviderTypeFromRepository(repo); providerType != "github" {
+		return "", nil, fmt.Errorf("pinging the webhook of a %s repository is not supported, only GitHub webhooks are", providerType)
+	}
+	if repo.Spec.GitProvider == nil || repo.Spec.GitProvider.Secret == nil {
+		return "", []Finding{{
+			Problem:    fmt.Sprintf("repository %s has no git_provider secret, its events are sent by the GitHub App and not by a webhook", repo.GetName()),
+			Suggestion: fmt.Sprintf("check the GitHub App installation with: tkn pac describe --check -n %s %s", repo.GetNamespace(), repo.GetName()),
+		}}, nil
+	}
+
+	installed, installationNS, err := bootstrap.DetectPacInstallation(ctx, opts.PACNamespace, opts.Run)
+	if !installed {
+		return "", nil, fmt.Errorf("pipelines as code not installed")
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	controllerURL, token, findings, ok := webhookSettings(ctx, &opts.CheckOptions, installationNS)
+	if !ok {
+		return "", findings, nil
+	}
+	gh, err := newGitHubConfig(repo, token)
+	if err != nil {
+		return "", nil, err
+	}
+	return gh.ping(ctx, repo, controllerURL, opts.Timeout, opts.Interval)
+}
+
+func (gh *gitHubConfig) ping(ctx context.Context, repo *v1alpha1.Repository, controllerURL string, timeout, interval time.Duration) (string, []Finding, error) {
+	ghClient, err := gh.newGHClientByToken(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	hook, finding, err := gh.findHook(ctx, ghClient, repo, controllerURL)
+	if err != nil {
+		return "", nil, err
+	}
+	if finding != nil {
+		return "", []Finding{*finding}, nil
+	}
+
+	// remember the deliveries made before the ping to spot the new one
+	seen := map[int64]bool{}
+	deliveries, _, err := ghClient.Repositories.ListHookDeliveries(ctx, gh.repoOwner, gh.repoName, hook.GetID(), &github.ListCursorOptions{PerPage: 100})
+	if err != nil {
+		return "", nil, fmt.Errorf("cannot list the deliveries of the webhook %d on %s/%s: %w", hook.GetID(), gh.repoOwner, gh.repoName, err)
+	}
+	for _, d := range deliveries {
+		seen[d.GetID()] = true
+	}
+
+	if _, err := ghClient.Repositories.PingHook(ctx, gh.repoOwner, gh.repoName, hook.GetID()); err != nil {
+		return "", nil, fmt.Errorf("cannot ping the webhook %d on %s/%s: %w", hook.GetID(), gh.repoOwner, gh.repoName, err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		deliveries, _, err := ghClient.Repositories.ListHookDeliveries(ctx, gh.repoOwner, gh.repoName, hook.GetID(), &github.ListCursorOptions{PerPage: 100})
+		if err != nil {
+			return "", nil, fmt.Errorf("cannot list the deliveries of the webhook %d on %s/%s: %w", hook.GetID(), gh.repoOwner, gh.repoName, err)
+		}
+		for _, d := range deliveries {
+			if seen[d.GetID()] || d.GetEvent() != "ping" {
+				continue
+			}
+			delivery, _, err := ghClient.Repositories.GetHookDelivery(ctx, gh.repoOwner, gh.repoName, hook.GetID(), d.GetID())
+			if err != nil {
+				return "", nil, fmt.Errorf("cannot get the delivery %d of the webhook %d on %s/%s: %w", d.GetID(), hook.GetID(), gh.repoOwner, gh.repoName, err)
+			}
+			return pingOutcome(repo, hook, delivery)
+		}
+		if time.Now().After(deadline) {
+			return "", []Finding{{
+				Problem:    fmt.Sprintf("GitHub has not delivered the ping to the webhook %d on %s/%s after %s", hook.GetID(), gh.repoOwner, gh.repoName, timeout),
+				Suggestion: "look at the recent deliveries in the webhook settings of the repository",
+			}}, nil
+		}
+		select {
+		case <-ctx.Done():
+			return "", nil, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// pingOutcome tells from the response of the controller recorded by GitHub
+// with the delivery where the chain broke.
+func pingOutcome(repo *v1alpha1.Repository, hook *github.Hook, delivery *github.HookDelivery) (string, []Finding, error) {
+	hookURL, _ := hook.Config["url"].(string)
+	message := deliveryMessage(delivery)
+	statusCode := delivery.GetStatusCode()
+	switch {
+	case statusCode == 0:
+		return "", []Finding{{
+			Problem:    fmt.Sprintf("GitHub could not deliver the ping to %s: %s", hookURL, delivery.GetStatus()),
+			Suggestion: "make sure the controller URL is reachable from GitHub, through a route or an ingress",
+		}}, nil
+	case statusCode >= 200 && statusCode < 300 && strings.HasPrefix(message, pongFromRepository):
+		return message, nil, nil
+	case statusCode >= 200 && statusCode < 300:
+		return "", []Finding{{
+			Problem:    fmt.Sprintf("the ping was delivered to %s but the controller did not verify it", hookURL),
+			Suggestion: "upgrade Pipelines as Code to a version verifying the webhook pings",
+		}}, nil
+	case statusCode == http.StatusUnauthorized:
+		// the controller doesn't tell why, it would tell the unauthenticated
+		// pings which Repositories are configured
+		return "", []Finding{{
+			Problem: fmt.Sprintf("the controller refused the ping: %s", message),
+			Suggestion: fmt.Sprintf("make sure the spec.url of the Repository %s is the URL of the repository on GitHub and set the same secret on the webhook and in the webhook_secret of the Repository with: tkn pac webhook add -n %s %s",
+				repo.GetName(), repo.GetNamespace(), repo.GetName()),
+		}}, nil
+	}
+	return "", []Finding{{
+		Problem:    fmt.Sprintf("the controller answered the ping with the status %d: %s", statusCode, message),
+		Suggestion: "look at the logs of the controller",
+	}}, nil
+}
+
+// deliveryMessage returns the message the controller answered the delivery
+// with, GitHub records the body of the response as a string.
+func deliveryMessage(delivery *github.HookDelivery) string {
+	if delivery.Response == nil || delivery.Response.RawPayload == nil {
+		return ""
+	}
+	body := []byte(*delivery.Response.RawPayload)
+	var s string
+	if err := json.Unmarshal(body, &s); err == nil {
+		body = []byte(s)
+	}
+	response := pingResponse{}
+	if err := json.Unmarshal(body, &response); err == nil && response.Message != "" {
+		return response.Message
+	}
+	return strings.TrimSpace(string(body))
+}
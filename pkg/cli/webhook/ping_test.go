@@ -0,0 +1,94 @@
+package webhook
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	ghtesthelper "github.com/openshift-pipelines/pipelines-as-code/pkg/test/github"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestGitHubPing(t *testing.T) {
+	tests := []struct {
+		name        string
+		delivery    string
+		noDelivery  bool
+		wantMessage string
+		wantProblem string
+	}{
+		{
+			name:        "verified by the controller",
+			delivery:    `{"id": 2, "event": "ping", "status_code": 200, "response": {"payload": "{\"status\":200,\"message\":\"pong from Repository ns/repo\"}\n"}}`,
+			wantMessage: "pong from Repository ns/repo",
+		},
+		{
+			name:        "not verified by the controller",
+			delivery:    `{"id": 2, "event": "ping", "status_code": 200, "response": {"payload": "{\"status\":200,\"message\":\"skipping non supported event\"}"}}`,
+			wantProblem: "the ping was delivered to https://controller.url but the controller did not verify it",
+		},
+		{
+			name:        "controller not reachable",
+			delivery:    `{"id": 2, "event": "ping", "status": "failed to connect to host", "status_code": 0}`,
+			wantProblem: "GitHub could not deliver the ping to https://controller.url: failed to connect to host",
+		},
+		{
+			name:        "refused by the controller",
+			delivery:    `{"id": 2, "event": "ping", "status_code": 401, "response": {"payload": "{\"status\":401,\"message\":\"the ping could not be verified, the reason is in the logs of the controller\"}"}}`,
+			wantProblem: "the controller refused the ping: the ping could not be verified, the reason is in the logs of the controller",
+		},
+		{
+			name:        "not delivered",
+			noDelivery:  true,
+			wantProblem: "GitHub has not delivered the ping to the webhook 1 on owner/repo after 10ms",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeclient, mux, _, teardown := ghtesthelper.SetupGH()
+			defer teardown()
+
+			pinged := false
+			mux.HandleFunc("/repos/owner/repo/hooks", func(w http.ResponseWriter, r *http.Request) {
+				_, _ = fmt.Fprint(w, `[{"id": 1, "active": true, "events": ["*"], "config": {"url": "https://controller.url"}}]`)
+			})
+			mux.HandleFunc("/repos/owner/repo/hooks/1/pings", func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, r.Method, http.MethodPost)
+				pinged = true
+				w.WriteHeader(http.StatusNoContent)
+			})
+			mux.HandleFunc("/repos/owner/repo/hooks/1/deliveries", func(w http.ResponseWriter, r *http.Request) {
+				if pinged && !tt.noDelivery {
+					_, _ = fmt.Fprint(w, `[{"id": 2, "event": "ping"}, {"id": 1, "event": "ping"}]`)
+					return
+				}
+				_, _ = fmt.Fprint(w, `[{"id": 1, "event": "ping"}]`)
+			})
+			mux.HandleFunc("/repos/owner/repo/hooks/1/deliveries/2", func(w http.ResponseWriter, r *http.Request) {
+				_, _ = fmt.Fprint(w, tt.delivery)
+			})
+
+			ctx, _ := rtesting.SetupFakeContext(t)
+			repo := &v1alpha1.Repository{
+				ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"},
+				Spec:       v1alpha1.RepositorySpec{URL: "https://github.com/owner/repo"},
+			}
+			gh := &gitHubConfig{Client: fakeclient, repoOwner: "owner", repoName: "repo"}
+			message, findings, err := gh.ping(ctx, repo, "https://controller.url", 10*time.Millisecond, time.Millisecond)
+			assert.NilError(t, err)
+			assert.Assert(t, pinged)
+			assert.Equal(t, message, tt.wantMessage)
+			if tt.wantProblem == "" {
+				assert.Equal(t, len(findings), 0)
+				return
+			}
+			assert.Equal(t, len(findings), 1)
+			assert.Equal(t, findings[0].Problem, tt.wantProblem)
+			assert.Assert(t, findings[0].Suggestion != "")
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/pipelineascode"
 	corev1 "k8s.io/api/core/v1"
@@ -13,7 +14,8 @@ import (
 func (w *Options) createWebhookSecret(ctx context.Context, response *response) error {
 	_, err := w.Run.Clients.Kube.CoreV1().Secrets(w.RepositoryNamespace).Create(ctx, &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: w.RepositoryName,
+			Name:   w.RepositoryName,
+			Labels: map[string]string{keys.GeneratedFor: w.RepositoryName},
 		},
 		Data: map[string][]byte{
 			pipelineascode.DefaultGitProviderSecretKey:        []byte(response.PersonalAccessToken),
@@ -26,7 +26,7 @@ const (
 	defaultWebForwarderURL = "https://smee.io"
 )
 
-var providerTargets = []string{"github-app", "github-enterprise-app"}
+var providerTargets = []string{"github-app", "github-enterprise-app", giteaProviderType}
 
 type bootstrapOpts struct {
 	providerType      string
@@ -42,12 +42,23 @@ type bootstrapOpts struct {
 	forwarderURL      string
 	dashboardURL      string
 
+	ingressHost           string
+	ingressClass          string
+	certManagerIssuer     string
+	certManagerIssuerKind string
+
 	RouteName              string
 	GithubAPIURL           string
 	GithubApplicationName  string
 	GithubApplicationURL   string
 	GithubOrganizationName string
 	forceGitHubApp         bool
+
+	GiteaURL             string
+	GiteaOrganization    string
+	GiteaUser            string
+	GiteaToken           string
+	GiteaSecretNamespace string
 }
 
 const infoConfigMap = "pipelines-as-code-info"
@@ -98,6 +109,10 @@ func install(ctx context.Context, run *params.Run, opts *bootstrapOpts) error {
 	} else if err := installPac(ctx, run, opts); err != nil {
 		return err
 	}
+
+	if opts.ingressHost != "" && opts.RouteName == "" {
+		return createControllerIngress(ctx, run, opts)
+	}
 	return nil
 }
 
@@ -150,6 +165,10 @@ func Command(run *params.Run, ioStreams *cli.IOStreams) *cobra.Command {
 				}
 			}
 
+			if opts.providerType == giteaProviderType {
+				return createGiteaSetup(ctx, run, opts)
+			}
+
 			if !opts.forceGitHubApp {
 				if info.IsGithubAppInstalled(ctx, run, opts.targetNamespace) {
 					fmt.Fprintln(opts.ioStreams.Out, "👌 Skips bootstrapping GitHub App, as one is already configured. Please pass --force-configure to override existing")
@@ -169,9 +188,12 @@ func Command(run *params.Run, ioStreams *cli.IOStreams) *cobra.Command {
 		},
 	}
 	cmd.AddCommand(GithubApp(run, ioStreams))
+	cmd.AddCommand(Gitea(run, ioStreams))
 
 	addCommonFlags(cmd, ioStreams)
 	addGithubAppFlag(cmd, opts)
+	addGiteaFlags(cmd, opts)
+	addIngressFlags(cmd, opts)
 
 	cmd.PersistentFlags().BoolVar(&opts.forceInstall, "force-install", false, "whether we should force pac install even if it's already installed")
 	cmd.PersistentFlags().BoolVar(&opts.skipInstall, "skip-install", false, "skip Pipelines as Code installation")
@@ -260,17 +282,20 @@ func DetectPacInstallation(ctx context.Context, wantedNS string, run *params.Run
 		return installed, "", fmt.Errorf("could not detect Pipelines as Code configmap in %s namespace : %w, please reinstall", wantedNS, err)
 	}
 
-	cms, err := run.Clients.Kube.CoreV1().ConfigMaps("").List(ctx, metav1.ListOptions{
-		LabelSelector: configMapPacLabel,
-	})
-	if err == nil {
-		for _, cm := range cms.Items {
-			if cm.Name == infoConfigMap {
-				return installed, cm.Namespace, nil
+	ns, err := run.Clients.CachedLookup("pac-namespace", func() (string, error) {
+		cms, err := run.Clients.Kube.CoreV1().ConfigMaps("").List(ctx, metav1.ListOptions{
+			LabelSelector: configMapPacLabel,
+		})
+		if err == nil {
+			for _, cm := range cms.Items {
+				if cm.Name == infoConfigMap {
+					return cm.Namespace, nil
+				}
 			}
 		}
-	}
-	return installed, "", fmt.Errorf("could not detect Pipelines as Code configmap on the cluster, please reinstall")
+		return "", fmt.Errorf("could not detect Pipelines as Code configmap on the cluster, please reinstall")
+	})
+	return installed, ns, err
 }
 
 func addGithubAppFlag(cmd *cobra.Command, opts *bootstrapOpts) {
@@ -0,0 +1,249 @@
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"code.gitea.io/sdk/gitea"
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli/prompt"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/pipelineascode"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/random"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	kapierror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	giteaProviderType = "gitea"
+	giteaTokenPrefix  = "pipelines-as-code"
+)
+
+// giteaHookEvents are the events Pipelines as Code is listening to on Gitea
+var giteaHookEvents = []string{"push", "pull_request", "issue_comment"}
+
+func Gitea(run *params.Run, ioStreams *cli.IOStreams) *cobra.Command {
+	opts := &bootstrapOpts{
+		ioStreams:    ioStreams,
+		providerType: giteaProviderType,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "gitea",
+		Long:  "A command helper to configure a Gitea (or Forgejo) instance with Pipelines as Code, it will create a token, register a webhook on an organization and store the token and the webhook secret in a Secret",
+		Short: "Configure a Gitea instance for PAC",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			opts.cliOpts = cli.NewCliOptions(cmd)
+			opts.ioStreams.SetColorEnabled(!opts.cliOpts.NoColoring)
+			if err := run.Clients.NewClients(ctx, &run.Info); err != nil {
+				return err
+			}
+
+			installed, ns, err := DetectPacInstallation(ctx, opts.targetNamespace, run)
+			if !installed {
+				return fmt.Errorf("pipelines as code is not installed, run \"%s pac bootstrap --install-type %s\" first",
+					settings.TknBinaryName, giteaProviderType)
+			}
+			if err != nil {
+				return err
+			}
+			opts.targetNamespace = ns
+
+			return createGiteaSetup(ctx, run, opts)
+		},
+		Annotations: map[string]string{
+			"commandType": "main",
+		},
+	}
+	addCommonFlags(cmd, ioStreams)
+	addGiteaFlags(cmd, opts)
+
+	cmd.PersistentFlags().StringVar(&opts.RouteName, "route-url", "", "The public URL for the pipelines-as-code controller")
+	cmd.PersistentFlags().StringVarP(&opts.targetNamespace, "namespace", "n", "", "target namespace where pac is installed")
+	return cmd
+}
+
+// createGiteaSetup configures the Gitea instance: it creates a token if none
+// has been provided, registers the webhook on the organization and stores the
+// token and the webhook secret in a Secret to be referenced by the Repository
+// CRs.
+func createGiteaSetup(ctx context.Context, run *params.Run, opts *bootstrapOpts) error {
+	if opts.RouteName == "" {
+		opts.RouteName, _ = DetectOpenShiftRoute(ctx, run, opts.targetNamespace)
+		if opts.RouteName != "" {
+			opts.autoDetectedRoute = true
+		}
+	}
+	if err := askGiteaQuestions(opts); err != nil {
+		return err
+	}
+	if opts.GiteaSecretNamespace == "" {
+		opts.GiteaSecretNamespace = run.Info.Kube.Namespace
+	}
+
+	if opts.GiteaToken == "" {
+		token, err := createGiteaToken(opts)
+		if err != nil {
+			return err
+		}
+		opts.GiteaToken = token
+		fmt.Fprintf(opts.ioStreams.Out, "🔑 Token has been created for the user %s on %s\n", opts.GiteaUser, opts.GiteaURL)
+	}
+
+	webhookSecret := random.AlphaString(12)
+	if err := createGiteaWebhook(opts, webhookSecret); err != nil {
+		return err
+	}
+	fmt.Fprintf(opts.ioStreams.Out, "✓ Webhook has been created on the %s organization\n", opts.GiteaOrganization)
+
+	secret, err := createGiteaSecret(ctx, run, opts, webhookSecret)
+	if err != nil {
+		return err
+	}
+
+	if err := info.UpdateInfoConfigMap(ctx, run, &info.Options{
+		TargetNamespace: opts.targetNamespace,
+		ControllerURL:   opts.RouteName,
+		Provider:        giteaProviderType,
+	}); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(opts.ioStreams.Out, `🚀 You can now reference the secret in the Repository CRs of the %s organization, ie:
+
+  git_provider:
+    url: %s
+    secret:
+      name: %s
+      key: %s
+    webhook_secret:
+      name: %s
+      key: %s
+
+`, opts.GiteaOrganization, opts.GiteaURL, secret, pipelineascode.DefaultGitProviderSecretKey, secret, pipelineascode.DefaultGitProviderWebhookSecretKey)
+	return nil
+}
+
+func askGiteaQuestions(opts *bootstrapOpts) error {
+	var qs []*survey.Question
+	if opts.GiteaURL == "" {
+		qs = append(qs, &survey.Question{
+			Name:     "GiteaURL",
+			Prompt:   &survey.Input{Message: "Enter the URL of your Gitea instance: "},
+			Validate: survey.Required,
+		})
+	}
+	if opts.GiteaOrganization == "" {
+		qs = append(qs, &survey.Question{
+			Name:     "GiteaOrganization",
+			Prompt:   &survey.Input{Message: "Enter the Gitea organization where the webhook will be configured: "},
+			Validate: survey.Required,
+		})
+	}
+	if opts.GiteaToken == "" && opts.GiteaUser == "" {
+		qs = append(qs, &survey.Question{
+			Name:     "GiteaUser",
+			Prompt:   &survey.Input{Message: "Enter the Gitea username to create the token with: "},
+			Validate: survey.Required,
+		})
+	}
+	if len(qs) > 0 {
+		if err := prompt.SurveyAsk(qs, opts); err != nil {
+			return err
+		}
+	}
+
+	if !strings.HasPrefix(opts.GiteaURL, "http") {
+		opts.GiteaURL = "https://" + opts.GiteaURL
+	}
+	opts.GiteaURL = strings.TrimSuffix(opts.GiteaURL, "/")
+
+	return askRouteURL(opts)
+}
+
+// createGiteaToken creates a token for the user, Gitea only allows to create
+// tokens with basic auth, the password is only used for this.
+func createGiteaToken(opts *bootstrapOpts) (string, error) {
+	var password string
+	if err := prompt.SurveyAskOne(&survey.Password{
+		Message: fmt.Sprintf("Enter the password of the Gitea user %s: ", opts.GiteaUser),
+	}, &password, survey.WithValidator(survey.Required)); err != nil {
+		return "", err
+	}
+
+	client, err := gitea.NewClient(opts.GiteaURL, gitea.SetBasicAuth(opts.GiteaUser, password))
+	if err != nil {
+		return "", err
+	}
+	token, _, err := client.CreateAccessToken(gitea.CreateAccessTokenOption{
+		Name: fmt.Sprintf("%s-%s", giteaTokenPrefix, random.AlphaString(5)),
+	})
+	if err != nil {
+		return "", fmt.Errorf("cannot create a token for user %s on %s: %w", opts.GiteaUser, opts.GiteaURL, err)
+	}
+	return token.Token, nil
+}
+
+func createGiteaWebhook(opts *bootstrapOpts, webhookSecret string) error {
+	client, err := gitea.NewClient(opts.GiteaURL, gitea.SetToken(opts.GiteaToken))
+	if err != nil {
+		return err
+	}
+	_, _, err = client.CreateOrgHook(opts.GiteaOrganization, gitea.CreateHookOption{
+		Type:   gitea.HookTypeGitea,
+		Active: true,
+		Config: map[string]string{
+			"url":          opts.RouteName,
+			"content_type": "json",
+			"secret":       webhookSecret,
+		},
+		Events: giteaHookEvents,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot create webhook on organization %s: %w", opts.GiteaOrganization, err)
+	}
+	return nil
+}
+
+// createGiteaSecret stores the token and the webhook secret in a Secret named
+// after the organization, it is updated if it already exists.
+func createGiteaSecret(ctx context.Context, run *params.Run, opts *bootstrapOpts, webhookSecret string) (string, error) {
+	name := fmt.Sprintf("%s-%s", giteaProviderType, strings.ToLower(opts.GiteaOrganization))
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				"app.kubernetes.io/part-of": "pipelines-as-code",
+			},
+		},
+		Data: map[string][]byte{
+			pipelineascode.DefaultGitProviderSecretKey:        []byte(opts.GiteaToken),
+			pipelineascode.DefaultGitProviderWebhookSecretKey: []byte(webhookSecret),
+		},
+	}
+	secrets := run.Clients.Kube.CoreV1().Secrets(opts.GiteaSecretNamespace)
+	_, err := secrets.Create(ctx, secret, metav1.CreateOptions{})
+	if kapierror.IsAlreadyExists(err) {
+		_, err = secrets.Update(ctx, secret, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintf(opts.ioStreams.Out, "🔑 Secret %s has been created in the %s namespace\n", name, opts.GiteaSecretNamespace)
+	return name, nil
+}
+
+func addGiteaFlags(cmd *cobra.Command, opts *bootstrapOpts) {
+	cmd.PersistentFlags().StringVar(&opts.GiteaURL, "gitea-url", "", "The URL of the Gitea instance")
+	cmd.PersistentFlags().StringVar(&opts.GiteaOrganization, "gitea-organization", "", "The Gitea organization where the webhook will be configured")
+	cmd.PersistentFlags().StringVar(&opts.GiteaUser, "gitea-user", "", "The Gitea user used to create the token")
+	cmd.PersistentFlags().StringVar(&opts.GiteaToken, "gitea-token", "", "Use this Gitea token instead of creating a new one")
+	cmd.PersistentFlags().StringVar(&opts.GiteaSecretNamespace, "gitea-secret-namespace", "", "The namespace where to store the Secret with the token, where your Repository CRs are (default: the current namespace)")
+}
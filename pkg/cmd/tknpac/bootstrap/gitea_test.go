@@ -0,0 +1,132 @@
+package bootstrap
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"code.gitea.io/sdk/gitea"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli/prompt"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/pipelineascode"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestCreateGiteaSetup(t *testing.T) {
+	tests := []struct {
+		name          string
+		token         string
+		user          string
+		askStubs      func(*prompt.AskStubber)
+		hookStatus    int
+		wantErr       string
+		wantToken     string
+		wantTokenUser string
+	}{
+		{
+			name:       "with a token",
+			token:      "existingtoken",
+			hookStatus: http.StatusCreated,
+			wantToken:  "existingtoken",
+		},
+		{
+			name: "create a token",
+			user: "admin",
+			askStubs: func(as *prompt.AskStubber) {
+				as.StubOne("password")
+			},
+			hookStatus:    http.StatusCreated,
+			wantToken:     "newtoken",
+			wantTokenUser: "admin",
+		},
+		{
+			name:       "cannot create webhook",
+			token:      "existingtoken",
+			hookStatus: http.StatusNotFound,
+			wantErr:    "cannot create webhook on organization org",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokenUser := ""
+			hook := gitea.CreateHookOption{}
+			mux := http.NewServeMux()
+			mux.HandleFunc("/api/v1/version", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"version": "1.17.0"}`)
+			})
+			mux.HandleFunc("/api/v1/users/admin/tokens", func(w http.ResponseWriter, r *http.Request) {
+				tokenUser, _, _ = r.BasicAuth()
+				w.WriteHeader(http.StatusCreated)
+				fmt.Fprint(w, `{"name": "pipelines-as-code", "sha1": "newtoken"}`)
+			})
+			mux.HandleFunc("/api/v1/orgs/org/hooks", func(w http.ResponseWriter, r *http.Request) {
+				assert.NilError(t, json.NewDecoder(r.Body).Decode(&hook))
+				w.WriteHeader(tt.hookStatus)
+				fmt.Fprint(w, `{}`)
+			})
+			server := httptest.NewServer(mux)
+			defer server.Close()
+
+			as, teardown := prompt.InitAskStubber()
+			defer teardown()
+			if tt.askStubs != nil {
+				tt.askStubs(as)
+			}
+
+			ctx, _ := rtesting.SetupFakeContext(t)
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+				ConfigMap: []*corev1.ConfigMap{
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: infoConfigMap, Namespace: pacNS},
+						Data:       map[string]string{},
+					},
+				},
+			})
+			run := &params.Run{
+				Clients: clients.Clients{Kube: stdata.Kube},
+				Info:    info.Info{Kube: info.KubeOpts{Namespace: "repos"}},
+			}
+			ioStreams, _, out, _ := cli.IOTest()
+			opts := &bootstrapOpts{
+				ioStreams:         ioStreams,
+				targetNamespace:   pacNS,
+				RouteName:         "https://pac.route",
+				GiteaURL:          server.URL,
+				GiteaOrganization: "org",
+				GiteaUser:         tt.user,
+				GiteaToken:        tt.token,
+			}
+			err := createGiteaSetup(ctx, run, opts)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, tokenUser, tt.wantTokenUser)
+
+			assert.Equal(t, hook.Type, gitea.HookTypeGitea)
+			assert.Equal(t, hook.Config["url"], "https://pac.route")
+			assert.DeepEqual(t, hook.Events, giteaHookEvents)
+
+			secret, err := stdata.Kube.CoreV1().Secrets("repos").Get(ctx, "gitea-org", metav1.GetOptions{})
+			assert.NilError(t, err)
+			assert.Equal(t, string(secret.Data[pipelineascode.DefaultGitProviderSecretKey]), tt.wantToken)
+			assert.Equal(t, string(secret.Data[pipelineascode.DefaultGitProviderWebhookSecretKey]), hook.Config["secret"])
+			assert.Assert(t, hook.Config["secret"] != "")
+
+			cm, err := stdata.Kube.CoreV1().ConfigMaps(pacNS).Get(ctx, infoConfigMap, metav1.GetOptions{})
+			assert.NilError(t, err)
+			assert.Equal(t, cm.Data["provider"], giteaProviderType)
+			assert.Assert(t, out.String() != "")
+		})
+	}
+}
@@ -21,6 +21,7 @@ func generateManifest(opts *bootstrapOpts) ([]byte, error) {
 			"check_run",
 			"issue_comment",
 			"pull_request",
+			"pull_request_review",
 			"push",
 		},
 		DefaultPermissions: &github.InstallationPermissions{
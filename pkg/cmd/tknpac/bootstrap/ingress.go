@@ -0,0 +1,164 @@
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/spf13/cobra"
+	networkingv1 "k8s.io/api/networking/v1"
+	kapierror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	controllerServiceName = "pipelines-as-code-controller"
+	controllerServicePort = 8080
+	controllerIngressName = "pipelines-as-code-controller"
+	controllerTLSSecret   = "pipelines-as-code-controller-tls"
+
+	certManagerGroup             = "cert-manager.io"
+	certManagerVersion           = "v1"
+	certManagerResource          = "certificates"
+	defaultCertManagerIssuerKind = "ClusterIssuer"
+)
+
+var certificateGVR = schema.GroupVersionResource{
+	Group: certManagerGroup, Version: certManagerVersion, Resource: certManagerResource,
+}
+
+func addIngressFlags(cmd *cobra.Command, opts *bootstrapOpts) {
+	cmd.PersistentFlags().StringVar(&opts.ingressHost, "ingress-host", "",
+		"Create an Ingress exposing the controller on this host, instead of the gosmee forwarder on Kubernetes")
+	cmd.PersistentFlags().StringVar(&opts.ingressClass, "ingress-class", "", "The IngressClass of the Ingress of the controller")
+	cmd.PersistentFlags().StringVar(&opts.certManagerIssuer, "cert-manager-issuer", "",
+		"Create a cert-manager Certificate for the Ingress host signed by this issuer and serve the controller over TLS")
+	cmd.PersistentFlags().StringVar(&opts.certManagerIssuerKind, "cert-manager-issuer-kind", defaultCertManagerIssuerKind,
+		"The kind of the cert-manager issuer, Issuer or ClusterIssuer")
+}
+
+// createControllerIngress exposes the controller with an Ingress on the
+// ingress host, with a cert-manager Certificate for its TLS when an issuer is
+// given, and sets the controller URL to it.
+func createControllerIngress(ctx context.Context, run *params.Run, opts *bootstrapOpts) error {
+	if opts.certManagerIssuerKind != "Issuer" && opts.certManagerIssuerKind != "ClusterIssuer" {
+		return fmt.Errorf("invalid cert-manager issuer kind %q, it needs to be Issuer or ClusterIssuer", opts.certManagerIssuerKind)
+	}
+	protocol := "http"
+	if opts.certManagerIssuer != "" {
+		installed, err := checkGroupInstalled(run, certManagerGroup)
+		if err != nil {
+			return err
+		}
+		if !installed {
+			return fmt.Errorf("cert-manager has not been found on this cluster, install it or create the Ingress without --cert-manager-issuer")
+		}
+		if err := applyCertificate(ctx, run, opts); err != nil {
+			return err
+		}
+		fmt.Fprintf(opts.ioStreams.Out, "🔒 Certificate %s for %s has been created in the %s namespace\n",
+			controllerIngressName, opts.ingressHost, opts.targetNamespace)
+		protocol = "https"
+	}
+
+	if err := applyIngress(ctx, run, opts); err != nil {
+		return err
+	}
+	opts.RouteName = fmt.Sprintf("%s://%s", protocol, opts.ingressHost)
+	fmt.Fprintf(opts.ioStreams.Out, "🌍 Ingress %s exposing the controller on %s has been created in the %s namespace\n",
+		controllerIngressName, opts.RouteName, opts.targetNamespace)
+	return nil
+}
+
+func controllerIngress(opts *bootstrapOpts) *networkingv1.Ingress {
+	pathType := networkingv1.PathTypePrefix
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      controllerIngressName,
+			Namespace: opts.targetNamespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/part-of": "pipelines-as-code",
+				"pipelines-as-code/route":   "controller",
+			},
+		},
+		Spec: networkingv1.IngressSpec{
+			Rules: []networkingv1.IngressRule{{
+				Host: opts.ingressHost,
+				IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{
+					Paths: []networkingv1.HTTPIngressPath{{
+						Path:     "/",
+						PathType: &pathType,
+						Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{
+							Name: controllerServiceName,
+							Port: networkingv1.ServiceBackendPort{Number: controllerServicePort},
+						}},
+					}},
+				}},
+			}},
+		},
+	}
+	if opts.ingressClass != "" {
+		ingress.Spec.IngressClassName = &opts.ingressClass
+	}
+	if opts.certManagerIssuer != "" {
+		ingress.Spec.TLS = []networkingv1.IngressTLS{{Hosts: []string{opts.ingressHost}, SecretName: controllerTLSSecret}}
+	}
+	return ingress
+}
+
+func applyIngress(ctx context.Context, run *params.Run, opts *bootstrapOpts) error {
+	ingress := controllerIngress(opts)
+	ingresses := run.Clients.Kube.NetworkingV1().Ingresses(opts.targetNamespace)
+	_, err := ingresses.Create(ctx, ingress, metav1.CreateOptions{})
+	if !kapierror.IsAlreadyExists(err) {
+		return err
+	}
+	existing, err := ingresses.Get(ctx, ingress.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	existing.Spec = ingress.Spec
+	_, err = ingresses.Update(ctx, existing, metav1.UpdateOptions{})
+	return err
+}
+
+func controllerCertificate(opts *bootstrapOpts) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": fmt.Sprintf("%s/%s", certManagerGroup, certManagerVersion),
+		"kind":       "Certificate",
+		"metadata": map[string]interface{}{
+			"name":      controllerIngressName,
+			"namespace": opts.targetNamespace,
+			"labels": map[string]interface{}{
+				"app.kubernetes.io/part-of": "pipelines-as-code",
+			},
+		},
+		"spec": map[string]interface{}{
+			"secretName": controllerTLSSecret,
+			"dnsNames":   []interface{}{opts.ingressHost},
+			"issuerRef": map[string]interface{}{
+				"name":  opts.certManagerIssuer,
+				"kind":  opts.certManagerIssuerKind,
+				"group": certManagerGroup,
+			},
+		},
+	}}
+}
+
+func applyCertificate(ctx context.Context, run *params.Run, opts *bootstrapOpts) error {
+	certificate := controllerCertificate(opts)
+	certificates := run.Clients.Dynamic.Resource(certificateGVR).Namespace(opts.targetNamespace)
+	_, err := certificates.Create(ctx, certificate, metav1.CreateOptions{})
+	if !kapierror.IsAlreadyExists(err) {
+		return err
+	}
+	existing, err := certificates.Get(ctx, certificate.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	existing.Object["spec"] = certificate.Object["spec"]
+	_, err = certificates.Update(ctx, existing, metav1.UpdateOptions{})
+	return err
+}
@@ -0,0 +1,127 @@
+package bootstrap
+
+import (
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestCreateControllerIngress(t *testing.T) {
+	tests := []struct {
+		name                string
+		issuer              string
+		issuerKind          string
+		ingressClass        string
+		certManager         bool
+		existingCertificate bool
+		wantErr             string
+		wantURL             string
+	}{
+		{
+			name:       "without tls",
+			issuerKind: defaultCertManagerIssuerKind,
+			wantURL:    "http://pac.example.com",
+		},
+		{
+			name:         "with cert-manager",
+			issuer:       "letsencrypt",
+			issuerKind:   defaultCertManagerIssuerKind,
+			ingressClass: "nginx",
+			certManager:  true,
+			wantURL:      "https://pac.example.com",
+		},
+		{
+			name:                "update the existing certificate",
+			issuer:              "letsencrypt",
+			issuerKind:          "Issuer",
+			certManager:         true,
+			existingCertificate: true,
+			wantURL:             "https://pac.example.com",
+		},
+		{
+			name:       "cert-manager not installed",
+			issuer:     "letsencrypt",
+			issuerKind: defaultCertManagerIssuerKind,
+			wantErr:    "cert-manager has not been found on this cluster, install it or create the Ingress without --cert-manager-issuer",
+		},
+		{
+			name:       "invalid issuer kind",
+			issuer:     "letsencrypt",
+			issuerKind: "Authority",
+			wantErr:    `invalid cert-manager issuer kind "Authority", it needs to be Issuer or ClusterIssuer`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			kube := kubefake.NewSimpleClientset()
+			if tt.certManager {
+				fd, _ := kube.Discovery().(*fakediscovery.FakeDiscovery)
+				fd.Resources = []*metav1.APIResourceList{{GroupVersion: "cert-manager.io/v1"}}
+			}
+			objects := []runtime.Object{}
+			if tt.existingCertificate {
+				objects = append(objects, &unstructured.Unstructured{Object: map[string]interface{}{
+					"apiVersion": "cert-manager.io/v1",
+					"kind":       "Certificate",
+					"metadata":   map[string]interface{}{"name": controllerIngressName, "namespace": "pac"},
+					"spec":       map[string]interface{}{"secretName": "old"},
+				}})
+			}
+			dynamic := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), objects...)
+			run := &params.Run{Clients: clients.Clients{Kube: kube, Dynamic: dynamic}}
+			io, _, _, _ := cli.IOTest()
+			opts := &bootstrapOpts{
+				ioStreams:             io,
+				targetNamespace:       "pac",
+				ingressHost:           "pac.example.com",
+				ingressClass:          tt.ingressClass,
+				certManagerIssuer:     tt.issuer,
+				certManagerIssuerKind: tt.issuerKind,
+			}
+
+			err := createControllerIngress(ctx, run, opts)
+			if tt.wantErr != "" {
+				assert.Error(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, opts.RouteName, tt.wantURL)
+
+			ingress, err := kube.NetworkingV1().Ingresses("pac").Get(ctx, controllerIngressName, metav1.GetOptions{})
+			assert.NilError(t, err)
+			assert.Equal(t, ingress.Spec.Rules[0].Host, "pac.example.com")
+			backend := ingress.Spec.Rules[0].HTTP.Paths[0].Backend.Service
+			assert.Equal(t, backend.Name, controllerServiceName)
+			assert.Equal(t, backend.Port.Number, int32(controllerServicePort))
+			if tt.ingressClass != "" {
+				assert.Equal(t, *ingress.Spec.IngressClassName, tt.ingressClass)
+			}
+
+			certificate, err := dynamic.Resource(certificateGVR).Namespace("pac").Get(ctx, controllerIngressName, metav1.GetOptions{})
+			if tt.issuer == "" {
+				assert.Equal(t, len(ingress.Spec.TLS), 0)
+				assert.ErrorContains(t, err, "not found")
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, ingress.Spec.TLS[0].SecretName, controllerTLSSecret)
+			secretName, _, _ := unstructured.NestedString(certificate.Object, "spec", "secretName")
+			assert.Equal(t, secretName, controllerTLSSecret)
+			issuerName, _, _ := unstructured.NestedString(certificate.Object, "spec", "issuerRef", "name")
+			assert.Equal(t, issuerName, tt.issuer)
+			issuerKind, _, _ := unstructured.NestedString(certificate.Object, "spec", "issuerRef", "kind")
+			assert.Equal(t, issuerKind, tt.issuerKind)
+		})
+	}
+}
@@ -113,7 +113,7 @@ func installGosmeeForwarder(opts *bootstrapOpts) error {
 		return err
 	}
 	if !gosmeInstall {
-		return fmt.Errorf("please install a ingress object pointing to the controller service as documented here: https://is.gd/FzI0eb and pass the full ingress url as argument to the --route-url flag")
+		return fmt.Errorf("please install a ingress object pointing to the controller service as documented here: https://is.gd/FzI0eb and pass the full ingress url as argument to the --route-url flag, or let me create it with the --ingress-host flag")
 	}
 
 	// maybe we can use https://webhook.chmouel.com too
@@ -176,7 +176,7 @@ func installPac(ctx context.Context, run *params.Run, opts *bootstrapOpts) error
 
 	fmt.Fprintf(opts.ioStreams.Out, "✓ Pipelines-as-Code %s has been installed\n", latestVersion)
 
-	if !isOpenShift && opts.RouteName == "" {
+	if !isOpenShift && opts.RouteName == "" && opts.ingressHost == "" {
 		if err := installGosmeeForwarder(opts); err != nil {
 			return err
 		}
@@ -3,6 +3,7 @@ package bootstrap
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/google/go-github/v49/github"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
@@ -49,15 +50,37 @@ func checkOpenshiftRoute(run *params.Run) (bool, error) {
 }
 
 func checkGroupInstalled(run *params.Run, resourceGroup string) (bool, error) {
-	sg, err := run.Clients.Kube.Discovery().ServerGroups()
+	serverGroups := func() (string, error) {
+		sg, err := run.Clients.Kube.Discovery().ServerGroups()
+		if err != nil {
+			return "", err
+		}
+		names := make([]string, 0, len(sg.Groups))
+		for _, t := range sg.Groups {
+			names = append(names, t.Name)
+		}
+		return strings.Join(names, ","), nil
+	}
+	groups, err := run.Clients.CachedLookup("server-groups", serverGroups)
 	if err != nil {
 		return false, err
 	}
-	found := false
-	for _, t := range sg.Groups {
-		if t.Name == resourceGroup {
-			found = true
+	if hasGroup(groups, resourceGroup) {
+		return true, nil
+	}
+	// the group may have been installed since the groups have been cached
+	groups, err = serverGroups()
+	if err != nil {
+		return false, err
+	}
+	return hasGroup(groups, resourceGroup), nil
+}
+
+func hasGroup(groups, resourceGroup string) bool {
+	for _, name := range strings.Split(groups, ",") {
+		if name == resourceGroup {
+			return true
 		}
 	}
-	return found, nil
+	return false
 }
@@ -60,6 +60,20 @@ func askQuestions(opts *bootstrapOpts) error {
 		opts.GithubAPIURL = "https://" + strings.Trim(opts.GithubAPIURL, "/")
 	}
 
+	if err := askRouteURL(opts); err != nil {
+		return err
+	}
+
+	if opts.GithubApplicationURL == "" {
+		opts.GithubApplicationURL = opts.RouteName
+	}
+
+	return nil
+}
+
+// askRouteURL ask the public URL of the controller, confirming the detected
+// OpenShift Route if there is one.
+func askRouteURL(opts *bootstrapOpts) error {
 	if opts.autoDetectedRoute && opts.RouteName != "" {
 		answer, err := askYN(true,
 			fmt.Sprintf("👀 I have detected an OpenShift Route on: %s", opts.RouteName),
@@ -80,9 +94,5 @@ func askQuestions(opts *bootstrapOpts) error {
 		}
 	}
 
-	if opts.GithubApplicationURL == "" {
-		opts.GithubApplicationURL = opts.RouteName
-	}
-
 	return nil
 }
@@ -0,0 +1,110 @@
+package controller
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// bundleVersion is the version of the format of the bundles.
+const bundleVersion = "v1"
+
+// bundle is the portable export of a Pipelines as Code installation: its
+// settings, its Repositories with their run history and the references to
+// the secrets they use, the content of the secrets is never exported.
+type bundle struct {
+	Version      string                `json:"version"`
+	ExportedAt   metav1.Time           `json:"exportedAt"`
+	Settings     map[string]string     `json:"settings,omitempty"`
+	Repositories []v1alpha1.Repository `json:"repositories,omitempty"`
+	Secrets      []secretReference     `json:"secrets,omitempty"`
+}
+
+// secretReference is a secret used by the Repositories, it needs to be
+// created again on the new cluster.
+type secretReference struct {
+	Namespace string   `json:"namespace"`
+	Name      string   `json:"name"`
+	Keys      []string `json:"keys,omitempty"`
+}
+
+// portableRepository returns a copy of the Repository with its run history
+// but without the metadata of the cluster it comes from and without its
+// conditions, the controller computes them again on the new cluster.
+func portableRepository(repo *v1alpha1.Repository) v1alpha1.Repository {
+	return v1alpha1.Repository{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       pipelinesascode.RepositoryKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        repo.GetName(),
+			Namespace:   repo.GetNamespace(),
+			Labels:      repo.GetLabels(),
+			Annotations: repo.GetAnnotations(),
+		},
+		Spec:   repo.Spec,
+		Status: repo.Status,
+	}
+}
+
+// secretReferences returns the secrets referenced by the Repositories, sorted
+// by namespace and name.
+func secretReferences(repos []v1alpha1.Repository) []secretReference {
+	refs := map[string]*secretReference{}
+	add := func(namespace string, secret *v1alpha1.Secret) {
+		if secret == nil || secret.Name == "" {
+			return
+		}
+		id := namespace + "/" + secret.Name
+		ref, ok := refs[id]
+		if !ok {
+			ref = &secretReference{Namespace: namespace, Name: secret.Name}
+			refs[id] = ref
+		}
+		if secret.Key == "" {
+			return
+		}
+		for _, key := range ref.Keys {
+			if key == secret.Key {
+				return
+			}
+		}
+		ref.Keys = append(ref.Keys, secret.Key)
+	}
+
+	for i := range repos {
+		repo := &repos[i]
+		ns := repo.GetNamespace()
+		if repo.Spec.GitProvider != nil {
+			add(ns, repo.Spec.GitProvider.Secret)
+			add(ns, repo.Spec.GitProvider.WebhookSecret)
+		}
+		if repo.Spec.Incomings != nil {
+			for j := range *repo.Spec.Incomings {
+				add(ns, &(*repo.Spec.Incomings)[j].Secret)
+			}
+		}
+		for j := range repo.Spec.StatusSinks {
+			add(ns, repo.Spec.StatusSinks[j].Secret)
+		}
+		for _, env := range repo.Spec.Env {
+			if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil {
+				add(ns, &v1alpha1.Secret{Name: env.ValueFrom.SecretKeyRef.Name, Key: env.ValueFrom.SecretKeyRef.Key})
+			}
+		}
+	}
+
+	ret := make([]secretReference, 0, len(refs))
+	for _, ref := range refs {
+		sort.Strings(ref.Keys)
+		ret = append(ret, *ref)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return fmt.Sprintf("%s/%s", ret[i].Namespace, ret[i].Name) < fmt.Sprintf("%s/%s", ret[j].Namespace, ret[j].Name)
+	})
+	return ret
+}
@@ -0,0 +1,147 @@
+package controller
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	tcli "github.com/openshift-pipelines/pipelines-as-code/pkg/test/cli"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+	"sigs.k8s.io/yaml"
+)
+
+func TestExportImportBundle(t *testing.T) {
+	ns := "pipelines-as-code"
+	infoConfigMap := func() *corev1.ConfigMap {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "pipelines-as-code-info", Namespace: ns}}
+	}
+	repo := &v1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "repo",
+			Namespace:       "ns",
+			ResourceVersion: "12",
+			UID:             types.UID("uid"),
+		},
+		Spec: v1alpha1.RepositorySpec{
+			URL: "https://github.com/owner/repo",
+			GitProvider: &v1alpha1.GitProvider{
+				Secret:        &v1alpha1.Secret{Name: "token", Key: "provider.token"},
+				WebhookSecret: &v1alpha1.Secret{Name: "token", Key: "webhook.secret"},
+			},
+			Incomings: &[]v1alpha1.Incoming{{Type: "webhook-url", Secret: v1alpha1.Secret{Name: "incoming"}, Targets: []string{"main"}}},
+		},
+		Status: []v1alpha1.RepositoryRunStatus{{
+			Status:          duckv1.Status{ObservedGeneration: 1},
+			PipelineRunName: "pr-1",
+		}},
+	}
+
+	ctx, _ := rtesting.SetupFakeContext(t)
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+		Repositories: []*v1alpha1.Repository{repo},
+		ConfigMap: []*corev1.ConfigMap{
+			infoConfigMap(),
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: params.PACConfigmapName, Namespace: ns},
+				Data:       map[string]string{"application-name": "My CI"},
+			},
+		},
+	})
+	run := &params.Run{Clients: clients.Clients{PipelineAsCode: stdata.PipelineAsCode, Kube: stdata.Kube}}
+	io, _ := tcli.NewIOStream()
+	out := &bytes.Buffer{}
+	assert.NilError(t, exportBundle(ctx, &exportOptions{run: run, ioStreams: io, pacNamespace: ns}, out))
+
+	b := bundle{}
+	assert.NilError(t, yaml.Unmarshal(out.Bytes(), &b))
+	assert.Equal(t, b.Version, bundleVersion)
+	assert.DeepEqual(t, b.Settings, map[string]string{"application-name": "My CI"})
+	assert.Equal(t, len(b.Repositories), 1)
+	assert.Equal(t, b.Repositories[0].GetResourceVersion(), "")
+	assert.Equal(t, string(b.Repositories[0].GetUID()), "")
+	assert.DeepEqual(t, b.Secrets, []secretReference{
+		{Namespace: "ns", Name: "incoming"},
+		{Namespace: "ns", Name: "token", Keys: []string{"provider.token", "webhook.secret"}},
+	})
+
+	tests := []struct {
+		name             string
+		namespaces       []*corev1.Namespace
+		secrets          []*corev1.Secret
+		createNamespaces bool
+		wantImported     bool
+		wantErrOut       string
+	}{
+		{
+			name:         "import",
+			namespaces:   []*corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: "ns"}}},
+			wantImported: true,
+			wantErrOut: "! Secret incoming in the ns namespace is missing\n" +
+				"! Secret token in the ns namespace is missing, it needs the keys: provider.token, webhook.secret\n",
+		},
+		{
+			name:             "create the namespaces",
+			createNamespaces: true,
+			secrets: []*corev1.Secret{
+				{ObjectMeta: metav1.ObjectMeta{Name: "incoming", Namespace: "ns"}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "token", Namespace: "ns"}, Data: map[string][]byte{"provider.token": []byte("t")}},
+			},
+			wantImported: true,
+			wantErrOut:   "! Secret token in the ns namespace is incomplete, it needs the keys: webhook.secret\n",
+		},
+		{
+			name: "missing namespace",
+			wantErrOut: "! cannot create the Repository ns/repo: the namespace ns does not exist, create it or use --create-namespaces\n" +
+				"! Secret incoming in the ns namespace is missing\n" +
+				"! Secret token in the ns namespace is missing, it needs the keys: provider.token, webhook.secret\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+				Namespaces: tt.namespaces,
+				Secret:     tt.secrets,
+				ConfigMap: []*corev1.ConfigMap{
+					infoConfigMap(),
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: params.PACConfigmapName, Namespace: ns},
+						Data:       map[string]string{"application-name": "Pipelines as Code CI", "hub-url": "https://hub"},
+					},
+				},
+			})
+			run := &params.Run{Clients: clients.Clients{PipelineAsCode: stdata.PipelineAsCode, Kube: stdata.Kube}}
+			io, _, _, errOut := cli.IOTest()
+			imported := b
+			assert.NilError(t, importBundle(ctx, &importOptions{
+				run:              run,
+				ioStreams:        io,
+				pacNamespace:     ns,
+				createNamespaces: tt.createNamespaces,
+			}, &imported))
+			assert.Equal(t, errOut.String(), tt.wantErrOut)
+
+			cm, err := run.Clients.Kube.CoreV1().ConfigMaps(ns).Get(ctx, params.PACConfigmapName, metav1.GetOptions{})
+			assert.NilError(t, err)
+			assert.DeepEqual(t, cm.Data, map[string]string{"application-name": "My CI", "hub-url": "https://hub"})
+
+			got, err := run.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories("ns").Get(ctx, "repo", metav1.GetOptions{})
+			if !tt.wantImported {
+				assert.ErrorContains(t, err, "not found")
+				return
+			}
+			assert.NilError(t, err)
+			assert.DeepEqual(t, got.Spec, repo.Spec)
+			assert.DeepEqual(t, got.Status, repo.Status)
+		})
+	}
+}
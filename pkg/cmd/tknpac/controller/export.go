@@ -0,0 +1,102 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/bootstrap"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/spf13/cobra"
+	kapierror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const exportLonghelp = `
+
+export - export the Pipelines as Code installation to a bundle
+
+tkn pac controller export writes the settings of Pipelines as Code, all the
+Repositories of the cluster with their run history and the references to the
+secrets they use to a YAML bundle, which can be imported on a new cluster with
+tkn pac controller import.
+
+The content of the secrets is never exported, they need to be created again on
+the new cluster.`
+
+type exportOptions struct {
+	run          *params.Run
+	ioStreams    *cli.IOStreams
+	pacNamespace string
+	output       string
+}
+
+func exportCommand(run *params.Run, ioStreams *cli.IOStreams) *cobra.Command {
+	opts := &exportOptions{run: run, ioStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "export",
+		Long:  exportLonghelp,
+		Short: "Export the settings and the Repositories of Pipelines as Code to a bundle",
+		Annotations: map[string]string{
+			"commandType": "main",
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if err := run.Clients.NewClients(ctx, &run.Info); err != nil {
+				return err
+			}
+			out := opts.ioStreams.Out
+			if opts.output != "" && opts.output != "-" {
+				f, err := os.Create(opts.output)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				out = f
+			}
+			return exportBundle(ctx, opts, out)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.output, "output", "o", "", "The file to write the bundle to (default to the standard output)")
+	cmd.Flags().StringVarP(&opts.pacNamespace, pacNamespaceFlag, "", "", "The namespace where pac is installed")
+	return cmd
+}
+
+func exportBundle(ctx context.Context, opts *exportOptions, out io.Writer) error {
+	installed, ns, err := bootstrap.DetectPacInstallation(ctx, opts.pacNamespace, opts.run)
+	if !installed {
+		return fmt.Errorf("pipelines as code not installed")
+	}
+	if err != nil {
+		return err
+	}
+
+	b := bundle{Version: bundleVersion, ExportedAt: metav1.Now()}
+	cm, err := opts.run.Clients.Kube.CoreV1().ConfigMaps(ns).Get(ctx, params.PACConfigmapName, metav1.GetOptions{})
+	if err != nil && !kapierror.IsNotFound(err) {
+		return fmt.Errorf("cannot get the settings of Pipelines as Code: %w", err)
+	}
+	if err == nil {
+		b.Settings = cm.Data
+	}
+
+	repos, err := opts.run.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("cannot list the Repositories: %w", err)
+	}
+	for i := range repos.Items {
+		b.Repositories = append(b.Repositories, portableRepository(&repos.Items[i]))
+	}
+	b.Secrets = secretReferences(b.Repositories)
+
+	data, err := yaml.Marshal(b)
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(data)
+	return err
+}
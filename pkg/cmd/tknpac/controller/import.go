@@ -0,0 +1,198 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/bootstrap"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	kapierror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const importLonghelp = `
+
+import - import a bundle exported by tkn pac controller export
+
+tkn pac controller import restores the settings of Pipelines as Code and
+creates the Repositories of a bundle with their run history, on a cluster
+where Pipelines as Code is installed. The Repositories already on the cluster
+are skipped.
+
+The secrets referenced by the Repositories are not in the bundle, the ones
+missing on the cluster are listed so they can be created again.`
+
+type importOptions struct {
+	run              *params.Run
+	ioStreams        *cli.IOStreams
+	pacNamespace     string
+	filename         string
+	skipSettings     bool
+	createNamespaces bool
+}
+
+func importCommand(run *params.Run, ioStreams *cli.IOStreams) *cobra.Command {
+	opts := &importOptions{run: run, ioStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "import",
+		Long:  importLonghelp,
+		Short: "Import the settings and the Repositories of a bundle exported by tkn pac controller export",
+		Annotations: map[string]string{
+			"commandType": "main",
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.filename == "" {
+				return fmt.Errorf("a bundle needs to be specified with --filename")
+			}
+			data, err := os.ReadFile(opts.filename)
+			if err != nil {
+				return err
+			}
+			b := bundle{}
+			if err := yaml.Unmarshal(data, &b); err != nil {
+				return fmt.Errorf("cannot parse the bundle %s: %w", opts.filename, err)
+			}
+			if b.Version != bundleVersion {
+				return fmt.Errorf("unsupported bundle version %q, expected %s", b.Version, bundleVersion)
+			}
+			ctx := cmd.Context()
+			if err := run.Clients.NewClients(ctx, &run.Info); err != nil {
+				return err
+			}
+			return importBundle(ctx, opts, &b)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.filename, "filename", "f", "", "The bundle to import")
+	cmd.Flags().BoolVar(&opts.skipSettings, "skip-settings", false, "Do not restore the settings of Pipelines as Code")
+	cmd.Flags().BoolVar(&opts.createNamespaces, "create-namespaces", false, "Create the namespaces of the Repositories missing on the cluster")
+	cmd.Flags().StringVarP(&opts.pacNamespace, pacNamespaceFlag, "", "", "The namespace where pac is installed")
+	return cmd
+}
+
+func importBundle(ctx context.Context, opts *importOptions, b *bundle) error {
+	cs := opts.ioStreams.ColorScheme()
+	installed, ns, err := bootstrap.DetectPacInstallation(ctx, opts.pacNamespace, opts.run)
+	if !installed {
+		return fmt.Errorf("pipelines as code not installed")
+	}
+	if err != nil {
+		return err
+	}
+
+	if !opts.skipSettings && len(b.Settings) > 0 {
+		if err := importSettings(ctx, opts.run, ns, b.Settings); err != nil {
+			return err
+		}
+		fmt.Fprintf(opts.ioStreams.Out, "%s Settings of Pipelines as Code have been restored in the %s namespace\n",
+			cs.SuccessIconWithColor(cs.Green), ns)
+	}
+
+	imported := 0
+	for i := range b.Repositories {
+		repo := b.Repositories[i]
+		if err := ensureNamespace(ctx, opts, repo.GetNamespace()); err != nil {
+			fmt.Fprintf(opts.ioStreams.ErrOut, "%s cannot create the Repository %s/%s: %s\n",
+				cs.WarningIcon(), repo.GetNamespace(), repo.GetName(), err.Error())
+			continue
+		}
+		_, err := opts.run.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(repo.GetNamespace()).Create(ctx, &repo, metav1.CreateOptions{})
+		if kapierror.IsAlreadyExists(err) {
+			fmt.Fprintf(opts.ioStreams.Out, "%s Repository %s already exists in the %s namespace\n", cs.InfoIcon(), repo.GetName(), repo.GetNamespace())
+			continue
+		}
+		if err != nil {
+			fmt.Fprintf(opts.ioStreams.ErrOut, "%s cannot create the Repository %s/%s: %s\n",
+				cs.WarningIcon(), repo.GetNamespace(), repo.GetName(), err.Error())
+			continue
+		}
+		fmt.Fprintf(opts.ioStreams.Out, "%s Repository %s has been created in the %s namespace\n",
+			cs.SuccessIconWithColor(cs.Green), repo.GetName(), repo.GetNamespace())
+		imported++
+	}
+	fmt.Fprintf(opts.ioStreams.Out, "%d of %d Repositories have been imported\n", imported, len(b.Repositories))
+
+	for _, ref := range b.Secrets {
+		found, missing, err := missingSecretKeys(ctx, opts.run, ref)
+		if err != nil {
+			return err
+		}
+		switch {
+		case !found:
+			fmt.Fprintf(opts.ioStreams.ErrOut, "%s Secret %s in the %s namespace is missing", cs.WarningIcon(), ref.Name, ref.Namespace)
+		case len(missing) > 0:
+			fmt.Fprintf(opts.ioStreams.ErrOut, "%s Secret %s in the %s namespace is incomplete", cs.WarningIcon(), ref.Name, ref.Namespace)
+		default:
+			continue
+		}
+		if len(missing) > 0 {
+			fmt.Fprintf(opts.ioStreams.ErrOut, ", it needs the keys: %s", strings.Join(missing, ", "))
+		}
+		fmt.Fprintln(opts.ioStreams.ErrOut)
+	}
+	return nil
+}
+
+// importSettings merges the settings of the bundle into the configmap of
+// Pipelines as Code.
+func importSettings(ctx context.Context, run *params.Run, ns string, settings map[string]string) error {
+	configMaps := run.Clients.Kube.CoreV1().ConfigMaps(ns)
+	cm, err := configMaps.Get(ctx, params.PACConfigmapName, metav1.GetOptions{})
+	if kapierror.IsNotFound(err) {
+		_, err = configMaps.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: params.PACConfigmapName, Namespace: ns},
+			Data:       settings,
+		}, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	for k, v := range settings {
+		cm.Data[k] = v
+	}
+	_, err = configMaps.Update(ctx, cm, metav1.UpdateOptions{})
+	return err
+}
+
+func ensureNamespace(ctx context.Context, opts *importOptions, ns string) error {
+	namespaces := opts.run.Clients.Kube.CoreV1().Namespaces()
+	_, err := namespaces.Get(ctx, ns, metav1.GetOptions{})
+	if err == nil || !kapierror.IsNotFound(err) {
+		return err
+	}
+	if !opts.createNamespaces {
+		return fmt.Errorf("the namespace %s does not exist, create it or use --create-namespaces", ns)
+	}
+	_, err = namespaces.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}}, metav1.CreateOptions{})
+	return err
+}
+
+// missingSecretKeys checks if the secret of the reference is on the cluster
+// and returns its keys missing on the cluster, all of them when the secret
+// itself is missing.
+func missingSecretKeys(ctx context.Context, run *params.Run, ref secretReference) (bool, []string, error) {
+	secret, err := run.Clients.Kube.CoreV1().Secrets(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	if kapierror.IsNotFound(err) {
+		return false, ref.Keys, nil
+	}
+	if err != nil {
+		return false, nil, err
+	}
+	missing := []string{}
+	for _, key := range ref.Keys {
+		if _, ok := secret.Data[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	return true, missing, nil
+}
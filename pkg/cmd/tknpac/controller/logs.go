@@ -0,0 +1,190 @@
+package controller
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/bootstrap"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const longhelp = `
+
+logs - show the logs of the Pipelines as Code controller
+
+tkn pac controller logs locates the pods of the Pipelines as Code adapter
+(the controller receiving the webhooks) and of the watcher in the namespace
+where Pipelines as Code is installed and shows their logs.
+
+Use --delivery-id to only show the lines about a webhook delivery, ie: the
+X-GitHub-Delivery header shown in the GitHub App or webhook settings.`
+
+const (
+	followFlag       = "follow"
+	componentFlag    = "component"
+	deliveryIDFlag   = "delivery-id"
+	pacNamespaceFlag = "pac-namespace"
+)
+
+// componentSelectors are the label selectors of the pods of each component.
+var componentSelectors = map[string]string{
+	"adapter": "app.kubernetes.io/part-of=pipelines-as-code,app.kubernetes.io/component=controller",
+	"watcher": "app.kubernetes.io/part-of=pipelines-as-code,app.kubernetes.io/component=watcher",
+}
+
+// components is the order the logs of the components are shown in.
+var components = []string{"adapter", "watcher"}
+
+type logsOptions struct {
+	run          *params.Run
+	ioStreams    *cli.IOStreams
+	pacNamespace string
+	component    string
+	deliveryID   string
+	follow       bool
+}
+
+func logsCommand(run *params.Run, ioStreams *cli.IOStreams) *cobra.Command {
+	opts := &logsOptions{run: run, ioStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "logs",
+		Long:  longhelp,
+		Short: "Display the logs of the Pipelines as Code controller",
+		Annotations: map[string]string{
+			"commandType": "main",
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.component != "" {
+				if _, ok := componentSelectors[opts.component]; !ok {
+					return fmt.Errorf("invalid component %q, choices are: %s", opts.component, strings.Join(components, ", "))
+				}
+			}
+			ctx := cmd.Context()
+			if err := run.Clients.NewClients(ctx, &run.Info); err != nil {
+				return err
+			}
+			return controllerLogs(ctx, opts)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&opts.follow, followFlag, "f", false, "Follow the logs")
+	cmd.Flags().StringVarP(&opts.component, componentFlag, "", "",
+		fmt.Sprintf("Only show the logs of this component, choices are: %s (default to all)", strings.Join(components, ", ")))
+	_ = cmd.RegisterFlagCompletionFunc(componentFlag,
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return components, cobra.ShellCompDirectiveNoFileComp
+		},
+	)
+	cmd.Flags().StringVarP(&opts.deliveryID, deliveryIDFlag, "", "", "Only show the log lines containing this webhook delivery ID")
+	cmd.Flags().StringVarP(&opts.pacNamespace, pacNamespaceFlag, "", "", "The namespace where pac is installed")
+	return cmd
+}
+
+func controllerLogs(ctx context.Context, opts *logsOptions) error {
+	installed, ns, err := bootstrap.DetectPacInstallation(ctx, opts.pacNamespace, opts.run)
+	if !installed {
+		return fmt.Errorf("pipelines as code not installed")
+	}
+	if err != nil {
+		return err
+	}
+
+	pods, err := ListPods(ctx, opts.run, ns, opts.component)
+	if err != nil {
+		return err
+	}
+	if len(pods) == 0 {
+		return fmt.Errorf("cannot find any Pipelines as Code pods in the %s namespace", ns)
+	}
+
+	// the lines are prefixed by the pod name when showing the logs of
+	// multiple pods, the pods are streamed concurrently when following since
+	// the streams never end.
+	prefix := len(pods) > 1
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	errs := make([]error, len(pods))
+	for i := range pods {
+		stream := func(i int) {
+			errs[i] = streamPodLogs(ctx, opts, &pods[i], prefix, &mu)
+		}
+		if !opts.follow {
+			stream(i)
+			continue
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			stream(i)
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListPods returns the pods of a Pipelines as Code component in the
+// installation namespace, or the pods of all the components if component is
+// empty.
+func ListPods(ctx context.Context, run *params.Run, ns, component string) ([]corev1.Pod, error) {
+	pods := []corev1.Pod{}
+	for _, c := range components {
+		if component != "" && component != c {
+			continue
+		}
+		podList, err := run.Clients.Kube.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{
+			LabelSelector: componentSelectors[c],
+		})
+		if err != nil {
+			return nil, err
+		}
+		pods = append(pods, podList.Items...)
+	}
+	return pods, nil
+}
+
+func streamPodLogs(ctx context.Context, opts *logsOptions, pod *corev1.Pod, prefix bool, mu *sync.Mutex) error {
+	rc, err := opts.run.Clients.Kube.CoreV1().Pods(pod.GetNamespace()).GetLogs(pod.GetName(), &corev1.PodLogOptions{
+		Follow: opts.follow,
+	}).Stream(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot get the logs of pod %s: %w", pod.GetName(), err)
+	}
+	defer rc.Close()
+	return filterLogs(rc, opts.ioStreams.Out, pod.GetName(), prefix, opts.deliveryID, mu)
+}
+
+// filterLogs copies the lines of the logs containing the delivery ID (or all
+// of them if it is empty) to the output.
+func filterLogs(r io.Reader, out io.Writer, podName string, prefix bool, deliveryID string, mu *sync.Mutex) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if deliveryID != "" && !strings.Contains(line, deliveryID) {
+			continue
+		}
+		if prefix {
+			line = fmt.Sprintf("[%s] %s", podName, line)
+		}
+		mu.Lock()
+		_, err := fmt.Fprintln(out, line)
+		mu.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,112 @@
+package controller
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	tcli "github.com/openshift-pipelines/pipelines-as-code/pkg/test/cli"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestControllerLogs(t *testing.T) {
+	ns := "pipelines-as-code"
+	pod := func(name, component string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: ns,
+				Labels: map[string]string{
+					"app.kubernetes.io/part-of":   "pipelines-as-code",
+					"app.kubernetes.io/component": component,
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		component string
+		pods      []*corev1.Pod
+		want      string
+		wantErr   string
+	}{
+		{
+			name:      "adapter logs",
+			component: "adapter",
+			pods:      []*corev1.Pod{pod("controller-1", "controller"), pod("watcher-1", "watcher")},
+			want:      "fake logs\n",
+		},
+		{
+			name: "all components logs are prefixed",
+			pods: []*corev1.Pod{pod("controller-1", "controller"), pod("watcher-1", "watcher")},
+			want: "[controller-1] fake logs\n[watcher-1] fake logs\n",
+		},
+		{
+			name:      "no pods",
+			component: "watcher",
+			pods:      []*corev1.Pod{pod("controller-1", "controller")},
+			wantErr:   "cannot find any Pipelines as Code pods in the pipelines-as-code namespace",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			tdata := testclient.Data{
+				Pods: tt.pods,
+				ConfigMap: []*corev1.ConfigMap{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "pipelines-as-code-info",
+							Namespace: ns,
+						},
+					},
+				},
+			}
+			stdata, _ := testclient.SeedTestData(t, ctx, tdata)
+			run := &params.Run{
+				Clients: clients.Clients{
+					PipelineAsCode: stdata.PipelineAsCode,
+					Kube:           stdata.Kube,
+				},
+			}
+			io, out := tcli.NewIOStream()
+			err := controllerLogs(ctx, &logsOptions{
+				run:          run,
+				ioStreams:    io,
+				pacNamespace: ns,
+				component:    tt.component,
+			})
+			if tt.wantErr != "" {
+				assert.Error(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, out.String(), tt.want)
+		})
+	}
+}
+
+func TestFilterLogs(t *testing.T) {
+	logs := strings.Join([]string{
+		`{"msg": "event received", "event-id": "abcd"}`,
+		`{"msg": "event received", "event-id": "efgh"}`,
+		`{"msg": "pipelinerun created", "event-id": "abcd"}`,
+	}, "\n")
+
+	out := &bytes.Buffer{}
+	assert.NilError(t, filterLogs(strings.NewReader(logs), out, "pod", false, "abcd", &sync.Mutex{}))
+	assert.Equal(t, out.String(),
+		"{\"msg\": \"event received\", \"event-id\": \"abcd\"}\n{\"msg\": \"pipelinerun created\", \"event-id\": \"abcd\"}\n")
+
+	out.Reset()
+	assert.NilError(t, filterLogs(strings.NewReader(logs), out, "pod", true, "efgh", &sync.Mutex{}))
+	assert.Equal(t, out.String(), "[pod] {\"msg\": \"event received\", \"event-id\": \"efgh\"}\n")
+}
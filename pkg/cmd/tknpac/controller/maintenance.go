@@ -0,0 +1,234 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/bootstrap"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"github.com/spf13/cobra"
+	kapierror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const maintenanceLonghelp = `
+
+maintenance - manage the maintenance windows of Pipelines as Code
+
+During a maintenance window, ie: while the cluster is upgraded, Pipelines as
+Code keeps accepting the events of the git providers and creates their
+PipelineRuns in pending state. The PipelineRuns are reported as queued on the
+git providers and are started once the window ends.
+
+The windows are stored in the maintenance-windows setting of the
+pipelines-as-code configmap, the windows already ended are removed when the
+windows are changed.`
+
+const (
+	maintenanceStartFlag    = "start"
+	maintenanceDurationFlag = "duration"
+)
+
+type maintenanceOptions struct {
+	run          *params.Run
+	ioStreams    *cli.IOStreams
+	clock        clockwork.Clock
+	pacNamespace string
+	start        string
+	duration     time.Duration
+}
+
+func maintenanceCommand(run *params.Run, ioStreams *cli.IOStreams) *cobra.Command {
+	opts := &maintenanceOptions{run: run, ioStreams: ioStreams, clock: clockwork.NewRealClock()}
+	cmd := &cobra.Command{
+		Use:   "maintenance",
+		Long:  maintenanceLonghelp,
+		Short: "Manage the maintenance windows during which the PipelineRuns are queued",
+		Annotations: map[string]string{
+			"commandType": "main",
+		},
+	}
+	cmd.PersistentFlags().StringVarP(&opts.pacNamespace, pacNamespaceFlag, "", "", "The namespace where pac is installed")
+
+	withClients := func(f func(context.Context, *maintenanceOptions) error) func(*cobra.Command, []string) error {
+		return func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if err := run.Clients.NewClients(ctx, &run.Info); err != nil {
+				return err
+			}
+			return f(ctx, opts)
+		}
+	}
+
+	startCmd := &cobra.Command{
+		Use:   "start",
+		Short: "Add a maintenance window, starting now unless --start is given",
+		Args:  cobra.NoArgs,
+		RunE:  withClients(maintenanceStart),
+	}
+	startCmd.Flags().StringVarP(&opts.start, maintenanceStartFlag, "", "", "When the window starts as a RFC3339 time, ie: 2023-05-20T22:00:00Z (default to now)")
+	startCmd.Flags().DurationVarP(&opts.duration, maintenanceDurationFlag, "d", 0, "How long the window lasts, ie: 2h")
+	cmd.AddCommand(startCmd)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "stop",
+		Short: "End the maintenance in progress and start the queued PipelineRuns",
+		Args:  cobra.NoArgs,
+		RunE:  withClients(maintenanceStop),
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "status",
+		Short: "Show if a maintenance is in progress and the upcoming windows",
+		Args:  cobra.NoArgs,
+		RunE:  withClients(maintenanceStatus),
+	})
+	return cmd
+}
+
+// maintenanceStart adds a maintenance window to the settings.
+func maintenanceStart(ctx context.Context, opts *maintenanceOptions) error {
+	if opts.duration <= 0 {
+		return fmt.Errorf("the duration of the window needs to be specified with --%s", maintenanceDurationFlag)
+	}
+	start := opts.clock.Now()
+	if opts.start != "" {
+		var err error
+		if start, err = time.Parse(time.RFC3339, opts.start); err != nil {
+			return fmt.Errorf("invalid --%s: %w", maintenanceStartFlag, err)
+		}
+	}
+	window := settings.MaintenanceWindow{Start: start.Truncate(time.Second), End: start.Add(opts.duration).Truncate(time.Second)}
+	if !window.End.After(window.Start) {
+		return fmt.Errorf("the window needs to last at least a second")
+	}
+	windows, err := updateMaintenanceWindows(ctx, opts, func(windows []settings.MaintenanceWindow) []settings.MaintenanceWindow {
+		return append(windows, window)
+	})
+	if err != nil {
+		return err
+	}
+	cs := opts.ioStreams.ColorScheme()
+	fmt.Fprintf(opts.ioStreams.Out, "%s Maintenance window from %s to %s has been added\n",
+		cs.SuccessIconWithColor(cs.Green), window.Start.Format(time.RFC3339), window.End.Format(time.RFC3339))
+	printMaintenance(opts, windows)
+	return nil
+}
+
+// maintenanceStop removes the windows in progress, the upcoming ones are kept.
+func maintenanceStop(ctx context.Context, opts *maintenanceOptions) error {
+	now := opts.clock.Now()
+	stopped := 0
+	windows, err := updateMaintenanceWindows(ctx, opts, func(windows []settings.MaintenanceWindow) []settings.MaintenanceWindow {
+		upcoming := []settings.MaintenanceWindow{}
+		for _, w := range windows {
+			if w.Start.After(now) {
+				upcoming = append(upcoming, w)
+				continue
+			}
+			stopped++
+		}
+		return upcoming
+	})
+	if err != nil {
+		return err
+	}
+	cs := opts.ioStreams.ColorScheme()
+	if stopped == 0 {
+		fmt.Fprintf(opts.ioStreams.Out, "%s No maintenance is in progress\n", cs.InfoIcon())
+	} else {
+		fmt.Fprintf(opts.ioStreams.Out, "%s Maintenance has been stopped, the queued PipelineRuns are going to start\n",
+			cs.SuccessIconWithColor(cs.Green))
+	}
+	printMaintenance(opts, windows)
+	return nil
+}
+
+func maintenanceStatus(ctx context.Context, opts *maintenanceOptions) error {
+	_, windows, err := maintenanceWindows(ctx, opts)
+	if err != nil {
+		return err
+	}
+	printMaintenance(opts, windows)
+	return nil
+}
+
+// printMaintenance shows if a maintenance is in progress and the upcoming
+// windows.
+func printMaintenance(opts *maintenanceOptions, windows []settings.MaintenanceWindow) {
+	now := opts.clock.Now()
+	cs := opts.ioStreams.ColorScheme()
+	s := &settings.Settings{MaintenanceWindows: windows}
+	if end, inMaintenance := s.MaintenanceEnd(now); inMaintenance {
+		fmt.Fprintf(opts.ioStreams.Out, "%s Maintenance in progress until %s, the PipelineRuns are queued\n",
+			cs.WarningIcon(), end.Format(time.RFC3339))
+	} else {
+		fmt.Fprintf(opts.ioStreams.Out, "%s No maintenance in progress, the PipelineRuns are started\n", cs.SuccessIcon())
+	}
+	upcoming := []string{}
+	for _, w := range windows {
+		if w.Start.After(now) {
+			upcoming = append(upcoming, fmt.Sprintf("  - %s to %s", w.Start.Format(time.RFC3339), w.End.Format(time.RFC3339)))
+		}
+	}
+	if len(upcoming) > 0 {
+		fmt.Fprintf(opts.ioStreams.Out, "Upcoming maintenance windows:\n%s\n", strings.Join(upcoming, "\n"))
+	}
+}
+
+// updateMaintenanceWindows changes the maintenance windows of the settings,
+// the windows already ended are removed.
+func updateMaintenanceWindows(ctx context.Context, opts *maintenanceOptions, update func([]settings.MaintenanceWindow) []settings.MaintenanceWindow) ([]settings.MaintenanceWindow, error) {
+	ns, windows, err := maintenanceWindows(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	now := opts.clock.Now()
+	current := []settings.MaintenanceWindow{}
+	for _, w := range windows {
+		if w.End.After(now) {
+			current = append(current, w)
+		}
+	}
+	windows = update(current)
+	sort.SliceStable(windows, func(i, j int) bool { return windows[i].Start.Before(windows[j].Start) })
+	values := make([]string, 0, len(windows))
+	for _, w := range windows {
+		values = append(values, w.String())
+	}
+	if err := importSettings(ctx, opts.run, ns, map[string]string{settings.MaintenanceWindowsKey: strings.Join(values, ",")}); err != nil {
+		return nil, err
+	}
+	return windows, nil
+}
+
+// maintenanceWindows returns the namespace where Pipelines as Code is
+// installed and the maintenance windows of its settings.
+func maintenanceWindows(ctx context.Context, opts *maintenanceOptions) (string, []settings.MaintenanceWindow, error) {
+	installed, ns, err := bootstrap.DetectPacInstallation(ctx, opts.pacNamespace, opts.run)
+	if !installed {
+		return "", nil, fmt.Errorf("pipelines as code not installed")
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	cm, err := opts.run.Clients.Kube.CoreV1().ConfigMaps(ns).Get(ctx, params.PACConfigmapName, metav1.GetOptions{})
+	if kapierror.IsNotFound(err) {
+		return ns, nil, nil
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	windows, err := settings.ParseMaintenanceWindows(cm.Data[settings.MaintenanceWindowsKey])
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid %s setting: %w", settings.MaintenanceWindowsKey, err)
+	}
+	return ns, windows, nil
+}
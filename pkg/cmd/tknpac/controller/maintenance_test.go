@@ -0,0 +1,127 @@
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	tcli "github.com/openshift-pipelines/pipelines-as-code/pkg/test/cli"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestMaintenance(t *testing.T) {
+	ns := "pipelines-as-code"
+	clock := clockwork.NewFakeClockAt(time.Date(2023, time.May, 20, 22, 0, 0, 0, time.UTC))
+	ended := "2023-05-19T22:00:00Z/2023-05-19T23:00:00Z"
+	inProgress := "2023-05-20T21:00:00Z/2023-05-20T23:00:00Z"
+	upcoming := "2023-05-21T22:00:00Z/2023-05-21T23:00:00Z"
+
+	tests := []struct {
+		name        string
+		run         func(context.Context, *maintenanceOptions) error
+		start       string
+		duration    time.Duration
+		windows     string
+		wantWindows string
+		wantOutput  []string
+		wantErr     string
+	}{
+		{
+			name:        "start now",
+			run:         maintenanceStart,
+			duration:    2 * time.Hour,
+			windows:     ended + "," + upcoming,
+			wantWindows: "2023-05-20T22:00:00Z/2023-05-21T00:00:00Z," + upcoming,
+			wantOutput: []string{
+				"Maintenance window from 2023-05-20T22:00:00Z to 2023-05-21T00:00:00Z has been added\n",
+				"Maintenance in progress until 2023-05-21T00:00:00Z, the PipelineRuns are queued\n",
+				"Upcoming maintenance windows:\n  - 2023-05-21T22:00:00Z to 2023-05-21T23:00:00Z\n",
+			},
+		},
+		{
+			name:        "start later",
+			run:         maintenanceStart,
+			start:       "2023-05-21T01:00:00+02:00",
+			duration:    time.Hour,
+			wantWindows: "2023-05-21T01:00:00+02:00/2023-05-21T02:00:00+02:00",
+			wantOutput:  []string{"No maintenance in progress, the PipelineRuns are started\n"},
+		},
+		{
+			name:    "start without a duration",
+			run:     maintenanceStart,
+			windows: inProgress,
+			wantErr: "the duration of the window needs to be specified with --duration",
+		},
+		{
+			name:        "stop",
+			run:         maintenanceStop,
+			windows:     inProgress + "," + upcoming,
+			wantWindows: upcoming,
+			wantOutput:  []string{"Maintenance has been stopped, the queued PipelineRuns are going to start\n"},
+		},
+		{
+			name:        "stop without maintenance",
+			run:         maintenanceStop,
+			windows:     ended,
+			wantWindows: "",
+			wantOutput:  []string{"No maintenance is in progress\n"},
+		},
+		{
+			name:        "status",
+			run:         maintenanceStatus,
+			windows:     inProgress,
+			wantWindows: inProgress,
+			wantOutput:  []string{"Maintenance in progress until 2023-05-20T23:00:00Z"},
+		},
+		{
+			name:    "invalid setting",
+			run:     maintenanceStatus,
+			windows: "tomorrow",
+			wantErr: `invalid maintenance-windows setting: invalid window "tomorrow", it needs to be in the start/end format`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+				ConfigMap: []*corev1.ConfigMap{
+					{ObjectMeta: metav1.ObjectMeta{Name: "pipelines-as-code-info", Namespace: ns}},
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: params.PACConfigmapName, Namespace: ns},
+						Data:       map[string]string{settings.MaintenanceWindowsKey: tt.windows},
+					},
+				},
+			})
+			run := &params.Run{Clients: clients.Clients{PipelineAsCode: stdata.PipelineAsCode, Kube: stdata.Kube}}
+			io, out := tcli.NewIOStream()
+			err := tt.run(ctx, &maintenanceOptions{
+				run:          run,
+				ioStreams:    io,
+				clock:        clock,
+				pacNamespace: ns,
+				start:        tt.start,
+				duration:     tt.duration,
+			})
+			if tt.wantErr != "" {
+				assert.Error(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			for _, want := range tt.wantOutput {
+				assert.Assert(t, strings.Contains(out.String(), want), "%q not in:\n%s", want, out.String())
+			}
+			cm, err := stdata.Kube.CoreV1().ConfigMaps(ns).Get(ctx, params.PACConfigmapName, metav1.GetOptions{})
+			assert.NilError(t, err)
+			assert.Equal(t, cm.Data[settings.MaintenanceWindowsKey], tt.wantWindows)
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package controller
+
+import (
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/spf13/cobra"
+)
+
+func Root(clients *params.Run, ioStreams *cli.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "controller",
+		Short:        "Pipelines as Code controller commands",
+		Long:         `Commands to inspect, export, import and put in maintenance the Pipelines as Code controller running on the cluster`,
+		SilenceUsage: true,
+		Annotations: map[string]string{
+			"commandType": "main",
+		},
+	}
+
+	cmd.AddCommand(logsCommand(clients, ioStreams))
+	cmd.AddCommand(exportCommand(clients, ioStreams))
+	cmd.AddCommand(importCommand(clients, ioStreams))
+	cmd.AddCommand(maintenanceCommand(clients, ioStreams))
+	return cmd
+}
@@ -41,6 +41,31 @@ type RepoOptions struct {
 	cliOpts   *cli.PacCliOpts
 }
 
+// NewRepoOptions returns the options to interactively create a Repository,
+// ie: from the setup wizard.
+func NewRepoOptions(run *params.Run, ioStreams *cli.IOStreams, cliOpts *cli.PacCliOpts, gitInfo *git.Info) *RepoOptions {
+	return &RepoOptions{
+		Event:      info.NewEvent(),
+		Repository: &apipac.Repository{},
+		Run:        run,
+		GitInfo:    gitInfo,
+		IoStreams:  ioStreams,
+		cliOpts:    cliOpts,
+	}
+}
+
+// AskRepoURL asks for the URL of the repository, the URL of the git remote
+// being the default.
+func (r *RepoOptions) AskRepoURL() error {
+	return getRepoURL(r)
+}
+
+// GenerateTemplate generates a sample PipelineRun in the .tekton directory
+// of the repository.
+func (r *RepoOptions) GenerateTemplate() error {
+	return r.generateTemplate(nil)
+}
+
 func repositoryCommand(run *params.Run, ioStreams *cli.IOStreams) *cobra.Command {
 	createOpts := &RepoOptions{
 		Event:      info.NewEvent(),
@@ -139,6 +164,9 @@ func (r *RepoOptions) generateTemplate(gopt *generate.Opts) error {
 	// defaulting the values for repo create command
 	gopt.Event.EventType = "pull_request, push"
 	gopt.Event.BaseBranch = "main"
+	if r.GitInfo != nil && r.GitInfo.DefaultBranch != "" {
+		gopt.Event.BaseBranch = r.GitInfo.DefaultBranch
+	}
 
 	return generate.Generate(gopt, false)
 }
@@ -182,7 +210,13 @@ func getOrCreateNamespace(ctx context.Context, opts *RepoOptions) error {
 		chosenNS = autoNS
 	}
 	// check if the namespace exists if it does just exit
-	_, err := opts.Run.Clients.Kube.CoreV1().Namespaces().Get(ctx, chosenNS, metav1.GetOptions{})
+	_, err := opts.Run.Clients.CachedLookup("namespace/"+chosenNS, func() (string, error) {
+		ns, err := opts.Run.Clients.Kube.CoreV1().Namespaces().Get(ctx, chosenNS, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		return ns.GetName(), nil
+	})
 	if err == nil {
 		opts.Repository.Namespace = chosenNS
 		return nil
@@ -6,15 +6,16 @@ import (
 	"fmt"
 	"os"
 	"regexp"
-	"text/tabwriter"
+	"strings"
 	"text/template"
 
 	"github.com/jonboulle/clockwork"
-	"github.com/juju/ansiterm"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli/output"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli/prompt"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli/status"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli/webhook"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/completion"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/consoleui"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
@@ -32,8 +33,17 @@ var (
 	targetPRFlag      = "target-pipelinerun"
 	useRealTimeFlag   = "use-realtime"
 	showEventflag     = "show-events"
+	outputFlag        = "output"
+	outputMarkdown    = "markdown"
+	checkFlag         = "check"
+	pacNamespaceFlag  = "pac-namespace"
+	limitFlag         = "limit"
+	resultsURLFlag    = "results-url"
 	creationTimestamp = "{.metadata.creationTimestamp}"
 	maxEventLimit     = 50
+	defaultRunsLimit  = 5
+	// resultsPageSize is the number of runs asked to Tekton Results at a time
+	resultsPageSize = 50
 )
 
 //go:embed templates/describe.tmpl
@@ -46,21 +56,89 @@ func formatError(cs *cli.ColorScheme, log string) string {
 	return n
 }
 
-func formatStatus(status v1alpha1.RepositoryRunStatus, cs *cli.ColorScheme, c clockwork.Clock) string {
+func formatStatus(r *output.Renderer, status v1alpha1.RepositoryRunStatus) string {
+	cs := r.ColorScheme
 	return fmt.Sprintf("%s\t%s\t%s\t%s\t%s\t%s\t%s",
 		cs.ColorStatus(status.Status.Conditions[0].Reason),
 		*status.EventType,
 		formatting.SanitizeBranch(*status.TargetBranch),
 		cs.HyperLink(formatting.ShortSHA(*status.SHA), *status.SHAURL),
-		formatting.Age(status.StartTime, c),
+		r.Time(status.StartTime),
 		formatting.PRDuration(status),
-		cs.HyperLink(status.PipelineRunName, *status.LogURL))
+		cs.HyperLink(formatting.RunName(status), formatting.LogURL(status)))
+}
+
+// formatRouting formats the routing of a Repository, the Repositories without a
+// routing handle the events not routed to another one.
+func formatRouting(routing *v1alpha1.Routing) string {
+	parts := []string{}
+	if routing != nil && len(routing.EventTypes) > 0 {
+		parts = append(parts, "events: "+strings.Join(routing.EventTypes, ", "))
+	}
+	if routing != nil && len(routing.Branches) > 0 {
+		parts = append(parts, "branches: "+strings.Join(routing.Branches, ", "))
+	}
+	if len(parts) == 0 {
+		return "any event"
+	}
+	return strings.Join(parts, "; ")
+}
+
+// sharedURLRepositories returns the Repositories declaring the same URL as the
+// repository, itself included, in the order the events are routed to them. It
+// returns nothing when the repository is the only one, or when the
+// Repositories of the other namespaces cannot be listed.
+func sharedURLRepositories(ctx context.Context, cs *params.Run, repository *v1alpha1.Repository) []v1alpha1.Repository {
+	repositories, err := cs.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil
+	}
+	url := formatting.NormalizeRepoURL(repository.Spec.URL)
+	shared := []v1alpha1.Repository{}
+	for _, repo := range repositories.Items {
+		if strings.EqualFold(formatting.NormalizeRepoURL(repo.Spec.URL), url) {
+			shared = append(shared, repo)
+		}
+	}
+	shared = v1alpha1.RoutableRepositories(shared)
+	if len(shared) < 2 {
+		return nil
+	}
+	v1alpha1.SortByRouting(shared)
+	return shared
+}
+
+// formatEvent formats the reason and the message of an event, the events not
+// on the Repository itself (ie: on its PipelineRuns) are prefixed by the
+// object they are about and the warnings are highlighted.
+func formatEvent(ev corev1.Event, cs *cli.ColorScheme) string {
+	reason := ev.Reason
+	if ev.Type == corev1.EventTypeWarning {
+		reason = cs.Yellow(reason)
+	}
+	s := fmt.Sprintf("%s - %s", reason, ev.Message)
+	if ev.InvolvedObject.Kind != "" && ev.InvolvedObject.Kind != "Repository" {
+		s = fmt.Sprintf("%s %s - %s", ev.InvolvedObject.Kind, cs.Bold(ev.InvolvedObject.Name), s)
+	}
+	return s
 }
 
 type describeOpts struct {
 	cli.PacCliOpts
 	TargetPipelineRun string
 	ShowEvents        bool
+	Output            string
+	Check             bool
+	PACNamespace      string
+	// Limit is the number of runs to show, 0 shows all of them.
+	Limit int
+	// ResultsURL is the URL of the Tekton Results API the history of the
+	// runs is paged from, when the runs are not on the cluster anymore.
+	ResultsURL   string
+	ResultsToken string
+	// checkRepository checks the configuration of the repository on its git
+	// provider, webhook.Check when not replaced by the tests.
+	checkRepository func(context.Context, *webhook.CheckOptions) ([]webhook.Finding, error)
 }
 
 func newDescribeOptions(cmd *cobra.Command) *describeOpts {
@@ -106,6 +184,38 @@ func Root(run *params.Run, ioStreams *cli.IOStreams) *cobra.Command {
 				return err
 			}
 
+			opts.Output, err = cmd.Flags().GetString(outputFlag)
+			if err != nil {
+				return err
+			}
+			if opts.Output != "" && opts.Output != outputMarkdown {
+				return fmt.Errorf("invalid output format %q, only %q is supported", opts.Output, outputMarkdown)
+			}
+
+			opts.Check, err = cmd.Flags().GetBool(checkFlag)
+			if err != nil {
+				return err
+			}
+
+			opts.PACNamespace, err = cmd.Flags().GetString(pacNamespaceFlag)
+			if err != nil {
+				return err
+			}
+
+			opts.Limit, err = cmd.Flags().GetInt(limitFlag)
+			if err != nil {
+				return err
+			}
+			if opts.Limit < 0 {
+				return fmt.Errorf("invalid limit %d, it needs to be 0 to show all the runs or more", opts.Limit)
+			}
+
+			opts.ResultsURL, err = cmd.Flags().GetString(resultsURLFlag)
+			if err != nil {
+				return err
+			}
+			opts.ResultsToken = os.Getenv("TEKTON_RESULTS_TOKEN")
+
 			if len(args) > 0 {
 				repoName = args[0]
 			}
@@ -147,6 +257,21 @@ func Root(run *params.Run, ioStreams *cli.IOStreams) *cobra.Command {
 		showEventflag, "", false, "show kubernetes events associated with this repository, useful if you have an error that cannot be reported on the git provider interface")
 	cmd.PersistentFlags().BoolVarP(&useRealTime, useRealTimeFlag, "", false,
 		"display the time as RFC3339 instead of a relative time")
+	cmd.Flags().StringP(
+		outputFlag, "o", "", "Output format, set to markdown to print the summary of the last run (or the target PipelineRun) as markdown")
+	_ = cmd.RegisterFlagCompletionFunc(outputFlag,
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return []string{outputMarkdown}, cobra.ShellCompDirectiveNoFileComp
+		},
+	)
+	cmd.Flags().BoolP(
+		checkFlag, "", false, "check the webhook or the GitHub App installation of the repository on the git provider and report the discrepancies")
+	cmd.Flags().StringP(
+		pacNamespaceFlag, "", "", "The namespace where pac is installed, used by the check")
+	cmd.Flags().IntP(
+		limitFlag, "", defaultRunsLimit, "Number of runs to show, 0 to show all the recorded runs")
+	cmd.Flags().StringP(
+		resultsURLFlag, "", os.Getenv("TEKTON_RESULTS_URL"), "URL of the Tekton Results API to page through the history of the runs pruned from the cluster")
 	return cmd
 }
 
@@ -161,6 +286,44 @@ func filterOnlyToPipelineRun(opts *describeOpts, statuses []v1alpha1.RepositoryR
 	return ret
 }
 
+// enoughRuns returns true if the runs are enough to describe the repository,
+// or contain the target PipelineRun.
+func (o *describeOpts) enoughRuns(statuses []v1alpha1.RepositoryRunStatus) bool {
+	if o.TargetPipelineRun != "" {
+		return len(filterOnlyToPipelineRun(o, statuses)) > 0
+	}
+	return o.Limit > 0 && len(statuses) >= o.Limit
+}
+
+// addResultsHistory pages through the runs archived by Tekton Results until
+// there are enough runs to show, the runs still on the cluster or in the
+// Repository status are not added twice.
+func addResultsHistory(ctx context.Context, cs *params.Run, opts *describeOpts, repository *v1alpha1.Repository, statuses []v1alpha1.RepositoryRunStatus) ([]v1alpha1.RepositoryRunStatus, error) {
+	client := &status.ResultsClient{HTTP: &cs.Clients.HTTP, BaseURL: opts.ResultsURL, Token: opts.ResultsToken}
+	seen := map[string]bool{}
+	for _, st := range statuses {
+		seen[st.PipelineRunName] = true
+	}
+	for pageToken := ""; !opts.enoughRuns(statuses); {
+		page, next, err := client.RepositoryHistory(ctx, cs.Clients.ConsoleUI, repository, resultsPageSize, pageToken)
+		if err != nil {
+			return nil, err
+		}
+		for _, st := range page {
+			if seen[st.PipelineRunName] {
+				continue
+			}
+			seen[st.PipelineRunName] = true
+			statuses = append(statuses, st)
+		}
+		if next == "" {
+			break
+		}
+		pageToken = next
+	}
+	return sort.RepositorySortRunStatus(statuses), nil
+}
+
 func describe(ctx context.Context, cs *params.Run, clock clockwork.Clock, opts *describeOpts, ioStreams *cli.IOStreams, repoName string) error {
 	var repository *v1alpha1.Repository
 	var err error
@@ -187,7 +350,10 @@ func describe(ctx context.Context, cs *params.Run, clock clockwork.Clock, opts *
 		if err != nil {
 			return err
 		}
-		events, _ := kinteract.GetEvents(ctx, repository.GetNamespace(), "Repository", repository.GetName())
+		events, err := kinteract.GetEvents(ctx, repository.GetNamespace(), "Repository", repository.GetName())
+		if err != nil {
+			return err
+		}
 
 		// events to runtime obj
 		runTimeObj := []runtime.Object{}
@@ -222,22 +388,26 @@ func describe(ctx context.Context, cs *params.Run, clock clockwork.Clock, opts *
 		// if there are more events than the max limit, take only the latest
 		// equal to max limit
 		if len(eventList) > maxEventLimit {
-			eventList = eventList[:maxEventLimit-1]
+			eventList = eventList[:maxEventLimit]
 		}
 	}
 
-	colorScheme := ioStreams.ColorScheme()
+	renderer := output.NewRenderer(ioStreams, clock, opts.UseRealTime)
+	colorScheme := renderer.ColorScheme
 	funcMap := template.FuncMap{
-		"formatError":     formatError,
-		"formatStatus":    formatStatus,
-		"formatEventType": formatting.CamelCasit,
-		"formatDuration":  formatting.PRDuration,
-		"formatTime":      formatting.Age,
-		"sanitizeBranch":  formatting.SanitizeBranch,
-		"shortSHA":        formatting.ShortSHA,
+		"formatError":   formatError,
+		"formatStatus":  formatStatus,
+		"formatEvent":   formatEvent,
+		"formatRouting": formatRouting,
 	}
 
 	statuses := status.MixLivePRandRepoStatus(ctx, cs, *repository)
+	if opts.ResultsURL != "" {
+		statuses, err = addResultsHistory(ctx, cs, opts, repository, statuses)
+		if err != nil {
+			return err
+		}
+	}
 
 	if opts.TargetPipelineRun != "" {
 		statuses = filterOnlyToPipelineRun(opts, statuses)
@@ -245,28 +415,74 @@ func describe(ctx context.Context, cs *params.Run, clock clockwork.Clock, opts *
 			return fmt.Errorf("cannot find target pipelinerun %s", opts.TargetPipelineRun)
 		}
 	}
+	if opts.Limit > 0 && len(statuses) > opts.Limit {
+		statuses = statuses[:opts.Limit]
+	}
+
+	if opts.Output == outputMarkdown {
+		if len(statuses) == 0 {
+			return fmt.Errorf("no runs has started on repository %s", repository.GetName())
+		}
+		md, err := formatting.MarkdownRunSummary(repository, statuses[0])
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprint(ioStreams.Out, md)
+		return err
+	}
 
 	data := struct {
 		Repository  *v1alpha1.Repository
 		Statuses    []v1alpha1.RepositoryRunStatus
 		ColorScheme *cli.ColorScheme
-		Clock       clockwork.Clock
+		Renderer    *output.Renderer
 		Opts        *describeOpts
 		EventList   []corev1.Event
+		SharedURL   []v1alpha1.Repository
 	}{
 		Repository:  repository,
 		Statuses:    statuses,
 		ColorScheme: colorScheme,
-		Clock:       clock,
+		Renderer:    renderer,
 		EventList:   eventList,
 		Opts:        opts,
+		SharedURL:   sharedURLRepositories(ctx, cs, repository),
+	}
+	if err := renderer.Table(ioStreams.Out, "Describe Repository", describeTemplate, funcMap, data); err != nil {
+		return err
 	}
-	w := ansiterm.NewTabWriter(ioStreams.Out, 0, 5, 3, ' ', tabwriter.TabIndent)
-	t := template.Must(template.New("Describe Repository").Funcs(funcMap).Parse(describeTemplate))
 
-	if err := t.Execute(w, data); err != nil {
+	if !opts.Check {
+		return nil
+	}
+	checkRepository := opts.checkRepository
+	if checkRepository == nil {
+		checkRepository = webhook.Check
+	}
+	findings, err := checkRepository(ctx, &webhook.CheckOptions{
+		Run:          cs,
+		Repository:   repository,
+		PACNamespace: opts.PACNamespace,
+	})
+	if err != nil {
 		return err
 	}
+	_, err = fmt.Fprint(ioStreams.Out, formatFindings(findings, colorScheme))
+	return err
+}
 
-	return w.Flush()
+// formatFindings formats the result of the check of the repository
+// configuration on the git provider.
+func formatFindings(findings []webhook.Finding, cs *cli.ColorScheme) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n%s\n", cs.Bold("Git provider check"))
+	if len(findings) == 0 {
+		fmt.Fprintf(&b, "%s The repository is correctly configured on the git provider\n", cs.SuccessIcon())
+		return b.String()
+	}
+	for _, f := range findings {
+		fmt.Fprintf(&b, "%s %s\n", cs.FailureIcon(), f.Problem)
+		fmt.Fprintf(&b, "  %s %s\n", cs.InfoIcon(), f.Suggestion)
+	}
+	return b.String()
 }
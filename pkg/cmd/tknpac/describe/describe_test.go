@@ -1,15 +1,21 @@
 package describe
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
-	"strings"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
 	"testing"
 	"time"
 
 	"github.com/google/go-github/v49/github"
 	"github.com/jonboulle/clockwork"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli/webhook"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/consoleui"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
@@ -18,7 +24,7 @@ import (
 	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
 	tektontest "github.com/openshift-pipelines/pipelines-as-code/pkg/test/tekton"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
-	"gotest.tools/v3/golden"
+	"gotest.tools/v3/assert"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	knativeapis "knative.dev/pkg/apis"
@@ -30,6 +36,12 @@ func TestDescribe(t *testing.T) {
 	cw := clockwork.NewFakeClock()
 	ns := "ns"
 	running := tektonv1.PipelineRunReasonRunning.String()
+	displayNamed := tektontest.MakePRCompletion(cw, "unit-tests-abcde", ns, running, map[string]string{
+		"pipelinesascode.tekton.dev/repository": "test-run",
+		"pipelinesascode.tekton.dev/sha":        "sha2",
+		"pipelinesascode.tekton.dev/branch":     "vavaroom",
+	}, 40)
+	displayNamed.SetAnnotations(map[string]string{keys.DisplayName: "Unit tests"})
 	type args struct {
 		currentNamespace string
 		repoName         string
@@ -37,6 +49,13 @@ func TestDescribe(t *testing.T) {
 		opts             *describeOpts
 		pruns            []*tektonv1.PipelineRun
 		events           []*corev1.Event
+		findings         []webhook.Finding
+		// results are the PipelineRuns archived by Tekton Results, served
+		// one per page
+		results []*tektonv1.PipelineRun
+		routing *v1alpha1.Routing
+		// sharedURL are the other Repositories declaring the same URL
+		sharedURL []*v1alpha1.Repository
 	}
 	tests := []struct {
 		name    string
@@ -179,6 +198,54 @@ func TestDescribe(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "tasks and results of a pruned run",
+			args: args{
+				repoName:         "test-run",
+				currentNamespace: "namespace",
+				opts:             &describeOpts{},
+				statuses: []v1alpha1.RepositoryRunStatus{
+					{
+						Status: knativeduckv1.Status{
+							Conditions: []knativeapis.Condition{
+								{
+									Reason: "Failed",
+								},
+							},
+						},
+						TaskRuns: []v1alpha1.TaskRunSummary{
+							{
+								Name:           "build",
+								Reason:         "Succeeded",
+								StartTime:      &metav1.Time{Time: cw.Now().Add(-16 * time.Minute)},
+								CompletionTime: &metav1.Time{Time: cw.Now().Add(-15 * time.Minute)},
+							},
+							{
+								Name:           "unit-tests",
+								Reason:         "Failed",
+								StartTime:      &metav1.Time{Time: cw.Now().Add(-15 * time.Minute)},
+								CompletionTime: &metav1.Time{Time: cw.Now().Add(-13 * time.Minute)},
+							},
+							{
+								Name: "deploy",
+							},
+						},
+						Results: []v1alpha1.RunResult{
+							{Name: "image", Value: "quay.io/org/app@sha256:1234"},
+						},
+						PipelineRunName: "pipelinerun1",
+						LogURL:          github.String("https://everywhere.anwywhere"),
+						StartTime:       &metav1.Time{Time: cw.Now().Add(-16 * time.Minute)},
+						CompletionTime:  &metav1.Time{Time: cw.Now().Add(-13 * time.Minute)},
+						SHA:             github.String("SHA"),
+						SHAURL:          github.String("https://anurl.com/commit/SHA"),
+						Title:           github.String("A title"),
+						TargetBranch:    github.String("TargetBranch"),
+					},
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "use real time",
 			args: args{
@@ -370,6 +437,96 @@ func TestDescribe(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "pipelinerun warning event",
+			args: args{
+				repoName:         "test-run",
+				currentNamespace: "namespace",
+				opts: &describeOpts{
+					PacCliOpts: cli.PacCliOpts{
+						Namespace: "namespace",
+					},
+					ShowEvents: true,
+				},
+				events: []*corev1.Event{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							CreationTimestamp: metav1.Time{Time: cw.Now().Add(-5 * time.Minute)},
+							Namespace:         "namespace",
+							Name:              "pipelinerun1-a",
+						},
+						Message: "pipelinerun has failed to start",
+						Reason:  "FailedToStart",
+						Type:    corev1.EventTypeWarning,
+						InvolvedObject: corev1.ObjectReference{
+							Name: "pipelinerun1", Kind: "PipelineRun", Namespace: "namespace",
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "markdown output",
+			args: args{
+				repoName:         "test-run",
+				currentNamespace: "namespace",
+				opts:             &describeOpts{Output: outputMarkdown},
+				statuses: []v1alpha1.RepositoryRunStatus{
+					{
+						Status: knativeduckv1.Status{
+							Conditions: []knativeapis.Condition{
+								{
+									Status: corev1.ConditionFalse,
+									Reason: "Failed",
+								},
+							},
+						},
+						CollectedTaskInfos: &map[string]v1alpha1.TaskInfos{
+							"task1": {
+								Reason:     "Failed",
+								LogSnippet: "Error: this is a failure",
+							},
+						},
+						PipelineRunName: "pipelinerun1",
+						LogURL:          github.String("https://everywhere.anwywhere"),
+						StartTime:       &metav1.Time{Time: cw.Now().Add(-16 * time.Minute)},
+						CompletionTime:  &metav1.Time{Time: cw.Now().Add(-15 * time.Minute)},
+						SHA:             github.String("SHA"),
+						SHAURL:          github.String("https://anurl.com/commit/SHA"),
+						Title:           github.String("A title"),
+						TargetBranch:    github.String("TargetBranch"),
+						EventType:       github.String("pull_request"),
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "check git provider configuration",
+			args: args{
+				repoName:         "test-run",
+				currentNamespace: "namespace",
+				opts:             &describeOpts{Check: true},
+				findings: []webhook.Finding{
+					{
+						Problem:    "no webhook on owner/repo points to the controller URL https://controller.url",
+						Suggestion: "recreate the webhook with: tkn pac webhook add -n namespace test-run",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "check git provider configuration without findings",
+			args: args{
+				repoName:         "test-run",
+				currentNamespace: "namespace",
+				opts:             &describeOpts{Check: true},
+				findings:         []webhook.Finding{},
+			},
+			wantErr: false,
+		},
 		{
 			name: "multiple repo status",
 			args: args{
@@ -437,9 +594,128 @@ func TestDescribe(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "limit the runs",
+			args: args{
+				repoName:         "test-run",
+				currentNamespace: ns,
+				opts:             &describeOpts{Limit: 2},
+				pruns: []*tektonv1.PipelineRun{
+					tektontest.MakePRCompletion(cw, "running", ns, running, map[string]string{
+						"pipelinesascode.tekton.dev/repository": "test-run",
+						"pipelinesascode.tekton.dev/sha":        "sha1",
+						"pipelinesascode.tekton.dev/branch":     "tartanpion",
+					}, 30),
+					tektontest.MakePRCompletion(cw, "running2", ns, running, map[string]string{
+						"pipelinesascode.tekton.dev/repository": "test-run",
+						"pipelinesascode.tekton.dev/sha":        "sha2",
+						"pipelinesascode.tekton.dev/branch":     "vavaroom",
+					}, 40),
+					tektontest.MakePRCompletion(cw, "running3", ns, running, map[string]string{
+						"pipelinesascode.tekton.dev/repository": "test-run",
+						"pipelinesascode.tekton.dev/sha":        "sha3",
+						"pipelinesascode.tekton.dev/branch":     "papayolo",
+					}, 50),
+				},
+				statuses: []v1alpha1.RepositoryRunStatus{},
+			},
+			wantErr: false,
+		},
+		{
+			name: "display name",
+			args: args{
+				repoName:         "test-run",
+				currentNamespace: ns,
+				opts:             &describeOpts{},
+				pruns: []*tektonv1.PipelineRun{
+					tektontest.MakePRCompletion(cw, "running", ns, running, map[string]string{
+						"pipelinesascode.tekton.dev/repository": "test-run",
+						"pipelinesascode.tekton.dev/sha":        "sha1",
+						"pipelinesascode.tekton.dev/branch":     "tartanpion",
+					}, 30),
+					displayNamed,
+				},
+				statuses: []v1alpha1.RepositoryRunStatus{},
+			},
+			wantErr: false,
+		},
+		{
+			name: "history from tekton results",
+			args: args{
+				repoName:         "test-run",
+				currentNamespace: ns,
+				opts:             &describeOpts{Limit: 3},
+				pruns: []*tektonv1.PipelineRun{
+					tektontest.MakePRCompletion(cw, "running", ns, running, map[string]string{
+						"pipelinesascode.tekton.dev/repository": "test-run",
+						"pipelinesascode.tekton.dev/sha":        "sha1",
+						"pipelinesascode.tekton.dev/branch":     "tartanpion",
+					}, 30),
+				},
+				results: []*tektonv1.PipelineRun{
+					tektontest.MakePRCompletion(cw, "running", ns, running, map[string]string{
+						"pipelinesascode.tekton.dev/repository": "test-run",
+						"pipelinesascode.tekton.dev/sha":        "sha1",
+						"pipelinesascode.tekton.dev/branch":     "tartanpion",
+					}, 30),
+					tektontest.MakePRCompletion(cw, "pruned", ns, "", map[string]string{
+						"pipelinesascode.tekton.dev/repository": "test-run",
+						"pipelinesascode.tekton.dev/sha":        "sha4",
+						"pipelinesascode.tekton.dev/branch":     "vavaroom",
+						"pipelinesascode.tekton.dev/event-type": "push",
+					}, 60),
+					tektontest.MakePRCompletion(cw, "pruned2", ns, "", map[string]string{
+						"pipelinesascode.tekton.dev/repository": "test-run",
+						"pipelinesascode.tekton.dev/sha":        "sha5",
+						"pipelinesascode.tekton.dev/branch":     "papayolo",
+						"pipelinesascode.tekton.dev/event-type": "pull_request",
+					}, 90),
+					tektontest.MakePRCompletion(cw, "not-reached", ns, "", map[string]string{
+						"pipelinesascode.tekton.dev/repository": "test-run",
+						"pipelinesascode.tekton.dev/sha":        "sha6",
+						"pipelinesascode.tekton.dev/branch":     "main",
+					}, 120),
+				},
+				statuses: []v1alpha1.RepositoryRunStatus{},
+			},
+			wantErr: false,
+		},
+		{
+			name: "repositories sharing the url",
+			args: args{
+				repoName:         "test-run",
+				currentNamespace: ns,
+				opts:             &describeOpts{},
+				routing:          &v1alpha1.Routing{Branches: []string{"release-*"}},
+				sharedURL: []*v1alpha1.Repository{
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "catch-all", Namespace: ns},
+						Spec:       v1alpha1.RepositorySpec{URL: "https://anurl.com/"},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "pushes", Namespace: ns},
+						Spec: v1alpha1.RepositorySpec{
+							URL:     "https://anurl.com",
+							Routing: &v1alpha1.Routing{EventTypes: []string{"push"}, Branches: []string{"main"}},
+						},
+					},
+				},
+				pruns: []*tektonv1.PipelineRun{
+					tektontest.MakePRCompletion(cw, "running", ns, running, map[string]string{
+						"pipelinesascode.tekton.dev/repository": "test-run",
+						"pipelinesascode.tekton.dev/sha":        "sha1",
+						"pipelinesascode.tekton.dev/branch":     "release-1.0",
+					}, 30),
+				},
+				statuses: []v1alpha1.RepositoryRunStatus{},
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
+		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
 			ns := tt.args.currentNamespace
 			if tt.args.opts.Namespace != "" {
 				ns = tt.args.opts.Namespace
@@ -451,11 +727,13 @@ func TestDescribe(t *testing.T) {
 						Namespace: ns,
 					},
 					Spec: v1alpha1.RepositorySpec{
-						URL: "https://anurl.com",
+						URL:     "https://anurl.com",
+						Routing: tt.args.routing,
 					},
 					Status: tt.args.statuses,
 				},
 			}
+			repositories = append(repositories, tt.args.sharedURL...)
 
 			tdata := testclient.Data{
 				Events: tt.args.events,
@@ -481,12 +759,31 @@ func TestDescribe(t *testing.T) {
 				Info: info.Info{Kube: info.KubeOpts{Namespace: tt.args.currentNamespace}},
 			}
 
+			if len(tt.args.results) > 0 {
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					page, _ := strconv.Atoi(r.URL.Query().Get("page_token"))
+					// the pages after the limit is reached are never asked
+					assert.Assert(t, page < len(tt.args.results)-1)
+					value, _ := json.Marshal(tt.args.results[page])
+					record, _ := json.Marshal(map[string]interface{}{
+						"data": map[string]interface{}{"type": "tekton.dev/v1.PipelineRun", "value": value},
+					})
+					fmt.Fprintf(w, `{"records": [%s], "nextPageToken": "%d"}`, record, page+1)
+				}))
+				defer server.Close()
+				tt.args.opts.ResultsURL = server.URL
+			}
+
+			tt.args.opts.checkRepository = func(context.Context, *webhook.CheckOptions) ([]webhook.Finding, error) {
+				return tt.args.findings, nil
+			}
+
 			io, out := tcli.NewIOStream()
 			if err := describe(
 				ctx, cs, cw, tt.args.opts, io, tt.args.repoName); (err != nil) != tt.wantErr {
 				t.Errorf("describe() error = %v, wantErr %v", err, tt.wantErr)
 			} else {
-				golden.Assert(t, out.String(), strings.ReplaceAll(fmt.Sprintf("%s.golden", t.Name()), "/", "-"))
+				tcli.AssertGolden(t, out.String())
 			}
 		})
 	}
@@ -3,6 +3,7 @@ package generate
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -38,6 +39,8 @@ type Opts struct {
 	overwrite               bool
 	language                string
 	generateWithClusterTask bool
+	notification            string
+	dryRun                  bool
 }
 
 func MakeOpts() *Opts {
@@ -99,11 +102,33 @@ func Command(run *params.Run, ioStreams *cli.IOStreams) *cobra.Command {
 		"Wether to overwrite the file if it exist")
 	cmd.PersistentFlags().StringVarP(&gopt.language, "language", "l", "",
 		"Generate for this programming language")
+	cmd.PersistentFlags().StringVar(&gopt.notification, "notification", "",
+		"Send a notification when the PipelineRun finishes: none, slack or email")
 	cmd.PersistentFlags().BoolVarP(&gopt.generateWithClusterTask, "use-clustertasks", "", false,
 		"By default we will generate the pipeline using task from hub. If you want to use cluster tasks, set this flag")
+	cmd.PersistentFlags().BoolVar(&gopt.dryRun, "dry-run", false,
+		"Print the generated PipelineRun on the standard output instead of writing it to a file")
 	return cmd
 }
 
+// messagesOut is where the messages to the user are written, the standard
+// error on a dry run to keep the standard output for the PipelineRun.
+func (o *Opts) messagesOut() io.Writer {
+	if o.dryRun {
+		return o.IOStreams.ErrOut
+	}
+	return o.IOStreams.Out
+}
+
+// askOpts are the options of the prompts, they are shown on the standard
+// error on a dry run to keep the standard output for the PipelineRun.
+func (o *Opts) askOpts() []survey.AskOpt {
+	if !o.dryRun {
+		return nil
+	}
+	return []survey.AskOpt{survey.WithStdio(os.Stdin, os.Stderr, os.Stderr)}
+}
+
 func Generate(o *Opts, recreateTemplate bool) error {
 	if err := o.targetEvent(); err != nil {
 		return err
@@ -124,27 +149,28 @@ func (o *Opts) targetEvent() error {
 	if o.Event.EventType != "" {
 		return nil
 	}
-	msg := "Enter the Git event type for triggering the pipeline: "
+	p := o.IOStreams.Printer()
+	msg := p.T("Enter the Git event type for triggering the pipeline: ")
 
 	eventLabels := make([]string, 0, len(eventTypes))
 	for _, label := range eventTypes {
-		eventLabels = append(eventLabels, label)
+		eventLabels = append(eventLabels, p.T(label))
 	}
 	if err := prompt.SurveyAskOne(
 		&survey.Select{
 			Message: msg,
 			Options: eventLabels,
 			Default: 0,
-		}, &choice); err != nil {
+		}, &choice, o.askOpts()...); err != nil {
 		return err
 	}
 
 	if choice == "" {
-		choice = defaultEventType
+		choice = p.T(defaultEventType)
 	}
 
 	for k, v := range eventTypes {
-		if v == choice {
+		if p.T(v) == choice {
 			o.Event.EventType = k
 			return nil
 		}
@@ -153,6 +179,19 @@ func (o *Opts) targetEvent() error {
 	return fmt.Errorf("invalid event type: %s", choice)
 }
 
+// defaultBranch returns the default branch of the repository, the one of the
+// event if the provider has told us about it or the one of the git remote,
+// falling back to main when nobody knows.
+func (o *Opts) defaultBranch() string {
+	if o.Event.DefaultBranch != "" {
+		return o.Event.DefaultBranch
+	}
+	if o.GitInfo != nil && o.GitInfo.DefaultBranch != "" {
+		return o.GitInfo.DefaultBranch
+	}
+	return mainBranch
+}
+
 func (o *Opts) branchOrTag() error {
 	var msg string
 	choice := new(string)
@@ -160,7 +199,7 @@ func (o *Opts) branchOrTag() error {
 		return nil
 	}
 
-	o.Event.BaseBranch = mainBranch
+	o.Event.BaseBranch = o.defaultBranch()
 
 	if o.Event.EventType == "pull_request" {
 		msg = "Enter the target GIT branch for the Pull Request (default: %s): "
@@ -170,8 +209,8 @@ func (o *Opts) branchOrTag() error {
 
 	if err := prompt.SurveyAskOne(
 		&survey.Input{
-			Message: fmt.Sprintf(msg, mainBranch),
-		}, choice); err != nil {
+			Message: o.IOStreams.Printer().Sprintf(msg, o.Event.BaseBranch),
+		}, choice, o.askOpts()...); err != nil {
 		return err
 	}
 
@@ -196,8 +235,23 @@ func generatefileName(eventType string) string {
 // directory.
 func (o *Opts) samplePipeline(recreateTemplate bool) error {
 	cs := o.IOStreams.ColorScheme()
+	p := o.IOStreams.Printer()
 	var relpath, fpath string
 
+	if o.dryRun {
+		if recreateTemplate {
+			if err := o.askNotification(); err != nil {
+				return err
+			}
+		}
+		tmpl, err := o.genTmpl()
+		if err != nil {
+			return err
+		}
+		_, err = o.IOStreams.Out.Write(tmpl.Bytes())
+		return err
+	}
+
 	if o.FileName != "" {
 		fpath = o.FileName
 		relpath = fpath
@@ -209,30 +263,37 @@ func (o *Opts) samplePipeline(recreateTemplate bool) error {
 			if err := os.MkdirAll(filepath.Join(o.GitInfo.TopLevelPath, ".tekton"), 0o755); err != nil {
 				return err
 			}
-			fmt.Fprintf(o.IOStreams.Out, "%s Directory %s has been created.\n",
+			fmt.Fprint(o.IOStreams.Out, p.Sprintf("%s Directory %s has been created.\n",
 				cs.InfoIcon(),
 				cs.Bold(".tekton"),
-			)
+			))
 		}
 	}
 
 	if _, err := os.Stat(fpath); !os.IsNotExist(err) && !o.overwrite {
 		if recreateTemplate {
 			var overwrite bool
-			msg := fmt.Sprintf("There is already a file named: %s would you like me to override it?", relpath)
+			msg := p.Sprintf("There is already a file named: %s would you like me to override it?", relpath)
 			if err := prompt.SurveyAskOne(&survey.Confirm{Message: msg, Default: false}, &overwrite); err != nil {
 				return err
 			}
 			if !overwrite {
-				fmt.Fprintf(o.IOStreams.ErrOut, "%s Not overwriting file, exiting...\n", cs.WarningIcon())
-				fmt.Fprintf(o.IOStreams.ErrOut, "%s Feel free to use the -f flag if you want to target another file name\n...", cs.InfoIcon())
+				fmt.Fprint(o.IOStreams.ErrOut, p.Sprintf("%s Not overwriting file, exiting...\n", cs.WarningIcon()))
+				fmt.Fprint(o.IOStreams.ErrOut, p.Sprintf("%s Feel free to use the -f flag if you want to target another file name\n...", cs.InfoIcon()))
 			}
 		} else {
-			fmt.Fprintf(o.IOStreams.Out, "%s There is already a file named: %s, skipping template generation, feel free to use \"%s pac generate\" command to generate sample template.\n", cs.InfoIcon(), relpath,
-				settings.TknBinaryName)
+			fmt.Fprint(o.IOStreams.Out, p.Sprintf("%s There is already a file named: %s, skipping template generation, feel free to use \"%s pac generate\" command to generate sample template.\n", cs.InfoIcon(), relpath,
+				settings.TknBinaryName))
 		}
 		return nil
 	}
+	// only the generate command offers the notification, the commands
+	// creating a Repository generate a basic template
+	if recreateTemplate {
+		if err := o.askNotification(); err != nil {
+			return err
+		}
+	}
 	tmpl, err := o.genTmpl()
 	if err != nil {
 		return err
@@ -244,11 +305,11 @@ func (o *Opts) samplePipeline(recreateTemplate bool) error {
 		return fmt.Errorf("cannot write template to %s: %w", fpath, err)
 	}
 
-	fmt.Fprintf(o.IOStreams.Out, "%s A basic template has been created in %s, feel free to customize it.\n",
+	fmt.Fprint(o.IOStreams.Out, p.Sprintf("%s A basic template has been created in %s, feel free to customize it.\n",
 		cs.SuccessIcon(),
 		cs.Bold(fpath),
-	)
-	fmt.Fprintf(o.IOStreams.Out, "%s You can test your pipeline by pushing generated template to your git repository\n", cs.InfoIcon())
+	))
+	fmt.Fprint(o.IOStreams.Out, p.Sprintf("%s You can test your pipeline by pushing generated template to your git repository\n", cs.InfoIcon()))
 
 	return nil
 }
@@ -48,6 +48,23 @@ func TestGenerateTemplate(t *testing.T) {
 			},
 			regenerateTemplate: true,
 		},
+		{
+			name: "pull request default branch from git",
+			askStubs: func(as *prompt.AskStubber) {
+				as.StubOneDefault() // pull_request
+				as.StubOne("")      // default as trunk
+				as.StubOne(true)    // pipelinerun generation
+			},
+			checkGeneratedFile: ".tekton/pull-request.yaml",
+			checkRegInGeneratedFile: []*regexp.Regexp{
+				regexp.MustCompile(`.*on-target-branch: "\[trunk\]"`),
+			},
+			gitinfo: git.Info{
+				URL:           "https://hello/moto",
+				DefaultBranch: "trunk",
+			},
+			regenerateTemplate: true,
+		},
 		{
 			name: "pull request already exist don't overwrite",
 			askStubs: func(as *prompt.AskStubber) {
@@ -72,6 +89,7 @@ func TestGenerateTemplate(t *testing.T) {
 			askStubs: func(as *prompt.AskStubber) {
 				as.StubOneDefault() // pull_request
 				as.StubOne("")      // default as main
+				as.StubOneDefault() // no notification
 			},
 			addExtraFilesInRepo: map[string]string{
 				"go.mod": "random string",
@@ -94,6 +112,7 @@ func TestGenerateTemplate(t *testing.T) {
 				// I can't see to make the stubbing work for push :\
 				as.StubOneDefault() // pull_request
 				as.StubOne("")      // default as main
+				as.StubOneDefault() // no notification
 			},
 			addExtraFilesInRepo: map[string]string{
 				"setup.py": "random string",
@@ -110,6 +129,45 @@ func TestGenerateTemplate(t *testing.T) {
 			},
 			regenerateTemplate: true,
 		},
+		{
+			name: "pull request with slack notification",
+			askStubs: func(as *prompt.AskStubber) {
+				as.StubOneDefault() // pull_request
+				as.StubOne("")      // default as main
+				as.StubOne("Slack") // notification
+			},
+			addExtraFilesInRepo: map[string]string{
+				"go.mod": "random string",
+			},
+			checkGeneratedFile: ".tekton/pull-request.yaml",
+			checkRegInGeneratedFile: []*regexp.Regexp{
+				regexp.MustCompile(`pipelinesascode.tekton.dev/task-3: "send-to-channel-slack"`),
+				regexp.MustCompile(`(?m)^    finally:\n(      #.*\n)*      - name: notify-slack$`),
+				regexp.MustCompile(`\$\(tasks.status\)`),
+			},
+			gitinfo: git.Info{
+				URL: "https://hello/golang",
+			},
+			regenerateTemplate: true,
+		},
+		{
+			name: "push with email notification",
+			askStubs: func(as *prompt.AskStubber) {
+				as.StubOne("")      // default as main
+				as.StubOne("Email") // notification
+			},
+			event:              info.Event{EventType: "push"},
+			checkGeneratedFile: ".tekton/push.yaml",
+			checkRegInGeneratedFile: []*regexp.Regexp{
+				regexp.MustCompile(`pipelinesascode.tekton.dev/task-3: "sendmail"`),
+				regexp.MustCompile(`(?m)^          name: sendmail$`),
+				regexp.MustCompile(`(?m)^            value: smtp-server$`),
+			},
+			gitinfo: git.Info{
+				URL: "https://hello/moto",
+			},
+			regenerateTemplate: true,
+		},
 		{
 			name: "pull request already exist don't regenerate sample template",
 			askStubs: func(as *prompt.AskStubber) {
@@ -177,3 +235,28 @@ func TestGenerateTemplate(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateDryRun(t *testing.T) {
+	as, teardown := prompt.InitAskStubber()
+	defer teardown()
+	as.StubOne("") // default as main
+	io, _, stdout, stderr := cli.IOTest()
+
+	nd := fs.NewDir(t, "TestGenerateDryRun", fs.WithFile("go.mod", "module moto"))
+	defer nd.Remove()
+
+	err := Generate(&Opts{
+		Event:        &info.Event{EventType: "push"},
+		GitInfo:      &git.Info{URL: "https://hello/moto", TopLevelPath: nd.Path()},
+		IOStreams:    io,
+		CLIOpts:      &cli.PacCliOpts{},
+		notification: notificationNone,
+		dryRun:       true,
+	}, true)
+	assert.NilError(t, err)
+
+	assert.Assert(t, regexp.MustCompile("name: moto-push").MatchString(stdout.String()), stdout.String())
+	assert.Assert(t, regexp.MustCompile("programming language").MatchString(stderr.String()), stderr.String())
+	_, err = os.Stat(nd.Join(".tekton"))
+	assert.Assert(t, os.IsNotExist(err), "nothing should have been written on a dry run")
+}
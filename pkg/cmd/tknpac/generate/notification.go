@@ -0,0 +1,148 @@
+package generate
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli/prompt"
+)
+
+const (
+	notificationNone  = "none"
+	notificationSlack = "slack"
+	notificationEmail = "email"
+)
+
+var notificationChoices = map[string]string{
+	notificationNone:  "None",
+	notificationSlack: "Slack",
+	notificationEmail: "Email",
+}
+
+// notificationTasks are the hub tasks sending the notifications and the
+// finally tasks using them, they get the status of the PipelineRun from the
+// aggregated status of its tasks.
+var notificationTasks = map[string]struct {
+	hubTask string
+	finally string
+}{
+	notificationSlack: {
+		hubTask: "send-to-channel-slack",
+		finally: `    finally:
+      # Notify a Slack channel of the result of the PipelineRun. Create the
+      # secret with the token of your Slack application in the namespace of the
+      # Repository with:
+      # kubectl create secret generic slack-token --from-literal=token=xoxb-...
+      - name: notify-slack
+        taskRef:
+          name: send-to-channel-slack
+        params:
+          - name: token-secret
+            value: slack-token
+          - name: channel
+            value: CHANGEME
+          - name: message
+            value: "PipelineRun $(context.pipelineRun.name) of $(params.repo_url) at $(params.revision) finished with status: $(tasks.status)"
+`,
+	},
+	notificationEmail: {
+		hubTask: "sendmail",
+		finally: `    finally:
+      # Send an email with the result of the PipelineRun. Create the secret
+      # with the SMTP server in the namespace of the Repository with:
+      # kubectl create secret generic smtp-server --from-literal=url=smtp.example.com \
+      #   --from-literal=port=587 --from-literal=user=USER \
+      #   --from-literal=password=PASSWORD --from-literal=tls=true
+      - name: notify-email
+        taskRef:
+          name: sendmail
+        params:
+          - name: server
+            value: smtp-server
+          - name: sender
+            value: ci@example.com
+          - name: recipients
+            value: team@example.com
+          - name: subject
+            value: "PipelineRun $(context.pipelineRun.name): $(tasks.status)"
+          - name: body
+            value: "PipelineRun $(context.pipelineRun.name) of $(params.repo_url) at $(params.revision) finished with status: $(tasks.status)"
+`,
+	},
+}
+
+var (
+	taskAnnotationRe = regexp.MustCompile(`pipelinesascode\.tekton\.dev/task-(\d+):`)
+	maxKeepRunsRe    = regexp.MustCompile(`(?m)^(    # how many runs we want to keep attached to this event\n)?    pipelinesascode\.tekton\.dev/max-keep-runs:`)
+	// the workspaces of the PipelineRun follow the pipelineSpec in the templates
+	specWorkspacesRe = regexp.MustCompile(`(?m)^\n?  workspaces:$`)
+)
+
+// askNotification asks which notification to send at the end of the
+// PipelineRun, unless it has been set with the flag.
+func (o *Opts) askNotification() error {
+	if o.notification != "" {
+		if _, ok := notificationChoices[o.notification]; !ok {
+			return fmt.Errorf("invalid notification %s, acceptable values: %s, %s or %s", o.notification,
+				notificationNone, notificationSlack, notificationEmail)
+		}
+		return nil
+	}
+
+	var choice string
+	p := o.IOStreams.Printer()
+	if err := prompt.SurveyAskOne(
+		&survey.Select{
+			Message: p.T("Would you like to send a notification when the PipelineRun finishes: "),
+			Options: []string{p.T(notificationChoices[notificationNone]), p.T(notificationChoices[notificationSlack]), p.T(notificationChoices[notificationEmail])},
+			Default: p.T(notificationChoices[notificationNone]),
+		}, &choice, o.askOpts()...); err != nil {
+		return err
+	}
+	o.notification = notificationNone
+	for k, v := range notificationChoices {
+		if p.T(v) == choice {
+			o.notification = k
+		}
+	}
+	return nil
+}
+
+// addNotification adds the hub task of the notification to the annotations
+// of the template and the task sending it to the finally tasks of its
+// pipeline.
+func addNotification(tmpl []byte, notification string) []byte {
+	task, ok := notificationTasks[notification]
+	if !ok {
+		return tmpl
+	}
+	next := 1
+	for _, match := range taskAnnotationRe.FindAllSubmatch(tmpl, -1) {
+		if n, _ := strconv.Atoi(string(match[1])); n >= next {
+			next = n + 1
+		}
+	}
+	annotation := fmt.Sprintf("    # Fetch the %s task from hub to send the notification\n    pipelinesascode.tekton.dev/task-%d: \"%s\"\n\n",
+		notification, next, task.hubTask)
+	loc := maxKeepRunsRe.FindIndex(tmpl)
+	if loc == nil {
+		return tmpl
+	}
+	tmpl = insertAt(tmpl, loc[0], annotation)
+
+	if loc = specWorkspacesRe.FindIndex(tmpl); loc == nil {
+		return tmpl
+	}
+	return insertAt(tmpl, loc[0], task.finally)
+}
+
+func insertAt(b []byte, pos int, s string) []byte {
+	var out bytes.Buffer
+	out.Write(b[:pos])
+	out.WriteString(s)
+	out.Write(b[pos:])
+	return out.Bytes()
+}
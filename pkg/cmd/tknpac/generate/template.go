@@ -7,6 +7,7 @@ import (
 	"io"
 	"log"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
@@ -54,10 +55,10 @@ func (o *Opts) detectLanguage() (string, error) {
 		}
 		fpath := filepath.Join(o.GitInfo.TopLevelPath, v.detectionFile)
 		if _, err := os.Stat(fpath); !os.IsNotExist(err) {
-			fmt.Fprintf(o.IOStreams.Out, "%s We have detected your repository using the programming language %s.\n",
+			fmt.Fprint(o.messagesOut(), o.IOStreams.Printer().Sprintf("%s We have detected your repository using the programming language %s.\n",
 				cs.SuccessIcon(),
 				cs.Bold(cases.Title(language.Und, cases.NoLower).String(t)),
-			)
+			))
 			return t, nil
 		}
 	}
@@ -76,8 +77,13 @@ func (o *Opts) genTmpl() (*bytes.Buffer, error) {
 	}
 	defer embedfile.Close()
 	tmplB, _ := io.ReadAll(embedfile)
+	// the templates may have been checked out with CRLF line endings (ie: on
+	// windows with core.autocrlf), always write them with LF.
+	tmplB = bytes.ReplaceAll(tmplB, []byte("\r\n"), []byte("\n"))
 
-	prName := filepath.Base(o.GitInfo.URL)
+	// this is an URL, not a file path, don't use filepath which would split on
+	// backslashes on windows.
+	prName := path.Base(o.GitInfo.URL)
 
 	// if eventType has both the events [push, pull_request] then skip
 	// adding it to pipelinerun name
@@ -94,5 +100,7 @@ func (o *Opts) genTmpl() (*bytes.Buffer, error) {
 	tmplB = bytes.ReplaceAll(tmplB, []byte(fmt.Sprintf("name: pipelinerun-%s", lang)),
 		[]byte(fmt.Sprintf("name: %s", prName)))
 
+	tmplB = addNotification(tmplB, o.notification)
+
 	return bytes.NewBuffer(tmplB), nil
 }
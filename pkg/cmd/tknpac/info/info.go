@@ -0,0 +1,114 @@
+package info
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/completion"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const longhelp = `
+
+info - show the features of Pipelines as Code supported by the git providers
+
+tkn pac info shows which features each git provider supports: the check runs,
+the comments on the pull requests, the filtering on the changed paths and the
+GitOps comment commands. With --repo it shows the features supported by the
+git provider of a Repository.`
+
+const (
+	namespaceFlag = "namespace"
+	repoFlag      = "repo"
+)
+
+type infoOptions struct {
+	run       *params.Run
+	ioStreams *cli.IOStreams
+	namespace string
+	repoName  string
+}
+
+func Command(run *params.Run, ioStreams *cli.IOStreams) *cobra.Command {
+	opts := &infoOptions{run: run, ioStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "info",
+		Long:  longhelp,
+		Short: "Show the features supported by the git providers",
+		Annotations: map[string]string{
+			"commandType": "main",
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.repoName == "" {
+				return printCapabilities(opts.ioStreams, provider.ProviderTypes)
+			}
+			ctx := cmd.Context()
+			if err := run.Clients.NewClients(ctx, &run.Info); err != nil {
+				return err
+			}
+			return repositoryInfo(ctx, opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.repoName, repoFlag, "", "", "The name of the Repository")
+	_ = cmd.RegisterFlagCompletionFunc(repoFlag,
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.BaseCompletion("repositories", args)
+		},
+	)
+	cmd.Flags().StringVarP(&opts.namespace, namespaceFlag, "n", "", "If present, the namespace scope for this CLI request")
+	_ = cmd.RegisterFlagCompletionFunc(namespaceFlag,
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.BaseCompletion(namespaceFlag, args)
+		},
+	)
+	return cmd
+}
+
+// repositoryInfo shows the features supported by the git provider of the
+// Repository.
+func repositoryInfo(ctx context.Context, opts *infoOptions) error {
+	if opts.namespace != "" {
+		opts.run.Info.Kube.Namespace = opts.namespace
+	}
+	repo, err := opts.run.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(opts.run.Info.Kube.Namespace).Get(ctx,
+		opts.repoName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	providerType := provider.RepositoryProviderType(repo)
+	if provider.Capabilities(providerType) == nil {
+		return fmt.Errorf("cannot detect the git provider of the Repository %s, set its spec.git_provider.type", repo.GetName())
+	}
+	fmt.Fprintf(opts.ioStreams.Out, "Repository %s uses the %s provider\n\n", repo.GetName(), providerType)
+	return printCapabilities(opts.ioStreams, []string{providerType})
+}
+
+// printCapabilities prints a table of the capabilities of the provider types.
+func printCapabilities(ioStreams *cli.IOStreams, providerTypes []string) error {
+	cs := ioStreams.ColorScheme()
+	w := tabwriter.NewWriter(ioStreams.Out, 0, 5, 3, ' ', tabwriter.TabIndent)
+	header := []string{"PROVIDER"}
+	for _, c := range provider.AllCapabilities {
+		header = append(header, strings.ToUpper(string(c)))
+	}
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, providerType := range providerTypes {
+		row := []string{providerType}
+		for _, c := range provider.AllCapabilities {
+			if provider.HasCapability(providerType, c) {
+				row = append(row, cs.SuccessIcon())
+			} else {
+				row = append(row, cs.FailureIcon())
+			}
+		}
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
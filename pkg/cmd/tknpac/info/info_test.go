@@ -0,0 +1,62 @@
+package info
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	tcli "github.com/openshift-pipelines/pipelines-as-code/pkg/test/cli"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestPrintCapabilities(t *testing.T) {
+	io, out := tcli.NewIOStream()
+	assert.NilError(t, printCapabilities(io, []string{"github-app", "bitbucket-cloud"}))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.DeepEqual(t, lines, []string{
+		"PROVIDER          CHECKS   COMMENTS   PATH-FILTERING   COMMENT-COMMANDS",
+		"github-app        ✓        X          ✓                ✓",
+		"bitbucket-cloud   X        ✓          X                ✓",
+	})
+}
+
+func TestRepositoryInfo(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+		Repositories: []*v1alpha1.Repository{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"},
+				Spec: v1alpha1.RepositorySpec{
+					URL:         "https://gitlab.example.com/group/repo",
+					GitProvider: &v1alpha1.GitProvider{Secret: &v1alpha1.Secret{Name: "token"}},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "unknown", Namespace: "ns"},
+				Spec: v1alpha1.RepositorySpec{
+					URL:         "https://git.example.com/owner/repo",
+					GitProvider: &v1alpha1.GitProvider{Secret: &v1alpha1.Secret{Name: "token"}},
+				},
+			},
+		},
+	})
+	run := &params.Run{
+		Clients: clients.Clients{PipelineAsCode: stdata.PipelineAsCode},
+		Info:    info.Info{Kube: info.KubeOpts{Namespace: "ns"}},
+	}
+
+	io, out := tcli.NewIOStream()
+	assert.NilError(t, repositoryInfo(ctx, &infoOptions{run: run, ioStreams: io, repoName: "repo"}))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Equal(t, lines[0], "Repository repo uses the gitlab provider")
+	assert.Equal(t, lines[3], "gitlab     X        ✓          ✓                ✓")
+
+	err := repositoryInfo(ctx, &infoOptions{run: run, ioStreams: io, repoName: "unknown"})
+	assert.Error(t, err, "cannot detect the git provider of the Repository unknown, set its spec.git_provider.type")
+}
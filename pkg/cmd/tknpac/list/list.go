@@ -4,18 +4,18 @@ import (
 	"context"
 	_ "embed"
 	"fmt"
-	"text/tabwriter"
 	"text/template"
 
 	"github.com/jonboulle/clockwork"
-	"github.com/juju/ansiterm"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli/output"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli/status"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/completion"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -92,6 +92,8 @@ func Root(run *params.Run, ioStreams *cli.IOStreams) *cobra.Command {
 	cmd.Flags().BoolVar(
 		&noheaders, noHeadersFlag, false, "don't print headers.")
 
+	ioStreams.AddQuietFlag(cmd, "Only output the names of the repositories, to be used in scripts")
+
 	cmd.Flags().StringVarP(&selectors, "selectors", "l",
 		"", "Selector (label query) to filter on, "+
 			"supports '=', "+
@@ -100,33 +102,51 @@ func Root(run *params.Run, ioStreams *cli.IOStreams) *cobra.Command {
 	return cmd
 }
 
-func formatStatus(status *v1alpha1.RepositoryRunStatus, cs *cli.ColorScheme, c clockwork.Clock, ns string, opts *cli.PacCliOpts) string {
+// successRate returns the percentage of the finished runs in statuses which
+// succeeded, running runs are not accounted for.
+func successRate(statuses []v1alpha1.RepositoryRunStatus) string {
+	finished, succeeded := 0, 0
+	for _, st := range statuses {
+		if len(st.Status.Conditions) == 0 {
+			continue
+		}
+		switch st.Status.Conditions[0].Status {
+		case corev1.ConditionTrue:
+			succeeded++
+			finished++
+		case corev1.ConditionFalse:
+			finished++
+		}
+	}
+	if finished == 0 {
+		return "---"
+	}
+	return fmt.Sprintf("%d%%", succeeded*100/finished)
+}
+
+// formatStatus formats the last run of a repository, the columns are always
+// in the same order so they can be relied on by scripts, the namespace
+// column being only appended at the end with --all-namespaces.
+func formatStatus(r *output.Renderer, status *v1alpha1.RepositoryRunStatus, rate, ns string, opts *cli.PacCliOpts) string {
 	// TODO: we could make a hyperlink to the console namespace list of repo if
 	// we wanted to go the extra step
+	cs := r.ColorScheme
+	var s string
 	if status == nil {
-		s := fmt.Sprintf("%s\t%s\t%s\t", cs.Dimmed("---"), cs.Dimmed("---"), cs.Dimmed("---"))
-		if opts.AllNameSpaces {
-			s += fmt.Sprintf("%s\t", ns)
-		}
-		return fmt.Sprintf("%s%s", s, cs.Dimmed("NoRun"))
-	}
-	starttime := formatting.Age(status.StartTime, c)
-	if opts.UseRealTime {
-		starttime = status.StartTime.Format("2006-01-02T15:04:05Z07:00") // RFC3339
+		s = fmt.Sprintf("%s\t%s\t%s\t%s\t%s",
+			cs.Dimmed("---"), cs.Dimmed("---"), cs.Dimmed("---"), cs.Dimmed("---"), cs.Dimmed("NoRun"))
+	} else {
+		s = fmt.Sprintf("%s\t%s\t%s\t%s\t%s",
+			cs.HyperLink(formatting.ShortSHA(*status.SHA), *status.SHAURL),
+			r.Time(status.StartTime),
+			formatting.PRDuration(*status),
+			rate,
+			r.Status(*status))
 	}
-	s := fmt.Sprintf("%s\t%s\t%s",
-		cs.HyperLink(formatting.ShortSHA(*status.SHA), *status.SHAURL),
-		starttime,
-		formatting.PRDuration(*status))
 	if opts.AllNameSpaces {
 		s = fmt.Sprintf("%s\t%s", s, ns)
 	}
-
-	reason := "UNKNOWN"
-	if len(status.Status.Conditions) > 0 {
-		reason = status.Status.Conditions[0].Reason
-	}
-	return fmt.Sprintf("%s\t%s", s, cs.HyperLink(cs.ColorStatus(reason), *status.LogURL))
+	return s
 }
 
 func list(ctx context.Context, cs *params.Run, opts *cli.PacCliOpts, ioStreams *cli.IOStreams, clock clockwork.Clock, selectors string) error {
@@ -145,9 +165,20 @@ func list(ctx context.Context, cs *params.Run, opts *cli.PacCliOpts, ioStreams *
 		return err
 	}
 
+	if ioStreams.IsQuiet() {
+		for _, repo := range repositories.Items {
+			if opts.AllNameSpaces {
+				fmt.Fprintf(ioStreams.Out, "%s/%s\n", repo.GetNamespace(), repo.GetName())
+				continue
+			}
+			fmt.Fprintln(ioStreams.Out, repo.GetName())
+		}
+		return nil
+	}
+
 	type repoStatusInfo struct {
-		Status               *v1alpha1.RepositoryRunStatus
-		Name, Namespace, URL string
+		Status                            *v1alpha1.RepositoryRunStatus
+		Name, Namespace, URL, SuccessRate string
 	}
 	repoStatuses := []repoStatusInfo{}
 	for _, repo := range repositories.Items {
@@ -160,30 +191,24 @@ func list(ctx context.Context, cs *params.Run, opts *cli.PacCliOpts, ioStreams *
 		if len(statuses) > 0 {
 			rs.Status = &statuses[0]
 		}
+		rs.SuccessRate = successRate(statuses)
 		repoStatuses = append(repoStatuses, rs)
 	}
 
-	w := ansiterm.NewTabWriter(ioStreams.Out, 0, 5, 3, ' ', tabwriter.TabIndent)
-	colorScheme := ioStreams.ColorScheme()
+	renderer := output.NewRenderer(ioStreams, clock, opts.UseRealTime)
 	data := struct {
 		Statuses    []repoStatusInfo
 		ColorScheme *cli.ColorScheme
-		Clock       clockwork.Clock
+		Renderer    *output.Renderer
 		Opts        *cli.PacCliOpts
 	}{
 		Statuses:    repoStatuses,
-		ColorScheme: colorScheme,
-		Clock:       clock,
+		ColorScheme: renderer.ColorScheme,
+		Renderer:    renderer,
 		Opts:        opts,
 	}
 	funcMap := template.FuncMap{
 		"formatStatus": formatStatus,
 	}
-
-	t := template.Must(template.New("LS Template").Funcs(funcMap).Parse(lsTmpl))
-	if err := t.Execute(w, data); err != nil {
-		return err
-	}
-	w.Flush()
-	return nil
+	return renderer.Table(ioStreams.Out, "LS Template", lsTmpl, funcMap, data)
 }
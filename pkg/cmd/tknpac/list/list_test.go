@@ -1,10 +1,6 @@
 package list
 
 import (
-	"bytes"
-	"fmt"
-	"io"
-	"strings"
 	"testing"
 	"time"
 
@@ -16,10 +12,10 @@ import (
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	tcli "github.com/openshift-pipelines/pipelines-as-code/pkg/test/cli"
 	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
 	tektontest "github.com/openshift-pipelines/pipelines-as-code/pkg/test/tekton"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
-	"gotest.tools/v3/golden"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	knativeapis "knative.dev/pkg/apis"
@@ -27,17 +23,6 @@ import (
 	rtesting "knative.dev/pkg/reconciler/testing"
 )
 
-func newIOStream() (*cli.IOStreams, *bytes.Buffer) {
-	in := &bytes.Buffer{}
-	out := &bytes.Buffer{}
-	errOut := &bytes.Buffer{}
-	return &cli.IOStreams{
-		In:     io.NopCloser(in),
-		Out:    out,
-		ErrOut: errOut,
-	}, out
-}
-
 func TestList(t *testing.T) {
 	running := tektonv1.PipelineRunReasonRunning.String()
 	cw := clockwork.NewFakeClock()
@@ -65,6 +50,7 @@ func TestList(t *testing.T) {
 				Status: knativeduckv1.Status{
 					Conditions: []knativeapis.Condition{
 						{
+							Status: corev1.ConditionTrue,
 							Reason: "Success",
 						},
 					},
@@ -92,6 +78,7 @@ func TestList(t *testing.T) {
 				Status: knativeduckv1.Status{
 					Conditions: []knativeapis.Condition{
 						{
+							Status: corev1.ConditionTrue,
 							Reason: "Success",
 						},
 					},
@@ -104,6 +91,32 @@ func TestList(t *testing.T) {
 				Title:           github.String("A title"),
 				LogURL:          github.String("https://help.me.obiwan.kenobi"),
 			},
+			{
+				Status: knativeduckv1.Status{
+					Conditions: []knativeapis.Condition{
+						{
+							Status: corev1.ConditionFalse,
+							Reason: "Failed",
+						},
+					},
+				},
+				PipelineRunName: "pipelinerun3",
+				StartTime:       &metav1.Time{Time: cw.Now().Add(-30 * time.Minute)},
+				CompletionTime:  &metav1.Time{Time: cw.Now().Add(-29 * time.Minute)},
+				SHA:             github.String("SHA3"),
+				SHAURL:          github.String("https://somewhereandnowhere/3"),
+				Title:           github.String("A title"),
+				LogURL:          github.String("https://help.me.obiwan.kenobi/3"),
+			},
+		},
+	}
+	repoNoRun := &pacv1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "norun",
+			Namespace: namespace1.GetName(),
+		},
+		Spec: pacv1alpha1.RepositorySpec{
+			URL: "https://anurl.com/owner/norun",
 		},
 	}
 
@@ -114,6 +127,8 @@ func TestList(t *testing.T) {
 		currentNamespace string
 		opts             *cli.PacCliOpts
 		selectors        string
+		quiet            bool
+		locale           string
 	}
 	tests := []struct {
 		name    string
@@ -175,9 +190,40 @@ func TestList(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Test no headers",
+			args: args{
+				opts:             &cli.PacCliOpts{NoHeaders: true},
+				currentNamespace: namespace1.GetName(),
+				namespaces:       []*corev1.Namespace{namespace1},
+				repositories:     []*pacv1alpha1.Repository{repoNamespace1, repoNoRun},
+			},
+		},
+		{
+			name: "Test quiet all namespaces",
+			args: args{
+				opts:             &cli.PacCliOpts{AllNameSpaces: true},
+				currentNamespace: "namespace",
+				namespaces:       []*corev1.Namespace{namespace1, namespace2},
+				repositories:     []*pacv1alpha1.Repository{repoNamespace1, repoNamespace2},
+				quiet:            true,
+			},
+		},
+		{
+			name: "Test french headers",
+			args: args{
+				opts:             &cli.PacCliOpts{AllNameSpaces: true},
+				currentNamespace: "namespace",
+				namespaces:       []*corev1.Namespace{namespace1, namespace2},
+				repositories:     []*pacv1alpha1.Repository{repoNamespace1, repoNamespace2},
+				locale:           "fr",
+			},
+		},
 	}
 	for _, tt := range tests {
+		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
 			tdata := testclient.Data{
 				Namespaces:   tt.args.namespaces,
 				Repositories: tt.args.repositories,
@@ -193,12 +239,14 @@ func TestList(t *testing.T) {
 				},
 				Info: info.Info{Kube: info.KubeOpts{Namespace: tt.args.currentNamespace}},
 			}
-			io, out := newIOStream()
+			io, out := tcli.NewIOStream()
+			io.SetQuiet(tt.args.quiet)
+			io.SetLocale(tt.args.locale)
 			if err := list(ctx, cs, tt.args.opts, io,
 				cw, tt.args.selectors); (err != nil) != tt.wantErr {
 				t.Errorf("describe() error = %v, wantErr %v", err, tt.wantErr)
 			} else {
-				golden.Assert(t, out.String(), strings.ReplaceAll(fmt.Sprintf("%s.golden", t.Name()), "/", "-"))
+				tcli.AssertGolden(t, out.String())
 			}
 		})
 	}
@@ -0,0 +1,415 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/google/go-github/v49/github"
+	apipac "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli/webhook"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/bootstrap"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/pipelineascode"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// controllerTimeout is how long to wait for the controller to answer.
+const controllerTimeout = 10 * time.Second
+
+// priority is how much a problem found by the doctor prevents the Repository
+// from working.
+type priority int
+
+const (
+	// priorityCritical problems prevent the events of the Repository from
+	// being handled.
+	priorityCritical priority = iota
+	// priorityWarning problems degrade how the events are handled.
+	priorityWarning
+)
+
+func (p priority) String() string {
+	if p == priorityCritical {
+		return "critical"
+	}
+	return "warning"
+}
+
+// diagnosis is a problem found by a check of the doctor, with the suggestion
+// to fix it.
+type diagnosis struct {
+	webhook.Finding
+	priority priority
+}
+
+type doctorOpts struct {
+	namespace    string
+	pacNamespace string
+
+	ioStreams *cli.IOStreams
+	// checkWebhook checks the webhook or the GitHub App installation of the
+	// Repository, webhook.Check when not replaced by the tests.
+	checkWebhook func(context.Context, *webhook.CheckOptions) ([]webhook.Finding, error)
+	// gitHubClient returns the client the .tekton directory is looked up
+	// with, a client authenticated with the token of the Repository or as the
+	// GitHub App when not replaced by the tests.
+	gitHubClient func(context.Context, *params.Run, *apipac.Repository, string) (*github.Client, error)
+}
+
+// doctor is the state shared by the checks of a Repository.
+type doctor struct {
+	run            *params.Run
+	opts           *doctorOpts
+	repo           *apipac.Repository
+	installationNS string
+	diagnoses      []diagnosis
+}
+
+func doctorCommand(run *params.Run, ioStreams *cli.IOStreams) *cobra.Command {
+	opts := &doctorOpts{}
+	cmd := &cobra.Command{
+		Use:   "doctor <name>",
+		Short: "Diagnose the configuration of a Repository and list the fixes to make",
+		Long: `Diagnose the configuration of a Repository and list the fixes to make.
+
+The doctor checks the Repository is valid, that the secret of its git provider
+has the token and the webhook secret with the needed scopes, that the webhook
+or the GitHub App is installed on the repository, that the controller is
+reachable and that the repository has a .tekton directory on its default
+branch. The problems found are listed by priority with how to fix them, the
+critical ones first.
+
+The .tekton directory is only checked on GitHub.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			opts.ioStreams = ioStreams
+			cliOpts := cli.NewCliOptions(cmd)
+			opts.ioStreams.SetColorEnabled(!cliOpts.NoColoring)
+			if err := run.Clients.NewClients(ctx, &run.Info); err != nil {
+				return err
+			}
+			if opts.namespace == "" {
+				opts.namespace = run.Info.Kube.Namespace
+			}
+			return diagnose(ctx, run, opts, args[0])
+		},
+		Annotations: map[string]string{
+			"commandType": "main",
+		},
+	}
+	cmd.Flags().StringVarP(&opts.namespace, "namespace", "n", "", "The namespace of the Repository")
+	cmd.Flags().StringVar(&opts.pacNamespace, "pac-namespace", "", "The namespace where pac is installed")
+	return cmd
+}
+
+func diagnose(ctx context.Context, run *params.Run, opts *doctorOpts, name string) error {
+	repo, err := run.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(opts.namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if opts.checkWebhook == nil {
+		opts.checkWebhook = webhook.Check
+	}
+	if opts.gitHubClient == nil {
+		opts.gitHubClient = repositoryGitHubClient
+	}
+
+	cs := opts.ioStreams.ColorScheme()
+	fmt.Fprintf(opts.ioStreams.Out, "%s\n", cs.Bold(fmt.Sprintf("Diagnosing the Repository %s/%s", repo.GetNamespace(), repo.GetName())))
+	d := &doctor{run: run, opts: opts, repo: repo}
+	checks := []struct {
+		name string
+		run  func(context.Context) string
+	}{
+		{"Repository", d.checkRepository},
+		{"Installation", d.checkInstallation},
+		{"Secret", d.checkSecret},
+		{"Git provider", d.checkGitProvider},
+		{"Controller", d.checkController},
+		{".tekton", d.checkTektonDir},
+	}
+	for _, check := range checks {
+		before := len(d.diagnoses)
+		summary := check.run(ctx)
+		icon := cs.SuccessIcon()
+		for _, diag := range d.diagnoses[before:] {
+			if diag.priority == priorityCritical {
+				icon = cs.FailureIcon()
+				break
+			}
+			icon = cs.WarningIcon()
+		}
+		fmt.Fprintf(opts.ioStreams.Out, "%s %s: %s\n", icon, cs.Bold(check.name), summary)
+	}
+
+	if len(d.diagnoses) == 0 {
+		fmt.Fprintf(opts.ioStreams.Out, "\n%s The Repository is correctly configured\n", cs.SuccessIcon())
+		return nil
+	}
+	sort.SliceStable(d.diagnoses, func(i, j int) bool { return d.diagnoses[i].priority < d.diagnoses[j].priority })
+	fmt.Fprintf(opts.ioStreams.Out, "\n%s\n", cs.Bold("Fixes, by priority:"))
+	critical := 0
+	for i, diag := range d.diagnoses {
+		label := cs.Yellow(diag.priority.String())
+		if diag.priority == priorityCritical {
+			critical++
+			label = cs.Red(diag.priority.String())
+		}
+		fmt.Fprintf(opts.ioStreams.Out, "%d. [%s] %s\n", i+1, label, diag.Problem)
+		fmt.Fprintf(opts.ioStreams.Out, "   %s %s\n", cs.InfoIcon(), diag.Suggestion)
+	}
+	switch {
+	case critical == 1:
+		return fmt.Errorf("the Repository %s has a critical problem to fix", name)
+	case critical > 1:
+		return fmt.Errorf("the Repository %s has %d critical problems to fix", name, critical)
+	}
+	return nil
+}
+
+// add records the diagnoses, the ones already found by another check are not
+// added twice.
+func (d *doctor) add(p priority, findings ...webhook.Finding) {
+	for _, f := range findings {
+		found := false
+		for _, diag := range d.diagnoses {
+			if diag.Problem == f.Problem {
+				found = true
+				break
+			}
+		}
+		if !found {
+			d.diagnoses = append(d.diagnoses, diagnosis{Finding: f, priority: p})
+		}
+	}
+}
+
+// checkRepository validates the Repository as the admission webhook of the
+// controller would, with a server side dry run of its update.
+func (d *doctor) checkRepository(ctx context.Context) string {
+	_, err := d.run.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(d.repo.GetNamespace()).Update(ctx, d.repo,
+		metav1.UpdateOptions{DryRun: []string{metav1.DryRunAll}})
+	if err != nil {
+		d.add(priorityCritical, webhook.Finding{
+			Problem:    fmt.Sprintf("the Repository is not valid: %v", err),
+			Suggestion: fmt.Sprintf("fix the Repository with: kubectl edit repository -n %s %s", d.repo.GetNamespace(), d.repo.GetName()),
+		})
+		return "invalid"
+	}
+	return "valid"
+}
+
+func (d *doctor) checkInstallation(ctx context.Context) string {
+	installed, installationNS, err := bootstrap.DetectPacInstallation(ctx, d.opts.pacNamespace, d.run)
+	if !installed || err != nil {
+		problem := "Pipelines as Code is not installed on the cluster"
+		if err != nil {
+			problem = fmt.Sprintf("cannot find the installation of Pipelines as Code: %v", err)
+		}
+		d.add(priorityCritical, webhook.Finding{
+			Problem:    problem,
+			Suggestion: "install Pipelines as Code with tkn pac bootstrap or give its namespace with --pac-namespace",
+		})
+		return "not found"
+	}
+	d.installationNS = installationNS
+	return fmt.Sprintf("installed in the %s namespace", installationNS)
+}
+
+// checkSecret checks the secret of the git provider has the token and the
+// webhook secret, and that the token has the scopes the controller reported
+// as needed.
+func (d *doctor) checkSecret(ctx context.Context) string {
+	repo := d.repo
+	if repo.Spec.GitProvider == nil || repo.Spec.GitProvider.Secret == nil {
+		if webhook.ProviderTypeFromRepository(repo) != "github" {
+			d.add(priorityCritical, webhook.Finding{
+				Problem:    fmt.Sprintf("repository %s has no git_provider secret and only GitHub supports the GitHub App", repo.GetName()),
+				Suggestion: fmt.Sprintf("configure a webhook with: tkn pac webhook add -n %s %s", repo.GetNamespace(), repo.GetName()),
+			})
+			return "missing"
+		}
+		return "none needed with the GitHub App"
+	}
+
+	updateToken := fmt.Sprintf("update it with: tkn pac webhook update-token -n %s %s", repo.GetNamespace(), repo.GetName())
+	secret := repo.Spec.GitProvider.Secret
+	if !d.checkSecretKey(ctx, secret.Name, secret.Key, pipelineascode.DefaultGitProviderSecretKey, "token", updateToken) {
+		return "incomplete"
+	}
+	if wh := repo.Spec.GitProvider.WebhookSecret; wh != nil {
+		if !d.checkSecretKey(ctx, wh.Name, wh.Key, pipelineascode.DefaultGitProviderWebhookSecretKey, "webhook secret",
+			fmt.Sprintf("recreate the webhook with: tkn pac webhook add -n %s %s", repo.GetNamespace(), repo.GetName())) {
+			return "incomplete"
+		}
+	} else if webhook.ProviderTypeFromRepository(repo) != "bitbucket-cloud" {
+		d.add(priorityWarning, webhook.Finding{
+			Problem:    "the Repository has no webhook_secret, the payloads of its webhook cannot be verified",
+			Suggestion: fmt.Sprintf("recreate the webhook with a secret with: tkn pac webhook add -n %s %s", repo.GetNamespace(), repo.GetName()),
+		})
+	}
+
+	if cond := repo.GetCondition(apipac.RepositoryConditionTokenScopes); cond != nil && cond.Status == corev1.ConditionFalse {
+		d.add(priorityWarning, webhook.Finding{
+			Problem:    cond.Message,
+			Suggestion: "create a token with the scopes listed in the documentation and " + updateToken,
+		})
+		return "token missing scopes"
+	}
+	return fmt.Sprintf("%s has the token", secret.Name)
+}
+
+// checkSecretKey checks the key of the secret in the namespace of the
+// Repository has a value, the default key is used when the key is empty.
+func (d *doctor) checkSecretKey(ctx context.Context, name, key, defaultKey, what, suggestion string) bool {
+	if key == "" {
+		key = defaultKey
+	}
+	secret, err := d.run.Clients.Kube.CoreV1().Secrets(d.repo.GetNamespace()).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		d.add(priorityCritical, webhook.Finding{
+			Problem:    fmt.Sprintf("cannot read the secret %s with the %s: %v", name, what, err),
+			Suggestion: "create the secret or " + suggestion,
+		})
+		return false
+	}
+	if len(secret.Data[key]) == 0 {
+		d.add(priorityCritical, webhook.Finding{
+			Problem:    fmt.Sprintf("the secret %s has no %s in its %s key", name, what, key),
+			Suggestion: suggestion,
+		})
+		return false
+	}
+	return true
+}
+
+func (d *doctor) checkGitProvider(ctx context.Context) string {
+	if d.installationNS == "" {
+		return "skipped, Pipelines as Code has not been found"
+	}
+	findings, err := d.opts.checkWebhook(ctx, &webhook.CheckOptions{
+		Run:          d.run,
+		Repository:   d.repo,
+		PACNamespace: d.installationNS,
+	})
+	if err != nil {
+		d.add(priorityWarning, webhook.Finding{
+			Problem:    fmt.Sprintf("cannot check the configuration of the repository on the git provider: %v", err),
+			Suggestion: "verify the token of the Repository can read the settings of the repository",
+		})
+		return "not checked"
+	}
+	if len(findings) > 0 {
+		d.add(priorityCritical, findings...)
+		return "misconfigured"
+	}
+	return "the events are sent to the controller"
+}
+
+// checkController checks the URL of the controller the git provider sends the
+// events to answers, any HTTP response is enough.
+func (d *doctor) checkController(ctx context.Context) string {
+	if d.installationNS == "" {
+		return "skipped, Pipelines as Code has not been found"
+	}
+	pacInfo, err := info.GetPACInfo(ctx, d.run, d.installationNS)
+	if err != nil || pacInfo.ControllerURL == "" {
+		return "skipped, the controller URL is not set in the pipelines-as-code-info configmap"
+	}
+	ctx, cancel := context.WithTimeout(ctx, controllerTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pacInfo.ControllerURL, nil)
+	if err == nil {
+		var resp *http.Response
+		if resp, err = d.run.Clients.HTTP.Do(req); err == nil {
+			resp.Body.Close()
+		}
+	}
+	if err != nil {
+		d.add(priorityCritical, webhook.Finding{
+			Problem:    fmt.Sprintf("the controller URL %s is not reachable: %v", pacInfo.ControllerURL, err),
+			Suggestion: "verify the Route or the Ingress of the controller exposes it on this URL and the git provider can reach it",
+		})
+		return "not reachable"
+	}
+	return fmt.Sprintf("%s is reachable", pacInfo.ControllerURL)
+}
+
+// checkTektonDir checks the repository has a .tekton directory on its default
+// branch, only on GitHub.
+func (d *doctor) checkTektonDir(ctx context.Context) string {
+	if webhook.ProviderTypeFromRepository(d.repo) != "github" {
+		return "skipped, only checked on GitHub"
+	}
+	owner, name, err := formatting.GetRepoOwnerSplitted(d.repo.Spec.URL)
+	if err != nil {
+		return fmt.Sprintf("skipped, %v", err)
+	}
+	client, err := d.opts.gitHubClient(ctx, d.run, d.repo, d.installationNS)
+	var ghRepo *github.Repository
+	if err == nil {
+		ghRepo, _, err = client.Repositories.Get(ctx, owner, name)
+	}
+	var found bool
+	if err == nil {
+		found, err = hasTektonDir(ctx, client, orgRepository{owner: owner, name: name, defaultBranch: ghRepo.GetDefaultBranch()})
+	}
+	if err != nil {
+		d.add(priorityWarning, webhook.Finding{
+			Problem:    fmt.Sprintf("cannot look for the %s directory of %s/%s: %v", tektonDir, owner, name, err),
+			Suggestion: "verify the token of the Repository or the GitHub App can read the repository",
+		})
+		return "not checked"
+	}
+	if !found {
+		d.add(priorityCritical, webhook.Finding{
+			Problem: fmt.Sprintf("%s/%s has no %s directory on its default branch %s", owner, name, tektonDir, ghRepo.GetDefaultBranch()),
+			Suggestion: fmt.Sprintf("generate a PipelineRun with tkn pac generate and push it to the %s directory of the %s branch",
+				tektonDir, ghRepo.GetDefaultBranch()),
+		})
+		return "missing"
+	}
+	return fmt.Sprintf("found on the %s branch", ghRepo.GetDefaultBranch())
+}
+
+// repositoryGitHubClient returns a GitHub client authenticated with the token of
+// the Repository, or as the installation of the GitHub App on the repository
+// when it has no git_provider secret.
+func repositoryGitHubClient(ctx context.Context, run *params.Run, repo *apipac.Repository, installationNS string) (*github.Client, error) {
+	apiURL := ""
+	if repo.Spec.GitProvider != nil {
+		apiURL = repo.Spec.GitProvider.URL
+	}
+	if repo.Spec.GitProvider != nil && repo.Spec.GitProvider.Secret != nil {
+		key := repo.Spec.GitProvider.Secret.Key
+		if key == "" {
+			key = pipelineascode.DefaultGitProviderSecretKey
+		}
+		secret, err := run.Clients.Kube.CoreV1().Secrets(repo.GetNamespace()).Get(ctx, repo.Spec.GitProvider.Secret.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return newTokenClient(ctx, apiURL, string(secret.Data[key])), nil
+	}
+	owner, name, err := formatting.GetRepoOwnerSplitted(repo.Spec.URL)
+	if err != nil {
+		return nil, err
+	}
+	return newAppClient(ctx, run, installationNS, apiURL,
+		func(ctx context.Context, appClient *github.Client) (*github.Installation, error) {
+			installation, _, err := appClient.Apps.FindRepositoryInstallation(ctx, owner, name)
+			if err != nil {
+				return nil, fmt.Errorf("cannot find the installation of the GitHub App on %s/%s: %w", owner, name, err)
+			}
+			return installation, nil
+		})
+}
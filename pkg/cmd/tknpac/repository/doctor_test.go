@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v49/github"
+	apipac "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli/webhook"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	ghtesthelper "github.com/openshift-pipelines/pipelines-as-code/pkg/test/github"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestDiagnose(t *testing.T) {
+	ghClient, mux, _, teardown := ghtesthelper.SetupGH()
+	defer teardown()
+	mux.HandleFunc("/repos/owner/repo", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"default_branch": "main"}`)
+	})
+	mux.HandleFunc("/repos/owner/repo/contents/.tekton", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `[{"name": "pr.yaml", "type": "file"}]`)
+	})
+	mux.HandleFunc("/repos/owner/empty", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"default_branch": "trunk"}`)
+	})
+	mux.HandleFunc("/repos/owner/empty/contents/.tekton", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	controller := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}))
+	defer controller.Close()
+	unreachable := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	unreachable.Close()
+
+	gitProvider := &apipac.GitProvider{
+		Secret:        &apipac.Secret{Name: "provider"},
+		WebhookSecret: &apipac.Secret{Name: "provider"},
+	}
+	tests := []struct {
+		name          string
+		url           string
+		gitProvider   *apipac.GitProvider
+		secretData    map[string][]byte
+		conditions    []apis.Condition
+		controllerURL string
+		findings      []webhook.Finding
+		wantOutput    []string
+		wantErr       string
+	}{
+		{
+			name:          "healthy",
+			url:           "https://github.com/owner/repo",
+			gitProvider:   gitProvider,
+			secretData:    map[string][]byte{"provider.token": []byte("token"), "webhook.secret": []byte("secret")},
+			controllerURL: controller.URL,
+			wantOutput: []string{
+				"✓ Repository: valid\n",
+				"✓ Installation: installed in the pac namespace\n",
+				"✓ Secret: provider has the token\n",
+				"✓ Git provider: the events are sent to the controller\n",
+				fmt.Sprintf("✓ Controller: %s is reachable\n", controller.URL),
+				"✓ .tekton: found on the main branch\n",
+				"✓ The Repository is correctly configured\n",
+			},
+		},
+		{
+			name:          "warnings only",
+			url:           "https://github.com/owner/repo",
+			gitProvider:   &apipac.GitProvider{Secret: &apipac.Secret{Name: "provider", Key: "token"}},
+			secretData:    map[string][]byte{"token": []byte("token")},
+			controllerURL: controller.URL,
+			conditions: []apis.Condition{{
+				Type:    apipac.RepositoryConditionTokenScopes,
+				Status:  corev1.ConditionFalse,
+				Message: "the token in secret provider is missing the scopes: repo",
+			}},
+			wantOutput: []string{
+				"! Secret: token missing scopes\n",
+				"1. [warning] the Repository has no webhook_secret, the payloads of its webhook cannot be verified\n",
+				"2. [warning] the token in secret provider is missing the scopes: repo\n",
+			},
+		},
+		{
+			name:          "broken",
+			url:           "https://github.com/owner/empty",
+			gitProvider:   gitProvider,
+			secretData:    map[string][]byte{"webhook.secret": []byte("secret")},
+			controllerURL: unreachable.URL,
+			findings:      []webhook.Finding{{Problem: "no webhook on owner/empty points to the controller", Suggestion: "add it"}},
+			wantOutput: []string{
+				"X Secret: incomplete\n",
+				"X Git provider: misconfigured\n",
+				"X Controller: not reachable\n",
+				"X .tekton: missing\n",
+				"1. [critical] the secret provider has no token in its provider.token key\n",
+				"2. [critical] no webhook on owner/empty points to the controller\n   ℹ add it\n",
+				fmt.Sprintf("3. [critical] the controller URL %s is not reachable", unreachable.URL),
+				"4. [critical] owner/empty has no .tekton directory on its default branch trunk\n",
+			},
+			wantErr: "the Repository repo has 4 critical problems to fix",
+		},
+		{
+			name:       "no secret outside of GitHub",
+			url:        "https://gitlab.com/owner/repo",
+			wantOutput: []string{"X Secret: missing\n", "✓ Controller: skipped, the controller URL is not set", "✓ .tekton: skipped, only checked on GitHub\n"},
+			wantErr:    "the Repository repo has a critical problem to fix",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+				Repositories: []*apipac.Repository{{
+					ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"},
+					Spec:       apipac.RepositorySpec{URL: tt.url, GitProvider: tt.gitProvider},
+					Conditions: tt.conditions,
+				}},
+				Secret: []*corev1.Secret{{
+					ObjectMeta: metav1.ObjectMeta{Name: "provider", Namespace: "ns"},
+					Data:       tt.secretData,
+				}},
+				ConfigMap: []*corev1.ConfigMap{{
+					ObjectMeta: metav1.ObjectMeta{Name: "pipelines-as-code-info", Namespace: "pac"},
+					Data:       map[string]string{"controller-url": tt.controllerURL},
+				}},
+			})
+			run := &params.Run{Clients: clients.Clients{PipelineAsCode: stdata.PipelineAsCode, Kube: stdata.Kube}}
+			io, _, out, _ := cli.IOTest()
+			opts := &doctorOpts{
+				namespace:    "ns",
+				pacNamespace: "pac",
+				ioStreams:    io,
+				checkWebhook: func(_ context.Context, opts *webhook.CheckOptions) ([]webhook.Finding, error) {
+					assert.Equal(t, opts.PACNamespace, "pac")
+					return tt.findings, nil
+				},
+				gitHubClient: func(context.Context, *params.Run, *apipac.Repository, string) (*github.Client, error) {
+					return ghClient, nil
+				},
+			}
+
+			err := diagnose(ctx, run, opts, "repo")
+			if tt.wantErr != "" {
+				assert.Error(t, err, tt.wantErr)
+			} else {
+				assert.NilError(t, err)
+			}
+			for _, want := range tt.wantOutput {
+				assert.Assert(t, strings.Contains(out.String(), want), "%q not in:\n%s", want, out.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,328 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bradleyfalzon/ghinstallation/v2"
+	"github.com/google/go-github/v49/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode"
+	apipac "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/bootstrap"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/pipelineascode"
+	"github.com/spf13/cobra"
+	"golang.org/x/oauth2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	tektonDir = ".tekton"
+	// reposPerPage is the maximum number of repositories the API returns in a
+	// page.
+	reposPerPage = 100
+)
+
+type importOpts struct {
+	org          string
+	token        string
+	apiURL       string
+	namespace    string
+	pacNamespace string
+	secretName   string
+	dryRun       bool
+
+	ioStreams *cli.IOStreams
+}
+
+// orgRepository is a repository of the organization as needed to import it.
+type orgRepository struct {
+	fullName      string
+	owner         string
+	name          string
+	url           string
+	defaultBranch string
+}
+
+func importCommand(run *params.Run, ioStreams *cli.IOStreams) *cobra.Command {
+	opts := &importOpts{}
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Create Repositories for the repositories of a GitHub organization having a .tekton directory",
+		Long: `Create Repositories for the repositories of a GitHub organization having a .tekton directory.
+
+The repositories are listed with the GitHub App of Pipelines as Code when it is
+installed on the organization, or with a personal access token given with the
+--token flag or the PAC_PROVIDER_TOKEN environment variable.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			opts.ioStreams = ioStreams
+			cliOpts := cli.NewCliOptions(cmd)
+			opts.ioStreams.SetColorEnabled(!cliOpts.NoColoring)
+			if opts.org == "" {
+				return fmt.Errorf("an organization needs to be specified with --from-org")
+			}
+			if opts.token == "" {
+				opts.token = os.Getenv("PAC_PROVIDER_TOKEN")
+			}
+			if err := run.Clients.NewClients(ctx, &run.Info); err != nil {
+				return err
+			}
+			if opts.namespace == "" {
+				opts.namespace = run.Info.Kube.Namespace
+			}
+
+			var (
+				client *github.Client
+				err    error
+			)
+			if opts.token != "" {
+				client = newTokenClient(ctx, opts.apiURL, opts.token)
+			} else {
+				client, err = newAppClient(ctx, run, opts.pacNamespace, opts.apiURL,
+					func(ctx context.Context, appClient *github.Client) (*github.Installation, error) {
+						installation, _, err := appClient.Apps.FindOrganizationInstallation(ctx, opts.org)
+						if err != nil {
+							return nil, fmt.Errorf("cannot find the installation of the GitHub App on the organization %s: %w", opts.org, err)
+						}
+						return installation, nil
+					})
+				if err != nil {
+					return err
+				}
+			}
+			return importRepositories(ctx, run, client, opts)
+		},
+		Annotations: map[string]string{
+			"commandType": "main",
+		},
+	}
+	cmd.Flags().StringVar(&opts.org, "from-org", "", "The GitHub organization to import the repositories from")
+	cmd.Flags().StringVar(&opts.token, "token", "",
+		"The personal access token to list the repositories with, the GitHub App of Pipelines as Code is used when not set")
+	cmd.Flags().StringVar(&opts.apiURL, "api-url", "", "The API URL of the GitHub Enterprise instance")
+	cmd.Flags().StringVarP(&opts.namespace, "namespace", "n", "",
+		"The target namespace where the Repositories are created and the runs will be created")
+	cmd.Flags().StringVar(&opts.pacNamespace, "pac-namespace", "", "The namespace where pac is installed")
+	cmd.Flags().StringVar(&opts.secretName, "git-provider-secret", "",
+		fmt.Sprintf("The Secret of the target namespace with the %s and %s keys the Repositories use for their webhooks",
+			pipelineascode.DefaultGitProviderSecretKey, pipelineascode.DefaultGitProviderWebhookSecretKey))
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Only print the Repositories that would be created")
+	return cmd
+}
+
+func enterpriseURL(apiURL string) string {
+	if !strings.HasPrefix(apiURL, "https://") && !strings.HasPrefix(apiURL, "http://") {
+		apiURL = "https://" + apiURL
+	}
+	return apiURL
+}
+
+// newTokenClient returns a GitHub client authenticated with a personal access
+// token.
+func newTokenClient(ctx context.Context, apiURL, token string) *github.Client {
+	tc := oauth2.NewClient(ctx, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}))
+	if apiURL == "" {
+		return github.NewClient(tc)
+	}
+	client, _ := github.NewEnterpriseClient(enterpriseURL(apiURL), enterpriseURL(apiURL), tc)
+	return client
+}
+
+// newAppClient returns a GitHub client authenticated as the installation of
+// the Pipelines as Code GitHub App found by findInstallation, ie: the one on
+// an organization.
+func newAppClient(ctx context.Context, run *params.Run, pacNamespace, apiURL string,
+	findInstallation func(context.Context, *github.Client) (*github.Installation, error),
+) (*github.Client, error) {
+	installed, installationNS, err := bootstrap.DetectPacInstallation(ctx, pacNamespace, run)
+	if !installed {
+		return nil, fmt.Errorf("pipelines as code not installed")
+	}
+	if err != nil {
+		return nil, err
+	}
+	secret, err := run.Clients.Kube.CoreV1().Secrets(installationNS).Get(ctx, pipelineascode.DefaultPipelinesAscodeSecretName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("cannot get the GitHub App secret %s in %s, you can use a personal access token with --token instead: %w",
+			pipelineascode.DefaultPipelinesAscodeSecretName, installationNS, err)
+	}
+	appID, err := strconv.ParseInt(strings.TrimSpace(string(secret.Data["github-application-id"])), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the github application_id number from secret: %w", err)
+	}
+	atr, err := ghinstallation.NewAppsTransport(http.DefaultTransport, appID, secret.Data["github-private-key"])
+	if err != nil {
+		return nil, err
+	}
+
+	newClient := func(tr http.RoundTripper) *github.Client {
+		if apiURL == "" {
+			return github.NewClient(&http.Client{Transport: tr})
+		}
+		client, _ := github.NewEnterpriseClient(enterpriseURL(apiURL), enterpriseURL(apiURL), &http.Client{Transport: tr})
+		return client
+	}
+	appClient := newClient(atr)
+	atr.BaseURL = strings.TrimSuffix(appClient.BaseURL.String(), "/")
+	installation, err := findInstallation(ctx, appClient)
+	if err != nil {
+		return nil, err
+	}
+	itr := ghinstallation.NewFromAppsTransport(atr, installation.GetID())
+	itr.BaseURL = atr.BaseURL
+	return newClient(itr), nil
+}
+
+// listOrgRepositories lists the repositories of the organization the client
+// has access to, the ones of the installation for a GitHub App and the ones
+// of the organization for a token. Archived repositories are skipped.
+func listOrgRepositories(ctx context.Context, client *github.Client, org string, asApp bool) ([]orgRepository, error) {
+	repos := []*github.Repository{}
+	for page := 1; page != 0; {
+		var (
+			list []*github.Repository
+			resp *github.Response
+			err  error
+		)
+		if asApp {
+			var installationRepos *github.ListRepositories
+			installationRepos, resp, err = client.Apps.ListRepos(ctx, &github.ListOptions{Page: page, PerPage: reposPerPage})
+			if installationRepos != nil {
+				list = installationRepos.Repositories
+			}
+		} else {
+			list, resp, err = client.Repositories.ListByOrg(ctx, org, &github.RepositoryListByOrgOptions{
+				ListOptions: github.ListOptions{Page: page, PerPage: reposPerPage},
+			})
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot list the repositories of %s: %w", org, err)
+		}
+		repos = append(repos, list...)
+		page = resp.NextPage
+	}
+
+	ret := []orgRepository{}
+	for _, repo := range repos {
+		if repo.GetArchived() || !strings.EqualFold(repo.GetOwner().GetLogin(), org) {
+			continue
+		}
+		ret = append(ret, orgRepository{
+			fullName:      repo.GetFullName(),
+			owner:         repo.GetOwner().GetLogin(),
+			name:          repo.GetName(),
+			url:           repo.GetHTMLURL(),
+			defaultBranch: repo.GetDefaultBranch(),
+		})
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].fullName < ret[j].fullName })
+	return ret, nil
+}
+
+// hasTektonDir checks if the repository has a .tekton directory on its default
+// branch.
+func hasTektonDir(ctx context.Context, client *github.Client, repo orgRepository) (bool, error) {
+	_, dir, resp, err := client.Repositories.GetContents(ctx, repo.owner, repo.name, tektonDir,
+		&github.RepositoryContentGetOptions{Ref: repo.defaultBranch})
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return dir != nil, nil
+}
+
+// repositoryName returns the name of the Repository for a repository of the
+// organization.
+func repositoryName(repo orgRepository) string {
+	return strings.ToLower(strings.ReplaceAll(repo.fullName, "/", "-"))
+}
+
+func importRepositories(ctx context.Context, run *params.Run, client *github.Client, opts *importOpts) error {
+	cs := opts.ioStreams.ColorScheme()
+	repos, err := listOrgRepositories(ctx, client, opts.org, opts.token == "")
+	if err != nil {
+		return err
+	}
+
+	existing := map[string]string{}
+	if !opts.dryRun {
+		list, err := run.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(opts.namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+		for _, repo := range list.Items {
+			existing[strings.TrimSuffix(strings.ToLower(repo.Spec.URL), "/")] = repo.GetName()
+		}
+	}
+
+	imported := 0
+	for _, repo := range repos {
+		found, err := hasTektonDir(ctx, client, repo)
+		if err != nil {
+			fmt.Fprintf(opts.ioStreams.ErrOut, "%s cannot check the %s directory of %s: %s\n", cs.WarningIcon(), tektonDir, repo.fullName, err.Error())
+			continue
+		}
+		if !found {
+			continue
+		}
+		if name, ok := existing[strings.ToLower(repo.url)]; ok {
+			fmt.Fprintf(opts.ioStreams.Out, "%s %s already has the Repository %s in the %s namespace\n", cs.InfoIcon(), repo.fullName, name, opts.namespace)
+			continue
+		}
+
+		repository := &apipac.Repository{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: apipac.SchemeGroupVersion.String(),
+				Kind:       pipelinesascode.RepositoryKind,
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      repositoryName(repo),
+				Namespace: opts.namespace,
+			},
+			Spec: apipac.RepositorySpec{
+				URL: repo.url,
+			},
+		}
+		if opts.secretName != "" {
+			repository.Spec.GitProvider = &apipac.GitProvider{
+				Secret:        &apipac.Secret{Name: opts.secretName, Key: pipelineascode.DefaultGitProviderSecretKey},
+				WebhookSecret: &apipac.Secret{Name: opts.secretName, Key: pipelineascode.DefaultGitProviderWebhookSecretKey},
+			}
+			if opts.apiURL != "" {
+				repository.Spec.GitProvider.URL = opts.apiURL
+			}
+		}
+
+		if opts.dryRun {
+			b, err := yaml.Marshal(repository)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(opts.ioStreams.Out, "---\n%s", string(b))
+			imported++
+			continue
+		}
+		if _, err := run.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(opts.namespace).Create(ctx, repository, metav1.CreateOptions{}); err != nil {
+			fmt.Fprintf(opts.ioStreams.ErrOut, "%s cannot create the Repository for %s: %s\n", cs.WarningIcon(), repo.fullName, err.Error())
+			continue
+		}
+		fmt.Fprintf(opts.ioStreams.Out, "%s Repository %s has been created in %s namespace\n",
+			cs.SuccessIconWithColor(cs.Green), repository.GetName(), opts.namespace)
+		imported++
+	}
+
+	if !opts.dryRun {
+		fmt.Fprintf(opts.ioStreams.Out, "%d repositories of %s with a %s directory have been imported\n", imported, opts.org, tektonDir)
+	}
+	return nil
+}
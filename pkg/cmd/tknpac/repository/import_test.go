@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	apipac "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	ghtesthelper "github.com/openshift-pipelines/pipelines-as-code/pkg/test/github"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestImportRepositories(t *testing.T) {
+	ns := "pipelines"
+	tests := []struct {
+		name          string
+		dryRun        bool
+		secretName    string
+		existing      []*apipac.Repository
+		wantCreated   map[string]string
+		wantOutput    string
+		wantNotOutput string
+	}{
+		{
+			name: "import the repositories with a tekton directory",
+			wantCreated: map[string]string{
+				"org-with-tekton":  "https://github.com/org/with-tekton",
+				"org-other-tekton": "https://github.com/org/Other-Tekton",
+			},
+			wantOutput: "2 repositories of org with a .tekton directory have been imported",
+		},
+		{
+			name: "skip the repositories already having a Repository",
+			existing: []*apipac.Repository{{
+				ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: ns},
+				Spec:       apipac.RepositorySpec{URL: "https://github.com/org/with-tekton/"},
+			}},
+			wantCreated: map[string]string{
+				"org-other-tekton": "https://github.com/org/Other-Tekton",
+			},
+			wantOutput: "org/with-tekton already has the Repository existing in the pipelines namespace",
+		},
+		{
+			name:       "reference the git provider secret",
+			secretName: "github-webhook",
+			wantCreated: map[string]string{
+				"org-with-tekton":  "https://github.com/org/with-tekton",
+				"org-other-tekton": "https://github.com/org/Other-Tekton",
+			},
+		},
+		{
+			name:          "dry run",
+			dryRun:        true,
+			wantOutput:    "name: org-other-tekton\n  namespace: pipelines\nspec:\n  url: https://github.com/org/Other-Tekton\n",
+			wantNotOutput: "org-without-tekton",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			client, mux, _, teardown := ghtesthelper.SetupGH()
+			defer teardown()
+
+			mux.HandleFunc("/orgs/org/repos", func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("page") == "2" {
+					fmt.Fprint(w, `[{"full_name": "org/Other-Tekton", "name": "Other-Tekton", "owner": {"login": "org"},
+					"html_url": "https://github.com/org/Other-Tekton", "default_branch": "trunk"}]`)
+					return
+				}
+				w.Header().Set("Link", `<https://api.github.com/orgs/org/repos?page=2>; rel="next"`)
+				fmt.Fprint(w, `[
+					{"full_name": "org/with-tekton", "name": "with-tekton", "owner": {"login": "org"},
+					 "html_url": "https://github.com/org/with-tekton", "default_branch": "main"},
+					{"full_name": "org/without-tekton", "name": "without-tekton", "owner": {"login": "org"},
+					 "html_url": "https://github.com/org/without-tekton", "default_branch": "main"},
+					{"full_name": "org/archived", "name": "archived", "owner": {"login": "org"}, "archived": true,
+					 "html_url": "https://github.com/org/archived", "default_branch": "main"}
+				]`)
+			})
+			mux.HandleFunc("/repos/org/with-tekton/contents/.tekton", func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, r.URL.Query().Get("ref"), "main")
+				fmt.Fprint(w, `[{"name": "pull-request.yaml", "path": ".tekton/pull-request.yaml", "type": "file"}]`)
+			})
+			mux.HandleFunc("/repos/org/Other-Tekton/contents/.tekton", func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, r.URL.Query().Get("ref"), "trunk")
+				fmt.Fprint(w, `[{"name": "push.yaml", "path": ".tekton/push.yaml", "type": "file"}]`)
+			})
+			mux.HandleFunc("/repos/org/without-tekton/contents/.tekton", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			})
+
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+				Namespaces:   []*corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: ns}}},
+				Repositories: tt.existing,
+			})
+			run := &params.Run{Clients: clients.Clients{PipelineAsCode: stdata.PipelineAsCode, Kube: stdata.Kube}}
+			io, _, out, _ := cli.IOTest()
+			opts := &importOpts{
+				org:        "org",
+				token:      "token",
+				namespace:  ns,
+				secretName: tt.secretName,
+				dryRun:     tt.dryRun,
+				ioStreams:  io,
+			}
+			assert.NilError(t, importRepositories(ctx, run, client, opts))
+			assert.Assert(t, !tt.dryRun || len(tt.wantCreated) == 0)
+
+			for name, url := range tt.wantCreated {
+				repo, err := stdata.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(ns).Get(ctx, name, metav1.GetOptions{})
+				assert.NilError(t, err)
+				assert.Equal(t, repo.Spec.URL, url)
+				if tt.secretName != "" {
+					assert.Equal(t, repo.Spec.GitProvider.Secret.Name, tt.secretName)
+					assert.Equal(t, repo.Spec.GitProvider.Secret.Key, "provider.token")
+					assert.Equal(t, repo.Spec.GitProvider.WebhookSecret.Key, "webhook.secret")
+				} else {
+					assert.Assert(t, repo.Spec.GitProvider == nil)
+				}
+			}
+			repos, err := stdata.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(ns).List(ctx, metav1.ListOptions{})
+			assert.NilError(t, err)
+			assert.Equal(t, len(repos.Items), len(tt.wantCreated)+len(tt.existing))
+
+			assert.Assert(t, tt.wantOutput == "" || strings.Contains(out.String(), tt.wantOutput), out.String())
+			assert.Assert(t, tt.wantNotOutput == "" || !strings.Contains(out.String(), tt.wantNotOutput), out.String())
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli/webhook"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	defaultPingTimeout = 30 * time.Second
+	// pingInterval is how often the deliveries of the webhook are looked at
+	// while waiting for the ping.
+	pingInterval = 2 * time.Second
+)
+
+// pingWebhook pings the webhook of the repository, it is replaced in the
+// tests.
+var pingWebhook = webhook.Ping
+
+type pingOpts struct {
+	namespace    string
+	pacNamespace string
+	timeout      time.Duration
+
+	ioStreams *cli.IOStreams
+}
+
+func pingCommand(run *params.Run, ioStreams *cli.IOStreams) *cobra.Command {
+	opts := &pingOpts{}
+	cmd := &cobra.Command{
+		Use:   "ping <name>",
+		Short: "Ping the webhook of a Repository and verify the controller validated it",
+		Long: `Ping the webhook of a Repository and verify the controller validated it.
+
+GitHub is asked to send a ping to the webhook of the repository, the response
+of the controller is then read from the deliveries of the webhook to verify the
+ping reached the controller, matched the Repository and was signed with its
+webhook secret. When it was not, the command tells where the chain broke.
+
+Only the GitHub webhooks are supported.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			opts.ioStreams = ioStreams
+			cliOpts := cli.NewCliOptions(cmd)
+			opts.ioStreams.SetColorEnabled(!cliOpts.NoColoring)
+			if err := run.Clients.NewClients(ctx, &run.Info); err != nil {
+				return err
+			}
+			if opts.namespace == "" {
+				opts.namespace = run.Info.Kube.Namespace
+			}
+			return ping(ctx, run, opts, args[0])
+		},
+		Annotations: map[string]string{
+			"commandType": "main",
+		},
+	}
+	cmd.Flags().StringVarP(&opts.namespace, "namespace", "n", "", "The namespace of the Repository")
+	cmd.Flags().StringVar(&opts.pacNamespace, "pac-namespace", "", "The namespace where pac is installed")
+	cmd.Flags().DurationVar(&opts.timeout, "timeout", defaultPingTimeout, "How long to wait for GitHub to deliver the ping")
+	return cmd
+}
+
+func ping(ctx context.Context, run *params.Run, opts *pingOpts, name string) error {
+	repo, err := run.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(opts.namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	message, findings, err := pingWebhook(ctx, &webhook.PingOptions{
+		CheckOptions: webhook.CheckOptions{
+			Run:          run,
+			Repository:   repo,
+			PACNamespace: opts.pacNamespace,
+		},
+		Timeout:  opts.timeout,
+		Interval: pingInterval,
+	})
+	if err != nil {
+		return err
+	}
+
+	cs := opts.ioStreams.ColorScheme()
+	if len(findings) == 0 {
+		fmt.Fprintf(opts.ioStreams.Out, "%s The controller validated the ping: %s\n", cs.SuccessIcon(), message)
+		return nil
+	}
+	for _, f := range findings {
+		fmt.Fprintf(opts.ioStreams.Out, "%s %s\n", cs.FailureIcon(), f.Problem)
+		fmt.Fprintf(opts.ioStreams.Out, "  %s %s\n", cs.InfoIcon(), f.Suggestion)
+	}
+	return fmt.Errorf("the ping of the webhook of the Repository %s has not been validated", name)
+}
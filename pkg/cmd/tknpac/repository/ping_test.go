@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	apipac "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli/webhook"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestPing(t *testing.T) {
+	tests := []struct {
+		name       string
+		message    string
+		findings   []webhook.Finding
+		wantErr    string
+		wantOutput string
+	}{
+		{
+			name:       "validated",
+			message:    "pong from Repository ns/repo",
+			wantOutput: "✓ The controller validated the ping: pong from Repository ns/repo\n",
+		},
+		{
+			name:       "broken",
+			findings:   []webhook.Finding{{Problem: "the controller refused the ping", Suggestion: "fix the secret"}},
+			wantErr:    "the ping of the webhook of the Repository repo has not been validated",
+			wantOutput: "X the controller refused the ping\n  ℹ fix the secret\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+				Repositories: []*apipac.Repository{{
+					ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"},
+					Spec:       apipac.RepositorySpec{URL: "https://github.com/owner/repo"},
+				}},
+			})
+			run := &params.Run{Clients: clients.Clients{PipelineAsCode: stdata.PipelineAsCode, Kube: stdata.Kube}}
+			pingWebhook = func(_ context.Context, opts *webhook.PingOptions) (string, []webhook.Finding, error) {
+				assert.Equal(t, opts.Repository.GetName(), "repo")
+				assert.Equal(t, opts.PACNamespace, "pac")
+				return tt.message, tt.findings, nil
+			}
+			defer func() { pingWebhook = webhook.Ping }()
+
+			io, _, out, _ := cli.IOTest()
+			err := ping(ctx, run, &pingOpts{namespace: "ns", pacNamespace: "pac", ioStreams: io}, "repo")
+			if tt.wantErr != "" {
+				assert.Error(t, err, tt.wantErr)
+			} else {
+				assert.NilError(t, err)
+			}
+			assert.Equal(t, out.String(), tt.wantOutput)
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/describe"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/spf13/cobra"
+)
+
+func Root(clients *params.Run, ioStreams *cli.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "repository",
+		Aliases:      []string{"repo"},
+		Short:        "Manage Repositories in bulk",
+		Long:         `Manage Pipelines as Code Repositories in bulk`,
+		SilenceUsage: true,
+		Annotations: map[string]string{
+			"commandType": "main",
+		},
+	}
+
+	cmd.AddCommand(importCommand(clients, ioStreams))
+	cmd.AddCommand(describe.Root(clients, ioStreams))
+	cmd.AddCommand(pingCommand(clients, ioStreams))
+	cmd.AddCommand(doctorCommand(clients, ioStreams))
+	return cmd
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli/prompt"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/secrets"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/yaml"
@@ -93,7 +94,7 @@ func makeGitAuthSecret(ctx context.Context, cs *params.Run, filenames []string,
 			},
 			SHA: params["revision"],
 		}
-		basicAuthsecretName = secrets.GenerateBasicAuthSecretName()
+		basicAuthsecretName = secrets.GenerateBasicAuthSecretName(settings.SecretAutoCreateNamePrefixDefaultValue)
 		basicAuthSecret, err := secrets.MakeBasicAuthSecret(runevent, basicAuthsecretName)
 		if err != nil {
 			return "", "", err
@@ -21,20 +21,25 @@ import (
 	"github.com/spf13/cobra"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/yaml"
 )
 
 var (
-	filenames      []string
-	parameters     []string
-	skipInlining   []string
-	noGenerateName bool
-	remoteTask     bool
-	noSecret       bool
-	providerToken  string
-	output         string
+	filenames       []string
+	parameters      []string
+	skipInlining    []string
+	noGenerateName  bool
+	remoteTask      bool
+	noSecret        bool
+	useClusterTasks bool
+	providerToken   string
+	output          string
 )
 
+const clusterTaskKind tektonv1.TaskKind = "ClusterTask"
+
 var longhelp = fmt.Sprintf(`
 
 resolve - resolve a PipelineRun and all its referenced Pipeline/Tasks embedded.
@@ -59,12 +64,18 @@ or a directory where it will get all the files ending by .yaml  :
 
 %s pac resolve -f .tekton/
 
+With --use-cluster-tasks the tasks already present on the cluster, as a Task
+of the namespace or a ClusterTask, are referenced instead of being inlined for
+a smaller PipelineRun:
+
+%s pac resolve -f .tekton/ --use-cluster-tasks
+
 If it detect a {{ git_auth_secret }} in the template it will ask you if you want
 to provide a token. You can set the environment variable PAC_PROVIDER_TOKEN to
 not have to ask about it.
 
 *It does not support task from local directory referenced in annotations at the
- moment*.`, settings.TknBinaryName, settings.TknBinaryName, settings.TknBinaryName)
+ moment*.`, settings.TknBinaryName, settings.TknBinaryName, settings.TknBinaryName, settings.TknBinaryName)
 
 func Command(run *params.Run, streams *cli.IOStreams) *cobra.Command {
 	cmd := &cobra.Command{
@@ -92,6 +103,9 @@ func Command(run *params.Run, streams *cli.IOStreams) *cobra.Command {
 				if !noConfigErr {
 					return errc
 				}
+				if useClusterTasks {
+					return fmt.Errorf("--use-cluster-tasks needs access to a cluster: %w", errc)
+				}
 			} else {
 				// it's OK  if pac is not installed, ignore the error
 				_ = run.UpdatePACInfo(ctx)
@@ -117,6 +131,10 @@ func Command(run *params.Run, streams *cli.IOStreams) *cobra.Command {
 				mapped["revision"] = gitinfo.SHA
 			}
 
+			if _, ok := mapped["default_branch"]; !ok && gitinfo.DefaultBranch != "" {
+				mapped["default_branch"] = gitinfo.DefaultBranch
+			}
+
 			if _, ok := mapped["repo_owner"]; !ok && gitinfo.URL != "" {
 				repoOwner, err := formatting.GetRepoOwnerFromURL(gitinfo.URL)
 				if err != nil {
@@ -164,6 +182,9 @@ func Command(run *params.Run, streams *cli.IOStreams) *cobra.Command {
 	cmd.Flags().BoolVar(&remoteTask, "remoteTask", true,
 		"set this to false to avoid fetching and embed remote tasks")
 
+	cmd.Flags().BoolVar(&useClusterTasks, "use-cluster-tasks", false,
+		"reference the tasks present on the cluster instead of inlining them")
+
 	cmd.Flags().StringVarP(&providerToken, "providerToken", "t", "", "use this token to generate the git-auth secret,\n you can set the environment PAC_PROVIDER_TOKEN to have this set automatically")
 	err := run.Info.Pac.AddFlags(cmd)
 	if err != nil {
@@ -191,6 +212,12 @@ func resolveFilenames(ctx context.Context, cs *params.Run, filenames []string, p
 		SkipInlining:  skipInlining,
 		ProviderToken: providerToken,
 	}
+	if useClusterTasks {
+		var err error
+		if ropt.ClusterTasks, err = clusterTasks(ctx, cs, cs.Info.Kube.Namespace); err != nil {
+			return "", err
+		}
+	}
 	allTemplates := enumerateFiles(filenames)
 	if !noSecret {
 		outSecret, secretName, err := makeGitAuthSecret(ctx, cs, filenames, ropt.ProviderToken, params)
@@ -229,12 +256,37 @@ func resolveFilenames(ctx context.Context, cs *params.Run, filenames []string, p
 	return ret, nil
 }
 
+// clusterTasks returns the kind of the tasks present on the cluster by name,
+// a Task of the namespace is referenced rather than a ClusterTask of the same
+// name. The ClusterTasks are skipped on the clusters not serving them anymore.
+func clusterTasks(ctx context.Context, cs *params.Run, ns string) (map[string]tektonv1.TaskKind, error) {
+	found := map[string]tektonv1.TaskKind{}
+	ctasks, err := cs.Clients.Tekton.TektonV1beta1().ClusterTasks().List(ctx, metav1.ListOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, fmt.Errorf("cannot list the ClusterTasks: %w", err)
+	}
+	if err == nil {
+		for _, task := range ctasks.Items {
+			found[task.GetName()] = clusterTaskKind
+		}
+	}
+	tasks, err := cs.Clients.Tekton.TektonV1().Tasks(ns).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("cannot list the Tasks of namespace %s: %w", ns, err)
+	}
+	for _, task := range tasks.Items {
+		found[task.GetName()] = tektonv1.NamespacedTaskKind
+	}
+	return found, nil
+}
+
 func appendYaml(filename string) string {
 	b, err := os.ReadFile(filename)
 	if err != nil {
 		log.Fatal(err)
 	}
-	s := string(b)
+	// the files may have CRLF line endings when edited on windows
+	s := strings.ReplaceAll(string(b), "\r\n", "\n")
 	if strings.HasPrefix(s, "---") {
 		return s
 	}
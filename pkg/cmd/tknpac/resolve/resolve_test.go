@@ -12,11 +12,14 @@ import (
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
 	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	"go.uber.org/zap"
 	zapobserver "go.uber.org/zap/zaptest/observer"
 	"gotest.tools/v3/assert"
 	assertfs "gotest.tools/v3/fs"
 	"gotest.tools/v3/golden"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	rtesting "knative.dev/pkg/reconciler/testing"
 )
 
@@ -89,6 +92,11 @@ func TestResolveFilenames(t *testing.T) {
 			tmpl:    tmplSimpleWithPrefix,
 			wantErr: false,
 		},
+		{
+			name:    "Resolve templates with CRLF",
+			tmpl:    strings.ReplaceAll(tmplSimpleNoPrefix, "\n", "\r\n"),
+			wantErr: false,
+		},
 		{
 			name:    "No pipelinerun",
 			tmpl:    `---\nfoo:bar`,
@@ -112,3 +120,30 @@ func TestResolveFilenames(t *testing.T) {
 		})
 	}
 }
+
+func TestClusterTasks(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{})
+	for _, name := range []string{"git-clone", "buildah"} {
+		_, err := stdata.Pipeline.TektonV1beta1().ClusterTasks().Create(ctx,
+			&tektonv1beta1.ClusterTask{ObjectMeta: metav1.ObjectMeta{Name: name}}, metav1.CreateOptions{})
+		assert.NilError(t, err)
+	}
+	for _, ns := range []string{"ns", "other"} {
+		_, err := stdata.Pipeline.TektonV1().Tasks(ns).Create(ctx,
+			&tektonv1.Task{ObjectMeta: metav1.ObjectMeta{Name: "buildah-" + ns, Namespace: ns}}, metav1.CreateOptions{})
+		assert.NilError(t, err)
+	}
+	_, err := stdata.Pipeline.TektonV1().Tasks("ns").Create(ctx,
+		&tektonv1.Task{ObjectMeta: metav1.ObjectMeta{Name: "git-clone", Namespace: "ns"}}, metav1.CreateOptions{})
+	assert.NilError(t, err)
+
+	cs := &params.Run{Clients: clients.Clients{Tekton: stdata.Pipeline}}
+	got, err := clusterTasks(ctx, cs, "ns")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, got, map[string]tektonv1.TaskKind{
+		"git-clone":  tektonv1.NamespacedTaskKind,
+		"buildah":    clusterTaskKind,
+		"buildah-ns": tektonv1.NamespacedTaskKind,
+	})
+}
@@ -0,0 +1,320 @@
+package retest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli/prompt"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/completion"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/sort"
+	"github.com/spf13/cobra"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/pkg/apis"
+)
+
+const longhelp = `
+
+retest - re-execute the PipelineRuns of the last commit of a Repository
+
+tkn pac retest will create again the PipelineRuns which ran on the last commit
+of a Repository, the same way a /retest comment on the pull request would,
+without having to push a new commit.
+
+The parameters of the PipelineRun can be overridden with --param to debug a
+Pipeline with tweaked inputs and a single PipelineRun can be selected with
+--pipeline. Only the PipelineRuns which failed on the last commit are
+re-executed with --failed, like a /retest --failed comment would.
+
+The PipelineRuns of an older commit, ie: the last release of a branch, can be
+re-executed instead of the ones of the last commit with --sha.
+
+The PipelineRuns need to exist on the kubernetes cluster to be able to be
+re-executed, the commits without any PipelineRun left can be run with an
+incoming webhook allowing to pin the commit.`
+
+const (
+	namespaceFlag = "namespace"
+	paramFlag     = "param"
+	pipelineFlag  = "pipeline"
+	failedFlag    = "failed"
+	shaFlag       = "sha"
+)
+
+type retestOptions struct {
+	cs        *params.Run
+	ioStreams *cli.IOStreams
+	opts      *cli.PacCliOpts
+	repoName  string
+	pipeline  string
+	failed    bool
+	sha       string
+	params    []string
+}
+
+func Command(run *params.Run, ioStreams *cli.IOStreams) *cobra.Command {
+	ropts := &retestOptions{cs: run, ioStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "retest",
+		Long:  longhelp,
+		Short: "Re-execute the PipelineRuns of the last commit of a Repository",
+		Annotations: map[string]string{
+			"commandType": "main",
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.BaseCompletion("repositories", args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			ropts.opts = cli.NewCliOptions(cmd)
+			ropts.opts.Namespace, err = cmd.Flags().GetString(namespaceFlag)
+			if err != nil {
+				return err
+			}
+
+			if len(args) > 0 {
+				ropts.repoName = args[0]
+			}
+
+			ctx := context.Background()
+			if err := run.Clients.NewClients(ctx, &run.Info); err != nil {
+				return err
+			}
+			return retest(ctx, ropts)
+		},
+	}
+
+	cmd.Flags().StringP(
+		namespaceFlag, "n", "", "If present, the namespace scope for this CLI request")
+	_ = cmd.RegisterFlagCompletionFunc(namespaceFlag,
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.BaseCompletion(namespaceFlag, args)
+		},
+	)
+
+	cmd.Flags().StringVar(&ropts.pipeline, pipelineFlag, "",
+		"Only re-execute the PipelineRun with this name (as named in the .tekton directory)")
+
+	cmd.Flags().BoolVar(&ropts.failed, failedFlag, false,
+		"Only re-execute the PipelineRuns which failed on the last commit")
+
+	cmd.Flags().StringVar(&ropts.sha, shaFlag, "",
+		"Re-execute the PipelineRuns still on the cluster of this commit rather than the last one, the SHA can be abbreviated")
+
+	cmd.Flags().StringArrayVarP(&ropts.params, paramFlag, "p", []string{},
+		"Override a parameter of the PipelineRun, ie: --param key=value (can be repeated)")
+
+	ioStreams.AddQuietFlag(cmd, "Only output the names of the created PipelineRuns, to be used in scripts")
+
+	return cmd
+}
+
+// parseParams parses the key=value parameters overrides.
+func parseParams(params []string) (map[string]string, error) {
+	ret := map[string]string{}
+	for _, p := range params {
+		key, value, found := strings.Cut(p, "=")
+		if !found || key == "" {
+			return nil, fmt.Errorf("invalid parameter %q, it needs to be in the key=value format", p)
+		}
+		ret[key] = value
+	}
+	return ret, nil
+}
+
+// overrideParams sets the value of the parameters of the PipelineRun, the
+// parameters which were not passed to the PipelineRun are added.
+func overrideParams(pr *tektonv1.PipelineRun, overrides map[string]string) {
+	for key, value := range overrides {
+		found := false
+		for i := range pr.Spec.Params {
+			if pr.Spec.Params[i].Name == key {
+				pr.Spec.Params[i].Value = *tektonv1.NewStructuredValues(value)
+				found = true
+			}
+		}
+		if !found {
+			pr.Spec.Params = append(pr.Spec.Params, tektonv1.Param{
+				Name:  key,
+				Value: *tektonv1.NewStructuredValues(value),
+			})
+		}
+	}
+}
+
+// newPipelineRun returns a copy of the PipelineRun ready to be created again,
+// it is started straight away since it has already been through the
+// concurrency queue when it first ran.
+func newPipelineRun(pr *tektonv1.PipelineRun, queued bool) *tektonv1.PipelineRun {
+	labels := map[string]string{}
+	for k, v := range pr.GetLabels() {
+		labels[k] = v
+	}
+	labels[keys.State] = kubeinteraction.StateStarted
+	if queued {
+		labels[keys.State] = kubeinteraction.StateQueued
+	}
+
+	annotations := map[string]string{}
+	for k, v := range pr.GetAnnotations() {
+		// the log url is the one of the previous PipelineRun
+		if k == keys.LogURL || strings.HasPrefix(k, "kubectl.kubernetes.io/") {
+			continue
+		}
+		annotations[k] = v
+	}
+
+	generateName := pr.GetGenerateName()
+	if generateName == "" {
+		generateName = labels[keys.OriginalPRName] + "-"
+	}
+
+	newpr := &tektonv1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: generateName,
+			Namespace:    pr.GetNamespace(),
+			Labels:       labels,
+			Annotations:  annotations,
+		},
+		Spec: *pr.Spec.DeepCopy(),
+	}
+	newpr.Spec.Status = ""
+	if queued {
+		newpr.Spec.Status = tektonv1.PipelineRunSpecStatusPending
+	}
+	return newpr
+}
+
+// queuePipelineRun sets the execution order of a queued PipelineRun, the
+// watcher adds it to the concurrency queue of its Repository once it has one.
+// The order needs the name generated for the PipelineRun when it is created.
+func queuePipelineRun(ctx context.Context, cs *params.Run, pr *tektonv1.PipelineRun) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{
+				keys.ExecutionOrder: fmt.Sprintf("%s/%s", pr.GetNamespace(), pr.GetName()),
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = cs.Clients.Tekton.TektonV1().PipelineRuns(pr.GetNamespace()).Patch(ctx, pr.GetName(), types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+func retest(ctx context.Context, ropts *retestOptions) error {
+	overrides, err := parseParams(ropts.params)
+	if err != nil {
+		return err
+	}
+
+	if ropts.opts.Namespace != "" {
+		ropts.cs.Info.Kube.Namespace = ropts.opts.Namespace
+	}
+	ns := ropts.cs.Info.Kube.Namespace
+
+	repoName := ropts.repoName
+	var repository *v1alpha1.Repository
+	if repoName == "" {
+		if repository, err = prompt.SelectRepo(ctx, ropts.cs, ns); err != nil {
+			return err
+		}
+		repoName = repository.GetName()
+	} else {
+		repository, err = ropts.cs.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(ns).Get(ctx, repoName, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("cannot get repository %s in namespace %s: %w", repoName, ns, err)
+		}
+	}
+	// the PipelineRuns go through the concurrency queue of the Repository
+	// like the ones created by the controller, the watcher starts them
+	queued := repository.Spec.ConcurrencyLimit != nil && *repository.Spec.ConcurrencyLimit > 0
+
+	runs, err := ropts.cs.Clients.Tekton.TektonV1().PipelineRuns(ns).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", keys.Repository, repoName),
+	})
+	if err != nil {
+		return err
+	}
+	if len(runs.Items) == 0 {
+		return fmt.Errorf("no PipelineRun found for repository %s in namespace %s", repoName, ns)
+	}
+	sort.PipelineRunSortByStartTime(runs.Items)
+	targetSHA, commit := runs.Items[0].GetLabels()[keys.SHA], "last commit"
+	if ropts.sha != "" {
+		targetSHA, commit = "", "commit"
+		for _, pr := range runs.Items {
+			if sha := pr.GetLabels()[keys.SHA]; strings.HasPrefix(sha, ropts.sha) {
+				targetSHA = sha
+				break
+			}
+		}
+		if targetSHA == "" {
+			return fmt.Errorf("no PipelineRun found on commit %s of repository %s, the PipelineRuns of the commit need to still be on the cluster", ropts.sha, repoName)
+		}
+	}
+
+	// only keep the latest PipelineRun of every Pipeline which ran on the
+	// SHA, they may have been retested already
+	seen := map[string]bool{}
+	selected := []tektonv1.PipelineRun{}
+	for _, pr := range runs.Items {
+		name := pr.GetLabels()[keys.OriginalPRName]
+		if pr.GetLabels()[keys.SHA] != targetSHA || seen[name] {
+			continue
+		}
+		if ropts.pipeline != "" && name != ropts.pipeline {
+			continue
+		}
+		seen[name] = true
+		if ropts.failed && !(pr.IsDone() && pr.Status.GetCondition(apis.ConditionSucceeded).IsFalse()) {
+			continue
+		}
+		selected = append(selected, pr)
+	}
+	if len(selected) == 0 {
+		what := "PipelineRun"
+		if ropts.failed {
+			what = "failed PipelineRun"
+		}
+		if ropts.pipeline != "" {
+			what += " named " + ropts.pipeline
+		}
+		return fmt.Errorf("cannot find a %s on the %s %s of repository %s",
+			what, commit, formatting.ShortSHA(targetSHA), repoName)
+	}
+
+	cs := ropts.ioStreams.ColorScheme()
+	for i := range selected {
+		newpr := newPipelineRun(&selected[i], queued)
+		overrideParams(newpr, overrides)
+		created, err := ropts.cs.Clients.Tekton.TektonV1().PipelineRuns(ns).Create(ctx, newpr, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("cannot create PipelineRun from %s: %w", selected[i].GetName(), err)
+		}
+		how := ""
+		if queued {
+			if err := queuePipelineRun(ctx, ropts.cs, created); err != nil {
+				return fmt.Errorf("cannot queue PipelineRun %s: %w", created.GetName(), err)
+			}
+			how = ", it is queued until the concurrency limit of the repository allows it to start"
+		}
+		if ropts.ioStreams.IsQuiet() {
+			fmt.Fprintln(ropts.ioStreams.Out, created.GetName())
+			continue
+		}
+		fmt.Fprintf(ropts.ioStreams.Out, "%s PipelineRun %s has been created from %s on commit %s%s\n",
+			cs.SuccessIcon(), cs.Bold(created.GetName()), selected[i].GetName(), formatting.ShortSHA(targetSHA), how)
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+package retest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ktesting "k8s.io/client-go/testing"
+	"knative.dev/pkg/apis"
+	knativeduckv1 "knative.dev/pkg/apis/duck/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestRetest(t *testing.T) {
+	ns := "ns"
+	cw := clockwork.NewFakeClock()
+	makePR := func(name, pipeline, sha string, succeeded corev1.ConditionStatus, started time.Duration) *tektonv1.PipelineRun {
+		return &tektonv1.PipelineRun{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:         name,
+				GenerateName: pipeline + "-",
+				Namespace:    ns,
+				Labels: map[string]string{
+					keys.Repository:     "repo",
+					keys.SHA:            sha,
+					keys.OriginalPRName: pipeline,
+					keys.State:          kubeinteraction.StateCompleted,
+				},
+				Annotations: map[string]string{
+					keys.LogURL: "https://console/" + name,
+				},
+			},
+			Spec: tektonv1.PipelineRunSpec{
+				Params: []tektonv1.Param{
+					{Name: "image", Value: *tektonv1.NewStructuredValues("quay.io/image:latest")},
+				},
+			},
+			Status: tektonv1.PipelineRunStatus{
+				Status: knativeduckv1.Status{
+					Conditions: knativeduckv1.Conditions{
+						{Type: apis.ConditionSucceeded, Status: succeeded},
+					},
+				},
+				PipelineRunStatusFields: tektonv1.PipelineRunStatusFields{
+					StartTime: &metav1.Time{Time: cw.Now().Add(-started)},
+				},
+			},
+		}
+	}
+	pruns := []*tektonv1.PipelineRun{
+		makePR("build-abcde", "build", "newsha", corev1.ConditionFalse, 5*time.Minute),
+		makePR("build-fghij", "build", "newsha", corev1.ConditionTrue, 10*time.Minute),
+		makePR("test-klmno", "test", "newsha", corev1.ConditionTrue, 10*time.Minute),
+		makePR("lint-pqrst", "lint", "oldsha", corev1.ConditionFalse, 20*time.Minute),
+	}
+
+	tests := []struct {
+		name          string
+		pipeline      string
+		failed        bool
+		sha           string
+		params        []string
+		wantErr       string
+		wantSHA       string
+		concurrency   int
+		quiet         bool
+		wantPipelines []string
+		wantParams    map[string]string
+	}{
+		{
+			name:          "all pipelines of the last commit",
+			wantPipelines: []string{"build", "test"},
+			wantParams:    map[string]string{"image": "quay.io/image:latest"},
+		},
+		{
+			name:          "quiet",
+			quiet:         true,
+			wantPipelines: []string{"build", "test"},
+			wantParams:    map[string]string{"image": "quay.io/image:latest"},
+		},
+		{
+			name:          "queued with a concurrency limit",
+			concurrency:   1,
+			wantPipelines: []string{"build", "test"},
+			wantParams:    map[string]string{"image": "quay.io/image:latest"},
+		},
+		{
+			name:          "select a pipeline and override params",
+			pipeline:      "test",
+			params:        []string{"image=quay.io/image:debug", "verbose=true"},
+			wantPipelines: []string{"test"},
+			wantParams:    map[string]string{"image": "quay.io/image:debug", "verbose": "true"},
+		},
+		{
+			name:     "pipeline not on the last commit",
+			pipeline: "lint",
+			wantErr:  "cannot find a PipelineRun named lint on the last commit newsha of repository repo",
+		},
+		{
+			name:          "only the failed pipelines of the last commit",
+			failed:        true,
+			wantPipelines: []string{"build"},
+			wantParams:    map[string]string{"image": "quay.io/image:latest"},
+		},
+		{
+			name:     "selected pipeline did not fail",
+			pipeline: "test",
+			failed:   true,
+			wantErr:  "cannot find a failed PipelineRun named test on the last commit newsha of repository repo",
+		},
+		{
+			name:          "pipelines of an older commit",
+			sha:           "old",
+			wantSHA:       "oldsha",
+			wantPipelines: []string{"lint"},
+			wantParams:    map[string]string{"image": "quay.io/image:latest"},
+		},
+		{
+			name:    "no pipeline on the commit",
+			sha:     "unknown",
+			wantErr: "no PipelineRun found on commit unknown of repository repo, the PipelineRuns of the commit need to still be on the cluster",
+		},
+		{
+			name:     "pipeline not on the commit",
+			pipeline: "build",
+			sha:      "oldsha",
+			wantErr:  "cannot find a PipelineRun named build on the commit oldsha of repository repo",
+		},
+		{
+			name:    "invalid param",
+			params:  []string{"image"},
+			wantErr: `invalid parameter "image", it needs to be in the key=value format`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			repo := &v1alpha1.Repository{ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: ns}}
+			if tt.concurrency > 0 {
+				repo.Spec.ConcurrencyLimit = &tt.concurrency
+			}
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+				Namespaces:   []*corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: ns}}},
+				PipelineRuns: pruns,
+				Repositories: []*v1alpha1.Repository{repo},
+			})
+			// the fake client doesn't generate names
+			created := 0
+			stdata.Pipeline.PrependReactor("create", "pipelineruns", func(action ktesting.Action) (bool, runtime.Object, error) {
+				pr, _ := action.(ktesting.CreateAction).GetObject().(*tektonv1.PipelineRun)
+				created++
+				pr.Name = fmt.Sprintf("%sretest%d", pr.GenerateName, created)
+				return false, nil, nil
+			})
+			cs := &params.Run{
+				Clients: clients.Clients{Tekton: stdata.Pipeline, PipelineAsCode: stdata.PipelineAsCode},
+				Info:    info.Info{Kube: info.KubeOpts{Namespace: ns}},
+			}
+			ioStreams, _, out, _ := cli.IOTest()
+			ioStreams.SetQuiet(tt.quiet)
+			err := retest(ctx, &retestOptions{
+				cs:        cs,
+				ioStreams: ioStreams,
+				opts:      &cli.PacCliOpts{},
+				repoName:  "repo",
+				pipeline:  tt.pipeline,
+				failed:    tt.failed,
+				sha:       tt.sha,
+				params:    tt.params,
+			})
+			if tt.wantErr != "" {
+				assert.Error(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, created, len(tt.wantPipelines))
+			if tt.wantSHA == "" {
+				tt.wantSHA = "newsha"
+			}
+
+			prs, err := stdata.Pipeline.TektonV1().PipelineRuns(ns).List(ctx, metav1.ListOptions{})
+			assert.NilError(t, err)
+			wantState, wantStatus := kubeinteraction.StateStarted, tektonv1.PipelineRunSpecStatus("")
+			if tt.concurrency > 0 {
+				wantState, wantStatus = kubeinteraction.StateQueued, tektonv1.PipelineRunSpecStatusPending
+			}
+			retested := []string{}
+			for _, pr := range prs.Items {
+				if pr.GetLabels()[keys.State] != wantState {
+					continue
+				}
+				assert.Equal(t, pr.Spec.Status, wantStatus)
+				if tt.concurrency > 0 {
+					assert.Equal(t, pr.GetAnnotations()[keys.ExecutionOrder], ns+"/"+pr.GetName())
+				}
+				retested = append(retested, pr.GetLabels()[keys.OriginalPRName])
+				assert.Equal(t, pr.GetLabels()[keys.SHA], tt.wantSHA)
+				assert.Equal(t, pr.GetAnnotations()[keys.LogURL], "")
+				assert.Equal(t, len(pr.Spec.Params), len(tt.wantParams))
+				for _, p := range pr.Spec.Params {
+					assert.Equal(t, p.Value.StringVal, tt.wantParams[p.Name])
+				}
+			}
+			sort.Strings(retested)
+			assert.DeepEqual(t, retested, tt.wantPipelines)
+			if tt.quiet {
+				assert.Equal(t, out.String(), "build-retest1\ntest-retest2\n")
+				return
+			}
+			assert.Equal(t, strings.Count(out.String(), "has been created"), len(tt.wantPipelines))
+		})
+	}
+}
@@ -2,15 +2,24 @@ package tknpac
 
 import (
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli/prompt"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/bootstrap"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/completion"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/controller"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/create"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/deleterepo"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/describe"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/generate"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/info"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/list"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/logs"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/repository"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/resolve"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/retest"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/setup"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/testinstall"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/trace"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/validate"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/version"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/webhook"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
@@ -28,8 +37,12 @@ func Root(clients *params.Run) *cobra.Command {
 		},
 	}
 	clients.Info.Kube.AddFlags(cmd)
+	prompt.AddFlags(cmd)
 
 	ioStreams := cli.NewIOStreams()
+	ioStreams.AddFlags(cmd)
+	clients.Clients.WrapTransport = ioStreams.TraceTransport
+	clients.Clients.Cache = cli.NewCache(cli.CachePath(), ioStreams.CacheTTL())
 
 	cmd.AddCommand(version.Command(ioStreams))
 	cmd.AddCommand(create.Root(clients, ioStreams))
@@ -37,10 +50,18 @@ func Root(clients *params.Run) *cobra.Command {
 	cmd.AddCommand(deleterepo.Root(clients, ioStreams))
 	cmd.AddCommand(describe.Root(clients, ioStreams))
 	cmd.AddCommand(logs.Command(clients, ioStreams))
+	cmd.AddCommand(retest.Command(clients, ioStreams))
 	cmd.AddCommand(resolve.Command(clients, ioStreams))
 	cmd.AddCommand(completion.Command())
 	cmd.AddCommand(bootstrap.Command(clients, ioStreams))
 	cmd.AddCommand(generate.Command(clients, ioStreams))
 	cmd.AddCommand(webhook.Root(clients, ioStreams))
+	cmd.AddCommand(repository.Root(clients, ioStreams))
+	cmd.AddCommand(validate.Command(clients, ioStreams))
+	cmd.AddCommand(setup.Command(clients, ioStreams))
+	cmd.AddCommand(controller.Root(clients, ioStreams))
+	cmd.AddCommand(trace.Command(clients, ioStreams))
+	cmd.AddCommand(info.Command(clients, ioStreams))
+	cmd.AddCommand(testinstall.Command(clients, ioStreams))
 	return cmd
 }
@@ -0,0 +1,194 @@
+package setup
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	pacInfo "github.com/openshift-pipelines/pipelines-as-code/pkg/cli/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli/prompt"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli/webhook"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/bootstrap"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/create"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/git"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	providerGithubApp      = "github-app"
+	providerGithubWebhook  = "github-webhook"
+	providerGitlab         = "gitlab"
+	providerBitbucketCloud = "bitbucket-cloud"
+
+	totalSteps = 5
+)
+
+var providers = []string{providerGithubApp, providerGithubWebhook, providerGitlab, providerBitbucketCloud}
+
+type options struct {
+	run          *params.Run
+	ioStreams    *cli.IOStreams
+	cliOpts      *cli.PacCliOpts
+	pacNamespace string
+	provider     string
+
+	// configureGithubApp creates the GitHub App, overridden in tests
+	configureGithubApp func(ctx context.Context, installationNS string) error
+}
+
+func Command(run *params.Run, ioStreams *cli.IOStreams) *cobra.Command {
+	opts := &options{
+		run:       run,
+		ioStreams: ioStreams,
+	}
+	opts.configureGithubApp = func(ctx context.Context, installationNS string) error {
+		app := bootstrap.GithubApp(run, ioStreams)
+		app.SetArgs([]string{"--namespace", installationNS})
+		return app.ExecuteContext(ctx)
+	}
+
+	cmd := &cobra.Command{
+		Use:   "setup",
+		Short: "Guided setup of a repository with Pipelines as Code",
+		Long: `A wizard guiding you through the setup of a Git repository with Pipelines as Code.
+
+It checks Pipelines as Code is installed, lets you choose how the Git provider
+sends its events (GitHub App or webhook), creates the Repository CR in the
+namespace of your choice, configures the webhook and its secrets on the Git
+provider and generates a sample PipelineRun in the .tekton directory.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			opts.cliOpts = cli.NewCliOptions(cmd)
+			opts.ioStreams.SetColorEnabled(!opts.cliOpts.NoColoring)
+			if err := run.Clients.NewClients(ctx, &run.Info); err != nil {
+				return err
+			}
+			cwd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			return opts.wizard(ctx, git.GetGitInfo(cwd))
+		},
+		Annotations: map[string]string{
+			"commandType": "main",
+		},
+	}
+	cmd.PersistentFlags().BoolP("no-color", "C", !ioStreams.ColorEnabled(), "Disable coloring")
+	cmd.PersistentFlags().StringVarP(&opts.pacNamespace, "pac-namespace", "", "",
+		"The namespace where pac is installed")
+	cmd.PersistentFlags().StringVar(&opts.provider, "git-provider", "",
+		fmt.Sprintf("How the Git provider sends its events, choices are: %s", strings.Join(providers, ", ")))
+	return cmd
+}
+
+func (o *options) step(n int, title string) {
+	cs := o.ioStreams.ColorScheme()
+	fmt.Fprintf(o.ioStreams.Out, "\n%s\n", cs.Bold(fmt.Sprintf("Step %d/%d: %s", n, totalSteps, title)))
+}
+
+// askProvider asks how the Git provider sends its events, proposing the one
+// matching the repository URL by default.
+func (o *options) askProvider(repoURL string) error {
+	if o.provider != "" {
+		for _, p := range providers {
+			if p == o.provider {
+				return nil
+			}
+		}
+		return fmt.Errorf("invalid git provider %s, choices are: %s", o.provider, strings.Join(providers, ", "))
+	}
+
+	deflt := providerGithubApp
+	switch {
+	case strings.Contains(repoURL, "gitlab"):
+		deflt = providerGitlab
+	case strings.Contains(repoURL, "bitbucket"):
+		deflt = providerBitbucketCloud
+	}
+	return prompt.SurveyAskOne(&survey.Select{
+		Message: "How should your Git provider send its events to Pipelines as Code?",
+		Options: providers,
+		Default: deflt,
+	}, &o.provider)
+}
+
+func (o *options) wizard(ctx context.Context, gitInfo *git.Info) error {
+	cs := o.ioStreams.ColorScheme()
+
+	o.step(1, "Pipelines as Code installation")
+	installed, installationNS, err := bootstrap.DetectPacInstallation(ctx, o.pacNamespace, o.run)
+	if !installed {
+		return fmt.Errorf("pipelines as code is not installed on the cluster, you can install it with \"%s pac bootstrap\"", settings.TknBinaryName)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(o.ioStreams.Out, "%s Pipelines as Code is installed in the %s namespace\n", cs.SuccessIcon(), installationNS)
+
+	o.step(2, "Git repository and provider")
+	repoOpts := create.NewRepoOptions(o.run, o.ioStreams, o.cliOpts, gitInfo)
+	if err := repoOpts.AskRepoURL(); err != nil {
+		return err
+	}
+	if err := o.askProvider(repoOpts.Event.URL); err != nil {
+		return err
+	}
+	if o.provider == providerGithubApp && !pacInfo.IsGithubAppInstalled(ctx, o.run, installationNS) {
+		fmt.Fprintf(o.ioStreams.Out, "%s There is no GitHub App configured yet, let's create one\n", cs.InfoIcon())
+		if err := o.configureGithubApp(ctx, installationNS); err != nil {
+			return err
+		}
+		if !pacInfo.IsGithubAppInstalled(ctx, o.run, installationNS) {
+			return fmt.Errorf("the GitHub App has not been configured in the %s namespace", installationNS)
+		}
+	}
+
+	o.step(3, "Namespace and Repository")
+	repoName, repoNamespace, err := repoOpts.Create(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err := o.run.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(repoNamespace).Get(ctx, repoName, metav1.GetOptions{}); err != nil {
+		return fmt.Errorf("cannot find the Repository %s in the %s namespace after creating it: %w", repoName, repoNamespace, err)
+	}
+
+	o.step(4, "Webhook and secrets")
+	if o.provider == providerGithubApp {
+		fmt.Fprintf(o.ioStreams.Out, "%s Events are sent by the GitHub App, make sure it is installed on %s\n", cs.InfoIcon(), repoOpts.Event.URL)
+	} else {
+		webhookProvider := o.provider
+		if webhookProvider == providerGithubWebhook {
+			webhookProvider = "github"
+		}
+		config := &webhook.Options{
+			Run:                      o.run,
+			PACNamespace:             installationNS,
+			RepositoryURL:            repoOpts.Event.URL,
+			IOStreams:                o.ioStreams,
+			RepositoryName:           repoName,
+			RepositoryNamespace:      repoNamespace,
+			RepositoryCreateORUpdate: true,
+		}
+		if err := config.Install(ctx, webhookProvider); err != nil {
+			return err
+		}
+	}
+
+	o.step(5, "Sample PipelineRun")
+	if gitInfo.TopLevelPath == "" {
+		fmt.Fprintf(o.ioStreams.Out, "%s Not in a git checkout, you can generate a sample PipelineRun later with \"%s pac generate\"\n",
+			cs.WarningIcon(), settings.TknBinaryName)
+	} else if err := repoOpts.GenerateTemplate(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(o.ioStreams.Out, "\n%s Your repository %s is ready, commit and push the .tekton directory to start running your pipelines!\n",
+		cs.SuccessIcon(), repoOpts.Event.URL)
+	return nil
+}
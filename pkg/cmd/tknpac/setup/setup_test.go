@@ -0,0 +1,123 @@
+package setup
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli/prompt"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/git"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/pipelineascode"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestWizard(t *testing.T) {
+	pacNS := "pipelines-as-code"
+	infoConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pipelines-as-code-info",
+			Namespace: pacNS,
+			Labels:    map[string]string{"app.kubernetes.io/part-of": "pipelines-as-code"},
+		},
+	}
+	appSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: pipelineascode.DefaultPipelinesAscodeSecretName, Namespace: pacNS},
+	}
+	targetNS := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "target"}}
+
+	tests := []struct {
+		name          string
+		tdata         testclient.Data
+		provider      string
+		wantErr       string
+		wantAppConfig bool
+	}{
+		{
+			name: "github app already configured",
+			tdata: testclient.Data{
+				ConfigMap:  []*corev1.ConfigMap{infoConfigMap},
+				Secret:     []*corev1.Secret{appSecret},
+				Namespaces: []*corev1.Namespace{targetNS},
+			},
+		},
+		{
+			name: "github app to configure",
+			tdata: testclient.Data{
+				ConfigMap:  []*corev1.ConfigMap{infoConfigMap},
+				Namespaces: []*corev1.Namespace{targetNS},
+			},
+			wantAppConfig: true,
+			wantErr:       "the GitHub App has not been configured in the pipelines-as-code namespace",
+		},
+		{
+			name: "pac not installed properly",
+			tdata: testclient.Data{
+				Namespaces: []*corev1.Namespace{targetNS},
+			},
+			wantErr: "could not detect Pipelines as Code configmap",
+		},
+		{
+			name:     "invalid provider",
+			provider: "gitea",
+			tdata: testclient.Data{
+				ConfigMap: []*corev1.ConfigMap{infoConfigMap},
+			},
+			wantErr: "invalid git provider gitea",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			stdata, _ := testclient.SeedTestData(t, ctx, tt.tdata)
+			run := &params.Run{
+				Clients: clients.Clients{
+					PipelineAsCode: stdata.PipelineAsCode,
+					Kube:           stdata.Kube,
+				},
+			}
+			as, teardown := prompt.InitAskStubber()
+			defer teardown()
+			as.StubOne("")           // repository url, the git one by default
+			as.StubOne("github-app") // provider
+			as.StubOne("target")     // namespace
+
+			io, _, out, _ := cli.IOTest()
+			appConfigured := false
+			opts := &options{
+				run:       run,
+				ioStreams: io,
+				cliOpts:   &cli.PacCliOpts{},
+				provider:  tt.provider,
+				configureGithubApp: func(ctx context.Context, installationNS string) error {
+					appConfigured = true
+					return nil
+				},
+			}
+			topdir := t.TempDir()
+			err := opts.wizard(ctx, &git.Info{URL: "https://github.com/owner/repo", TopLevelPath: topdir})
+			assert.Equal(t, appConfigured, tt.wantAppConfig)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+
+			repo, err := stdata.PipelineAsCode.PipelinesascodeV1alpha1().Repositories("target").Get(ctx, "owner-repo", metav1.GetOptions{})
+			assert.NilError(t, err)
+			assert.Equal(t, repo.Spec.URL, "https://github.com/owner/repo")
+			_, err = os.Stat(filepath.Join(topdir, ".tekton"))
+			assert.NilError(t, err)
+			assert.Assert(t, strings.Contains(out.String(), "Step 5/5: Sample PipelineRun"), out.String())
+			assert.Assert(t, strings.Contains(out.String(), "Your repository https://github.com/owner/repo is ready"), out.String())
+		})
+	}
+}
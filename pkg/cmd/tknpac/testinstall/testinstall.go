@@ -0,0 +1,311 @@
+package testinstall
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v49/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/completion"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/pipelineascode"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	"github.com/spf13/cobra"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"golang.org/x/oauth2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"knative.dev/pkg/apis"
+)
+
+const longhelp = `
+
+test-install - verify an installation of Pipelines as Code end to end
+
+tkn pac test-install pushes a trivial PipelineRun in the .tekton directory of
+a temporary branch of the repository of a Repository, waits for Pipelines as
+Code to run it and to report its status on the commit, and deletes the branch
+and the PipelineRun.
+
+Only the GitHub repositories with a git_provider secret are supported, the
+token of the secret needs to be able to push to the repository.`
+
+const (
+	defaultTimeout = 10 * time.Minute
+	// branchPrefix is the prefix of the temporary branch the PipelineRun is
+	// pushed to.
+	branchPrefix = "pac-test-install-"
+	// pipelineRunPath is where the PipelineRun is pushed in the temporary
+	// branch.
+	pipelineRunPath = ".tekton/pac-test-install.yaml"
+)
+
+// pollInterval is how often the PipelineRun and the status of the commit are
+// looked at, it is a variable to be replaced in the tests.
+var pollInterval = 5 * time.Second
+
+const pipelineRunTemplate = `apiVersion: tekton.dev/v1
+kind: PipelineRun
+metadata:
+  name: pac-test-install
+  annotations:
+    pipelinesascode.tekton.dev/on-event: "[push]"
+    pipelinesascode.tekton.dev/on-target-branch: "[%s]"
+spec:
+  pipelineSpec:
+    tasks:
+      - name: hello
+        taskSpec:
+          steps:
+            - name: hello
+              image: registry.access.redhat.com/ubi9/ubi-micro
+              script: |
+                echo "Pipelines as Code is installed"
+`
+
+type testInstallOptions struct {
+	run       *params.Run
+	ioStreams *cli.IOStreams
+	namespace string
+	timeout   time.Duration
+	// ghClient is the GitHub client, it is created from the token of the
+	// Repository when nil.
+	ghClient *github.Client
+}
+
+func Command(run *params.Run, ioStreams *cli.IOStreams) *cobra.Command {
+	opts := &testInstallOptions{run: run, ioStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "test-install <repository>",
+		Long:  longhelp,
+		Short: "Verify an installation by running a PipelineRun on a temporary branch of a Repository",
+		Args:  cobra.ExactArgs(1),
+		Annotations: map[string]string{
+			"commandType": "main",
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.BaseCompletion("repositories", args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// the temporary branch is deleted when the command is interrupted
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+			defer stop()
+			if err := run.Clients.NewClients(ctx, &run.Info); err != nil {
+				return err
+			}
+			if opts.namespace == "" {
+				opts.namespace = run.Info.Kube.Namespace
+			}
+			return testInstall(ctx, opts, args[0])
+		},
+	}
+	cmd.Flags().StringVarP(&opts.namespace, "namespace", "n", "", "The namespace of the Repository")
+	_ = cmd.RegisterFlagCompletionFunc("namespace",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.BaseCompletion("namespace", args)
+		},
+	)
+	cmd.Flags().DurationVar(&opts.timeout, "timeout", defaultTimeout, "How long to wait for the PipelineRun and its status")
+	return cmd
+}
+
+// testInstall pushes the PipelineRun to a temporary branch of the repository
+// and waits for it to run and for its status to be reported, the branch and
+// the PipelineRun are deleted at the end.
+func testInstall(ctx context.Context, opts *testInstallOptions, name string) error {
+	cs := opts.ioStreams.ColorScheme()
+	repo, err := opts.run.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(opts.namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if providerType := provider.RepositoryProviderType(repo); providerType != "github" {
+		return fmt.Errorf("testing the installation with a %s repository is not supported, only GitHub repositories with a git_provider secret are", providerType)
+	}
+	client, err := opts.githubClient(ctx, repo)
+	if err != nil {
+		return err
+	}
+	owner, repoName, err := formatting.GetRepoOwnerSplitted(repo.Spec.URL)
+	if err != nil {
+		return err
+	}
+
+	ghRepo, _, err := client.Repositories.Get(ctx, owner, repoName)
+	if err != nil {
+		return fmt.Errorf("cannot get the repository %s/%s: %w", owner, repoName, err)
+	}
+	defaultRef, _, err := client.Git.GetRef(ctx, owner, repoName, "refs/heads/"+ghRepo.GetDefaultBranch())
+	if err != nil {
+		return fmt.Errorf("cannot get the default branch %s of %s/%s: %w", ghRepo.GetDefaultBranch(), owner, repoName, err)
+	}
+	branch := branchPrefix + rand.String(5)
+	if _, _, err := client.Git.CreateRef(ctx, owner, repoName, &github.Reference{
+		Ref:    github.String("refs/heads/" + branch),
+		Object: &github.GitObject{SHA: defaultRef.GetObject().SHA},
+	}); err != nil {
+		return fmt.Errorf("cannot create the branch %s on %s/%s: %w", branch, owner, repoName, err)
+	}
+	fmt.Fprintf(opts.ioStreams.Out, "%s Created the temporary branch %s on %s/%s\n", cs.SuccessIcon(), branch, owner, repoName)
+
+	var sha string
+	defer func() {
+		// clean up even when the command has been interrupted
+		opts.teardown(context.Background(), client, repo, owner, repoName, branch, sha)
+	}()
+
+	content, _, err := client.Repositories.CreateFile(ctx, owner, repoName, pipelineRunPath, &github.RepositoryContentFileOptions{
+		Message: github.String("Test the installation of Pipelines as Code"),
+		Content: []byte(fmt.Sprintf(pipelineRunTemplate, branch)),
+		Branch:  github.String(branch),
+	})
+	if err != nil {
+		return fmt.Errorf("cannot push %s to the branch %s: %w", pipelineRunPath, branch, err)
+	}
+	sha = content.Commit.GetSHA()
+	fmt.Fprintf(opts.ioStreams.Out, "%s Pushed %s in the commit %s\n", cs.SuccessIcon(), pipelineRunPath, formatting.ShortSHA(sha))
+
+	deadline := time.Now().Add(opts.timeout)
+	pr, err := opts.waitPipelineRun(ctx, repo, sha, deadline)
+	if err != nil {
+		return err
+	}
+	cond := pr.Status.GetCondition(apis.ConditionSucceeded)
+	if cond.Status != corev1.ConditionTrue {
+		return fmt.Errorf("the PipelineRun %s has failed: %s", pr.GetName(), cond.Message)
+	}
+	fmt.Fprintf(opts.ioStreams.Out, "%s The PipelineRun %s has succeeded\n", cs.SuccessIcon(), pr.GetName())
+
+	state, err := waitCommitStatus(ctx, client, owner, repoName, sha, deadline)
+	if err != nil {
+		return err
+	}
+	if state != "success" {
+		return fmt.Errorf("the status of the commit %s has been reported as %s", formatting.ShortSHA(sha), state)
+	}
+	fmt.Fprintf(opts.ioStreams.Out, "%s The status of the commit has been reported on %s/%s\n", cs.SuccessIcon(), owner, repoName)
+	fmt.Fprintf(opts.ioStreams.Out, "%s Pipelines as Code is working with the Repository %s\n", cs.SuccessIcon(), repo.GetName())
+	return nil
+}
+
+// githubClient returns the GitHub client with the token of the git_provider
+// secret of the Repository.
+func (opts *testInstallOptions) githubClient(ctx context.Context, repo *v1alpha1.Repository) (*github.Client, error) {
+	if opts.ghClient != nil {
+		return opts.ghClient, nil
+	}
+	if repo.Spec.GitProvider == nil || repo.Spec.GitProvider.Secret == nil {
+		return nil, fmt.Errorf("the Repository %s has no git_provider secret, the token of the secret is used to push to the repository", repo.GetName())
+	}
+	secret, err := opts.run.Clients.Kube.CoreV1().Secrets(repo.GetNamespace()).Get(ctx, repo.Spec.GitProvider.Secret.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	secretKey := repo.Spec.GitProvider.Secret.Key
+	if secretKey == "" {
+		secretKey = pipelineascode.DefaultGitProviderSecretKey
+	}
+	token := strings.TrimSpace(string(secret.Data[secretKey]))
+	if token == "" {
+		return nil, fmt.Errorf("cannot find key %s in secret %s", secretKey, secret.GetName())
+	}
+	httpClient := oauth2.NewClient(ctx, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}))
+	apiURL := repo.Spec.GitProvider.URL
+	if apiURL == "" || apiURL == keys.PublicGithubAPIURL {
+		return github.NewClient(httpClient), nil
+	}
+	return github.NewEnterpriseClient(apiURL, "", httpClient)
+}
+
+// waitPipelineRun waits for the PipelineRun of the commit to be created and
+// to be done.
+func (opts *testInstallOptions) waitPipelineRun(ctx context.Context, repo *v1alpha1.Repository, sha string, deadline time.Time) (*tektonv1.PipelineRun, error) {
+	cs := opts.ioStreams.ColorScheme()
+	selector := fmt.Sprintf("%s=%s,%s=%s", keys.Repository, formatting.K8LabelsCleanup(repo.GetName()), keys.SHA, sha)
+	var started string
+	for {
+		prs, err := opts.run.Clients.Tekton.TektonV1().PipelineRuns(repo.GetNamespace()).List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return nil, err
+		}
+		if len(prs.Items) > 0 {
+			pr := &prs.Items[0]
+			if started == "" {
+				started = pr.GetName()
+				fmt.Fprintf(opts.ioStreams.Out, "%s The PipelineRun %s has been created in the %s namespace\n", cs.SuccessIcon(), started, repo.GetNamespace())
+			}
+			if cond := pr.Status.GetCondition(apis.ConditionSucceeded); cond != nil && cond.Status != corev1.ConditionUnknown {
+				return pr, nil
+			}
+		}
+		if time.Now().After(deadline) {
+			if started == "" {
+				return nil, fmt.Errorf("no PipelineRun has been created for the commit %s, check the webhook of the repository with: tkn pac describe --check -n %s %s",
+					formatting.ShortSHA(sha), repo.GetNamespace(), repo.GetName())
+			}
+			return nil, fmt.Errorf("the PipelineRun %s is not done after %s", started, opts.timeout)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// waitCommitStatus waits for the status of the commit to be reported and
+// returns its state.
+func waitCommitStatus(ctx context.Context, client *github.Client, owner, repoName, sha string, deadline time.Time) (string, error) {
+	for {
+		status, _, err := client.Repositories.GetCombinedStatus(ctx, owner, repoName, sha, &github.ListOptions{})
+		if err != nil {
+			return "", fmt.Errorf("cannot get the status of the commit %s: %w", formatting.ShortSHA(sha), err)
+		}
+		if status.GetTotalCount() > 0 && status.GetState() != "pending" {
+			return status.GetState(), nil
+		}
+		if time.Now().After(deadline) {
+			return "", fmt.Errorf("the status of the commit %s has not been reported", formatting.ShortSHA(sha))
+		}
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// teardown deletes the temporary branch and the PipelineRuns of the commit,
+// the failures are only reported.
+func (opts *testInstallOptions) teardown(ctx context.Context, client *github.Client, repo *v1alpha1.Repository, owner, repoName, branch, sha string) {
+	cs := opts.ioStreams.ColorScheme()
+	if _, err := client.Git.DeleteRef(ctx, owner, repoName, "refs/heads/"+branch); err != nil {
+		fmt.Fprintf(opts.ioStreams.ErrOut, "%s cannot delete the branch %s: %v\n", cs.FailureIcon(), branch, err)
+	} else {
+		fmt.Fprintf(opts.ioStreams.Out, "%s Deleted the temporary branch %s\n", cs.SuccessIcon(), branch)
+	}
+	if sha == "" {
+		return
+	}
+	selector := fmt.Sprintf("%s=%s,%s=%s", keys.Repository, formatting.K8LabelsCleanup(repo.GetName()), keys.SHA, sha)
+	pipelineRuns := opts.run.Clients.Tekton.TektonV1().PipelineRuns(repo.GetNamespace())
+	prs, err := pipelineRuns.List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		fmt.Fprintf(opts.ioStreams.ErrOut, "%s cannot list the PipelineRuns of the commit %s: %v\n", cs.FailureIcon(), formatting.ShortSHA(sha), err)
+		return
+	}
+	for _, pr := range prs.Items {
+		if err := pipelineRuns.Delete(ctx, pr.GetName(), metav1.DeleteOptions{}); err != nil {
+			fmt.Fprintf(opts.ioStreams.ErrOut, "%s cannot delete the PipelineRun %s: %v\n", cs.FailureIcon(), pr.GetName(), err)
+			continue
+		}
+		fmt.Fprintf(opts.ioStreams.Out, "%s Deleted the PipelineRun %s\n", cs.SuccessIcon(), pr.GetName())
+	}
+}
@@ -0,0 +1,132 @@
+package testinstall
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	tcli "github.com/openshift-pipelines/pipelines-as-code/pkg/test/cli"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	ghtesthelper "github.com/openshift-pipelines/pipelines-as-code/pkg/test/github"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	knativeapis "knative.dev/pkg/apis"
+	knativeduckv1 "knative.dev/pkg/apis/duck/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestTestInstall(t *testing.T) {
+	defer func(interval time.Duration) { pollInterval = interval }(pollInterval)
+	pollInterval = time.Millisecond
+	sha := "6113728f27ae82c7b1a177c8d03f9e96e0adf246"
+	tests := []struct {
+		name         string
+		pipelineRuns []*tektonv1.PipelineRun
+		statusState  string
+		wantErr      string
+	}{
+		{
+			name: "installation works",
+			pipelineRuns: []*tektonv1.PipelineRun{{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "pac-test-install-abcde",
+					Namespace: "ns",
+					Labels:    map[string]string{keys.Repository: "repo", keys.SHA: sha},
+				},
+				Status: tektonv1.PipelineRunStatus{Status: knativeduckv1.Status{Conditions: knativeduckv1.Conditions{{
+					Type:   knativeapis.ConditionSucceeded,
+					Status: corev1.ConditionTrue,
+				}}}},
+			}},
+			statusState: "success",
+		},
+		{
+			name:    "no pipelinerun created",
+			wantErr: "no PipelineRun has been created for the commit 6113728, check the webhook of the repository with: tkn pac describe --check -n ns repo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			ghClient, mux, _, teardown := ghtesthelper.SetupGH()
+			defer teardown()
+
+			var branch string
+			deleted := false
+			mux.HandleFunc("/repos/owner/repo", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"default_branch": "main"}`)
+			})
+			mux.HandleFunc("/repos/owner/repo/git/ref/heads/main", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"ref": "refs/heads/main", "object": {"sha": "mainsha"}}`)
+			})
+			mux.HandleFunc("/repos/owner/repo/git/refs", func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, r.Method, http.MethodPost)
+				body := map[string]string{}
+				assert.NilError(t, json.NewDecoder(r.Body).Decode(&body))
+				assert.Equal(t, body["sha"], "mainsha")
+				branch = strings.TrimPrefix(body["ref"], "refs/heads/")
+				assert.Assert(t, strings.HasPrefix(branch, branchPrefix))
+				fmt.Fprintf(w, `{"ref": "%s"}`, body["ref"])
+			})
+			mux.HandleFunc("/repos/owner/repo/contents/.tekton/pac-test-install.yaml", func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, r.Method, http.MethodPut)
+				body := map[string]string{}
+				assert.NilError(t, json.NewDecoder(r.Body).Decode(&body))
+				assert.Equal(t, body["branch"], branch)
+				fmt.Fprintf(w, `{"commit": {"sha": "%s"}}`, sha)
+			})
+			mux.HandleFunc(fmt.Sprintf("/repos/owner/repo/commits/%s/status", sha), func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, `{"state": "%s", "total_count": 1}`, tt.statusState)
+			})
+			mux.HandleFunc("/repos/owner/repo/git/refs/heads/", func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, r.Method, http.MethodDelete)
+				assert.Equal(t, r.URL.Path, "/repos/owner/repo/git/refs/heads/"+branch)
+				deleted = true
+			})
+
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+				Repositories: []*v1alpha1.Repository{{
+					ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"},
+					Spec: v1alpha1.RepositorySpec{
+						URL:         "https://github.com/owner/repo",
+						GitProvider: &v1alpha1.GitProvider{Secret: &v1alpha1.Secret{Name: "token"}},
+					},
+				}},
+				PipelineRuns: tt.pipelineRuns,
+			})
+			io, out := tcli.NewIOStream()
+			opts := &testInstallOptions{
+				run: &params.Run{Clients: clients.Clients{
+					PipelineAsCode: stdata.PipelineAsCode,
+					Tekton:         stdata.Pipeline,
+				}},
+				ioStreams: io,
+				namespace: "ns",
+				timeout:   10 * time.Millisecond,
+				ghClient:  ghClient,
+			}
+
+			err := testInstall(ctx, opts, "repo")
+			assert.Assert(t, deleted)
+			if tt.wantErr != "" {
+				assert.Error(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Assert(t, strings.Contains(out.String(), "Pipelines as Code is working with the Repository repo"), out.String())
+
+			prs, err := stdata.Pipeline.TektonV1().PipelineRuns("ns").List(ctx, metav1.ListOptions{})
+			assert.NilError(t, err)
+			assert.Equal(t, len(prs.Items), 0)
+		})
+	}
+}
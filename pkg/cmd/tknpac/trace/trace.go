@@ -0,0 +1,349 @@
+package trace
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli/prompt"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/bootstrap"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/completion"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/controller"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/spf13/cobra"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const longhelp = `
+
+trace - show the timeline of what happened for a commit SHA on a Repository
+
+tkn pac trace correlates the controller logs, the Kubernetes events, the
+PipelineRuns and the statuses of the Repository about a commit SHA into a
+single timeline, from the webhook delivery to the status reported on the git
+provider.
+
+The controller logs are only shown if you have access to the pods of the
+namespace where Pipelines as Code is installed.`
+
+const (
+	namespaceFlag    = "namespace"
+	shaFlag          = "sha"
+	repoFlag         = "repo"
+	pacNamespaceFlag = "pac-namespace"
+	// eventWindow is the time after the last controller log line about the
+	// SHA during which the events of the Repository are considered to be
+	// about the SHA.
+	eventWindow = 30 * time.Second
+)
+
+type traceOptions struct {
+	run          *params.Run
+	ioStreams    *cli.IOStreams
+	namespace    string
+	pacNamespace string
+	repoName     string
+	sha          string
+}
+
+// entry is a step of the timeline.
+type entry struct {
+	time    time.Time
+	source  string
+	message string
+}
+
+func Command(run *params.Run, ioStreams *cli.IOStreams) *cobra.Command {
+	opts := &traceOptions{run: run, ioStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "trace",
+		Long:  longhelp,
+		Short: "Show the timeline of what happened for a commit SHA on a Repository",
+		Annotations: map[string]string{
+			"commandType": "main",
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.sha == "" {
+				return fmt.Errorf("the --%s flag is required", shaFlag)
+			}
+			ctx := cmd.Context()
+			if err := run.Clients.NewClients(ctx, &run.Info); err != nil {
+				return err
+			}
+			return trace(ctx, opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.sha, shaFlag, "", "", "The commit SHA to trace, it can be abbreviated")
+	cmd.Flags().StringVarP(&opts.repoName, repoFlag, "", "", "The name of the Repository")
+	_ = cmd.RegisterFlagCompletionFunc(repoFlag,
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.BaseCompletion("repositories", args)
+		},
+	)
+	cmd.Flags().StringVarP(&opts.namespace, namespaceFlag, "n", "", "If present, the namespace scope for this CLI request")
+	_ = cmd.RegisterFlagCompletionFunc(namespaceFlag,
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completion.BaseCompletion(namespaceFlag, args)
+		},
+	)
+	cmd.Flags().StringVarP(&opts.pacNamespace, pacNamespaceFlag, "", "", "The namespace where pac is installed")
+	return cmd
+}
+
+func trace(ctx context.Context, opts *traceOptions) error {
+	var repository *v1alpha1.Repository
+	var err error
+
+	if opts.namespace != "" {
+		opts.run.Info.Kube.Namespace = opts.namespace
+	}
+	if opts.repoName != "" {
+		repository, err = opts.run.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(opts.run.Info.Kube.Namespace).Get(ctx,
+			opts.repoName, metav1.GetOptions{})
+	} else {
+		repository, err = prompt.SelectRepo(ctx, opts.run, opts.run.Info.Kube.Namespace)
+	}
+	if err != nil {
+		return err
+	}
+
+	entries := []entry{}
+	warnings := []string{}
+
+	logEntries, err := controllerLogEntries(ctx, opts)
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("cannot get the controller logs: %v", err))
+	}
+	entries = append(entries, logEntries...)
+
+	prs, err := opts.run.Clients.Tekton.TektonV1().PipelineRuns(repository.GetNamespace()).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", keys.Repository, repository.GetName()),
+	})
+	if err != nil {
+		return err
+	}
+	prNames := map[string]bool{}
+	for i := range prs.Items {
+		pr := &prs.Items[i]
+		if !strings.HasPrefix(pr.GetLabels()[keys.SHA], opts.sha) {
+			continue
+		}
+		prNames[pr.GetName()] = true
+		entries = append(entries, pipelineRunEntries(pr)...)
+	}
+
+	for _, status := range repository.Status {
+		if status.SHA == nil || !strings.HasPrefix(*status.SHA, opts.sha) || status.CompletionTime == nil {
+			continue
+		}
+		reason := ""
+		if len(status.Status.Conditions) > 0 {
+			reason = status.Status.Conditions[0].Reason
+		}
+		entries = append(entries, entry{
+			time:    status.CompletionTime.Time,
+			source:  "Repository",
+			message: fmt.Sprintf("status of PipelineRun %s recorded: %s", status.PipelineRunName, reason),
+		})
+	}
+
+	eventEntries, err := eventsEntries(ctx, opts, repository, prNames, logEntries)
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("cannot get the events: %v", err))
+	}
+	entries = append(entries, eventEntries...)
+
+	if len(entries) == 0 {
+		return fmt.Errorf("cannot find anything about the SHA %s on repository %s", opts.sha, repository.GetName())
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].time.Before(entries[j].time) })
+	return printTimeline(opts, repository, entries, warnings)
+}
+
+// controllerLogEntries returns the lines of the controller logs containing
+// the SHA.
+func controllerLogEntries(ctx context.Context, opts *traceOptions) ([]entry, error) {
+	installed, ns, err := bootstrap.DetectPacInstallation(ctx, opts.pacNamespace, opts.run)
+	if !installed {
+		return nil, fmt.Errorf("pipelines as code not installed")
+	}
+	if err != nil {
+		return nil, err
+	}
+	pods, err := controller.ListPods(ctx, opts.run, ns, "")
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []entry{}
+	for _, pod := range pods {
+		rc, err := opts.run.Clients.Kube.CoreV1().Pods(pod.GetNamespace()).GetLogs(pod.GetName(), &corev1.PodLogOptions{
+			Timestamps: true,
+		}).Stream(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get the logs of pod %s: %w", pod.GetName(), err)
+		}
+		podEntries, err := parseLogs(rc, pod.GetName(), opts.sha)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, podEntries...)
+	}
+	return entries, nil
+}
+
+// parseLogs returns the lines containing the SHA of logs prefixed by their
+// timestamp, the message and the delivery ID are extracted from the JSON
+// lines.
+func parseLogs(r io.Reader, podName, sha string) ([]entry, error) {
+	entries := []entry{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.Contains(line, sha) {
+			continue
+		}
+		ts, msg, found := strings.Cut(line, " ")
+		if !found {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339Nano, ts)
+		if err != nil {
+			continue
+		}
+		fields := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(msg), &fields); err == nil {
+			if m, ok := fields["msg"].(string); ok {
+				msg = m
+			}
+			if id, ok := fields["event-id"].(string); ok && id != "" {
+				msg = fmt.Sprintf("%s (delivery %s)", msg, id)
+			}
+		}
+		entries = append(entries, entry{time: t, source: "log " + podName, message: msg})
+	}
+	return entries, scanner.Err()
+}
+
+func pipelineRunEntries(pr *tektonv1.PipelineRun) []entry {
+	entries := []entry{{
+		time:   pr.GetCreationTimestamp().Time,
+		source: "PipelineRun " + pr.GetName(),
+		message: fmt.Sprintf("created for the %s event on %s",
+			pr.GetLabels()[keys.EventType], pr.GetLabels()[keys.Branch]),
+	}}
+	if pr.Status.StartTime != nil {
+		entries = append(entries, entry{
+			time:    pr.Status.StartTime.Time,
+			source:  "PipelineRun " + pr.GetName(),
+			message: "started",
+		})
+	}
+	if pr.Status.CompletionTime != nil {
+		reason := ""
+		if len(pr.Status.Conditions) > 0 {
+			reason = pr.Status.Conditions[0].Reason
+		}
+		entries = append(entries, entry{
+			time:    pr.Status.CompletionTime.Time,
+			source:  "PipelineRun " + pr.GetName(),
+			message: "completed: " + reason,
+		})
+	}
+	return entries
+}
+
+// eventsEntries returns the events of the PipelineRuns of the SHA and the
+// events of the Repository mentioning the SHA or emitted while the controller
+// was processing it, ie: why no PipelineRun matched.
+func eventsEntries(ctx context.Context, opts *traceOptions, repository *v1alpha1.Repository, prNames map[string]bool, logEntries []entry) ([]entry, error) {
+	kinteract, err := kubeinteraction.NewKubernetesInteraction(opts.run)
+	if err != nil {
+		return nil, err
+	}
+
+	var start, end time.Time
+	for _, e := range logEntries {
+		if start.IsZero() || e.time.Before(start) {
+			start = e.time
+		}
+		if e.time.After(end) {
+			end = e.time
+		}
+	}
+	end = end.Add(eventWindow)
+
+	entries := []entry{}
+	events, err := kinteract.GetEvents(ctx, repository.GetNamespace(), "Repository", repository.GetName())
+	if err != nil {
+		return nil, err
+	}
+	for _, ev := range events.Items {
+		if ev.InvolvedObject.Kind != "Repository" || ev.InvolvedObject.Name != repository.GetName() {
+			continue
+		}
+		t := eventTime(ev)
+		inWindow := !start.IsZero() && !t.Before(start) && !t.After(end)
+		if !inWindow && !strings.Contains(ev.Message, opts.sha) {
+			continue
+		}
+		entries = append(entries, entry{time: t, source: "Event Repository", message: fmt.Sprintf("%s - %s", ev.Reason, ev.Message)})
+	}
+
+	for name := range prNames {
+		events, err := kinteract.GetEvents(ctx, repository.GetNamespace(), "PipelineRun", name)
+		if err != nil {
+			return nil, err
+		}
+		for _, ev := range events.Items {
+			if ev.InvolvedObject.Kind != "PipelineRun" || ev.InvolvedObject.Name != name {
+				continue
+			}
+			entries = append(entries, entry{time: eventTime(ev), source: "Event PipelineRun " + name, message: fmt.Sprintf("%s - %s", ev.Reason, ev.Message)})
+		}
+	}
+	return entries, nil
+}
+
+func eventTime(ev corev1.Event) time.Time {
+	if !ev.LastTimestamp.IsZero() {
+		return ev.LastTimestamp.Time
+	}
+	if !ev.EventTime.IsZero() {
+		return ev.EventTime.Time
+	}
+	return ev.GetCreationTimestamp().Time
+}
+
+func printTimeline(opts *traceOptions, repository *v1alpha1.Repository, entries []entry, warnings []string) error {
+	cs := opts.ioStreams.ColorScheme()
+	fmt.Fprintf(opts.ioStreams.Out, "%s %s %s %s/%s\n\n", cs.Bold("Trace of"), opts.sha, cs.Bold("on Repository"),
+		repository.GetNamespace(), repository.GetName())
+	w := tabwriter.NewWriter(opts.ioStreams.Out, 0, 5, 3, ' ', tabwriter.TabIndent)
+	fmt.Fprintln(w, "TIME\tSOURCE\tMESSAGE")
+	for _, e := range entries {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", e.time.UTC().Format(time.RFC3339), e.source, strings.ReplaceAll(e.message, "\n", " "))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	for _, warning := range warnings {
+		fmt.Fprintf(opts.ioStreams.Out, "\n%s %s\n", cs.WarningIcon(), warning)
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package trace
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v49/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	tcli "github.com/openshift-pipelines/pipelines-as-code/pkg/test/cli"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	knativeapis "knative.dev/pkg/apis"
+	knativeduckv1 "knative.dev/pkg/apis/duck/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestTrace(t *testing.T) {
+	ns := "ns"
+	sha := "abcdef123456"
+	now := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	mtime := func(d time.Duration) *metav1.Time {
+		return &metav1.Time{Time: now.Add(d)}
+	}
+	succeeded := knativeduckv1.Status{Conditions: knativeduckv1.Conditions{{
+		Type:   knativeapis.ConditionSucceeded,
+		Status: corev1.ConditionTrue,
+		Reason: "Succeeded",
+	}}}
+
+	repo := &v1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: ns},
+		Spec:       v1alpha1.RepositorySpec{URL: "https://github.com/owner/repo"},
+		Status: []v1alpha1.RepositoryRunStatus{
+			{
+				Status:          succeeded,
+				PipelineRunName: "pr-abcd",
+				SHA:             &sha,
+				CompletionTime:  mtime(3 * time.Minute),
+			},
+			{
+				Status:          succeeded,
+				PipelineRunName: "pr-other",
+				SHA:             github.String("otherSHA"),
+				CompletionTime:  mtime(time.Minute),
+			},
+		},
+	}
+	pruns := []*tektonv1.PipelineRun{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "pr-abcd",
+				Namespace:         ns,
+				CreationTimestamp: *mtime(0),
+				Labels: map[string]string{
+					keys.Repository: "repo",
+					keys.SHA:        sha,
+					keys.EventType:  "pull_request",
+					keys.Branch:     "main",
+				},
+			},
+			Status: tektonv1.PipelineRunStatus{
+				Status: succeeded,
+				PipelineRunStatusFields: tektonv1.PipelineRunStatusFields{
+					StartTime:      mtime(time.Minute),
+					CompletionTime: mtime(2 * time.Minute),
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pr-other",
+				Namespace: ns,
+				Labels: map[string]string{
+					keys.Repository: "repo",
+					keys.SHA:        "otherSHA",
+				},
+			},
+		},
+	}
+	events := []*corev1.Event{
+		{
+			ObjectMeta:     metav1.ObjectMeta{Name: "ev1", Namespace: ns},
+			InvolvedObject: corev1.ObjectReference{Kind: "PipelineRun", Name: "pr-abcd"},
+			Reason:         "Started",
+			Message:        "pipelinerun started",
+			LastTimestamp:  *mtime(time.Minute),
+		},
+		{
+			ObjectMeta:     metav1.ObjectMeta{Name: "ev2", Namespace: ns},
+			InvolvedObject: corev1.ObjectReference{Kind: "Repository", Name: "repo"},
+			Reason:         "RepositoryNoMatch",
+			Message:        "no match for another sha",
+			LastTimestamp:  *mtime(-time.Hour),
+		},
+	}
+
+	ctx, _ := rtesting.SetupFakeContext(t)
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+		Repositories: []*v1alpha1.Repository{repo},
+		PipelineRuns: pruns,
+		Events:       events,
+		Namespaces:   []*corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: ns}}},
+		ConfigMap: []*corev1.ConfigMap{{
+			ObjectMeta: metav1.ObjectMeta{Name: "pipelines-as-code-info", Namespace: "pipelines-as-code"},
+		}},
+	})
+	run := &params.Run{
+		Clients: clients.Clients{
+			PipelineAsCode: stdata.PipelineAsCode,
+			Tekton:         stdata.Pipeline,
+			Kube:           stdata.Kube,
+		},
+		Info: info.Info{Kube: info.KubeOpts{Namespace: ns}},
+	}
+
+	io, out := tcli.NewIOStream()
+	err := trace(ctx, &traceOptions{
+		run:          run,
+		ioStreams:    io,
+		repoName:     "repo",
+		pacNamespace: "pipelines-as-code",
+		sha:          "abcdef",
+	})
+	assert.NilError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Equal(t, lines[0], "Trace of abcdef on Repository ns/repo")
+	want := []string{
+		"2023-01-01T10:00:00Z   PipelineRun pr-abcd         created for the pull_request event on main",
+		"2023-01-01T10:01:00Z   PipelineRun pr-abcd         started",
+		"2023-01-01T10:01:00Z   Event PipelineRun pr-abcd   Started - pipelinerun started",
+		"2023-01-01T10:02:00Z   PipelineRun pr-abcd         completed: Succeeded",
+		"2023-01-01T10:03:00Z   Repository                  status of PipelineRun pr-abcd recorded: Succeeded",
+	}
+	assert.DeepEqual(t, lines[3:], want)
+
+	err = trace(ctx, &traceOptions{
+		run:          run,
+		ioStreams:    io,
+		repoName:     "repo",
+		pacNamespace: "pipelines-as-code",
+		sha:          "unknown",
+	})
+	assert.Error(t, err, "cannot find anything about the SHA unknown on repository repo")
+}
+
+func TestParseLogs(t *testing.T) {
+	logs := strings.Join([]string{
+		`2023-01-01T10:00:00.123Z {"level":"info","msg":"received event for abcdef","event-id":"delivery1"}`,
+		`2023-01-01T10:00:01Z {"level":"info","msg":"another sha 123456","event-id":"delivery2"}`,
+		`2023-01-01T10:00:02Z not a json line about abcdef`,
+		`no timestamp abcdef`,
+	}, "\n")
+	entries, err := parseLogs(strings.NewReader(logs), "controller", "abcdef")
+	assert.NilError(t, err)
+	assert.Equal(t, len(entries), 2)
+	assert.Equal(t, entries[0].message, "received event for abcdef (delivery delivery1)")
+	assert.Equal(t, entries[0].source, "log controller")
+	assert.Equal(t, entries[0].time, time.Date(2023, 1, 1, 10, 0, 0, 123000000, time.UTC))
+	assert.Equal(t, entries[1].message, "not a json line about abcdef")
+}
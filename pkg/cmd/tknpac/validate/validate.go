@@ -0,0 +1,258 @@
+package validate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/git"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/matcher"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/bitbucketcloud"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/bitbucketserver"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gitea"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gitlab"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/resolve"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/templates"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+const tektonDir = ".tekton"
+
+// providerEventHeaders is the header where each provider send the event type
+var providerEventHeaders = map[string]string{
+	"github":           "X-GitHub-Event",
+	"gitlab":           "X-Gitlab-Event",
+	"gitea":            "X-Gitea-Event-Type",
+	"bitbucket-cloud":  "X-Event-Key",
+	"bitbucket-server": "X-Event-Key",
+}
+
+var longhelp = fmt.Sprintf(`
+
+validate - check which PipelineRuns would match an event.
+
+Read the PipelineRuns in the .tekton directory (or the files passed with -f) and
+match them against the webhook payload passed with --against-payload, the same
+way the Pipelines as Code controller would do. Every PipelineRun is reported
+with the reason why it matched or not.
+
+The event type is the value of the event header sent by the provider, for
+example with GitHub:
+
+%s pac validate --against-payload payload.json --event-type pull_request
+
+or with GitLab:
+
+%s pac validate --against-payload payload.json --git-provider gitlab \
+	--event-type "Merge Request Hook"
+
+No token is generated and no call to the provider is made, expressions using
+the pathChanged CEL function will always evaluate to false.`, settings.TknBinaryName, settings.TknBinaryName)
+
+type validateOpts struct {
+	payloadFile  string
+	eventType    string
+	providerType string
+	filenames    []string
+}
+
+func Command(run *params.Run, ioStreams *cli.IOStreams) *cobra.Command {
+	opts := &validateOpts{}
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Long:  longhelp,
+		Short: "Check which PipelineRuns would match an event payload",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			if opts.payloadFile == "" {
+				return fmt.Errorf("you need to specify a payload file with --against-payload")
+			}
+			// only report error here on CLI
+			zaplog, err := zap.NewProduction(
+				zap.IncreaseLevel(zap.FatalLevel),
+			)
+			if err != nil {
+				return err
+			}
+			run.Clients.Log = zaplog.Sugar()
+			if len(opts.filenames) == 0 {
+				cwd, err := os.Getwd()
+				if err != nil {
+					return err
+				}
+				topdir := git.GetGitInfo(cwd).TopLevelPath
+				if topdir == "" {
+					topdir = cwd
+				}
+				opts.filenames = []string{filepath.Join(topdir, tektonDir)}
+			}
+			return validate(ctx, run, opts, ioStreams)
+		},
+		Annotations: map[string]string{
+			"commandType": "main",
+		},
+	}
+	cmd.Flags().StringVar(&opts.payloadFile, "against-payload", "",
+		"A file containing the webhook payload to match the PipelineRuns against")
+	cmd.Flags().StringVar(&opts.eventType, "event-type", "",
+		"The event type as sent by the provider in the event header (ie: pull_request, push, Merge Request Hook)")
+	cmd.Flags().StringVar(&opts.providerType, "git-provider", "github",
+		fmt.Sprintf("The git provider type of the payload (%s)", strings.Join(providerTypes(), ", ")))
+	cmd.Flags().StringSliceVarP(&opts.filenames, "filename", "f", []string{},
+		"Filename or directory containing the PipelineRuns, default to the .tekton directory of the repository")
+	return cmd
+}
+
+func providerTypes() []string {
+	return []string{"github", "gitlab", "gitea", "bitbucket-cloud", "bitbucket-server"}
+}
+
+func newProvider(providerType string) (provider.Interface, error) {
+	switch providerType {
+	case "github":
+		return github.New(), nil
+	case "gitlab":
+		return &gitlab.Provider{}, nil
+	case "gitea":
+		return &gitea.Provider{}, nil
+	case "bitbucket-cloud":
+		return &bitbucketcloud.Provider{}, nil
+	case "bitbucket-server":
+		return &bitbucketserver.Provider{}, nil
+	}
+	return nil, fmt.Errorf("unsupported git provider %s, supported providers are: %s", providerType, strings.Join(providerTypes(), ", "))
+}
+
+// localProvider wraps a provider which has no client set, we don't want to
+// reach the provider API when validating locally.
+type localProvider struct {
+	provider.Interface
+}
+
+func (localProvider) GetFiles(context.Context, *info.Event) ([]string, error) {
+	return nil, fmt.Errorf("changed files are not available when validating locally")
+}
+
+// ParsePayload parse a payload as the controller would do, the installation
+// is removed from GitHub payloads so we don't try to generate a token for it.
+func ParsePayload(ctx context.Context, run *params.Run, providerType, eventType string, payload []byte) (provider.Interface, *info.Event, error) {
+	vcx, err := newProvider(providerType)
+	if err != nil {
+		return nil, nil, err
+	}
+	if eventType == "" {
+		return nil, nil, fmt.Errorf("you need to specify the event type of the payload with --event-type")
+	}
+	vcx.SetLogger(run.Clients.Log)
+
+	if providerType == "github" {
+		var data map[string]interface{}
+		if err := json.Unmarshal(payload, &data); err != nil {
+			return nil, nil, fmt.Errorf("invalid payload: %w", err)
+		}
+		delete(data, "installation")
+		if payload, err = json.Marshal(data); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://localhost", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	request.Header.Set(providerEventHeaders[providerType], eventType)
+	event, err := vcx.ParsePayload(ctx, run, request, string(payload))
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot parse payload: %w", err)
+	}
+	if event == nil || event.TriggerTarget == "" {
+		return nil, nil, fmt.Errorf("event %s is not an event Pipelines as Code would act on", eventType)
+	}
+	return localProvider{vcx}, event, nil
+}
+
+func enumerateFiles(filenames []string) ([]string, error) {
+	files := []string{}
+	for _, path := range filenames {
+		stat, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !stat.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		if err := filepath.Walk(path, func(path string, fi os.FileInfo, err error) error {
+			if filepath.Ext(path) == ".yaml" || filepath.Ext(path) == ".yml" {
+				files = append(files, path)
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+func validate(ctx context.Context, run *params.Run, opts *validateOpts, ioStreams *cli.IOStreams) error {
+	if run.Info.Pac == nil {
+		run.Info.Pac = &info.PacOpts{Settings: &settings.Settings{}}
+	}
+	// we don't have a source ip to check locally
+	run.Info.Pac.BitbucketCloudCheckSourceIP = false
+
+	payload, err := os.ReadFile(opts.payloadFile)
+	if err != nil {
+		return err
+	}
+	vcx, event, err := ParsePayload(ctx, run, opts.providerType, opts.eventType, payload)
+	if err != nil {
+		return err
+	}
+
+	files, err := enumerateFiles(opts.filenames)
+	if err != nil {
+		return err
+	}
+
+	cs := ioStreams.ColorScheme()
+	fmt.Fprintf(ioStreams.Out, "%s Event: %s, Target Branch: %s, Source Branch: %s\n", cs.InfoIcon(),
+		cs.Bold(event.TriggerTarget), cs.Bold(event.BaseBranch), cs.Bold(event.HeadBranch))
+	matched := 0
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		data := templates.Process(event, &v1alpha1.Repository{}, string(b))
+		pruns, err := resolve.PipelineRuns(ctx, run.Clients.Log, data)
+		if err != nil {
+			fmt.Fprintf(ioStreams.Out, "%s %s: %s\n", cs.FailureIcon(), file, err.Error())
+			continue
+		}
+		for _, explanation := range matcher.ExplainMatch(ctx, pruns, event, vcx) {
+			icon := cs.FailureIcon()
+			if explanation.Matched {
+				icon = cs.SuccessIcon()
+				matched++
+			}
+			fmt.Fprintf(ioStreams.Out, "%s %s (%s): %s\n", icon, cs.Bold(explanation.PipelineRun), file, explanation.Reason)
+		}
+	}
+	if matched == 0 {
+		fmt.Fprintf(ioStreams.Out, "%s No PipelineRun would be triggered by this event\n", cs.WarningIcon())
+	}
+	return nil
+}
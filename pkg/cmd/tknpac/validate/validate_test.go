@@ -0,0 +1,154 @@
+package validate
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	tcli "github.com/openshift-pipelines/pipelines-as-code/pkg/test/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/test/logger"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+)
+
+const pullRequestPayload = `{
+  "action": "opened",
+  "number": 1,
+  "pull_request": {
+    "number": 1,
+    "head": {"sha": "abcdef", "ref": "feature"},
+    "base": {"ref": "main", "repo": {"id": 1}},
+    "user": {"login": "sender"}
+  },
+  "repository": {
+    "name": "repo",
+    "html_url": "https://github.com/owner/repo",
+    "default_branch": "main",
+    "owner": {"login": "owner"}
+  },
+  "installation": {"id": 12345}
+}`
+
+const pullRequestPipelineRun = `---
+apiVersion: tekton.dev/v1beta1
+kind: PipelineRun
+metadata:
+  name: pull-request
+  annotations:
+    pipelinesascode.tekton.dev/on-event: "[pull_request]"
+    pipelinesascode.tekton.dev/on-target-branch: "[{{ target_branch }}]"
+spec:
+  pipelineSpec:
+    tasks:
+      - name: task
+        taskSpec:
+          steps:
+            - name: step
+              image: alpine
+              script: "echo hello"
+`
+
+const pushPipelineRun = `---
+apiVersion: tekton.dev/v1beta1
+kind: PipelineRun
+metadata:
+  name: push
+  annotations:
+    pipelinesascode.tekton.dev/on-event: "[push]"
+    pipelinesascode.tekton.dev/on-target-branch: "[main]"
+spec:
+  pipelineSpec:
+    tasks:
+      - name: task
+        taskSpec:
+          steps:
+            - name: step
+              image: alpine
+              script: "echo hello"
+`
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name         string
+		providerType string
+		eventType    string
+		files        map[string]string
+		wantErr      string
+		wantOutput   []string
+	}{
+		{
+			name:         "match pull request",
+			providerType: "github",
+			eventType:    "pull_request",
+			files: map[string]string{
+				"pull-request.yaml": pullRequestPipelineRun,
+				"push.yaml":         pushPipelineRun,
+			},
+			wantOutput: []string{
+				"✓ pull-request",
+				"X push",
+				"does not match the event pull_request",
+			},
+		},
+		{
+			name:         "no match",
+			providerType: "github",
+			eventType:    "pull_request",
+			files: map[string]string{
+				"push.yaml": pushPipelineRun,
+			},
+			wantOutput: []string{
+				"No PipelineRun would be triggered by this event",
+			},
+		},
+		{
+			name:         "unknown provider",
+			providerType: "nowhere",
+			eventType:    "pull_request",
+			wantErr:      "unsupported git provider nowhere",
+		},
+		{
+			name:         "no event type",
+			providerType: "github",
+			wantErr:      "you need to specify the event type",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ops := []fs.PathOp{fs.WithFile("payload.json", pullRequestPayload)}
+			tektonOps := []fs.PathOp{}
+			for name, content := range tt.files {
+				tektonOps = append(tektonOps, fs.WithFile(name, content))
+			}
+			ops = append(ops, fs.WithDir(".tekton", tektonOps...))
+			dir := fs.NewDir(t, "validate", ops...)
+			defer dir.Remove()
+
+			log, _ := logger.GetLogger()
+			run := &params.Run{
+				Clients: clients.Clients{Log: log},
+				Info:    info.Info{Pac: &info.PacOpts{Settings: &settings.Settings{}}},
+			}
+			io, out := tcli.NewIOStream()
+			opts := &validateOpts{
+				payloadFile:  dir.Join("payload.json"),
+				eventType:    tt.eventType,
+				providerType: tt.providerType,
+				filenames:    []string{dir.Join(".tekton")},
+			}
+			err := validate(context.Background(), run, opts, io)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			for _, want := range tt.wantOutput {
+				assert.Assert(t, strings.Contains(out.String(), want), "%s not in output: %s", want, out.String())
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/client-go/dynamic"
 )
 
@@ -49,3 +50,25 @@ func New(ctx context.Context, kdyn dynamic.Interface, _ *info.Info) Interface {
 	// TODO: Try to detect TektonDashboard somehow by ingress?
 	return FallBackConsole{}
 }
+
+// OpenShiftConsoleHost returns the host of the OpenShift console of the
+// cluster, empty when the cluster doesn't have one.
+func OpenShiftConsoleHost(ctx context.Context, kdyn dynamic.Interface) (string, error) {
+	oc := &OpenshiftConsole{}
+	if err := oc.UI(ctx, kdyn); err != nil {
+		if kerrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return oc.host, nil
+}
+
+// FromOpenShiftConsoleHost returns the OpenShift console at host, the fallback
+// console when host is empty.
+func FromOpenShiftConsoleHost(host string) Interface {
+	if host == "" {
+		return FallBackConsole{}
+	}
+	return &OpenshiftConsole{host: host}
+}
@@ -36,3 +36,29 @@ func TestFallbackConsole(t *testing.T) {
 	assert.Assert(t, fbc.DetailURL(pr) != "")
 	assert.Assert(t, fbc.TaskLogURL(pr, trStatus) != "")
 }
+
+func TestOpenShiftConsoleHost(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	route := &unstructured.Unstructured{}
+	route.SetUnstructuredContent(map[string]interface{}{
+		"apiVersion": "route.openshift.io/v1",
+		"kind":       "Route",
+		"metadata": map[string]interface{}{
+			"name":      "console",
+			"namespace": "openshift-console",
+		},
+		"spec": map[string]interface{}{
+			"host": "console.example.com",
+		},
+	})
+
+	host, err := OpenShiftConsoleHost(ctx, dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), route))
+	assert.NilError(t, err)
+	assert.Equal(t, host, "console.example.com")
+	assert.Equal(t, FromOpenShiftConsoleHost(host).URL(), "https://console.example.com")
+
+	host, err = OpenShiftConsoleHost(ctx, dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()))
+	assert.NilError(t, err)
+	assert.Equal(t, host, "")
+	assert.Equal(t, FromOpenShiftConsoleHost(host).URL(), consoleIsnotConfiguredURL)
+}
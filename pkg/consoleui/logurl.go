@@ -0,0 +1,28 @@
+package consoleui
+
+import (
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/templates"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+)
+
+// RunLogURL returns the URL where the logs of the PipelineRun can be seen,
+// this is the URL recorded in the Repository status so it has to stay valid
+// after the PipelineRun has been pruned from the cluster.
+//
+// The archive URL takes precedence when it is configured since it outlives
+// the PipelineRun object, then the log URL annotated on the PipelineRun when
+// it was created, and finally the detail URL of the console.
+func RunLogURL(ui Interface, archiveURL string, pr *tektonv1.PipelineRun) string {
+	if archiveURL != "" {
+		return templates.ReplacePlaceHoldersVariables(archiveURL, map[string]string{
+			"namespace": pr.GetNamespace(),
+			"pr":        pr.GetName(),
+			"uid":       string(pr.GetUID()),
+		})
+	}
+	if logURL := pr.GetAnnotations()[keys.LogURL]; logURL != "" {
+		return logURL
+	}
+	return ui.DetailURL(pr)
+}
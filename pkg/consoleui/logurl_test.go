@@ -0,0 +1,49 @@
+package consoleui
+
+import (
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRunLogURL(t *testing.T) {
+	tests := []struct {
+		name        string
+		archiveURL  string
+		annotations map[string]string
+		want        string
+	}{
+		{
+			name: "console detail url",
+			want: "https://dashboard/#/namespaces/ns/pipelineruns/pr",
+		},
+		{
+			name:        "annotated log url",
+			annotations: map[string]string{keys.LogURL: "https://recorded/pr"},
+			want:        "https://recorded/pr",
+		},
+		{
+			name:        "archive url",
+			archiveURL:  "https://archive/{{ namespace }}/{{ pr }}/{{ uid }}",
+			annotations: map[string]string{keys.LogURL: "https://recorded/pr"},
+			want:        "https://archive/ns/pr/1234",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr := &tektonv1.PipelineRun{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   "ns",
+					Name:        "pr",
+					UID:         "1234",
+					Annotations: tt.annotations,
+				},
+			}
+			ui := &TektonDashboard{BaseURL: "https://dashboard"}
+			assert.Equal(t, RunLogURL(ui, tt.archiveURL, pr), tt.want)
+		})
+	}
+}
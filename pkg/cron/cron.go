@@ -0,0 +1,172 @@
+// Package cron parses the standard five fields cron expressions and computes
+// the next time they fire.
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxSearch bounds the search of the next time a schedule fires, an
+// expression like 0 0 30 2 * never fires.
+const maxSearch = 5 * 366 * 24 * time.Hour
+
+// macros are the shortcuts for the common schedules.
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@nightly":  "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// field is the allowed range of a field of the expression.
+type field struct {
+	name     string
+	min, max int
+}
+
+var fields = []field{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12},
+	{name: "day of week", min: 0, max: 7},
+}
+
+// Schedule is a parsed cron expression, the fields are the sets of values
+// they match.
+type Schedule struct {
+	minutes, hours, days, months, weekdays map[int]bool
+	// anyDay and anyWeekday are true when the fields are *, when both day
+	// fields are restricted a time matching either of them matches.
+	anyDay, anyWeekday bool
+}
+
+// Parse parses a cron expression made of the minute, hour, day of month,
+// month and day of week fields. A field is a * or a comma separated list of
+// values or ranges with an optional step, ie: */15, 1-5 or 0,30. The day of
+// week is 0 or 7 for Sunday. The @hourly, @daily, @nightly, @weekly, @monthly
+// and @yearly macros are supported.
+func Parse(expr string) (*Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if macro, ok := macros[expr]; ok {
+		expr = macro
+	}
+	parts := strings.Fields(expr)
+	if len(parts) != len(fields) {
+		return nil, fmt.Errorf("invalid cron expression %q, it needs five fields: minute hour day-of-month month day-of-week", expr)
+	}
+	sets := make([]map[int]bool, len(fields))
+	for i, part := range parts {
+		set, err := parseField(part, fields[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+		}
+		sets[i] = set
+	}
+	if sets[4][7] {
+		sets[4][0] = true
+	}
+	return &Schedule{
+		minutes:    sets[0],
+		hours:      sets[1],
+		days:       sets[2],
+		months:     sets[3],
+		weekdays:   sets[4],
+		anyDay:     parts[2] == "*",
+		anyWeekday: parts[4] == "*",
+	}, nil
+}
+
+func parseField(part string, f field) (map[int]bool, error) {
+	set := map[int]bool{}
+	for _, item := range strings.Split(part, ",") {
+		rng, step := item, 1
+		if i := strings.Index(item, "/"); i >= 0 {
+			s, err := strconv.Atoi(item[i+1:])
+			if err != nil || s <= 0 {
+				return nil, fmt.Errorf("invalid step in %s field %q", f.name, item)
+			}
+			rng, step = item[:i], s
+		}
+		low, high := f.min, f.max
+		switch {
+		case rng == "*":
+		case strings.Contains(rng, "-"):
+			bounds := strings.SplitN(rng, "-", 2)
+			var err error
+			if low, err = parseValue(bounds[0], f); err != nil {
+				return nil, err
+			}
+			if high, err = parseValue(bounds[1], f); err != nil {
+				return nil, err
+			}
+			if low > high {
+				return nil, fmt.Errorf("invalid range in %s field %q", f.name, item)
+			}
+		default:
+			v, err := parseValue(rng, f)
+			if err != nil {
+				return nil, err
+			}
+			low = v
+			// a single value with a step runs from the value to the end
+			if step == 1 {
+				high = v
+			}
+		}
+		for v := low; v <= high; v += step {
+			set[v] = true
+		}
+	}
+	return set, nil
+}
+
+func parseValue(s string, f field) (int, error) {
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid %s %q, it needs to be between %d and %d", f.name, s, f.min, f.max)
+	}
+	return v, nil
+}
+
+func (s *Schedule) matchDay(t time.Time) bool {
+	day, weekday := s.days[t.Day()], s.weekdays[int(t.Weekday())]
+	switch {
+	case s.anyDay && s.anyWeekday:
+		return true
+	case s.anyDay:
+		return weekday
+	case s.anyWeekday:
+		return day
+	}
+	return day || weekday
+}
+
+// Next returns the first time after t the schedule fires, in the location of
+// t, or the zero time if it never fires.
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(maxSearch)
+	for t.Before(limit) {
+		switch {
+		case !s.months[int(t.Month())]:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.matchDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case !s.hours[t.Hour()]:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case !s.minutes[t.Minute()]:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,79 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		wantErr string
+	}{
+		{name: "every minute", expr: "* * * * *"},
+		{name: "lists ranges and steps", expr: "*/15 1-5,22 1,15 */2 1-5"},
+		{name: "macro", expr: "@daily"},
+		{name: "sunday as 7", expr: "0 0 * * 7"},
+		{
+			name:    "missing field",
+			expr:    "0 0 * *",
+			wantErr: `invalid cron expression "0 0 * *", it needs five fields: minute hour day-of-month month day-of-week`,
+		},
+		{
+			name:    "out of range",
+			expr:    "60 * * * *",
+			wantErr: `invalid cron expression "60 * * * *": invalid minute "60", it needs to be between 0 and 59`,
+		},
+		{
+			name:    "invalid step",
+			expr:    "*/0 * * * *",
+			wantErr: `invalid cron expression "*/0 * * * *": invalid step in minute field "*/0"`,
+		},
+		{
+			name:    "inverted range",
+			expr:    "* 5-1 * * *",
+			wantErr: `invalid cron expression "* 5-1 * * *": invalid range in hour field "5-1"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.expr)
+			if tt.wantErr != "" {
+				assert.Error(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+		})
+	}
+}
+
+func TestNext(t *testing.T) {
+	// a Wednesday
+	from := time.Date(2023, 3, 15, 10, 30, 20, 0, time.UTC)
+	tests := []struct {
+		name string
+		expr string
+		want time.Time
+	}{
+		{name: "every minute", expr: "* * * * *", want: time.Date(2023, 3, 15, 10, 31, 0, 0, time.UTC)},
+		{name: "every quarter", expr: "*/15 * * * *", want: time.Date(2023, 3, 15, 10, 45, 0, 0, time.UTC)},
+		{name: "nightly", expr: "0 2 * * *", want: time.Date(2023, 3, 16, 2, 0, 0, 0, time.UTC)},
+		{name: "daily macro", expr: "@daily", want: time.Date(2023, 3, 16, 0, 0, 0, 0, time.UTC)},
+		{name: "weekly on sunday", expr: "0 0 * * 7", want: time.Date(2023, 3, 19, 0, 0, 0, 0, time.UTC)},
+		{name: "next month", expr: "0 0 1 * *", want: time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "next year", expr: "30 6 1 1 *", want: time.Date(2024, 1, 1, 6, 30, 0, 0, time.UTC)},
+		{name: "day of month or day of week", expr: "0 0 20 * 5", want: time.Date(2023, 3, 17, 0, 0, 0, 0, time.UTC)},
+		{name: "leap day", expr: "0 0 29 2 *", want: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{name: "never", expr: "0 0 30 2 *"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := Parse(tt.expr)
+			assert.NilError(t, err)
+			assert.Equal(t, schedule.Next(from), tt.want)
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package formatting
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+)
+
+// MarkdownTaskStatusTemplate is the task status template rendering the
+// TaskRuns as a GitHub flavoured markdown table, for the providers which don't
+// support HTML in their comments.
+const MarkdownTaskStatusTemplate = `| **Status** | **Duration** | **Name** |
+| --- | --- | --- |
+{{range $taskrun := .TaskRunList }}|{{ formatCondition $taskrun.PipelineRunTaskRunStatus.Status.Conditions }}|{{ formatDuration $taskrun.PipelineRunTaskRunStatus.Status.StartTime $taskrun.PipelineRunTaskRunStatus.Status.CompletionTime }}|{{ $taskrun.ConsoleLogURL }}|
+{{ end }}`
+
+const runSummaryTemplate = `### {{ formatCondition .Status.Status.Conditions }} {{ cell (runName .Status) }}
+
+| | |
+| --- | --- |
+| **Repository** | [{{ .Repository.Namespace }}/{{ .Repository.Name }}]({{ .Repository.Spec.URL }}) |
+| **Event** | {{ cell (deref .Status.EventType) }} |
+| **Branch** | {{ cell (sanitizeBranch (deref .Status.TargetBranch)) }} |
+| **Commit** | {{ link (shortSHA (deref .Status.SHA)) (deref .Status.SHAURL) }}{{ with .Status.Title }} {{ cell (deref .) }}{{ end }} |
+| **Started** | {{ if .Status.StartTime }}{{ .Status.StartTime.UTC.Format "2006-01-02T15:04:05Z07:00" }}{{ else }}---{{ end }} |
+| **Duration** | {{ formatDuration .Status }} |
+| **Logs** | {{ link (deref .Status.LogURL) (deref .Status.LogURL) }} |
+{{- if and .Status.CollectedTaskInfos (gt (len .Status.CollectedTaskInfos) 0) }}
+
+#### Failures
+{{ range $name, $task := .Status.CollectedTaskInfos }}
+**{{ $name }}**{{ if ne $task.Reason "Failed" }} ({{ $task.Reason }}){{ end }}
+{{ if eq $task.LogSnippet "" }}
+{{ $task.Message }}
+{{ else }}
+` + "```" + `
+{{ $task.LogSnippet }}
+` + "```" + `
+{{ end }}
+{{- end }}
+{{- end }}
+`
+
+// markdownCell escapes the characters breaking a markdown table cell.
+func markdownCell(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nonAttributedStr
+	}
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+func markdownLink(title, href string) string {
+	if href == "" {
+		return markdownCell(title)
+	}
+	return "[" + markdownCell(title) + "](" + href + ")"
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// MarkdownRunSummary renders the summary of a run of the repository as GitHub
+// flavoured markdown, it is used by tkn pac describe -o markdown for the users
+// to paste it anywhere markdown is supported. The comments posted by the
+// providers use their TaskStatusTMPL instead.
+func MarkdownRunSummary(repository *v1alpha1.Repository, status v1alpha1.RepositoryRunStatus) (string, error) {
+	funcMap := template.FuncMap{
+		"formatCondition": ConditionEmoji,
+		"cell":            markdownCell,
+		"link":            markdownLink,
+		"deref":           deref,
+		"sanitizeBranch":  SanitizeBranch,
+		"shortSHA":        ShortSHA,
+		"formatDuration":  PRDuration,
+		"runName":         RunName,
+	}
+	data := struct {
+		Repository *v1alpha1.Repository
+		Status     v1alpha1.RepositoryRunStatus
+	}{
+		Repository: repository,
+		Status:     status,
+	}
+	out := bytes.Buffer{}
+	t := template.Must(template.New("Run Summary").Funcs(funcMap).Parse(runSummaryTemplate))
+	if err := t.Execute(&out, data); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
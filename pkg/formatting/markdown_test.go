@@ -0,0 +1,69 @@
+package formatting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/golden"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	knativeapis "knative.dev/pkg/apis"
+	knativeduckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+func TestMarkdownRunSummary(t *testing.T) {
+	str := func(s string) *string { return &s }
+	started := time.Date(2023, 1, 2, 10, 0, 0, 0, time.UTC)
+	repository := &v1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"},
+		Spec:       v1alpha1.RepositorySpec{URL: "https://github.com/owner/repo"},
+	}
+	tests := []struct {
+		name   string
+		status v1alpha1.RepositoryRunStatus
+	}{
+		{
+			name: "succeeded",
+			status: v1alpha1.RepositoryRunStatus{
+				Status: knativeduckv1.Status{
+					Conditions: []knativeapis.Condition{{Status: corev1.ConditionTrue, Reason: "Succeeded"}},
+				},
+				PipelineRunName: "pr-abcde",
+				StartTime:       &metav1.Time{Time: started},
+				CompletionTime:  &metav1.Time{Time: started.Add(2 * time.Minute)},
+				SHA:             str("0123456789abcdef"),
+				SHAURL:          str("https://github.com/owner/repo/commit/0123456789abcdef"),
+				Title:           str("fix | the pipe"),
+				LogURL:          str("https://console/ns/pr-abcde"),
+				TargetBranch:    str("refs/heads/main"),
+				EventType:       str("pull_request"),
+			},
+		},
+		{
+			name: "failed",
+			status: v1alpha1.RepositoryRunStatus{
+				Status: knativeduckv1.Status{
+					Conditions: []knativeapis.Condition{{Status: corev1.ConditionFalse, Reason: "Failed"}},
+				},
+				PipelineRunName: "pr-fghij",
+				StartTime:       &metav1.Time{Time: started},
+				CompletionTime:  &metav1.Time{Time: started.Add(time.Minute)},
+				SHA:             str("0123456789abcdef"),
+				EventType:       str("push"),
+				CollectedTaskInfos: &map[string]v1alpha1.TaskInfos{
+					"lint": {Reason: "Failed", LogSnippet: "main.go:1:1: ERROR: syntax error"},
+					"test": {Reason: "TaskRunTimeout", Message: "timed out after 1h"},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MarkdownRunSummary(repository, tt.status)
+			assert.NilError(t, err)
+			golden.Assert(t, got, "markdown-"+tt.name+".golden")
+		})
+	}
+}
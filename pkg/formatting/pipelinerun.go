@@ -1,6 +1,9 @@
 package formatting
 
 import (
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	corev1 "k8s.io/api/core/v1"
 )
@@ -15,3 +18,9 @@ func PipelineRunStatus(pr *tektonv1.PipelineRun) string {
 	}
 	return "success"
 }
+
+// PipelineRunDisplayName returns the display name a PipelineRun has been given
+// with its display-name annotation, or an empty string if it doesn't have one.
+func PipelineRunDisplayName(pr *tektonv1.PipelineRun) string {
+	return strings.TrimSpace(pr.GetAnnotations()[keys.DisplayName])
+}
@@ -3,6 +3,8 @@ package formatting
 import (
 	"testing"
 
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	"gotest.tools/v3/assert"
 	corev1 "k8s.io/api/core/v1"
@@ -64,3 +66,10 @@ func TestPipelineRunStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestPipelineRunDisplayName(t *testing.T) {
+	pr := &tektonv1.PipelineRun{}
+	assert.Equal(t, PipelineRunDisplayName(pr), "")
+	pr.SetAnnotations(map[string]string{keys.DisplayName: "  Unit tests\n"})
+	assert.Equal(t, PipelineRunDisplayName(pr), "Unit tests")
+}
@@ -20,8 +20,25 @@ func ShowStatus(repository v1alpha1.Repository, cs *cli.ColorScheme) string {
 		return cs.ColorStatus("NoRun")
 	}
 	status := repository.Status[len(repository.Status)-1].Status.Conditions[0].GetReason()
-	logurl := repository.Status[len(repository.Status)-1].LogURL
-	return cs.HyperLink(cs.ColorStatus(status), *logurl)
+	return cs.HyperLink(cs.ColorStatus(status), LogURL(repository.Status[len(repository.Status)-1]))
+}
+
+// LogURL returns the log URL of the run, statuses recorded by older versions
+// may not have one.
+func LogURL(status v1alpha1.RepositoryRunStatus) string {
+	if status.LogURL == nil {
+		return ""
+	}
+	return *status.LogURL
+}
+
+// RunName returns the name a run is shown with: the display name of its
+// PipelineRun if it has one or the name of the PipelineRun.
+func RunName(status v1alpha1.RepositoryRunStatus) string {
+	if status.DisplayName != "" {
+		return status.DisplayName
+	}
+	return status.PipelineRunName
 }
 
 func ShowLastAge(repository v1alpha1.Repository, cw clockwork.Clock) string {
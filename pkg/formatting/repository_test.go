@@ -135,3 +135,15 @@ func TestShowStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestRunName(t *testing.T) {
+	cw := clockwork.NewFakeClock()
+	status := makeRepoStatus("pr-abcde", "sha1", "Success", cw, -20*time.Minute, -25*time.Minute)
+	if got := RunName(status); got != "pr-abcde" {
+		t.Errorf("RunName() = %v, want %v", got, "pr-abcde")
+	}
+	status.DisplayName = "Unit tests"
+	if got := RunName(status); got != "Unit tests" {
+		t.Errorf("RunName() = %v, want %v", got, "Unit tests")
+	}
+}
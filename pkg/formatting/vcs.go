@@ -42,8 +42,12 @@ func GetRepoOwnerFromURL(ghURL string) (string, error) {
 	return strings.ToLower(fmt.Sprintf("%s/%s", org, repo)), nil
 }
 
+// GetRepoOwnerSplitted returns the owner and the repository name of a
+// repository URL, the owner includes all the groups and subgroups on GitLab,
+// ie: https://gitlab.com/group/sub/sub2/project returns group/sub/sub2 and
+// project.
 func GetRepoOwnerSplitted(u string) (string, string, error) {
-	uparse, err := url.Parse(u)
+	uparse, err := url.Parse(NormalizeRepoURL(u))
 	if err != nil {
 		return "", "", err
 	}
@@ -56,6 +60,16 @@ func GetRepoOwnerSplitted(u string) (string, string, error) {
 	return org, repo, nil
 }
 
+// NormalizeRepoURL removes the trailing slash and the .git suffix of a
+// repository URL so the URL of a Repository CR can be compared to the one
+// coming from the git provider.
+func NormalizeRepoURL(u string) string {
+	u = strings.TrimSpace(u)
+	u = strings.TrimSuffix(u, "/")
+	u = strings.TrimSuffix(u, ".git")
+	return strings.TrimSuffix(u, "/")
+}
+
 // CamelCasit pull_request > PullRequest
 func CamelCasit(s string) string {
 	c := cases.Title(language.AmericanEnglish)
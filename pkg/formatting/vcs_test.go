@@ -53,6 +53,12 @@ func TestGetRepoOwnerSplitted(t *testing.T) {
 			retOrg:  "foo/bar/owner",
 			retRepo: "repo",
 		},
+		{
+			name:    "good/parse url gitlab nested subgroups",
+			url:     "https://forge/group/sub/sub2/sub3/project.git/",
+			retOrg:  "group/sub/sub2/sub3",
+			retRepo: "project",
+		},
 		{
 			name:    "bad/no org/repo in url",
 			url:     "https://forge/repo",
@@ -207,3 +213,39 @@ func TestShortSHA(t *testing.T) {
 		})
 	}
 }
+
+func TestNormalizeRepoURL(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{
+			name: "already normalized",
+			url:  "https://forge/group/sub/project",
+			want: "https://forge/group/sub/project",
+		},
+		{
+			name: "trailing slash",
+			url:  "https://forge/group/sub/project/",
+			want: "https://forge/group/sub/project",
+		},
+		{
+			name: "git suffix",
+			url:  "https://forge/group/sub/project.git",
+			want: "https://forge/group/sub/project",
+		},
+		{
+			name: "git suffix and trailing slash",
+			url:  " https://forge/group/sub/project.git/ ",
+			want: "https://forge/group/sub/project",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeRepoURL(tt.url); got != tt.want {
+				t.Errorf("NormalizeRepoURL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
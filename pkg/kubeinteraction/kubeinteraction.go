@@ -16,6 +16,8 @@ type Interface interface {
 	CleanupPipelines(context.Context, *zap.SugaredLogger, *v1alpha1.Repository, *pipelinev1.PipelineRun, int) error
 	CreateSecret(ctx context.Context, ns string, secret *corev1.Secret) error
 	UpdateSecretWithOwnerRef(context.Context, *zap.SugaredLogger, string, string, *pipelinev1.PipelineRun) error
+	AttachSecretToServiceAccount(ctx context.Context, ns, serviceAccount, secretName string) error
+	DetachSecretFromServiceAccount(ctx context.Context, ns, serviceAccount, secretName string) error
 	GetSecret(context.Context, ktypes.GetSecretOpt) (string, error)
 	GetPodLogs(context.Context, string, string, string, int64) (string, error)
 }
@@ -1,7 +1,9 @@
 package kubeinteraction
 
 import (
+	"regexp"
 	"strconv"
+	"strings"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
@@ -9,16 +11,26 @@ import (
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/version"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/templates"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
+var reInvalidLabelValueChars = regexp.MustCompile(`[^-A-Za-z0-9_.]`)
+
 const (
-	StateStarted   = "started"
-	StateQueued    = "queued"
-	StateCompleted = "completed"
-	StateFailed    = "failed"
+	StateStarted     = "started"
+	StateQueued      = "queued"
+	StateWaiting     = "waiting"     // waiting for the PipelineRuns of its run-after annotation to succeed
+	StateMaintenance = "maintenance" // waiting for the end of the maintenance window it has been created in
+	StateCompleted   = "completed"
+	StateFailed      = "failed"
 )
 
+// QueuePriorityHigh is the value of the queue priority annotation of the
+// PipelineRuns started before the others in the concurrency queue.
+const QueuePriorityHigh = "high"
+
 func AddLabelsAndAnnotations(event *info.Event, pipelineRun *tektonv1.PipelineRun, repo *apipac.Repository, providerinfo *info.ProviderConfig) {
 	// Add labels on the soon to be created pipelinerun so UI/CLI can easily
 	// query them.
@@ -71,3 +83,42 @@ func AddLabelsAndAnnotations(event *info.Event, pipelineRun *tektonv1.PipelineRu
 		pipelineRun.Annotations[k] = v
 	}
 }
+
+// AddLabelsAndAnnotationsFromSettings adds the labels and annotations
+// configured in the settings to the PipelineRun, their templates are
+// processed with the dynamic variables of the event. They never override the
+// labels and annotations already set and they are skipped when a variable is
+// not defined for the event (ie: the pull request labels on a push), the
+// labels values are sanitized to be valid.
+func AddLabelsAndAnnotationsFromSettings(event *info.Event, pipelineRun *tektonv1.PipelineRun, repo *apipac.Repository, labels, annotations map[string]string) {
+	for k, tmpl := range labels {
+		if _, ok := pipelineRun.Labels[k]; ok {
+			continue
+		}
+		value := templates.Process(event, repo, tmpl)
+		if strings.Contains(value, "{{") {
+			continue
+		}
+		if value = labelValue(value); value != "" {
+			pipelineRun.Labels[k] = value
+		}
+	}
+	for k, tmpl := range annotations {
+		if _, ok := pipelineRun.Annotations[k]; ok {
+			continue
+		}
+		if value := templates.Process(event, repo, tmpl); !strings.Contains(value, "{{") {
+			pipelineRun.Annotations[k] = value
+		}
+	}
+}
+
+// labelValue replaces the characters not allowed in a label value by an
+// underscore and truncates it to the maximum length of a label value.
+func labelValue(s string) string {
+	s = reInvalidLabelValueChars.ReplaceAllString(s, "_")
+	if len(s) > validation.LabelValueMaxLength {
+		s = s[:validation.LabelValueMaxLength]
+	}
+	return strings.Trim(s, "-_.")
+}
@@ -1,6 +1,7 @@
 package kubeinteraction
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
@@ -57,3 +58,46 @@ func TestAddLabelsAndAnnotations(t *testing.T) {
 		})
 	}
 }
+
+func TestAddLabelsAndAnnotationsFromSettings(t *testing.T) {
+	event := info.NewEvent()
+	event.Organization = "Org"
+	event.Sender = "sender"
+	event.BaseBranch = "refs/heads/main"
+	event.PullRequestLabel = []string{"team/infra", "needs review"}
+
+	pipelineRun := &tektonv1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels:      map[string]string{"existing": "value"},
+			Annotations: map[string]string{},
+		},
+	}
+	labels := map[string]string{
+		"team":      "{{ pull_request_labels }}",
+		"owner":     "{{ repo_owner }}-{{ target_branch }}",
+		"existing":  "{{ sender }}",
+		"undefined": "{{ pull_request_number }}",
+	}
+	annotations := map[string]string{
+		"example.com/labels": "{{ pull_request_labels }}",
+		"example.com/sender": "by {{ sender }}",
+	}
+	AddLabelsAndAnnotationsFromSettings(event, pipelineRun, &apipac.Repository{}, labels, annotations)
+
+	assert.DeepEqual(t, pipelineRun.Labels, map[string]string{
+		"existing": "value",
+		"team":     "team_infra_needs_review",
+		"owner":    "org-main",
+	})
+	assert.DeepEqual(t, pipelineRun.Annotations, map[string]string{
+		"example.com/labels": "team/infra,needs review",
+		"example.com/sender": "by sender",
+	})
+}
+
+func TestLabelValue(t *testing.T) {
+	assert.Equal(t, labelValue("-hello world-"), "hello_world")
+	assert.Equal(t, labelValue("{{ }}"), "")
+	long := labelValue(strings.Repeat("a", 70))
+	assert.Equal(t, len(long), 63)
+}
@@ -67,3 +67,49 @@ func (k Interaction) CreateSecret(ctx context.Context, ns string, secret *corev1
 	_, err := k.Run.Clients.Kube.CoreV1().Secrets(ns).Create(ctx, secret, metav1.CreateOptions{})
 	return err
 }
+
+// AttachSecretToServiceAccount adds the secret to the secrets of the
+// ServiceAccount, Tekton initializes the credentials of the steps from the
+// secrets of the ServiceAccount they run with.
+func (k Interaction) AttachSecretToServiceAccount(ctx context.Context, ns, serviceAccount, secretName string) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		sa, err := k.Run.Clients.Kube.CoreV1().ServiceAccounts(ns).Get(ctx, serviceAccount, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		for _, secret := range sa.Secrets {
+			if secret.Name == secretName {
+				return nil
+			}
+		}
+		sa.Secrets = append(sa.Secrets, corev1.ObjectReference{Name: secretName})
+		_, err = k.Run.Clients.Kube.CoreV1().ServiceAccounts(ns).Update(ctx, sa, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// DetachSecretFromServiceAccount removes the secret from the secrets of the
+// ServiceAccount, a ServiceAccount that doesn't exist anymore is ignored.
+func (k Interaction) DetachSecretFromServiceAccount(ctx context.Context, ns, serviceAccount, secretName string) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		sa, err := k.Run.Clients.Kube.CoreV1().ServiceAccounts(ns).Get(ctx, serviceAccount, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		secrets := []corev1.ObjectReference{}
+		for _, secret := range sa.Secrets {
+			if secret.Name != secretName {
+				secrets = append(secrets, secret)
+			}
+		}
+		if len(secrets) == len(sa.Secrets) {
+			return nil
+		}
+		sa.Secrets = secrets
+		_, err = k.Run.Clients.Kube.CoreV1().ServiceAccounts(ns).Update(ctx, sa, metav1.UpdateOptions{})
+		return err
+	})
+}
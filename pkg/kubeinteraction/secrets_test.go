@@ -108,3 +108,36 @@ func TestUpdateSecretWithOwnerRef(t *testing.T) {
 	assert.Equal(t, updatedSecret.OwnerReferences[0].Kind, "PipelineRun")
 	assert.Equal(t, updatedSecret.OwnerReferences[0].Name, pr.Name)
 }
+
+func TestAttachSecretToServiceAccount(t *testing.T) {
+	testNs := "there"
+	ctx, _ := rtesting.SetupFakeContext(t)
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{})
+	_, err := stdata.Kube.CoreV1().ServiceAccounts(testNs).Create(ctx, &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNs, Name: "builder"},
+		Secrets:    []corev1.ObjectReference{{Name: "registry"}},
+	}, metav1.CreateOptions{})
+	assert.NilError(t, err)
+	kint := Interaction{
+		Run: &params.Run{
+			Clients: clients.Clients{
+				Kube: stdata.Kube,
+			},
+		},
+	}
+
+	assert.NilError(t, kint.AttachSecretToServiceAccount(ctx, testNs, "builder", "pac-gitauth-abcd"))
+	// attaching twice doesn't duplicate the secret
+	assert.NilError(t, kint.AttachSecretToServiceAccount(ctx, testNs, "builder", "pac-gitauth-abcd"))
+	sa, err := stdata.Kube.CoreV1().ServiceAccounts(testNs).Get(ctx, "builder", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, sa.Secrets, []corev1.ObjectReference{{Name: "registry"}, {Name: "pac-gitauth-abcd"}})
+
+	assert.NilError(t, kint.DetachSecretFromServiceAccount(ctx, testNs, "builder", "pac-gitauth-abcd"))
+	sa, err = stdata.Kube.CoreV1().ServiceAccounts(testNs).Get(ctx, "builder", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, sa.Secrets, []corev1.ObjectReference{{Name: "registry"}})
+
+	assert.ErrorContains(t, kint.AttachSecretToServiceAccount(ctx, testNs, "missing", "pac-gitauth-abcd"), "not found")
+	assert.NilError(t, kint.DetachSecretFromServiceAccount(ctx, testNs, "missing", "pac-gitauth-abcd"))
+}
@@ -0,0 +1,87 @@
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"unicode/utf8"
+
+	pacv1alpha1 "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+)
+
+// the summary of a run is kept in the status of the Repository with the
+// summaries of the other last runs, they are capped to keep the Repository
+// small whatever the size of the PipelineRun is
+const (
+	maxSummaryTaskRuns       = 50
+	maxSummaryResults        = 20
+	maxSummaryResultValueLen = 256
+)
+
+// CollectTaskRunsSummary returns the name, the reason and the times of the
+// tasks of the PipelineRun ordered by the time they started.
+func CollectTaskRunsSummary(ctx context.Context, cs *params.Run, pr *tektonv1.PipelineRun) []pacv1alpha1.TaskRunSummary {
+	summaries := []pacv1alpha1.TaskRunSummary{}
+	if pr == nil {
+		return summaries
+	}
+	for _, task := range GetStatusFromTaskStatusOrFromAsking(ctx, pr, cs) {
+		if task.Status == nil {
+			continue
+		}
+		summary := pacv1alpha1.TaskRunSummary{
+			Name:           task.PipelineTaskName,
+			StartTime:      task.Status.StartTime,
+			CompletionTime: task.Status.CompletionTime,
+		}
+		if len(task.Status.Conditions) > 0 {
+			summary.Reason = task.Status.Conditions[0].Reason
+		}
+		summaries = append(summaries, summary)
+	}
+	sort.SliceStable(summaries, func(i, j int) bool {
+		ti, tj := summaries[i].StartTime, summaries[j].StartTime
+		switch {
+		// the tasks which haven't started go last
+		case ti.IsZero() != tj.IsZero():
+			return !ti.IsZero()
+		case !ti.Equal(tj):
+			return ti.Before(tj)
+		}
+		return summaries[i].Name < summaries[j].Name
+	})
+	if len(summaries) > maxSummaryTaskRuns {
+		summaries = summaries[:maxSummaryTaskRuns]
+	}
+	return summaries
+}
+
+// CollectResults returns the results emitted by the PipelineRun, the array
+// and object results are formatted as json and the long values are
+// truncated.
+func CollectResults(pr *tektonv1.PipelineRun) []pacv1alpha1.RunResult {
+	results := []pacv1alpha1.RunResult{}
+	if pr == nil {
+		return results
+	}
+	for _, result := range pr.Status.Results {
+		if len(results) == maxSummaryResults {
+			break
+		}
+		value := result.Value.StringVal
+		if result.Value.Type != tektonv1.ParamTypeString {
+			b, err := json.Marshal(result.Value)
+			if err != nil {
+				continue
+			}
+			value = string(b)
+		}
+		if utf8.RuneCountInString(value) > maxSummaryResultValueLen {
+			value = string([]rune(value)[:maxSummaryResultValueLen-1]) + "…"
+		}
+		results = append(results, pacv1alpha1.RunResult{Name: result.Name, Value: value})
+	}
+	return results
+}
@@ -0,0 +1,69 @@
+package status
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	paramclients "github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	tektontest "github.com/openshift-pipelines/pipelines-as-code/pkg/test/tekton"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"gotest.tools/v3/assert"
+	"k8s.io/apimachinery/pkg/runtime"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestCollectTaskRunsSummary(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	pr := tektontest.MakePRCompletion(clock, "pr", "ns", tektonv1.PipelineRunReasonFailed.String(), map[string]string{}, 30)
+	for _, name := range []string{"unit-tests", "build"} {
+		pr.Status.ChildReferences = append(pr.Status.ChildReferences, tektonv1.ChildStatusReference{
+			TypeMeta:         runtime.TypeMeta{Kind: "TaskRun"},
+			Name:             "pr-" + name,
+			PipelineTaskName: name,
+		})
+	}
+	tdata := testclient.Data{
+		TaskRuns: []*tektonv1.TaskRun{
+			tektontest.MakeTaskRunCompletion(clock, "pr-build", "ns", "Succeeded", map[string]string{}, tektonv1.TaskRunStatusFields{}, nil, 10),
+			tektontest.MakeTaskRunCompletion(clock, "pr-unit-tests", "ns", "Failed", map[string]string{}, tektonv1.TaskRunStatusFields{}, nil, 20),
+		},
+	}
+	ctx, _ := rtesting.SetupFakeContext(t)
+	stdata, _ := testclient.SeedTestData(t, ctx, tdata)
+	cs := &params.Run{Clients: paramclients.Clients{Tekton: stdata.Pipeline}}
+
+	got := CollectTaskRunsSummary(ctx, cs, pr)
+	assert.Equal(t, len(got), 2)
+	assert.Equal(t, got[0].Name, "build")
+	assert.Equal(t, got[0].Reason, "Succeeded")
+	assert.Equal(t, got[1].Name, "unit-tests")
+	assert.Equal(t, got[1].Reason, "Failed")
+	assert.Assert(t, got[1].StartTime != nil && got[1].CompletionTime != nil)
+
+	assert.Equal(t, len(CollectTaskRunsSummary(ctx, cs, nil)), 0)
+}
+
+func TestCollectResults(t *testing.T) {
+	pr := &tektonv1.PipelineRun{}
+	pr.Status.Results = []tektonv1.PipelineRunResult{
+		{Name: "image", Value: *tektonv1.NewStructuredValues("quay.io/org/app@sha256:1234")},
+		{Name: "tags", Value: *tektonv1.NewStructuredValues("latest", "v1")},
+		{Name: "report", Value: *tektonv1.NewStructuredValues(strings.Repeat("a", 300))},
+	}
+	for i := 0; i < maxSummaryResults; i++ {
+		pr.Status.Results = append(pr.Status.Results, tektonv1.PipelineRunResult{
+			Name: fmt.Sprintf("result-%d", i), Value: *tektonv1.NewStructuredValues("value"),
+		})
+	}
+
+	got := CollectResults(pr)
+	assert.Equal(t, len(got), maxSummaryResults)
+	assert.Equal(t, got[0].Value, "quay.io/org/app@sha256:1234")
+	assert.Equal(t, got[1].Value, `["latest","v1"]`)
+	assert.Equal(t, len([]rune(got[2].Value)), maxSummaryResultValueLen)
+	assert.Assert(t, strings.HasSuffix(got[2].Value, "…"))
+}
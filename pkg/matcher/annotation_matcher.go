@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/gobwas/glob"
@@ -81,38 +82,47 @@ func getAnnotationValues(annotation string) ([]string, error) {
 	return splitted, nil
 }
 
-func getTargetBranch(prun *tektonv1.PipelineRun, logger *zap.SugaredLogger, event *info.Event) (bool, string, string, error) {
-	var targetEvent, targetBranch string
-	if key, ok := prun.GetObjectMeta().GetAnnotations()[keys.OnEvent]; ok {
-		targetEvent = event.TriggerTarget
-		if event.EventType == "incoming" {
-			targetEvent = "incoming"
-		}
-		matched, err := matchOnAnnotation(key, targetEvent, false)
-		targetEvent = key
-		if err != nil {
-			return false, "", "", err
-		}
-		if !matched {
-			return false, "", "", nil
-		}
+// targetEventType returns the event type matched against the on-event
+// annotation.
+func targetEventType(event *info.Event) string {
+	if event.EventType == "incoming" {
+		return "incoming"
 	}
-	if key, ok := prun.GetObjectMeta().GetAnnotations()[keys.OnTargetBranch]; ok {
-		matched, err := matchOnAnnotation(key, event.BaseBranch, true)
-		targetBranch = key
-		if err != nil {
-			return false, "", "", err
-		}
-		if !matched {
-			return false, "", "", nil
-		}
+	if event.CustomEventType != "" {
+		return event.CustomEventType
 	}
+	return event.TriggerTarget
+}
+
+// approvalsCount counts the approvals of the pull request of the event, the
+// approvals are only counted once for all the PipelineRuns.
+type approvalsCount struct {
+	count   int
+	counted bool
+}
 
-	if targetEvent == "" || targetBranch == "" {
-		logger.Infof("skipping pipelinerun %s, no on-target-event or on-target-branch has been set in pipelinerun", prun.GetGenerateName())
-		return false, "", "", nil
+// enoughApprovals returns true if the pull request of the event has at least
+// the number of approvals of the min-approvals annotation, it is always true
+// for the events which aren't about a pull request.
+func (a *approvalsCount) enoughApprovals(ctx context.Context, minApprovals string, event *info.Event, vcx provider.Interface) (bool, int, error) {
+	minimum, err := strconv.Atoi(minApprovals)
+	if err != nil || minimum < 0 {
+		return false, 0, fmt.Errorf("annotation %s has an invalid value %q, it needs to be a positive number", keys.MinApprovals, minApprovals)
 	}
-	return true, targetEvent, targetBranch, nil
+	if event.TriggerTarget != "pull_request" || minimum == 0 {
+		return true, minimum, nil
+	}
+	if !a.counted {
+		counter, ok := vcx.(provider.ApprovalsCounter)
+		if !ok {
+			return false, minimum, fmt.Errorf("annotation %s is not supported on this git provider", keys.MinApprovals)
+		}
+		if a.count, err = counter.CountApprovals(ctx, event); err != nil {
+			return false, minimum, fmt.Errorf("cannot count the approvals of the pull request: %w", err)
+		}
+		a.counted = true
+	}
+	return a.count >= minimum, minimum, nil
 }
 
 type Match struct {
@@ -124,6 +134,7 @@ type Match struct {
 func MatchPipelinerunByAnnotation(ctx context.Context, logger *zap.SugaredLogger, pruns []*tektonv1.PipelineRun, cs *params.Run, event *info.Event, vcx provider.Interface) ([]Match, error) {
 	matchedPRs := []Match{}
 	configurations := map[string]map[string]string{}
+	approvals := &approvalsCount{}
 	logger.Infof("matching pipelineruns to event: URL=%s, target-branch=%s, source-branch=%s, target-event=%s",
 		event.URL,
 		event.BaseBranch,
@@ -131,76 +142,123 @@ func MatchPipelinerunByAnnotation(ctx context.Context, logger *zap.SugaredLogger
 		event.TriggerTarget)
 
 	for _, prun := range pruns {
-		prMatch := Match{
-			PipelineRun: prun,
-			Config:      map[string]string{},
+		prMatch, matched, reason, err := matchPipelineRun(ctx, prun, cs, event, vcx, approvals)
+		if err != nil {
+			return matchedPRs, err
 		}
-
-		if event.TargetPipelineRun != "" && event.TargetPipelineRun == strings.TrimSuffix(prun.GetGenerateName(), "-") {
-			logger.Infof("matched target pipelinerun with name: %s, annotation Config: %q", prun.GetGenerateName(), prMatch.Config)
-			matchedPRs = append(matchedPRs, prMatch)
+		if !matched {
+			logger.Infof("skipping pipelinerun %s: %s", prun.GetGenerateName(), reason)
 			continue
 		}
+		logger.Infof("matched pipelinerun with name: %s, %s, annotation Config: %q", prun.GetGenerateName(), reason, prMatch.Config)
+		matchedPRs = append(matchedPRs, prMatch)
+	}
 
-		if prun.GetObjectMeta().GetAnnotations() == nil {
-			logger.Warnf("PipelineRun %s does not have any annotations", prun.GetName())
-			continue
-		}
+	if len(matchedPRs) > 0 {
+		return matchedPRs, nil
+	}
 
-		if maxPrNumber, ok := prun.GetObjectMeta().GetAnnotations()[keys.MaxKeepRuns]; ok {
-			prMatch.Config["max-keep-runs"] = maxPrNumber
-		}
+	logger.Warn("could not find a match to a pipelinerun matching payload: hint: check your yaml files are correct")
+	logger.Warn("available configuration in pipelineRuns annotations")
+	for name, maps := range configurations {
+		logger.Infof("pipelineRun: %s, target-branch=%s, target-event=%s",
+			name, maps["target-branch"], maps["target-event"])
+	}
+
+	// TODO: more descriptive error message
+	return nil, fmt.Errorf("cannot match pipeline from webhook to pipelineruns on event=%s, branch=%s",
+		event.EventType, event.BaseBranch)
+}
+
+// matchPipelineRun matches a single PipelineRun against the event and returns
+// the reason of why it matched or not, the reason is used for the logs of the
+// matcher and to explain the match to the user. The Repository targeted by the
+// target-namespace annotation is only looked up when cs is set, it is
+// otherwise reported as needed in the reason. An error is only returned when
+// the on-event or on-target-branch annotations are invalid.
+func matchPipelineRun(ctx context.Context, prun *tektonv1.PipelineRun, cs *params.Run, event *info.Event, vcx provider.Interface, approvals *approvalsCount) (Match, bool, string, error) {
+	prMatch := Match{
+		PipelineRun: prun,
+		Config:      map[string]string{},
+	}
+
+	if event.TargetPipelineRun != "" && event.TargetPipelineRun == strings.TrimSuffix(prun.GetGenerateName(), "-") {
+		return prMatch, true, "explicitly targeted by the incoming webhook", nil
+	}
+
+	annotations := prun.GetObjectMeta().GetAnnotations()
+	if annotations == nil {
+		return prMatch, false, "no annotations has been set on the PipelineRun", nil
+	}
+
+	if maxPrNumber, ok := annotations[keys.MaxKeepRuns]; ok {
+		prMatch.Config["max-keep-runs"] = maxPrNumber
+	}
 
-		if targetNS, ok := prun.GetObjectMeta().GetAnnotations()[keys.TargetNamespace]; ok {
-			prMatch.Config["target-namespace"] = targetNS
+	var targetNSReason string
+	if targetNS, ok := annotations[keys.TargetNamespace]; ok {
+		prMatch.Config["target-namespace"] = targetNS
+		if cs == nil {
+			targetNSReason = fmt.Sprintf(", a Repository in the namespace %s is needed", targetNS)
+		} else {
 			prMatch.Repo, _ = MatchEventURLRepo(ctx, cs, event, targetNS)
 			if prMatch.Repo == nil {
-				logger.Warnf("could not find Repository CRD in branch %s, the pipelineRun %s has a label that explicitly targets it", targetNS, prun.GetGenerateName())
-				continue
+				return prMatch, false, fmt.Sprintf("could not find a Repository matching the event URL in the namespace %s targeted by the %s annotation", targetNS, keys.TargetNamespace), nil
 			}
 		}
+	}
 
-		if celExpr, ok := prun.GetObjectMeta().GetAnnotations()[keys.OnCelExpression]; ok {
-			out, err := celEvaluate(ctx, celExpr, event, vcx)
+	var reason string
+	if celExpr, ok := annotations[keys.OnCelExpression]; ok {
+		out, err := celEvaluate(ctx, celExpr, event, vcx)
+		if err != nil {
+			return prMatch, false, fmt.Sprintf("error evaluating %s annotation: %s", keys.OnCelExpression, err.Error()), nil
+		}
+		if out != types.True {
+			return prMatch, false, fmt.Sprintf("%s annotation %q has been evaluated to false", keys.OnCelExpression, celExpr), nil
+		}
+		reason = fmt.Sprintf("%s annotation %q has been evaluated to true", keys.OnCelExpression, celExpr)
+	} else {
+		onEvent, hasOnEvent := annotations[keys.OnEvent]
+		onTargetBranch, hasOnTargetBranch := annotations[keys.OnTargetBranch]
+		if hasOnEvent {
+			targetEvent := targetEventType(event)
+			matched, err := matchOnAnnotation(onEvent, targetEvent, false)
 			if err != nil {
-				logger.Errorf("there was an error evaluating CEL expression, skipping: %w", err)
-				continue
+				return prMatch, false, "", fmt.Errorf("%s annotation is invalid: %w", keys.OnEvent, err)
 			}
-			if out != types.True {
-				logger.Warnf("CEL expression is not matching %s, skipping", prun.GetGenerateName())
-				continue
+			if !matched {
+				return prMatch, false, fmt.Sprintf("%s annotation %s does not match the event %s", keys.OnEvent, onEvent, targetEvent), nil
 			}
-			logger.Infof("CEL expression has been evaluated and matched")
-		} else {
-			matched, targetEvent, targetBranch, err := getTargetBranch(prun, logger, event)
+		}
+		if hasOnTargetBranch {
+			matched, err := matchOnAnnotation(onTargetBranch, event.BaseBranch, true)
 			if err != nil {
-				return matchedPRs, err
+				return prMatch, false, "", fmt.Errorf("%s annotation is invalid: %w", keys.OnTargetBranch, err)
 			}
 			if !matched {
-				continue
+				return prMatch, false, fmt.Sprintf("%s annotation %s does not match the branch %s", keys.OnTargetBranch, onTargetBranch, event.BaseBranch), nil
 			}
-			prMatch.Config["target-branch"] = targetBranch
-			prMatch.Config["target-event"] = targetEvent
 		}
-
-		logger.Infof("matched pipelinerun with name: %s, annotation Config: %q", prun.GetGenerateName(), prMatch.Config)
-		matchedPRs = append(matchedPRs, prMatch)
-	}
-
-	if len(matchedPRs) > 0 {
-		return matchedPRs, nil
+		if !hasOnEvent || !hasOnTargetBranch {
+			return prMatch, false, fmt.Sprintf("no %s or %s annotation has been set", keys.OnEvent, keys.OnTargetBranch), nil
+		}
+		prMatch.Config["target-branch"] = onTargetBranch
+		prMatch.Config["target-event"] = onEvent
+		reason = fmt.Sprintf("%s annotation %s and %s annotation %s match the event", keys.OnEvent, onEvent, keys.OnTargetBranch, onTargetBranch)
 	}
 
-	logger.Warn("could not find a match to a pipelinerun matching payload: hint: check your yaml files are correct")
-	logger.Warn("available configuration in pipelineRuns annotations")
-	for name, maps := range configurations {
-		logger.Infof("pipelineRun: %s, target-branch=%s, target-event=%s",
-			name, maps["target-branch"], maps["target-event"])
+	if minApprovals, ok := annotations[keys.MinApprovals]; ok {
+		enough, minimum, err := approvals.enoughApprovals(ctx, minApprovals, event, vcx)
+		if err != nil {
+			return prMatch, false, err.Error(), nil
+		}
+		if !enough {
+			return prMatch, false, fmt.Sprintf("%s annotation requires %d approvals and the pull request has %d", keys.MinApprovals, minimum, approvals.count), nil
+		}
 	}
 
-	// TODO: more descriptive error message
-	return nil, fmt.Errorf("cannot match pipeline from webhook to pipelineruns on event=%s, branch=%s",
-		event.EventType, event.BaseBranch)
+	return prMatch, true, reason + targetNSReason, nil
 }
 
 func matchOnAnnotation(annotations, eventType string, branchMatching bool) (bool, error) {
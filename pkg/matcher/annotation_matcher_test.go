@@ -23,6 +23,7 @@ import (
 	gltesthelper "github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gitlab/test"
 	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
 	ghtesthelper "github.com/openshift-pipelines/pipelines-as-code/pkg/test/github"
+	testprovider "github.com/openshift-pipelines/pipelines-as-code/pkg/test/provider"
 	testnewrepo "github.com/openshift-pipelines/pipelines-as-code/pkg/test/repository"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	"github.com/xanzy/go-gitlab"
@@ -451,6 +452,114 @@ func TestMatchPipelinerunAnnotationAndRepositories(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:         "match same webhook on multiple repos routes to the matching routing",
+			wantPRName:   pipelineTargetNSName,
+			wantRepoName: "test-routed",
+			args: annotationTestArgs{
+				pruns: []*tektonv1.PipelineRun{pipelineTargetNS},
+				runevent: info.Event{
+					URL: targetURL, TriggerTarget: "pull_request", EventType: "pull_request",
+					BaseBranch: mainBranch,
+				},
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "test-oldest",
+								URL:              targetURL,
+								InstallNamespace: targetNamespace,
+								CreateTime:       metav1.Time{Time: cw.Now().Add(-55 * time.Minute)},
+							},
+						),
+						func() *v1alpha1.Repository {
+							repo := testnewrepo.NewRepo(
+								testnewrepo.RepoTestcreationOpts{
+									Name:             "test-routed",
+									URL:              targetURL,
+									InstallNamespace: targetNamespace,
+									CreateTime:       metav1.Time{Time: cw.Now().Add(-50 * time.Minute)},
+								},
+							)
+							repo.Spec.Routing = &v1alpha1.Routing{EventTypes: []string{"pull_request"}, Branches: []string{mainBranch}}
+							return repo
+						}(),
+					},
+				},
+			},
+		},
+		{
+			name:         "match same webhook on multiple repos falls back when the routing does not match",
+			wantPRName:   pipelineTargetNSName,
+			wantRepoName: "test-oldest",
+			args: annotationTestArgs{
+				pruns: []*tektonv1.PipelineRun{pipelineTargetNS},
+				runevent: info.Event{
+					URL: targetURL, TriggerTarget: "pull_request", EventType: "pull_request",
+					BaseBranch: mainBranch,
+				},
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "test-oldest",
+								URL:              targetURL,
+								InstallNamespace: targetNamespace,
+								CreateTime:       metav1.Time{Time: cw.Now().Add(-55 * time.Minute)},
+							},
+						),
+						func() *v1alpha1.Repository {
+							repo := testnewrepo.NewRepo(
+								testnewrepo.RepoTestcreationOpts{
+									Name:             "test-routed",
+									URL:              targetURL,
+									InstallNamespace: targetNamespace,
+									CreateTime:       metav1.Time{Time: cw.Now().Add(-50 * time.Minute)},
+								},
+							)
+							repo.Spec.Routing = &v1alpha1.Routing{EventTypes: []string{"pull_request"}, Branches: []string{"release-*"}}
+							return repo
+						}(),
+					},
+				},
+			},
+		},
+		{
+			name:         "match same webhook on multiple repos ignores the routing of another namespace",
+			wantPRName:   pipelineTargetNSName,
+			wantRepoName: "test-oldest",
+			args: annotationTestArgs{
+				pruns: []*tektonv1.PipelineRun{pipelineTargetNS},
+				runevent: info.Event{
+					URL: targetURL, TriggerTarget: "pull_request", EventType: "pull_request",
+					BaseBranch: mainBranch,
+				},
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "test-oldest",
+								URL:              targetURL,
+								InstallNamespace: targetNamespace,
+								CreateTime:       metav1.Time{Time: cw.Now().Add(-55 * time.Minute)},
+							},
+						),
+						func() *v1alpha1.Repository {
+							repo := testnewrepo.NewRepo(
+								testnewrepo.RepoTestcreationOpts{
+									Name:             "test-takeover",
+									URL:              targetURL,
+									InstallNamespace: "other",
+									CreateTime:       metav1.Time{Time: cw.Now().Add(-50 * time.Minute)},
+								},
+							)
+							repo.Spec.Routing = &v1alpha1.Routing{Branches: []string{"*"}}
+							return repo
+						}(),
+					},
+				},
+			},
+		},
 		{
 			name:    "error on only when on annotation",
 			wantErr: true,
@@ -911,6 +1020,34 @@ func TestMatchPipelinerunByAnnotation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "custom-event-type-match",
+			args: args{
+				pruns: []*tektonv1.PipelineRun{
+					pipelineGood,
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "pipeline-approved",
+							Annotations: map[string]string{
+								keys.OnEvent:        "[pr-approved]",
+								keys.OnTargetBranch: "[main]",
+							},
+						},
+					},
+				},
+				runevent: info.Event{TriggerTarget: "pull_request", EventType: "pull_request_review", CustomEventType: "pr-approved", BaseBranch: "main"},
+			},
+			wantErr:    false,
+			wantPrName: "pipeline-approved",
+		},
+		{
+			name: "custom-event-type-no-match",
+			args: args{
+				pruns:    []*tektonv1.PipelineRun{pipelineGood},
+				runevent: info.Event{TriggerTarget: "pull_request", EventType: "pull_request_review", CustomEventType: "pr-approved", BaseBranch: "main"},
+			},
+			wantErr: true,
+		},
 		{
 			name: "ref-heads-main-push-rerequested-case",
 			args: args{
@@ -1016,3 +1153,74 @@ func Test_getAnnotationValues(t *testing.T) {
 		})
 	}
 }
+
+type approvalsProvider struct {
+	testprovider.TestProviderImp
+	approvals int
+	calls     int
+}
+
+func (a *approvalsProvider) CountApprovals(_ context.Context, _ *info.Event) (int, error) {
+	a.calls++
+	return a.approvals, nil
+}
+
+func TestMatchPipelinerunMinApprovals(t *testing.T) {
+	makePR := func(name, minApprovals string) *tektonv1.PipelineRun {
+		return &tektonv1.PipelineRun{
+			ObjectMeta: metav1.ObjectMeta{
+				GenerateName: name + "-",
+				Annotations: map[string]string{
+					keys.OnEvent:        "[pull_request_approved, push]",
+					keys.OnTargetBranch: "[main]",
+					keys.MinApprovals:   minApprovals,
+				},
+			},
+		}
+	}
+	pruns := []*tektonv1.PipelineRun{makePR("one", "1"), makePR("two", "2"), makePR("invalid", "two")}
+
+	tests := []struct {
+		name      string
+		event     info.Event
+		approvals int
+		want      []string
+		wantCalls int
+	}{
+		{
+			name:      "one approval",
+			event:     info.Event{TriggerTarget: "pull_request", CustomEventType: "pull_request_approved", BaseBranch: "main"},
+			approvals: 1,
+			want:      []string{"one-"},
+			wantCalls: 1,
+		},
+		{
+			name:      "two approvals",
+			event:     info.Event{TriggerTarget: "pull_request", CustomEventType: "pull_request_approved", BaseBranch: "main"},
+			approvals: 2,
+			want:      []string{"one-", "two-"},
+			wantCalls: 1,
+		},
+		{
+			name:  "push events are not affected",
+			event: info.Event{TriggerTarget: "push", BaseBranch: "main"},
+			want:  []string{"one-", "two-"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			observer, _ := zapobserver.New(zap.InfoLevel)
+			logger := zap.New(observer).Sugar()
+			vcx := &approvalsProvider{approvals: tt.approvals}
+			matches, err := MatchPipelinerunByAnnotation(ctx, logger, pruns, &params.Run{}, &tt.event, vcx)
+			assert.NilError(t, err)
+			got := []string{}
+			for _, match := range matches {
+				got = append(got, match.PipelineRun.GetGenerateName())
+			}
+			assert.DeepEqual(t, got, tt.want)
+			assert.Equal(t, vcx.calls, tt.wantCalls)
+		})
+	}
+}
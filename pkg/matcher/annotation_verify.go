@@ -0,0 +1,121 @@
+package matcher
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+)
+
+// knownAnnotations are the Pipelines as Code annotations a PipelineRun can
+// have, the ones set by the controller are known too since they end up in
+// the PipelineRuns copied from the cluster.
+var knownAnnotations = []string{
+	keys.OnEvent, keys.OnTargetBranch, keys.OnCelExpression, keys.TargetNamespace,
+	keys.MaxKeepRuns, keys.CancelInProgress, keys.MinApprovals, keys.RunAfter,
+	keys.Task, keys.Pipeline,
+	keys.URLOrg, keys.URLRepository, keys.SHA, keys.Sender, keys.EventType,
+	keys.Branch, keys.Repository, keys.GitProvider, keys.State, keys.ShaTitle,
+	keys.ShaURL, keys.RepoURL, keys.PullRequest, keys.InstallationID, keys.GHEURL,
+	keys.SourceProjectID, keys.TargetProjectID, keys.OriginalPRName, keys.GitAuthSecret,
+	keys.GitAuthServiceAccount, keys.CheckRunID, keys.LogURL, keys.ExecutionOrder,
+	keys.OverriddenBy, keys.OverrideReason, keys.RunAfterPipelineRuns, keys.Schedule,
+	keys.ScheduledAt, keys.SourceFile, keys.SourceLines, keys.PinnedImages,
+	keys.DisplayName, keys.Deployment, keys.DeploymentID, keys.Retries, keys.RetryAttempt,
+	keys.QueuePriority, keys.Arch, keys.NodePool, keys.ClusterPipeline,
+	keys.ControllerVersion, keys.TaskDigests, keys.SettingsSnapshot, keys.SettingsDigest,
+}
+
+// remoteAnnotationsRe matches the numbered remote task and pipeline
+// annotations, ie: task-1.
+var remoteAnnotationsRe = regexp.MustCompile(fmt.Sprintf("^%s/(%s|%s)",
+	regexp.QuoteMeta(pipelinesascode.GroupName), taskAnnotationsRegexp, pipelineAnnotationsRegexp))
+
+// VerifyAnnotations checks the Pipelines as Code annotations of the
+// PipelineRuns are known, the unknown ones are most likely typos which would
+// otherwise silently prevent the PipelineRuns from matching. The error lists
+// every unknown annotation with the known annotation it is closest to.
+func VerifyAnnotations(prs []*tektonv1.PipelineRun) error {
+	problems := []string{}
+	for _, pr := range prs {
+		unknown := []string{}
+		for key := range pr.GetAnnotations() {
+			if !strings.HasPrefix(key, pipelinesascode.GroupName+"/") || isKnownAnnotation(key) {
+				continue
+			}
+			unknown = append(unknown, key)
+		}
+		sort.Strings(unknown)
+		for _, key := range unknown {
+			problem := fmt.Sprintf("PipelineRun %s has an unknown annotation %s", pipelineRunName(pr), key)
+			if suggestion := closestAnnotation(key); suggestion != "" {
+				problem += fmt.Sprintf(", did you mean %s?", suggestion)
+			}
+			problems = append(problems, problem)
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s", strings.Join(problems, "\n"))
+}
+
+func isKnownAnnotation(key string) bool {
+	for _, known := range knownAnnotations {
+		if key == known {
+			return true
+		}
+	}
+	return remoteAnnotationsRe.MatchString(key)
+}
+
+// closestAnnotation returns the known annotation with the fewest edits from
+// the key, or an empty string if none is close enough. An edit is allowed for
+// every four characters of the name so the short names don't get suggested
+// unrelated annotations.
+func closestAnnotation(key string) string {
+	name := strings.TrimPrefix(key, pipelinesascode.GroupName+"/")
+	maxEdits := len(name) / 4
+	if maxEdits == 0 {
+		maxEdits = 1
+	}
+	closest, distance := "", maxEdits+1
+	for _, known := range knownAnnotations {
+		if d := editDistance(name, strings.TrimPrefix(known, pipelinesascode.GroupName+"/")); d < distance {
+			closest, distance = known, d
+		}
+	}
+	return closest
+}
+
+// editDistance is the Levenshtein distance between two strings.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(minInt(prev[j]+1, cur[j-1]+1), prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,74 @@
+package matcher
+
+import (
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestVerifyAnnotations(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		wantErr     string
+	}{
+		{
+			name: "known annotations",
+			annotations: map[string]string{
+				keys.OnEvent:        "[pull_request]",
+				keys.OnTargetBranch: "[main]",
+				keys.Task:           "git-clone",
+				keys.Task + "-2":    "pylint",
+				keys.Pipeline:       "https://remote/pipeline.yaml",
+				keys.MaxKeepRuns:    "5",
+			},
+		},
+		{
+			name: "other annotations are ignored",
+			annotations: map[string]string{
+				"tekton.dev/pipelines.minVersion": "0.12.1",
+				"team":                            "ci",
+			},
+		},
+		{
+			name: "typo is suggested the closest annotation",
+			annotations: map[string]string{
+				"pipelinesascode.tekton.dev/on-evnt": "[pull_request]",
+				keys.OnTargetBranch:                  "[main]",
+			},
+			wantErr: "PipelineRun pr has an unknown annotation pipelinesascode.tekton.dev/on-evnt, did you mean pipelinesascode.tekton.dev/on-event?",
+		},
+		{
+			name: "unknown annotations without a close one",
+			annotations: map[string]string{
+				"pipelinesascode.tekton.dev/team":               "ci",
+				"pipelinesascode.tekton.dev/on-target-branches": "[main]",
+			},
+			wantErr: "PipelineRun pr has an unknown annotation pipelinesascode.tekton.dev/on-target-branches, did you mean pipelinesascode.tekton.dev/on-target-branch?\n" +
+				"PipelineRun pr has an unknown annotation pipelinesascode.tekton.dev/team",
+		},
+		{
+			name: "remote task with a name is unknown",
+			annotations: map[string]string{
+				"pipelinesascode.tekton.dev/task-lint": "pylint",
+			},
+			wantErr: "PipelineRun pr has an unknown annotation pipelinesascode.tekton.dev/task-lint",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prs := []*tektonv1.PipelineRun{{
+				ObjectMeta: metav1.ObjectMeta{GenerateName: "pr-", Annotations: tt.annotations},
+			}}
+			err := VerifyAnnotations(prs)
+			if tt.wantErr == "" {
+				assert.NilError(t, err)
+				return
+			}
+			assert.Error(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package matcher
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+)
+
+// UnsupportedCapabilities returns a warning for each PipelineRun with an
+// annotation relying on a capability the provider type doesn't support.
+func UnsupportedCapabilities(providerType string, prs []*tektonv1.PipelineRun) []string {
+	warnings := []string{}
+	for _, pr := range prs {
+		celExpr := pr.GetAnnotations()[keys.OnCelExpression]
+		if strings.Contains(celExpr, ".pathChanged(") && !provider.HasCapability(providerType, provider.CapabilityPathFiltering) {
+			warnings = append(warnings, fmt.Sprintf(
+				"PipelineRun %s uses pathChanged in its %s annotation but the %s provider doesn't support %s, no path is ever seen as changed",
+				pipelineRunName(pr), keys.OnCelExpression, provider.NormalizeProviderType(providerType), provider.CapabilityPathFiltering))
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,25 @@
+package matcher
+
+import (
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestUnsupportedCapabilities(t *testing.T) {
+	prs := []*tektonv1.PipelineRun{
+		{ObjectMeta: metav1.ObjectMeta{Name: "docs", Annotations: map[string]string{
+			keys.OnCelExpression: `event == "pull_request" && "docs/*.md".pathChanged()`,
+		}}},
+		{ObjectMeta: metav1.ObjectMeta{GenerateName: "main-", Annotations: map[string]string{
+			keys.OnCelExpression: `event == "push" && target_branch == "main"`,
+		}}},
+	}
+	assert.DeepEqual(t, UnsupportedCapabilities("gitlab", prs), []string{})
+	assert.DeepEqual(t, UnsupportedCapabilities("bitbucket-cloud", prs), []string{
+		"PipelineRun docs uses pathChanged in its pipelinesascode.tekton.dev/on-cel-expression annotation but the bitbucket-cloud provider doesn't support path-filtering, no path is ever seen as changed",
+	})
+}
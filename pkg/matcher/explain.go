@@ -0,0 +1,49 @@
+package matcher
+
+import (
+	"context"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+)
+
+// Explanation is the result of matching a single PipelineRun against an
+// event, with a human readable reason of why it matched or not.
+type Explanation struct {
+	PipelineRun string
+	Matched     bool
+	Reason      string
+}
+
+// ExplainMatch goes over the PipelineRuns and explains for each of them if
+// they would match the event according to their annotations, with the same
+// logic as the matcher. It has no side effect and does not need access to the
+// cluster, the target-namespace annotation is only reported as is since it
+// needs a cluster lookup.
+func ExplainMatch(ctx context.Context, pruns []*tektonv1.PipelineRun, event *info.Event, vcx provider.Interface) []Explanation {
+	explanations := []Explanation{}
+	approvals := &approvalsCount{}
+	for _, prun := range pruns {
+		_, matched, reason, err := matchPipelineRun(ctx, prun, nil, event, vcx, approvals)
+		if err != nil {
+			reason = err.Error()
+		}
+		explanations = append(explanations, Explanation{
+			PipelineRun: pipelineRunName(prun),
+			Matched:     matched,
+			Reason:      reason,
+		})
+	}
+	return explanations
+}
+
+// pipelineRunName returns the name of the PipelineRun as written in the
+// .tekton directory, before the generateName is set.
+func pipelineRunName(prun *tektonv1.PipelineRun) string {
+	if name := prun.GetName(); name != "" {
+		return name
+	}
+	return strings.TrimSuffix(prun.GetGenerateName(), "-")
+}
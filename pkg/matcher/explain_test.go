@@ -0,0 +1,116 @@
+package matcher
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	testprovider "github.com/openshift-pipelines/pipelines-as-code/pkg/test/provider"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestExplainMatch(t *testing.T) {
+	tests := []struct {
+		name              string
+		annotations       map[string]string
+		targetPipelineRun string
+		wantMatched       bool
+		wantReason        string
+	}{
+		{
+			name: "match event and branch",
+			annotations: map[string]string{
+				keys.OnEvent:        "[pull_request]",
+				keys.OnTargetBranch: "[main]",
+			},
+			wantMatched: true,
+			wantReason:  "match the event",
+		},
+		{
+			name: "event does not match",
+			annotations: map[string]string{
+				keys.OnEvent:        "[push]",
+				keys.OnTargetBranch: "[main]",
+			},
+			wantReason: "on-event annotation [push] does not match the event pull_request",
+		},
+		{
+			name: "branch does not match",
+			annotations: map[string]string{
+				keys.OnEvent:        "[pull_request]",
+				keys.OnTargetBranch: "[release-*]",
+			},
+			wantReason: "on-target-branch annotation [release-*] does not match the branch main",
+		},
+		{
+			name: "missing target branch",
+			annotations: map[string]string{
+				keys.OnEvent: "[pull_request]",
+			},
+			wantReason: "no pipelinesascode.tekton.dev/on-event or pipelinesascode.tekton.dev/on-target-branch annotation",
+		},
+		{
+			name: "invalid annotation",
+			annotations: map[string]string{
+				keys.OnEvent:        "[pull_request",
+				keys.OnTargetBranch: "[main]",
+			},
+			wantReason: "annotation is invalid",
+		},
+		{
+			name: "cel expression matching",
+			annotations: map[string]string{
+				keys.OnCelExpression: `event == "pull_request" && target_branch == "main"`,
+			},
+			wantMatched: true,
+			wantReason:  "has been evaluated to true",
+		},
+		{
+			name: "cel expression not matching",
+			annotations: map[string]string{
+				keys.OnCelExpression: `event == "push"`,
+			},
+			wantReason: "has been evaluated to false",
+		},
+		{
+			name:       "no annotations",
+			wantReason: "no annotations",
+		},
+		{
+			name: "targeted by the incoming webhook",
+			annotations: map[string]string{
+				keys.OnEvent:        "[push]",
+				keys.OnTargetBranch: "[main]",
+			},
+			targetPipelineRun: "pipelinerun",
+			wantMatched:       true,
+			wantReason:        "explicitly targeted by the incoming webhook",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := &info.Event{
+				TriggerTarget:     "pull_request",
+				EventType:         "pull_request",
+				BaseBranch:        "main",
+				TargetPipelineRun: tt.targetPipelineRun,
+			}
+			prun := &tektonv1.PipelineRun{
+				ObjectMeta: metav1.ObjectMeta{
+					GenerateName: "pipelinerun-",
+					Annotations:  tt.annotations,
+				},
+			}
+			explanations := ExplainMatch(context.Background(), []*tektonv1.PipelineRun{prun}, event, &testprovider.TestProviderImp{})
+			assert.Equal(t, len(explanations), 1)
+			assert.Equal(t, explanations[0].PipelineRun, "pipelinerun")
+			assert.Equal(t, explanations[0].Matched, tt.wantMatched)
+			assert.Assert(t, len(explanations[0].Reason) > 0)
+			assert.Assert(t, strings.Contains(explanations[0].Reason, tt.wantReason), "%s does not contain %s", explanations[0].Reason, tt.wantReason)
+		})
+	}
+}
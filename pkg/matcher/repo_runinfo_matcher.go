@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	apipac "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -16,14 +17,28 @@ func MatchEventURLRepo(ctx context.Context, cs *params.Run, event *info.Event, n
 	if err != nil {
 		return nil, err
 	}
-	for i := len(repositories.Items) - 1; i >= 0; i-- {
+	eventURL := formatting.NormalizeRepoURL(event.URL)
+	candidates := []apipac.Repository{}
+	for i := range repositories.Items {
 		repo := repositories.Items[i]
 		repo.Spec.URL = strings.TrimSuffix(repo.Spec.URL, "/")
-		if repo.Spec.URL == event.URL {
-			return &repo, nil
+		// paths are case insensitive on the git providers, ie: a GitLab
+		// project in a group/subgroup can be referenced as Group/SubGroup
+		if strings.EqualFold(formatting.NormalizeRepoURL(repo.Spec.URL), eventURL) {
+			candidates = append(candidates, repo)
 		}
 	}
 
+	// several Repositories of a namespace can declare the same URL with a
+	// routing, the one with the most specific routing matching the event
+	// handles it
+	candidates = apipac.RoutableRepositories(candidates)
+	apipac.SortByRouting(candidates)
+	for i := range candidates {
+		if candidates[i].RoutingMatches(event.EventTypes(), event.BaseBranch) {
+			return &candidates[i], nil
+		}
+	}
 	return nil, nil
 }
 
@@ -70,6 +70,44 @@ func Test_getRepoByCR(t *testing.T) {
 			wantTargetNS: targetNamespace,
 			wantErr:      false,
 		},
+		{
+			name: "test-match-url-gitlab-nested-subgroups",
+			args: args{
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "test-good",
+								URL:              "https://gitlab.com/Group/sub/sub2/project.git",
+								InstallNamespace: targetNamespace,
+							},
+						),
+					},
+				},
+				runevent: info.Event{URL: "https://gitlab.com/group/sub/sub2/project", BaseBranch: mainBranch, EventType: "pull_request"},
+			},
+			wantTargetNS: targetNamespace,
+			wantErr:      false,
+		},
+		{
+			name: "test-nomatch-url-gitlab-parent-group",
+			args: args{
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "test-good",
+								URL:              "https://gitlab.com/group/sub",
+								InstallNamespace: targetNamespace,
+							},
+						),
+					},
+				},
+				runevent: info.Event{URL: "https://gitlab.com/group/sub/project", BaseBranch: mainBranch, EventType: "pull_request"},
+			},
+			wantTargetNS: "",
+			wantErr:      false,
+		},
 		{
 			name: "test-nomatch-url",
 			args: args{
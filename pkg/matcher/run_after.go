@@ -0,0 +1,16 @@
+package matcher
+
+import (
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+)
+
+// RunAfter returns the names of the PipelineRuns which need to succeed
+// before the PipelineRun can run, as set in its run-after annotation.
+func RunAfter(pr *tektonv1.PipelineRun) ([]string, error) {
+	annotation, ok := pr.GetAnnotations()[keys.RunAfter]
+	if !ok {
+		return nil, nil
+	}
+	return getAnnotationValues(annotation)
+}
@@ -18,6 +18,13 @@ import (
 	"k8s.io/client-go/tools/clientcmd"
 )
 
+// Cache caches the values of the lookups on the cluster between the
+// invocations of the CLI.
+type Cache interface {
+	Get(key string) (string, bool)
+	Set(key, value string)
+}
+
 type Clients struct {
 	ClientInitialized bool
 	PipelineAsCode    versioned.Interface
@@ -27,6 +34,34 @@ type Clients struct {
 	Log               *zap.SugaredLogger
 	Dynamic           dynamic.Interface
 	ConsoleUI         consoleui.Interface
+	// WrapTransport wraps the transport of the kubernetes and http clients,
+	// ie: to trace the API calls from the CLI.
+	WrapTransport func(http.RoundTripper) http.RoundTripper
+	// Cache caches the lookups done with CachedLookup, they are not cached
+	// when it's nil.
+	Cache Cache
+	// cluster is the API server the clients talk to, the cached lookups are
+	// per cluster.
+	cluster string
+}
+
+// CachedLookup returns the value of the lookup named key on the cluster from
+// the cache, or calls lookup and caches its value when it's not there. The
+// value is not cached when lookup fails.
+func (c *Clients) CachedLookup(key string, lookup func() (string, error)) (string, error) {
+	if c.Cache == nil {
+		return lookup()
+	}
+	key = c.cluster + "|" + key
+	if value, ok := c.Cache.Get(key); ok {
+		return value, nil
+	}
+	value, err := lookup()
+	if err != nil {
+		return "", err
+	}
+	c.Cache.Set(key, value)
+	return value, nil
 }
 
 func (c *Clients) GetURL(ctx context.Context, url string) ([]byte, error) {
@@ -111,7 +146,16 @@ func (c *Clients) pacClient(config *rest.Config) (versioned.Interface, error) {
 }
 
 func (c *Clients) consoleUIClient(ctx context.Context, dynamic dynamic.Interface, info *info.Info) consoleui.Interface {
-	return consoleui.New(ctx, dynamic, info)
+	if c.Cache == nil {
+		return consoleui.New(ctx, dynamic, info)
+	}
+	host, err := c.CachedLookup("console", func() (string, error) {
+		return consoleui.OpenShiftConsoleHost(ctx, dynamic)
+	})
+	if err != nil {
+		return consoleui.FallBackConsole{}
+	}
+	return consoleui.FromOpenShiftConsoleHost(host)
 }
 
 func (c *Clients) NewClients(ctx context.Context, info *info.Info) error {
@@ -129,8 +173,13 @@ func (c *Clients) NewClients(ctx context.Context, info *info.Info) error {
 	if err != nil {
 		return err
 	}
+	c.cluster = config.Host
 	config.QPS = 50
 	config.Burst = 50
+	if c.WrapTransport != nil {
+		config.Wrap(c.WrapTransport)
+		c.HTTP.Transport = c.WrapTransport(c.HTTP.Transport)
+	}
 
 	c.Kube, err = c.kubeClient(config)
 	if err != nil {
@@ -1,6 +1,7 @@
 package clients
 
 import (
+	"fmt"
 	"testing"
 
 	httptesthelper "github.com/openshift-pipelines/pipelines-as-code/pkg/test/http"
@@ -54,3 +55,44 @@ func TestClients_GetURL(t *testing.T) {
 		})
 	}
 }
+
+type mapCache map[string]string
+
+func (m mapCache) Get(key string) (string, bool) {
+	value, ok := m[key]
+	return value, ok
+}
+
+func (m mapCache) Set(key, value string) {
+	m[key] = value
+}
+
+func TestCachedLookup(t *testing.T) {
+	calls := 0
+	lookup := func() (string, error) {
+		calls++
+		return "value", nil
+	}
+
+	c := &Clients{}
+	value, err := c.CachedLookup("key", lookup)
+	assert.NilError(t, err)
+	assert.Equal(t, value, "value")
+	_, _ = c.CachedLookup("key", lookup)
+	assert.Equal(t, calls, 2, "without a cache the lookup is always done")
+
+	calls = 0
+	cache := mapCache{}
+	c = &Clients{Cache: cache, cluster: "https://api.cluster"}
+	_, _ = c.CachedLookup("key", lookup)
+	value, err = c.CachedLookup("key", lookup)
+	assert.NilError(t, err)
+	assert.Equal(t, value, "value")
+	assert.Equal(t, calls, 1)
+	assert.Equal(t, cache["https://api.cluster|key"], "value")
+
+	_, err = c.CachedLookup("failing", func() (string, error) { return "", fmt.Errorf("boom") })
+	assert.Error(t, err, "boom")
+	_, ok := cache["https://api.cluster|failing"]
+	assert.Assert(t, !ok, "a failed lookup should not be cached")
+}
@@ -21,6 +21,12 @@ type Event struct {
 	// a push or a pull_request
 	TriggerTarget string
 
+	// CustomEventType is used instead of TriggerTarget to match the on-event
+	// annotation, it is either pull_request_approved for a pull request
+	// approval or a custom event type defined by the admin in the
+	// custom-event-types setting.
+	CustomEventType string
+
 	// Target PipelineRun, the target PipelineRun user request. Used in incoming webhook
 	TargetPipelineRun string
 
@@ -28,12 +34,14 @@ type Event struct {
 	DefaultBranch     string // master/main branches to know where things like the OWNERS file is located.
 	HeadBranch        string // branch from where our SHA get tested
 	SHA               string
+	BeforeSHA         string // SHA the branch pointed to before a push, empty or zeros when it creates the branch
 	Sender            string
-	URL               string // WEB url not the git URL, which would match to the repo.spec
-	SHAURL            string // pretty URL for web browsing for UIs (cli/web)
-	SHATitle          string // commit title for UIs
-	PullRequestNumber int    // Pull or Merge Request number
-	PullRequestTitle  string // Title of the pull Request
+	URL               string   // WEB url not the git URL, which would match to the repo.spec
+	SHAURL            string   // pretty URL for web browsing for UIs (cli/web)
+	SHATitle          string   // commit title for UIs
+	PullRequestNumber int      // Pull or Merge Request number
+	PullRequestTitle  string   // Title of the pull Request
+	PullRequestLabel  []string // Labels of the pull Request
 
 	// TODO: move forge specifics to each driver
 	// Github
@@ -54,12 +62,26 @@ type Event struct {
 	// Gitlab
 	SourceProjectID int
 	TargetProjectID int
+	// MergeRequestRef is the ref of the merged result of the merge request,
+	// refs/merge-requests/<iid>/merge or refs/merge-requests/<iid>/train when
+	// running for a merge train.
+	MergeRequestRef string
 }
 
 type State struct {
 	TargetTestPipelineRun   string
 	CancelPipelineRuns      bool
 	TargetCancelPipelineRun string
+	// OverridePipelineRuns is set by a /override comment, the failed
+	// PipelineRuns are reported as overridden.
+	OverridePipelineRuns      bool
+	TargetOverridePipelineRun string
+	OverrideReason            string
+	// TriggerComment is the body of the comment which triggered the run and
+	// TriggerCommentArgs the arguments following the PipelineRun name of a
+	// /test or /retest comment, ie: "staging" for "/test deploy staging".
+	TriggerComment     string
+	TriggerCommentArgs string
 }
 
 type Provider struct {
@@ -68,6 +90,8 @@ type Provider struct {
 	User                  string
 	WebhookSecret         string
 	WebhookSecretFromRepo bool
+	// CommentStrategy is the comment_strategy of the Repository.
+	CommentStrategy string
 }
 
 type Request struct {
@@ -80,6 +104,19 @@ func (r *Event) DeepCopyInto(out *Event) {
 	*out = *r
 }
 
+// EventTypes returns the types the event is matched on by the Repositories:
+// its trigger target, its type from the provider header and its custom event
+// type when set.
+func (r *Event) EventTypes() []string {
+	types := []string{}
+	for _, t := range []string{r.TriggerTarget, r.EventType, r.CustomEventType} {
+		if t != "" {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
 // NewEvent returns a new Event
 func NewEvent() *Event {
 	return &Event{
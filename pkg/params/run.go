@@ -22,6 +22,10 @@ const (
 	<br><code>%s pr logs -n %s %s</code>`
 	QueuingPipelineRunText = `PipelineRun <b>%s</b> has been queued Queuing in namespace
   <b>%s</b><br><br>`
+	WaitingPipelineRunText = `PipelineRun <b>%s</b> in namespace <b>%s</b> is waiting for
+  <b>%s</b> to succeed before starting<br><br>`
+	MaintenancePipelineRunText = `PipelineRun <b>%s</b> has been queued in namespace <b>%s</b>
+  during a maintenance of the cluster, it will start after <b>%s</b><br><br>`
 )
 
 type Run struct {
@@ -2,21 +2,28 @@ package settings
 
 import (
 	"fmt"
+	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	"go.uber.org/zap"
 )
 
 const (
 	ApplicationNameKey                    = "application-name"
+	ApplicationAvatarURLKey               = "application-avatar-url"
+	ApplicationDetailsURLKey              = "application-details-url"
+	ApplicationFooterKey                  = "application-footer"
 	HubURLKey                             = "hub-url"
 	HubCatalogNameKey                     = "hub-catalog-name"
 	MaxKeepRunUpperLimitKey               = "max-keep-run-upper-limit"
 	DefaultMaxKeepRunsKey                 = "default-max-keep-runs"
 	RemoteTasksKey                        = "remote-tasks"
+	RemoteTasksExtraReposKey              = "remote-tasks-extra-repos"
 	BitbucketCloudCheckSourceIPKey        = "bitbucket-cloud-check-source-ip"
 	BitbucketCloudAdditionalSourceIPKey   = "bitbucket-cloud-additional-source-ip"
+	IncomingTrustedProxiesKey             = "incoming-trusted-proxies"
 	TektonDashboardURLKey                 = "tekton-dashboard-url"
 	AutoConfigureNewGitHubRepoKey         = "auto-configure-new-github-repo"
 	AutoConfigureRepoNamespaceTemplateKey = "auto-configure-repo-namespace-template"
@@ -26,8 +33,14 @@ const (
 	CustomConsolePRDetailKey  = "custom-console-url-pr-details"
 	CustomConsolePRTaskLogKey = "custom-console-url-pr-tasklog"
 
+	LogArchiveURLKey = "log-archive-url"
+
 	SecretAutoCreateKey                          = "secret-auto-create"
 	secretAutoCreateDefaultValue                 = "true"
+	SecretAutoCreateNamePrefixKey                = "secret-auto-create-name-prefix"
+	SecretAutoCreateNamePrefixDefaultValue       = "pac-gitauth"
+	SecretAutoCreateServiceAccountKey            = "secret-auto-create-service-account"
+	SecretAutoCreateModeKey                      = "secret-auto-create-mode"
 	SecretGhAppTokenRepoScopedKey                = "secret-github-app-token-scoped" //nolint: gosec
 	secretGhAppTokenRepoScopedDefaultValue       = "true"
 	SecretGhAppTokenScopedExtraReposKey          = "secret-github-app-scope-extra-repos" //nolint: gosec
@@ -51,24 +64,82 @@ const (
 
 	ErrorDetectionSimpleRegexpKey   = "error-detection-simple-regexp"
 	errorDetectionSimpleRegexpValue = `^(?P<filename>[^:]*):(?P<line>[0-9]+):(?P<column>[0-9]+):([ ]*)?(?P<error>.*)`
+
+	NoMatchNeutralStatusKey   = "no-match-neutral-status"
+	noMatchNeutralStatusValue = "false"
+
+	ObserveOnlyKey   = "observe-only"
+	observeOnlyValue = "false"
+
+	GroupStatusesByCommitKey   = "group-statuses-by-commit"
+	groupStatusesByCommitValue = "false"
+
+	WebhookDeduplicationWindowKey   = "webhook-deduplication-window"
+	webhookDeduplicationWindowValue = "10m"
+
+	PullRequestDebounceWindowKey   = "pull-request-debounce-window"
+	pullRequestDebounceWindowValue = "0"
+
+	CustomEventTypesKey = "custom-event-types"
+
+	PipelineRunLabelsKey      = "pipelinerun-labels"
+	PipelineRunAnnotationsKey = "pipelinerun-annotations"
+
+	PipelineRunInjectionTemplateKey = "pipelinerun-injection-template"
+
+	PinStepImagesKey   = "pin-step-images"
+	pinStepImagesValue = "false"
+
+	ProviderAPIClientsKey = "provider-api-clients"
+
+	EventFilterURLKey       = "event-filter-url"
+	EventFilterTimeoutKey   = "event-filter-timeout"
+	eventFilterTimeoutValue = "10s"
+
+	SecretScanningKey   = "secret-scanning"
+	secretScanningValue = "false"
+
+	PrioritizeDefaultBranchPushKey   = "prioritize-default-branch-push"
+	prioritizeDefaultBranchPushValue = "false"
+
+	NodePoolLabelKey          = "node-pool-label"
+	NodePoolLabelDefaultValue = "pipelinesascode.tekton.dev/node-pool"
+
+	AuditLogPathKey       = "audit-log-path"
+	AuditLogMaxSizeKey    = "audit-log-max-size"
+	auditLogMaxSizeValue  = "100"
+	AuditLogMaxFilesKey   = "audit-log-max-files"
+	auditLogMaxFilesValue = "5"
+	AuditLogCompressKey   = "audit-log-compress"
+	auditLogCompressValue = "true"
+
+	MaintenanceWindowsKey = "maintenance-windows"
 )
 
 var TknBinaryName = `tkn`
 
 type Settings struct {
 	ApplicationName                    string
+	ApplicationAvatarURL               string
+	ApplicationDetailsURL              string
+	ApplicationFooter                  string
 	HubURL                             string
 	HubCatalogName                     string
 	RemoteTasks                        bool
+	RemoteTasksExtraRepos              string
 	MaxKeepRunsUpperLimit              int
 	DefaultMaxKeepRuns                 int
 	BitbucketCloudCheckSourceIP        bool
 	BitbucketCloudAdditionalSourceIP   string
+	IncomingTrustedProxies             string
 	TektonDashboardURL                 string
 	AutoConfigureNewGitHubRepo         bool
 	AutoConfigureRepoNamespaceTemplate string
 
 	SecretAutoCreation               bool
+	SecretAutoCreateNamePrefix       string
+	SecretAutoCreateServiceAccount   string
+	SecretAutoCreateMode             string
 	SecretGHAppRepoScoped            bool
 	SecretGhAppTokenScopedExtraRepos string
 
@@ -77,10 +148,49 @@ type Settings struct {
 	ErrorDetectionNumberOfLines int
 	ErrorDetectionSimpleRegexp  string
 
+	NoMatchNeutralStatus bool
+
+	ObserveOnly bool
+
+	GroupStatusesByCommit bool
+
+	WebhookDeduplicationWindow time.Duration
+
+	PullRequestDebounceWindow time.Duration
+
+	CustomEventTypes []CustomEventType
+
+	PipelineRunLabels      map[string]string
+	PipelineRunAnnotations map[string]string
+
+	PipelineRunInjection *PipelineRunInjection
+
+	PinStepImages bool
+
+	ProviderAPIClients map[string]ProviderAPIClient
+
+	EventFilterURL     string
+	EventFilterTimeout time.Duration
+
+	SecretScanning bool
+
+	PrioritizeDefaultBranchPush bool
+
+	NodePoolLabel string
+
 	CustomConsoleName      string
 	CustomConsoleURL       string
 	CustomConsolePRdetail  string
 	CustomConsolePRTaskLog string
+
+	LogArchiveURL string
+
+	AuditLogPath     string
+	AuditLogMaxSize  int
+	AuditLogMaxFiles int
+	AuditLogCompress bool
+
+	MaintenanceWindows []MaintenanceWindow
 }
 
 func ConfigToSettings(logger *zap.SugaredLogger, setting *Settings, config map[string]string) error {
@@ -97,12 +207,43 @@ func ConfigToSettings(logger *zap.SugaredLogger, setting *Settings, config map[s
 		setting.ApplicationName = config[ApplicationNameKey]
 	}
 
+	if setting.ApplicationAvatarURL != config[ApplicationAvatarURLKey] {
+		logger.Infof("CONFIG: application avatar url set to %v", config[ApplicationAvatarURLKey])
+		setting.ApplicationAvatarURL = config[ApplicationAvatarURLKey]
+	}
+
+	if setting.ApplicationDetailsURL != config[ApplicationDetailsURLKey] {
+		logger.Infof("CONFIG: application details url set to %v", config[ApplicationDetailsURLKey])
+		setting.ApplicationDetailsURL = config[ApplicationDetailsURLKey]
+	}
+
+	applicationFooter := strings.TrimSpace(config[ApplicationFooterKey])
+	if setting.ApplicationFooter != applicationFooter {
+		logger.Infof("CONFIG: application footer set to %v", applicationFooter)
+		setting.ApplicationFooter = applicationFooter
+	}
+
 	secretAutoCreate := StringToBool(config[SecretAutoCreateKey])
 	if setting.SecretAutoCreation != secretAutoCreate {
 		logger.Infof("CONFIG: secret auto create set to %v", secretAutoCreate)
 		setting.SecretAutoCreation = secretAutoCreate
 	}
 
+	if setting.SecretAutoCreateNamePrefix != config[SecretAutoCreateNamePrefixKey] {
+		logger.Infof("CONFIG: secret auto create name prefix set to %v", config[SecretAutoCreateNamePrefixKey])
+		setting.SecretAutoCreateNamePrefix = config[SecretAutoCreateNamePrefixKey]
+	}
+
+	if setting.SecretAutoCreateServiceAccount != config[SecretAutoCreateServiceAccountKey] {
+		logger.Infof("CONFIG: secret auto create service account set to %v", config[SecretAutoCreateServiceAccountKey])
+		setting.SecretAutoCreateServiceAccount = config[SecretAutoCreateServiceAccountKey]
+	}
+
+	if setting.SecretAutoCreateMode != config[SecretAutoCreateModeKey] {
+		logger.Infof("CONFIG: secret auto create mode set to %v", config[SecretAutoCreateModeKey])
+		setting.SecretAutoCreateMode = config[SecretAutoCreateModeKey]
+	}
+
 	secretGHAppRepoScoped := StringToBool(config[SecretGhAppTokenRepoScopedKey])
 	if setting.SecretGHAppRepoScoped != secretGHAppRepoScoped {
 		logger.Infof("CONFIG: not scoping the token generated from gh %v", secretGHAppRepoScoped)
@@ -128,6 +269,10 @@ func ConfigToSettings(logger *zap.SugaredLogger, setting *Settings, config map[s
 		logger.Infof("CONFIG: remote tasks setting set to %v", remoteTask)
 		setting.RemoteTasks = remoteTask
 	}
+	if setting.RemoteTasksExtraRepos != config[RemoteTasksExtraReposKey] {
+		logger.Infof("CONFIG: remote tasks extra repositories set to %v", config[RemoteTasksExtraReposKey])
+		setting.RemoteTasksExtraRepos = config[RemoteTasksExtraReposKey]
+	}
 	maxKeepRunUpperLimit, _ := strconv.Atoi(config[MaxKeepRunUpperLimitKey])
 	if setting.MaxKeepRunsUpperLimit != maxKeepRunUpperLimit {
 		logger.Infof("CONFIG: max keep runs upper limit set to %v", maxKeepRunUpperLimit)
@@ -147,6 +292,10 @@ func ConfigToSettings(logger *zap.SugaredLogger, setting *Settings, config map[s
 		logger.Infof("CONFIG: bitbucket cloud additional source ip set to %v", config[BitbucketCloudAdditionalSourceIPKey])
 		setting.BitbucketCloudAdditionalSourceIP = config[BitbucketCloudAdditionalSourceIPKey]
 	}
+	if setting.IncomingTrustedProxies != config[IncomingTrustedProxiesKey] {
+		logger.Infof("CONFIG: incoming webhook trusted proxies set to %v", config[IncomingTrustedProxiesKey])
+		setting.IncomingTrustedProxies = config[IncomingTrustedProxiesKey]
+	}
 	if setting.TektonDashboardURL != config[TektonDashboardURLKey] {
 		logger.Infof("CONFIG: tekton dashboard url set to %v", config[TektonDashboardURLKey])
 		setting.TektonDashboardURL = config[TektonDashboardURLKey]
@@ -185,6 +334,108 @@ func ConfigToSettings(logger *zap.SugaredLogger, setting *Settings, config map[s
 		setting.ErrorDetectionSimpleRegexp = strings.TrimSpace(config[ErrorDetectionSimpleRegexpKey])
 	}
 
+	noMatchNeutralStatus := StringToBool(config[NoMatchNeutralStatusKey])
+	if setting.NoMatchNeutralStatus != noMatchNeutralStatus {
+		logger.Infof("CONFIG: setting neutral status when no PipelineRun match to %v", noMatchNeutralStatus)
+		setting.NoMatchNeutralStatus = noMatchNeutralStatus
+	}
+
+	observeOnly := StringToBool(config[ObserveOnlyKey])
+	if setting.ObserveOnly != observeOnly {
+		logger.Infof("CONFIG: setting observe only mode to %v", observeOnly)
+		setting.ObserveOnly = observeOnly
+	}
+
+	groupStatusesByCommit := StringToBool(config[GroupStatusesByCommitKey])
+	if setting.GroupStatusesByCommit != groupStatusesByCommit {
+		logger.Infof("CONFIG: setting group statuses by commit to %v", groupStatusesByCommit)
+		setting.GroupStatusesByCommit = groupStatusesByCommit
+	}
+
+	// already validated
+	webhookDeduplicationWindow, _ := time.ParseDuration(config[WebhookDeduplicationWindowKey])
+	if setting.WebhookDeduplicationWindow != webhookDeduplicationWindow {
+		logger.Infof("CONFIG: setting webhook deduplication window to %v", webhookDeduplicationWindow)
+		setting.WebhookDeduplicationWindow = webhookDeduplicationWindow
+	}
+
+	// already validated
+	pullRequestDebounceWindow, _ := time.ParseDuration(config[PullRequestDebounceWindowKey])
+	if setting.PullRequestDebounceWindow != pullRequestDebounceWindow {
+		logger.Infof("CONFIG: setting pull request debounce window to %v", pullRequestDebounceWindow)
+		setting.PullRequestDebounceWindow = pullRequestDebounceWindow
+	}
+
+	// already validated
+	customEventTypes, _ := ParseCustomEventTypes(config[CustomEventTypesKey])
+	if !reflect.DeepEqual(setting.CustomEventTypes, customEventTypes) {
+		logger.Infof("CONFIG: setting custom event types to %v", strings.TrimSpace(config[CustomEventTypesKey]))
+		setting.CustomEventTypes = customEventTypes
+	}
+
+	// already validated
+	pipelineRunLabels, _ := ParsePipelineRunMetadata(config[PipelineRunLabelsKey])
+	if !reflect.DeepEqual(setting.PipelineRunLabels, pipelineRunLabels) {
+		logger.Infof("CONFIG: setting pipelinerun labels to %v", strings.TrimSpace(config[PipelineRunLabelsKey]))
+		setting.PipelineRunLabels = pipelineRunLabels
+	}
+
+	// already validated
+	pipelineRunAnnotations, _ := ParsePipelineRunMetadata(config[PipelineRunAnnotationsKey])
+	if !reflect.DeepEqual(setting.PipelineRunAnnotations, pipelineRunAnnotations) {
+		logger.Infof("CONFIG: setting pipelinerun annotations to %v", strings.TrimSpace(config[PipelineRunAnnotationsKey]))
+		setting.PipelineRunAnnotations = pipelineRunAnnotations
+	}
+
+	// already validated
+	pipelineRunInjection, _ := ParsePipelineRunInjection(config[PipelineRunInjectionTemplateKey])
+	if !reflect.DeepEqual(setting.PipelineRunInjection, pipelineRunInjection) {
+		logger.Infof("CONFIG: setting pipelinerun injection template to %v", strings.TrimSpace(config[PipelineRunInjectionTemplateKey]))
+		setting.PipelineRunInjection = pipelineRunInjection
+	}
+
+	pinStepImages := StringToBool(config[PinStepImagesKey])
+	if setting.PinStepImages != pinStepImages {
+		logger.Infof("CONFIG: setting pin step images to %v", pinStepImages)
+		setting.PinStepImages = pinStepImages
+	}
+
+	// already validated
+	providerAPIClients, _ := ParseProviderAPIClients(config[ProviderAPIClientsKey])
+	if !reflect.DeepEqual(setting.ProviderAPIClients, providerAPIClients) {
+		logger.Infof("CONFIG: setting provider api clients to %v", strings.TrimSpace(config[ProviderAPIClientsKey]))
+		setting.ProviderAPIClients = providerAPIClients
+	}
+
+	if setting.EventFilterURL != config[EventFilterURLKey] {
+		logger.Infof("CONFIG: setting event filter url to %v", config[EventFilterURLKey])
+		setting.EventFilterURL = config[EventFilterURLKey]
+	}
+
+	// already validated
+	eventFilterTimeout, _ := time.ParseDuration(config[EventFilterTimeoutKey])
+	if setting.EventFilterTimeout != eventFilterTimeout {
+		logger.Infof("CONFIG: setting event filter timeout to %v", eventFilterTimeout)
+		setting.EventFilterTimeout = eventFilterTimeout
+	}
+
+	secretScanning := StringToBool(config[SecretScanningKey])
+	if setting.SecretScanning != secretScanning {
+		logger.Infof("CONFIG: setting secret scanning to %v", secretScanning)
+		setting.SecretScanning = secretScanning
+	}
+
+	prioritizeDefaultBranchPush := StringToBool(config[PrioritizeDefaultBranchPushKey])
+	if setting.PrioritizeDefaultBranchPush != prioritizeDefaultBranchPush {
+		logger.Infof("CONFIG: setting prioritize default branch push to %v", prioritizeDefaultBranchPush)
+		setting.PrioritizeDefaultBranchPush = prioritizeDefaultBranchPush
+	}
+
+	if setting.NodePoolLabel != config[NodePoolLabelKey] {
+		logger.Infof("CONFIG: setting node pool label to %v", config[NodePoolLabelKey])
+		setting.NodePoolLabel = config[NodePoolLabelKey]
+	}
+
 	if setting.CustomConsoleName != config[CustomConsoleNameKey] {
 		logger.Infof("CONFIG: setting custom console name to %v", config[CustomConsoleNameKey])
 		setting.CustomConsoleName = config[CustomConsoleNameKey]
@@ -205,6 +456,42 @@ func ConfigToSettings(logger *zap.SugaredLogger, setting *Settings, config map[s
 		setting.CustomConsolePRTaskLog = config[CustomConsolePRTaskLogKey]
 	}
 
+	if setting.LogArchiveURL != config[LogArchiveURLKey] {
+		logger.Infof("CONFIG: setting log archive URL to %v", config[LogArchiveURLKey])
+		setting.LogArchiveURL = config[LogArchiveURLKey]
+	}
+
+	if setting.AuditLogPath != config[AuditLogPathKey] {
+		logger.Infof("CONFIG: setting audit log path to %v", config[AuditLogPathKey])
+		setting.AuditLogPath = config[AuditLogPathKey]
+	}
+
+	// already validated
+	auditLogMaxSize, _ := strconv.Atoi(config[AuditLogMaxSizeKey])
+	if setting.AuditLogMaxSize != auditLogMaxSize {
+		logger.Infof("CONFIG: setting audit log max size to %vMB", auditLogMaxSize)
+		setting.AuditLogMaxSize = auditLogMaxSize
+	}
+
+	auditLogMaxFiles, _ := strconv.Atoi(config[AuditLogMaxFilesKey])
+	if setting.AuditLogMaxFiles != auditLogMaxFiles {
+		logger.Infof("CONFIG: setting audit log max files to %v", auditLogMaxFiles)
+		setting.AuditLogMaxFiles = auditLogMaxFiles
+	}
+
+	auditLogCompress := StringToBool(config[AuditLogCompressKey])
+	if setting.AuditLogCompress != auditLogCompress {
+		logger.Infof("CONFIG: setting audit log compress to %v", auditLogCompress)
+		setting.AuditLogCompress = auditLogCompress
+	}
+
+	// already validated
+	maintenanceWindows, _ := ParseMaintenanceWindows(config[MaintenanceWindowsKey])
+	if !reflect.DeepEqual(setting.MaintenanceWindows, maintenanceWindows) {
+		logger.Infof("CONFIG: setting maintenance windows to %v", maintenanceWindows)
+		setting.MaintenanceWindows = maintenanceWindows
+	}
+
 	return nil
 }
 
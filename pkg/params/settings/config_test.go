@@ -71,6 +71,16 @@ func TestConfigToSettings(t *testing.T) {
 			},
 			wantLogContains: "application name set to test",
 		},
+		{
+			name: "set application footer",
+			args: args{
+				setting: &Settings{},
+				config: map[string]string{
+					ApplicationFooterKey: " Reported by ACME CI \n",
+				},
+			},
+			wantLogContains: "application footer set to Reported by ACME CI",
+		},
 		{
 			name: "set auto create key",
 			args: args{
@@ -91,6 +101,168 @@ func TestConfigToSettings(t *testing.T) {
 			},
 			wantLogContains: "hub URL set to https://test",
 		},
+		{
+			name: "set no match neutral status",
+			args: args{
+				setting: &Settings{},
+				config: map[string]string{
+					NoMatchNeutralStatusKey: "true",
+				},
+			},
+			wantLogContains: "neutral status when no PipelineRun match to true",
+		},
+		{
+			name: "set observe only",
+			args: args{
+				setting: &Settings{},
+				config: map[string]string{
+					ObserveOnlyKey: "true",
+				},
+			},
+			wantLogContains: "observe only mode to true",
+		},
+		{
+			name: "set group statuses by commit",
+			args: args{
+				setting: &Settings{},
+				config: map[string]string{
+					GroupStatusesByCommitKey: "true",
+				},
+			},
+			wantLogContains: "group statuses by commit to true",
+		},
+		{
+			name: "set secret auto create mode",
+			args: args{
+				setting: &Settings{},
+				config: map[string]string{
+					SecretAutoCreateModeKey: SecretAutoCreateModeGitConfig,
+				},
+			},
+			wantLogContains: "secret auto create mode set to gitconfig",
+		},
+		{
+			name: "set webhook deduplication window",
+			args: args{
+				setting: &Settings{},
+				config: map[string]string{
+					WebhookDeduplicationWindowKey: "90s",
+				},
+			},
+			wantLogContains: "webhook deduplication window to 1m30s",
+		},
+		{
+			name: "set pull request debounce window",
+			args: args{
+				setting: &Settings{},
+				config: map[string]string{
+					PullRequestDebounceWindowKey: "30s",
+				},
+			},
+			wantLogContains: "pull request debounce window to 30s",
+		},
+		{
+			name: "set provider api clients",
+			args: args{
+				setting: &Settings{},
+				config: map[string]string{
+					ProviderAPIClientsKey: "gitlab: timeout=10s, retries=2",
+				},
+			},
+			wantLogContains: "provider api clients to gitlab: timeout=10s, retries=2",
+		},
+		{
+			name: "set pipelinerun injection template",
+			args: args{
+				setting: &Settings{},
+				config: map[string]string{
+					PipelineRunInjectionTemplateKey: "sidecars:\n  - name: cache\n    image: cache\n",
+				},
+			},
+			wantLogContains: "pipelinerun injection template to sidecars:",
+		},
+		{
+			name: "set pin step images",
+			args: args{
+				setting: &Settings{},
+				config: map[string]string{
+					PinStepImagesKey: "true",
+				},
+			},
+			wantLogContains: "pin step images to true",
+		},
+		{
+			name: "set log archive url",
+			args: args{
+				setting: &Settings{},
+				config: map[string]string{
+					LogArchiveURLKey: "https://archive/{{ namespace }}/{{ pr }}",
+				},
+			},
+			wantLogContains: "log archive URL to https://archive/{{ namespace }}/{{ pr }}",
+		},
+		{
+			name: "set event filter",
+			args: args{
+				setting: &Settings{},
+				config: map[string]string{
+					EventFilterURLKey:     "https://policy.svc/filter",
+					EventFilterTimeoutKey: "3s",
+				},
+			},
+			wantLogContains: "event filter url to https://policy.svc/filter",
+		},
+		{
+			name: "set secret scanning",
+			args: args{
+				setting: &Settings{},
+				config: map[string]string{
+					SecretScanningKey: "true",
+				},
+			},
+			wantLogContains: "secret scanning to true",
+		},
+		{
+			name: "set prioritize default branch push",
+			args: args{
+				setting: &Settings{},
+				config: map[string]string{
+					PrioritizeDefaultBranchPushKey: "true",
+				},
+			},
+			wantLogContains: "prioritize default branch push to true",
+		},
+		{
+			name: "set node pool label",
+			args: args{
+				setting: &Settings{},
+				config: map[string]string{
+					NodePoolLabelKey: "cloud.google.com/gke-nodepool",
+				},
+			},
+			wantLogContains: "node pool label to cloud.google.com/gke-nodepool",
+		},
+		{
+			name: "set audit log",
+			args: args{
+				setting: &Settings{},
+				config: map[string]string{
+					AuditLogPathKey:    "/var/log/pac/audit.jsonl",
+					AuditLogMaxSizeKey: "10",
+				},
+			},
+			wantLogContains: "audit log path to /var/log/pac/audit.jsonl",
+		},
+		{
+			name: "set maintenance windows",
+			args: args{
+				setting: &Settings{},
+				config: map[string]string{
+					MaintenanceWindowsKey: "2023-05-20T22:00:00Z/2023-05-21T02:00:00Z",
+				},
+			},
+			wantLogContains: "maintenance windows to [2023-05-20T22:00:00Z/2023-05-21T02:00:00Z]",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
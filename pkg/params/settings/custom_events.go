@@ -0,0 +1,81 @@
+package settings
+
+import (
+	"fmt"
+	"strings"
+)
+
+// reservedEventTypes are the event types handled by Pipelines as Code which
+// cannot be redefined as custom event types.
+var reservedEventTypes = []string{"pull_request", "pull_request_approved", "push", "incoming"}
+
+// CustomEventType maps a custom event type name, usable in the on-event
+// annotation, to a git provider event and optionally its action and state.
+type CustomEventType struct {
+	Name   string
+	Event  string
+	Action string
+	State  string
+}
+
+// Matches returns true if the provider event, action and state are the ones
+// of the custom event type, the action and the state are only checked when
+// they are set.
+func (c CustomEventType) Matches(event, action, state string) bool {
+	if c.Event != event {
+		return false
+	}
+	if c.Action != "" && c.Action != action {
+		return false
+	}
+	return c.State == "" || strings.EqualFold(c.State, state)
+}
+
+// ParseCustomEventTypes parses the custom event types setting, the mappings
+// are separated by commas or newlines, ie:
+//
+//	pr-changes-requested=pull_request_review:submitted:changes_requested
+//	pr-labeled=pull_request:labeled
+func ParseCustomEventTypes(value string) ([]CustomEventType, error) {
+	var ret []CustomEventType
+	for _, entry := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == '\n' }) {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, mapping, found := strings.Cut(entry, "=")
+		name, mapping = strings.TrimSpace(name), strings.TrimSpace(mapping)
+		if !found || name == "" || mapping == "" {
+			return nil, fmt.Errorf("invalid custom event type %q, it needs to be in the name=event[:action[:state]] format", entry)
+		}
+		for _, reserved := range reservedEventTypes {
+			if name == reserved {
+				return nil, fmt.Errorf("custom event type %q cannot use the reserved name %s", entry, reserved)
+			}
+		}
+		parts := strings.Split(mapping, ":")
+		if len(parts) > 3 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid custom event type %q, it needs to be in the name=event[:action[:state]] format", entry)
+		}
+		cet := CustomEventType{Name: name, Event: parts[0]}
+		if len(parts) > 1 {
+			cet.Action = parts[1]
+		}
+		if len(parts) > 2 {
+			cet.State = parts[2]
+		}
+		ret = append(ret, cet)
+	}
+	return ret, nil
+}
+
+// MatchCustomEventType returns the name of the first custom event type
+// matching the provider event, action and state or an empty string.
+func MatchCustomEventType(customEventTypes []CustomEventType, event, action, state string) string {
+	for _, cet := range customEventTypes {
+		if cet.Matches(event, action, state) {
+			return cet.Name
+		}
+	}
+	return ""
+}
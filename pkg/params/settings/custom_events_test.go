@@ -0,0 +1,79 @@
+package settings
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParseCustomEventTypes(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    []CustomEventType
+		wantErr string
+	}{
+		{
+			name:  "empty",
+			value: "",
+		},
+		{
+			name:  "separated by commas and newlines",
+			value: "pr-approved=pull_request_review:submitted:approved, pr-labeled = pull_request:labeled\n\nreview=pull_request_review\n",
+			want: []CustomEventType{
+				{Name: "pr-approved", Event: "pull_request_review", Action: "submitted", State: "approved"},
+				{Name: "pr-labeled", Event: "pull_request", Action: "labeled"},
+				{Name: "review", Event: "pull_request_review"},
+			},
+		},
+		{
+			name:    "no mapping",
+			value:   "pr-approved",
+			wantErr: `invalid custom event type "pr-approved", it needs to be in the name=event[:action[:state]] format`,
+		},
+		{
+			name:    "too many parts",
+			value:   "pr-approved=a:b:c:d",
+			wantErr: `invalid custom event type "pr-approved=a:b:c:d", it needs to be in the name=event[:action[:state]] format`,
+		},
+		{
+			name:    "reserved name",
+			value:   "push=pull_request:closed",
+			wantErr: `custom event type "push=pull_request:closed" cannot use the reserved name push`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCustomEventTypes(tt.value)
+			if tt.wantErr != "" {
+				assert.Error(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.DeepEqual(t, got, tt.want)
+		})
+	}
+}
+
+func TestMatchCustomEventType(t *testing.T) {
+	customEventTypes := []CustomEventType{
+		{Name: "pr-approved", Event: "pull_request_review", Action: "submitted", State: "approved"},
+		{Name: "pr-labeled", Event: "pull_request", Action: "labeled"},
+		{Name: "review", Event: "pull_request_review"},
+	}
+	tests := []struct {
+		name                 string
+		event, action, state string
+		want                 string
+	}{
+		{name: "event action and state", event: "pull_request_review", action: "submitted", state: "APPROVED", want: "pr-approved"},
+		{name: "fallback on the event only", event: "pull_request_review", action: "submitted", state: "commented", want: "review"},
+		{name: "event and action", event: "pull_request", action: "labeled", want: "pr-labeled"},
+		{name: "no match", event: "pull_request", action: "closed", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, MatchCustomEventType(customEventTypes, tt.event, tt.action, tt.state), tt.want)
+		})
+	}
+}
@@ -11,6 +11,14 @@ func SetDefaults(config map[string]string) {
 		config[SecretAutoCreateKey] = secretAutoCreateDefaultValue
 	}
 
+	if namePrefix, ok := config[SecretAutoCreateNamePrefixKey]; !ok || namePrefix == "" {
+		config[SecretAutoCreateNamePrefixKey] = SecretAutoCreateNamePrefixDefaultValue
+	}
+
+	if mode, ok := config[SecretAutoCreateModeKey]; !ok || mode == "" {
+		config[SecretAutoCreateModeKey] = SecretAutoCreateModeWorkspace
+	}
+
 	if ghScopedToken, ok := config[SecretGhAppTokenRepoScopedKey]; !ok || ghScopedToken == "" {
 		config[SecretGhAppTokenRepoScopedKey] = secretGhAppTokenRepoScopedDefaultValue
 	}
@@ -51,6 +59,58 @@ func SetDefaults(config map[string]string) {
 		config[ErrorDetectionSimpleRegexpKey] = errorDetectionSimpleRegexpValue
 	}
 
+	if noMatchNeutralStatus, ok := config[NoMatchNeutralStatusKey]; !ok || noMatchNeutralStatus == "" {
+		config[NoMatchNeutralStatusKey] = noMatchNeutralStatusValue
+	}
+
+	if observeOnly, ok := config[ObserveOnlyKey]; !ok || observeOnly == "" {
+		config[ObserveOnlyKey] = observeOnlyValue
+	}
+
+	if groupStatuses, ok := config[GroupStatusesByCommitKey]; !ok || groupStatuses == "" {
+		config[GroupStatusesByCommitKey] = groupStatusesByCommitValue
+	}
+
+	if pinStepImages, ok := config[PinStepImagesKey]; !ok || pinStepImages == "" {
+		config[PinStepImagesKey] = pinStepImagesValue
+	}
+
+	if window, ok := config[WebhookDeduplicationWindowKey]; !ok || window == "" {
+		config[WebhookDeduplicationWindowKey] = webhookDeduplicationWindowValue
+	}
+
+	if window, ok := config[PullRequestDebounceWindowKey]; !ok || window == "" {
+		config[PullRequestDebounceWindowKey] = pullRequestDebounceWindowValue
+	}
+
+	if timeout, ok := config[EventFilterTimeoutKey]; !ok || timeout == "" {
+		config[EventFilterTimeoutKey] = eventFilterTimeoutValue
+	}
+
+	if secretScanning, ok := config[SecretScanningKey]; !ok || secretScanning == "" {
+		config[SecretScanningKey] = secretScanningValue
+	}
+
+	if prioritize, ok := config[PrioritizeDefaultBranchPushKey]; !ok || prioritize == "" {
+		config[PrioritizeDefaultBranchPushKey] = prioritizeDefaultBranchPushValue
+	}
+
+	if label, ok := config[NodePoolLabelKey]; !ok || label == "" {
+		config[NodePoolLabelKey] = NodePoolLabelDefaultValue
+	}
+
+	if size, ok := config[AuditLogMaxSizeKey]; !ok || size == "" {
+		config[AuditLogMaxSizeKey] = auditLogMaxSizeValue
+	}
+
+	if files, ok := config[AuditLogMaxFilesKey]; !ok || files == "" {
+		config[AuditLogMaxFilesKey] = auditLogMaxFilesValue
+	}
+
+	if compress, ok := config[AuditLogCompressKey]; !ok || compress == "" {
+		config[AuditLogCompressKey] = auditLogCompressValue
+	}
+
 	if v, ok := config[CustomConsoleNameKey]; !ok || v == "" {
 		config[CustomConsoleNameKey] = v
 	}
@@ -11,8 +11,20 @@ func TestSetDefaults(t *testing.T) {
 	SetDefaults(config)
 	assert.Equal(t, config[RemoteTasksKey], remoteTasksDefaultValue)
 	assert.Equal(t, config[SecretAutoCreateKey], secretAutoCreateDefaultValue)
+	assert.Equal(t, config[SecretAutoCreateNamePrefixKey], SecretAutoCreateNamePrefixDefaultValue)
+	assert.Equal(t, config[SecretAutoCreateModeKey], SecretAutoCreateModeWorkspace)
 	assert.Equal(t, config[BitbucketCloudCheckSourceIPKey], bitbucketCloudCheckSourceIPDefaultValue)
 	assert.Equal(t, config[ApplicationNameKey], PACApplicationNameDefaultValue)
 	assert.Equal(t, config[HubURLKey], HubURLDefaultValue)
 	assert.Equal(t, config[HubCatalogNameKey], hubCatalogNameDefaultValue)
+	assert.Equal(t, config[NoMatchNeutralStatusKey], noMatchNeutralStatusValue)
+	assert.Equal(t, config[WebhookDeduplicationWindowKey], webhookDeduplicationWindowValue)
+	assert.Equal(t, config[PullRequestDebounceWindowKey], pullRequestDebounceWindowValue)
+	assert.Equal(t, config[EventFilterTimeoutKey], eventFilterTimeoutValue)
+	assert.Equal(t, config[SecretScanningKey], secretScanningValue)
+	assert.Equal(t, config[PrioritizeDefaultBranchPushKey], prioritizeDefaultBranchPushValue)
+	assert.Equal(t, config[NodePoolLabelKey], NodePoolLabelDefaultValue)
+	assert.Equal(t, config[AuditLogMaxSizeKey], auditLogMaxSizeValue)
+	assert.Equal(t, config[AuditLogMaxFilesKey], auditLogMaxFilesValue)
+	assert.Equal(t, config[AuditLogCompressKey], auditLogCompressValue)
 }
@@ -0,0 +1,44 @@
+package settings
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	// SecretAutoCreateModeWorkspace creates the git-auth secret with a
+	// .gitconfig and .git-credentials for the basic-auth workspace of the
+	// git-clone task, it is referenced with {{ git_auth_secret }}.
+	SecretAutoCreateModeWorkspace = "workspace"
+	// SecretAutoCreateModeGitConfig creates the git-auth secret as a Tekton
+	// basic-auth secret attached to a ServiceAccount, Tekton writes the
+	// .gitconfig and .git-credentials in the home of every step.
+	SecretAutoCreateModeGitConfig = "gitconfig"
+)
+
+// ValidateSecretNamePrefix checks the prefix of the generated git-auth secret
+// names gives valid secret names once the random suffix is added.
+func ValidateSecretNamePrefix(prefix string) error {
+	if errs := validation.IsDNS1123Subdomain(prefix + "-abcd"); len(errs) > 0 {
+		return fmt.Errorf("invalid secret name prefix %s, it needs to be made of lowercase alphanumeric characters, '-' or '.'", prefix)
+	}
+	return nil
+}
+
+// ValidateServiceAccountName checks the name of the ServiceAccount the git-auth
+// secrets are attached to.
+func ValidateServiceAccountName(name string) error {
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		return fmt.Errorf("invalid service account name %s, it needs to be made of lowercase alphanumeric characters, '-' or '.'", name)
+	}
+	return nil
+}
+
+// ValidateSecretMode checks how the git-auth secrets are exposed.
+func ValidateSecretMode(mode string) error {
+	if mode != SecretAutoCreateModeWorkspace && mode != SecretAutoCreateModeGitConfig {
+		return fmt.Errorf("acceptable values: %s or %s", SecretAutoCreateModeWorkspace, SecretAutoCreateModeGitConfig)
+	}
+	return nil
+}
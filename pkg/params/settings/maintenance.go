@@ -0,0 +1,73 @@
+package settings
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MaintenanceWindow is a period during which the PipelineRuns created for the
+// incoming events are queued instead of being started.
+type MaintenanceWindow struct {
+	Start time.Time
+	End   time.Time
+}
+
+func (w MaintenanceWindow) String() string {
+	return w.Start.Format(time.RFC3339) + "/" + w.End.Format(time.RFC3339)
+}
+
+// ParseMaintenanceWindows parses the maintenance windows, the start/end pairs
+// of RFC3339 times are separated by commas or newlines and sorted by start,
+// ie:
+//
+//	2023-05-20T22:00:00Z/2023-05-21T02:00:00Z
+//	2023-06-17T22:00:00+02:00/2023-06-18T01:00:00+02:00
+func ParseMaintenanceWindows(value string) ([]MaintenanceWindow, error) {
+	var ret []MaintenanceWindow
+	for _, entry := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == '\n' }) {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		start, end, found := strings.Cut(entry, "/")
+		if !found {
+			return nil, fmt.Errorf("invalid window %q, it needs to be in the start/end format", entry)
+		}
+		w := MaintenanceWindow{}
+		var err error
+		if w.Start, err = time.Parse(time.RFC3339, strings.TrimSpace(start)); err != nil {
+			return nil, fmt.Errorf("invalid start of window %q: %w", entry, err)
+		}
+		if w.End, err = time.Parse(time.RFC3339, strings.TrimSpace(end)); err != nil {
+			return nil, fmt.Errorf("invalid end of window %q: %w", entry, err)
+		}
+		if !w.End.After(w.Start) {
+			return nil, fmt.Errorf("invalid window %q, it needs to end after its start", entry)
+		}
+		ret = append(ret, w)
+	}
+	sort.SliceStable(ret, func(i, j int) bool { return ret[i].Start.Before(ret[j].Start) })
+	return ret, nil
+}
+
+// MaintenanceEnd returns when the maintenance in progress at now ends, the
+// windows overlapping or following each other without a gap are one
+// maintenance. It returns false when no window is in progress.
+func (s *Settings) MaintenanceEnd(now time.Time) (time.Time, bool) {
+	var end time.Time
+	for _, w := range s.MaintenanceWindows {
+		current := end
+		if current.IsZero() {
+			current = now
+		}
+		if w.Start.After(current) {
+			break
+		}
+		if w.End.After(current) {
+			end = w.End
+		}
+	}
+	return end, !end.IsZero()
+}
@@ -0,0 +1,95 @@
+package settings
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParseMaintenanceWindows(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    []string
+		wantErr string
+	}{
+		{
+			name:  "empty",
+			value: "",
+		},
+		{
+			name:  "separated by commas and newlines",
+			value: "2023-06-17T22:00:00+02:00/2023-06-18T01:00:00+02:00,\n 2023-05-20T22:00:00Z / 2023-05-21T02:00:00Z\n",
+			want:  []string{"2023-05-20T22:00:00Z/2023-05-21T02:00:00Z", "2023-06-17T22:00:00+02:00/2023-06-18T01:00:00+02:00"},
+		},
+		{
+			name:    "no end",
+			value:   "2023-05-20T22:00:00Z",
+			wantErr: `invalid window "2023-05-20T22:00:00Z", it needs to be in the start/end format`,
+		},
+		{
+			name:    "invalid time",
+			value:   "2023-05-20 22:00/2023-05-21T02:00:00Z",
+			wantErr: `invalid start of window "2023-05-20 22:00/2023-05-21T02:00:00Z": parsing time "2023-05-20 22:00" as "2006-01-02T15:04:05Z07:00": cannot parse " 22:00" as "T"`,
+		},
+		{
+			name:    "empty window",
+			value:   "2023-05-20T22:00:00Z/2023-05-20T22:00:00Z",
+			wantErr: `invalid window "2023-05-20T22:00:00Z/2023-05-20T22:00:00Z", it needs to end after its start`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMaintenanceWindows(tt.value)
+			if tt.wantErr != "" {
+				assert.Error(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			var windows []string
+			for _, w := range got {
+				windows = append(windows, w.String())
+			}
+			assert.DeepEqual(t, windows, tt.want)
+		})
+	}
+}
+
+func TestMaintenanceEnd(t *testing.T) {
+	windows, err := ParseMaintenanceWindows(`2023-05-20T22:00:00Z/2023-05-21T02:00:00Z
+2023-05-21T01:00:00Z/2023-05-21T03:00:00Z
+2023-05-21T03:00:00Z/2023-05-21T04:00:00Z
+2023-05-21T10:00:00Z/2023-05-21T11:00:00Z`)
+	assert.NilError(t, err)
+	s := &Settings{MaintenanceWindows: windows}
+	at := func(value string) time.Time {
+		ts, err := time.Parse(time.RFC3339, value)
+		assert.NilError(t, err)
+		return ts
+	}
+	tests := []struct {
+		name string
+		now  string
+		want string
+	}{
+		{name: "before the windows", now: "2023-05-20T21:59:59Z"},
+		{name: "at the start of a window", now: "2023-05-20T22:00:00Z", want: "2023-05-21T04:00:00Z"},
+		{name: "in overlapping windows", now: "2023-05-21T01:30:00Z", want: "2023-05-21T04:00:00Z"},
+		{name: "in the last of the following windows", now: "2023-05-21T03:30:00Z", want: "2023-05-21T04:00:00Z"},
+		{name: "between windows", now: "2023-05-21T04:00:00Z"},
+		{name: "in a single window", now: "2023-05-21T10:30:00Z", want: "2023-05-21T11:00:00Z"},
+		{name: "after the windows", now: "2023-05-21T12:00:00Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			end, in := s.MaintenanceEnd(at(tt.now))
+			assert.Equal(t, in, tt.want != "")
+			if tt.want != "" {
+				assert.Equal(t, end.UTC(), at(tt.want))
+			}
+		})
+	}
+	_, in := (&Settings{}).MaintenanceEnd(time.Now())
+	assert.Assert(t, !in)
+}
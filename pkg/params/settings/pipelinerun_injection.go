@@ -0,0 +1,71 @@
+package settings
+
+import (
+	"fmt"
+
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// PipelineRunInjection are the steps and sidecars injected in every task of
+// the PipelineRuns created by Pipelines as Code, ie: a compliance scanner
+// running after the steps of the tasks or a cache warmer running before them.
+type PipelineRunInjection struct {
+	StepsBefore []tektonv1.Step    `json:"stepsBefore,omitempty"`
+	StepsAfter  []tektonv1.Step    `json:"stepsAfter,omitempty"`
+	Sidecars    []tektonv1.Sidecar `json:"sidecars,omitempty"`
+}
+
+// ParsePipelineRunInjection parses the injection template setting, a yaml
+// document with the steps to add before and after the steps of every task and
+// the sidecars to add to them, ie:
+//
+//	stepsAfter:
+//	  - name: compliance-scan
+//	    image: registry.example.com/scanner
+//	    script: scan /workspace
+//	sidecars:
+//	  - name: cache
+//	    image: registry.example.com/cache
+//
+// An empty template injects nothing and returns nil.
+func ParsePipelineRunInjection(value string) (*PipelineRunInjection, error) {
+	injection := &PipelineRunInjection{}
+	if err := yaml.UnmarshalStrict([]byte(value), injection); err != nil {
+		return nil, fmt.Errorf("cannot parse the template: %w", err)
+	}
+	if len(injection.StepsBefore)+len(injection.StepsAfter)+len(injection.Sidecars) == 0 {
+		return nil, nil
+	}
+
+	names := map[string]bool{}
+	steps := append(append([]tektonv1.Step{}, injection.StepsBefore...), injection.StepsAfter...)
+	for _, step := range steps {
+		if err := validateInjected("step", step.Name, step.Image, names); err != nil {
+			return nil, err
+		}
+	}
+	names = map[string]bool{}
+	for _, sidecar := range injection.Sidecars {
+		if err := validateInjected("sidecar", sidecar.Name, sidecar.Image, names); err != nil {
+			return nil, err
+		}
+	}
+	return injection, nil
+}
+
+// validateInjected checks an injected step or sidecar has a unique name, to
+// be told apart from the ones of the tasks, and an image.
+func validateInjected(kind, name, image string, names map[string]bool) error {
+	if name == "" {
+		return fmt.Errorf("every injected %s needs a name", kind)
+	}
+	if names[name] {
+		return fmt.Errorf("the %s %s is injected more than once", kind, name)
+	}
+	names[name] = true
+	if image == "" {
+		return fmt.Errorf("the injected %s %s has no image", kind, name)
+	}
+	return nil
+}
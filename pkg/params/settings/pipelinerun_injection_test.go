@@ -0,0 +1,83 @@
+package settings
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParsePipelineRunInjection(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		wantSteps   []string
+		wantSidecar []string
+		wantErr     string
+	}{
+		{
+			name:  "empty",
+			value: "",
+		},
+		{
+			name: "steps and sidecars",
+			value: `
+stepsBefore:
+  - name: warm-cache
+    image: cache
+stepsAfter:
+  - name: compliance-scan
+    image: scanner
+    script: scan /workspace
+sidecars:
+  - name: cache
+    image: cache
+`,
+			wantSteps:   []string{"warm-cache", "compliance-scan"},
+			wantSidecar: []string{"cache"},
+		},
+		{
+			name:    "unknown field",
+			value:   "steps:\n  - name: scan\n    image: scanner\n",
+			wantErr: `cannot parse the template: error unmarshaling JSON: while decoding JSON: json: unknown field "steps"`,
+		},
+		{
+			name:    "step without name",
+			value:   "stepsBefore:\n  - image: scanner\n",
+			wantErr: "every injected step needs a name",
+		},
+		{
+			name:    "step injected before and after",
+			value:   "stepsBefore:\n  - name: scan\n    image: scanner\nstepsAfter:\n  - name: scan\n    image: scanner\n",
+			wantErr: "the step scan is injected more than once",
+		},
+		{
+			name:    "sidecar without image",
+			value:   "sidecars:\n  - name: cache\n",
+			wantErr: "the injected sidecar cache has no image",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePipelineRunInjection(tt.value)
+			if tt.wantErr != "" {
+				assert.Error(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			if tt.wantSteps == nil && tt.wantSidecar == nil {
+				assert.Assert(t, got == nil)
+				return
+			}
+			steps := []string{}
+			for _, step := range append(got.StepsBefore, got.StepsAfter...) {
+				steps = append(steps, step.Name)
+			}
+			assert.DeepEqual(t, steps, tt.wantSteps)
+			sidecars := []string{}
+			for _, sidecar := range got.Sidecars {
+				sidecars = append(sidecars, sidecar.Name)
+			}
+			assert.DeepEqual(t, sidecars, tt.wantSidecar)
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package settings
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// ParsePipelineRunMetadata parses the labels or annotations to add to the
+// created PipelineRuns, the key=template pairs are separated by newlines and
+// the templates can use the dynamic variables, ie:
+//
+//	pipelinesascode.tekton.dev/team={{ pull_request_labels }}
+//	cost-center={{ repo_owner }}
+func ParsePipelineRunMetadata(value string) (map[string]string, error) {
+	var ret map[string]string
+	for _, entry := range strings.Split(value, "\n") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, template, found := strings.Cut(entry, "=")
+		key, template = strings.TrimSpace(key), strings.TrimSpace(template)
+		if !found || key == "" || template == "" {
+			return nil, fmt.Errorf("invalid entry %q, it needs to be in the key=template format", entry)
+		}
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid key %q: %s", key, strings.Join(errs, ", "))
+		}
+		if ret == nil {
+			ret = map[string]string{}
+		}
+		ret[key] = template
+	}
+	return ret, nil
+}
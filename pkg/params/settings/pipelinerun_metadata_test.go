@@ -0,0 +1,50 @@
+package settings
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParsePipelineRunMetadata(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    map[string]string
+		wantErr string
+	}{
+		{
+			name:  "empty",
+			value: "",
+		},
+		{
+			name:  "separated by newlines",
+			value: "example.com/team = {{ pull_request_labels }}\n\ncost-center={{ repo_owner }}-{{ target_branch }}\n",
+			want: map[string]string{
+				"example.com/team": "{{ pull_request_labels }}",
+				"cost-center":      "{{ repo_owner }}-{{ target_branch }}",
+			},
+		},
+		{
+			name:    "no template",
+			value:   "team",
+			wantErr: `invalid entry "team", it needs to be in the key=template format`,
+		},
+		{
+			name:    "invalid key",
+			value:   "my team={{ sender }}",
+			wantErr: `invalid key "my team": name part must consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character (e.g. 'MyName',  or 'my.name',  or '123-abc', regex used for validation is '([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]')`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePipelineRunMetadata(tt.value)
+			if tt.wantErr != "" {
+				assert.Error(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.DeepEqual(t, got, tt.want)
+		})
+	}
+}
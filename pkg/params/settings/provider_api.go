@@ -0,0 +1,148 @@
+package settings
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ProviderAPIClientDefault is the name of the entry of the provider API
+// clients setting applying to the providers without their own entry.
+const ProviderAPIClientDefault = "default"
+
+// providerAPIClientNames are the providers which can be configured in the
+// provider API clients setting.
+var providerAPIClientNames = []string{ProviderAPIClientDefault, "github", "gitlab", "gitea", "bitbucket-cloud", "bitbucket-server"}
+
+// ProviderAPIClient configures how the client of a git provider calls its
+// API, the zero value doesn't change anything.
+type ProviderAPIClient struct {
+	// Timeout is the maximum duration of a request, 0 for no timeout.
+	Timeout time.Duration
+	// Retries is the number of times a read request failing with a network
+	// error or a server error is retried.
+	Retries int
+	// CircuitBreakerThreshold is the number of consecutive failures to the
+	// API after which the requests fail right away, 0 to disable it.
+	CircuitBreakerThreshold int
+	// CircuitBreakerCooldown is how long the requests fail right away before
+	// one is let through to check if the API is back.
+	CircuitBreakerCooldown time.Duration
+}
+
+// ParseProviderAPIClients parses the provider API clients setting, an entry
+// per line made of the provider name and its comma separated options, ie:
+//
+//	default: timeout=30s
+//	gitlab: timeout=10s, retries=2, circuit-breaker-threshold=5, circuit-breaker-cooldown=1m
+//
+// The options not set for a provider are taken from the default entry.
+func ParseProviderAPIClients(value string) (map[string]ProviderAPIClient, error) {
+	entries := map[string]string{}
+	for _, line := range strings.Split(value, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		name, options, found := strings.Cut(line, ":")
+		name = strings.TrimSpace(name)
+		if !found || name == "" {
+			return nil, fmt.Errorf("invalid entry %q, it needs to be in the provider: option=value, ... format", line)
+		}
+		known := false
+		for _, n := range providerAPIClientNames {
+			if n == name {
+				known = true
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("invalid provider %q, acceptable values: %s", name, strings.Join(providerAPIClientNames, ", "))
+		}
+		if _, ok := entries[name]; ok {
+			return nil, fmt.Errorf("provider %s is defined more than once", name)
+		}
+		entries[name] = options
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+
+	ret := map[string]ProviderAPIClient{}
+	defaults, err := parseProviderAPIClientOptions(ProviderAPIClient{}, entries[ProviderAPIClientDefault])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ProviderAPIClientDefault, err)
+	}
+	ret[ProviderAPIClientDefault] = defaults
+	for name, options := range entries {
+		if name == ProviderAPIClientDefault {
+			continue
+		}
+		if ret[name], err = parseProviderAPIClientOptions(defaults, options); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return ret, nil
+}
+
+func parseProviderAPIClientOptions(client ProviderAPIClient, options string) (ProviderAPIClient, error) {
+	for _, option := range strings.Split(options, ",") {
+		option = strings.TrimSpace(option)
+		if option == "" {
+			continue
+		}
+		key, value, found := strings.Cut(option, "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !found || value == "" {
+			return client, fmt.Errorf("invalid option %q, it needs to be in the option=value format", option)
+		}
+		var err error
+		switch key {
+		case "timeout":
+			client.Timeout, err = parsePositiveDuration(value)
+		case "retries":
+			client.Retries, err = parsePositiveInt(value)
+		case "circuit-breaker-threshold":
+			client.CircuitBreakerThreshold, err = parsePositiveInt(value)
+		case "circuit-breaker-cooldown":
+			client.CircuitBreakerCooldown, err = parsePositiveDuration(value)
+		default:
+			return client, fmt.Errorf("invalid option %q, acceptable options: timeout, retries, circuit-breaker-threshold, circuit-breaker-cooldown", key)
+		}
+		if err != nil {
+			return client, fmt.Errorf("invalid %s %q: %w", key, value, err)
+		}
+	}
+	return client, nil
+}
+
+func parsePositiveDuration(value string) (time.Duration, error) {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("it cannot be negative")
+	}
+	return d, nil
+}
+
+func parsePositiveInt(value string) (int, error) {
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, err
+	}
+	if i < 0 {
+		return 0, fmt.Errorf("it cannot be negative")
+	}
+	return i, nil
+}
+
+// ProviderAPIClientFor returns the API client configuration of the provider,
+// or the default one if the provider has no entry.
+func ProviderAPIClientFor(clients map[string]ProviderAPIClient, provider string) ProviderAPIClient {
+	if client, ok := clients[provider]; ok {
+		return client
+	}
+	return clients[ProviderAPIClientDefault]
+}
@@ -0,0 +1,80 @@
+package settings
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParseProviderAPIClients(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    map[string]ProviderAPIClient
+		wantErr string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "providers inherit the default options",
+			value: `
+default: timeout=30s, retries=1
+gitlab: timeout=10s, circuit-breaker-threshold=5, circuit-breaker-cooldown=1m
+github: retries=0
+`,
+			want: map[string]ProviderAPIClient{
+				"default": {Timeout: 30 * time.Second, Retries: 1},
+				"gitlab":  {Timeout: 10 * time.Second, Retries: 1, CircuitBreakerThreshold: 5, CircuitBreakerCooldown: time.Minute},
+				"github":  {Timeout: 30 * time.Second},
+			},
+		},
+		{
+			name:    "unknown provider",
+			value:   "gitbucket: timeout=1s",
+			wantErr: `invalid provider "gitbucket", acceptable values: default, github, gitlab, gitea, bitbucket-cloud, bitbucket-server`,
+		},
+		{
+			name:    "missing provider",
+			value:   "timeout=1s",
+			wantErr: `invalid entry "timeout=1s", it needs to be in the provider: option=value, ... format`,
+		},
+		{
+			name:    "provider defined twice",
+			value:   "gitlab: timeout=1s\ngitlab: retries=1",
+			wantErr: "provider gitlab is defined more than once",
+		},
+		{
+			name:    "unknown option",
+			value:   "gitea: backoff=1s",
+			wantErr: `gitea: invalid option "backoff", acceptable options: timeout, retries, circuit-breaker-threshold, circuit-breaker-cooldown`,
+		},
+		{
+			name:    "negative retries",
+			value:   "default: retries=-1",
+			wantErr: `default: invalid retries "-1": it cannot be negative`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseProviderAPIClients(tt.value)
+			if tt.wantErr != "" {
+				assert.Error(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.DeepEqual(t, got, tt.want)
+		})
+	}
+}
+
+func TestProviderAPIClientFor(t *testing.T) {
+	clients := map[string]ProviderAPIClient{
+		"default": {Timeout: time.Minute},
+		"gitlab":  {Timeout: time.Second},
+	}
+	assert.Equal(t, ProviderAPIClientFor(clients, "gitlab").Timeout, time.Second)
+	assert.Equal(t, ProviderAPIClientFor(clients, "gitea").Timeout, time.Minute)
+	assert.Equal(t, ProviderAPIClientFor(nil, "gitea"), ProviderAPIClient{})
+}
@@ -2,9 +2,14 @@ package settings
 
 import (
 	"fmt"
+	"net"
 	"net/url"
 	"regexp"
 	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 func Validate(config map[string]string) error {
@@ -14,6 +19,24 @@ func Validate(config map[string]string) error {
 		}
 	}
 
+	if v, ok := config[SecretAutoCreateNamePrefixKey]; ok && v != "" {
+		if err := ValidateSecretNamePrefix(v); err != nil {
+			return fmt.Errorf("invalid value for key %v: %w", SecretAutoCreateNamePrefixKey, err)
+		}
+	}
+
+	if v, ok := config[SecretAutoCreateServiceAccountKey]; ok && v != "" {
+		if err := ValidateServiceAccountName(v); err != nil {
+			return fmt.Errorf("invalid value for key %v: %w", SecretAutoCreateServiceAccountKey, err)
+		}
+	}
+
+	if v, ok := config[SecretAutoCreateModeKey]; ok && v != "" {
+		if err := ValidateSecretMode(v); err != nil {
+			return fmt.Errorf("invalid value for key %v: %w", SecretAutoCreateModeKey, err)
+		}
+	}
+
 	if remoteTask, ok := config[RemoteTasksKey]; ok && remoteTask != "" {
 		if !isValidBool(remoteTask) {
 			return fmt.Errorf("invalid value for key %v, acceptable values: true or false", RemoteTasksKey)
@@ -26,6 +49,14 @@ func Validate(config map[string]string) error {
 		}
 	}
 
+	if proxies, ok := config[IncomingTrustedProxiesKey]; ok && proxies != "" {
+		for _, cidr := range strings.Split(proxies, ",") {
+			if _, _, err := net.ParseCIDR(strings.TrimSpace(cidr)); err != nil {
+				return fmt.Errorf("invalid value for key %v: %w", IncomingTrustedProxiesKey, err)
+			}
+		}
+	}
+
 	if runs, ok := config[MaxKeepRunUpperLimitKey]; ok && runs != "" {
 		_, err := strconv.Atoi(runs)
 		if err != nil {
@@ -64,6 +95,139 @@ func Validate(config map[string]string) error {
 		}
 	}
 
+	if check, ok := config[NoMatchNeutralStatusKey]; ok && check != "" {
+		if !isValidBool(check) {
+			return fmt.Errorf("invalid value for key %v, acceptable values: true or false", NoMatchNeutralStatusKey)
+		}
+	}
+
+	if check, ok := config[ObserveOnlyKey]; ok && check != "" {
+		if !isValidBool(check) {
+			return fmt.Errorf("invalid value for key %v, acceptable values: true or false", ObserveOnlyKey)
+		}
+	}
+
+	if check, ok := config[GroupStatusesByCommitKey]; ok && check != "" {
+		if !isValidBool(check) {
+			return fmt.Errorf("invalid value for key %v, acceptable values: true or false", GroupStatusesByCommitKey)
+		}
+	}
+
+	if check, ok := config[PinStepImagesKey]; ok && check != "" {
+		if !isValidBool(check) {
+			return fmt.Errorf("invalid value for key %v, acceptable values: true or false", PinStepImagesKey)
+		}
+	}
+
+	if window, ok := config[WebhookDeduplicationWindowKey]; ok && window != "" {
+		d, err := time.ParseDuration(window)
+		if err != nil {
+			return fmt.Errorf("invalid value for key %v, invalid duration: %w", WebhookDeduplicationWindowKey, err)
+		}
+		if d < 0 {
+			return fmt.Errorf("invalid value for key %v, the duration cannot be negative", WebhookDeduplicationWindowKey)
+		}
+	}
+
+	if window, ok := config[PullRequestDebounceWindowKey]; ok && window != "" {
+		d, err := time.ParseDuration(window)
+		if err != nil {
+			return fmt.Errorf("invalid value for key %v, invalid duration: %w", PullRequestDebounceWindowKey, err)
+		}
+		if d < 0 {
+			return fmt.Errorf("invalid value for key %v, the duration cannot be negative", PullRequestDebounceWindowKey)
+		}
+	}
+
+	if v, ok := config[EventFilterURLKey]; ok && v != "" {
+		u, err := url.Parse(v)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid value for key %v, it must be an http or https URL", EventFilterURLKey)
+		}
+	}
+
+	if timeout, ok := config[EventFilterTimeoutKey]; ok && timeout != "" {
+		d, err := time.ParseDuration(timeout)
+		if err != nil {
+			return fmt.Errorf("invalid value for key %v, invalid duration: %w", EventFilterTimeoutKey, err)
+		}
+		if d <= 0 {
+			return fmt.Errorf("invalid value for key %v, the duration must be positive", EventFilterTimeoutKey)
+		}
+	}
+
+	if check, ok := config[SecretScanningKey]; ok && check != "" {
+		if !isValidBool(check) {
+			return fmt.Errorf("invalid value for key %v, acceptable values: true or false", SecretScanningKey)
+		}
+	}
+
+	if check, ok := config[PrioritizeDefaultBranchPushKey]; ok && check != "" {
+		if !isValidBool(check) {
+			return fmt.Errorf("invalid value for key %v, acceptable values: true or false", PrioritizeDefaultBranchPushKey)
+		}
+	}
+
+	if v, ok := config[NodePoolLabelKey]; ok && v != "" {
+		if errs := validation.IsQualifiedName(v); len(errs) > 0 {
+			return fmt.Errorf("invalid value for key %v, invalid label name: %s", NodePoolLabelKey, strings.Join(errs, ", "))
+		}
+	}
+
+	for _, key := range []string{AuditLogMaxSizeKey, AuditLogMaxFilesKey} {
+		if v, ok := config[key]; ok && v != "" {
+			if n, err := strconv.Atoi(v); err != nil || n <= 0 {
+				return fmt.Errorf("invalid value for key %v, it must be a positive number", key)
+			}
+		}
+	}
+
+	if check, ok := config[AuditLogCompressKey]; ok && check != "" {
+		if !isValidBool(check) {
+			return fmt.Errorf("invalid value for key %v, acceptable values: true or false", AuditLogCompressKey)
+		}
+	}
+
+	if v, ok := config[CustomEventTypesKey]; ok && v != "" {
+		if _, err := ParseCustomEventTypes(v); err != nil {
+			return fmt.Errorf("invalid value for key %v: %w", CustomEventTypesKey, err)
+		}
+	}
+
+	for _, key := range []string{PipelineRunLabelsKey, PipelineRunAnnotationsKey} {
+		if v, ok := config[key]; ok && v != "" {
+			if _, err := ParsePipelineRunMetadata(v); err != nil {
+				return fmt.Errorf("invalid value for key %v: %w", key, err)
+			}
+		}
+	}
+
+	if v, ok := config[MaintenanceWindowsKey]; ok && v != "" {
+		if _, err := ParseMaintenanceWindows(v); err != nil {
+			return fmt.Errorf("invalid value for key %v: %w", MaintenanceWindowsKey, err)
+		}
+	}
+
+	if v, ok := config[PipelineRunInjectionTemplateKey]; ok && v != "" {
+		if _, err := ParsePipelineRunInjection(v); err != nil {
+			return fmt.Errorf("invalid value for key %v: %w", PipelineRunInjectionTemplateKey, err)
+		}
+	}
+
+	if v, ok := config[ProviderAPIClientsKey]; ok && v != "" {
+		if _, err := ParseProviderAPIClients(v); err != nil {
+			return fmt.Errorf("invalid value for key %v: %w", ProviderAPIClientsKey, err)
+		}
+	}
+
+	for _, key := range []string{ApplicationAvatarURLKey, ApplicationDetailsURLKey} {
+		if v, ok := config[key]; ok && v != "" {
+			if _, err := url.ParseRequestURI(v); err != nil {
+				return fmt.Errorf("invalid value for key %v, invalid url: %w", key, err)
+			}
+		}
+	}
+
 	if v, ok := config[CustomConsoleURLKey]; ok && v != "" {
 		if _, err := url.ParseRequestURI(v); err != nil {
 			return fmt.Errorf("invalid value for key %v, invalid url: %w", CustomConsoleURLKey, err)
@@ -51,6 +51,132 @@ func TestValidate(t *testing.T) {
 			},
 			wantErr: "invalid value for key bitbucket-cloud-check-source-ip, acceptable values: true or false",
 		},
+		{
+			name: "invalid incoming trusted proxies",
+			config: map[string]string{
+				IncomingTrustedProxiesKey: "10.0.0.0/8, 192.0.2.1",
+			},
+			wantErr: "invalid value for key incoming-trusted-proxies: invalid CIDR address: 192.0.2.1",
+		},
+		{
+			name: "invalid custom event types",
+			config: map[string]string{
+				CustomEventTypesKey: "pr-approved",
+			},
+			wantErr: `invalid value for key custom-event-types: invalid custom event type "pr-approved", it needs to be in the name=event[:action[:state]] format`,
+		},
+		{
+			name: "invalid pipelinerun labels",
+			config: map[string]string{
+				PipelineRunLabelsKey: "team",
+			},
+			wantErr: `invalid value for key pipelinerun-labels: invalid entry "team", it needs to be in the key=template format`,
+		},
+		{
+			name: "invalid provider api clients",
+			config: map[string]string{
+				ProviderAPIClientsKey: "gitlab: timeout=10",
+			},
+			wantErr: `invalid value for key provider-api-clients: gitlab: invalid timeout "10": time: missing unit in duration "10"`,
+		},
+		{
+			name: "invalid group statuses by commit",
+			config: map[string]string{
+				GroupStatusesByCommitKey: "yes",
+			},
+			wantErr: "invalid value for key group-statuses-by-commit, acceptable values: true or false",
+		},
+		{
+			name: "invalid secret auto create name prefix",
+			config: map[string]string{
+				SecretAutoCreateNamePrefixKey: "PAC_auth",
+			},
+			wantErr: "invalid value for key secret-auto-create-name-prefix: invalid secret name prefix PAC_auth, it needs to be made of lowercase alphanumeric characters, '-' or '.'",
+		},
+		{
+			name: "invalid secret auto create service account",
+			config: map[string]string{
+				SecretAutoCreateServiceAccountKey: "pipeline runner",
+			},
+			wantErr: "invalid value for key secret-auto-create-service-account: invalid service account name pipeline runner, it needs to be made of lowercase alphanumeric characters, '-' or '.'",
+		},
+		{
+			name: "invalid secret auto create mode",
+			config: map[string]string{
+				SecretAutoCreateModeKey: "env",
+			},
+			wantErr: "invalid value for key secret-auto-create-mode: acceptable values: workspace or gitconfig",
+		},
+		{
+			name: "invalid webhook deduplication window",
+			config: map[string]string{
+				WebhookDeduplicationWindowKey: "10",
+			},
+			wantErr: `invalid value for key webhook-deduplication-window, invalid duration: time: missing unit in duration "10"`,
+		},
+		{
+			name: "negative webhook deduplication window",
+			config: map[string]string{
+				WebhookDeduplicationWindowKey: "-1m",
+			},
+			wantErr: "invalid value for key webhook-deduplication-window, the duration cannot be negative",
+		},
+		{
+			name: "invalid pull request debounce window",
+			config: map[string]string{
+				PullRequestDebounceWindowKey: "30",
+			},
+			wantErr: `invalid value for key pull-request-debounce-window, invalid duration: time: missing unit in duration "30"`,
+		},
+		{
+			name: "invalid event filter url",
+			config: map[string]string{
+				EventFilterURLKey: "policy.svc/filter",
+			},
+			wantErr: "invalid value for key event-filter-url, it must be an http or https URL",
+		},
+		{
+			name: "zero event filter timeout",
+			config: map[string]string{
+				EventFilterTimeoutKey: "0s",
+			},
+			wantErr: "invalid value for key event-filter-timeout, the duration must be positive",
+		},
+		{
+			name: "invalid secret scanning",
+			config: map[string]string{
+				SecretScanningKey: "yes",
+			},
+			wantErr: "invalid value for key secret-scanning, acceptable values: true or false",
+		},
+		{
+			name: "invalid prioritize default branch push",
+			config: map[string]string{
+				PrioritizeDefaultBranchPushKey: "1",
+			},
+			wantErr: "invalid value for key prioritize-default-branch-push, acceptable values: true or false",
+		},
+		{
+			name: "invalid node pool label",
+			config: map[string]string{
+				NodePoolLabelKey: "node pool",
+			},
+			wantErr: "invalid value for key node-pool-label, invalid label name: name part must consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character (e.g. 'MyName',  or 'my.name',  or '123-abc', regex used for validation is '([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]')",
+		},
+		{
+			name: "invalid audit log max size",
+			config: map[string]string{
+				AuditLogMaxSizeKey: "0",
+			},
+			wantErr: "invalid value for key audit-log-max-size, it must be a positive number",
+		},
+		{
+			name: "invalid audit log compress",
+			config: map[string]string{
+				AuditLogCompressKey: "gzip",
+			},
+			wantErr: "invalid value for key audit-log-compress, acceptable values: true or false",
+		},
 		{
 			name: "invalid url value",
 			config: map[string]string{
@@ -58,6 +184,27 @@ func TestValidate(t *testing.T) {
 			},
 			wantErr: "invalid value for key tekton-dashboard-url, invalid url: parse \"abc.xyz\": invalid URI for request",
 		},
+		{
+			name: "invalid application avatar url",
+			config: map[string]string{
+				ApplicationAvatarURLKey: "avatar.png",
+			},
+			wantErr: "invalid value for key application-avatar-url, invalid url: parse \"avatar.png\": invalid URI for request",
+		},
+		{
+			name: "injected step without image",
+			config: map[string]string{
+				PipelineRunInjectionTemplateKey: "stepsAfter:\n  - name: scan\n",
+			},
+			wantErr: "invalid value for key pipelinerun-injection-template: the injected step scan has no image",
+		},
+		{
+			name: "maintenance window ending before its start",
+			config: map[string]string{
+				MaintenanceWindowsKey: "2023-05-21T02:00:00Z/2023-05-20T22:00:00Z",
+			},
+			wantErr: `invalid value for key maintenance-windows: invalid window "2023-05-21T02:00:00Z/2023-05-20T22:00:00Z", it needs to end after its start`,
+		},
 		{
 			name: "empty values",
 			config: map[string]string{
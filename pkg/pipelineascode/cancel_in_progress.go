@@ -0,0 +1,69 @@
+package pipelineascode
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/action"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// cancelInProgress cancels the PipelineRuns superseded by pr when it has the
+// cancel-in-progress annotation, the superseded PipelineRuns are the ones
+// still running for the same PipelineRun of the same pull request, or of the
+// same branch on push events. The PipelineRuns without the annotation are
+// always allowed to finish.
+func (p *PacRun) cancelInProgress(ctx context.Context, repo *v1alpha1.Repository, pr *tektonv1.PipelineRun) error {
+	if pr.GetAnnotations()[keys.CancelInProgress] != "true" || pr.GetLabels()[keys.OriginalPRName] == "" {
+		return nil
+	}
+
+	selector := map[string]string{
+		keys.Repository:     pr.GetLabels()[keys.Repository],
+		keys.OriginalPRName: pr.GetLabels()[keys.OriginalPRName],
+	}
+	if p.event.PullRequestNumber != 0 {
+		selector[keys.PullRequest] = strconv.Itoa(p.event.PullRequestNumber)
+	} else {
+		selector[keys.EventType] = pr.GetLabels()[keys.EventType]
+		selector[keys.Branch] = pr.GetLabels()[keys.Branch]
+	}
+	prs, err := p.run.Clients.Tekton.TektonV1().PipelineRuns(pr.GetNamespace()).List(ctx, metav1.ListOptions{
+		LabelSelector: getLabelSelector(selector),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list pipelineRuns : %w", err)
+	}
+
+	var wg sync.WaitGroup
+	for _, previous := range prs.Items {
+		if previous.GetName() == pr.GetName() || previous.IsDone() {
+			continue
+		}
+		if previous.IsCancelled() || previous.IsGracefullyCancelled() || previous.IsGracefullyStopped() {
+			continue
+		}
+
+		wg.Add(1)
+		go func(ctx context.Context, previous tektonv1.PipelineRun) {
+			defer wg.Done()
+			if _, err := action.PatchPipelineRun(ctx, p.logger, "cancel patch", p.run.Clients.Tekton, &previous, cancelMergePatch); err != nil {
+				errMsg := fmt.Sprintf("failed to cancel pipelineRun %s/%s: %s", previous.GetNamespace(), previous.GetName(), err.Error())
+				p.eventEmitter.EmitMessage(repo, zap.ErrorLevel, "RepositoryPipelineRun", errMsg)
+				return
+			}
+			msg := fmt.Sprintf("PipelineRun %s has been cancelled since it has been superseded by %s",
+				previous.GetName(), pr.GetName())
+			p.eventEmitter.EmitMessage(repo, zap.InfoLevel, "RepositoryPipelineRunCancelled", msg)
+		}(ctx, previous)
+	}
+	wg.Wait()
+
+	return nil
+}
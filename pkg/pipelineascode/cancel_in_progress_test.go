@@ -0,0 +1,127 @@
+package pipelineascode
+
+import (
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	pipelinev1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	knativeduckv1 "knative.dev/pkg/apis/duck/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestCancelInProgress(t *testing.T) {
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+
+	makePR := func(name, prName, pullRequest, branch string, done bool) *pipelinev1.PipelineRun {
+		pr := &pipelinev1.PipelineRun{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "foo",
+				Labels: map[string]string{
+					keys.Repository:     "foo",
+					keys.OriginalPRName: prName,
+					keys.EventType:      "pull_request",
+					keys.Branch:         branch,
+				},
+			},
+		}
+		if pullRequest != "" {
+			pr.Labels[keys.PullRequest] = pullRequest
+		} else {
+			pr.Labels[keys.EventType] = "push"
+		}
+		if done {
+			pr.Status = pipelinev1.PipelineRunStatus{
+				Status: knativeduckv1.Status{
+					Conditions: knativeduckv1.Conditions{{Type: apis.ConditionSucceeded, Status: corev1.ConditionTrue}},
+				},
+			}
+		}
+		return pr
+	}
+
+	tests := []struct {
+		name                  string
+		event                 *info.Event
+		annotation            string
+		newPR                 *pipelinev1.PipelineRun
+		pipelineRuns          []*pipelinev1.PipelineRun
+		cancelledPipelineRuns map[string]bool
+	}{
+		{
+			name:       "cancel the previous runs of the pull request",
+			event:      &info.Event{PullRequestNumber: 11},
+			annotation: "true",
+			newPR:      makePR("build-new", "build", "11", "main", false),
+			pipelineRuns: []*pipelinev1.PipelineRun{
+				makePR("build-new", "build", "11", "main", false),
+				makePR("build-old", "build", "11", "main", false),
+				makePR("build-done", "build", "11", "main", true),
+				makePR("deploy-old", "deploy", "11", "main", false),
+				makePR("build-other-pr", "build", "12", "main", false),
+			},
+			cancelledPipelineRuns: map[string]bool{"build-old": true},
+		},
+		{
+			name:       "cancel the previous runs of the branch on push",
+			event:      &info.Event{},
+			annotation: "true",
+			newPR:      makePR("build-new", "build", "", "main", false),
+			pipelineRuns: []*pipelinev1.PipelineRun{
+				makePR("build-new", "build", "", "main", false),
+				makePR("build-old", "build", "", "main", false),
+				makePR("build-other-branch", "build", "", "release", false),
+				makePR("build-pull-request", "build", "11", "main", false),
+			},
+			cancelledPipelineRuns: map[string]bool{"build-old": true},
+		},
+		{
+			name:  "no annotation",
+			event: &info.Event{PullRequestNumber: 11},
+			newPR: makePR("build-new", "build", "11", "main", false),
+			pipelineRuns: []*pipelinev1.PipelineRun{
+				makePR("build-new", "build", "11", "main", false),
+				makePR("build-old", "build", "11", "main", false),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{PipelineRuns: tt.pipelineRuns})
+			cs := &params.Run{
+				Clients: clients.Clients{
+					Log:    logger,
+					Tekton: stdata.Pipeline,
+					Kube:   stdata.Kube,
+				},
+			}
+			if tt.annotation != "" {
+				tt.newPR.Annotations = map[string]string{keys.CancelInProgress: tt.annotation}
+			}
+			pac := NewPacs(tt.event, nil, cs, nil, logger)
+			assert.NilError(t, pac.cancelInProgress(ctx, fooRepo, tt.newPR))
+
+			got, err := cs.Clients.Tekton.TektonV1().PipelineRuns("foo").List(ctx, metav1.ListOptions{})
+			assert.NilError(t, err)
+			for _, pr := range got.Items {
+				if tt.cancelledPipelineRuns[pr.Name] {
+					assert.Equal(t, string(pr.Spec.Status), pipelinev1.PipelineRunSpecStatusCancelledRunFinally, pr.Name)
+					continue
+				}
+				assert.Assert(t, string(pr.Spec.Status) != pipelinev1.PipelineRunSpecStatusCancelledRunFinally, pr.Name)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package pipelineascode
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/resolve"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// verifyClusterPipeline checks the Pipeline referenced by a PipelineRun with
+// the cluster-pipeline annotation is installed in the namespace the
+// PipelineRun is created in, Tekton would otherwise only fail the PipelineRun
+// once created.
+func (p *PacRun) verifyClusterPipeline(ctx context.Context, pr *tektonv1.PipelineRun, namespace string) error {
+	if !resolve.IsClusterPipeline(pr) {
+		return nil
+	}
+	name := pr.Spec.PipelineRef.Name
+	_, err := p.run.Clients.Tekton.TektonV1().Pipelines(namespace).Get(ctx, name, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		return fmt.Errorf("the Pipeline %s referenced by the pipelineRef is not installed in the namespace %s", name, namespace)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot get the Pipeline %s in the namespace %s: %w", name, namespace, err)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package pipelineascode
+
+import (
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestVerifyClusterPipeline(t *testing.T) {
+	ns := "ns"
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		installed   bool
+		wantErr     string
+	}{
+		{
+			name: "no annotation",
+		},
+		{
+			name:        "installed",
+			annotations: map[string]string{keys.ClusterPipeline: "true"},
+			installed:   true,
+		},
+		{
+			name:        "not installed",
+			annotations: map[string]string{keys.ClusterPipeline: "true"},
+			wantErr:     "the Pipeline build referenced by the pipelineRef is not installed in the namespace ns",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+				Namespaces: []*corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: ns}}},
+			})
+			if tt.installed {
+				_, err := stdata.Pipeline.TektonV1().Pipelines(ns).Create(ctx, &tektonv1.Pipeline{
+					ObjectMeta: metav1.ObjectMeta{Name: "build", Namespace: ns},
+				}, metav1.CreateOptions{})
+				assert.NilError(t, err)
+			}
+			pr := &tektonv1.PipelineRun{
+				ObjectMeta: metav1.ObjectMeta{Name: "pr", Annotations: tt.annotations},
+				Spec:       tektonv1.PipelineRunSpec{PipelineRef: &tektonv1.PipelineRef{Name: "build"}},
+			}
+			p := &PacRun{run: &params.Run{Clients: clients.Clients{Tekton: stdata.Pipeline}}}
+			err := p.verifyClusterPipeline(ctx, pr, ns)
+			if tt.wantErr != "" {
+				assert.Error(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package pipelineascode
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/pod"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// archLabel is the well known label of the architecture of the nodes.
+const archLabel = corev1.LabelArchStable
+
+// supportedArchs are the architectures a PipelineRun can request with the arch
+// annotation.
+var supportedArchs = []string{"amd64", "arm64", "ppc64le", "s390x"}
+
+// applyComputeAnnotations schedules the pods of the PipelineRun on the nodes of
+// the architecture and of the node pool requested by its arch and node-pool
+// annotations, with a node selector and a toleration of the taint the nodes
+// may have on the same label in its pod template. The node pool is selected
+// with the node pool label of the settings. The node selector and the
+// tolerations already in the pod template of the PipelineRun win.
+func applyComputeAnnotations(pr *tektonv1.PipelineRun, nodePoolLabel string) error {
+	arch := pr.GetAnnotations()[keys.Arch]
+	nodePool := pr.GetAnnotations()[keys.NodePool]
+	if arch == "" && nodePool == "" {
+		return nil
+	}
+	if arch != "" && !isSupportedArch(arch) {
+		return fmt.Errorf("invalid value %q for the annotation %s, acceptable values: %s",
+			arch, keys.Arch, strings.Join(supportedArchs, ", "))
+	}
+	if nodePool != "" {
+		if errs := validation.IsValidLabelValue(nodePool); len(errs) > 0 {
+			return fmt.Errorf("invalid value %q for the annotation %s: %s", nodePool, keys.NodePool, strings.Join(errs, ", "))
+		}
+	}
+	if nodePoolLabel == "" {
+		nodePoolLabel = settings.NodePoolLabelDefaultValue
+	}
+
+	if pr.Spec.TaskRunTemplate.PodTemplate == nil {
+		pr.Spec.TaskRunTemplate.PodTemplate = &pod.PodTemplate{}
+	}
+	podTemplate := pr.Spec.TaskRunTemplate.PodTemplate
+	if arch != "" {
+		scheduleOn(podTemplate, archLabel, arch)
+	}
+	if nodePool != "" {
+		scheduleOn(podTemplate, nodePoolLabel, nodePool)
+	}
+	return nil
+}
+
+func isSupportedArch(arch string) bool {
+	for _, a := range supportedArchs {
+		if a == arch {
+			return true
+		}
+	}
+	return false
+}
+
+// scheduleOn selects the nodes with the label in the pod template and
+// tolerates their taint on the same label.
+func scheduleOn(podTemplate *pod.PodTemplate, label, value string) {
+	if _, ok := podTemplate.NodeSelector[label]; !ok {
+		if podTemplate.NodeSelector == nil {
+			podTemplate.NodeSelector = map[string]string{}
+		}
+		podTemplate.NodeSelector[label] = value
+	}
+	for _, t := range podTemplate.Tolerations {
+		if t.Key == label {
+			return
+		}
+	}
+	podTemplate.Tolerations = append(podTemplate.Tolerations, corev1.Toleration{
+		Key:      label,
+		Operator: corev1.TolerationOpEqual,
+		Value:    value,
+		Effect:   corev1.TaintEffectNoSchedule,
+	})
+}
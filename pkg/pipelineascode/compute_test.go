@@ -0,0 +1,97 @@
+package pipelineascode
+
+import (
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/pod"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestApplyComputeAnnotations(t *testing.T) {
+	toleration := func(key, value string) corev1.Toleration {
+		return corev1.Toleration{Key: key, Operator: corev1.TolerationOpEqual, Value: value, Effect: corev1.TaintEffectNoSchedule}
+	}
+	tests := []struct {
+		name            string
+		annotations     map[string]string
+		nodePoolLabel   string
+		podTemplate     *pod.PodTemplate
+		wantErr         string
+		wantPodTemplate *pod.PodTemplate
+	}{
+		{
+			name: "no annotation",
+		},
+		{
+			name:        "arch",
+			annotations: map[string]string{keys.Arch: "arm64"},
+			wantPodTemplate: &pod.PodTemplate{
+				NodeSelector: map[string]string{"kubernetes.io/arch": "arm64"},
+				Tolerations:  []corev1.Toleration{toleration("kubernetes.io/arch", "arm64")},
+			},
+		},
+		{
+			name:        "node pool with the default label",
+			annotations: map[string]string{keys.NodePool: "gpu"},
+			wantPodTemplate: &pod.PodTemplate{
+				NodeSelector: map[string]string{"pipelinesascode.tekton.dev/node-pool": "gpu"},
+				Tolerations:  []corev1.Toleration{toleration("pipelinesascode.tekton.dev/node-pool", "gpu")},
+			},
+		},
+		{
+			name:          "arch and node pool with a custom label",
+			annotations:   map[string]string{keys.Arch: "amd64", keys.NodePool: "gpu"},
+			nodePoolLabel: "cloud.google.com/gke-nodepool",
+			wantPodTemplate: &pod.PodTemplate{
+				NodeSelector: map[string]string{"kubernetes.io/arch": "amd64", "cloud.google.com/gke-nodepool": "gpu"},
+				Tolerations: []corev1.Toleration{
+					toleration("kubernetes.io/arch", "amd64"),
+					toleration("cloud.google.com/gke-nodepool", "gpu"),
+				},
+			},
+		},
+		{
+			name:        "pod template of the PipelineRun wins",
+			annotations: map[string]string{keys.Arch: "arm64"},
+			podTemplate: &pod.PodTemplate{
+				NodeSelector: map[string]string{"kubernetes.io/arch": "amd64"},
+				Tolerations:  []corev1.Toleration{{Key: "kubernetes.io/arch", Operator: corev1.TolerationOpExists}},
+			},
+			wantPodTemplate: &pod.PodTemplate{
+				NodeSelector: map[string]string{"kubernetes.io/arch": "amd64"},
+				Tolerations:  []corev1.Toleration{{Key: "kubernetes.io/arch", Operator: corev1.TolerationOpExists}},
+			},
+		},
+		{
+			name:        "unsupported arch",
+			annotations: map[string]string{keys.Arch: "riscv"},
+			wantErr:     `invalid value "riscv" for the annotation pipelinesascode.tekton.dev/arch, acceptable values: amd64, arm64, ppc64le, s390x`,
+		},
+		{
+			name:        "invalid node pool",
+			annotations: map[string]string{keys.NodePool: "gpu pool"},
+			wantErr:     `invalid value "gpu pool" for the annotation pipelinesascode.tekton.dev/node-pool`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr := &tektonv1.PipelineRun{
+				ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations},
+				Spec: tektonv1.PipelineRunSpec{
+					TaskRunTemplate: tektonv1.PipelineTaskRunTemplate{PodTemplate: tt.podTemplate},
+				},
+			}
+			err := applyComputeAnnotations(pr, tt.nodePoolLabel)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.DeepEqual(t, pr.Spec.TaskRunTemplate.PodTemplate, tt.wantPodTemplate)
+		})
+	}
+}
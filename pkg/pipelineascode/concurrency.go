@@ -4,6 +4,10 @@ import (
 	"fmt"
 	"sync"
 
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/sort"
 	v1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -72,3 +76,21 @@ func getOrderByName(runs []*v1.PipelineRun) string {
 	}
 	return order
 }
+
+// setQueuePriority gives the PipelineRuns of a push on the default branch a
+// high priority in the concurrency queue when the setting is enabled, so the
+// release and deploy PipelineRuns are not stuck behind the ones of the pull
+// requests. The priority is only set by Pipelines as Code, never by the
+// PipelineRun itself.
+func setQueuePriority(pacInfo *info.PacOpts, event *info.Event, pr *v1.PipelineRun) {
+	delete(pr.Annotations, keys.QueuePriority)
+	if !pacInfo.PrioritizeDefaultBranchPush || !isDefaultBranchPush(event) {
+		return
+	}
+	pr.Annotations[keys.QueuePriority] = kubeinteraction.QueuePriorityHigh
+}
+
+func isDefaultBranchPush(event *info.Event) bool {
+	return event.TriggerTarget == "push" && event.DefaultBranch != "" &&
+		formatting.SanitizeBranch(event.BaseBranch) == event.DefaultBranch
+}
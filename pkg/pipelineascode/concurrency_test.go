@@ -3,6 +3,10 @@ package pipelineascode
 import (
 	"testing"
 
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	"gotest.tools/v3/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -29,3 +33,49 @@ func TestExecutionOrder(t *testing.T) {
 	assert.Equal(t, order, "test/abc,test/def,test/mno,test/pqr")
 	assert.Equal(t, len(runs), 4)
 }
+
+func TestSetQueuePriority(t *testing.T) {
+	tests := []struct {
+		name        string
+		disabled    bool
+		event       *info.Event
+		annotations map[string]string
+		wantHigh    bool
+	}{
+		{
+			name:     "push on the default branch",
+			event:    &info.Event{TriggerTarget: "push", BaseBranch: "refs/heads/main", DefaultBranch: "main"},
+			wantHigh: true,
+		},
+		{
+			name:     "disabled",
+			disabled: true,
+			event:    &info.Event{TriggerTarget: "push", BaseBranch: "refs/heads/main", DefaultBranch: "main"},
+		},
+		{
+			name:  "push on another branch",
+			event: &info.Event{TriggerTarget: "push", BaseBranch: "refs/heads/feature", DefaultBranch: "main"},
+		},
+		{
+			name:  "pull request on the default branch",
+			event: &info.Event{TriggerTarget: "pull_request", BaseBranch: "main", DefaultBranch: "main"},
+		},
+		{
+			name:        "set by the pipelinerun",
+			event:       &info.Event{TriggerTarget: "pull_request", BaseBranch: "main", DefaultBranch: "main"},
+			annotations: map[string]string{keys.QueuePriority: kubeinteraction.QueuePriorityHigh},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr := &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{}}}
+			for k, v := range tt.annotations {
+				pr.Annotations[k] = v
+			}
+			pacInfo := &info.PacOpts{Settings: &settings.Settings{PrioritizeDefaultBranchPush: !tt.disabled}}
+			setQueuePriority(pacInfo, tt.event, pr)
+			_, ok := pr.GetAnnotations()[keys.QueuePriority]
+			assert.Equal(t, ok, tt.wantHigh)
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package pipelineascode
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/matcher"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// the decisions the event filter can return
+const (
+	eventFilterAllow  = "allow"
+	eventFilterDeny   = "deny"
+	eventFilterModify = "modify"
+)
+
+// eventFilterRequest is the summary of the event sent to the event filter.
+type eventFilterRequest struct {
+	Provider          string             `json:"provider"`
+	EventType         string             `json:"event_type"`
+	TriggerTarget     string             `json:"trigger_target"`
+	Organization      string             `json:"organization"`
+	Repository        string             `json:"repository"`
+	URL               string             `json:"url"`
+	SHA               string             `json:"sha"`
+	BaseBranch        string             `json:"base_branch"`
+	HeadBranch        string             `json:"head_branch"`
+	Sender            string             `json:"sender"`
+	PullRequestNumber int                `json:"pull_request_number,omitempty"`
+	PullRequestLabels []string           `json:"pull_request_labels,omitempty"`
+	RepositoryCR      eventFilterRepoRef `json:"repository_cr"`
+	PipelineRuns      []string           `json:"pipelineruns"`
+}
+
+type eventFilterRepoRef struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+// eventFilterResponse is the decision of the event filter, when modifying
+// only the listed PipelineRuns are run and they get the labels.
+type eventFilterResponse struct {
+	Decision     string            `json:"decision"`
+	Reason       string            `json:"reason"`
+	PipelineRuns []string          `json:"pipelineruns"`
+	Labels       map[string]string `json:"labels"`
+}
+
+// askEventFilter sends the summary of the event and of the matched
+// PipelineRuns to the event filter and returns its decision.
+func (p *PacRun) askEventFilter(ctx context.Context, repo *v1alpha1.Repository, names []string) (*eventFilterResponse, error) {
+	request := eventFilterRequest{
+		Provider:          p.vcx.GetConfig().Name,
+		EventType:         p.event.EventType,
+		TriggerTarget:     p.event.TriggerTarget,
+		Organization:      p.event.Organization,
+		Repository:        p.event.Repository,
+		URL:               p.event.URL,
+		SHA:               p.event.SHA,
+		BaseBranch:        p.event.BaseBranch,
+		HeadBranch:        p.event.HeadBranch,
+		Sender:            p.event.Sender,
+		PullRequestNumber: p.event.PullRequestNumber,
+		PullRequestLabels: p.event.PullRequestLabel,
+		RepositoryCR:      eventFilterRepoRef{Name: repo.GetName(), Namespace: repo.GetNamespace()},
+		PipelineRuns:      names,
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.run.Info.Pac.EventFilterTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.run.Info.Pac.EventFilterURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := p.run.Clients.HTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+		return nil, fmt.Errorf("the event filter returned %s: %s", res.Status, bytes.TrimSpace(b))
+	}
+
+	response := &eventFilterResponse{}
+	if err := json.NewDecoder(res.Body).Decode(response); err != nil {
+		return nil, fmt.Errorf("cannot decode the response of the event filter: %w", err)
+	}
+	switch response.Decision {
+	case eventFilterAllow, eventFilterDeny, eventFilterModify:
+	default:
+		return nil, fmt.Errorf("the event filter returned the unknown decision %q, it must be %s, %s or %s",
+			response.Decision, eventFilterAllow, eventFilterDeny, eventFilterModify)
+	}
+	if err := validateEventFilterLabels(response.Labels); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// validateEventFilterLabels checks the labels returned by the event filter
+// are valid and cannot override the labels of Pipelines as Code, which drive
+// how the PipelineRuns are reported and queued.
+func validateEventFilterLabels(labels map[string]string) error {
+	for key, value := range labels {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("the event filter returned the invalid label %q: %s", key, strings.Join(errs, ", "))
+		}
+		if prefix, _, found := strings.Cut(key, "/"); found &&
+			(prefix == pipelinesascode.GroupName || strings.HasSuffix(prefix, "."+pipelinesascode.GroupName)) {
+			return fmt.Errorf("the event filter returned the label %q, the %s labels are reserved to Pipelines as Code", key, pipelinesascode.GroupName)
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return fmt.Errorf("the event filter returned the invalid value %q for label %q: %s", value, key, strings.Join(errs, ", "))
+		}
+	}
+	return nil
+}
+
+// filterEvent consults the event filter when one is configured and returns
+// the matched PipelineRuns it allows to run, the ones it denies get a neutral
+// status with the reason. The PipelineRuns are denied when the event filter
+// cannot be consulted.
+func (p *PacRun) filterEvent(ctx context.Context, repo *v1alpha1.Repository, matches []matcher.Match) []matcher.Match {
+	if p.run.Info.Pac.EventFilterURL == "" {
+		return matches
+	}
+	names := make([]string, 0, len(matches))
+	for _, match := range matches {
+		names = append(names, match.PipelineRun.GetLabels()[keys.OriginalPRName])
+	}
+
+	response, err := p.askEventFilter(ctx, repo, names)
+	if err != nil {
+		p.eventEmitter.EmitMessage(repo, zap.ErrorLevel, "RepositoryEventFilter", fmt.Sprintf("cannot consult the event filter: %v", err))
+		response = &eventFilterResponse{Decision: eventFilterDeny, Reason: "the event filter could not be consulted"}
+	}
+
+	switch response.Decision {
+	case eventFilterAllow:
+		return matches
+	case eventFilterDeny:
+		response.PipelineRuns = nil
+	}
+	allowed := map[string]bool{}
+	for _, name := range response.PipelineRuns {
+		allowed[name] = true
+	}
+
+	kept := []matcher.Match{}
+	for _, match := range matches {
+		name := match.PipelineRun.GetLabels()[keys.OriginalPRName]
+		if !allowed[name] {
+			p.reportDeniedByEventFilter(ctx, repo, name, response.Reason)
+			continue
+		}
+		for k, v := range response.Labels {
+			match.PipelineRun.Labels[k] = v
+		}
+		kept = append(kept, match)
+	}
+	return kept
+}
+
+func (p *PacRun) reportDeniedByEventFilter(ctx context.Context, repo *v1alpha1.Repository, name, reason string) {
+	text := fmt.Sprintf("The event filter denied the PipelineRun %s", name)
+	if reason != "" {
+		text += ": " + reason
+	}
+	p.eventEmitter.EmitMessage(repo, zap.InfoLevel, "RepositoryEventFilter", text)
+
+	status := provider.StatusOpts{
+		Status:                  "completed",
+		Conclusion:              "neutral",
+		Title:                   "Denied",
+		Text:                    text + ".",
+		DetailsURL:              p.run.Clients.ConsoleUI.URL(),
+		OriginalPipelineRunName: name,
+	}
+	if err := p.vcx.CreateStatus(ctx, p.run.Clients.Tekton, p.event, p.run.Info.Pac, status); err != nil {
+		p.logger.Errorf("failed to create the status of the PipelineRun %s denied by the event filter: %s", name, err.Error())
+	}
+}
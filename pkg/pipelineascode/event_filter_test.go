@@ -0,0 +1,155 @@
+package pipelineascode
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/consoleui"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/matcher"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	testprovider "github.com/openshift-pipelines/pipelines-as-code/pkg/test/provider"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestFilterEvent(t *testing.T) {
+	tests := []struct {
+		name       string
+		noFilter   bool
+		response   string
+		statusCode int
+		wantKept   []string
+		wantLabels map[string]string
+		wantEvents []string
+	}{
+		{
+			name:     "no event filter",
+			noFilter: true,
+			wantKept: []string{"lint", "tests"},
+		},
+		{
+			name:     "allowed",
+			response: `{"decision": "allow"}`,
+			wantKept: []string{"lint", "tests"},
+		},
+		{
+			name:     "denied",
+			response: `{"decision": "deny", "reason": "code freeze"}`,
+			wantKept: []string{},
+			wantEvents: []string{
+				"The event filter denied the PipelineRun lint: code freeze",
+				"The event filter denied the PipelineRun tests: code freeze",
+			},
+		},
+		{
+			name:       "modified",
+			response:   `{"decision": "modify", "reason": "tests only", "pipelineruns": ["tests"], "labels": {"team": "ci"}}`,
+			wantKept:   []string{"tests"},
+			wantLabels: map[string]string{"team": "ci"},
+			wantEvents: []string{"The event filter denied the PipelineRun lint: tests only"},
+		},
+		{
+			name:       "modified with a label of pipelines as code",
+			response:   `{"decision": "modify", "pipelineruns": ["tests"], "labels": {"pipelinesascode.tekton.dev/state": "completed"}}`,
+			wantKept:   []string{},
+			wantEvents: []string{`cannot consult the event filter: the event filter returned the label "pipelinesascode.tekton.dev/state", the pipelinesascode.tekton.dev labels are reserved to Pipelines as Code`},
+		},
+		{
+			name:       "modified with an invalid label value",
+			response:   `{"decision": "modify", "pipelineruns": ["tests"], "labels": {"team": "ci team"}}`,
+			wantKept:   []string{},
+			wantEvents: []string{`cannot consult the event filter: the event filter returned the invalid value "ci team" for label "team": a valid label must be an empty string or consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character (e.g. 'MyValue',  or 'my_value',  or '12345', regex used for validation is '(([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9])?')`},
+		},
+		{
+			name:       "unknown decision",
+			response:   `{"decision": "maybe"}`,
+			wantKept:   []string{},
+			wantEvents: []string{`cannot consult the event filter: the event filter returned the unknown decision "maybe", it must be allow, deny or modify`},
+		},
+		{
+			name:       "filter failing",
+			statusCode: http.StatusInternalServerError,
+			response:   "oops",
+			wantKept:   []string{},
+			wantEvents: []string{"cannot consult the event filter: the event filter returned 500 Internal Server Error: oops"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				request := eventFilterRequest{}
+				assert.NilError(t, json.NewDecoder(r.Body).Decode(&request))
+				assert.Equal(t, request.EventType, "pull_request")
+				assert.Equal(t, request.SHA, "abcdef")
+				assert.Equal(t, request.RepositoryCR.Namespace, "ns")
+				assert.DeepEqual(t, request.PipelineRuns, []string{"lint", "tests"})
+				if tt.statusCode != 0 {
+					w.WriteHeader(tt.statusCode)
+				}
+				fmt.Fprint(w, tt.response)
+			}))
+			defer server.Close()
+
+			ctx := context.Background()
+			observer, logs := zapobserver.New(zap.InfoLevel)
+			logger := zap.New(observer).Sugar()
+			kube := kubefake.NewSimpleClientset()
+			pacSettings := &settings.Settings{EventFilterURL: server.URL, EventFilterTimeout: 5 * time.Second}
+			if tt.noFilter {
+				pacSettings.EventFilterURL = ""
+			}
+			run := &params.Run{
+				Clients: clients.Clients{Kube: kube, ConsoleUI: consoleui.FallBackConsole{}, HTTP: *server.Client()},
+				Info:    info.Info{Pac: &info.PacOpts{Settings: pacSettings}},
+			}
+			repo := &v1alpha1.Repository{ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"}}
+			matches := []matcher.Match{}
+			for _, name := range []string{"lint", "tests"} {
+				matches = append(matches, matcher.Match{
+					PipelineRun: &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{
+						GenerateName: name + "-",
+						Labels:       map[string]string{keys.OriginalPRName: name},
+					}},
+					Repo: repo,
+				})
+			}
+			event := &info.Event{EventType: "pull_request", SHA: "abcdef"}
+			p := NewPacs(event, &testprovider.TestProviderImp{}, run, nil, logger)
+
+			kept := p.filterEvent(ctx, repo, matches)
+			names := []string{}
+			for _, match := range kept {
+				names = append(names, match.PipelineRun.GetLabels()[keys.OriginalPRName])
+				for k, v := range tt.wantLabels {
+					assert.Equal(t, match.PipelineRun.GetLabels()[k], v)
+				}
+			}
+			assert.DeepEqual(t, names, tt.wantKept)
+
+			kevents, err := kube.CoreV1().Events("ns").List(ctx, metav1.ListOptions{})
+			assert.NilError(t, err)
+			if len(tt.wantEvents) == 0 {
+				assert.Equal(t, len(kevents.Items), 0)
+			} else {
+				assert.Equal(t, kevents.Items[0].Reason, "RepositoryEventFilter")
+			}
+			for _, want := range tt.wantEvents {
+				assert.Equal(t, logs.FilterMessage(want).Len(), 1, "missing message %q", want)
+			}
+		})
+	}
+}
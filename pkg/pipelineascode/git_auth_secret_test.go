@@ -0,0 +1,160 @@
+package pipelineascode
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/consoleui"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/matcher"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	kitesthelper "github.com/openshift-pipelines/pipelines-as-code/pkg/test/kubernetestint"
+	pipelinev1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestStartPRGitAuthSecret(t *testing.T) {
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	autoCreate := false
+
+	tests := []struct {
+		name               string
+		gitAuthSecret      *v1alpha1.GitAuthSecret
+		prServiceAccount   string
+		wantSecret         bool
+		wantServiceAccount string
+		wantPRSA           string
+	}{
+		{
+			name:       "workspace secret not attached",
+			wantSecret: true,
+		},
+		{
+			name: "attached to the service account of the repository",
+			gitAuthSecret: &v1alpha1.GitAuthSecret{
+				ServiceAccount: "builder",
+				Mode:           settings.SecretAutoCreateModeGitConfig,
+			},
+			wantSecret:         true,
+			wantServiceAccount: "builder",
+			wantPRSA:           "builder",
+		},
+		{
+			name:               "gitconfig attached to the service account of the pipelinerun",
+			gitAuthSecret:      &v1alpha1.GitAuthSecret{Mode: settings.SecretAutoCreateModeGitConfig},
+			prServiceAccount:   "custom",
+			wantSecret:         true,
+			wantServiceAccount: "custom",
+			wantPRSA:           "custom",
+		},
+		{
+			name:          "auto create disabled on the repository",
+			gitAuthSecret: &v1alpha1.GitAuthSecret{AutoCreate: &autoCreate, ServiceAccount: "builder"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+				Namespaces: []*corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: "foo"}}},
+			})
+			cs := &params.Run{
+				Clients: clients.Clients{
+					Log:       logger,
+					Tekton:    stdata.Pipeline,
+					Kube:      stdata.Kube,
+					ConsoleUI: consoleui.FallBackConsole{},
+				},
+				Info: info.Info{Pac: &info.PacOpts{Settings: &settings.Settings{SecretAutoCreation: true}}},
+			}
+			repo := fooRepo.DeepCopy()
+			repo.Spec.GitAuthSecret = tt.gitAuthSecret
+
+			prs := []*pipelinev1.PipelineRun{{
+				ObjectMeta: metav1.ObjectMeta{GenerateName: "build-", Namespace: "foo"},
+				Spec: pipelinev1.PipelineRunSpec{
+					TaskRunTemplate: pipelinev1.PipelineTaskRunTemplate{ServiceAccountName: tt.prServiceAccount},
+				},
+			}}
+			assert.NilError(t, changeSecret(prs, "pac-gitauth"))
+			prs[0].Name = "build-abcde"
+			prs[0].Labels = map[string]string{}
+
+			k8int := &kitesthelper.KinterfaceTest{}
+			event := &info.Event{Organization: "owner", Repository: "foo", URL: "https://forge/owner/foo", SHA: "foosha", Provider: &info.Provider{Token: "token"}}
+			p := NewPacs(event, &statusRecorder{}, cs, k8int, logger)
+			pr, err := p.startPR(ctx, matcher.Match{PipelineRun: prs[0], Repo: repo})
+			assert.NilError(t, err)
+
+			secretName := pr.GetAnnotations()[keys.GitAuthSecret]
+			assert.Assert(t, strings.HasPrefix(secretName, "pac-gitauth-"))
+			assert.Equal(t, k8int.AttachedSecrets[secretName], tt.wantServiceAccount)
+			assert.Equal(t, pr.GetAnnotations()[keys.GitAuthServiceAccount], tt.wantServiceAccount)
+			assert.Equal(t, pr.Spec.TaskRunTemplate.ServiceAccountName, tt.wantPRSA)
+		})
+	}
+}
+
+func TestStartPRQuotaExceededNoGitAuthSecret(t *testing.T) {
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	ctx, _ := rtesting.SetupFakeContext(t)
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+		Namespaces: []*corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: "foo"}}},
+	})
+	_, err := stdata.Kube.CoreV1().ResourceQuotas("foo").Create(ctx, &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "quota", Namespace: "foo"},
+		Status: corev1.ResourceQuotaStatus{
+			Hard: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")},
+			Used: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("0")},
+		},
+	}, metav1.CreateOptions{})
+	assert.NilError(t, err)
+	cs := &params.Run{
+		Clients: clients.Clients{
+			Log:       logger,
+			Tekton:    stdata.Pipeline,
+			Kube:      stdata.Kube,
+			ConsoleUI: consoleui.FallBackConsole{},
+		},
+		Info: info.Info{Pac: &info.PacOpts{Settings: &settings.Settings{SecretAutoCreation: true}}},
+	}
+
+	prs := []*pipelinev1.PipelineRun{{
+		ObjectMeta: metav1.ObjectMeta{GenerateName: "build-", Namespace: "foo"},
+		Spec: pipelinev1.PipelineRunSpec{
+			PipelineSpec: &pipelinev1.PipelineSpec{Tasks: []pipelinev1.PipelineTask{{
+				Name: "build",
+				TaskSpec: &pipelinev1.EmbeddedTask{TaskSpec: pipelinev1.TaskSpec{
+					Steps: []pipelinev1.Step{{ComputeResources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")},
+					}}},
+				}},
+			}}},
+		},
+	}}
+	assert.NilError(t, changeSecret(prs, "pac-gitauth"))
+	prs[0].Labels = map[string]string{}
+
+	k8int := &kitesthelper.KinterfaceTest{}
+	recorder := &statusRecorder{}
+	event := &info.Event{Organization: "owner", Repository: "foo", URL: "https://forge/owner/foo", SHA: "foosha", Provider: &info.Provider{Token: "token"}}
+	p := NewPacs(event, recorder, cs, k8int, logger)
+	_, err = p.startPR(ctx, matcher.Match{PipelineRun: prs[0], Repo: fooRepo.DeepCopy()})
+	assert.ErrorContains(t, err, "insufficient quota")
+	assert.Equal(t, len(k8int.CreatedSecrets), 0, "the git auth secret should not be created when the quota is exceeded")
+	assert.Equal(t, len(recorder.statuses), 1)
+	assert.Equal(t, recorder.statuses[0].Title, "Insufficient quota")
+}
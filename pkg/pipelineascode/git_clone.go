@@ -0,0 +1,27 @@
+package pipelineascode
+
+import (
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/resolve"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/secrets"
+)
+
+// gitCloneInjection returns the git clone task to prepend to the PipelineRuns
+// of the Repository or nil when it is not enabled. The git-auth secret is
+// bound to the task when it is created as a workspace secret, its name is
+// set by changeSecret.
+func gitCloneInjection(pacOpts *info.PacOpts, repo *v1alpha1.Repository) *resolve.GitCloneInjection {
+	if repo.Spec.GitClone == nil || !repo.Spec.GitClone.Enabled {
+		return nil
+	}
+	injection := &resolve.GitCloneInjection{
+		Workspace: repo.Spec.GitClone.Workspace,
+		Image:     repo.Spec.GitClone.Image,
+	}
+	if policy := secrets.GitAuthPolicyFor(pacOpts, repo); policy.AutoCreate && policy.Mode == settings.SecretAutoCreateModeWorkspace {
+		injection.AuthSecret = "{{ git_auth_secret }}"
+	}
+	return injection
+}
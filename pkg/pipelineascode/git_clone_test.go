@@ -0,0 +1,54 @@
+package pipelineascode
+
+import (
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/resolve"
+	"gotest.tools/v3/assert"
+)
+
+func TestGitCloneInjection(t *testing.T) {
+	tests := []struct {
+		name       string
+		gitClone   *v1alpha1.GitClone
+		autoCreate bool
+		mode       string
+		want       *resolve.GitCloneInjection
+	}{
+		{
+			name: "not set",
+		},
+		{
+			name:     "disabled",
+			gitClone: &v1alpha1.GitClone{Workspace: "source"},
+		},
+		{
+			name:     "without the git-auth secret",
+			gitClone: &v1alpha1.GitClone{Enabled: true, Workspace: "checkout", Image: "alpine/git"},
+			want:     &resolve.GitCloneInjection{Workspace: "checkout", Image: "alpine/git"},
+		},
+		{
+			name:       "with the git-auth secret workspace",
+			gitClone:   &v1alpha1.GitClone{Enabled: true},
+			autoCreate: true,
+			want:       &resolve.GitCloneInjection{AuthSecret: "{{ git_auth_secret }}"},
+		},
+		{
+			name:       "git-auth secret in the gitconfig",
+			gitClone:   &v1alpha1.GitClone{Enabled: true},
+			autoCreate: true,
+			mode:       settings.SecretAutoCreateModeGitConfig,
+			want:       &resolve.GitCloneInjection{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pacOpts := &info.PacOpts{Settings: &settings.Settings{SecretAutoCreation: tt.autoCreate, SecretAutoCreateMode: tt.mode}}
+			repo := &v1alpha1.Repository{Spec: v1alpha1.RepositorySpec{GitClone: tt.gitClone}}
+			assert.DeepEqual(t, gitCloneInjection(pacOpts, repo), tt.want)
+		})
+	}
+}
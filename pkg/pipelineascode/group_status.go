@@ -0,0 +1,42 @@
+package pipelineascode
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// createPipelineRunStatus creates the status of a PipelineRun on the
+// provider, or the status of all the PipelineRuns of the commit when the
+// statuses are grouped by commit. The PipelineRuns are started concurrently,
+// the grouped statuses are created one at a time so every update sees the
+// PipelineRuns created before it.
+func (p *PacRun) createPipelineRunStatus(ctx context.Context, repo *v1alpha1.Repository, status provider.StatusOpts) error {
+	if !p.run.Info.Pac.GroupStatusesByCommit || status.PipelineRun == nil {
+		return p.vcx.CreateStatus(ctx, p.run.Clients.Tekton, p.event, p.run.Info.Pac, status)
+	}
+
+	p.statusMutex.Lock()
+	defer p.statusMutex.Unlock()
+	prs, err := p.run.Clients.Tekton.TektonV1().PipelineRuns(repo.GetNamespace()).List(ctx, metav1.ListOptions{
+		LabelSelector: getLabelSelector(map[string]string{
+			keys.Repository: formatting.K8LabelsCleanup(repo.GetName()),
+			keys.SHA:        formatting.K8LabelsCleanup(p.event.SHA),
+		}),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list pipelineRuns : %w", err)
+	}
+	all := make([]*tektonv1.PipelineRun, 0, len(prs.Items))
+	for i := range prs.Items {
+		all = append(all, &prs.Items[i])
+	}
+	status = provider.GroupStatus(status, all, p.run.Clients.ConsoleUI.DetailURL)
+	return p.vcx.CreateStatus(ctx, p.run.Clients.Tekton, p.event, p.run.Info.Pac, status)
+}
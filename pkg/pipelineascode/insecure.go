@@ -0,0 +1,29 @@
+package pipelineascode
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	"go.uber.org/zap"
+)
+
+// InsecureSkipTLSVerifyMessage is the warning about a Repository not verifying
+// the TLS certificate of its git provider.
+func InsecureSkipTLSVerifyMessage(repo *v1alpha1.Repository) string {
+	return fmt.Sprintf("TLS certificate verification of the git provider is disabled for Repository %s/%s "+
+		"with spec.git_provider.insecure_skip_tls_verify, this is insecure and should only be used for testing",
+		repo.GetNamespace(), repo.GetName())
+}
+
+// WithRepositoryTLSConfig returns the context to use to set the provider
+// client of the Repository, the TLS certificate of the git provider is not
+// verified if the Repository opted in for it.
+func WithRepositoryTLSConfig(ctx context.Context, repo *v1alpha1.Repository, logger *zap.SugaredLogger) context.Context {
+	if repo == nil || !repo.InsecureSkipTLSVerify() {
+		return ctx
+	}
+	logger.Warnf("⚠️ %s", InsecureSkipTLSVerifyMessage(repo))
+	return provider.WithInsecureSkipTLSVerify(ctx)
+}
@@ -0,0 +1,45 @@
+package pipelineascode
+
+import (
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	testnewrepo "github.com/openshift-pipelines/pipelines-as-code/pkg/test/repository"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestWithRepositoryTLSConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		insecure bool
+	}{
+		{
+			name:     "opted in",
+			insecure: true,
+		},
+		{
+			name: "not opted in",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			repo := testnewrepo.NewRepo(testnewrepo.RepoTestcreationOpts{
+				Name:             "repo",
+				InstallNamespace: "ns",
+				URL:              "https://gitea.lab/org/repo",
+			})
+			repo.Spec.GitProvider = &v1alpha1.GitProvider{InsecureSkipTLSVerify: tt.insecure}
+			observer, logs := zapobserver.New(zap.InfoLevel)
+			logger := zap.New(observer).Sugar()
+
+			nctx := WithRepositoryTLSConfig(ctx, repo, logger)
+			assert.Equal(t, provider.HTTPClient(nctx) != nil, tt.insecure)
+			assert.Equal(t, logs.FilterMessageSnippet("insecure_skip_tls_verify").Len() > 0, tt.insecure)
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package pipelineascode
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/consoleui"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/matcher"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	kitesthelper "github.com/openshift-pipelines/pipelines-as-code/pkg/test/kubernetestint"
+	pipelinev1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestStartPRMaintenance(t *testing.T) {
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	now := time.Now().UTC().Truncate(time.Second)
+	end := now.Add(time.Hour)
+	concurrency := 1
+
+	tests := []struct {
+		name        string
+		windows     []settings.MaintenanceWindow
+		concurrency *int
+		runAfter    string
+		wantPending bool
+		wantState   string
+		wantStatus  string
+		wantText    string
+	}{
+		{
+			name:       "no maintenance",
+			windows:    []settings.MaintenanceWindow{{Start: now.Add(-2 * time.Hour), End: now.Add(-time.Hour)}},
+			wantState:  kubeinteraction.StateStarted,
+			wantStatus: "in_progress",
+			wantText:   "Starting Pipelinerun",
+		},
+		{
+			name:        "queued during the maintenance",
+			windows:     []settings.MaintenanceWindow{{Start: now.Add(-time.Hour), End: end}},
+			wantPending: true,
+			wantState:   kubeinteraction.StateMaintenance,
+			wantStatus:  "queued",
+			wantText:    "it will start after <b>" + end.Format(time.RFC3339) + "</b>",
+		},
+		{
+			name:        "concurrency queue after the maintenance",
+			windows:     []settings.MaintenanceWindow{{Start: now.Add(-time.Hour), End: end}},
+			concurrency: &concurrency,
+			wantPending: true,
+			wantState:   kubeinteraction.StateMaintenance,
+			wantStatus:  "queued",
+			wantText:    "during a maintenance of the cluster",
+		},
+		{
+			name:        "waiting for the pipelineruns it runs after",
+			windows:     []settings.MaintenanceWindow{{Start: now.Add(-time.Hour), End: end}},
+			runAfter:    "build-abcde",
+			wantPending: true,
+			wantState:   kubeinteraction.StateWaiting,
+			wantStatus:  "queued",
+			wantText:    "is waiting for",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+				Namespaces: []*corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: "foo"}}},
+			})
+			cs := &params.Run{
+				Clients: clients.Clients{
+					Log:       logger,
+					Tekton:    stdata.Pipeline,
+					Kube:      stdata.Kube,
+					ConsoleUI: consoleui.FallBackConsole{},
+				},
+				Info: info.Info{Pac: &info.PacOpts{Settings: &settings.Settings{MaintenanceWindows: tt.windows}}},
+			}
+			repo := fooRepo.DeepCopy()
+			repo.Spec.ConcurrencyLimit = tt.concurrency
+
+			pr := &pipelinev1.PipelineRun{
+				ObjectMeta: metav1.ObjectMeta{Name: "deploy-abcde", Namespace: "foo", Labels: map[string]string{}, Annotations: map[string]string{}},
+			}
+			if tt.runAfter != "" {
+				pr.Annotations[keys.RunAfterPipelineRuns] = tt.runAfter
+			}
+			vcx := &statusRecorder{}
+			event := &info.Event{Organization: "owner", Repository: "foo", URL: "https://forge/owner/foo", SHA: "foosha", Provider: &info.Provider{Token: "token"}}
+			p := NewPacs(event, vcx, cs, &kitesthelper.KinterfaceTest{}, logger)
+			created, err := p.startPR(ctx, matcher.Match{PipelineRun: pr, Repo: repo})
+			assert.NilError(t, err)
+
+			assert.Equal(t, created.Spec.Status == pipelinev1.PipelineRunSpecStatusPending, tt.wantPending)
+			assert.Equal(t, created.GetLabels()[keys.State], tt.wantState)
+			assert.Equal(t, len(vcx.statuses), 1)
+			assert.Equal(t, vcx.statuses[0].Status, tt.wantStatus)
+			assert.Assert(t, strings.Contains(vcx.statuses[0].Text, tt.wantText), vcx.statuses[0].Text)
+		})
+	}
+}
@@ -31,6 +31,10 @@ func (p *PacRun) matchRepoPR(ctx context.Context) ([]matcher.Match, *v1alpha1.Re
 		return nil, repo, p.cancelPipelineRuns(ctx, repo)
 	}
 
+	if p.event.OverridePipelineRuns {
+		return nil, repo, p.overridePipelineRuns(ctx, repo)
+	}
+
 	matchedPRs, err := p.getPipelineRunsFromRepo(ctx, repo)
 	if err != nil {
 		return nil, repo, err
@@ -68,6 +72,13 @@ func (p *PacRun) verifyRepoAndUser(ctx context.Context) (*v1alpha1.Repository, e
 		return nil, nil
 	}
 
+	// Skip right away the revisions we already know have nothing to run,
+	// before reading the secrets and calling the provider.
+	if knownWithoutTektonDir(p.event) {
+		p.logger.Infof("skipping the event on %s at %s, there is no %s directory in this revision", p.event.URL, p.event.SHA, tektonDir)
+		return nil, nil
+	}
+
 	// If we have a git_provider field in repository spec, then get all the
 	// information from there, including the webhook secret.
 	// otherwise get the secret from the current ns (i.e: pipelines-as-code/openshift-pipelines.)
@@ -103,11 +114,26 @@ is that what you want? make sure you use -n when generating the secret, eg: echo
 
 	// Set the client, we should error out if there is a problem with
 	// token or secret or we won't be able to do much.
-	err = p.vcx.SetClient(ctx, p.run, p.event)
+	p.event.Provider.CommentStrategy = repo.Spec.CommentStrategy
+	err = p.vcx.SetClient(WithRepositoryTLSConfig(ctx, repo, p.logger), p.run, p.event)
 	if err != nil {
 		return repo, err
 	}
 
+	if err := p.verifyPinnedRevision(ctx); err != nil {
+		p.eventEmitter.EmitMessage(repo, zap.ErrorLevel, "RepositoryPinnedRevisionNotOnBranch", err.Error())
+		return repo, err
+	}
+
+	// Don't go further for the revisions without a .tekton directory, the
+	// permissions checks and the fetching of the PipelineRuns would be made
+	// for nothing.
+	if !p.hasTektonDir(ctx) {
+		msg := fmt.Sprintf("cannot locate templates in %s/ directory for this repository in %s", tektonDir, p.event.HeadBranch)
+		p.eventEmitter.EmitMessage(nil, zap.InfoLevel, "RepositoryPipelineRunNotFound", msg)
+		return nil, nil
+	}
+
 	// Get the SHA commit info, we want to get the URL and commit title
 	err = p.vcx.GetCommitInfo(ctx, p.event)
 	if err != nil {
@@ -166,6 +192,9 @@ func (p *PacRun) getPipelineRunsFromRepo(ctx context.Context, repo *v1alpha1.Rep
 	pipelineRuns, err := resolve.Resolve(ctx, p.run, p.logger, p.vcx, p.event, allTemplates, &resolve.Opts{
 		GenerateName: true,
 		RemoteTasks:  p.run.Info.Pac.RemoteTasks,
+		Injection:    p.run.Info.Pac.PipelineRunInjection,
+		PinImages:    p.run.Info.Pac.PinStepImages,
+		GitClone:     gitCloneInjection(p.run.Info.Pac, repo),
 	})
 	if err != nil {
 		p.eventEmitter.EmitMessage(repo, zap.ErrorLevel, "RepositoryFailedToMatch", fmt.Sprintf("failed to match pipelineRuns: %s", err.Error()))
@@ -177,6 +206,21 @@ func (p *PacRun) getPipelineRunsFromRepo(ctx context.Context, repo *v1alpha1.Rep
 		return nil, nil
 	}
 
+	// with the strict annotation policy the unknown annotations, most likely
+	// typos, fail the validation instead of silently not matching
+	if repo.StrictAnnotations() {
+		if err := matcher.VerifyAnnotations(pipelineRuns); err != nil {
+			p.eventEmitter.EmitMessage(repo, zap.ErrorLevel, "RepositoryInvalidAnnotations", err.Error())
+			return nil, err
+		}
+	}
+
+	// warn about the annotations relying on a capability the git provider
+	// doesn't have, ie: the pathChanged function on Bitbucket
+	for _, warning := range matcher.UnsupportedCapabilities(provider.EventProviderType(p.vcx, p.event), pipelineRuns) {
+		p.eventEmitter.EmitMessage(repo, zap.WarnLevel, "RepositoryUnsupportedCapability", warning)
+	}
+
 	// if /test command is used then filter out the pipelinerun
 	pipelineRuns = filterRunningPipelineRunOnTargetTest(p.event.TargetTestPipelineRun, pipelineRuns)
 	if pipelineRuns == nil {
@@ -185,7 +229,21 @@ func (p *PacRun) getPipelineRunsFromRepo(ctx context.Context, repo *v1alpha1.Rep
 		return nil, nil
 	}
 
-	err = changeSecret(pipelineRuns)
+	// if /retest --failed command is used then only keep the failed pipelineruns
+	if provider.IsRetestFailedComment(p.event.TriggerComment) {
+		failed, err := p.failedPipelineRunNames(ctx, repo)
+		if err != nil {
+			return nil, err
+		}
+		pipelineRuns = filterFailedPipelineRuns(failed, pipelineRuns)
+		if len(pipelineRuns) == 0 {
+			msg := fmt.Sprintf("no failed pipelinerun to retest found for repository: %v , sha: %v", p.event.Repository, p.event.SHA)
+			p.eventEmitter.EmitMessage(repo, zap.InfoLevel, "RepositoryPipelineRun", msg)
+			return nil, nil
+		}
+	}
+
+	err = changeSecret(pipelineRuns, secrets.GitAuthPolicyFor(p.run.Info.Pac, repo).NamePrefix)
 	if err != nil {
 		return nil, err
 	}
@@ -194,13 +252,37 @@ func (p *PacRun) getPipelineRunsFromRepo(ctx context.Context, repo *v1alpha1.Rep
 	matchedPRs, err := matcher.MatchPipelinerunByAnnotation(ctx, p.logger, pipelineRuns, p.run, p.event, p.vcx)
 	if err != nil {
 		// Don't fail when you don't have a match between pipeline and annotations
-		p.eventEmitter.EmitMessage(nil, zap.WarnLevel, "RepositoryNoMatch", err.Error())
+		p.reportNoMatch(ctx, repo, pipelineRuns, err)
 		return nil, nil
 	}
 
 	return matchedPRs, nil
 }
 
+// reportNoMatch explains for every PipelineRun why it didn't match the event,
+// the explanation is emitted as an event on the Repository and reported as a
+// neutral status on the git provider if the setting is enabled.
+func (p *PacRun) reportNoMatch(ctx context.Context, repo *v1alpha1.Repository, pipelineRuns []*tektonv1.PipelineRun, matchErr error) {
+	msg := matchErr.Error()
+	for _, explanation := range matcher.ExplainMatch(ctx, pipelineRuns, p.event, p.vcx) {
+		msg += fmt.Sprintf("\n- %s: %s", explanation.PipelineRun, explanation.Reason)
+	}
+	p.eventEmitter.EmitMessage(repo, zap.WarnLevel, "RepositoryNoMatch", msg)
+
+	if !p.run.Info.Pac.NoMatchNeutralStatus {
+		return
+	}
+	status := provider.StatusOpts{
+		Status:     "completed",
+		Conclusion: "neutral",
+		Title:      "No PipelineRun matched this event",
+		Text:       msg,
+	}
+	if err := p.vcx.CreateStatus(ctx, p.run.Clients.Tekton, p.event, p.run.Info.Pac, status); err != nil {
+		p.logger.Errorf("failed to create neutral status when no PipelineRun matched: %s", err.Error())
+	}
+}
+
 func filterRunningPipelineRunOnTargetTest(testPipeline string, prs []*tektonv1.PipelineRun) []*tektonv1.PipelineRun {
 	if testPipeline == "" {
 		return prs
@@ -218,14 +300,14 @@ func filterRunningPipelineRunOnTargetTest(testPipeline string, prs []*tektonv1.P
 // changeSecret we need to go in each pipelinerun,
 // change the secret template variable with a random one as generated from GetBasicAuthSecretName and store in in the
 // annotations so we can create one delete after.
-func changeSecret(prs []*tektonv1.PipelineRun) error {
+func changeSecret(prs []*tektonv1.PipelineRun, prefix string) error {
 	for k, p := range prs {
 		b, err := json.Marshal(p)
 		if err != nil {
 			return err
 		}
 
-		name := secrets.GenerateBasicAuthSecretName()
+		name := secrets.GenerateBasicAuthSecretName(prefix)
 		processed := templates.ReplacePlaceHoldersVariables(string(b), map[string]string{
 			"git_auth_secret": name,
 		})
@@ -1,15 +1,23 @@
 package pipelineascode
 
 import (
+	"context"
+	"fmt"
 	"strings"
 	"testing"
 
 	apipac "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/test/logger"
+	testprovider "github.com/openshift-pipelines/pipelines-as-code/pkg/test/provider"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	"gotest.tools/v3/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
 )
 
 func TestPacRun_checkNeedUpdate(t *testing.T) {
@@ -51,9 +59,9 @@ func TestChangeSecret(t *testing.T) {
 			},
 		},
 	}
-	err := changeSecret(prs)
+	err := changeSecret(prs, "ci-gitauth")
 	assert.NilError(t, err)
-	assert.Assert(t, strings.HasPrefix(prs[0].GetName(), "pac-gitauth"), prs[0].GetName(), "has no pac-gitauth prefix")
+	assert.Assert(t, strings.HasPrefix(prs[0].GetName(), "ci-gitauth-"), prs[0].GetName(), "has no ci-gitauth prefix")
 	assert.Assert(t, prs[0].GetAnnotations()[apipac.GitAuthSecret] != "")
 }
 
@@ -77,3 +85,63 @@ func TestFilterRunningPipelineRunOnTargetTest(t *testing.T) {
 	ret = filterRunningPipelineRunOnTargetTest(testPipeline, prs)
 	assert.Assert(t, ret == nil)
 }
+
+func TestReportNoMatch(t *testing.T) {
+	tests := []struct {
+		name                 string
+		noMatchNeutralStatus bool
+		createStatusErroring bool
+		wantLog              string
+	}{
+		{
+			name: "emit event only",
+		},
+		{
+			name:                 "neutral status failing",
+			noMatchNeutralStatus: true,
+			createStatusErroring: true,
+			wantLog:              "failed to create neutral status when no PipelineRun matched",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			log, catcher := logger.GetLogger()
+			kube := kubefake.NewSimpleClientset()
+			run := &params.Run{
+				Clients: clients.Clients{Kube: kube},
+				Info: info.Info{
+					Pac: &info.PacOpts{Settings: &settings.Settings{NoMatchNeutralStatus: tt.noMatchNeutralStatus}},
+				},
+			}
+			repo := &v1alpha1.Repository{
+				ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"},
+			}
+			event := &info.Event{TriggerTarget: "pull_request", EventType: "pull_request", BaseBranch: "main"}
+			prs := []*tektonv1.PipelineRun{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						GenerateName: "push-",
+						Annotations: map[string]string{
+							apipac.OnEvent:        "[push]",
+							apipac.OnTargetBranch: "[main]",
+						},
+					},
+				},
+			}
+			vcx := &testprovider.TestProviderImp{CreateStatusErorring: tt.createStatusErroring}
+			p := NewPacs(event, vcx, run, nil, log)
+			p.reportNoMatch(ctx, repo, prs, fmt.Errorf("cannot match pipeline"))
+
+			events, err := kube.CoreV1().Events("ns").List(ctx, metav1.ListOptions{})
+			assert.NilError(t, err)
+			assert.Equal(t, len(events.Items), 1)
+			assert.Equal(t, events.Items[0].Reason, "RepositoryNoMatch")
+			assert.Assert(t, strings.Contains(events.Items[0].Message, "- push: "), events.Items[0].Message)
+			assert.Assert(t, strings.Contains(events.Items[0].Message, "does not match the event pull_request"), events.Items[0].Message)
+			if tt.wantLog != "" {
+				assert.Assert(t, catcher.FilterMessageSnippet(tt.wantLog).Len() > 0)
+			}
+		})
+	}
+}
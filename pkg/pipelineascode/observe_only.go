@@ -0,0 +1,44 @@
+package pipelineascode
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/matcher"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	"go.uber.org/zap"
+)
+
+// reportObserveOnly reports the matched PipelineRuns without creating them
+// when the controller runs in observe-only mode: every PipelineRun is
+// reported as an event on the Repository and as a neutral status on the git
+// provider.
+func (p *PacRun) reportObserveOnly(ctx context.Context, repo *v1alpha1.Repository, matchedPRs []matcher.Match) {
+	for _, match := range matchedPRs {
+		ns := repo.GetNamespace()
+		if match.Repo != nil {
+			ns = match.Repo.GetNamespace()
+		}
+		name := match.PipelineRun.GetLabels()[keys.OriginalPRName]
+		if name == "" {
+			name = match.PipelineRun.GetGenerateName()
+		}
+		msg := fmt.Sprintf("observe-only mode: PipelineRun %s would have run in namespace %s for the %s event on %s",
+			name, ns, p.event.EventType, p.event.SHA)
+		p.eventEmitter.EmitMessage(repo, zap.InfoLevel, "RepositoryObserveOnly", msg)
+
+		status := provider.StatusOpts{
+			Status:                  "completed",
+			Conclusion:              "neutral",
+			Title:                   "Observe-only",
+			Text:                    fmt.Sprintf("Pipelines as Code runs in observe-only mode, the PipelineRun %s would have run in namespace %s.", name, ns),
+			DetailsURL:              p.run.Clients.ConsoleUI.URL(),
+			OriginalPipelineRunName: name,
+		}
+		if err := p.vcx.CreateStatus(ctx, p.run.Clients.Tekton, p.event, p.run.Info.Pac, status); err != nil {
+			p.logger.Errorf("failed to create neutral status in observe-only mode: %s", err.Error())
+		}
+	}
+}
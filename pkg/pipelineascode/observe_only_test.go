@@ -0,0 +1,81 @@
+package pipelineascode
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/consoleui"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/matcher"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	testprovider "github.com/openshift-pipelines/pipelines-as-code/pkg/test/provider"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestReportObserveOnly(t *testing.T) {
+	tests := []struct {
+		name                 string
+		createStatusErroring bool
+		wantLog              string
+	}{
+		{
+			name: "report the matched pipelineruns",
+		},
+		{
+			name:                 "neutral status failing",
+			createStatusErroring: true,
+			wantLog:              "failed to create neutral status in observe-only mode",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			observer, logs := zapobserver.New(zap.InfoLevel)
+			logger := zap.New(observer).Sugar()
+			kube := kubefake.NewSimpleClientset()
+			run := &params.Run{
+				Clients: clients.Clients{Kube: kube, ConsoleUI: consoleui.FallBackConsole{}},
+				Info: info.Info{
+					Pac: &info.PacOpts{Settings: &settings.Settings{ObserveOnly: true}},
+				},
+			}
+			repo := &v1alpha1.Repository{
+				ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"},
+			}
+			event := &info.Event{EventType: "pull_request", SHA: "abcdef"}
+			matches := []matcher.Match{
+				{
+					PipelineRun: &tektonv1.PipelineRun{
+						ObjectMeta: metav1.ObjectMeta{
+							GenerateName: "pr-",
+							Labels:       map[string]string{keys.OriginalPRName: "pr"},
+						},
+					},
+					Repo: repo,
+				},
+			}
+			vcx := &testprovider.TestProviderImp{CreateStatusErorring: tt.createStatusErroring}
+			p := NewPacs(event, vcx, run, nil, logger)
+			p.reportObserveOnly(ctx, repo, matches)
+
+			kevents, err := kube.CoreV1().Events("ns").List(ctx, metav1.ListOptions{})
+			assert.NilError(t, err)
+			assert.Equal(t, len(kevents.Items), 1)
+			assert.Equal(t, kevents.Items[0].Reason, "RepositoryObserveOnly")
+			assert.Equal(t, kevents.Items[0].Message,
+				"observe-only mode: PipelineRun pr would have run in namespace ns for the pull_request event on abcdef")
+			if tt.wantLog != "" {
+				assert.Assert(t, logs.FilterMessageSnippet(tt.wantLog).Len() > 0)
+			}
+		})
+	}
+}
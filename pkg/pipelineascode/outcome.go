@@ -0,0 +1,77 @@
+package pipelineascode
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+)
+
+const (
+	// DecisionError is an event which could not be processed.
+	DecisionError = "error"
+	// DecisionNoRepository is an event of a repository without a Repository.
+	DecisionNoRepository = "no-repository"
+	// DecisionNoMatch is an event matching no PipelineRun.
+	DecisionNoMatch = "no-match"
+	// DecisionSkipped is an event whose matched PipelineRuns were all
+	// skipped, ie: by the secret scanning or the event filter.
+	DecisionSkipped = "skipped"
+	// DecisionObserveOnly is an event whose matched PipelineRuns were only
+	// reported since the controller is in observe only mode.
+	DecisionObserveOnly = "observe-only"
+	// DecisionStarted is an event which started at least one PipelineRun.
+	DecisionStarted = "started"
+	// DecisionFailed is an event whose matched PipelineRuns could not be
+	// started.
+	DecisionFailed = "failed"
+)
+
+// Outcome is what has been decided for an event, it is recorded in the audit
+// log of the controller.
+type Outcome struct {
+	// Repository is the namespace/name of the Repository of the event.
+	Repository string
+	Decision   string
+	Reason     string
+	// PipelineRuns are the names of the PipelineRuns created for the event.
+	PipelineRuns []string
+}
+
+// outcomeRecorder records the outcome of an event while its PipelineRuns are
+// started concurrently.
+type outcomeRecorder struct {
+	mu      sync.Mutex
+	outcome Outcome
+}
+
+func (o *outcomeRecorder) setRepository(repo *v1alpha1.Repository) {
+	if repo == nil {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.outcome.Repository = repo.GetNamespace() + "/" + repo.GetName()
+}
+
+func (o *outcomeRecorder) decide(decision, reason string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.outcome.Decision = decision
+	o.outcome.Reason = reason
+}
+
+func (o *outcomeRecorder) addPipelineRun(name string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.outcome.PipelineRuns = append(o.outcome.PipelineRuns, name)
+}
+
+func (o *outcomeRecorder) get() Outcome {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	outcome := o.outcome
+	outcome.PipelineRuns = append([]string{}, o.outcome.PipelineRuns...)
+	sort.Strings(outcome.PipelineRuns)
+	return outcome
+}
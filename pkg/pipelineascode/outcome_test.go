@@ -0,0 +1,25 @@
+package pipelineascode
+
+import (
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestOutcomeRecorder(t *testing.T) {
+	o := &outcomeRecorder{}
+	o.setRepository(nil)
+	assert.DeepEqual(t, o.get(), Outcome{PipelineRuns: []string{}})
+
+	o.setRepository(&v1alpha1.Repository{ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"}})
+	o.addPipelineRun("pr-b")
+	o.addPipelineRun("pr-a")
+	o.decide(DecisionStarted, "")
+	assert.DeepEqual(t, o.get(), Outcome{
+		Repository:   "ns/repo",
+		Decision:     DecisionStarted,
+		PipelineRuns: []string{"pr-a", "pr-b"},
+	})
+}
@@ -0,0 +1,92 @@
+package pipelineascode
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/action"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+)
+
+// overridePipelineRuns reports the failed PipelineRuns of the pull request as
+// overridden after a /override comment from a member, so a pull request
+// blocked by a known flaky CI can be merged. The PipelineRuns are annotated
+// with who overrode them and why.
+func (p *PacRun) overridePipelineRuns(ctx context.Context, repo *v1alpha1.Repository) error {
+	if p.event.TriggerTarget != "pull_request" {
+		msg := fmt.Sprintf("not a pullRequest event, event: %v", p.event.TriggerTarget)
+		p.eventEmitter.EmitMessage(repo, zap.WarnLevel, "RepositoryEvent", msg)
+		return nil
+	}
+
+	prs, err := p.run.Clients.Tekton.TektonV1().PipelineRuns(repo.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: getLabelSelector(map[string]string{
+			keys.URLRepository: formatting.K8LabelsCleanup(p.event.Repository),
+			keys.SHA:           formatting.K8LabelsCleanup(p.event.SHA),
+			keys.PullRequest:   strconv.Itoa(p.event.PullRequestNumber),
+		}),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list pipelineRuns : %w", err)
+	}
+
+	overridden := 0
+	for i := range prs.Items {
+		pr := &prs.Items[i]
+		originalPRName := pr.GetLabels()[keys.OriginalPRName]
+		if p.event.TargetOverridePipelineRun != "" && originalPRName != p.event.TargetOverridePipelineRun {
+			continue
+		}
+		if !pr.IsDone() || !pr.Status.GetCondition(apis.ConditionSucceeded).IsFalse() {
+			continue
+		}
+		if _, ok := pr.GetAnnotations()[keys.OverriddenBy]; ok {
+			p.logger.Infof("pipelinerun %v/%v has already been overridden", pr.GetNamespace(), pr.GetName())
+			continue
+		}
+
+		msg := fmt.Sprintf("PipelineRun %s has failed and has been overridden by %s", pr.GetName(), p.event.Sender)
+		if p.event.OverrideReason != "" {
+			msg += fmt.Sprintf(": %s", p.event.OverrideReason)
+		}
+		status := provider.StatusOpts{
+			Status:                  "completed",
+			Conclusion:              provider.ConclusionOverridden,
+			Text:                    msg,
+			PipelineRun:             pr,
+			PipelineRunName:         pr.GetName(),
+			OriginalPipelineRunName: originalPRName,
+			DetailsURL:              p.run.Clients.ConsoleUI.DetailURL(pr),
+		}
+		if err := p.vcx.CreateStatus(ctx, p.run.Clients.Tekton, p.event, p.run.Info.Pac, status); err != nil {
+			return fmt.Errorf("cannot report pipelinerun %s as overridden: %w", pr.GetName(), err)
+		}
+
+		if _, err := action.PatchPipelineRun(ctx, p.logger, "override", p.run.Clients.Tekton, pr, map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"annotations": map[string]string{
+					keys.OverriddenBy:   p.event.Sender,
+					keys.OverrideReason: p.event.OverrideReason,
+				},
+			},
+		}); err != nil {
+			return err
+		}
+		p.eventEmitter.EmitMessage(repo, zap.InfoLevel, "RepositoryPipelineRunOverridden", msg)
+		overridden++
+	}
+
+	if overridden == 0 {
+		msg := fmt.Sprintf("no failed pipelinerun to override found for repository: %v , sha: %v and pullRequest %v",
+			p.event.Repository, p.event.SHA, p.event.PullRequestNumber)
+		p.eventEmitter.EmitMessage(repo, zap.InfoLevel, "RepositoryPipelineRun", msg)
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package pipelineascode
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/consoleui"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	tprovider "github.com/openshift-pipelines/pipelines-as-code/pkg/test/provider"
+	pipelinev1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	knativeduckv1 "knative.dev/pkg/apis/duck/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+// statusRecorder records the statuses created on the provider.
+type statusRecorder struct {
+	tprovider.TestProviderImp
+	statuses []provider.StatusOpts
+}
+
+func (v *statusRecorder) CreateStatus(_ context.Context, _ versioned.Interface, _ *info.Event, _ *info.PacOpts, statusOpts provider.StatusOpts) error {
+	v.statuses = append(v.statuses, statusOpts)
+	return nil
+}
+
+func TestOverridePipelineRuns(t *testing.T) {
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	makePR := func(name, originalName string, status corev1.ConditionStatus, annotations map[string]string) *pipelinev1.PipelineRun {
+		labels := map[string]string{}
+		for k, v := range fooRepoLabels {
+			labels[k] = v
+		}
+		labels[keys.OriginalPRName] = originalName
+		return &pipelinev1.PipelineRun{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "foo",
+				Labels:      labels,
+				Annotations: annotations,
+			},
+			Status: pipelinev1.PipelineRunStatus{
+				Status: knativeduckv1.Status{
+					Conditions: knativeduckv1.Conditions{
+						{Type: apis.ConditionSucceeded, Status: status},
+					},
+				},
+			},
+		}
+	}
+	pipelineRuns := []*pipelinev1.PipelineRun{
+		makePR("flaky-abcde", "flaky", corev1.ConditionFalse, nil),
+		makePR("lint-fghij", "lint", corev1.ConditionFalse, nil),
+		makePR("build-klmno", "build", corev1.ConditionTrue, nil),
+		makePR("running-pqrst", "running", corev1.ConditionUnknown, nil),
+		makePR("e2e-uvwxy", "e2e", corev1.ConditionFalse, map[string]string{keys.OverriddenBy: "someone"}),
+	}
+	tests := []struct {
+		name           string
+		event          *info.Event
+		wantOverridden []string
+		wantText       string
+	}{
+		{
+			name:  "not a pull request event",
+			event: &info.Event{TriggerTarget: "push"},
+		},
+		{
+			name: "override all failed",
+			event: &info.Event{
+				Repository:        "foo",
+				SHA:               "foosha",
+				Sender:            "maintainer",
+				TriggerTarget:     "pull_request",
+				PullRequestNumber: 11,
+				State:             info.State{OverridePipelineRuns: true},
+			},
+			wantOverridden: []string{"flaky-abcde", "lint-fghij"},
+			wantText:       "PipelineRun flaky-abcde has failed and has been overridden by maintainer",
+		},
+		{
+			name: "override a single pipelinerun with a reason",
+			event: &info.Event{
+				Repository:        "foo",
+				SHA:               "foosha",
+				Sender:            "maintainer",
+				TriggerTarget:     "pull_request",
+				PullRequestNumber: 11,
+				State: info.State{
+					OverridePipelineRuns:      true,
+					TargetOverridePipelineRun: "flaky",
+					OverrideReason:            "known flaky test",
+				},
+			},
+			wantOverridden: []string{"flaky-abcde"},
+			wantText:       "PipelineRun flaky-abcde has failed and has been overridden by maintainer: known flaky test",
+		},
+		{
+			name: "nothing to override",
+			event: &info.Event{
+				Repository:        "foo",
+				SHA:               "foosha",
+				TriggerTarget:     "pull_request",
+				PullRequestNumber: 11,
+				State: info.State{
+					OverridePipelineRuns:      true,
+					TargetOverridePipelineRun: "build",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+				Namespaces:   []*corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: "foo"}}},
+				PipelineRuns: pipelineRuns,
+			})
+			cs := &params.Run{
+				Clients: clients.Clients{
+					Log:       logger,
+					Tekton:    stdata.Pipeline,
+					Kube:      stdata.Kube,
+					ConsoleUI: consoleui.FallBackConsole{},
+				},
+				Info: info.Info{Pac: &info.PacOpts{}},
+			}
+			vcx := &statusRecorder{}
+			pac := NewPacs(tt.event, vcx, cs, nil, logger)
+			assert.NilError(t, pac.overridePipelineRuns(ctx, fooRepo))
+
+			overridden := []string{}
+			for _, status := range vcx.statuses {
+				assert.Equal(t, status.Conclusion, provider.ConclusionOverridden)
+				overridden = append(overridden, status.PipelineRunName)
+			}
+			assert.Equal(t, len(overridden), len(tt.wantOverridden))
+			if len(tt.wantOverridden) == 0 {
+				return
+			}
+			assert.DeepEqual(t, overridden, tt.wantOverridden)
+			assert.Equal(t, vcx.statuses[0].Text, tt.wantText)
+
+			pr, err := stdata.Pipeline.TektonV1().PipelineRuns("foo").Get(ctx, tt.wantOverridden[0], metav1.GetOptions{})
+			assert.NilError(t, err)
+			assert.Equal(t, pr.GetAnnotations()[keys.OverriddenBy], "maintainer")
+			assert.Equal(t, pr.GetAnnotations()[keys.OverrideReason], tt.event.OverrideReason)
+		})
+	}
+}
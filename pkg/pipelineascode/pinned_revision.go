@@ -0,0 +1,33 @@
+package pipelineascode
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+)
+
+// verifyPinnedRevision checks the commit pinned by the caller of an incoming
+// webhook is part of the history of the branch allowed by the incoming
+// webhook rule, so a commit of a fork or of another branch cannot be run with
+// the secrets of the branch. The commit is refused on the providers unable to
+// check it.
+func (p *PacRun) verifyPinnedRevision(ctx context.Context) error {
+	if p.event.EventType != "incoming" || p.event.SHA == "" {
+		return nil
+	}
+	checker, ok := p.vcx.(provider.RevisionChecker)
+	if !ok {
+		return fmt.Errorf("cannot run commit %s, the git provider cannot check it is on the branch %s, only the head of the branch can be run",
+			formatting.ShortSHA(p.event.SHA), p.event.HeadBranch)
+	}
+	onBranch, err := checker.IsRevisionOnBranch(ctx, p.event, p.event.SHA, p.event.HeadBranch)
+	if err != nil {
+		return fmt.Errorf("cannot check commit %s is on the branch %s: %w", formatting.ShortSHA(p.event.SHA), p.event.HeadBranch, err)
+	}
+	if !onBranch {
+		return fmt.Errorf("commit %s is not part of the history of the branch %s", formatting.ShortSHA(p.event.SHA), p.event.HeadBranch)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package pipelineascode
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	testprovider "github.com/openshift-pipelines/pipelines-as-code/pkg/test/provider"
+	"gotest.tools/v3/assert"
+)
+
+type revisionCheckerProvider struct {
+	testprovider.TestProviderImp
+	onBranch bool
+	err      error
+	checks   int
+}
+
+func (v *revisionCheckerProvider) IsRevisionOnBranch(_ context.Context, _ *info.Event, _, branch string) (bool, error) {
+	v.checks++
+	if branch != "release-1.0" {
+		return false, fmt.Errorf("unexpected branch %s", branch)
+	}
+	return v.onBranch, v.err
+}
+
+func TestVerifyPinnedRevision(t *testing.T) {
+	sha := "0123456789abcdef0123456789abcdef01234567"
+	tests := []struct {
+		name       string
+		event      *info.Event
+		onBranch   bool
+		err        error
+		noCheck    bool
+		wantErr    string
+		wantChecks int
+	}{
+		{
+			name:  "not pinned",
+			event: &info.Event{EventType: "incoming", HeadBranch: "release-1.0"},
+		},
+		{
+			name:  "not an incoming webhook",
+			event: &info.Event{EventType: "push", SHA: sha, HeadBranch: "release-1.0"},
+		},
+		{
+			name:       "on the branch",
+			event:      &info.Event{EventType: "incoming", SHA: sha, HeadBranch: "release-1.0"},
+			onBranch:   true,
+			wantChecks: 1,
+		},
+		{
+			name:       "not on the branch",
+			event:      &info.Event{EventType: "incoming", SHA: sha, HeadBranch: "release-1.0"},
+			wantErr:    "commit 0123456 is not part of the history of the branch release-1.0",
+			wantChecks: 1,
+		},
+		{
+			name:    "provider unable to check",
+			event:   &info.Event{EventType: "incoming", SHA: sha, HeadBranch: "release-1.0"},
+			noCheck: true,
+			wantErr: "cannot run commit 0123456, the git provider cannot check it is on the branch release-1.0, only the head of the branch can be run",
+		},
+		{
+			name:       "error checking",
+			event:      &info.Event{EventType: "incoming", SHA: sha, HeadBranch: "release-1.0"},
+			err:        fmt.Errorf("not found"),
+			wantErr:    "cannot check commit 0123456 is on the branch release-1.0: not found",
+			wantChecks: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vcx := &revisionCheckerProvider{onBranch: tt.onBranch, err: tt.err}
+			p := &PacRun{vcx: vcx, event: tt.event}
+			if tt.noCheck {
+				p.vcx = &testprovider.TestProviderImp{}
+			}
+			err := p.verifyPinnedRevision(context.Background())
+			if tt.wantErr != "" {
+				assert.Error(t, err, tt.wantErr)
+			} else {
+				assert.NilError(t, err)
+			}
+			assert.Equal(t, vcx.checks, tt.wantChecks)
+		})
+	}
+}
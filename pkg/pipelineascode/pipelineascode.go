@@ -3,10 +3,13 @@ package pipelineascode
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/action"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/events"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/matcher"
@@ -23,6 +26,9 @@ import (
 
 const (
 	tektonDir = ".tekton"
+	// maxConcurrentStarts is the maximum number of matched PipelineRuns
+	// started at the same time.
+	maxConcurrentStarts = 8
 )
 
 type PacRun struct {
@@ -33,6 +39,8 @@ type PacRun struct {
 	logger       *zap.SugaredLogger
 	eventEmitter *events.EventEmitter
 	manager      *ConcurrencyManager
+	statusMutex  sync.Mutex
+	outcome      *outcomeRecorder
 }
 
 func NewPacs(event *info.Event, vcx provider.Interface, run *params.Run, k8int kubeinteraction.Interface, logger *zap.SugaredLogger) PacRun {
@@ -40,12 +48,20 @@ func NewPacs(event *info.Event, vcx provider.Interface, run *params.Run, k8int k
 		event: event, run: run, vcx: vcx, k8int: k8int, logger: logger,
 		eventEmitter: events.NewEventEmitter(run.Clients.Kube, logger),
 		manager:      NewConcurrencyManager(),
+		outcome:      &outcomeRecorder{},
 	}
 }
 
+// Outcome returns what has been decided for the event once Run returned.
+func (p *PacRun) Outcome() Outcome {
+	return p.outcome.get()
+}
+
 func (p *PacRun) Run(ctx context.Context) error {
 	matchedPRs, repo, err := p.matchRepoPR(ctx)
+	p.outcome.setRepository(repo)
 	if err != nil {
+		p.outcome.decide(DecisionError, err.Error())
 		createStatusErr := p.vcx.CreateStatus(ctx, p.run.Clients.Tekton, p.event, p.run.Info.Pac, provider.StatusOpts{
 			Status:     "completed",
 			Conclusion: "failure",
@@ -58,14 +74,47 @@ func (p *PacRun) Run(ctx context.Context) error {
 		}
 	}
 	if len(matchedPRs) == 0 {
+		switch {
+		case err != nil:
+		case repo == nil:
+			p.outcome.decide(DecisionNoRepository, "")
+		default:
+			p.outcome.decide(DecisionNoMatch, "")
+		}
+		return nil
+	}
+	if matchedPRs = p.scanSecrets(ctx, repo, matchedPRs); len(matchedPRs) == 0 {
+		p.outcome.decide(DecisionSkipped, "secrets have been found in the PipelineRuns")
+		return nil
+	}
+	if matchedPRs = p.filterEvent(ctx, repo, matchedPRs); len(matchedPRs) == 0 {
+		p.outcome.decide(DecisionSkipped, "the event filter rejected the PipelineRuns")
+		return nil
+	}
+	if p.run.Info.Pac.ObserveOnly {
+		p.outcome.decide(DecisionObserveOnly, "")
+		p.reportObserveOnly(ctx, repo, matchedPRs)
 		return nil
 	}
 	if repo.Spec.ConcurrencyLimit != nil && *repo.Spec.ConcurrencyLimit != 0 {
 		p.manager.Enable()
 	}
 
+	independent, dependent, invalid := orderByRunAfter(matchedPRs)
+	for _, inv := range invalid {
+		p.reportInvalidRunAfter(ctx, repo, inv)
+	}
+
+	// the names of the created PipelineRuns by their original names, for the
+	// PipelineRuns running after them
+	var mu sync.Mutex
+	created := map[string]string{}
+
+	// the independent PipelineRuns are started concurrently, a bounded number
+	// at a time to not flood the cluster and the provider API
+	sem := make(chan struct{}, maxConcurrentStarts)
 	var wg sync.WaitGroup
-	for _, match := range matchedPRs {
+	for _, match := range independent {
 		if match.Repo == nil {
 			match.Repo = repo
 		}
@@ -73,17 +122,54 @@ func (p *PacRun) Run(ctx context.Context) error {
 
 		go func(match matcher.Match) {
 			defer wg.Done()
-			pr, err := p.startPR(ctx, match)
-			if err != nil {
-				errMsg := fmt.Sprintf("PipelineRun %s has failed: %s", match.PipelineRun.GetGenerateName(), err.Error())
-				p.eventEmitter.EmitMessage(repo, zap.ErrorLevel, "RepositoryPipelineRun", errMsg)
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			pr := p.startMatch(ctx, repo, match)
+			if pr == nil {
 				return
 			}
+			mu.Lock()
+			created[match.PipelineRun.GetLabels()[keys.OriginalPRName]] = pr.GetName()
+			mu.Unlock()
+			p.outcome.addPipelineRun(pr.GetName())
 			p.manager.AddPipelineRun(pr)
 		}(match)
 	}
 	wg.Wait()
 
+	// the PipelineRuns running after others are created one by one in order,
+	// waiting in pending state until the watcher starts them
+	for _, match := range dependent {
+		if match.Repo == nil {
+			match.Repo = repo
+		}
+		after, _ := matcher.RunAfter(match.PipelineRun)
+		names := []string{}
+		for _, name := range after {
+			if created[name] == "" {
+				p.reportInvalidRunAfter(ctx, repo, invalidRunAfter{
+					match:  match,
+					reason: fmt.Errorf("it runs after %s which could not be started", name),
+				})
+				break
+			}
+			names = append(names, created[name])
+		}
+		if len(names) != len(after) {
+			continue
+		}
+		match.PipelineRun.Annotations[keys.RunAfterPipelineRuns] = strings.Join(names, ",")
+		if pr := p.startMatch(ctx, repo, match); pr != nil {
+			created[match.PipelineRun.GetLabels()[keys.OriginalPRName]] = pr.GetName()
+			p.outcome.addPipelineRun(pr.GetName())
+		}
+	}
+	if len(created) > 0 {
+		p.outcome.decide(DecisionStarted, "")
+	} else {
+		p.outcome.decide(DecisionFailed, "none of the matched PipelineRuns could be started")
+	}
+
 	order, prs := p.manager.GetExecutionOrder()
 	if order != "" {
 		for _, pr := range prs {
@@ -103,18 +189,80 @@ func (p *PacRun) Run(ctx context.Context) error {
 	return nil
 }
 
+// startMatch starts a matched PipelineRun and cancels the ones in progress it
+// replaces, errors are reported as events on the Repository.
+func (p *PacRun) startMatch(ctx context.Context, repo *v1alpha1.Repository, match matcher.Match) *tektonv1.PipelineRun {
+	pr, err := p.startPR(ctx, match)
+	if err != nil {
+		errMsg := fmt.Sprintf("PipelineRun %s has failed: %s", match.PipelineRun.GetGenerateName(), err.Error())
+		p.eventEmitter.EmitMessage(repo, zap.ErrorLevel, "RepositoryPipelineRun", errMsg)
+		return nil
+	}
+	if err := p.cancelInProgress(ctx, match.Repo, pr); err != nil {
+		p.eventEmitter.EmitMessage(repo, zap.ErrorLevel, "RepositoryPipelineRun", err.Error())
+	}
+	return pr
+}
+
+// reportStartFailure reports the reason a matched PipelineRun could not be
+// created as a failure status on the git provider.
+func (p *PacRun) reportStartFailure(ctx context.Context, match matcher.Match, title string, err error) {
+	if serr := p.vcx.CreateStatus(ctx, p.run.Clients.Tekton, p.event, p.run.Info.Pac, provider.StatusOpts{
+		Status:                  "completed",
+		Conclusion:              "failure",
+		Title:                   title,
+		Text:                    err.Error(),
+		DetailsURL:              p.run.Clients.ConsoleUI.URL(),
+		OriginalPipelineRunName: match.PipelineRun.GetLabels()[keys.OriginalPRName],
+	}); serr != nil {
+		p.logger.Errorf("cannot create a failure status on the provider platform: %v", serr)
+	}
+}
+
 func (p *PacRun) startPR(ctx context.Context, match matcher.Match) (*tektonv1.PipelineRun, error) {
 	var gitAuthSecretName string
 
+	if err := applyComputeAnnotations(match.PipelineRun, p.run.Info.Pac.NodePoolLabel); err != nil {
+		p.reportStartFailure(ctx, match, "Invalid compute annotations", err)
+		return nil, err
+	}
+	if err := p.verifyClusterPipeline(ctx, match.PipelineRun, match.Repo.GetNamespace()); err != nil {
+		p.reportStartFailure(ctx, match, "Pipeline not found on the cluster", err)
+		return nil, err
+	}
+	applyServiceAccountMapping(p.event, match.Repo, match.PipelineRun)
+
+	// check the quota before creating the git auth secret, it would not be
+	// owned by any PipelineRun and leak when the quota is exceeded
+	quota, err := p.checkQuota(ctx, match.Repo.GetNamespace(), match.PipelineRun)
+	if err != nil {
+		p.logger.Warnf("cannot check the resource quotas of namespace %s: %v", match.Repo.GetNamespace(), err)
+	}
+	if quota != nil && quota.exceedsHard {
+		p.eventEmitter.EmitMessage(match.Repo, zap.WarnLevel, "RepositoryInsufficientQuota", quota.message)
+		if err := p.vcx.CreateStatus(ctx, p.run.Clients.Tekton, p.event, p.run.Info.Pac, provider.StatusOpts{
+			Status:                  "completed",
+			Conclusion:              "failure",
+			Title:                   "Insufficient quota",
+			Text:                    quota.message,
+			DetailsURL:              p.run.Clients.ConsoleUI.URL(),
+			OriginalPipelineRunName: match.PipelineRun.GetLabels()[keys.OriginalPRName],
+		}); err != nil {
+			return nil, fmt.Errorf("cannot create a failure status on the provider platform: %w", err)
+		}
+		return nil, fmt.Errorf("%s", quota.message)
+	}
+
 	// Automatically create a secret with the token to be reused by git-clone task
-	if p.run.Info.Pac.SecretAutoCreation {
+	gitAuth := secrets.GitAuthPolicyFor(p.run.Info.Pac, match.Repo)
+	if gitAuth.AutoCreate {
 		if annotation, ok := match.PipelineRun.GetAnnotations()[keys.GitAuthSecret]; ok {
 			gitAuthSecretName = annotation
 		} else {
 			return nil, fmt.Errorf("cannot get annotation %s as set on PR", keys.GitAuthSecret)
 		}
 
-		authSecret, err := secrets.MakeBasicAuthSecret(p.event, gitAuthSecretName)
+		authSecret, err := gitAuth.MakeSecret(p.event, gitAuthSecretName)
 		if err != nil {
 			return nil, err
 		}
@@ -126,6 +274,9 @@ func (p *PacRun) startPR(ctx context.Context, match matcher.Match) (*tektonv1.Pi
 
 	// Add labels and annotations to pipelinerun
 	kubeinteraction.AddLabelsAndAnnotations(p.event, match.PipelineRun, match.Repo, p.vcx.GetConfig())
+	kubeinteraction.AddLabelsAndAnnotationsFromSettings(p.event, match.PipelineRun, match.Repo,
+		p.run.Info.Pac.PipelineRunLabels, p.run.Info.Pac.PipelineRunAnnotations)
+	setQueuePriority(p.run.Info.Pac, p.event, match.PipelineRun)
 
 	// if concurrency is defined then start the pipelineRun in pending state and
 	// state as queued
@@ -136,10 +287,54 @@ func (p *PacRun) startPR(ctx context.Context, match matcher.Match) (*tektonv1.Pi
 		match.PipelineRun.Labels[keys.State] = kubeinteraction.StateQueued
 	}
 
+	// a PipelineRun running after others waits for them in pending state,
+	// outside of the concurrency queue until the watcher releases it
+	waitingFor := match.PipelineRun.GetAnnotations()[keys.RunAfterPipelineRuns] != ""
+	if waitingFor {
+		match.PipelineRun.Spec.Status = tektonv1.PipelineRunSpecStatusPending
+		match.PipelineRun.Labels[keys.State] = kubeinteraction.StateWaiting
+	}
+
+	// during a maintenance window the PipelineRun is created in pending
+	// state and started by the watcher once the window ends
+	maintenanceEnd, inMaintenance := p.run.Info.Pac.MaintenanceEnd(time.Now())
+	if inMaintenance && !waitingFor {
+		match.PipelineRun.Spec.Status = tektonv1.PipelineRunSpecStatusPending
+		match.PipelineRun.Labels[keys.State] = kubeinteraction.StateMaintenance
+	}
+
+	InjectRepositoryEnv(match.PipelineRun, match.Repo.Spec.Env)
+
+	if err := stampRunEnvironment(match.PipelineRun, p.run.Info.Pac.Settings); err != nil {
+		p.logger.Warnf("cannot record the environment of the pipelinerun %s: %v", match.PipelineRun.GetGenerateName(), err)
+	}
+
+	// attach the secret to the ServiceAccount the PipelineRun runs with for
+	// the time of the run, the PipelineRuns not setting one run with the
+	// ServiceAccount of the policy
+	var gitAuthServiceAccount string
+	if gitAuth.AutoCreate {
+		if gitAuth.ServiceAccount != "" && match.PipelineRun.Spec.TaskRunTemplate.ServiceAccountName == "" {
+			match.PipelineRun.Spec.TaskRunTemplate.ServiceAccountName = gitAuth.ServiceAccount
+		}
+		gitAuthServiceAccount = gitAuth.ServiceAccountFor(match.PipelineRun.Spec.TaskRunTemplate.ServiceAccountName)
+	}
+	if gitAuthServiceAccount != "" {
+		if err := p.k8int.AttachSecretToServiceAccount(ctx, match.Repo.GetNamespace(), gitAuthServiceAccount, gitAuthSecretName); err != nil {
+			return nil, fmt.Errorf("attaching secret %s to service account %s has failed: %w", gitAuthSecretName, gitAuthServiceAccount, err)
+		}
+		match.PipelineRun.Annotations[keys.GitAuthServiceAccount] = gitAuthServiceAccount
+	}
+
 	// Create the actual pipeline
 	pr, err := p.run.Clients.Tekton.TektonV1().PipelineRuns(match.Repo.GetNamespace()).Create(ctx,
 		match.PipelineRun, metav1.CreateOptions{})
 	if err != nil {
+		if gitAuthServiceAccount != "" {
+			if err := p.k8int.DetachSecretFromServiceAccount(ctx, match.Repo.GetNamespace(), gitAuthServiceAccount, gitAuthSecretName); err != nil {
+				p.logger.Warnf("cannot detach secret %s from service account %s: %v", gitAuthSecretName, gitAuthServiceAccount, err)
+			}
+		}
 		return nil, fmt.Errorf("creating pipelinerun %s in %s has failed: %w ", match.PipelineRun.GetGenerateName(),
 			match.Repo.GetNamespace(), err)
 	}
@@ -170,8 +365,21 @@ func (p *PacRun) startPR(ctx context.Context, match matcher.Match) (*tektonv1.Pi
 		status.Status = "queued"
 		status.Text = fmt.Sprintf(params.QueuingPipelineRunText, pr.GetName(), match.Repo.GetNamespace())
 	}
+	if waitingFor {
+		after, _ := matcher.RunAfter(pr)
+		status.Text = fmt.Sprintf(params.WaitingPipelineRunText, pr.GetName(), match.Repo.GetNamespace(), strings.Join(after, ", "))
+	} else if inMaintenance {
+		status.Text = fmt.Sprintf(params.MaintenancePipelineRunText, pr.GetName(), match.Repo.GetNamespace(),
+			maintenanceEnd.Format(time.RFC3339))
+	}
+
+	// let the user know the PipelineRun will wait for the quota rather than
+	// just seeing its pods pending
+	if quota != nil {
+		status.Text = fmt.Sprintf("%s\n\n⚠️ %s", status.Text, quota.message)
+	}
 
-	if err := p.vcx.CreateStatus(ctx, p.run.Clients.Tekton, p.event, p.run.Info.Pac, status); err != nil {
+	if err := p.createPipelineRunStatus(ctx, match.Repo, status); err != nil {
 		return nil, fmt.Errorf("cannot create a in_progress status on the provider platform: %w", err)
 	}
 
@@ -184,7 +392,7 @@ func (p *PacRun) startPR(ctx context.Context, match matcher.Match) (*tektonv1.Pi
 	}
 
 	// update ownerRef of secret with pipelineRun, so that it gets cleanedUp with pipelineRun
-	if p.run.Info.Pac.SecretAutoCreation {
+	if gitAuth.AutoCreate {
 		return pr, p.k8int.UpdateSecretWithOwnerRef(ctx, p.logger, pr.Namespace, gitAuthSecretName, pr)
 	}
 	return pr, nil
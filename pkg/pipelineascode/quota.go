@@ -0,0 +1,138 @@
+package pipelineascode
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// quotaResources maps the resources of a ResourceQuota constraining the pods
+// compute to the resource of the containers they apply to and whether they
+// apply to their limits rather than their requests.
+var quotaResources = map[corev1.ResourceName]struct {
+	resource corev1.ResourceName
+	limits   bool
+}{
+	corev1.ResourceCPU:            {resource: corev1.ResourceCPU},
+	corev1.ResourceMemory:         {resource: corev1.ResourceMemory},
+	corev1.ResourceRequestsCPU:    {resource: corev1.ResourceCPU},
+	corev1.ResourceRequestsMemory: {resource: corev1.ResourceMemory},
+	corev1.ResourceLimitsCPU:      {resource: corev1.ResourceCPU, limits: true},
+	corev1.ResourceLimitsMemory:   {resource: corev1.ResourceMemory, limits: true},
+}
+
+// quotaCheck is the result of the comparison of the compute resources
+// declared by a PipelineRun against the ResourceQuotas of its namespace.
+type quotaCheck struct {
+	// message explains which task doesn't fit in which quota.
+	message string
+	// exceedsHard is true when a task needs more than what the quota allows
+	// at all, the PipelineRun can then never run.
+	exceedsHard bool
+}
+
+// taskPodResources returns the compute resources of the pod of a task: the
+// steps run one after the other so the pod needs the largest of the steps
+// resources while the sidecars run along all of them.
+func taskPodResources(spec *tektonv1.TaskSpec) (requests, limits corev1.ResourceList) {
+	requests, limits = corev1.ResourceList{}, corev1.ResourceList{}
+	maxInto := func(dst, src corev1.ResourceList) {
+		for name, q := range src {
+			if cur, ok := dst[name]; !ok || q.Cmp(cur) > 0 {
+				dst[name] = q.DeepCopy()
+			}
+		}
+	}
+	addInto := func(dst, src corev1.ResourceList) {
+		for name, q := range src {
+			cur := dst[name]
+			cur.Add(q)
+			dst[name] = cur
+		}
+	}
+	for _, step := range spec.Steps {
+		maxInto(requests, step.ComputeResources.Requests)
+		maxInto(limits, step.ComputeResources.Limits)
+	}
+	for _, sidecar := range spec.Sidecars {
+		addInto(requests, sidecar.ComputeResources.Requests)
+		addInto(limits, sidecar.ComputeResources.Limits)
+	}
+	return requests, limits
+}
+
+// checkQuota compares the compute resources declared by the embedded tasks
+// of the PipelineRun against the ResourceQuotas of the namespace, it returns
+// nil if every task fits in the remaining quota. Only the tasks embedded in
+// the PipelineRun are checked, the overrides of the taskRunSpecs are ignored.
+func (p *PacRun) checkQuota(ctx context.Context, ns string, pr *tektonv1.PipelineRun) (*quotaCheck, error) {
+	if pr.Spec.PipelineSpec == nil {
+		return nil, nil
+	}
+	quotas, err := p.run.Clients.Kube.CoreV1().ResourceQuotas(ns).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if len(quotas.Items) == 0 {
+		return nil, nil
+	}
+
+	tasks := append([]tektonv1.PipelineTask{}, pr.Spec.PipelineSpec.Tasks...)
+	tasks = append(tasks, pr.Spec.PipelineSpec.Finally...)
+	var advisory *quotaCheck
+	for _, task := range tasks {
+		if task.TaskSpec == nil {
+			continue
+		}
+		requests, limits := taskPodResources(&task.TaskSpec.TaskSpec)
+		for _, quota := range quotas.Items {
+			names := make([]string, 0, len(quota.Status.Hard))
+			for name := range quota.Status.Hard {
+				names = append(names, string(name))
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				qr, ok := quotaResources[corev1.ResourceName(name)]
+				if !ok {
+					continue
+				}
+				needed, ok := requests[qr.resource]
+				kind := "requests"
+				if qr.limits {
+					needed, ok = limits[qr.resource]
+					kind = "limits"
+				}
+				if !ok {
+					continue
+				}
+				hard := quota.Status.Hard[corev1.ResourceName(name)]
+				if needed.Cmp(hard) > 0 {
+					return &quotaCheck{
+						exceedsHard: true,
+						message: fmt.Sprintf("insufficient quota: task %s %s %s of %s but the ResourceQuota %s only allows %s",
+							task.Name, kind, needed.String(), qr.resource, quota.GetName(), hard.String()),
+					}, nil
+				}
+				remaining := hard.DeepCopy()
+				if used, ok := quota.Status.Used[corev1.ResourceName(name)]; ok {
+					remaining.Sub(used)
+				}
+				if remaining.Sign() < 0 {
+					remaining = resource.Quantity{}
+				}
+				if advisory == nil && needed.Cmp(remaining) > 0 {
+					advisory = &quotaCheck{
+						message: fmt.Sprintf("insufficient quota: task %s %s %s of %s but only %s remains in the ResourceQuota %s, it will wait for other pods of the namespace to finish",
+							task.Name, kind, needed.String(), qr.resource, remaining.String(), quota.GetName()),
+					}
+				}
+			}
+		}
+	}
+	return advisory, nil
+}
@@ -0,0 +1,125 @@
+package pipelineascode
+
+import (
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestCheckQuota(t *testing.T) {
+	ns := "ns"
+	step := func(cpu, memory string) tektonv1.Step {
+		return tektonv1.Step{ComputeResources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse(memory),
+			},
+		}}
+	}
+	pipelineRun := func(tasks ...tektonv1.PipelineTask) *tektonv1.PipelineRun {
+		return &tektonv1.PipelineRun{
+			ObjectMeta: metav1.ObjectMeta{Name: "pr", Namespace: ns},
+			Spec: tektonv1.PipelineRunSpec{
+				PipelineSpec: &tektonv1.PipelineSpec{Tasks: tasks},
+			},
+		}
+	}
+	task := func(name string, steps []tektonv1.Step, sidecars ...tektonv1.Sidecar) tektonv1.PipelineTask {
+		return tektonv1.PipelineTask{
+			Name: name,
+			TaskSpec: &tektonv1.EmbeddedTask{TaskSpec: tektonv1.TaskSpec{
+				Steps:    steps,
+				Sidecars: sidecars,
+			}},
+		}
+	}
+	quota := func(hard, used corev1.ResourceList) *corev1.ResourceQuota {
+		return &corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: "quota", Namespace: ns},
+			Status:     corev1.ResourceQuotaStatus{Hard: hard, Used: used},
+		}
+	}
+
+	tests := []struct {
+		name            string
+		pr              *tektonv1.PipelineRun
+		quota           *corev1.ResourceQuota
+		wantMessage     string
+		wantExceedsHard bool
+	}{
+		{
+			name: "no quota",
+			pr:   pipelineRun(task("build", []tektonv1.Step{step("4", "8Gi")})),
+		},
+		{
+			name: "fits in the quota",
+			pr:   pipelineRun(task("build", []tektonv1.Step{step("1", "1Gi"), step("2", "512Mi")})),
+			quota: quota(
+				corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("4")},
+				corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("1")},
+			),
+		},
+		{
+			name: "exceeds the hard quota",
+			pr:   pipelineRun(task("build", []tektonv1.Step{step("1", "4Gi")})),
+			quota: quota(
+				corev1.ResourceList{corev1.ResourceRequestsMemory: resource.MustParse("2Gi")},
+				nil,
+			),
+			wantExceedsHard: true,
+			wantMessage:     "insufficient quota: task build requests 4Gi of memory but the ResourceQuota quota only allows 2Gi",
+		},
+		{
+			name: "sidecars are added to the steps",
+			pr: pipelineRun(task("build", []tektonv1.Step{step("1", "1Gi")},
+				tektonv1.Sidecar{ComputeResources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+				}})),
+			quota: quota(
+				corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+				nil,
+			),
+			wantExceedsHard: true,
+			wantMessage:     "insufficient quota: task build requests 3 of cpu but the ResourceQuota quota only allows 2",
+		},
+		{
+			name: "exceeds the remaining quota",
+			pr:   pipelineRun(task("build", []tektonv1.Step{step("2", "1Gi")})),
+			quota: quota(
+				corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("4")},
+				corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("3")},
+			),
+			wantMessage: "insufficient quota: task build requests 2 of cpu but only 1 remains in the ResourceQuota quota, it will wait for other pods of the namespace to finish",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+				Namespaces: []*corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: ns}}},
+			})
+			if tt.quota != nil {
+				_, err := stdata.Kube.CoreV1().ResourceQuotas(ns).Create(ctx, tt.quota, metav1.CreateOptions{})
+				assert.NilError(t, err)
+			}
+			p := &PacRun{run: &params.Run{Clients: clients.Clients{Kube: stdata.Kube}}}
+			got, err := p.checkQuota(ctx, ns, tt.pr)
+			assert.NilError(t, err)
+			if tt.wantMessage == "" {
+				assert.Assert(t, got == nil)
+				return
+			}
+			assert.Assert(t, got != nil)
+			assert.Equal(t, got.message, tt.wantMessage)
+			assert.Equal(t, got.exceedsHard, tt.wantExceedsHard)
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package pipelineascode
+
+import (
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/pod"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// InjectRepositoryEnv sets the environment variables of the Repository in
+// the pod template of the PipelineRun so every step of its tasks gets them.
+// The variables the pod template of the PipelineRun already sets win over the
+// ones of the Repository.
+func InjectRepositoryEnv(pr *tektonv1.PipelineRun, env []corev1.EnvVar) {
+	if len(env) == 0 {
+		return
+	}
+	if pr.Spec.TaskRunTemplate.PodTemplate == nil {
+		pr.Spec.TaskRunTemplate.PodTemplate = &pod.PodTemplate{}
+	}
+	podTemplate := pr.Spec.TaskRunTemplate.PodTemplate
+	defined := map[string]bool{}
+	for _, e := range podTemplate.Env {
+		defined[e.Name] = true
+	}
+	for _, e := range env {
+		if defined[e.Name] {
+			continue
+		}
+		podTemplate.Env = append(podTemplate.Env, *e.DeepCopy())
+	}
+}
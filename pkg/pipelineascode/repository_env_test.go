@@ -0,0 +1,61 @@
+package pipelineascode
+
+import (
+	"testing"
+
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/pod"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestInjectRepositoryEnv(t *testing.T) {
+	secretEnv := corev1.EnvVar{
+		Name: "REGISTRY_TOKEN",
+		ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "registry"},
+			Key:                  "token",
+		}},
+	}
+	env := []corev1.EnvVar{
+		{Name: "HTTPS_PROXY", Value: "http://proxy:3128"},
+		secretEnv,
+	}
+
+	tests := []struct {
+		name        string
+		podTemplate *pod.PodTemplate
+		want        []corev1.EnvVar
+	}{
+		{
+			name: "no pod template",
+			want: env,
+		},
+		{
+			name: "pod template without env",
+			podTemplate: &pod.PodTemplate{
+				NodeSelector: map[string]string{"arch": "arm64"},
+			},
+			want: env,
+		},
+		{
+			name: "variables of the pipelinerun win",
+			podTemplate: &pod.PodTemplate{
+				Env: []corev1.EnvVar{{Name: "HTTPS_PROXY", Value: "http://other:3128"}},
+			},
+			want: []corev1.EnvVar{{Name: "HTTPS_PROXY", Value: "http://other:3128"}, secretEnv},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr := &tektonv1.PipelineRun{}
+			pr.Spec.TaskRunTemplate.PodTemplate = tt.podTemplate
+			InjectRepositoryEnv(pr, env)
+			assert.DeepEqual(t, pr.Spec.TaskRunTemplate.PodTemplate.Env, tt.want)
+		})
+	}
+
+	pr := &tektonv1.PipelineRun{}
+	InjectRepositoryEnv(pr, nil)
+	assert.Assert(t, pr.Spec.TaskRunTemplate.PodTemplate == nil)
+}
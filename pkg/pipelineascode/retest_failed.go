@@ -0,0 +1,57 @@
+package pipelineascode
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/sort"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+)
+
+// failedPipelineRunNames returns the original names of the PipelineRuns whose
+// last run on the SHA of the event failed, as recorded on the PipelineRuns of
+// the cluster. A PipelineRun which has been retested successfully since it
+// failed is not returned.
+func (p *PacRun) failedPipelineRunNames(ctx context.Context, repo *v1alpha1.Repository) (map[string]bool, error) {
+	prs, err := p.run.Clients.Tekton.TektonV1().PipelineRuns(repo.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: getLabelSelector(map[string]string{
+			keys.URLRepository: formatting.K8LabelsCleanup(p.event.Repository),
+			keys.SHA:           formatting.K8LabelsCleanup(p.event.SHA),
+		}),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pipelineRuns : %w", err)
+	}
+	sort.PipelineRunSortByStartTime(prs.Items)
+
+	seen := map[string]bool{}
+	failed := map[string]bool{}
+	for _, pr := range prs.Items {
+		name := pr.GetLabels()[keys.OriginalPRName]
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		if pr.IsDone() && pr.Status.GetCondition(apis.ConditionSucceeded).IsFalse() {
+			failed[name] = true
+		}
+	}
+	return failed, nil
+}
+
+// filterFailedPipelineRuns only keeps the PipelineRuns which failed on the
+// SHA of the event, for a /retest --failed comment.
+func filterFailedPipelineRuns(failed map[string]bool, prs []*tektonv1.PipelineRun) []*tektonv1.PipelineRun {
+	ret := []*tektonv1.PipelineRun{}
+	for _, pr := range prs {
+		if failed[pr.GetLabels()[keys.OriginalPRName]] {
+			ret = append(ret, pr)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,82 @@
+package pipelineascode
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	pipelinev1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	knativeduckv1 "knative.dev/pkg/apis/duck/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestRetestFailedPipelineRuns(t *testing.T) {
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	now := time.Now()
+	makePR := func(name, originalName string, status corev1.ConditionStatus, started time.Duration) *pipelinev1.PipelineRun {
+		labels := map[string]string{}
+		for k, v := range fooRepoLabels {
+			labels[k] = v
+		}
+		labels[keys.OriginalPRName] = originalName
+		return &pipelinev1.PipelineRun{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "foo",
+				Labels:    labels,
+			},
+			Status: pipelinev1.PipelineRunStatus{
+				Status: knativeduckv1.Status{
+					Conditions: knativeduckv1.Conditions{
+						{Type: apis.ConditionSucceeded, Status: status},
+					},
+				},
+				PipelineRunStatusFields: pipelinev1.PipelineRunStatusFields{
+					StartTime: &metav1.Time{Time: now.Add(-started)},
+				},
+			},
+		}
+	}
+	ctx, _ := rtesting.SetupFakeContext(t)
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+		Namespaces: []*corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: "foo"}}},
+		PipelineRuns: []*pipelinev1.PipelineRun{
+			makePR("lint-abcde", "lint", corev1.ConditionFalse, time.Hour),
+			makePR("build-fghij", "build", corev1.ConditionTrue, time.Hour),
+			// retested successfully after it failed
+			makePR("flaky-klmno", "flaky", corev1.ConditionFalse, time.Hour),
+			makePR("flaky-pqrst", "flaky", corev1.ConditionTrue, time.Minute),
+			makePR("running-uvwxy", "running", corev1.ConditionUnknown, time.Minute),
+		},
+	})
+	cs := &params.Run{
+		Clients: clients.Clients{Log: logger, Tekton: stdata.Pipeline, Kube: stdata.Kube},
+		Info:    info.Info{Pac: &info.PacOpts{}},
+	}
+	event := &info.Event{Repository: "foo", SHA: "foosha"}
+	pac := NewPacs(event, nil, cs, nil, logger)
+	failed, err := pac.failedPipelineRunNames(ctx, fooRepo)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, failed, map[string]bool{"lint": true})
+
+	prs := []*pipelinev1.PipelineRun{}
+	for _, name := range []string{"lint", "build", "flaky", "new"} {
+		prs = append(prs, &pipelinev1.PipelineRun{ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{keys.OriginalPRName: name},
+		}})
+	}
+	got := filterFailedPipelineRuns(failed, prs)
+	assert.Equal(t, len(got), 1)
+	assert.Equal(t, got[0].GetLabels()[keys.OriginalPRName], "lint")
+}
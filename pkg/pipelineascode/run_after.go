@@ -0,0 +1,118 @@
+package pipelineascode
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/matcher"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	"go.uber.org/zap"
+)
+
+// invalidRunAfter is a PipelineRun which cannot run because of its run-after
+// annotation.
+type invalidRunAfter struct {
+	match  matcher.Match
+	reason error
+}
+
+// orderByRunAfter splits the matched PipelineRuns between the ones starting
+// right away and the ones waiting for other PipelineRuns of the event with
+// the run-after annotation, the latter are sorted so every PipelineRun comes
+// after the ones it waits for. The PipelineRuns with an invalid run-after
+// annotation are returned in invalid with the reason.
+func orderByRunAfter(matches []matcher.Match) ([]matcher.Match, []matcher.Match, []invalidRunAfter) {
+	invalid := []invalidRunAfter{}
+	names := map[string]bool{}
+	for _, match := range matches {
+		names[match.PipelineRun.GetLabels()[keys.OriginalPRName]] = true
+	}
+
+	independent := []matcher.Match{}
+	waiting := []matcher.Match{}
+	runAfter := map[string][]string{}
+	for _, match := range matches {
+		name := match.PipelineRun.GetLabels()[keys.OriginalPRName]
+		after, err := matcher.RunAfter(match.PipelineRun)
+		if err != nil {
+			invalid = append(invalid, invalidRunAfter{match: match, reason: fmt.Errorf("invalid %s annotation: %w", keys.RunAfter, err)})
+			continue
+		}
+		if len(after) == 0 {
+			independent = append(independent, match)
+			continue
+		}
+		for _, dep := range after {
+			if !names[dep] {
+				err = fmt.Errorf("it runs after %s which doesn't run for this event", dep)
+				break
+			}
+		}
+		if err != nil {
+			invalid = append(invalid, invalidRunAfter{match: match, reason: err})
+			continue
+		}
+		runAfter[name] = after
+		waiting = append(waiting, match)
+	}
+
+	// sort the waiting PipelineRuns, a PipelineRun is ready once all the ones
+	// it runs after are either independent or already sorted.
+	ready := map[string]bool{}
+	for _, match := range independent {
+		ready[match.PipelineRun.GetLabels()[keys.OriginalPRName]] = true
+	}
+	dependent := []matcher.Match{}
+	for len(waiting) > 0 {
+		remaining := []matcher.Match{}
+		for _, match := range waiting {
+			name := match.PipelineRun.GetLabels()[keys.OriginalPRName]
+			isReady := true
+			for _, dep := range runAfter[name] {
+				if !ready[dep] {
+					isReady = false
+					break
+				}
+			}
+			if isReady {
+				dependent = append(dependent, match)
+				continue
+			}
+			remaining = append(remaining, match)
+		}
+		if len(remaining) == len(waiting) {
+			// nothing could be sorted, the remaining ones wait for each other
+			// or for an invalid one.
+			for _, match := range remaining {
+				invalid = append(invalid, invalidRunAfter{match: match, reason: fmt.Errorf("it runs after %s which can never run before it",
+					strings.Join(runAfter[match.PipelineRun.GetLabels()[keys.OriginalPRName]], ", "))})
+			}
+			break
+		}
+		for _, match := range dependent {
+			ready[match.PipelineRun.GetLabels()[keys.OriginalPRName]] = true
+		}
+		waiting = remaining
+	}
+	return independent, dependent, invalid
+}
+
+// reportInvalidRunAfter reports a PipelineRun which cannot be started because
+// of its run-after annotation as a failure.
+func (p *PacRun) reportInvalidRunAfter(ctx context.Context, repo *v1alpha1.Repository, invalid invalidRunAfter) {
+	name := invalid.match.PipelineRun.GetLabels()[keys.OriginalPRName]
+	msg := fmt.Sprintf("PipelineRun %s cannot be started: %s", name, invalid.reason.Error())
+	p.eventEmitter.EmitMessage(repo, zap.ErrorLevel, "RepositoryPipelineRunRunAfter", msg)
+	if err := p.vcx.CreateStatus(ctx, p.run.Clients.Tekton, p.event, p.run.Info.Pac, provider.StatusOpts{
+		Status:                  "completed",
+		Conclusion:              "failure",
+		Text:                    msg,
+		DetailsURL:              p.run.Clients.ConsoleUI.URL(),
+		OriginalPipelineRunName: name,
+	}); err != nil {
+		p.logger.Errorf("cannot create a failure status for PipelineRun %s: %v", name, err)
+	}
+}
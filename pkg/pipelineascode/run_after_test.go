@@ -0,0 +1,91 @@
+package pipelineascode
+
+import (
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/matcher"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestOrderByRunAfter(t *testing.T) {
+	match := func(name, runAfter string) matcher.Match {
+		pr := &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{keys.OriginalPRName: name},
+		}}
+		if runAfter != "" {
+			pr.Annotations = map[string]string{keys.RunAfter: runAfter}
+		}
+		return matcher.Match{PipelineRun: pr}
+	}
+	names := func(matches []matcher.Match) []string {
+		ret := []string{}
+		for _, m := range matches {
+			ret = append(ret, m.PipelineRun.GetLabels()[keys.OriginalPRName])
+		}
+		return ret
+	}
+
+	tests := []struct {
+		name            string
+		matches         []matcher.Match
+		wantIndependent []string
+		wantDependent   []string
+		wantInvalid     map[string]string
+	}{
+		{
+			name:            "no run-after",
+			matches:         []matcher.Match{match("tests", ""), match("lint", "")},
+			wantIndependent: []string{"tests", "lint"},
+			wantDependent:   []string{},
+		},
+		{
+			name: "chain sorted",
+			matches: []matcher.Match{
+				match("release", "deploy"),
+				match("deploy", "[tests, lint]"),
+				match("tests", ""),
+				match("lint", ""),
+			},
+			wantIndependent: []string{"tests", "lint"},
+			wantDependent:   []string{"deploy", "release"},
+		},
+		{
+			name: "runs after a PipelineRun not matching the event",
+			matches: []matcher.Match{
+				match("deploy", "tests"),
+				match("lint", ""),
+			},
+			wantIndependent: []string{"lint"},
+			wantDependent:   []string{},
+			wantInvalid:     map[string]string{"deploy": "it runs after tests which doesn't run for this event"},
+		},
+		{
+			name: "cycle",
+			matches: []matcher.Match{
+				match("a", "b"),
+				match("b", "a"),
+				match("c", ""),
+			},
+			wantIndependent: []string{"c"},
+			wantDependent:   []string{},
+			wantInvalid: map[string]string{
+				"a": "it runs after b which can never run before it",
+				"b": "it runs after a which can never run before it",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			independent, dependent, invalid := orderByRunAfter(tt.matches)
+			assert.DeepEqual(t, names(independent), tt.wantIndependent)
+			assert.DeepEqual(t, names(dependent), tt.wantDependent)
+			assert.Equal(t, len(invalid), len(tt.wantInvalid))
+			for _, inv := range invalid {
+				assert.Equal(t, inv.reason.Error(), tt.wantInvalid[inv.match.PipelineRun.GetLabels()[keys.OriginalPRName]])
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package pipelineascode
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/matcher"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.uber.org/zap"
+)
+
+type secretPattern struct {
+	name   string
+	regexp *regexp.Regexp
+}
+
+// secretPatterns are the credentials looked for in the PipelineRuns, they
+// match well known token formats to not refuse PipelineRuns on false
+// positives.
+var secretPatterns = []secretPattern{
+	{name: "AWS access key", regexp: regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{name: "GitHub token", regexp: regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})`)},
+	{name: "GitLab token", regexp: regexp.MustCompile(`\bglpat-[A-Za-z0-9_-]{20,}`)},
+	{name: "Slack token", regexp: regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}`)},
+	{name: "Google API key", regexp: regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}`)},
+	{name: "private key", regexp: regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`)},
+	// a password in a URL, not a Tekton or Pipelines as Code variable
+	{name: "password in a URL", regexp: regexp.MustCompile(`[A-Za-z][A-Za-z0-9+.-]*://[^/\s:@]+:[^/\s:@$({][^/\s:@]*@`)},
+}
+
+// secretFinding is a credential found in a field of a PipelineRun, the value
+// is never kept to not leak it in the reports.
+type secretFinding struct {
+	Field   string
+	Pattern string
+}
+
+// findSecrets returns the credentials inlined in the PipelineRun, sorted by
+// field.
+func findSecrets(pr *tektonv1.PipelineRun) ([]secretFinding, error) {
+	b, err := json.Marshal(pr)
+	if err != nil {
+		return nil, err
+	}
+	var doc interface{}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+	findings := []secretFinding{}
+	walkSecrets(doc, "", &findings)
+	return findings, nil
+}
+
+func walkSecrets(node interface{}, field string, findings *[]secretFinding) {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			child := name
+			if field != "" {
+				child = field + "." + name
+			}
+			walkSecrets(v[name], child, findings)
+		}
+	case []interface{}:
+		for i, item := range v {
+			walkSecrets(item, fmt.Sprintf("%s[%d]", field, i), findings)
+		}
+	case string:
+		for _, pattern := range secretPatterns {
+			if pattern.regexp.MatchString(v) {
+				*findings = append(*findings, secretFinding{Field: field, Pattern: pattern.name})
+			}
+		}
+	}
+}
+
+// scanSecrets returns the matched PipelineRuns without inlined credentials
+// when the secret scanning is enabled, the other ones are not created and get
+// a failure status listing where the credentials were found.
+func (p *PacRun) scanSecrets(ctx context.Context, repo *v1alpha1.Repository, matches []matcher.Match) []matcher.Match {
+	if !p.run.Info.Pac.SecretScanning {
+		return matches
+	}
+	kept := []matcher.Match{}
+	for _, match := range matches {
+		findings, err := findSecrets(match.PipelineRun)
+		if err != nil {
+			p.logger.Errorf("cannot scan the PipelineRun %s for secrets: %v", match.PipelineRun.GetGenerateName(), err)
+			continue
+		}
+		if len(findings) == 0 {
+			kept = append(kept, match)
+			continue
+		}
+		p.reportSecretsFound(ctx, repo, match.PipelineRun.GetLabels()[keys.OriginalPRName], findings)
+	}
+	return kept
+}
+
+func (p *PacRun) reportSecretsFound(ctx context.Context, repo *v1alpha1.Repository, name string, findings []secretFinding) {
+	lines := make([]string, 0, len(findings))
+	for _, finding := range findings {
+		lines = append(lines, fmt.Sprintf("- `%s`: %s", finding.Field, finding.Pattern))
+	}
+	msg := fmt.Sprintf("The PipelineRun %s has not been created, it appears to embed credentials:\n%s",
+		name, strings.Join(lines, "\n"))
+	p.eventEmitter.EmitMessage(repo, zap.ErrorLevel, "RepositorySecretScanning", msg)
+
+	status := provider.StatusOpts{
+		Status:                  "completed",
+		Conclusion:              "failure",
+		Title:                   "Credentials detected",
+		Text:                    msg + "\n\nStore the credentials in a Kubernetes Secret and reference it from the PipelineRun instead.",
+		DetailsURL:              p.run.Clients.ConsoleUI.URL(),
+		OriginalPipelineRunName: name,
+	}
+	if err := p.vcx.CreateStatus(ctx, p.run.Clients.Tekton, p.event, p.run.Info.Pac, status); err != nil {
+		p.logger.Errorf("failed to create the status of the PipelineRun %s embedding credentials: %s", name, err.Error())
+	}
+}
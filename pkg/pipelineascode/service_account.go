@@ -0,0 +1,17 @@
+package pipelineascode
+
+import (
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+)
+
+// applyServiceAccountMapping sets the ServiceAccount the Repository maps to
+// the type and the target branch of the event on the PipelineRun. The mapping
+// wins over the ServiceAccount of the PipelineRun, a pull request cannot pick
+// the ServiceAccount of the pushes.
+func applyServiceAccountMapping(event *info.Event, repo *v1alpha1.Repository, pr *tektonv1.PipelineRun) {
+	if sa := repo.ServiceAccountForEvent(event.EventTypes(), event.BaseBranch); sa != "" {
+		pr.Spec.TaskRunTemplate.ServiceAccountName = sa
+	}
+}
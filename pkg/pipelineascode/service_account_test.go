@@ -0,0 +1,59 @@
+package pipelineascode
+
+import (
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"gotest.tools/v3/assert"
+)
+
+func TestApplyServiceAccountMapping(t *testing.T) {
+	repo := &v1alpha1.Repository{
+		Spec: v1alpha1.RepositorySpec{
+			ServiceAccounts: []v1alpha1.ServiceAccountMapping{
+				{EventType: "incoming", ServiceAccount: "incoming"},
+				{EventType: "push", Branches: []string{"main"}, ServiceAccount: "deploy"},
+				{EventType: "pull_request", ServiceAccount: "restricted"},
+			},
+		},
+	}
+	tests := []struct {
+		name           string
+		event          *info.Event
+		serviceAccount string
+		want           string
+	}{
+		{
+			name:  "push to main",
+			event: &info.Event{TriggerTarget: "push", EventType: "push", BaseBranch: "main"},
+			want:  "deploy",
+		},
+		{
+			name:           "pull request overrides the ServiceAccount of the PipelineRun",
+			event:          &info.Event{TriggerTarget: "pull_request", EventType: "pull_request", BaseBranch: "main"},
+			serviceAccount: "deploy",
+			want:           "restricted",
+		},
+		{
+			name:  "incoming webhook",
+			event: &info.Event{TriggerTarget: "push", EventType: "incoming", BaseBranch: "main"},
+			want:  "incoming",
+		},
+		{
+			name:           "no mapping keeps the ServiceAccount of the PipelineRun",
+			event:          &info.Event{TriggerTarget: "push", EventType: "push", BaseBranch: "feature"},
+			serviceAccount: "builder",
+			want:           "builder",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr := &tektonv1.PipelineRun{}
+			pr.Spec.TaskRunTemplate.ServiceAccountName = tt.serviceAccount
+			applyServiceAccountMapping(tt.event, repo, pr)
+			assert.Equal(t, pr.Spec.TaskRunTemplate.ServiceAccountName, tt.want)
+		})
+	}
+}
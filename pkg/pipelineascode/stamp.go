@@ -0,0 +1,99 @@
+package pipelineascode
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/version"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+)
+
+// stampRunEnvironment records on the PipelineRun what it has been created
+// with so it can be audited and reproduced after an upgrade: the version of
+// the controller, the digests of the tasks embedded in it once resolved, a
+// snapshot of the settings changing how it runs and a digest of all the
+// settings. The PipelineRuns can be read by all the users of the namespace,
+// the other settings are not recorded since they can be sensitive, ie: the
+// URL of the event filter or the templates of the injected PipelineRuns.
+func stampRunEnvironment(pr *tektonv1.PipelineRun, pacSettings *settings.Settings) error {
+	if pr.Annotations == nil {
+		pr.Annotations = map[string]string{}
+	}
+	pr.Annotations[keys.ControllerVersion] = version.Version
+
+	digests, err := taskDigests(pr)
+	if err != nil {
+		return err
+	}
+	if len(digests) > 0 {
+		b, err := json.Marshal(digests)
+		if err != nil {
+			return err
+		}
+		pr.Annotations[keys.TaskDigests] = string(b)
+	}
+
+	if pacSettings != nil {
+		b, err := json.Marshal(settingsSnapshot(pacSettings))
+		if err != nil {
+			return err
+		}
+		pr.Annotations[keys.SettingsSnapshot] = string(b)
+
+		all, err := json.Marshal(pacSettings)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(all)
+		pr.Annotations[keys.SettingsDigest] = "sha256:" + hex.EncodeToString(sum[:])
+	}
+	return nil
+}
+
+// settingsSnapshot returns the settings changing how the PipelineRuns run by
+// their key in the configmap.
+func settingsSnapshot(s *settings.Settings) map[string]string {
+	return map[string]string{
+		settings.RemoteTasksKey:                strconv.FormatBool(s.RemoteTasks),
+		settings.HubURLKey:                     s.HubURL,
+		settings.HubCatalogNameKey:             s.HubCatalogName,
+		settings.MaxKeepRunUpperLimitKey:       strconv.Itoa(s.MaxKeepRunsUpperLimit),
+		settings.DefaultMaxKeepRunsKey:         strconv.Itoa(s.DefaultMaxKeepRuns),
+		settings.SecretAutoCreateKey:           strconv.FormatBool(s.SecretAutoCreation),
+		settings.SecretAutoCreateModeKey:       s.SecretAutoCreateMode,
+		settings.SecretGhAppTokenRepoScopedKey: strconv.FormatBool(s.SecretGHAppRepoScoped),
+		settings.ErrorDetectionKey:             strconv.FormatBool(s.ErrorDetection),
+		settings.ErrorLogSnippetKey:            strconv.FormatBool(s.ErrorLogSnippet),
+		settings.PinStepImagesKey:              strconv.FormatBool(s.PinStepImages),
+		settings.SecretScanningKey:             strconv.FormatBool(s.SecretScanning),
+		settings.NodePoolLabelKey:              s.NodePoolLabel,
+	}
+}
+
+// taskDigests returns the sha256 digests of the specs of the tasks embedded in
+// the PipelineRun by their name in the pipeline, the tasks referenced from the
+// cluster, a bundle or a resolver are resolved by Tekton and not recorded.
+func taskDigests(pr *tektonv1.PipelineRun) (map[string]string, error) {
+	digests := map[string]string{}
+	if pr.Spec.PipelineSpec == nil {
+		return digests, nil
+	}
+	tasks := append([]tektonv1.PipelineTask{}, pr.Spec.PipelineSpec.Tasks...)
+	tasks = append(tasks, pr.Spec.PipelineSpec.Finally...)
+	for _, task := range tasks {
+		if task.TaskSpec == nil {
+			continue
+		}
+		b, err := json.Marshal(task.TaskSpec)
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(b)
+		digests[task.Name] = "sha256:" + hex.EncodeToString(sum[:])
+	}
+	return digests, nil
+}
@@ -0,0 +1,92 @@
+package pipelineascode
+
+import (
+	"context"
+	"sync"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+)
+
+// noTektonDirCacheSize is the number of revisions remembered as having no
+// .tekton directory.
+const noTektonDirCacheSize = 4096
+
+// noTektonDirRevisions remembers the revisions of the repositories known to
+// have no .tekton directory. The content of a revision never changes, another
+// event on the same revision (ie: a comment on the pull request or a
+// redelivered webhook) is skipped without calling the provider. It is shared
+// by all the events handled by the process.
+var noTektonDirRevisions = newRevisionCache(noTektonDirCacheSize)
+
+// revisionCache is a set of revisions of a bounded size, the oldest revision
+// is forgotten when a new one is added to a full cache.
+type revisionCache struct {
+	mutex     sync.Mutex
+	size      int
+	revisions map[string]bool
+	order     []string
+}
+
+func newRevisionCache(size int) *revisionCache {
+	return &revisionCache{size: size, revisions: map[string]bool{}}
+}
+
+func (c *revisionCache) add(key string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.revisions[key] {
+		return
+	}
+	if len(c.order) >= c.size {
+		delete(c.revisions, c.order[0])
+		c.order = c.order[1:]
+	}
+	c.revisions[key] = true
+	c.order = append(c.order, key)
+}
+
+func (c *revisionCache) contains(key string) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.revisions[key]
+}
+
+// revisionKey returns the key of the revision of the event in the cache,
+// empty when the content the event is matched against can change: when we
+// don't know the SHA or on the GitLab merged results whose ref moves with the
+// target branch.
+func revisionKey(event *info.Event) string {
+	if event.SHA == "" || event.MergeRequestRef != "" {
+		return ""
+	}
+	return event.URL + "@" + event.SHA
+}
+
+// knownWithoutTektonDir returns true if the revision of the event is known to
+// have no .tekton directory.
+func knownWithoutTektonDir(event *info.Event) bool {
+	key := revisionKey(event)
+	return key != "" && noTektonDirRevisions.contains(key)
+}
+
+// hasTektonDir checks cheaply if the .tekton directory exists at the revision
+// of the event with the providers supporting it, the revision is remembered
+// when it doesn't. It returns true when the provider cannot tell, the
+// directory is then looked for when fetching the PipelineRuns.
+func (p *PacRun) hasTektonDir(ctx context.Context) bool {
+	checker, ok := p.vcx.(provider.TektonDirChecker)
+	key := revisionKey(p.event)
+	if !ok || key == "" {
+		return true
+	}
+	found, err := checker.HasTektonDir(ctx, p.event, tektonDir)
+	if err != nil {
+		p.logger.Debugf("cannot check if the %s directory exists at %s: %v", tektonDir, p.event.SHA, err)
+		return true
+	}
+	if !found {
+		noTektonDirRevisions.add(key)
+	}
+	return found
+}
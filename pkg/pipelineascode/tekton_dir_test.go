@@ -0,0 +1,99 @@
+package pipelineascode
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	testprovider "github.com/openshift-pipelines/pipelines-as-code/pkg/test/provider"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+)
+
+type tektonDirCheckerProvider struct {
+	testprovider.TestProviderImp
+	found  bool
+	err    error
+	checks int
+}
+
+func (v *tektonDirCheckerProvider) HasTektonDir(_ context.Context, _ *info.Event, path string) (bool, error) {
+	v.checks++
+	if path != tektonDir {
+		return false, fmt.Errorf("unexpected path %s", path)
+	}
+	return v.found, v.err
+}
+
+func TestRevisionCache(t *testing.T) {
+	cache := newRevisionCache(2)
+	cache.add("a")
+	cache.add("b")
+	cache.add("a")
+	assert.Assert(t, cache.contains("a"))
+	assert.Assert(t, cache.contains("b"))
+	cache.add("c")
+	assert.Assert(t, !cache.contains("a"))
+	assert.Assert(t, cache.contains("b"))
+	assert.Assert(t, cache.contains("c"))
+}
+
+func TestRevisionKey(t *testing.T) {
+	assert.Equal(t, revisionKey(&info.Event{URL: "https://forge/owner/repo", SHA: "sha"}), "https://forge/owner/repo@sha")
+	assert.Equal(t, revisionKey(&info.Event{URL: "https://forge/owner/repo"}), "")
+	assert.Equal(t, revisionKey(&info.Event{URL: "https://forge/owner/repo", SHA: "sha", MergeRequestRef: "refs/merge-requests/1/merge"}), "")
+}
+
+func TestHasTektonDir(t *testing.T) {
+	tests := []struct {
+		name           string
+		event          *info.Event
+		found          bool
+		err            error
+		want           bool
+		wantChecks     int
+		wantRemembered bool
+	}{
+		{
+			name:       "tekton dir found",
+			event:      &info.Event{URL: "https://forge/owner/found", SHA: "sha"},
+			found:      true,
+			want:       true,
+			wantChecks: 1,
+		},
+		{
+			name:           "no tekton dir",
+			event:          &info.Event{URL: "https://forge/owner/notfound", SHA: "sha"},
+			wantChecks:     1,
+			wantRemembered: true,
+		},
+		{
+			name:       "error checking",
+			event:      &info.Event{URL: "https://forge/owner/error", SHA: "sha"},
+			err:        fmt.Errorf("api down"),
+			want:       true,
+			wantChecks: 1,
+		},
+		{
+			name:  "no sha to check",
+			event: &info.Event{URL: "https://forge/owner/nosha"},
+			want:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			observer, _ := zapobserver.New(zap.InfoLevel)
+			vcx := &tektonDirCheckerProvider{found: tt.found, err: tt.err}
+			p := &PacRun{event: tt.event, vcx: vcx, logger: zap.New(observer).Sugar()}
+			assert.Equal(t, p.hasTektonDir(context.Background()), tt.want)
+			assert.Equal(t, vcx.checks, tt.wantChecks)
+			assert.Equal(t, knownWithoutTektonDir(tt.event), tt.wantRemembered)
+		})
+	}
+
+	// providers without the check are always looked into
+	p := &PacRun{event: &info.Event{URL: "https://forge/owner/nocheck", SHA: "sha"}, vcx: &testprovider.TestProviderImp{}}
+	assert.Assert(t, p.hasTektonDir(context.Background()))
+}
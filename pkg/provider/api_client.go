@@ -0,0 +1,239 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+)
+
+// defaultCircuitBreakerCooldown is how long the circuit breaker stays open
+// when the setting doesn't set a cooldown.
+const defaultCircuitBreakerCooldown = 30 * time.Second
+
+// retryBackoff is the wait before the first retry of a request, it doubles
+// on every retry.
+var retryBackoff = 500 * time.Millisecond
+
+// circuitBreakers are the circuit breakers of the provider APIs by host,
+// they are shared by all the provider clients of the process since a client
+// is created for every event.
+var circuitBreakers = struct {
+	sync.Mutex
+	hosts map[string]*circuitBreaker
+}{hosts: map[string]*circuitBreaker{}}
+
+// ErrCircuitOpen is returned without calling the provider API when its
+// circuit breaker is open.
+type ErrCircuitOpen struct {
+	Host  string
+	Until time.Time
+}
+
+func (e *ErrCircuitOpen) Error() string {
+	return fmt.Sprintf("the %s API failed too many times in a row, not calling it until %s", e.Host, e.Until.Format(time.RFC3339))
+}
+
+// circuitBreaker counts the consecutive failures of an API, when they reach
+// the threshold the requests fail right away until the cooldown is over and
+// a single request is let through to check if the API is back.
+type circuitBreaker struct {
+	mutex     sync.Mutex
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+func circuitBreakerFor(host string) *circuitBreaker {
+	circuitBreakers.Lock()
+	defer circuitBreakers.Unlock()
+	cb, ok := circuitBreakers.hosts[host]
+	if !ok {
+		cb = &circuitBreaker{}
+		circuitBreakers.hosts[host] = cb
+	}
+	return cb
+}
+
+// allow returns an error if the request cannot be made.
+func (cb *circuitBreaker) allow(host string, threshold int, now time.Time) error {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+	if cb.failures < threshold {
+		return nil
+	}
+	if now.Before(cb.openUntil) || cb.probing {
+		return &ErrCircuitOpen{Host: host, Until: cb.openUntil}
+	}
+	cb.probing = true
+	return nil
+}
+
+// release lets the next request through after a request which is neither a
+// success nor a failure of the API, ie: a rate limited one.
+func (cb *circuitBreaker) release() {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+	cb.probing = false
+}
+
+// record records the result of a request, the circuit opens for the cooldown
+// when the failures reach the threshold.
+func (cb *circuitBreaker) record(failed bool, threshold int, cooldown time.Duration, now time.Time) {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+	cb.probing = false
+	if !failed {
+		cb.failures = 0
+		return
+	}
+	cb.failures++
+	if cb.failures >= threshold {
+		cb.openUntil = now.Add(cooldown)
+	}
+}
+
+// apiTransport applies the timeout, retries and circuit breaker of the
+// provider API client settings to the requests.
+type apiTransport struct {
+	base   http.RoundTripper
+	config settings.ProviderAPIClient
+}
+
+// NewAPITransport returns a transport applying the provider API client
+// settings to the requests made through base.
+func NewAPITransport(base http.RoundTripper, config settings.ProviderAPIClient) http.RoundTripper {
+	if config == (settings.ProviderAPIClient{}) {
+		return base
+	}
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &apiTransport{base: base, config: config}
+}
+
+// WithAPIClientConfig returns a context where the provider clients created
+// by SetClient apply the API client settings of the provider named name,
+// wrapping the client already set in the context if any.
+func WithAPIClientConfig(ctx context.Context, run *params.Run, name string) context.Context {
+	if run == nil || run.Info.Pac == nil || run.Info.Pac.Settings == nil {
+		return ctx
+	}
+	config := settings.ProviderAPIClientFor(run.Info.Pac.ProviderAPIClients, name)
+	if config == (settings.ProviderAPIClient{}) {
+		return ctx
+	}
+	var base http.RoundTripper
+	if client := HTTPClient(ctx); client != nil {
+		base = client.Transport
+	}
+	return WithHTTPClient(ctx, &http.Client{Transport: NewAPITransport(base, config)})
+}
+
+// retryable returns true if the request can be sent again, only the read
+// requests are retried since a write may have been applied by the API even
+// if it failed to answer.
+func retryable(req *http.Request) bool {
+	return req.Method == http.MethodGet || req.Method == http.MethodHead || req.Method == http.MethodOptions
+}
+
+// failed returns true if the API is considered failing, the client errors
+// are the fault of the request.
+func failed(resp *http.Response, err error) bool {
+	return err != nil || resp.StatusCode >= http.StatusInternalServerError
+}
+
+// rateLimited returns true if the request was refused by the rate limit of
+// the API, which is the one of the credentials of the request: it doesn't
+// count as a failure of the API for the circuit breaker shared by all the
+// credentials of the host. GitHub answers 403 with no remaining request or a
+// Retry-After header for its rate limits.
+func rateLimited(resp *http.Response) bool {
+	if resp == nil {
+		return false
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	return resp.StatusCode == http.StatusForbidden &&
+		(resp.Header.Get("X-RateLimit-Remaining") == "0" || resp.Header.Get("Retry-After") != "")
+}
+
+func (t *apiTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var cb *circuitBreaker
+	if t.config.CircuitBreakerThreshold > 0 {
+		cb = circuitBreakerFor(req.URL.Host)
+	}
+	cooldown := t.config.CircuitBreakerCooldown
+	if cooldown == 0 {
+		cooldown = defaultCircuitBreakerCooldown
+	}
+
+	attempts := 1
+	if retryable(req) {
+		attempts += t.config.Retries
+	}
+	backoff := retryBackoff
+	for attempt := 1; ; attempt++ {
+		if cb != nil {
+			if err := cb.allow(req.URL.Host, t.config.CircuitBreakerThreshold, time.Now()); err != nil {
+				return nil, err
+			}
+		}
+		resp, err := t.roundTrip(req)
+		limited := err == nil && rateLimited(resp)
+		if cb != nil {
+			if limited {
+				cb.release()
+			} else {
+				cb.record(failed(resp, err), t.config.CircuitBreakerThreshold, cooldown, time.Now())
+			}
+		}
+		if !(failed(resp, err) || limited) || attempt >= attempts || req.Context().Err() != nil {
+			return resp, err
+		}
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// roundTrip sends the request with the timeout, the timeout covers reading
+// the body of the response which is why it is only cancelled when the body
+// is closed.
+func (t *apiTransport) roundTrip(req *http.Request) (*http.Response, error) {
+	if t.config.Timeout == 0 {
+		return t.base.RoundTrip(req)
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), t.config.Timeout)
+	resp, err := t.base.RoundTrip(req.Clone(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
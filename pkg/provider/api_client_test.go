@@ -0,0 +1,157 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"gotest.tools/v3/assert"
+)
+
+func TestAPITransport(t *testing.T) {
+	retryBackoff = time.Millisecond
+	tests := []struct {
+		name      string
+		config    settings.ProviderAPIClient
+		method    string
+		status    int
+		failures  int32
+		delay     time.Duration
+		requests  int
+		wantCalls int32
+		wantErr   bool
+		wantOpen  bool
+	}{
+		{
+			name:      "read request is retried",
+			config:    settings.ProviderAPIClient{Retries: 2},
+			method:    http.MethodGet,
+			failures:  2,
+			requests:  1,
+			wantCalls: 3,
+		},
+		{
+			name:      "write request is not retried",
+			config:    settings.ProviderAPIClient{Retries: 2},
+			method:    http.MethodPost,
+			failures:  2,
+			requests:  1,
+			wantCalls: 1,
+		},
+		{
+			name:      "timeout",
+			config:    settings.ProviderAPIClient{Timeout: 10 * time.Millisecond},
+			method:    http.MethodGet,
+			delay:     time.Second,
+			requests:  1,
+			wantCalls: 1,
+			wantErr:   true,
+		},
+		{
+			name:      "circuit breaker opens after the threshold",
+			config:    settings.ProviderAPIClient{CircuitBreakerThreshold: 2, CircuitBreakerCooldown: time.Hour},
+			method:    http.MethodGet,
+			failures:  10,
+			requests:  3,
+			wantCalls: 2,
+			wantOpen:  true,
+		},
+		{
+			name:      "rate limits are retried",
+			config:    settings.ProviderAPIClient{Retries: 2},
+			method:    http.MethodGet,
+			status:    http.StatusTooManyRequests,
+			failures:  2,
+			requests:  1,
+			wantCalls: 3,
+		},
+		{
+			name:      "rate limits don't open the circuit breaker",
+			config:    settings.ProviderAPIClient{CircuitBreakerThreshold: 2, CircuitBreakerCooldown: time.Hour},
+			method:    http.MethodGet,
+			status:    http.StatusTooManyRequests,
+			failures:  10,
+			requests:  3,
+			wantCalls: 3,
+		},
+		{
+			name:      "github rate limits don't open the circuit breaker",
+			config:    settings.ProviderAPIClient{CircuitBreakerThreshold: 2, CircuitBreakerCooldown: time.Hour},
+			method:    http.MethodGet,
+			status:    http.StatusForbidden,
+			failures:  10,
+			requests:  3,
+			wantCalls: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := atomic.AddInt32(&calls, 1)
+				if tt.delay > 0 {
+					select {
+					case <-time.After(tt.delay):
+					case <-r.Context().Done():
+					}
+				}
+				if n <= tt.failures {
+					status := tt.status
+					if status == 0 {
+						status = http.StatusBadGateway
+					}
+					if status == http.StatusForbidden {
+						w.Header().Set("X-RateLimit-Remaining", "0")
+					}
+					w.WriteHeader(status)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			client := &http.Client{Transport: NewAPITransport(nil, tt.config)}
+			var err error
+			for i := 0; i < tt.requests; i++ {
+				var req *http.Request
+				req, err = http.NewRequestWithContext(context.Background(), tt.method, server.URL, nil)
+				assert.NilError(t, err)
+				var resp *http.Response
+				resp, err = client.Do(req)
+				if err == nil {
+					resp.Body.Close()
+				}
+			}
+			assert.Equal(t, atomic.LoadInt32(&calls), tt.wantCalls)
+			assert.Equal(t, err != nil, tt.wantErr || tt.wantOpen)
+			var open *ErrCircuitOpen
+			assert.Equal(t, errors.As(err, &open), tt.wantOpen)
+		})
+	}
+}
+
+func TestWithAPIClientConfig(t *testing.T) {
+	run := &params.Run{Info: info.Info{Pac: &info.PacOpts{Settings: &settings.Settings{
+		ProviderAPIClients: map[string]settings.ProviderAPIClient{
+			"gitlab": {Timeout: time.Second},
+		},
+	}}}}
+	assert.Assert(t, HTTPClient(WithAPIClientConfig(context.Background(), run, "gitea")) == nil)
+	assert.Assert(t, HTTPClient(WithAPIClientConfig(context.Background(), &params.Run{}, "gitlab")) == nil)
+
+	// the client already set in the context is wrapped
+	ctx := WithAPIClientConfig(WithInsecureSkipTLSVerify(context.Background()), run, "gitlab")
+	transport, ok := HTTPClient(ctx).Transport.(*apiTransport)
+	assert.Assert(t, ok)
+	assert.Equal(t, transport.config.Timeout, time.Second)
+	base, ok := transport.base.(*http.Transport)
+	assert.Assert(t, ok)
+	assert.Assert(t, base.TLSClientConfig.InsecureSkipVerify)
+}
@@ -0,0 +1,19 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+)
+
+// PullRequestApprovedEventType is the event type of a pull request approval,
+// to be used in the on-event annotation.
+const PullRequestApprovedEventType = "pull_request_approved"
+
+// ApprovalsCounter is implemented by the providers able to count the
+// approvals of a pull request.
+type ApprovalsCounter interface {
+	// CountApprovals returns the number of users who currently approve the
+	// pull request of the event.
+	CountApprovals(ctx context.Context, event *info.Event) (int, error)
+}
@@ -21,6 +21,12 @@ func (v *Provider) IsAllowed(_ context.Context, event *info.Event) (bool, error)
 		return true, nil
 	}
 
+	// only the members can override failed PipelineRuns, not the users
+	// allowed to run the CI with /ok-to-test
+	if event.OverridePipelineRuns {
+		return false, nil
+	}
+
 	// Check then from comment if there is a approved user that has done a /ok-to-test
 	return v.checkOkToTestCommentFromApprovedMember(event)
 }
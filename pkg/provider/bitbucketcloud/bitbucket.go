@@ -8,6 +8,7 @@ import (
 
 	"github.com/ktrysmt/go-bitbucket"
 	"github.com/mitchellh/mapstructure"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
@@ -28,11 +29,6 @@ func (v *Provider) GetTaskURI(ctx context.Context, params *params.Run, event *in
 	return false, "", nil
 }
 
-const taskStatusTemplate = `| **Status** | **Duration** | **Name** |
-| --- | --- | --- |
-{{range $taskrun := .TaskRunList }}|{{ formatCondition $taskrun.PipelineRunTaskRunStatus.Status.Conditions }}|{{ formatDuration $taskrun.PipelineRunTaskRunStatus.Status.StartTime $taskrun.PipelineRunTaskRunStatus.Status.CompletionTime }}|{{ $taskrun.ConsoleLogURL }}|
-{{ end }}`
-
 func (v *Provider) Validate(ctx context.Context, params *params.Run, event *info.Event) error {
 	return nil
 }
@@ -43,13 +39,14 @@ func (v *Provider) SetLogger(logger *zap.SugaredLogger) {
 
 func (v *Provider) GetConfig() *info.ProviderConfig {
 	return &info.ProviderConfig{
-		TaskStatusTMPL: taskStatusTemplate,
+		TaskStatusTMPL: formatting.MarkdownTaskStatusTemplate,
 		APIURL:         bitbucket.DEFAULT_BITBUCKET_API_BASE_URL,
 		Name:           "bitbucket-cloud",
 	}
 }
 
 func (v *Provider) CreateStatus(_ context.Context, _ versioned.Interface, event *info.Event, pacopts *info.PacOpts, statusopts provider.StatusOpts) error {
+	statusopts = provider.BrandStatus(pacopts, statusopts)
 	switch statusopts.Conclusion {
 	case "skipped":
 		statusopts.Conclusion = "STOPPED"
@@ -69,6 +66,9 @@ func (v *Provider) CreateStatus(_ context.Context, _ versioned.Interface, event
 	case "completed":
 		statusopts.Conclusion = "SUCCESSFUL"
 		statusopts.Title = "✅ Completed"
+	case provider.ConclusionOverridden:
+		statusopts.Conclusion = "SUCCESSFUL"
+		statusopts.Title = "⏭️ Failure has been overridden"
 	}
 	detailsURL := event.Provider.URL
 	if statusopts.DetailsURL != "" {
@@ -95,18 +95,21 @@ func (v *Provider) CreateStatus(_ context.Context, _ versioned.Interface, event
 	if err != nil {
 		return err
 	}
+	// the comments cannot be edited, the throttled comment strategy only
+	// comments the failures
+	throttled := provider.IsCommentThrottled(event) && statusopts.Conclusion == "SUCCESSFUL"
 	if statusopts.Conclusion != "STOPPED" && statusopts.Status == "completed" &&
-		statusopts.Text != "" && event.EventType == "pull_request" {
+		statusopts.Text != "" && event.EventType == "pull_request" && !throttled {
 		onPr := ""
-		if statusopts.OriginalPipelineRunName != "" {
-			onPr = "/" + statusopts.OriginalPipelineRunName
+		if title := provider.PipelineRunTitle(statusopts); title != "" {
+			onPr = "/" + title
 		}
 		_, err = v.Client.Repositories.PullRequests.AddComment(
 			&bitbucket.PullRequestCommentOptions{
 				Owner:         event.Organization,
 				RepoSlug:      event.Repository,
 				PullRequestID: strconv.Itoa(event.PullRequestNumber),
-				Content:       fmt.Sprintf("**%s%s** - %s\n\n%s", pacopts.ApplicationName, onPr, statusopts.Title, statusopts.Text),
+				Content:       fmt.Sprintf("**%s%s** - %s\n\n%s", provider.ApplicationTitle(pacopts, false), onPr, statusopts.Title, statusopts.Text),
 			})
 		if err != nil {
 			return err
@@ -143,7 +146,7 @@ func (v *Provider) GetFileInsideRepo(_ context.Context, runevent *info.Event, pa
 	return v.getBlob(runevent, runevent.SHA, path)
 }
 
-func (v *Provider) SetClient(_ context.Context, run *params.Run, event *info.Event) error {
+func (v *Provider) SetClient(ctx context.Context, run *params.Run, event *info.Event) error {
 	if event.Provider.Token == "" {
 		return fmt.Errorf("no git_provider.secret has been set in the repo crd")
 	}
@@ -151,6 +154,10 @@ func (v *Provider) SetClient(_ context.Context, run *params.Run, event *info.Eve
 		return fmt.Errorf("no git_provider.user has been in repo crd")
 	}
 	v.Client = bitbucket.NewBasicAuth(event.Provider.User, event.Provider.Token)
+	ctx = provider.WithAPIClientConfig(ctx, run, v.GetConfig().Name)
+	if httpClient := provider.HTTPClient(ctx); httpClient != nil {
+		v.Client.HttpClient = httpClient
+	}
 	v.Token = &event.Provider.Token
 	v.Username = &event.Provider.User
 	return nil
@@ -222,15 +229,13 @@ func (v *Provider) concatAllYamlFiles(objects []bitbucket.RepositoryFile, runeve
 			allTemplates += fmt.Sprintf("\n%s\n", subdirdata)
 		} else if strings.HasSuffix(value.Path, ".yaml") ||
 			strings.HasSuffix(value.Path, ".yml") {
-			data, err := v.getBlob(runevent, runevent.SHA, value.Path)
+			data, err := provider.CachedFileContent(runevent.URL, runevent.SHA, value.Path, func() (string, error) {
+				return v.getBlob(runevent, runevent.SHA, value.Path)
+			})
 			if err != nil {
 				return "", err
 			}
-
-			if allTemplates != "" && !strings.HasPrefix(data, "---") {
-				allTemplates += "---"
-			}
-			allTemplates += "\n" + data + "\n"
+			allTemplates = provider.AppendTektonFile(allTemplates, value.Path, data)
 		}
 	}
 	return allTemplates, nil
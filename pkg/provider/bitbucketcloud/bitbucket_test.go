@@ -10,6 +10,7 @@ import (
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
 	bbcloudtest "github.com/openshift-pipelines/pipelines-as-code/pkg/provider/bitbucketcloud/test"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/bitbucketcloud/types"
+	testbbcloud "github.com/openshift-pipelines/pipelines-as-code/pkg/test/bitbucketcloud"
 	"gotest.tools/v3/assert"
 	rtesting "knative.dev/pkg/reconciler/testing"
 )
@@ -283,3 +284,38 @@ func TestCreateStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestFakeServer(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	fake := testbbcloud.NewFakeServer(t, "workspace", "repo")
+	fake.ServeFiles(t, "sha", map[string]string{
+		".tekton/pr.yaml":        "kind: PipelineRun\nmetadata:\n  name: pr",
+		".tekton/subdir/pr.yaml": "kind: PipelineRun\nmetadata:\n  name: subdir",
+		".tekton/README.md":      "hello",
+	})
+	v := &Provider{Client: fake.Client}
+	event := &info.Event{
+		Organization:      "workspace",
+		Repository:        "repo",
+		SHA:               "sha",
+		EventType:         "pull_request",
+		PullRequestNumber: 1,
+		Provider:          &info.Provider{},
+	}
+
+	got, err := v.GetTektonDir(ctx, event, ".tekton")
+	assert.NilError(t, err)
+	assert.Assert(t, strings.Contains(got, "name: pr"), got)
+	assert.Assert(t, strings.Contains(got, "name: subdir"), got)
+	assert.Assert(t, !strings.Contains(got, "hello"), got)
+
+	pacOpts := &info.PacOpts{Settings: &settings.Settings{ApplicationName: "Pipelines as Code CI"}}
+	err = v.CreateStatus(ctx, nil, event, pacOpts, provider.StatusOpts{Conclusion: "success", Status: "completed", Text: "Happy as a bunny"})
+	assert.NilError(t, err)
+	statuses := fake.Statuses(t)
+	assert.Equal(t, len(statuses), 1)
+	assert.Equal(t, statuses[0].State, "SUCCESSFUL")
+	comments := fake.Comments(t)
+	assert.Equal(t, len(comments), 1)
+	assert.Assert(t, strings.Contains(comments[0], "Happy as a bunny"), comments[0])
+}
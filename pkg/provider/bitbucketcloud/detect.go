@@ -4,12 +4,17 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/bitbucketcloud/types"
 	"go.uber.org/zap"
 )
 
+// pullRequestOpenState is the state of a pull request which can still be
+// tested from a comment.
+const pullRequestOpenState = "OPEN"
+
 func (v *Provider) Detect(req *http.Request, payload string, logger *zap.SugaredLogger) (bool, bool, *zap.SugaredLogger, string, error) {
 	isBitCloud := false
 	reqHeader := req.Header
@@ -41,6 +46,10 @@ func (v *Provider) Detect(req *http.Request, payload string, logger *zap.Sugared
 			return setLoggerAndProceed(true, "", nil)
 		}
 		if provider.Valid(event, []string{"pullrequest:comment_created"}) {
+			if e.PullRequest.State != "" && e.PullRequest.State != pullRequestOpenState {
+				return setLoggerAndProceed(false, fmt.Sprintf("pull request is not open but %s, skipping comment",
+					strings.ToLower(e.PullRequest.State)), nil)
+			}
 			if provider.IsTestRetestComment(e.Comment.Content.Raw) {
 				return setLoggerAndProceed(true, "", nil)
 			}
@@ -50,6 +59,9 @@ func (v *Provider) Detect(req *http.Request, payload string, logger *zap.Sugared
 			if provider.IsCancelComment(e.Comment.Content.Raw) {
 				return setLoggerAndProceed(true, "", nil)
 			}
+			if provider.IsOverrideComment(e.Comment.Content.Raw) {
+				return setLoggerAndProceed(true, "", nil)
+			}
 		}
 		return setLoggerAndProceed(false, fmt.Sprintf("not a valid gitops comment: \"%s\"", event), nil)
 
@@ -115,6 +115,34 @@ func TestProvider_Detect(t *testing.T) {
 			isBC:       true,
 			processReq: true,
 		},
+		{
+			name: "retest comment on an open pull request",
+			event: types.PullRequestEvent{
+				PullRequest: types.PullRequest{State: "OPEN"},
+				Comment: types.Comment{
+					Content: types.Content{
+						Raw: "/retest",
+					},
+				},
+			},
+			eventType:  "pullrequest:comment_created",
+			isBC:       true,
+			processReq: true,
+		},
+		{
+			name: "retest comment on a merged pull request",
+			event: types.PullRequestEvent{
+				PullRequest: types.PullRequest{State: "MERGED"},
+				Comment: types.Comment{
+					Content: types.Content{
+						Raw: "/retest",
+					},
+				},
+			},
+			eventType:  "pullrequest:comment_created",
+			isBC:       true,
+			processReq: false,
+		},
 		{
 			name: "cancel a pr",
 			event: types.PullRequestEvent{
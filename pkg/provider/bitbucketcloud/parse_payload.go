@@ -130,6 +130,7 @@ func (v *Provider) ParsePayload(ctx context.Context, run *params.Run, request *h
 			processedEvent.TriggerTarget = "pull_request"
 			processedEvent.EventType = "pull_request"
 		} else if provider.Valid(event, []string{"pullrequest:comment_created"}) {
+			processedEvent.TriggerComment = e.Comment.Content.Raw
 			switch {
 			case provider.IsTestRetestComment(e.Comment.Content.Raw):
 				processedEvent.TriggerTarget = "pull_request"
@@ -139,6 +140,7 @@ func (v *Provider) ParsePayload(ctx context.Context, run *params.Run, request *h
 					processedEvent.EventType = "retest-comment"
 				}
 				processedEvent.TargetTestPipelineRun = provider.GetPipelineRunFromTestComment(e.Comment.Content.Raw)
+				processedEvent.TriggerCommentArgs = provider.GetArgsFromTestComment(e.Comment.Content.Raw)
 			case provider.IsOkToTestComment(e.Comment.Content.Raw):
 				processedEvent.TriggerTarget = "pull_request"
 				processedEvent.EventType = "ok-to-test-comment"
@@ -147,6 +149,11 @@ func (v *Provider) ParsePayload(ctx context.Context, run *params.Run, request *h
 				processedEvent.EventType = "cancel-comment"
 				processedEvent.CancelPipelineRuns = true
 				processedEvent.TargetCancelPipelineRun = provider.GetPipelineRunFromCancelComment(e.Comment.Content.Raw)
+			case provider.IsOverrideComment(e.Comment.Content.Raw):
+				processedEvent.TriggerTarget = "pull_request"
+				processedEvent.EventType = "override-comment"
+				processedEvent.OverridePipelineRuns = true
+				processedEvent.TargetOverridePipelineRun, processedEvent.OverrideReason = provider.GetOverrideFromComment(e.Comment.Content.Raw)
 			}
 		}
 		processedEvent.Organization = e.Repository.Workspace.Slug
@@ -157,6 +164,12 @@ func (v *Provider) ParsePayload(ctx context.Context, run *params.Run, request *h
 		processedEvent.HeadBranch = e.PullRequest.Source.Branch.Name
 		processedEvent.AccountID = e.PullRequest.Author.AccountID
 		processedEvent.Sender = e.PullRequest.Author.Nickname
+		// on comments the permissions are checked against the commenter and
+		// not the author of the pull request
+		if event == "pullrequest:comment_created" {
+			processedEvent.AccountID = e.Comment.User.AccountID
+			processedEvent.Sender = e.Comment.User.Nickname
+		}
 		processedEvent.PullRequestNumber = e.PullRequest.ID
 		processedEvent.PullRequestTitle = e.PullRequest.Title
 	case *types.PushRequestEvent:
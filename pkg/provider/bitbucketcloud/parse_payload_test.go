@@ -9,6 +9,7 @@ import (
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
 	bbcloudtest "github.com/openshift-pipelines/pipelines-as-code/pkg/provider/bitbucketcloud/test"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/bitbucketcloud/types"
 	httptesthelper "github.com/openshift-pipelines/pipelines-as-code/pkg/test/http"
 	"gotest.tools/v3/assert"
 	rtesting "knative.dev/pkg/reconciler/testing"
@@ -159,6 +160,18 @@ func TestParsePayload(t *testing.T) {
 			expectedSHA:       "sha",
 			targetPipelinerun: "dummy",
 		},
+		{
+			name: "retest comment from another user than the author",
+			payloadEvent: func() types.PullRequestEvent {
+				pr := bbcloudtest.MakePREvent("account", "sender", "sha", "/retest")
+				pr.Comment.User = types.User{AccountID: "commenter", Nickname: "commenternick"}
+				return pr
+			}(),
+			eventType:         "pullrequest:comment_created",
+			expectedAccountID: "commenter",
+			expectedSender:    "commenternick",
+			expectedSHA:       "sha",
+		},
 		{
 			name:              "ok-to-test comment",
 			payloadEvent:      bbcloudtest.MakePREvent("account", "sender", "sha", "/ok-to-test"),
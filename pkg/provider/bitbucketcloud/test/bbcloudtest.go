@@ -266,7 +266,10 @@ func MakePREvent(accountid, nickname, sha, comment string) types.PullRequestEven
 			Content: types.Content{
 				Raw: comment,
 			},
-			User: types.User{},
+			User: types.User{
+				AccountID: accountid,
+				Nickname:  nickname,
+			},
 		}
 	}
 	return pr
@@ -51,6 +51,7 @@ type PullRequest struct {
 	ID          int         `json:"id"`
 	Links       Links
 	Title       string `json:"title"`
+	State       string `json:"state"`
 }
 
 type PullRequestEvent struct {
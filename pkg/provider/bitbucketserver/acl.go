@@ -23,6 +23,12 @@ func (v *Provider) IsAllowed(ctx context.Context, event *info.Event) (bool, erro
 		return true, nil
 	}
 
+	// only the members can override failed PipelineRuns, not the users
+	// allowed to run the CI with /ok-to-test
+	if event.OverridePipelineRuns {
+		return false, nil
+	}
+
 	// Check then from comment if there is a approved user that has done a /ok-to-test
 	return v.checkOkToTestCommentFromApprovedMember(event)
 }
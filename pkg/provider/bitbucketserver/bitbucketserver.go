@@ -53,6 +53,10 @@ func sanitizeTitle(s string) string {
 }
 
 func (v *Provider) CreateStatus(ctx context.Context, _ versioned.Interface, event *info.Event, pacOpts *info.PacOpts, statusOpts provider.StatusOpts) error {
+	if v.Client == nil {
+		return fmt.Errorf("no token has been set, cannot set status")
+	}
+	statusOpts = provider.BrandStatus(pacOpts, statusOpts)
 	detailsURL := event.Provider.URL
 	switch statusOpts.Conclusion {
 	case "skipped":
@@ -73,13 +77,13 @@ func (v *Provider) CreateStatus(ctx context.Context, _ versioned.Interface, even
 	case "completed":
 		statusOpts.Conclusion = "SUCCESSFUL"
 		statusOpts.Title = "Completed"
+	case provider.ConclusionOverridden:
+		statusOpts.Conclusion = "SUCCESSFUL"
+		statusOpts.Title = "⏭️ Failure has been overridden"
 	}
 	if statusOpts.DetailsURL != "" {
 		detailsURL = statusOpts.DetailsURL
 	}
-	if v.Client == nil {
-		return fmt.Errorf("no token has been set, cannot set status")
-	}
 
 	key := statusOpts.PipelineRunName
 	if key == "" {
@@ -101,15 +105,16 @@ func (v *Provider) CreateStatus(ctx context.Context, _ versioned.Interface, even
 	}
 
 	onPr := ""
-	if statusOpts.OriginalPipelineRunName != "" {
-		onPr = "/" + statusOpts.OriginalPipelineRunName
+	if title := provider.PipelineRunTitle(statusOpts); title != "" {
+		onPr = "/" + title
 	}
 	bbcomment := bbv1.Comment{
-		Text: fmt.Sprintf("**%s%s** - %s\n\n%s", pacOpts.ApplicationName, onPr,
+		Text: fmt.Sprintf("**%s%s** - %s\n\n%s", provider.ApplicationTitle(pacOpts, false), onPr,
 			statusOpts.Title, statusOpts.Text),
 	}
 
-	if statusOpts.Conclusion == "SUCCESSFUL" && statusOpts.Status == "completed" &&
+	// the throttled comment strategy never comments a success
+	if statusOpts.Conclusion == "SUCCESSFUL" && statusOpts.Status == "completed" && !provider.IsCommentThrottled(event) &&
 		statusOpts.Text != "" && event.EventType == "pull_request" && v.pullRequestNumber > 0 {
 		_, err := v.Client.DefaultApi.CreatePullRequestComment(
 			v.projectKey, event.Repository, v.pullRequestNumber,
@@ -128,15 +133,13 @@ func (v *Provider) concatAllYamlFiles(objects []string, runevent *info.Event) (s
 	for _, value := range objects {
 		if strings.HasSuffix(value, ".yaml") ||
 			strings.HasSuffix(value, ".yml") {
-			data, err := v.getRaw(runevent, runevent.SHA, value)
+			data, err := provider.CachedFileContent(runevent.URL, runevent.SHA, value, func() (string, error) {
+				return v.getRaw(runevent, runevent.SHA, value)
+			})
 			if err != nil {
 				return "", err
 			}
-
-			if allTemplates != "" && !strings.HasPrefix(data, "---") {
-				allTemplates += "---"
-			}
-			allTemplates += "\n" + data + "\n"
+			allTemplates = provider.AppendTektonFile(allTemplates, value, data)
 		}
 	}
 	return allTemplates, nil
@@ -212,6 +215,10 @@ func (v *Provider) SetClient(ctx context.Context, run *params.Run, event *info.E
 
 	ctx = context.WithValue(ctx, bbv1.ContextBasicAuth, basicAuth)
 	cfg := bbv1.NewConfiguration(event.Provider.URL)
+	ctx = provider.WithAPIClientConfig(ctx, run, v.GetConfig().Name)
+	if httpClient := provider.HTTPClient(ctx); httpClient != nil {
+		cfg.HTTPClient = httpClient
+	}
 	v.Client = bbv1.NewAPIClient(ctx, cfg)
 
 	return nil
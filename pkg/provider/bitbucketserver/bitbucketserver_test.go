@@ -20,6 +20,7 @@ import (
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
 	bbtest "github.com/openshift-pipelines/pipelines-as-code/pkg/provider/bitbucketserver/test"
+	testbbserver "github.com/openshift-pipelines/pipelines-as-code/pkg/test/bitbucketserver"
 	"gotest.tools/v3/assert"
 	rtesting "knative.dev/pkg/reconciler/testing"
 )
@@ -401,3 +402,37 @@ func TestValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestFakeServer(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	fake := testbbserver.NewFakeServer(ctx, t, "project", "repo")
+	fake.ServeFiles(t, map[string]string{
+		".tekton/pr.yaml":        "kind: PipelineRun\nmetadata:\n  name: pr",
+		".tekton/subdir/pr.yaml": "kind: PipelineRun\nmetadata:\n  name: subdir",
+		".tekton/README.md":      "hello",
+	})
+	v := &Provider{Client: fake.Client, projectKey: "project", pullRequestNumber: 1}
+	event := &info.Event{
+		Organization: "project",
+		Repository:   "repo",
+		SHA:          "sha",
+		EventType:    "pull_request",
+		Provider:     &info.Provider{},
+	}
+
+	got, err := v.GetTektonDir(ctx, event, ".tekton")
+	assert.NilError(t, err)
+	assert.Assert(t, strings.Contains(got, "name: pr"), got)
+	assert.Assert(t, strings.Contains(got, "name: subdir"), got)
+	assert.Assert(t, !strings.Contains(got, "hello"), got)
+
+	pacOpts := &info.PacOpts{Settings: &settings.Settings{ApplicationName: "Pipelines as Code CI"}}
+	err = v.CreateStatus(ctx, nil, event, pacOpts, provider.StatusOpts{Conclusion: "success", Status: "completed", Text: "Happy as a bunny"})
+	assert.NilError(t, err)
+	statuses := fake.Statuses(t)
+	assert.Equal(t, len(statuses), 1)
+	assert.Equal(t, statuses[0].State, "SUCCESSFUL")
+	comments := fake.Comments(t)
+	assert.Equal(t, len(comments), 1)
+	assert.Assert(t, strings.Contains(comments[0], "Happy as a bunny"), comments[0])
+}
@@ -51,6 +51,9 @@ func (v *Provider) Detect(req *http.Request, payload string, logger *zap.Sugared
 			if provider.IsCancelComment(e.Comment.Text) {
 				return setLoggerAndProceed(true, "", nil)
 			}
+			if provider.IsOverrideComment(e.Comment.Text) {
+				return setLoggerAndProceed(true, "", nil)
+			}
 		}
 		return setLoggerAndProceed(false, fmt.Sprintf("not a recognized bitbucket event: \"%s\"", event), nil)
 
@@ -51,6 +51,7 @@ func (v *Provider) ParsePayload(_ context.Context, _ *params.Run, request *http.
 			processedEvent.TriggerTarget = "pull_request"
 			processedEvent.EventType = "pull_request"
 		} else if provider.Valid(eventType, []string{"pr:comment:added", "pr:comment:edited"}) {
+			processedEvent.TriggerComment = e.Comment.Text
 			switch {
 			case provider.IsTestRetestComment(e.Comment.Text):
 				processedEvent.TriggerTarget = "pull_request"
@@ -60,6 +61,7 @@ func (v *Provider) ParsePayload(_ context.Context, _ *params.Run, request *http.
 					processedEvent.EventType = "retest-comment"
 				}
 				processedEvent.TargetTestPipelineRun = provider.GetPipelineRunFromTestComment(e.Comment.Text)
+				processedEvent.TriggerCommentArgs = provider.GetArgsFromTestComment(e.Comment.Text)
 			case provider.IsOkToTestComment(e.Comment.Text):
 				processedEvent.TriggerTarget = "pull_request"
 				processedEvent.EventType = "ok-to-test-comment"
@@ -68,6 +70,11 @@ func (v *Provider) ParsePayload(_ context.Context, _ *params.Run, request *http.
 				processedEvent.EventType = "cancel-comment"
 				processedEvent.CancelPipelineRuns = true
 				processedEvent.TargetCancelPipelineRun = provider.GetPipelineRunFromCancelComment(e.Comment.Text)
+			case provider.IsOverrideComment(e.Comment.Text):
+				processedEvent.TriggerTarget = "pull_request"
+				processedEvent.EventType = "override-comment"
+				processedEvent.OverridePipelineRuns = true
+				processedEvent.TargetOverridePipelineRun, processedEvent.OverrideReason = provider.GetOverrideFromComment(e.Comment.Text)
 			}
 		}
 		// TODO: It's Really not an OWNER but a PROJECT
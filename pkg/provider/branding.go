@@ -0,0 +1,54 @@
+package provider
+
+import (
+	"fmt"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+)
+
+// ApplicationTitle returns the application name as shown in the summaries
+// and comments, linked to the application details URL and prefixed by the
+// application avatar when they are set in the settings. The avatar is an
+// HTML image, only pass withAvatar for the providers rendering HTML in their
+// markdown.
+func ApplicationTitle(pacOpts *info.PacOpts, withAvatar bool) string {
+	title := pacOpts.ApplicationName
+	if pacOpts.ApplicationDetailsURL != "" {
+		title = fmt.Sprintf("[%s](%s)", title, pacOpts.ApplicationDetailsURL)
+	}
+	if withAvatar && pacOpts.ApplicationAvatarURL != "" {
+		title = fmt.Sprintf(`<img src="%s" alt="" width="16" height="16"> %s`, pacOpts.ApplicationAvatarURL, title)
+	}
+	return title
+}
+
+// PipelineRunTitle returns how the PipelineRun of a status is named in the
+// summaries and comments: its display name when it has one or else its name
+// in the .tekton directory. The grouped status isn't about a PipelineRun and
+// has no title.
+func PipelineRunTitle(statusOpts StatusOpts) string {
+	if statusOpts.OriginalPipelineRunName == "" {
+		return ""
+	}
+	if statusOpts.PipelineRun != nil {
+		if name := formatting.PipelineRunDisplayName(statusOpts.PipelineRun); name != "" {
+			return name
+		}
+	}
+	return statusOpts.OriginalPipelineRunName
+}
+
+// BrandStatus applies the application settings to a status: the application
+// details URL is used when the status doesn't link to anything and the
+// application footer is appended to its text. An empty text stays empty since
+// the providers only comment on the statuses having a text.
+func BrandStatus(pacOpts *info.PacOpts, statusOpts StatusOpts) StatusOpts {
+	if statusOpts.DetailsURL == "" {
+		statusOpts.DetailsURL = pacOpts.ApplicationDetailsURL
+	}
+	if statusOpts.Text != "" && pacOpts.ApplicationFooter != "" {
+		statusOpts.Text = fmt.Sprintf("%s\n\n%s", statusOpts.Text, pacOpts.ApplicationFooter)
+	}
+	return statusOpts
+}
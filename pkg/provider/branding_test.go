@@ -0,0 +1,76 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestApplicationTitle(t *testing.T) {
+	tests := []struct {
+		name       string
+		settings   settings.Settings
+		withAvatar bool
+		want       string
+	}{
+		{
+			name:     "default",
+			settings: settings.Settings{ApplicationName: "ACME CI"},
+			want:     "ACME CI",
+		},
+		{
+			name:     "linked to the details url",
+			settings: settings.Settings{ApplicationName: "ACME CI", ApplicationDetailsURL: "https://ci.acme.com"},
+			want:     "[ACME CI](https://ci.acme.com)",
+		},
+		{
+			name:       "with avatar",
+			settings:   settings.Settings{ApplicationName: "ACME CI", ApplicationAvatarURL: "https://ci.acme.com/logo.png"},
+			withAvatar: true,
+			want:       `<img src="https://ci.acme.com/logo.png" alt="" width="16" height="16"> ACME CI`,
+		},
+		{
+			name:     "avatar not rendered",
+			settings: settings.Settings{ApplicationName: "ACME CI", ApplicationAvatarURL: "https://ci.acme.com/logo.png"},
+			want:     "ACME CI",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, ApplicationTitle(&info.PacOpts{Settings: &tt.settings}, tt.withAvatar), tt.want)
+		})
+	}
+}
+
+func TestBrandStatus(t *testing.T) {
+	pacOpts := &info.PacOpts{Settings: &settings.Settings{
+		ApplicationDetailsURL: "https://ci.acme.com",
+		ApplicationFooter:     "Reported by ACME CI",
+	}}
+
+	got := BrandStatus(pacOpts, StatusOpts{Text: "all good"})
+	assert.Equal(t, got.DetailsURL, "https://ci.acme.com")
+	assert.Equal(t, got.Text, "all good\n\nReported by ACME CI")
+
+	got = BrandStatus(pacOpts, StatusOpts{DetailsURL: "https://console/pr"})
+	assert.Equal(t, got.DetailsURL, "https://console/pr")
+	assert.Equal(t, got.Text, "")
+}
+
+func TestPipelineRunTitle(t *testing.T) {
+	pr := &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{
+		Name:        "pr-abcde",
+		Annotations: map[string]string{keys.DisplayName: " Unit tests "},
+	}}
+
+	assert.Equal(t, PipelineRunTitle(StatusOpts{OriginalPipelineRunName: "pr", PipelineRun: pr}), "Unit tests")
+	assert.Equal(t, PipelineRunTitle(StatusOpts{OriginalPipelineRunName: "pr", PipelineRun: &tektonv1.PipelineRun{}}), "pr")
+	assert.Equal(t, PipelineRunTitle(StatusOpts{OriginalPipelineRunName: "pr"}), "pr")
+	// the grouped status has no PipelineRun of its own
+	assert.Equal(t, PipelineRunTitle(StatusOpts{PipelineRunName: GroupedStatusName, PipelineRun: pr}), "")
+}
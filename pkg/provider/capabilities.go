@@ -0,0 +1,135 @@
+package provider
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+)
+
+// Capability is a feature of Pipelines as Code which depends on the git
+// provider.
+type Capability string
+
+const (
+	// CapabilityChecks reports the PipelineRuns as check runs with the
+	// details of their tasks instead of commit statuses.
+	CapabilityChecks Capability = "checks"
+	// CapabilityComments reports the PipelineRuns as comments on the pull
+	// requests.
+	CapabilityComments Capability = "comments"
+	// CapabilityPathFiltering matches the PipelineRuns on the files changed
+	// by the event with the pathChanged CEL function.
+	CapabilityPathFiltering Capability = "path-filtering"
+	// CapabilityCommentCommands runs the GitOps commands commented on the
+	// pull requests, ie: /retest or /ok-to-test.
+	CapabilityCommentCommands Capability = "comment-commands"
+)
+
+// AllCapabilities are the capabilities in the order they are reported.
+var AllCapabilities = []Capability{CapabilityChecks, CapabilityComments, CapabilityPathFiltering, CapabilityCommentCommands}
+
+// ProviderTypes are the types of the git providers in the order they are
+// reported, a GitHub App is reported apart from a GitHub webhook.
+var ProviderTypes = []string{"github-app", "github", "gitlab", "gitea", "bitbucket-cloud", "bitbucket-server"}
+
+var providerCapabilities = map[string][]Capability{
+	"github-app":       {CapabilityChecks, CapabilityPathFiltering, CapabilityCommentCommands},
+	"github":           {CapabilityComments, CapabilityPathFiltering, CapabilityCommentCommands},
+	"gitlab":           {CapabilityComments, CapabilityPathFiltering, CapabilityCommentCommands},
+	"gitea":            {CapabilityComments, CapabilityPathFiltering, CapabilityCommentCommands},
+	"bitbucket-cloud":  {CapabilityComments, CapabilityCommentCommands},
+	"bitbucket-server": {CapabilityComments, CapabilityCommentCommands},
+}
+
+// NormalizeProviderType returns the provider type of the capabilities for a
+// git_provider type of a Repository or the name of a provider, GitHub
+// Enterprise is GitHub and the older bitbucket types are mapped to Bitbucket
+// Cloud and Server.
+func NormalizeProviderType(providerType string) string {
+	switch providerType {
+	case "github-enterprise":
+		return "github"
+	case "bitbucket":
+		return "bitbucket-cloud"
+	case "bitbucket-enteprise", "bitbucket-enterprise":
+		return "bitbucket-server"
+	}
+	return providerType
+}
+
+// EventProviderType returns the provider type of the capabilities for the
+// provider of an event, the events with an installation ID come from a GitHub
+// App.
+func EventProviderType(p Interface, event *info.Event) string {
+	providerType := NormalizeProviderType(p.GetConfig().Name)
+	if providerType == "github" && event.InstallationID > 0 {
+		return "github-app"
+	}
+	return providerType
+}
+
+// RepositoryProviderType returns the type of the git provider of the
+// Repository, guessed from the URL when the git_provider type is not set. A
+// Repository without git_provider is served by the GitHub App.
+func RepositoryProviderType(repo *v1alpha1.Repository) string {
+	if repo.Spec.GitProvider == nil {
+		return "github-app"
+	}
+	if repo.Spec.GitProvider.Type != "" {
+		return NormalizeProviderType(repo.Spec.GitProvider.Type)
+	}
+	u, err := url.Parse(repo.Spec.URL)
+	if err != nil {
+		return ""
+	}
+	switch {
+	case strings.Contains(u.Host, "github"):
+		return "github"
+	case strings.Contains(u.Host, "gitlab"):
+		return "gitlab"
+	case u.Host == "bitbucket.org":
+		return "bitbucket-cloud"
+	}
+	return ""
+}
+
+// Capabilities returns the capabilities supported by the provider type or nil
+// if the provider type is unknown.
+func Capabilities(providerType string) []Capability {
+	return providerCapabilities[NormalizeProviderType(providerType)]
+}
+
+// HasCapability returns true if the provider type supports the capability,
+// the unknown provider types are assumed to support it.
+func HasCapability(providerType string, capability Capability) bool {
+	capabilities, ok := providerCapabilities[NormalizeProviderType(providerType)]
+	if !ok {
+		return true
+	}
+	for _, c := range capabilities {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
+
+// CapabilitiesSummary describes the capabilities the provider type supports
+// and the ones it doesn't.
+func CapabilitiesSummary(providerType string) string {
+	supported, unsupported := []string{}, []string{}
+	for _, c := range AllCapabilities {
+		if HasCapability(providerType, c) {
+			supported = append(supported, string(c))
+		} else {
+			unsupported = append(unsupported, string(c))
+		}
+	}
+	summary := NormalizeProviderType(providerType) + " supports: " + strings.Join(supported, ", ")
+	if len(unsupported) > 0 {
+		summary += "; unsupported: " + strings.Join(unsupported, ", ")
+	}
+	return summary
+}
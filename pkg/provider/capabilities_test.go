@@ -0,0 +1,40 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"gotest.tools/v3/assert"
+)
+
+func TestRepositoryProviderType(t *testing.T) {
+	tests := []struct {
+		name        string
+		url         string
+		gitProvider *v1alpha1.GitProvider
+		want        string
+	}{
+		{name: "github app", url: "https://github.com/owner/repo", want: "github-app"},
+		{name: "github webhook", url: "https://github.com/owner/repo", gitProvider: &v1alpha1.GitProvider{}, want: "github"},
+		{name: "gitlab from url", url: "https://gitlab.example.com/group/repo", gitProvider: &v1alpha1.GitProvider{}, want: "gitlab"},
+		{name: "bitbucket cloud from url", url: "https://bitbucket.org/owner/repo", gitProvider: &v1alpha1.GitProvider{}, want: "bitbucket-cloud"},
+		{name: "older bitbucket type", url: "https://git.example.com/owner/repo", gitProvider: &v1alpha1.GitProvider{Type: "bitbucket-enteprise"}, want: "bitbucket-server"},
+		{name: "unknown", url: "https://git.example.com/owner/repo", gitProvider: &v1alpha1.GitProvider{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &v1alpha1.Repository{Spec: v1alpha1.RepositorySpec{URL: tt.url, GitProvider: tt.gitProvider}}
+			assert.Equal(t, RepositoryProviderType(repo), tt.want)
+		})
+	}
+}
+
+func TestCapabilities(t *testing.T) {
+	assert.Assert(t, HasCapability("github-app", CapabilityChecks))
+	assert.Assert(t, !HasCapability("github-enterprise", CapabilityChecks))
+	assert.Assert(t, !HasCapability("bitbucket-server", CapabilityPathFiltering))
+	// the unknown providers are not reported as lacking a capability
+	assert.Assert(t, HasCapability("unknown", CapabilityPathFiltering))
+	assert.Assert(t, Capabilities("unknown") == nil)
+	assert.Equal(t, CapabilitiesSummary("gitea"), "gitea supports: comments, path-filtering, comment-commands; unsupported: checks")
+}
@@ -0,0 +1,38 @@
+package provider
+
+import "strings"
+
+// ChangedFiles collects the paths of the files changed by an event without
+// duplicates, keeping the order they were first seen in.
+type ChangedFiles struct {
+	seen  map[string]bool
+	paths []string
+}
+
+// Add adds the paths to the changed files, empty paths are ignored.
+func (c *ChangedFiles) Add(paths ...string) {
+	if c.seen == nil {
+		c.seen = map[string]bool{}
+	}
+	for _, path := range paths {
+		if path == "" || c.seen[path] {
+			continue
+		}
+		c.seen[path] = true
+		c.paths = append(c.paths, path)
+	}
+}
+
+// Paths returns the changed files.
+func (c *ChangedFiles) Paths() []string {
+	if c.paths == nil {
+		return []string{}
+	}
+	return c.paths
+}
+
+// IsZeroSHA returns true if the SHA is empty or only made of zeros, the SHA
+// a push event reports as the previous one when a branch is created.
+func IsZeroSHA(sha string) bool {
+	return strings.Trim(sha, "0") == ""
+}
@@ -0,0 +1,43 @@
+package provider
+
+import (
+	"fmt"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+)
+
+// commentMarker identifies the comment of a PipelineRun on a pull request
+// with the throttled comment strategy.
+const commentMarker = "<!-- pipelines-as-code-comment: %s/%s -->"
+
+// IsCommentThrottled returns true if the Repository of the event comments at
+// most once per PipelineRun and never comments a success.
+func IsCommentThrottled(event *info.Event) bool {
+	return event.Provider != nil && event.Provider.CommentStrategy == v1alpha1.CommentStrategyThrottled
+}
+
+// CommentMarker returns the hidden marker added to the comment of the
+// PipelineRun of the status to find it again and edit it.
+func CommentMarker(pacOpts *info.PacOpts, statusOpts StatusOpts) string {
+	name := statusOpts.OriginalPipelineRunName
+	if name == "" {
+		name = statusOpts.PipelineRunName
+	}
+	return fmt.Sprintf(commentMarker, pacOpts.ApplicationName, name)
+}
+
+// NewCommentAllowed returns true if a new comment can be created for the
+// status with the throttled comment strategy: only the PipelineRuns which
+// are done and didn't succeed are commented, the existing comments are
+// edited with any status.
+func NewCommentAllowed(statusOpts StatusOpts) bool {
+	if statusOpts.Status != "completed" {
+		return false
+	}
+	switch statusOpts.Conclusion {
+	case "success", "completed", ConclusionOverridden:
+		return false
+	}
+	return true
+}
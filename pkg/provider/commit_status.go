@@ -0,0 +1,17 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+)
+
+// CommitStatusSetter is implemented by the providers able to set a plain
+// commit status, without the check runs and the comments of CreateStatus,
+// used to report the statuses of the PipelineRuns to a mirror of the
+// repository.
+type CommitStatusSetter interface {
+	// SetCommitStatus sets the status on the commit of the event with the
+	// title of the status as its description.
+	SetCommitStatus(ctx context.Context, event *info.Event, pacOpts *info.PacOpts, status StatusOpts) error
+}
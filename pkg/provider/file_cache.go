@@ -0,0 +1,93 @@
+package provider
+
+import (
+	"container/list"
+	"regexp"
+	"sync"
+)
+
+// fileCacheMaxBytes is the maximum size of the file contents kept in the
+// cache, the least recently used files are forgotten past it.
+const fileCacheMaxBytes = 32 * 1024 * 1024
+
+// reImmutableRevision matches the full SHA of a git object, a commit or a
+// blob, the content at such a revision never changes unlike a branch.
+var reImmutableRevision = regexp.MustCompile(`^([0-9a-f]{40}|[0-9a-f]{64})$`)
+
+// fileContents caches the content of the files of the .tekton directories,
+// it is shared by all the provider clients of the process since a client is
+// created for every event, a retest or another event on the same commit then
+// reuse the files already fetched.
+var fileContents = newFileCache(fileCacheMaxBytes)
+
+type fileCacheEntry struct {
+	key     string
+	content string
+}
+
+// fileCache is a least recently used cache of file contents bounded by their
+// total size.
+type fileCache struct {
+	mutex    sync.Mutex
+	maxBytes int
+	size     int
+	entries  map[string]*list.Element
+	order    *list.List
+}
+
+func newFileCache(maxBytes int) *fileCache {
+	return &fileCache{maxBytes: maxBytes, entries: map[string]*list.Element{}, order: list.New()}
+}
+
+func (c *fileCache) get(key string) (string, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return "", false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*fileCacheEntry).content, true
+}
+
+func (c *fileCache) add(key, content string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if len(content) > c.maxBytes {
+		return
+	}
+	if elem, ok := c.entries[key]; ok {
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&fileCacheEntry{key: key, content: content})
+	c.size += len(content)
+	for c.size > c.maxBytes {
+		oldest := c.order.Back()
+		entry := oldest.Value.(*fileCacheEntry)
+		c.order.Remove(oldest)
+		delete(c.entries, entry.key)
+		c.size -= len(entry.content)
+	}
+}
+
+// CachedFileContent returns the content of the file of the repository at the
+// revision, calling fetch to get it from the provider when it isn't in the
+// cache. The revision is either the SHA of the commit and the path the one of
+// the file, or the SHA of the blob of the file and the path empty. Only the
+// full SHAs are cached, the content of a branch can change.
+func CachedFileContent(repoURL, revision, path string, fetch func() (string, error)) (string, error) {
+	if !reImmutableRevision.MatchString(revision) {
+		return fetch()
+	}
+	key := repoURL + "@" + revision + ":" + path
+	if content, ok := fileContents.get(key); ok {
+		return content, nil
+	}
+	content, err := fetch()
+	if err != nil {
+		return "", err
+	}
+	fileContents.add(key, content)
+	return content, nil
+}
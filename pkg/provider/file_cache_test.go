@@ -0,0 +1,99 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestFileCacheEviction(t *testing.T) {
+	cache := newFileCache(10)
+	cache.add("a", "aaaa")
+	cache.add("b", "bbbb")
+	_, ok := cache.get("a")
+	assert.Assert(t, ok)
+	// b is the least recently used
+	cache.add("c", "cccc")
+	_, ok = cache.get("b")
+	assert.Assert(t, !ok)
+	content, ok := cache.get("a")
+	assert.Assert(t, ok)
+	assert.Equal(t, content, "aaaa")
+	_, ok = cache.get("c")
+	assert.Assert(t, ok)
+	assert.Equal(t, cache.size, 8)
+
+	// too big to be cached at all
+	cache.add("d", strings.Repeat("d", 11))
+	_, ok = cache.get("d")
+	assert.Assert(t, !ok)
+	assert.Equal(t, cache.size, 8)
+}
+
+func TestCachedFileContent(t *testing.T) {
+	sha := strings.Repeat("a1", 20)
+	tests := []struct {
+		name       string
+		revision   string
+		path       string
+		fetchErr   error
+		wantErr    string
+		wantCalled int
+	}{
+		{
+			name:       "commit sha and path",
+			revision:   sha,
+			path:       ".tekton/pr.yaml",
+			wantCalled: 1,
+		},
+		{
+			name:       "blob sha256",
+			revision:   strings.Repeat("b2", 32),
+			wantCalled: 1,
+		},
+		{
+			name:       "branch is not cached",
+			revision:   "main",
+			path:       ".tekton/pr.yaml",
+			wantCalled: 3,
+		},
+		{
+			name:       "short sha is not cached",
+			revision:   sha[:7],
+			path:       ".tekton/pr.yaml",
+			wantCalled: 3,
+		},
+		{
+			name:       "errors are not cached",
+			revision:   sha,
+			fetchErr:   fmt.Errorf("not found"),
+			wantErr:    "not found",
+			wantCalled: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := 0
+			fetch := func() (string, error) {
+				called++
+				if tt.fetchErr != nil {
+					return "", tt.fetchErr
+				}
+				return "content", nil
+			}
+			repoURL := "https://forge/owner/" + strings.ReplaceAll(tt.name, " ", "-")
+			for i := 0; i < 3; i++ {
+				content, err := CachedFileContent(repoURL, tt.revision, tt.path, fetch)
+				if tt.wantErr != "" {
+					assert.ErrorContains(t, err, tt.wantErr)
+					continue
+				}
+				assert.NilError(t, err)
+				assert.Equal(t, content, "content")
+			}
+			assert.Equal(t, called, tt.wantCalled)
+		})
+	}
+}
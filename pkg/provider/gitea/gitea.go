@@ -75,17 +75,22 @@ func (v *Provider) GetConfig() *info.ProviderConfig {
 	}
 }
 
-func (v *Provider) SetClient(_ context.Context, run *params.Run, runevent *info.Event) error {
+func (v *Provider) SetClient(ctx context.Context, run *params.Run, runevent *info.Event) error {
 	var err error
 	apiURL := runevent.Provider.URL
+	opts := []gitea.ClientOption{}
+	ctx = provider.WithAPIClientConfig(ctx, run, v.GetConfig().Name)
+	if httpClient := provider.HTTPClient(ctx); httpClient != nil {
+		opts = append(opts, gitea.SetHTTPClient(httpClient))
+	}
 	// password is not exposed to CRD, it's only used from the e2e tests
 	if v.Password != "" && runevent.Provider.User != "" {
-		v.Client, err = gitea.NewClient(apiURL, gitea.SetBasicAuth(runevent.Provider.User, v.Password))
+		v.Client, err = gitea.NewClient(apiURL, append(opts, gitea.SetBasicAuth(runevent.Provider.User, v.Password))...)
 	} else {
 		if runevent.Provider.Token == "" {
 			return fmt.Errorf("no git_provider.secret has been set in the repo crd")
 		}
-		v.Client, err = gitea.NewClient(apiURL, gitea.SetToken(runevent.Provider.Token))
+		v.Client, err = gitea.NewClient(apiURL, append(opts, gitea.SetToken(runevent.Provider.Token))...)
 	}
 	if err != nil {
 		return err
@@ -100,6 +105,7 @@ func (v *Provider) CreateStatus(_ context.Context, _ versioned.Interface, event
 	if v.Client == nil {
 		return fmt.Errorf("cannot set status on gitea no token or url set")
 	}
+	statusOpts = provider.BrandStatus(pacOpts, statusOpts)
 	switch statusOpts.Conclusion {
 	case "success":
 		statusOpts.Title = "Success"
@@ -113,6 +119,9 @@ func (v *Provider) CreateStatus(_ context.Context, _ versioned.Interface, event
 	case "neutral":
 		statusOpts.Title = "Unknown"
 		statusOpts.Summary = "doesn't know what happened with this commit."
+	case provider.ConclusionOverridden:
+		statusOpts.Title = "Overridden"
+		statusOpts.Summary = "has failed but it has been <b>overridden</b>."
 	}
 
 	if statusOpts.Status == "in_progress" {
@@ -121,19 +130,31 @@ func (v *Provider) CreateStatus(_ context.Context, _ versioned.Interface, event
 	}
 
 	onPr := ""
-	if statusOpts.PipelineRunName != "" {
+	if title := provider.PipelineRunTitle(statusOpts); title != "" {
+		onPr = "/" + title
+	} else if statusOpts.PipelineRunName != "" {
 		onPr = fmt.Sprintf("/%s", statusOpts.PipelineRunName)
 	}
 	// gitea show weirdly the <br>
-	statusOpts.Summary = fmt.Sprintf("%s%s %s", pacOpts.ApplicationName, onPr, statusOpts.Summary)
+	statusOpts.Summary = fmt.Sprintf("%s%s %s", provider.ApplicationTitle(pacOpts, true), onPr, statusOpts.Summary)
 
 	return v.createStatusCommit(event, pacOpts, statusOpts)
 }
 
+// SetCommitStatus sets a commit status on the commit of the event, the status
+// is only commented on a pull request event.
+func (v *Provider) SetCommitStatus(_ context.Context, event *info.Event, pacOpts *info.PacOpts, status provider.StatusOpts) error {
+	if v.Client == nil {
+		return fmt.Errorf("no gitea client has been initiliazed, " +
+			"exiting... (hint: did you forget setting a secret on your repo?)")
+	}
+	return v.createStatusCommit(event, pacOpts, status)
+}
+
 func (v *Provider) createStatusCommit(event *info.Event, pacopts *info.PacOpts, status provider.StatusOpts) error {
 	state := gitea.StatusState(status.Conclusion)
 	switch status.Conclusion {
-	case "skipped", "neutral":
+	case "skipped", "neutral", provider.ConclusionOverridden:
 		state = gitea.StatusSuccess // We don't have a choice than setting as success, no pending here.c
 	}
 	if status.Status == "in_progress" {
@@ -150,8 +171,19 @@ func (v *Provider) createStatusCommit(event *info.Event, pacopts *info.PacOpts,
 		return err
 	}
 
+	// only comment once all the PipelineRuns are done when the statuses are
+	// grouped
+	if provider.IsGroupedStatus(status) && status.Status != "completed" {
+		return nil
+	}
+
 	if status.Text != "" && event.EventType == "pull_request" {
 		status.Text = strings.ReplaceAll(strings.TrimSpace(status.Text), "<br>", "\n")
+		if provider.IsCommentThrottled(event) {
+			marker := provider.CommentMarker(pacopts, status)
+			return v.updateThrottledComment(event, fmt.Sprintf("%s\n%s\n\n%s", status.Summary, status.Text, marker), marker,
+				provider.NewCommentAllowed(status))
+		}
 		_, _, err := v.Client.CreateIssueComment(event.Organization, event.Repository,
 			int64(event.PullRequestNumber), gitea.CreateIssueCommentOption{
 				Body: fmt.Sprintf("%s\n%s", status.Summary, status.Text),
@@ -164,6 +196,36 @@ func (v *Provider) createStatusCommit(event *info.Event, pacopts *info.PacOpts,
 	return nil
 }
 
+// updateThrottledComment edits the comment of the PipelineRun on the pull
+// request found with its marker, a new comment is only created when create is
+// true.
+func (v *Provider) updateThrottledComment(event *info.Event, body, marker string, create bool) error {
+	opt := gitea.ListIssueCommentOptions{ListOptions: gitea.ListOptions{Page: 1, PageSize: 50}}
+	for {
+		comments, _, err := v.Client.ListIssueComments(event.Organization, event.Repository, int64(event.PullRequestNumber), opt)
+		if err != nil {
+			return err
+		}
+		for _, comment := range comments {
+			if strings.Contains(comment.Body, marker) {
+				_, _, err := v.Client.EditIssueComment(event.Organization, event.Repository, comment.ID,
+					gitea.EditIssueCommentOption{Body: body})
+				return err
+			}
+		}
+		if len(comments) < opt.PageSize {
+			break
+		}
+		opt.Page++
+	}
+	if !create {
+		return nil
+	}
+	_, _, err := v.Client.CreateIssueComment(event.Organization, event.Repository, int64(event.PullRequestNumber),
+		gitea.CreateIssueCommentOption{Body: body})
+	return err
+}
+
 // TODO: move to common since used in github and here
 func getCheckName(status provider.StatusOpts, pacopts *info.PacOpts) string {
 	if pacopts.ApplicationName != "" {
@@ -175,7 +237,9 @@ func getCheckName(status provider.StatusOpts, pacopts *info.PacOpts) string {
 	return status.OriginalPipelineRunName
 }
 
-func (v *Provider) GetTektonDir(_ context.Context, event *info.Event, path string) (string, error) {
+// tektonDirSHA returns the SHA of the tree of the directory at the root of
+// the repository at the revision of the event, empty if there is none.
+func (v *Provider) tektonDirSHA(event *info.Event, path string) (string, error) {
 	tektonDirSha := ""
 	rootobjects, _, err := v.Client.GetTrees(event.Organization, event.Repository, event.SHA, false)
 	if err != nil {
@@ -189,6 +253,24 @@ func (v *Provider) GetTektonDir(_ context.Context, event *info.Event, path strin
 			tektonDirSha = object.SHA
 		}
 	}
+	return tektonDirSha, nil
+}
+
+// HasTektonDir checks if the directory exists at the revision of the event
+// with the tree of the root of the repository.
+func (v *Provider) HasTektonDir(_ context.Context, event *info.Event, path string) (bool, error) {
+	tektonDirSha, err := v.tektonDirSHA(event, path)
+	if err != nil {
+		return false, err
+	}
+	return tektonDirSha != "", nil
+}
+
+func (v *Provider) GetTektonDir(_ context.Context, event *info.Event, path string) (string, error) {
+	tektonDirSha, err := v.tektonDirSHA(event, path)
+	if err != nil {
+		return "", err
+	}
 
 	// If we didn't find a .tekton directory then just silently ignore the error.
 	if tektonDirSha == "" {
@@ -200,10 +282,10 @@ func (v *Provider) GetTektonDir(_ context.Context, event *info.Event, path strin
 	if err != nil {
 		return "", err
 	}
-	return v.concatAllYamlFiles(tektonDirObjects.Entries, event)
+	return v.concatAllYamlFiles(path, tektonDirObjects.Entries, event)
 }
 
-func (v *Provider) concatAllYamlFiles(objects []gitea.GitEntry, event *info.Event) (string,
+func (v *Provider) concatAllYamlFiles(dir string, objects []gitea.GitEntry, event *info.Event) (string,
 	error,
 ) {
 	var allTemplates string
@@ -211,14 +293,14 @@ func (v *Provider) concatAllYamlFiles(objects []gitea.GitEntry, event *info.Even
 	for _, value := range objects {
 		if strings.HasSuffix(value.Path, ".yaml") ||
 			strings.HasSuffix(value.Path, ".yml") {
-			data, err := v.getObject(value.SHA, event)
+			data, err := provider.CachedFileContent(event.URL, value.SHA, "", func() (string, error) {
+				data, err := v.getObject(value.SHA, event)
+				return string(data), err
+			})
 			if err != nil {
 				return "", err
 			}
-			if allTemplates != "" && !strings.HasPrefix(string(data), "---") {
-				allTemplates += "---"
-			}
-			allTemplates += "\n" + string(data) + "\n"
+			allTemplates = provider.AppendTektonFile(allTemplates, path.Join(dir, value.Path), data)
 		}
 	}
 	return allTemplates, nil
@@ -0,0 +1,112 @@
+package gitea
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	testgitea "github.com/openshift-pipelines/pipelines-as-code/pkg/test/gitea"
+	"gotest.tools/v3/assert"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestCreateStatus(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       provider.StatusOpts
+		eventType    string
+		wantState    string
+		wantComments int
+	}{
+		{
+			name:         "success with comment",
+			status:       provider.StatusOpts{Conclusion: "success", Text: "Happy as a bunny"},
+			eventType:    "pull_request",
+			wantState:    "success",
+			wantComments: 1,
+		},
+		{
+			name:      "success on push",
+			status:    provider.StatusOpts{Conclusion: "success", Text: "Happy as a bunny"},
+			eventType: "push",
+			wantState: "success",
+		},
+		{
+			name:      "in progress",
+			status:    provider.StatusOpts{Status: "in_progress"},
+			eventType: "pull_request",
+			wantState: "pending",
+		},
+		{
+			name:      "neutral",
+			status:    provider.StatusOpts{Conclusion: "neutral"},
+			eventType: "pull_request",
+			wantState: "success",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			fake := testgitea.NewFakeServer(t, "owner", "repo")
+			v := &Provider{Client: fake.Client}
+			event := &info.Event{
+				Organization:      "owner",
+				Repository:        "repo",
+				SHA:               "sha",
+				EventType:         tt.eventType,
+				PullRequestNumber: 1,
+			}
+			pacOpts := &info.PacOpts{Settings: &settings.Settings{ApplicationName: "Pipelines as Code CI"}}
+			assert.NilError(t, v.CreateStatus(ctx, nil, event, pacOpts, tt.status))
+
+			statuses := fake.Statuses(t)
+			assert.Equal(t, len(statuses), 1)
+			assert.Equal(t, string(statuses[0].State), tt.wantState)
+			assert.Equal(t, statuses[0].Context, "Pipelines as Code CI")
+			comments := fake.Comments(t)
+			assert.Equal(t, len(comments), tt.wantComments)
+			if tt.wantComments > 0 {
+				assert.Assert(t, strings.Contains(comments[0], tt.status.Text), comments[0])
+			}
+		})
+	}
+}
+
+func TestGetTektonDir(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	fake := testgitea.NewFakeServer(t, "owner", "repo")
+	fake.ServeFiles(t, "sha", map[string]string{
+		".tekton/pr.yaml":        "kind: PipelineRun\nmetadata:\n  name: pr",
+		".tekton/subdir/pr.yaml": "kind: PipelineRun\nmetadata:\n  name: subdir",
+		".tekton/README.md":      "hello",
+		"main.go":                "package main",
+	})
+	v := &Provider{Client: fake.Client}
+	event := &info.Event{Organization: "owner", Repository: "repo", SHA: "sha"}
+
+	got, err := v.GetTektonDir(ctx, event, ".tekton")
+	assert.NilError(t, err)
+	assert.Assert(t, strings.Contains(got, "name: pr"), got)
+	assert.Assert(t, strings.Contains(got, "name: subdir"), got)
+	assert.Assert(t, !strings.Contains(got, "hello"), got)
+	assert.Assert(t, strings.Contains(got, provider.TektonFileMarker+".tekton/subdir/pr.yaml\n"), got)
+
+	got, err = v.GetTektonDir(ctx, event, "nothere")
+	assert.NilError(t, err)
+	assert.Equal(t, got, "")
+
+	got, err = v.GetFileInsideRepo(ctx, event, "main.go", "")
+	assert.NilError(t, err)
+	assert.Equal(t, got, "package main")
+
+	found, err := v.HasTektonDir(ctx, event, ".tekton")
+	assert.NilError(t, err)
+	assert.Assert(t, found)
+	found, err = v.HasTektonDir(ctx, event, "nothere")
+	assert.NilError(t, err)
+	assert.Assert(t, !found)
+	_, err = v.HasTektonDir(ctx, event, "main.go")
+	assert.ErrorContains(t, err, "main.go has been found but is not a directory")
+}
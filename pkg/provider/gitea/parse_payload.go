@@ -43,6 +43,9 @@ func (v *Provider) ParsePayload(_ context.Context, _ *params.Run, request *http.
 		processedEvent.BaseBranch = gitEvent.PullRequest.Base.Ref
 		processedEvent.PullRequestNumber = int(gitEvent.Index)
 		processedEvent.PullRequestTitle = gitEvent.PullRequest.Title
+		for _, label := range gitEvent.PullRequest.Labels {
+			processedEvent.PullRequestLabel = append(processedEvent.PullRequestLabel, label.Name)
+		}
 		processedEvent.Organization = gitEvent.Repository.Owner.UserName
 		processedEvent.Repository = gitEvent.Repository.Name
 		processedEvent.TriggerTarget = "pull_request"
@@ -78,13 +81,19 @@ func (v *Provider) ParsePayload(_ context.Context, _ *params.Run, request *http.
 		processedEvent.TriggerTarget = "pull_request"
 		processedEvent.EventType = "pull_request"
 
+		processedEvent.TriggerComment = gitEvent.Comment.Body
 		if provider.IsTestRetestComment(gitEvent.Comment.Body) {
 			processedEvent.TargetTestPipelineRun = provider.GetPipelineRunFromTestComment(gitEvent.Comment.Body)
+			processedEvent.TriggerCommentArgs = provider.GetArgsFromTestComment(gitEvent.Comment.Body)
 		}
 		if provider.IsCancelComment(gitEvent.Comment.Body) {
 			processedEvent.CancelPipelineRuns = true
 			processedEvent.TargetCancelPipelineRun = provider.GetPipelineRunFromCancelComment(gitEvent.Comment.Body)
 		}
+		if provider.IsOverrideComment(gitEvent.Comment.Body) {
+			processedEvent.OverridePipelineRuns = true
+			processedEvent.TargetOverridePipelineRun, processedEvent.OverrideReason = provider.GetOverrideFromComment(gitEvent.Comment.Body)
+		}
 		processedEvent.PullRequestNumber, err = convertPullRequestURLtoNumber(gitEvent.Issue.URL)
 		if err != nil {
 			return nil, err
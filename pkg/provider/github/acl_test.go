@@ -105,6 +105,25 @@ func TestOkToTestComment(t *testing.T) {
 			allowed: false,
 			wantErr: false,
 		},
+		{
+			name:          "override is not allowed from an ok-to-test",
+			commentsReply: `[{"body": "/ok-to-test", "user": {"login": "owner"}}]`,
+			runevent: info.Event{
+				Organization: "owner",
+				Sender:       "nonowner",
+				EventType:    "issue_comment",
+				Event: &github.IssueCommentEvent{
+					Issue: &github.Issue{
+						PullRequestLinks: &github.PullRequestLinks{
+							HTMLURL: github.String("http://url.com/owner/repo/1"),
+						},
+					},
+				},
+				State: info.State{OverridePipelineRuns: true},
+			},
+			allowed: false,
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,41 @@
+package github
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/go-github/v49/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+)
+
+// CountApprovals returns the number of users whose latest review of the pull
+// request is an approval.
+func (v *Provider) CountApprovals(ctx context.Context, event *info.Event) (int, error) {
+	latest := map[string]string{}
+	opt := &github.ListOptions{PerPage: 100}
+	for {
+		reviews, resp, err := v.Client.PullRequests.ListReviews(ctx, event.Organization, event.Repository, event.PullRequestNumber, opt)
+		if err != nil {
+			return 0, err
+		}
+		for _, review := range reviews {
+			// comments don't change the approval state of the reviewer
+			if strings.EqualFold(review.GetState(), "commented") {
+				continue
+			}
+			latest[review.GetUser().GetLogin()] = review.GetState()
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	approvals := 0
+	for _, state := range latest {
+		if strings.EqualFold(state, "approved") {
+			approvals++
+		}
+	}
+	return approvals, nil
+}
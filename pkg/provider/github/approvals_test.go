@@ -0,0 +1,39 @@
+package github
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	ghtesthelper "github.com/openshift-pipelines/pipelines-as-code/pkg/test/github"
+	"gotest.tools/v3/assert"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestCountApprovals(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	fakeclient, mux, _, teardown := ghtesthelper.SetupGH()
+	defer teardown()
+	mux.HandleFunc("/repos/owner/repo/pulls/42/reviews", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "" {
+			w.Header().Set("Link", fmt.Sprintf(`<%s?page=2>; rel="next"`, r.URL.Path))
+			fmt.Fprint(w, `[
+				{"user": {"login": "alice"}, "state": "APPROVED"},
+				{"user": {"login": "bob"}, "state": "APPROVED"},
+				{"user": {"login": "carol"}, "state": "CHANGES_REQUESTED"}
+			]`)
+			return
+		}
+		// bob asks for changes after approving, carol approves and alice only comments
+		fmt.Fprint(w, `[
+			{"user": {"login": "bob"}, "state": "CHANGES_REQUESTED"},
+			{"user": {"login": "carol"}, "state": "APPROVED"},
+			{"user": {"login": "alice"}, "state": "COMMENTED"}
+		]`)
+	})
+	v := &Provider{Client: fakeclient}
+	approvals, err := v.CountApprovals(ctx, &info.Event{Organization: "owner", Repository: "repo", PullRequestNumber: 42})
+	assert.NilError(t, err)
+	assert.Equal(t, approvals, 2)
+}
@@ -0,0 +1,129 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+)
+
+const (
+	// changedFilesPerPage is the maximum number of files the API returns in
+	// a page.
+	changedFilesPerPage = 100
+	// compareMaxFiles is the maximum number of files the compare API returns
+	// for a comparison, a comparison with that many files may be truncated.
+	compareMaxFiles = 300
+)
+
+// changedFile only decodes the paths of a file returned by the API, the
+// patches (which can be huge or meaningless for binary files) are skipped by
+// the decoder rather than held in memory.
+type changedFile struct {
+	Filename         string `json:"filename"`
+	PreviousFilename string `json:"previous_filename"`
+}
+
+// addChangedFiles adds the paths of the files to the changed files, the
+// previous path of a renamed file is added too since moving a file out of a
+// directory is a change of that directory.
+func addChangedFiles(changed *provider.ChangedFiles, files []changedFile) {
+	for _, file := range files {
+		changed.Add(file.Filename, file.PreviousFilename)
+	}
+}
+
+// getChangedFiles gets a page of the API returning changed files into the
+// given struct and returns the next page, 0 if it was the last one.
+func (v *Provider) getChangedFiles(ctx context.Context, u string, page int, into interface{}) (int, error) {
+	req, err := v.Client.NewRequest(http.MethodGet, fmt.Sprintf("%s?per_page=%d&page=%d", u, changedFilesPerPage, page), nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := v.Client.Do(ctx, req, into)
+	if err != nil {
+		return 0, err
+	}
+	return resp.NextPage, nil
+}
+
+// pullRequestChangedFiles returns the files changed by a pull request, going
+// through all the pages of the list.
+func (v *Provider) pullRequestChangedFiles(ctx context.Context, runevent *info.Event) ([]string, error) {
+	changed := &provider.ChangedFiles{}
+	u := fmt.Sprintf("repos/%v/%v/pulls/%d/files", runevent.Organization, runevent.Repository, runevent.PullRequestNumber)
+	for page := 1; page != 0; {
+		files := []changedFile{}
+		next, err := v.getChangedFiles(ctx, u, page, &files)
+		if err != nil {
+			return []string{}, err
+		}
+		addChangedFiles(changed, files)
+		page = next
+	}
+	return changed.Paths(), nil
+}
+
+// commitChangedFiles returns the files changed by a commit, going through all
+// the pages of the files.
+func (v *Provider) commitChangedFiles(ctx context.Context, runevent *info.Event, sha string, changed *provider.ChangedFiles) error {
+	u := fmt.Sprintf("repos/%v/%v/commits/%v", runevent.Organization, runevent.Repository, url.PathEscape(sha))
+	for page := 1; page != 0; {
+		commit := struct {
+			Files []changedFile `json:"files"`
+		}{}
+		next, err := v.getChangedFiles(ctx, u, page, &commit)
+		if err != nil {
+			return err
+		}
+		addChangedFiles(changed, commit.Files)
+		page = next
+	}
+	return nil
+}
+
+// pushChangedFiles returns the files changed by a push. The files of all the
+// commits of the push are listed with the compare API, which returns every
+// changed file on its first page however many commits there are. The commits
+// are only walked one by one when the comparison has more files than the API
+// returns, or the head commit used when the push creates a new branch.
+func (v *Provider) pushChangedFiles(ctx context.Context, runevent *info.Event) ([]string, error) {
+	changed := &provider.ChangedFiles{}
+	if provider.IsZeroSHA(runevent.BeforeSHA) {
+		if err := v.commitChangedFiles(ctx, runevent, runevent.SHA, changed); err != nil {
+			return []string{}, err
+		}
+		return changed.Paths(), nil
+	}
+
+	u := fmt.Sprintf("repos/%v/%v/compare/%v...%v", runevent.Organization, runevent.Repository,
+		url.PathEscape(runevent.BeforeSHA), url.PathEscape(runevent.SHA))
+	for page := 1; page != 0; {
+		comparison := struct {
+			Files   []changedFile `json:"files"`
+			Commits []struct {
+				SHA string `json:"sha"`
+			} `json:"commits"`
+		}{}
+		next, err := v.getChangedFiles(ctx, u, page, &comparison)
+		if err != nil {
+			return []string{}, err
+		}
+		if page == 1 {
+			addChangedFiles(changed, comparison.Files)
+			if len(comparison.Files) < compareMaxFiles {
+				return changed.Paths(), nil
+			}
+		}
+		for _, commit := range comparison.Commits {
+			if err := v.commitChangedFiles(ctx, runevent, commit.SHA, changed); err != nil {
+				return []string{}, err
+			}
+		}
+		page = next
+	}
+	return changed.Paths(), nil
+}
@@ -0,0 +1,136 @@
+package github
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	ghtesthelper "github.com/openshift-pipelines/pipelines-as-code/pkg/test/github"
+	"gotest.tools/v3/assert"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestGetFilesPaginated(t *testing.T) {
+	// nextPage serves the first page with a link to the second one
+	nextPage := func(rw http.ResponseWriter, r *http.Request, first, second string) {
+		if r.URL.Query().Get("page") == "2" {
+			fmt.Fprint(rw, second)
+			return
+		}
+		rw.Header().Set("Link", fmt.Sprintf(`<%s?page=2>; rel="next"`, r.URL.Path))
+		fmt.Fprint(rw, first)
+	}
+	manyFiles := "["
+	for i := 0; i < compareMaxFiles; i++ {
+		if i > 0 {
+			manyFiles += ","
+		}
+		manyFiles += fmt.Sprintf(`{"filename": "file%d"}`, i)
+	}
+	manyFiles += "]"
+
+	tests := []struct {
+		name     string
+		event    *info.Event
+		handlers map[string]func(rw http.ResponseWriter, r *http.Request)
+		want     []string
+		wantLen  int
+	}{
+		{
+			name: "pull request with several pages of files",
+			event: &info.Event{
+				TriggerTarget:     "pull_request",
+				Organization:      "owner",
+				Repository:        "repo",
+				PullRequestNumber: 10,
+			},
+			handlers: map[string]func(rw http.ResponseWriter, r *http.Request){
+				"/repos/owner/repo/pulls/10/files": func(rw http.ResponseWriter, r *http.Request) {
+					nextPage(rw, r,
+						`[{"filename": "first.yaml", "patch": "@@ -1 +1 @@"}, {"filename": "image.png"}]`,
+						`[{"filename": "new/name.go", "previous_filename": "old/name.go"}]`)
+				},
+			},
+			want: []string{"first.yaml", "image.png", "new/name.go", "old/name.go"},
+		},
+		{
+			name: "push of several commits",
+			event: &info.Event{
+				TriggerTarget: "push",
+				Organization:  "owner",
+				Repository:    "repo",
+				SHA:           "after",
+				BeforeSHA:     "before",
+			},
+			handlers: map[string]func(rw http.ResponseWriter, r *http.Request){
+				"/repos/owner/repo/compare/before...after": func(rw http.ResponseWriter, r *http.Request) {
+					nextPage(rw, r,
+						`{"files": [{"filename": "first.yaml"}, {"filename": "second.doc"}], "commits": [{"sha": "one"}]}`,
+						`{"commits": [{"sha": "two"}]}`)
+				},
+			},
+			want: []string{"first.yaml", "second.doc"},
+		},
+		{
+			name: "push creating a branch",
+			event: &info.Event{
+				TriggerTarget: "push",
+				Organization:  "owner",
+				Repository:    "repo",
+				SHA:           "after",
+				BeforeSHA:     "0000000000000000000000000000000000000000",
+			},
+			handlers: map[string]func(rw http.ResponseWriter, r *http.Request){
+				"/repos/owner/repo/commits/after": func(rw http.ResponseWriter, r *http.Request) {
+					nextPage(rw, r, `{"files": [{"filename": "first.yaml"}]}`, `{"files": [{"filename": "second.doc"}]}`)
+				},
+			},
+			want: []string{"first.yaml", "second.doc"},
+		},
+		{
+			name: "push with a truncated comparison walks the commits",
+			event: &info.Event{
+				TriggerTarget: "push",
+				Organization:  "owner",
+				Repository:    "repo",
+				SHA:           "after",
+				BeforeSHA:     "before",
+			},
+			handlers: map[string]func(rw http.ResponseWriter, r *http.Request){
+				"/repos/owner/repo/compare/before...after": func(rw http.ResponseWriter, r *http.Request) {
+					nextPage(rw, r,
+						fmt.Sprintf(`{"files": %s, "commits": [{"sha": "one"}]}`, manyFiles),
+						`{"commits": [{"sha": "two"}]}`)
+				},
+				"/repos/owner/repo/commits/one": func(rw http.ResponseWriter, r *http.Request) {
+					fmt.Fprint(rw, `{"files": [{"filename": "file0"}, {"filename": "beyond/one"}]}`)
+				},
+				"/repos/owner/repo/commits/two": func(rw http.ResponseWriter, r *http.Request) {
+					fmt.Fprint(rw, `{"files": [{"filename": "beyond/two"}]}`)
+				},
+			},
+			wantLen: compareMaxFiles + 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeclient, mux, _, teardown := ghtesthelper.SetupGH()
+			defer teardown()
+			for path, handler := range tt.handlers {
+				mux.HandleFunc(path, handler)
+			}
+
+			ctx, _ := rtesting.SetupFakeContext(t)
+			provider := &Provider{Client: fakeclient}
+			got, err := provider.GetFiles(ctx, tt.event)
+			assert.NilError(t, err)
+			if tt.wantLen > 0 {
+				assert.Equal(t, len(got), tt.wantLen)
+				assert.Equal(t, got[len(got)-1], "beyond/two")
+				return
+			}
+			assert.DeepEqual(t, got, tt.want)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package github
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+)
+
+// pullRequestActions are the pull_request actions handled by Pipelines as Code.
+var pullRequestActions = []string{"opened", "synchronize", "synchronized", "reopened"}
+
+// isApprovedReview returns true if the pull_request_review event is an
+// approval, handled by Pipelines as Code as the pull_request_approved event.
+func isApprovedReview(action, state string) bool {
+	return action == "submitted" && strings.EqualFold(state, "approved")
+}
+
+// customEventTypesEvents are the GitHub events which can be mapped to a custom
+// event type.
+var customEventTypesEvents = []string{"pull_request", "pull_request_review"}
+
+// customEventType returns the name of the custom event type matching the
+// event, the events already handled by Pipelines as Code are never mapped to
+// a custom event type.
+func customEventType(run *params.Run, eventType, payload string) string {
+	if run == nil || run.Info.Pac == nil || run.Info.Pac.Settings == nil ||
+		len(run.Info.Pac.CustomEventTypes) == 0 || !provider.Valid(eventType, customEventTypesEvents) {
+		return ""
+	}
+	var data struct {
+		Action string `json:"action"`
+		Review struct {
+			State string `json:"state"`
+		} `json:"review"`
+	}
+	if err := json.Unmarshal([]byte(payload), &data); err != nil {
+		return ""
+	}
+	if eventType == "pull_request" && provider.Valid(data.Action, pullRequestActions) {
+		return ""
+	}
+	if eventType == "pull_request_review" && isApprovedReview(data.Action, data.Review.State) {
+		return ""
+	}
+	return settings.MatchCustomEventType(run.Info.Pac.CustomEventTypes, eventType, data.Action, data.Review.State)
+}
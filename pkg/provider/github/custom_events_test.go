@@ -0,0 +1,148 @@
+package github
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-github/v49/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/test/logger"
+	"gotest.tools/v3/assert"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestCustomEventTypes(t *testing.T) {
+	run := &params.Run{
+		Info: info.Info{
+			Pac: &info.PacOpts{
+				Settings: &settings.Settings{
+					CustomEventTypes: []settings.CustomEventType{
+						{Name: "pr-changes-requested", Event: "pull_request_review", Action: "submitted", State: "changes_requested"},
+						{Name: "pr-labeled", Event: "pull_request", Action: "labeled"},
+						{Name: "pr-any", Event: "pull_request"},
+					},
+				},
+			},
+		},
+	}
+	pr := &github.PullRequest{
+		Number: github.Int(42),
+		User:   &github.User{Login: github.String("author")},
+		Head:   &github.PullRequestBranch{SHA: github.String("headsha"), Ref: github.String("feature")},
+		Base:   &github.PullRequestBranch{Ref: github.String("main")},
+	}
+	repo := &github.Repository{
+		Name:  github.String("repo"),
+		Owner: &github.User{Login: github.String("owner")},
+	}
+
+	tests := []struct {
+		name                string
+		eventType           string
+		event               interface{}
+		run                 *params.Run
+		wantProcess         bool
+		wantCustomEventType string
+	}{
+		{
+			name:      "changes requested review",
+			eventType: "pull_request_review",
+			event: github.PullRequestReviewEvent{
+				Action:      github.String("submitted"),
+				Review:      &github.PullRequestReview{State: github.String("changes_requested")},
+				PullRequest: pr,
+				Repo:        repo,
+			},
+			run:                 run,
+			wantProcess:         true,
+			wantCustomEventType: "pr-changes-requested",
+		},
+		{
+			name:      "approved review is handled by pipelines as code",
+			eventType: "pull_request_review",
+			event: github.PullRequestReviewEvent{
+				Action:      github.String("submitted"),
+				Review:      &github.PullRequestReview{State: github.String("approved")},
+				PullRequest: pr,
+				Repo:        repo,
+			},
+			run:                 run,
+			wantProcess:         true,
+			wantCustomEventType: "pull_request_approved",
+		},
+		{
+			name:      "review not mapped",
+			eventType: "pull_request_review",
+			event: github.PullRequestReviewEvent{
+				Action:      github.String("submitted"),
+				Review:      &github.PullRequestReview{State: github.String("commented")},
+				PullRequest: pr,
+				Repo:        repo,
+			},
+			run: run,
+		},
+		{
+			name:      "labeled pull request",
+			eventType: "pull_request",
+			event: github.PullRequestEvent{
+				Action:      github.String("labeled"),
+				PullRequest: pr,
+				Repo:        repo,
+			},
+			run:                 run,
+			wantProcess:         true,
+			wantCustomEventType: "pr-labeled",
+		},
+		{
+			name:      "pull request actions handled by pipelines as code are not mapped",
+			eventType: "pull_request",
+			event: github.PullRequestEvent{
+				Action:      github.String("opened"),
+				PullRequest: pr,
+				Repo:        repo,
+			},
+			run:         run,
+			wantProcess: true,
+		},
+		{
+			name:      "no custom event types",
+			eventType: "pull_request_review",
+			event: github.PullRequestReviewEvent{
+				Action:      github.String("submitted"),
+				Review:      &github.PullRequestReview{State: github.String("changes_requested")},
+				PullRequest: pr,
+				Repo:        repo,
+			},
+			run: &params.Run{Info: info.Info{Pac: &info.PacOpts{Settings: &settings.Settings{}}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			logger, _ := logger.GetLogger()
+			payload, err := json.Marshal(tt.event)
+			assert.NilError(t, err)
+			request := &http.Request{Header: http.Header{}}
+			request.Header.Set("X-GitHub-Event", tt.eventType)
+
+			gprovider := &Provider{Run: tt.run, Logger: logger}
+			_, process, _, _, err := gprovider.Detect(request, string(payload), logger)
+			assert.NilError(t, err)
+			assert.Equal(t, process, tt.wantProcess)
+			if !tt.wantProcess {
+				return
+			}
+
+			event, err := gprovider.ParsePayload(ctx, tt.run, request, string(payload))
+			assert.NilError(t, err)
+			assert.Equal(t, event.CustomEventType, tt.wantCustomEventType)
+			assert.Equal(t, event.TriggerTarget, "pull_request")
+			assert.Equal(t, event.SHA, "headsha")
+			assert.Equal(t, event.PullRequestNumber, 42)
+			assert.Equal(t, event.Sender, "author")
+		})
+	}
+}
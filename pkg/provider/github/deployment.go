@@ -0,0 +1,114 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v49/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/action"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	"github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
+)
+
+// deploymentDescriptions are the descriptions of the deployment statuses for
+// each of their states.
+var deploymentDescriptions = map[string]string{
+	"queued":      "The deployment is waiting to start.",
+	"in_progress": "The deployment is running.",
+	"success":     "The deployment has succeeded.",
+	"failure":     "The deployment has failed.",
+	"error":       "The deployment has been cancelled or has errored.",
+}
+
+// deploymentEnvironment returns the environment the PipelineRun deploys to
+// as set in its deployment annotation, an empty string when the PipelineRun
+// is not a deployment.
+func deploymentEnvironment(statusOpts provider.StatusOpts) string {
+	if statusOpts.PipelineRun == nil {
+		return ""
+	}
+	return strings.TrimSpace(statusOpts.PipelineRun.GetAnnotations()[keys.Deployment])
+}
+
+// deploymentState returns the state of the deployment status for the status
+// of the PipelineRun.
+func deploymentState(statusOpts provider.StatusOpts) string {
+	switch statusOpts.Status {
+	case "queued":
+		return "queued"
+	case "in_progress":
+		return "in_progress"
+	}
+	if isPipelineRunCancelledOrStopped(statusOpts.PipelineRun) {
+		return "error"
+	}
+	switch statusOpts.Conclusion {
+	case "success":
+		return "success"
+	case "failure", provider.ConclusionOverridden:
+		return "failure"
+	}
+	return "error"
+}
+
+// updateDeployment creates a GitHub deployment for the PipelineRun
+// annotated as a deployment the first time it gets a status and adds the
+// status of the PipelineRun to it, the environment then shows up in the
+// Environments of the repository. The id of the deployment is kept in an
+// annotation of the PipelineRun to update the same deployment.
+func (v *Provider) updateDeployment(ctx context.Context, tekton versioned.Interface, runevent *info.Event, pacopts *info.PacOpts, statusOpts provider.StatusOpts) error {
+	environment := deploymentEnvironment(statusOpts)
+	if environment == "" {
+		return nil
+	}
+
+	var deploymentID int64
+	if id, ok := statusOpts.PipelineRun.GetAnnotations()[keys.DeploymentID]; ok {
+		var err error
+		if deploymentID, err = strconv.ParseInt(id, 10, 64); err != nil {
+			return fmt.Errorf("cannot convert the deployment id %q: %w", id, err)
+		}
+	} else {
+		deployment, _, err := v.Client.Repositories.CreateDeployment(ctx, runevent.Organization, runevent.Repository, &github.DeploymentRequest{
+			Ref:         github.String(runevent.SHA),
+			Environment: github.String(environment),
+			Description: github.String(fmt.Sprintf("%s %s", pacopts.ApplicationName, statusOpts.PipelineRun.GetName())),
+			// the PipelineRun is the deployment, it doesn't wait for the
+			// other statuses of the commit or merge the default branch
+			AutoMerge:        github.Bool(false),
+			RequiredContexts: &[]string{},
+		})
+		if err != nil {
+			return fmt.Errorf("cannot create the deployment to the %s environment: %w", environment, err)
+		}
+		deploymentID = deployment.GetID()
+		patch := map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"annotations": map[string]string{
+					keys.DeploymentID: strconv.FormatInt(deploymentID, 10),
+				},
+			},
+		}
+		if _, err := action.PatchPipelineRun(ctx, v.Logger, "deployment id", tekton, statusOpts.PipelineRun, patch); err != nil {
+			return err
+		}
+	}
+
+	state := deploymentState(statusOpts)
+	request := &github.DeploymentStatusRequest{
+		State:       github.String(state),
+		Environment: github.String(environment),
+		Description: github.String(deploymentDescriptions[state]),
+	}
+	if statusOpts.DetailsURL != "" {
+		request.LogURL = github.String(statusOpts.DetailsURL)
+	}
+	if _, _, err := v.Client.Repositories.CreateDeploymentStatus(ctx, runevent.Organization, runevent.Repository, deploymentID, request); err != nil {
+		return fmt.Errorf("cannot create the status of the deployment %d: %w", deploymentID, err)
+	}
+	return nil
+}
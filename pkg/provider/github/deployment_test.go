@@ -0,0 +1,133 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-github/v49/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	ghtesthelper "github.com/openshift-pipelines/pipelines-as-code/pkg/test/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/test/logger"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestDeploymentState(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     string
+		conclusion string
+		cancelled  bool
+		want       string
+	}{
+		{name: "queued", status: "queued", want: "queued"},
+		{name: "running", status: "in_progress", want: "in_progress"},
+		{name: "succeeded", status: "completed", conclusion: "success", want: "success"},
+		{name: "failed", status: "completed", conclusion: "failure", want: "failure"},
+		{name: "overridden", status: "completed", conclusion: provider.ConclusionOverridden, want: "failure"},
+		{name: "cancelled", status: "completed", conclusion: "failure", cancelled: true, want: "error"},
+		{name: "unknown", status: "completed", conclusion: "neutral", want: "error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr := &tektonv1.PipelineRun{}
+			if tt.cancelled {
+				pr.Spec.Status = tektonv1.PipelineRunSpecStatusCancelled
+			}
+			got := deploymentState(provider.StatusOpts{PipelineRun: pr, Status: tt.status, Conclusion: tt.conclusion})
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
+
+func TestUpdateDeployment(t *testing.T) {
+	tests := []struct {
+		name            string
+		annotations     map[string]string
+		wantCreated     bool
+		wantStatusOnID  int64
+		wantStatusState string
+	}{
+		{
+			name:        "not a deployment",
+			annotations: map[string]string{},
+		},
+		{
+			name:            "deployment created on the first status",
+			annotations:     map[string]string{keys.Deployment: "production"},
+			wantCreated:     true,
+			wantStatusOnID:  42,
+			wantStatusState: "in_progress",
+		},
+		{
+			name:            "existing deployment is updated",
+			annotations:     map[string]string{keys.Deployment: "production", keys.DeploymentID: "24"},
+			wantStatusOnID:  24,
+			wantStatusState: "in_progress",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			fakeclient, mux, _, teardown := ghtesthelper.SetupGH()
+			defer teardown()
+			cnx := New()
+			cnx.Client = fakeclient
+			cnx.Logger, _ = logger.GetLogger()
+
+			pr := &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{
+				Name:        "deploy-abcde",
+				Namespace:   "ns",
+				Annotations: tt.annotations,
+			}}
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{PipelineRuns: []*tektonv1.PipelineRun{pr}})
+
+			created := false
+			mux.HandleFunc("/repos/owner/repo/deployments", func(w http.ResponseWriter, r *http.Request) {
+				created = true
+				request := github.DeploymentRequest{}
+				assert.NilError(t, json.NewDecoder(r.Body).Decode(&request))
+				assert.Equal(t, request.GetRef(), "sha")
+				assert.Equal(t, request.GetEnvironment(), "production")
+				assert.Equal(t, request.GetAutoMerge(), false)
+				assert.Equal(t, len(*request.RequiredContexts), 0)
+				_, _ = fmt.Fprint(w, `{"id": 42}`)
+			})
+			statusOnID := int64(0)
+			mux.HandleFunc("/repos/owner/repo/deployments/", func(w http.ResponseWriter, r *http.Request) {
+				_, _ = fmt.Sscanf(r.URL.Path, "/repos/owner/repo/deployments/%d/statuses", &statusOnID)
+				request := github.DeploymentStatusRequest{}
+				assert.NilError(t, json.NewDecoder(r.Body).Decode(&request))
+				assert.Equal(t, request.GetState(), tt.wantStatusState)
+				assert.Equal(t, request.GetLogURL(), "https://logs/deploy")
+				assert.Equal(t, request.GetEnvironment(), "production")
+				_, _ = fmt.Fprint(w, `{}`)
+			})
+
+			event := &info.Event{Organization: "owner", Repository: "repo", SHA: "sha"}
+			err := cnx.updateDeployment(ctx, stdata.Pipeline, event, &info.PacOpts{Settings: &settings.Settings{ApplicationName: "CI"}}, provider.StatusOpts{
+				PipelineRun:     pr,
+				PipelineRunName: pr.GetName(),
+				Status:          "in_progress",
+				DetailsURL:      "https://logs/deploy",
+			})
+			assert.NilError(t, err)
+			assert.Equal(t, created, tt.wantCreated)
+			assert.Equal(t, statusOnID, tt.wantStatusOnID)
+
+			if tt.wantCreated {
+				patched, err := stdata.Pipeline.TektonV1().PipelineRuns("ns").Get(ctx, pr.GetName(), metav1.GetOptions{})
+				assert.NilError(t, err)
+				assert.Equal(t, patched.GetAnnotations()[keys.DeploymentID], "42")
+			}
+		})
+	}
+}
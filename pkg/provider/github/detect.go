@@ -60,6 +60,9 @@ func (v *Provider) Detect(req *http.Request, payload string, logger *zap.Sugared
 			if provider.IsCancelComment(gitEvent.GetComment().GetBody()) {
 				return setLoggerAndProceed(true, "", nil)
 			}
+			if provider.IsOverrideComment(gitEvent.GetComment().GetBody()) {
+				return setLoggerAndProceed(true, "", nil)
+			}
 			return setLoggerAndProceed(false, "", nil)
 		}
 		return setLoggerAndProceed(false, "issue: not a gitops pull request comment", nil)
@@ -70,12 +73,27 @@ func (v *Provider) Detect(req *http.Request, payload string, logger *zap.Sugared
 		return setLoggerAndProceed(false, "push: no pusher in event", nil)
 
 	case *github.PullRequestEvent:
-		if provider.Valid(gitEvent.GetAction(), []string{"opened", "synchronize", "synchronized", "reopened"}) {
+		if provider.Valid(gitEvent.GetAction(), pullRequestActions) {
+			return setLoggerAndProceed(true, "", nil)
+		}
+		if customEventType(v.Run, event, payload) != "" {
 			return setLoggerAndProceed(true, "", nil)
 		}
 		return setLoggerAndProceed(false, fmt.Sprintf("pull_request: unsupported action \"%s\"", gitEvent.GetAction()), nil)
 
+	case *github.PullRequestReviewEvent:
+		if isApprovedReview(gitEvent.GetAction(), gitEvent.GetReview().GetState()) {
+			return setLoggerAndProceed(true, "", nil)
+		}
+		if customEventType(v.Run, event, payload) != "" {
+			return setLoggerAndProceed(true, "", nil)
+		}
+		return setLoggerAndProceed(false, "pull_request_review: not an approval", nil)
+
 	default:
+		if customEventType(v.Run, event, payload) != "" {
+			return setLoggerAndProceed(true, "", nil)
+		}
 		return setLoggerAndProceed(false, fmt.Sprintf("github: event \"%v\" is not supported", event), nil)
 	}
 }
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"path"
 	"strings"
 	"sync"
 	"time"
@@ -14,6 +15,7 @@ import (
 	"github.com/jonboulle/clockwork"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
 	"go.uber.org/zap"
 	"golang.org/x/oauth2"
@@ -38,6 +40,14 @@ type Provider struct {
 	providerName  string
 	Run           *params.Run
 	repositoryIDs []int64
+	// checksUnavailable is set when the check runs cannot be used, the
+	// commit status API is used instead.
+	checksUnavailable bool
+	// remoteClients are the clients reading the other repositories the
+	// GitHub App is installed on, by org/repo. The remote tasks are fetched
+	// concurrently, remoteClientsMutex guards it.
+	remoteClients      map[string]*github.Client
+	remoteClientsMutex sync.Mutex
 
 	skippedRun
 }
@@ -90,7 +100,12 @@ func splitGithubURL(event *info.Event, uri string) (string, string, string, stri
 	return spOrg, spRepo, spPath, spRef, nil
 }
 
-func (v *Provider) GetTaskURI(ctx context.Context, _ *params.Run, event *info.Event, uri string) (bool, string, error) {
+// GetTaskURI fetches a remote task from a GitHub URL. With the GitHub App, a
+// task in another repository the token of the event cannot read is fetched
+// with a token of the installation of the App on that repository, so private
+// libraries of tasks and pipelines can be shared across the organizations the
+// App is installed on.
+func (v *Provider) GetTaskURI(ctx context.Context, run *params.Run, event *info.Event, uri string) (bool, string, error) {
 	if ret := provider.CompareHostOfURLS(uri, event.URL); !ret {
 		return false, "", nil
 	}
@@ -104,6 +119,19 @@ func (v *Provider) GetTaskURI(ctx context.Context, _ *params.Run, event *info.Ev
 	nEvent.Repository = spRepo
 	nEvent.BaseBranch = spRef
 	ret, err := v.GetFileInsideRepo(ctx, nEvent, spPath, spRef)
+	if err != nil && event.InstallationID > 0 && isNotAccessible(err) &&
+		(spOrg != event.Organization || spRepo != event.Repository) {
+		if !remoteRepositoryAllowed(run, spOrg, spRepo) {
+			return false, "", fmt.Errorf("cannot read %s from %s/%s, the repository needs to be listed in the %s setting to be read with the GitHub App: %w",
+				spPath, spOrg, spRepo, settings.RemoteTasksExtraReposKey, err)
+		}
+		client, cerr := v.remoteRepositoryClient(ctx, run, spOrg, spRepo)
+		if cerr != nil {
+			return false, "", cerr
+		}
+		remote := &Provider{Client: client, Logger: v.Logger}
+		ret, err = remote.GetFileInsideRepo(ctx, nEvent, spPath, spRef)
+	}
 	if err != nil {
 		return false, "", err
 	}
@@ -227,7 +255,8 @@ func (v *Provider) checkWebhookSecretValidity(ctx context.Context, cw clockwork.
 }
 
 func (v *Provider) SetClient(ctx context.Context, run *params.Run, event *info.Event) error {
-	client, providerName, apiURL := makeClient(ctx, event.Provider.URL, event.Provider.Token)
+	// GitHub Enterprise uses the settings of github too
+	client, providerName, apiURL := makeClient(provider.WithAPIClientConfig(ctx, run, "github"), event.Provider.URL, event.Provider.Token)
 	v.providerName = providerName
 	v.Run = run
 
@@ -250,7 +279,9 @@ func (v *Provider) SetClient(ctx context.Context, run *params.Run, event *info.E
 }
 
 // GetTektonDir Get all yaml files in tekton directory return as a single concated file
-func (v *Provider) GetTektonDir(ctx context.Context, runevent *info.Event, path string) (string, error) {
+// tektonDirSHA returns the SHA of the tree of the directory at the root of
+// the repository at the revision of the event, empty if there is none.
+func (v *Provider) tektonDirSHA(ctx context.Context, runevent *info.Event, path string) (string, error) {
 	tektonDirSha := ""
 
 	rootobjects, _, err := v.Client.Git.GetTree(ctx, runevent.Organization, runevent.Repository, runevent.SHA, false)
@@ -265,6 +296,24 @@ func (v *Provider) GetTektonDir(ctx context.Context, runevent *info.Event, path
 			tektonDirSha = object.GetSHA()
 		}
 	}
+	return tektonDirSha, nil
+}
+
+// HasTektonDir checks if the directory exists at the revision of the event
+// with the tree of the root of the repository.
+func (v *Provider) HasTektonDir(ctx context.Context, runevent *info.Event, path string) (bool, error) {
+	tektonDirSha, err := v.tektonDirSHA(ctx, runevent, path)
+	if err != nil {
+		return false, err
+	}
+	return tektonDirSha != "", nil
+}
+
+func (v *Provider) GetTektonDir(ctx context.Context, runevent *info.Event, path string) (string, error) {
+	tektonDirSha, err := v.tektonDirSHA(ctx, runevent, path)
+	if err != nil {
+		return "", err
+	}
 
 	// If we didn't find a .tekton directory then just silently ignore the error.
 	if tektonDirSha == "" {
@@ -280,7 +329,7 @@ func (v *Provider) GetTektonDir(ctx context.Context, runevent *info.Event, path
 	if err != nil {
 		return "", err
 	}
-	return v.concatAllYamlFiles(ctx, tektonDirObjects.Entries, runevent)
+	return v.concatAllYamlFiles(ctx, path, tektonDirObjects.Entries, runevent)
 }
 
 // GetCommitInfo get info (url and title) on a commit in runevent, this needs to
@@ -342,20 +391,20 @@ func (v *Provider) GetFileInsideRepo(ctx context.Context, runevent *info.Event,
 }
 
 // concatAllYamlFiles concat all yaml files from a directory as one big multi document yaml string
-func (v *Provider) concatAllYamlFiles(ctx context.Context, objects []*github.TreeEntry, runevent *info.Event) (string, error) {
+func (v *Provider) concatAllYamlFiles(ctx context.Context, dir string, objects []*github.TreeEntry, runevent *info.Event) (string, error) {
 	var allTemplates string
 
 	for _, value := range objects {
 		if strings.HasSuffix(value.GetPath(), ".yaml") ||
 			strings.HasSuffix(value.GetPath(), ".yml") {
-			data, err := v.getObject(ctx, value.GetSHA(), runevent)
+			data, err := provider.CachedFileContent(runevent.URL, value.GetSHA(), "", func() (string, error) {
+				data, err := v.getObject(ctx, value.GetSHA(), runevent)
+				return string(data), err
+			})
 			if err != nil {
 				return "", err
 			}
-			if allTemplates != "" && !strings.HasPrefix(string(data), "---") {
-				allTemplates += "---"
-			}
-			allTemplates += "\n" + string(data) + "\n"
+			allTemplates = provider.AppendTektonFile(allTemplates, path.Join(dir, value.GetPath()), data)
 		}
 	}
 	return allTemplates, nil
@@ -388,30 +437,14 @@ func (v *Provider) getPullRequest(ctx context.Context, runevent *info.Event) (*i
 	return runevent, nil
 }
 
-// GetFiles get a files from pull request
+// GetFiles get the files changed by a pull request or a push
 func (v *Provider) GetFiles(ctx context.Context, runevent *info.Event) ([]string, error) {
 	if runevent.TriggerTarget == "pull_request" {
-		repoCommit, _, err := v.Client.PullRequests.ListFiles(ctx, runevent.Organization, runevent.Repository, runevent.PullRequestNumber, &github.ListOptions{})
-		if err != nil {
-			return []string{}, err
-		}
-		result := []string{}
-		for j := range repoCommit {
-			result = append(result, *repoCommit[j].Filename)
-		}
-		return result, nil
+		return v.pullRequestChangedFiles(ctx, runevent)
 	}
 
 	if runevent.TriggerTarget == "push" {
-		result := []string{}
-		rC, _, err := v.Client.Repositories.GetCommit(ctx, runevent.Organization, runevent.Repository, runevent.SHA, &github.ListOptions{})
-		if err != nil {
-			return []string{}, err
-		}
-		for i := range rC.Files {
-			result = append(result, *rC.Files[i].Filename)
-		}
-		return result, nil
+		return v.pushChangedFiles(ctx, runevent)
 	}
 	return []string{}, nil
 }
@@ -170,6 +170,13 @@ func TestGetTektonDir(t *testing.T) {
 			got, err := gvcs.GetTektonDir(ctx, tt.event, ".tekton")
 			assert.NilError(t, err)
 			assert.Assert(t, strings.Contains(got, tt.expectedString), "expected %s, got %s", tt.expectedString, got)
+
+			found, err := gvcs.HasTektonDir(ctx, tt.event, ".tekton")
+			assert.NilError(t, err)
+			assert.Assert(t, found)
+			found, err = gvcs.HasTektonDir(ctx, tt.event, "nothere")
+			assert.NilError(t, err)
+			assert.Assert(t, !found)
 		})
 	}
 }
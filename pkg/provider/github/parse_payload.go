@@ -50,6 +50,9 @@ func (v *Provider) GetAppToken(ctx context.Context, kube kubernetes.Interface, g
 	}
 	v.ApplicationID = &applicationID
 	tr := http.DefaultTransport
+	if httpClient := provider.HTTPClient(ctx); httpClient != nil && httpClient.Transport != nil {
+		tr = httpClient.Transport
+	}
 
 	itr, err := ghinstallation.New(tr, applicationID, installationID, privateKey)
 	if err != nil {
@@ -143,6 +146,8 @@ func (v *Provider) ParsePayload(ctx context.Context, run *params.Run, request *h
 
 	installationIDFrompayload := getInstallationIDFromPayload(payload)
 	if installationIDFrompayload != -1 {
+		// the client of the installation is kept by SetClient
+		ctx = provider.WithAPIClientConfig(ctx, run, "github")
 		var err error
 		if event.Provider.Token, err = v.GetAppToken(ctx, run.Clients.Kube, event.Provider.URL, installationIDFrompayload); err != nil {
 			return nil, err
@@ -161,6 +166,9 @@ func (v *Provider) ParsePayload(ctx context.Context, run *params.Run, request *h
 	if err != nil {
 		return nil, err
 	}
+	if processedEvent.CustomEventType == "" {
+		processedEvent.CustomEventType = customEventType(run, event.EventType, payload)
+	}
 
 	// regenerate token scoped to the repo IDs
 	if run.Info.Pac.SecretGHAppRepoScoped && installationIDFrompayload != -1 && len(v.repositoryIDs) > 0 {
@@ -232,6 +240,8 @@ func (v *Provider) processEvent(ctx context.Context, event *info.Event, eventInt
 		// on push event we may not get a head commit but only
 		if processedEvent.SHA == "" {
 			processedEvent.SHA = gitEvent.GetBefore()
+		} else {
+			processedEvent.BeforeSHA = gitEvent.GetBefore()
 		}
 		processedEvent.SHAURL = gitEvent.GetHeadCommit().GetURL()
 		processedEvent.SHATitle = gitEvent.GetHeadCommit().GetMessage()
@@ -240,21 +250,11 @@ func (v *Provider) processEvent(ctx context.Context, event *info.Event, eventInt
 		processedEvent.EventType = event.TriggerTarget
 		processedEvent.HeadBranch = processedEvent.BaseBranch // in push events Head Branch is the same as Basebranch
 	case *github.PullRequestEvent:
-		processedEvent = info.NewEvent()
-		processedEvent.Repository = gitEvent.GetRepo().GetName()
-		processedEvent.Organization = gitEvent.GetRepo().Owner.GetLogin()
-		processedEvent.DefaultBranch = gitEvent.GetRepo().GetDefaultBranch()
-		processedEvent.SHA = gitEvent.GetPullRequest().Head.GetSHA()
-		processedEvent.URL = gitEvent.GetRepo().GetHTMLURL()
-		processedEvent.BaseBranch = gitEvent.GetPullRequest().Base.GetRef()
-		processedEvent.HeadBranch = gitEvent.GetPullRequest().Head.GetRef()
-		processedEvent.Sender = gitEvent.GetPullRequest().GetUser().GetLogin()
-		processedEvent.EventType = event.EventType
-		processedEvent.PullRequestNumber = gitEvent.GetPullRequest().GetNumber()
-		// getting the repository ids of the base and head of the pull request
-		// to scope the token to
-		v.repositoryIDs = []int64{
-			gitEvent.GetPullRequest().GetBase().GetRepo().GetID(),
+		processedEvent = v.pullRequestEvent(event, gitEvent.GetRepo(), gitEvent.GetPullRequest())
+	case *github.PullRequestReviewEvent:
+		processedEvent = v.pullRequestEvent(event, gitEvent.GetRepo(), gitEvent.GetPullRequest())
+		if isApprovedReview(gitEvent.GetAction(), gitEvent.GetReview().GetState()) {
+			processedEvent.CustomEventType = provider.PullRequestApprovedEventType
 		}
 	default:
 		return nil, errors.New("this event is not supported")
@@ -267,6 +267,29 @@ func (v *Provider) processEvent(ctx context.Context, event *info.Event, eventInt
 	return processedEvent, nil
 }
 
+func (v *Provider) pullRequestEvent(event *info.Event, repo *github.Repository, pr *github.PullRequest) *info.Event {
+	processedEvent := info.NewEvent()
+	processedEvent.Repository = repo.GetName()
+	processedEvent.Organization = repo.GetOwner().GetLogin()
+	processedEvent.DefaultBranch = repo.GetDefaultBranch()
+	processedEvent.SHA = pr.GetHead().GetSHA()
+	processedEvent.URL = repo.GetHTMLURL()
+	processedEvent.BaseBranch = pr.GetBase().GetRef()
+	processedEvent.HeadBranch = pr.GetHead().GetRef()
+	processedEvent.Sender = pr.GetUser().GetLogin()
+	processedEvent.EventType = event.EventType
+	processedEvent.PullRequestNumber = pr.GetNumber()
+	for _, label := range pr.Labels {
+		processedEvent.PullRequestLabel = append(processedEvent.PullRequestLabel, label.GetName())
+	}
+	// getting the repository ids of the base and head of the pull request
+	// to scope the token to
+	v.repositoryIDs = []int64{
+		pr.GetBase().GetRepo().GetID(),
+	}
+	return processedEvent
+}
+
 func (v *Provider) handleReRequestEvent(ctx context.Context, event *github.CheckRunEvent) (*info.Event, error) {
 	runevent := info.NewEvent()
 	runevent.Organization = event.GetRepo().GetOwner().GetLogin()
@@ -310,14 +333,21 @@ func (v *Provider) handleIssueCommentEvent(ctx context.Context, event *github.Is
 	}
 
 	// if it is a /test or /retest comment with pipelinerun name figure out the pipelinerun name
+	runevent.TriggerComment = event.GetComment().GetBody()
 	if provider.IsTestRetestComment(event.GetComment().GetBody()) {
 		runevent.TargetTestPipelineRun = provider.GetPipelineRunFromTestComment(event.GetComment().GetBody())
+		runevent.TriggerCommentArgs = provider.GetArgsFromTestComment(event.GetComment().GetBody())
 	}
 	if provider.IsCancelComment(event.GetComment().GetBody()) {
 		action = "cancellation"
 		runevent.CancelPipelineRuns = true
 		runevent.TargetCancelPipelineRun = provider.GetPipelineRunFromCancelComment(event.GetComment().GetBody())
 	}
+	if provider.IsOverrideComment(event.GetComment().GetBody()) {
+		action = "override"
+		runevent.OverridePipelineRuns = true
+		runevent.TargetOverridePipelineRun, runevent.OverrideReason = provider.GetOverrideFromComment(event.GetComment().GetBody())
+	}
 	// We are getting the full URL so we have to get the last part to get the PR number,
 	// we don't have to care about URL query string/hash and other stuff because
 	// that comes up from the API.
@@ -0,0 +1,92 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/bradleyfalzon/ghinstallation/v2"
+	"github.com/google/go-github/v49/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"golang.org/x/oauth2"
+)
+
+// isNotAccessible returns true if the API answered that the resource doesn't
+// exist or is forbidden, which is what GitHub answers for a private
+// repository the token doesn't have access to.
+func isNotAccessible(err error) bool {
+	var errResp *github.ErrorResponse
+	if !errors.As(err, &errResp) || errResp.Response == nil {
+		return false
+	}
+	return errResp.Response.StatusCode == http.StatusNotFound || errResp.Response.StatusCode == http.StatusForbidden
+}
+
+// remoteRepositoryAllowed returns true if the repository is listed in the
+// remote-tasks-extra-repos setting, as owner/repo or as owner/* for every
+// repository of the owner.
+func remoteRepositoryAllowed(run *params.Run, org, repo string) bool {
+	if run == nil || run.Info.Pac == nil || run.Info.Pac.Settings == nil {
+		return false
+	}
+	for _, allowed := range strings.Split(run.Info.Pac.RemoteTasksExtraRepos, ",") {
+		allowedOrg, allowedRepo, ok := strings.Cut(strings.TrimSpace(allowed), "/")
+		if !ok || !strings.EqualFold(allowedOrg, org) {
+			continue
+		}
+		if allowedRepo == "*" || strings.EqualFold(allowedRepo, repo) {
+			return true
+		}
+	}
+	return false
+}
+
+// remoteRepositoryClient returns a client to read another repository the
+// GitHub App is installed on, possibly in another organization. The token of
+// the event is scoped to the installation of the App on the repository of the
+// event, and to that repository only when the tokens are scoped, a token of
+// the installation on the other repository is created with the permission to
+// read its contents only. The caller checks the repository is allowed with
+// remoteRepositoryAllowed first. The clients are kept for the other remote tasks of
+// the event.
+func (v *Provider) remoteRepositoryClient(ctx context.Context, run *params.Run, org, repo string) (*github.Client, error) {
+	key := org + "/" + repo
+	v.remoteClientsMutex.Lock()
+	defer v.remoteClientsMutex.Unlock()
+	if client, ok := v.remoteClients[key]; ok {
+		return client, nil
+	}
+
+	applicationID, privateKey, err := GetAppIDAndPrivateKey(ctx, run.Clients.Kube)
+	if err != nil {
+		return nil, err
+	}
+	atr, err := ghinstallation.NewAppsTransport(http.DefaultTransport, applicationID, privateKey)
+	if err != nil {
+		return nil, err
+	}
+	appClient := github.NewClient(&http.Client{Transport: atr})
+	appClient.BaseURL = v.Client.BaseURL
+
+	installation, _, err := appClient.Apps.FindRepositoryInstallation(ctx, org, repo)
+	if err != nil {
+		return nil, fmt.Errorf("cannot find an installation of the GitHub App on %s: %w", key, err)
+	}
+	token, _, err := appClient.Apps.CreateInstallationToken(ctx, installation.GetID(), &github.InstallationTokenOptions{
+		Repositories: []string{repo},
+		Permissions:  &github.InstallationPermissions{Contents: github.String("read")},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot create a token for the GitHub App installation on %s: %w", key, err)
+	}
+
+	client := github.NewClient(oauth2.NewClient(ctx, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token.GetToken()})))
+	client.BaseURL = v.Client.BaseURL
+	if v.remoteClients == nil {
+		v.remoteClients = map[string]*github.Client{}
+	}
+	v.remoteClients[key] = client
+	return client, nil
+}
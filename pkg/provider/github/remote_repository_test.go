@@ -0,0 +1,202 @@
+package github
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	ghtesthelper "github.com/openshift-pipelines/pipelines-as-code/pkg/test/github"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestGetTaskURIFromRemoteRepository(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NilError(t, err)
+	privateKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	task := "apiVersion: tekton.dev/v1\nkind: Task\n"
+
+	tests := []struct {
+		name           string
+		installationID int64
+		installed      bool
+		extraRepos     string
+		wantErr        string
+	}{
+		{
+			name:           "fetched with a token of the other installation",
+			installationID: 1,
+			installed:      true,
+			extraRepos:     "owner/other, shared/library",
+		},
+		{
+			name:           "fetched from an allowed owner",
+			installationID: 1,
+			installed:      true,
+			extraRepos:     "Shared/*",
+		},
+		{
+			name:           "repository not allowed",
+			installationID: 1,
+			installed:      true,
+			extraRepos:     "shared/other,owner/*",
+			wantErr:        "the repository needs to be listed in the remote-tasks-extra-repos setting",
+		},
+		{
+			name:           "app not installed on the remote repository",
+			installationID: 1,
+			extraRepos:     "shared/library",
+			wantErr:        "cannot find an installation of the GitHub App on shared/library",
+		},
+		{
+			name:    "not a github app",
+			wantErr: "404",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			t.Setenv("SYSTEM_NAMESPACE", "pac")
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+				Secret: []*corev1.Secret{{
+					ObjectMeta: metav1.ObjectMeta{Name: secretName, Namespace: "pac"},
+					Data: map[string][]byte{
+						"github-application-id": []byte("12345"),
+						"github-private-key":    privateKey,
+					},
+				}},
+			})
+			fakeclient, mux, _, teardown := ghtesthelper.SetupGH()
+			defer teardown()
+
+			mux.HandleFunc("/repos/shared/library/installation", func(w http.ResponseWriter, _ *http.Request) {
+				if !tt.installed {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				fmt.Fprint(w, `{"id": 42}`)
+			})
+			mux.HandleFunc("/app/installations/42/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, r.Method, http.MethodPost)
+				fmt.Fprint(w, `{"token": "remote-token"}`)
+			})
+			mux.HandleFunc("/repos/shared/library/contents/tasks/build.yaml", func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Authorization") != "Bearer remote-token" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				fmt.Fprint(w, `{"name": "build.yaml", "path": "tasks/build.yaml", "sha": "shatask"}`)
+			})
+			mux.HandleFunc("/repos/shared/library/git/blobs/shatask", func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, r.Header.Get("Authorization"), "Bearer remote-token")
+				fmt.Fprintf(w, `{"content": "%s", "sha": "shatask"}`, base64.StdEncoding.EncodeToString([]byte(task)))
+			})
+
+			gvcs := &Provider{Client: fakeclient}
+			run := &params.Run{
+				Clients: clients.Clients{Kube: stdata.Kube},
+				Info:    info.Info{Pac: &info.PacOpts{Settings: &settings.Settings{RemoteTasksExtraRepos: tt.extraRepos}}},
+			}
+			event := &info.Event{
+				Organization:   "owner",
+				Repository:     "repo",
+				URL:            "https://github.com/owner/repo",
+				InstallationID: tt.installationID,
+			}
+			fetched, got, err := gvcs.GetTaskURI(ctx, run, event, "https://github.com/shared/library/blob/main/tasks/build.yaml")
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Assert(t, fetched)
+			assert.Equal(t, got, task)
+		})
+	}
+}
+
+// TestGetTaskURIFromRemoteRepositoriesConcurrently fetches remote tasks from
+// several other repositories at the same time, as the remote tasks of the
+// annotations are fetched, run it with -race.
+func TestGetTaskURIFromRemoteRepositoriesConcurrently(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NilError(t, err)
+	privateKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	ctx, _ := rtesting.SetupFakeContext(t)
+	t.Setenv("SYSTEM_NAMESPACE", "pac")
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+		Secret: []*corev1.Secret{{
+			ObjectMeta: metav1.ObjectMeta{Name: secretName, Namespace: "pac"},
+			Data: map[string][]byte{
+				"github-application-id": []byte("12345"),
+				"github-private-key":    privateKey,
+			},
+		}},
+	})
+	fakeclient, mux, _, teardown := ghtesthelper.SetupGH()
+	defer teardown()
+
+	repos := []string{"library", "catalog", "tasks", "pipelines", "steps", "hub"}
+	for i, repo := range repos {
+		repo, installationID := repo, 42+i
+		mux.HandleFunc(fmt.Sprintf("/repos/shared/%s/installation", repo), func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprintf(w, `{"id": %d}`, installationID)
+		})
+		mux.HandleFunc(fmt.Sprintf("/app/installations/%d/access_tokens", installationID), func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprintf(w, `{"token": "%s-token"}`, repo)
+		})
+		mux.HandleFunc(fmt.Sprintf("/repos/shared/%s/contents/task.yaml", repo), func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != fmt.Sprintf("Bearer %s-token", repo) {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			fmt.Fprintf(w, `{"name": "task.yaml", "path": "task.yaml", "sha": "sha%s"}`, repo)
+		})
+		mux.HandleFunc(fmt.Sprintf("/repos/shared/%s/git/blobs/sha%s", repo, repo), func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprintf(w, `{"content": "%s", "sha": "sha%s"}`, base64.StdEncoding.EncodeToString([]byte(repo)), repo)
+		})
+	}
+
+	gvcs := &Provider{Client: fakeclient}
+	run := &params.Run{
+		Clients: clients.Clients{Kube: stdata.Kube},
+		Info:    info.Info{Pac: &info.PacOpts{Settings: &settings.Settings{RemoteTasksExtraRepos: "shared/*"}}},
+	}
+	event := &info.Event{
+		Organization:   "owner",
+		Repository:     "repo",
+		URL:            "https://github.com/owner/repo",
+		InstallationID: 1,
+	}
+
+	got := make([]string, len(repos))
+	errs := make([]error, len(repos))
+	var wg sync.WaitGroup
+	for i, repo := range repos {
+		wg.Add(1)
+		go func(i int, repo string) {
+			defer wg.Done()
+			_, got[i], errs[i] = gvcs.GetTaskURI(ctx, run, event, fmt.Sprintf("https://github.com/shared/%s/blob/main/task.yaml", repo))
+		}(i, repo)
+	}
+	wg.Wait()
+	for i, repo := range repos {
+		assert.NilError(t, errs[i])
+		assert.Equal(t, got[i], repo)
+	}
+	assert.Equal(t, len(gvcs.remoteClients), len(repos))
+}
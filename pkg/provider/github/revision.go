@@ -0,0 +1,19 @@
+package github
+
+import (
+	"context"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+)
+
+// IsRevisionOnBranch returns true if the commit is the head of the branch or
+// one of its ancestors, the branch is then identical to or ahead of the
+// commit.
+func (v *Provider) IsRevisionOnBranch(ctx context.Context, event *info.Event, sha, branch string) (bool, error) {
+	comparison, _, err := v.Client.Repositories.CompareCommits(ctx, event.Organization, event.Repository, sha, branch, nil)
+	if err != nil {
+		return false, err
+	}
+	status := comparison.GetStatus()
+	return status == "identical" || status == "ahead", nil
+}
@@ -0,0 +1,39 @@
+package github
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	ghtesthelper "github.com/openshift-pipelines/pipelines-as-code/pkg/test/github"
+	"gotest.tools/v3/assert"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestIsRevisionOnBranch(t *testing.T) {
+	tests := []struct {
+		name   string
+		status string
+		want   bool
+	}{
+		{name: "head of the branch", status: "identical", want: true},
+		{name: "ancestor of the branch", status: "ahead", want: true},
+		{name: "not on the branch", status: "diverged", want: false},
+		{name: "ahead of the branch", status: "behind", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			fakeclient, mux, _, teardown := ghtesthelper.SetupGH()
+			defer teardown()
+			mux.HandleFunc("/repos/owner/repo/compare/abcdef...release-1.0", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, `{"status": "%s"}`, tt.status)
+			})
+			v := &Provider{Client: fakeclient}
+			got, err := v.IsRevisionOnBranch(ctx, &info.Event{Organization: "owner", Repository: "repo"}, "abcdef", "release-1.0")
+			assert.NilError(t, err)
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
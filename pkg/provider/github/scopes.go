@@ -0,0 +1,51 @@
+package github
+
+import (
+	"context"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+)
+
+// statusScopes are the classic token scopes allowing to set the commit status,
+// repo includes repo:status.
+var statusScopes = []string{"repo", "repo:status"}
+
+// webhookScopes are the classic token scopes allowing to manage the webhooks of
+// the repository, admin:repo_hook includes write:repo_hook.
+var webhookScopes = []string{"admin:repo_hook", "write:repo_hook"}
+
+// MissingTokenScopes checks the scopes of the classic token from the
+// X-OAuth-Scopes header GitHub returns on every API call, fine-grained tokens
+// don't have scopes and cannot be checked.
+func (v *Provider) MissingTokenScopes(ctx context.Context, manageWebhook bool) ([]string, error) {
+	_, resp, err := v.Client.Users.Get(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	header := resp.Header.Get("X-OAuth-Scopes")
+	if header == "" {
+		return nil, provider.ErrTokenScopesNotAvailable
+	}
+	scopes := map[string]bool{}
+	for _, scope := range strings.Split(header, ",") {
+		scopes[strings.TrimSpace(scope)] = true
+	}
+	var missing []string
+	if !hasAnyScope(scopes, statusScopes) {
+		missing = append(missing, "repo:status")
+	}
+	if manageWebhook && !hasAnyScope(scopes, webhookScopes) {
+		missing = append(missing, "write:repo_hook")
+	}
+	return missing, nil
+}
+
+func hasAnyScope(scopes map[string]bool, wanted []string) bool {
+	for _, s := range wanted {
+		if scopes[s] {
+			return true
+		}
+	}
+	return false
+}
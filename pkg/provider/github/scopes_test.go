@@ -0,0 +1,78 @@
+package github
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	ghtesthelper "github.com/openshift-pipelines/pipelines-as-code/pkg/test/github"
+	"gotest.tools/v3/assert"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestMissingTokenScopes(t *testing.T) {
+	tests := []struct {
+		name          string
+		scopes        string
+		manageWebhook bool
+		wantMissing   []string
+		wantErr       error
+	}{
+		{
+			name:   "repo scope",
+			scopes: "read:org, repo",
+		},
+		{
+			name:   "repo:status scope",
+			scopes: "repo:status",
+		},
+		{
+			name:        "no status scope",
+			scopes:      "read:org, public_repo",
+			wantMissing: []string{"repo:status"},
+		},
+		{
+			name:          "webhook scope",
+			scopes:        "repo, admin:repo_hook",
+			manageWebhook: true,
+		},
+		{
+			name:          "no webhook scope",
+			scopes:        "repo",
+			manageWebhook: true,
+			wantMissing:   []string{"write:repo_hook"},
+		},
+		{
+			name:          "no status and webhook scopes",
+			scopes:        "read:org",
+			manageWebhook: true,
+			wantMissing:   []string{"repo:status", "write:repo_hook"},
+		},
+		{
+			name:    "fine-grained token",
+			wantErr: provider.ErrTokenScopesNotAvailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			fakeclient, mux, _, teardown := ghtesthelper.SetupGH()
+			defer teardown()
+			mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+				if tt.scopes != "" {
+					w.Header().Set("X-OAuth-Scopes", tt.scopes)
+				}
+				_, _ = w.Write([]byte(`{"login": "user"}`))
+			})
+			v := &Provider{Client: fakeclient}
+			missing, err := v.MissingTokenScopes(ctx, tt.manageWebhook)
+			if tt.wantErr != nil {
+				assert.Assert(t, errors.Is(err, tt.wantErr))
+				return
+			}
+			assert.NilError(t, err)
+			assert.DeepEqual(t, missing, tt.wantMissing)
+		})
+	}
+}
@@ -2,15 +2,19 @@ package github
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"regexp"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/go-github/v49/github"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/action"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
 	kstatus "github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction/status"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
@@ -34,6 +38,64 @@ const taskStatusTemplate = `
 {{- end }}
 </table>`
 
+const (
+	// checkRunOutputMaxLength is the maximum number of characters GitHub
+	// accepts for the summary and the text of a check run output.
+	checkRunOutputMaxLength = 65535
+	// checkRunMaxAnnotations is the maximum number of annotations GitHub
+	// accepts on a single check run update.
+	checkRunMaxAnnotations = 50
+)
+
+// truncateCheckRunOutput truncates the output to the maximum length accepted
+// by GitHub, it keeps the beginning and the end of the output where the
+// failing steps are and adds a link to the full logs in between.
+func truncateCheckRunOutput(output, logURL string) string {
+	return truncateOutput(output, logURL, checkRunOutputMaxLength)
+}
+
+// truncateOutput truncates the output to maxLength characters like
+// truncateCheckRunOutput.
+func truncateOutput(output, logURL string, maxLength int) string {
+	if utf8.RuneCountInString(output) <= maxLength {
+		return output
+	}
+	marker := "\n\n---\n\n⚠️ The output is too long and has been truncated"
+	if logURL != "" {
+		marker += fmt.Sprintf(", see the [full logs](%s)", logURL)
+	}
+	marker += ".\n\n---\n\n"
+
+	runes := []rune(output)
+	available := maxLength - utf8.RuneCountInString(marker)
+	headLength := available / 3
+	tailLength := available - headLength
+	head := string(runes[:headLength])
+	tail := string(runes[len(runes)-tailLength:])
+	// cut on lines to not break the markdown more than needed
+	if i := strings.LastIndex(head, "\n"); i > 0 {
+		head = head[:i]
+	}
+	if i := strings.Index(tail, "\n"); i >= 0 && i < len(tail)-1 {
+		tail = tail[i+1:]
+	}
+	return head + marker + tail
+}
+
+// batchAnnotations splits the annotations in batches of the maximum number
+// of annotations GitHub accepts on a check run update.
+func batchAnnotations(annotations []*github.CheckRunAnnotation) [][]*github.CheckRunAnnotation {
+	batches := [][]*github.CheckRunAnnotation{}
+	for len(annotations) > checkRunMaxAnnotations {
+		batches = append(batches, annotations[:checkRunMaxAnnotations])
+		annotations = annotations[checkRunMaxAnnotations:]
+	}
+	if len(annotations) > 0 {
+		batches = append(batches, annotations)
+	}
+	return batches
+}
+
 func getCheckName(status provider.StatusOpts, pacopts *info.PacOpts) string {
 	if pacopts.ApplicationName != "" {
 		if status.OriginalPipelineRunName == "" {
@@ -75,7 +137,7 @@ func isSkippedCheckrun(run *github.CheckRun) bool {
 	if run == nil || run.Output == nil {
 		return false
 	}
-	if run.Output.Title != nil && *run.Output.Title == "Skipped" &&
+	if run.Output.Title != nil && strings.HasSuffix(*run.Output.Title, "Skipped") &&
 		run.Output.Summary != nil &&
 		strings.Contains(*run.Output.Summary, "is skipping this commit") {
 		return true
@@ -210,19 +272,30 @@ func (v *Provider) getOrUpdateCheckRunStatus(ctx context.Context, tekton version
 		}
 	}
 
-	text := statusOpts.Text
+	// the name of the check run identifies it, ie: in the branch protection
+	// rules, the display name of the PipelineRun is only in its title
+	title := statusOpts.Title
+	if statusOpts.PipelineRun != nil {
+		if displayName := formatting.PipelineRunDisplayName(statusOpts.PipelineRun); displayName != "" {
+			title = fmt.Sprintf("%s: %s", displayName, statusOpts.Title)
+		}
+	}
+	summary := truncateCheckRunOutput(statusOpts.Summary, statusOpts.DetailsURL)
 	checkRunOutput := &github.CheckRunOutput{
-		Title:   &statusOpts.Title,
-		Summary: &statusOpts.Summary,
+		Title:   &title,
+		Summary: &summary,
+		Text:    github.String(truncateCheckRunOutput(statusOpts.Text, statusOpts.DetailsURL)),
 	}
 
+	var annotationsBatches [][]*github.CheckRunAnnotation
 	if statusOpts.PipelineRun != nil {
 		if pacopts.ErrorDetection {
-			checkRunOutput.Annotations = v.getFailuresMessageAsAnnotations(ctx, statusOpts.PipelineRun, pacopts)
+			annotationsBatches = batchAnnotations(v.getFailuresMessageAsAnnotations(ctx, statusOpts.PipelineRun, pacopts))
 		}
 	}
-
-	checkRunOutput.Text = github.String(text)
+	if len(annotationsBatches) > 0 {
+		checkRunOutput.Annotations = annotationsBatches[0]
+	}
 
 	opts := github.UpdateCheckRunOptions{
 		Name:   getCheckName(statusOpts, pacopts),
@@ -241,12 +314,40 @@ func (v *Provider) getOrUpdateCheckRunStatus(ctx context.Context, tekton version
 		opts.CompletedAt = &github.Timestamp{Time: time.Now()}
 		opts.Conclusion = &statusOpts.Conclusion
 	}
-	if isPipelineRunCancelledOrStopped(statusOpts.PipelineRun) {
+	if opts.Conclusion != nil && isPipelineRunCancelledOrStopped(statusOpts.PipelineRun) {
 		opts.Conclusion = github.String("cancelled")
 	}
 
-	_, _, err = v.Client.Checks.UpdateCheckRun(ctx, runevent.Organization, runevent.Repository, *checkRunID, opts)
-	return err
+	if _, _, err = v.Client.Checks.UpdateCheckRun(ctx, runevent.Organization, runevent.Repository, *checkRunID, opts); err != nil {
+		return err
+	}
+
+	// GitHub adds the annotations to the existing ones, send the other batches
+	// with the same output
+	for i := 1; i < len(annotationsBatches); i++ {
+		if _, _, err = v.Client.Checks.UpdateCheckRun(ctx, runevent.Organization, runevent.Repository, *checkRunID, github.UpdateCheckRunOptions{
+			Name: opts.Name,
+			Output: &github.CheckRunOutput{
+				Title:       checkRunOutput.Title,
+				Summary:     checkRunOutput.Summary,
+				Annotations: annotationsBatches[i],
+			},
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isChecksAPIUnavailable returns true if the error means the check runs
+// cannot be used: forbidden when the App doesn't have the checks:write
+// permission or not found when the GitHub instance doesn't support them.
+func isChecksAPIUnavailable(err error) bool {
+	var errResp *github.ErrorResponse
+	if !errors.As(err, &errResp) || errResp.Response == nil {
+		return false
+	}
+	return errResp.Response.StatusCode == http.StatusForbidden || errResp.Response.StatusCode == http.StatusNotFound
 }
 
 func isPipelineRunCancelledOrStopped(run *tektonv1.PipelineRun) bool {
@@ -274,6 +375,10 @@ func metadataPatch(checkRunID *int64, logURL string) map[string]interface{} {
 
 // createStatusCommit use the classic/old statuses API which is available when we
 // don't have a github app token
+// maxStatusDescriptionLength is the maximum length of the description of a
+// commit status.
+const maxStatusDescriptionLength = 140
+
 func (v *Provider) createStatusCommit(ctx context.Context, runevent *info.Event, pacopts *info.PacOpts, status provider.StatusOpts) error {
 	var err error
 	now := time.Now()
@@ -297,14 +402,10 @@ func (v *Provider) createStatusCommit(ctx context.Context, runevent *info.Event,
 		runevent.Organization, runevent.Repository, runevent.SHA, ghstatus); err != nil {
 		return err
 	}
-	if status.Status == "completed" && status.Text != "" && runevent.EventType == "pull_request" {
-		_, _, err = v.Client.Issues.CreateComment(ctx, runevent.Organization, runevent.Repository,
-			runevent.PullRequestNumber,
-			&github.IssueComment{
-				Body: github.String(fmt.Sprintf("%s<br>%s", status.Summary, status.Text)),
-			},
-		)
-		if err != nil {
+	// without the checks API the details of the PipelineRuns are in a
+	// single comment on the pull request
+	if runevent.EventType == "pull_request" && runevent.PullRequestNumber > 0 {
+		if err = v.updateStatusComment(ctx, runevent, pacopts, status); err != nil {
 			return err
 		}
 	}
@@ -312,10 +413,32 @@ func (v *Provider) createStatusCommit(ctx context.Context, runevent *info.Event,
 	return nil
 }
 
+// SetCommitStatus sets a commit status on the commit of the event, the
+// description is truncated to the length GitHub accepts. The status is only
+// commented on a pull request event.
+func (v *Provider) SetCommitStatus(ctx context.Context, event *info.Event, pacOpts *info.PacOpts, status provider.StatusOpts) error {
+	if v.Client == nil {
+		return fmt.Errorf("cannot set status on github no token or url set")
+	}
+	if status.Conclusion == provider.ConclusionOverridden {
+		status.Conclusion = "neutral"
+	}
+	if title := []rune(status.Title); len(title) > maxStatusDescriptionLength {
+		status.Title = string(title[:maxStatusDescriptionLength-3]) + "..."
+	}
+	return v.createStatusCommit(ctx, event, pacOpts, status)
+}
+
 func (v *Provider) CreateStatus(ctx context.Context, tekton versioned.Interface, runevent *info.Event, pacopts *info.PacOpts, statusOpts provider.StatusOpts) error {
 	if v.Client == nil {
 		return fmt.Errorf("cannot set status on github no token or url set")
 	}
+	statusOpts = provider.BrandStatus(pacopts, statusOpts)
+
+	// a failing deployment should not prevent the statuses to be reported
+	if err := v.updateDeployment(ctx, tekton, runevent, pacopts, statusOpts); err != nil {
+		v.Logger.Warnf("cannot update the deployment of the PipelineRun %s: %v", statusOpts.PipelineRunName, err)
+	}
 
 	switch statusOpts.Conclusion {
 	case "success":
@@ -330,6 +453,11 @@ func (v *Provider) CreateStatus(ctx context.Context, tekton versioned.Interface,
 	case "neutral":
 		statusOpts.Title = "Unknown"
 		statusOpts.Summary = "doesn't know what happened with this commit."
+	case provider.ConclusionOverridden:
+		// a neutral check run doesn't block the merge of the pull request
+		statusOpts.Conclusion = "neutral"
+		statusOpts.Title = "Overridden"
+		statusOpts.Summary = "has failed but it has been <b>overridden</b>."
 	}
 
 	if statusOpts.Status == "in_progress" {
@@ -338,14 +466,20 @@ func (v *Provider) CreateStatus(ctx context.Context, tekton versioned.Interface,
 	}
 
 	onPr := ""
-	if statusOpts.OriginalPipelineRunName != "" {
-		onPr = "/" + statusOpts.OriginalPipelineRunName
+	if title := provider.PipelineRunTitle(statusOpts); title != "" {
+		onPr = "/" + title
 	}
-	statusOpts.Summary = fmt.Sprintf("%s%s %s", pacopts.ApplicationName, onPr, statusOpts.Summary)
+	statusOpts.Summary = fmt.Sprintf("%s%s %s", provider.ApplicationTitle(pacopts, true), onPr, statusOpts.Summary)
 
 	// If we have an installationID which mean we have a github apps and we can use the checkRun API
-	if runevent.InstallationID > 0 {
-		return v.getOrUpdateCheckRunStatus(ctx, tekton, runevent, pacopts, statusOpts)
+	if runevent.InstallationID > 0 && !v.checksUnavailable {
+		err := v.getOrUpdateCheckRunStatus(ctx, tekton, runevent, pacopts, statusOpts)
+		if !isChecksAPIUnavailable(err) {
+			return err
+		}
+		v.Logger.Warnf("cannot use the checks API, the GitHub App may lack the checks:write permission "+
+			"or the GitHub instance doesn't support check runs, falling back to the commit status API: %v", err)
+		v.checksUnavailable = true
 	}
 
 	// Otherwise use the update status commit API
@@ -0,0 +1,179 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v49/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+)
+
+const (
+	// statusCommentMarker identifies the comment summarizing the statuses of
+	// the PipelineRuns of a pull request, per application so two installs of
+	// Pipelines as Code don't edit each other comment.
+	statusCommentMarker = "<!-- pipelines-as-code-status-summary: %s -->"
+	statusCommentSHA    = "<!-- sha: %s -->"
+	// groupedStatusSection is the section of the grouped status of the
+	// PipelineRuns of a commit.
+	groupedStatusSection = "grouped-status"
+	// statusCommentSectionMaxLength is the maximum length of the output of
+	// a PipelineRun in the comment, so the statuses of several PipelineRuns
+	// fit in a comment.
+	statusCommentSectionMaxLength = checkRunOutputMaxLength / 8
+)
+
+// statusCommentLocks serializes the updates of the status comment of a pull
+// request, the statuses of its PipelineRuns are reported concurrently and the
+// comment is read, modified and written back.
+var statusCommentLocks provider.KeyedMutex
+
+var (
+	statusCommentSHARe     = regexp.MustCompile(`<!-- sha: (\S+) -->`)
+	statusCommentSectionRe = regexp.MustCompile(`(?s)<!-- pipelinerun: (.+?) -->\n(.*?)\n<!-- /pipelinerun -->`)
+)
+
+// statusComment is the comment summarizing the statuses of the PipelineRuns
+// of the latest commit of a pull request, the sections are the statuses of
+// the PipelineRuns by name.
+type statusComment struct {
+	sha      string
+	sections map[string]string
+}
+
+func parseStatusComment(body string) *statusComment {
+	c := &statusComment{sections: map[string]string{}}
+	if m := statusCommentSHARe.FindStringSubmatch(body); m != nil {
+		c.sha = m[1]
+	}
+	for _, m := range statusCommentSectionRe.FindAllStringSubmatch(body, -1) {
+		c.sections[m[1]] = m[2]
+	}
+	return c
+}
+
+func (c *statusComment) render(marker, title string) string {
+	names := make([]string, 0, len(c.sections))
+	for name := range c.sections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString(marker + "\n")
+	fmt.Fprintf(&b, statusCommentSHA+"\n", c.sha)
+	fmt.Fprintf(&b, "### %s status of %s\n", title, shortSHA(c.sha))
+	for _, name := range names {
+		fmt.Fprintf(&b, "\n<!-- pipelinerun: %s -->\n%s\n<!-- /pipelinerun -->\n", name, c.sections[name])
+	}
+	return b.String()
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+func statusIcon(conclusion string) string {
+	switch conclusion {
+	case "success":
+		return "✅"
+	case "failure", "error":
+		return "❌"
+	default:
+		return "⏳"
+	}
+}
+
+// statusCommentSection is the status of the PipelineRun in the comment, the
+// details are folded.
+func statusCommentSection(name string, status provider.StatusOpts) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "<details><summary>%s <b>%s</b>: %s</summary>\n\n%s", statusIcon(status.Conclusion), name, status.Title, status.Summary)
+	if status.Text != "" {
+		b.WriteString("<br>" + truncateOutput(status.Text, status.DetailsURL, statusCommentSectionMaxLength))
+	}
+	if status.DetailsURL != "" {
+		fmt.Fprintf(&b, "\n\n[Details](%s)", status.DetailsURL)
+	}
+	b.WriteString("\n</details>")
+	return b.String()
+}
+
+// updateStatusComment updates the status of the PipelineRun in the comment
+// summarizing the statuses of the PipelineRuns of the pull request, which is
+// edited in place instead of posting a new comment on every status. The
+// statuses of the previous commits are dropped when a new commit is pushed.
+// With the throttled comment strategy, the comment is only created once a
+// PipelineRun didn't succeed. The comment is looked up and updated under the
+// lock of the pull request so the concurrent updates don't create several
+// comments or overwrite each other sections.
+func (v *Provider) updateStatusComment(ctx context.Context, runevent *info.Event, pacopts *info.PacOpts, status provider.StatusOpts) error {
+	unlock := statusCommentLocks.Lock(fmt.Sprintf("%s/%s#%d", runevent.Organization, runevent.Repository, runevent.PullRequestNumber))
+	defer unlock()
+
+	marker := fmt.Sprintf(statusCommentMarker, pacopts.ApplicationName)
+	existing, err := v.findStatusComment(ctx, runevent, marker)
+	if err != nil {
+		return err
+	}
+
+	if existing == nil && provider.IsCommentThrottled(runevent) && !provider.NewCommentAllowed(status) {
+		return nil
+	}
+
+	comment := &statusComment{sections: map[string]string{}}
+	if existing != nil {
+		comment = parseStatusComment(existing.GetBody())
+	}
+	if comment.sha != runevent.SHA {
+		comment = &statusComment{sha: runevent.SHA, sections: map[string]string{}}
+	}
+	name := status.OriginalPipelineRunName
+	if name == "" {
+		name = status.PipelineRunName
+	}
+	title := provider.PipelineRunTitle(status)
+	if title == "" {
+		title = name
+	}
+	if name == "" {
+		// the grouped status of all the PipelineRuns
+		name, title = groupedStatusSection, pacopts.ApplicationName
+	}
+	comment.sections[name] = statusCommentSection(title, status)
+	body := &github.IssueComment{Body: github.String(comment.render(marker, provider.ApplicationTitle(pacopts, true)))}
+
+	if existing != nil {
+		_, _, err = v.Client.Issues.EditComment(ctx, runevent.Organization, runevent.Repository, existing.GetID(), body)
+		return err
+	}
+	_, _, err = v.Client.Issues.CreateComment(ctx, runevent.Organization, runevent.Repository, runevent.PullRequestNumber, body)
+	return err
+}
+
+func (v *Provider) findStatusComment(ctx context.Context, runevent *info.Event, marker string) (*github.IssueComment, error) {
+	opts := &github.IssueListCommentsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		comments, resp, err := v.Client.Issues.ListComments(ctx, runevent.Organization, runevent.Repository,
+			runevent.PullRequestNumber, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, comment := range comments {
+			if strings.HasPrefix(comment.GetBody(), marker) {
+				return comment, nil
+			}
+		}
+		if resp == nil || resp.NextPage == 0 {
+			return nil, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
@@ -0,0 +1,204 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-github/v49/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	ghtesthelper "github.com/openshift-pipelines/pipelines-as-code/pkg/test/github"
+	"gotest.tools/v3/assert"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestUpdateStatusComment(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	fakeclient, mux, _, teardown := ghtesthelper.SetupGH()
+	defer teardown()
+
+	// the comments of the pull request, keyed by id
+	comments := map[int64]string{1: "LGTM"}
+	var nextID int64 = 2
+	created := 0
+	mux.HandleFunc("/repos/owner/repo/issues/42/comments", func(rw http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			list := []*github.IssueComment{}
+			for id := int64(1); id < nextID; id++ {
+				if body, ok := comments[id]; ok {
+					list = append(list, &github.IssueComment{ID: github.Int64(id), Body: github.String(body)})
+				}
+			}
+			_ = json.NewEncoder(rw).Encode(list)
+		case http.MethodPost:
+			comment := &github.IssueComment{}
+			assert.NilError(t, json.NewDecoder(r.Body).Decode(comment))
+			comments[nextID] = comment.GetBody()
+			nextID++
+			created++
+			fmt.Fprint(rw, "{}")
+		}
+	})
+	mux.HandleFunc("/repos/owner/repo/issues/comments/2", func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Method, http.MethodPatch)
+		comment := &github.IssueComment{}
+		assert.NilError(t, json.NewDecoder(r.Body).Decode(comment))
+		comments[2] = comment.GetBody()
+		fmt.Fprint(rw, "{}")
+	})
+
+	v := &Provider{Client: fakeclient}
+	pacopts := &info.PacOpts{Settings: &settings.Settings{ApplicationName: "Pipelines as Code CI"}}
+	event := &info.Event{Organization: "owner", Repository: "repo", PullRequestNumber: 42, SHA: "abcdef123456"}
+	status := func(name, conclusion, title, text string) provider.StatusOpts {
+		return provider.StatusOpts{
+			PipelineRunName:         name + "-xyz",
+			OriginalPipelineRunName: name,
+			Conclusion:              conclusion,
+			Title:                   title,
+			Summary:                 name + " summary",
+			Text:                    text,
+		}
+	}
+
+	assert.NilError(t, v.updateStatusComment(ctx, event, pacopts, status("lint", "pending", "CI has Started", "")))
+	assert.NilError(t, v.updateStatusComment(ctx, event, pacopts, status("tests", "pending", "CI has Started", "")))
+	assert.NilError(t, v.updateStatusComment(ctx, event, pacopts, status("lint", "failure", "Failed", "lint failed here")))
+	assert.Equal(t, created, 1, "the comment should be edited in place")
+	body := comments[2]
+	assert.Assert(t, strings.HasPrefix(body, "<!-- pipelines-as-code-status-summary: Pipelines as Code CI -->"))
+	assert.Assert(t, strings.Contains(body, "### Pipelines as Code CI status of abcdef1"), body)
+	assert.Assert(t, strings.Contains(body, "❌ <b>lint</b>: Failed"), body)
+	assert.Assert(t, strings.Contains(body, "lint failed here"), body)
+	assert.Assert(t, strings.Contains(body, "⏳ <b>tests</b>: CI has Started"), body)
+	assert.Assert(t, !strings.Contains(body, "lint</b>: CI has Started"), body)
+	assert.Assert(t, strings.Index(body, "<b>lint</b>") < strings.Index(body, "<b>tests</b>"), body)
+	assert.Equal(t, comments[1], "LGTM")
+
+	// a new commit only shows its PipelineRuns
+	event.SHA = "987654fedcba"
+	assert.NilError(t, v.updateStatusComment(ctx, event, pacopts, status("tests", "success", "Success", "")))
+	assert.Equal(t, created, 1)
+	body = comments[2]
+	assert.Assert(t, strings.Contains(body, "status of 987654f"), body)
+	assert.Assert(t, strings.Contains(body, "✅ <b>tests</b>: Success"), body)
+	assert.Assert(t, !strings.Contains(body, "<b>lint</b>"), body)
+}
+
+func TestCreateStatusConcurrentComment(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	fakeclient, mux, _, teardown := ghtesthelper.SetupGH()
+	defer teardown()
+
+	var mutex sync.Mutex
+	comments := map[int64]string{}
+	var nextID int64 = 1
+	mux.HandleFunc("/repos/owner/repo/statuses/abcdef123456", func(rw http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(rw, "{}")
+	})
+	mux.HandleFunc("/repos/owner/repo/issues/42/comments", func(rw http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		switch r.Method {
+		case http.MethodGet:
+			list := []*github.IssueComment{}
+			for id := int64(1); id < nextID; id++ {
+				list = append(list, &github.IssueComment{ID: github.Int64(id), Body: github.String(comments[id])})
+			}
+			_ = json.NewEncoder(rw).Encode(list)
+		case http.MethodPost:
+			comment := &github.IssueComment{}
+			assert.NilError(t, json.NewDecoder(r.Body).Decode(comment))
+			comments[nextID] = comment.GetBody()
+			nextID++
+			fmt.Fprint(rw, "{}")
+		}
+	})
+	mux.HandleFunc("/repos/owner/repo/issues/comments/", func(rw http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		assert.Equal(t, r.Method, http.MethodPatch)
+		id, err := strconv.ParseInt(strings.TrimPrefix(r.URL.Path, "/repos/owner/repo/issues/comments/"), 10, 64)
+		assert.NilError(t, err)
+		comment := &github.IssueComment{}
+		assert.NilError(t, json.NewDecoder(r.Body).Decode(comment))
+		comments[id] = comment.GetBody()
+		fmt.Fprint(rw, "{}")
+	})
+
+	pacopts := &info.PacOpts{Settings: &settings.Settings{ApplicationName: "Pipelines as Code CI"}}
+	names := []string{"build", "lint", "tests", "e2e", "docs", "deploy"}
+	var wg sync.WaitGroup
+	for _, name := range names {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			// every PipelineRun is started by its own goroutine with its own
+			// provider, the same way the controller starts them
+			v := &Provider{Client: fakeclient}
+			event := &info.Event{
+				Organization: "owner", Repository: "repo", PullRequestNumber: 42,
+				SHA: "abcdef123456", EventType: "pull_request",
+			}
+			assert.NilError(t, v.CreateStatus(ctx, nil, event, pacopts, provider.StatusOpts{
+				PipelineRunName:         name + "-xyz",
+				OriginalPipelineRunName: name,
+				Status:                  "in_progress",
+				Conclusion:              "pending",
+			}))
+		}(name)
+	}
+	wg.Wait()
+
+	assert.Equal(t, len(comments), 1, "a single status comment should be created")
+	for _, name := range names {
+		assert.Assert(t, strings.Contains(comments[1], "<b>"+name+"</b>"), "%s is missing from the comment: %s", name, comments[1])
+	}
+}
+
+func TestUpdateStatusCommentThrottled(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	fakeclient, mux, _, teardown := ghtesthelper.SetupGH()
+	defer teardown()
+	created := 0
+	mux.HandleFunc("/repos/owner/repo/issues/42/comments", func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			created++
+			fmt.Fprint(rw, "{}")
+			return
+		}
+		fmt.Fprint(rw, "[]")
+	})
+
+	v := &Provider{Client: fakeclient}
+	pacopts := &info.PacOpts{Settings: &settings.Settings{ApplicationName: "Pipelines as Code CI"}}
+	event := info.NewEvent()
+	event.Organization, event.Repository, event.PullRequestNumber = "owner", "repo", 42
+	event.Provider.CommentStrategy = "throttled"
+
+	assert.NilError(t, v.updateStatusComment(ctx, event, pacopts,
+		provider.StatusOpts{OriginalPipelineRunName: "lint", Status: "in_progress", Conclusion: "pending"}))
+	assert.NilError(t, v.updateStatusComment(ctx, event, pacopts,
+		provider.StatusOpts{OriginalPipelineRunName: "lint", Status: "completed", Conclusion: "success"}))
+	assert.Equal(t, created, 0)
+	assert.NilError(t, v.updateStatusComment(ctx, event, pacopts,
+		provider.StatusOpts{OriginalPipelineRunName: "tests", Status: "completed", Conclusion: "failure"}))
+	assert.Equal(t, created, 1)
+}
+
+func TestParseStatusComment(t *testing.T) {
+	c := &statusComment{sha: "sha", sections: map[string]string{
+		"a":              "<details>a\nmultiline</details>",
+		"grouped-status": "grouped",
+	}}
+	parsed := parseStatusComment(c.render("<!-- marker -->", "CI"))
+	assert.DeepEqual(t, parsed.sections, c.sections)
+	assert.Equal(t, parsed.sha, "sha")
+}
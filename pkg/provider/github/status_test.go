@@ -200,6 +200,31 @@ func TestGithubProviderCreateStatus(t *testing.T) {
 			want:                 &github.CheckRun{ID: &resultid},
 			wantErr:              false,
 		},
+		{
+			name: "success with a display name",
+			args: args{
+				runevent:    runEvent,
+				status:      "completed",
+				conclusion:  "success",
+				text:        "Yay",
+				detailsURL:  "https://cireport.com",
+				titleSubstr: "Unit tests: Success",
+				githubApps:  true,
+			},
+			pr: &tektonv1.PipelineRun{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: prname,
+					Labels: map[string]string{
+						keys.CheckRunID: strconv.Itoa(int(checkrunid)),
+					},
+					Annotations: map[string]string{
+						keys.DisplayName: "Unit tests",
+					},
+				},
+			},
+			want:    &github.CheckRun{ID: &resultid},
+			wantErr: false,
+		},
 		{
 			name: "success coming from webhook",
 			args: args{
@@ -432,13 +457,17 @@ func TestGithubProvidercreateStatusCommit(t *testing.T) {
 				body, _ := io.ReadAll(r.Body)
 				assert.Check(t, strings.Contains(string(body), fmt.Sprintf(`"state":"%s"`, tt.expectedConclusion)))
 			})
-			if tt.status.Status == "completed" {
-				mux.HandleFunc(fmt.Sprintf("/repos/%s/%s/issues/%d/comments",
-					tt.event.Organization, tt.event.Repository, issuenumber), func(rw http.ResponseWriter, r *http.Request) {
-					body, _ := io.ReadAll(r.Body)
-					assert.Equal(t, fmt.Sprintf(`{"body":"%s<br>%s"}`, tt.status.Summary, tt.status.Text)+"\n", string(body))
-				})
-			}
+			mux.HandleFunc(fmt.Sprintf("/repos/%s/%s/issues/%d/comments",
+				tt.event.Organization, tt.event.Repository, issuenumber), func(rw http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodGet {
+					fmt.Fprint(rw, "[]")
+					return
+				}
+				body, _ := io.ReadAll(r.Body)
+				assert.Check(t, strings.Contains(string(body), tt.status.Summary))
+				assert.Check(t, strings.Contains(string(body), tt.status.Text))
+				fmt.Fprint(rw, "{}")
+			})
 
 			ctx, _ := rtesting.SetupFakeContext(t)
 			provider := &Provider{
@@ -566,3 +595,146 @@ func TestProviderGetExistingCheckRunID(t *testing.T) {
 		})
 	}
 }
+
+func TestTruncateCheckRunOutput(t *testing.T) {
+	short := "short output"
+	assert.Equal(t, truncateCheckRunOutput(short, "https://logs"), short)
+
+	head := "<table>\n" + strings.Repeat("task succeeded\n", 3000)
+	tail := strings.Repeat("building things\n", 3000) + "step-build failed: exit code 1\n"
+	output := head + tail
+	assert.Assert(t, len(output) > checkRunOutputMaxLength)
+
+	truncated := truncateCheckRunOutput(output, "https://logs")
+	assert.Assert(t, len([]rune(truncated)) <= checkRunOutputMaxLength)
+	assert.Assert(t, strings.HasPrefix(truncated, "<table>\n"))
+	assert.Assert(t, strings.HasSuffix(truncated, "step-build failed: exit code 1\n"))
+	assert.Assert(t, strings.Contains(truncated, "see the [full logs](https://logs)"))
+
+	multibytes := strings.Repeat("✅ done\n", checkRunOutputMaxLength/3)
+	assert.Assert(t, len([]rune(truncateCheckRunOutput(multibytes, ""))) <= checkRunOutputMaxLength)
+}
+
+func TestBatchAnnotations(t *testing.T) {
+	makeAnnotations := func(n int) []*github.CheckRunAnnotation {
+		annotations := []*github.CheckRunAnnotation{}
+		for i := 0; i < n; i++ {
+			annotations = append(annotations, &github.CheckRunAnnotation{StartLine: github.Int(i)})
+		}
+		return annotations
+	}
+	assert.Equal(t, len(batchAnnotations(nil)), 0)
+	assert.Equal(t, len(batchAnnotations(makeAnnotations(50))), 1)
+
+	batches := batchAnnotations(makeAnnotations(120))
+	assert.Equal(t, len(batches), 3)
+	assert.Equal(t, len(batches[0]), 50)
+	assert.Equal(t, len(batches[2]), 20)
+	assert.Equal(t, batches[2][19].GetStartLine(), 119)
+}
+
+func TestGithubProviderCheckRunTruncatedOutput(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	fakeclient, mux, _, teardown := ghtesthelper.SetupGH()
+	defer teardown()
+	cnx := New()
+	cnx.Client = fakeclient
+
+	var sent github.UpdateCheckRunOptions
+	mux.HandleFunc("/repos/check/info/check-runs/555", func(w http.ResponseWriter, r *http.Request) {
+		assert.NilError(t, json.NewDecoder(r.Body).Decode(&sent))
+		_, _ = fmt.Fprint(w, `{"id": 555}`)
+	})
+	pr := &tektonv1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "pr1",
+			Labels: map[string]string{keys.CheckRunID: "555"},
+		},
+	}
+	event := &info.Event{Organization: "check", Repository: "info", SHA: "sha"}
+	err := cnx.getOrUpdateCheckRunStatus(ctx, nil, event, &info.PacOpts{Settings: &settings.Settings{}}, provider.StatusOpts{
+		PipelineRun:     pr,
+		PipelineRunName: "pr1",
+		Status:          "completed",
+		Conclusion:      "failure",
+		DetailsURL:      "https://logs/pr1",
+		Text:            strings.Repeat("very verbose line\n", 10000),
+	})
+	assert.NilError(t, err)
+	assert.Assert(t, len([]rune(sent.Output.GetText())) <= checkRunOutputMaxLength)
+	assert.Assert(t, strings.Contains(sent.Output.GetText(), "https://logs/pr1"))
+}
+
+func TestGithubProviderCheckRunFallback(t *testing.T) {
+	tests := []struct {
+		name           string
+		checkRunStatus int
+		wantFallback   bool
+		wantErr        bool
+	}{
+		{
+			name:           "app without the checks permission",
+			checkRunStatus: http.StatusForbidden,
+			wantFallback:   true,
+		},
+		{
+			name:           "instance without check runs",
+			checkRunStatus: http.StatusNotFound,
+			wantFallback:   true,
+		},
+		{
+			name:           "server error is not a fallback",
+			checkRunStatus: http.StatusInternalServerError,
+			wantErr:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			fakeclient, mux, _, teardown := ghtesthelper.SetupGH()
+			defer teardown()
+			fakelogger, _ := logger.GetLogger()
+			cnx := New()
+			cnx.Client = fakeclient
+			cnx.Logger = fakelogger
+
+			checkRunCalls := 0
+			mux.HandleFunc("/repos/check/info/check-runs/555", func(w http.ResponseWriter, r *http.Request) {
+				checkRunCalls++
+				w.WriteHeader(tt.checkRunStatus)
+				_, _ = fmt.Fprint(w, `{"message": "Resource not accessible by integration"}`)
+			})
+			var commitStatus github.RepoStatus
+			mux.HandleFunc("/repos/check/info/statuses/sha", func(w http.ResponseWriter, r *http.Request) {
+				assert.NilError(t, json.NewDecoder(r.Body).Decode(&commitStatus))
+				_, _ = fmt.Fprint(w, `{}`)
+			})
+
+			event := &info.Event{Organization: "check", Repository: "info", SHA: "sha", InstallationID: 1}
+			statusOpts := provider.StatusOpts{
+				PipelineRun: &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{
+					Name:   "pr1",
+					Labels: map[string]string{keys.CheckRunID: "555"},
+				}},
+				PipelineRunName:         "pr1",
+				OriginalPipelineRunName: "pr",
+				Status:                  "completed",
+				Conclusion:              "success",
+			}
+			pacopts := &info.PacOpts{Settings: &settings.Settings{ApplicationName: "CI"}}
+			err := cnx.CreateStatus(ctx, nil, event, pacopts, statusOpts)
+			if tt.wantErr {
+				assert.Assert(t, err != nil)
+				assert.Equal(t, commitStatus.GetState(), "")
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, commitStatus.GetState(), "success")
+			assert.Equal(t, commitStatus.GetContext(), "CI / pr")
+
+			// the check runs are not tried again once they are known to be unavailable
+			assert.NilError(t, cnx.CreateStatus(ctx, nil, event, pacopts, statusOpts))
+			assert.Equal(t, checkRunCalls, 1)
+		})
+	}
+}
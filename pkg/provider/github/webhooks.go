@@ -0,0 +1,46 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/v49/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+)
+
+// DeleteWebhooks deletes the webhooks of the repository pointing to the
+// controller URL, the webhook of the GitHub App is not a repository webhook
+// and is left alone.
+func (v *Provider) DeleteWebhooks(ctx context.Context, repoURL, controllerURL string) (int, error) {
+	owner, repo, err := formatting.GetRepoOwnerSplitted(repoURL)
+	if err != nil {
+		return 0, err
+	}
+	hooks := []*github.Hook{}
+	opt := &github.ListOptions{PerPage: 100}
+	for {
+		page, resp, err := v.Client.Repositories.ListHooks(ctx, owner, repo, opt)
+		if err != nil {
+			return 0, fmt.Errorf("cannot list the webhooks of %s/%s: %w", owner, repo, err)
+		}
+		hooks = append(hooks, page...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	deleted := 0
+	for _, hook := range hooks {
+		hookURL, _ := hook.Config["url"].(string)
+		if !provider.IsControllerWebhook(hookURL, controllerURL) {
+			continue
+		}
+		if _, err := v.Client.Repositories.DeleteHook(ctx, owner, repo, hook.GetID()); err != nil {
+			return deleted, fmt.Errorf("cannot delete the webhook %d of %s/%s: %w", hook.GetID(), owner, repo, err)
+		}
+		deleted++
+	}
+	return deleted, nil
+}
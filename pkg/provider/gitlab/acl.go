@@ -72,5 +72,11 @@ func (v *Provider) IsAllowed(ctx context.Context, event *info.Event) (bool, erro
 		return true, nil
 	}
 
+	// only the members can override failed PipelineRuns, not the users
+	// allowed to run the CI with /ok-to-test
+	if event.OverridePipelineRuns {
+		return false, nil
+	}
+
 	return v.checkOkToTestCommentFromApprovedMember(event, 1)
 }
@@ -0,0 +1,22 @@
+package gitlab
+
+import (
+	"context"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/xanzy/go-gitlab"
+)
+
+// approvalActions are the merge request actions sent when a user approves
+// it, approval when more approvals are required and approved otherwise.
+var approvalActions = []string{"approval", "approved"}
+
+// CountApprovals returns the number of users who have approved the merge
+// request.
+func (v *Provider) CountApprovals(ctx context.Context, event *info.Event) (int, error) {
+	approvals, _, err := v.Client.MergeRequestApprovals.GetConfiguration(event.TargetProjectID, event.PullRequestNumber, gitlab.WithContext(ctx))
+	if err != nil {
+		return 0, err
+	}
+	return len(approvals.ApprovedBy), nil
+}
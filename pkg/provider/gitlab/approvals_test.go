@@ -0,0 +1,25 @@
+package gitlab
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	thelp "github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gitlab/test"
+	"gotest.tools/v3/assert"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestCountApprovals(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	client, mux, tearDown := thelp.Setup(ctx, t)
+	defer tearDown()
+	mux.HandleFunc("/projects/10/merge_requests/42/approvals", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"approved_by": [{"user": {"username": "alice"}}, {"user": {"username": "bob"}}]}`)
+	})
+	v := &Provider{Client: client}
+	approvals, err := v.CountApprovals(ctx, &info.Event{TargetProjectID: 10, PullRequestNumber: 42})
+	assert.NilError(t, err)
+	assert.Equal(t, approvals, 2)
+}
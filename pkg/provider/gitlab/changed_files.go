@@ -0,0 +1,103 @@
+package gitlab
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	"github.com/xanzy/go-gitlab"
+)
+
+// changedFilesPerPage is the maximum number of diffs or commits the API
+// returns in a page.
+const changedFilesPerPage = 100
+
+// changedFile only decodes the paths of a diff returned by the API, the diff
+// itself (which can be huge or meaningless for binary files) is skipped by
+// the decoder rather than held in memory.
+type changedFile struct {
+	NewPath string `json:"new_path"`
+	OldPath string `json:"old_path"`
+}
+
+// addChangedFiles adds the paths of the diffs to the changed files, the old
+// path of a renamed file is added too since moving a file out of a directory
+// is a change of that directory.
+func addChangedFiles(changed *provider.ChangedFiles, files []changedFile) {
+	for _, file := range files {
+		changed.Add(file.NewPath, file.OldPath)
+	}
+}
+
+// commitChangedFiles adds the files changed by a commit to the changed files,
+// going through all the pages of the diff.
+func (v *Provider) commitChangedFiles(sha string, changed *provider.ChangedFiles) error {
+	u := fmt.Sprintf("projects/%d/repository/commits/%s/diff", v.sourceProjectID, url.PathEscape(sha))
+	opt := &gitlab.ListOptions{PerPage: changedFilesPerPage, Page: 1}
+	for opt.Page != 0 {
+		req, err := v.Client.NewRequest(http.MethodGet, u, opt, nil)
+		if err != nil {
+			return err
+		}
+		files := []changedFile{}
+		resp, err := v.Client.Do(req, &files)
+		if err != nil {
+			return err
+		}
+		addChangedFiles(changed, files)
+		opt.Page = resp.NextPage
+	}
+	return nil
+}
+
+// pushChangedFiles returns the files changed by a push. The files of all the
+// commits of the push are listed with a single call to the compare API. The
+// commits are only walked one by one when GitLab gives up on the comparison
+// because it is too large, or the head commit used when the push creates a
+// new branch.
+func (v *Provider) pushChangedFiles(runevent *info.Event) ([]string, error) {
+	changed := &provider.ChangedFiles{}
+	if provider.IsZeroSHA(runevent.BeforeSHA) {
+		if err := v.commitChangedFiles(runevent.SHA, changed); err != nil {
+			return []string{}, err
+		}
+		return changed.Paths(), nil
+	}
+
+	req, err := v.Client.NewRequest(http.MethodGet, fmt.Sprintf("projects/%d/repository/compare", v.sourceProjectID),
+		&gitlab.CompareOptions{From: gitlab.String(runevent.BeforeSHA), To: gitlab.String(runevent.SHA)}, nil)
+	if err != nil {
+		return []string{}, err
+	}
+	comparison := struct {
+		Diffs          []changedFile `json:"diffs"`
+		CompareTimeout bool          `json:"compare_timeout"`
+	}{}
+	if _, err := v.Client.Do(req, &comparison); err != nil {
+		return []string{}, err
+	}
+	if !comparison.CompareTimeout {
+		addChangedFiles(changed, comparison.Diffs)
+		return changed.Paths(), nil
+	}
+
+	opt := &gitlab.ListCommitsOptions{
+		RefName:     gitlab.String(fmt.Sprintf("%s..%s", runevent.BeforeSHA, runevent.SHA)),
+		ListOptions: gitlab.ListOptions{PerPage: changedFilesPerPage, Page: 1},
+	}
+	for opt.Page != 0 {
+		commits, resp, err := v.Client.Commits.ListCommits(v.sourceProjectID, opt)
+		if err != nil {
+			return []string{}, err
+		}
+		for _, commit := range commits {
+			if err := v.commitChangedFiles(commit.ID, changed); err != nil {
+				return []string{}, err
+			}
+		}
+		opt.Page = resp.NextPage
+	}
+	return changed.Paths(), nil
+}
@@ -0,0 +1,87 @@
+package gitlab
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	thelp "github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gitlab/test"
+	"gotest.tools/v3/assert"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestPushChangedFiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		event    *info.Event
+		handlers map[string]func(rw http.ResponseWriter, r *http.Request)
+		want     []string
+	}{
+		{
+			name:  "compare the push",
+			event: &info.Event{TriggerTarget: "push", SHA: "after", BeforeSHA: "before"},
+			handlers: map[string]func(rw http.ResponseWriter, r *http.Request){
+				"/projects/0/repository/compare": func(rw http.ResponseWriter, r *http.Request) {
+					assert.Equal(t, r.URL.Query().Get("from"), "before")
+					assert.Equal(t, r.URL.Query().Get("to"), "after")
+					fmt.Fprint(rw, `{"diffs": [
+						{"new_path": "first.txt", "old_path": "first.txt", "diff": "@@ -1 +1 @@"},
+						{"new_path": "new/name.go", "old_path": "old/name.go", "renamed_file": true}
+					]}`)
+				},
+			},
+			want: []string{"first.txt", "new/name.go", "old/name.go"},
+		},
+		{
+			name:  "push creating a branch",
+			event: &info.Event{TriggerTarget: "push", SHA: "after", BeforeSHA: "0000000000000000000000000000000000000000"},
+			handlers: map[string]func(rw http.ResponseWriter, r *http.Request){
+				"/projects/0/repository/commits/after/diff": func(rw http.ResponseWriter, r *http.Request) {
+					if r.URL.Query().Get("page") == "2" {
+						fmt.Fprint(rw, `[{"new_path": "second.yaml", "old_path": "second.yaml"}]`)
+						return
+					}
+					rw.Header().Set("X-Next-Page", "2")
+					fmt.Fprint(rw, `[{"new_path": "first.txt", "old_path": "first.txt"}]`)
+				},
+			},
+			want: []string{"first.txt", "second.yaml"},
+		},
+		{
+			name:  "comparison timing out walks the commits",
+			event: &info.Event{TriggerTarget: "push", SHA: "after", BeforeSHA: "before"},
+			handlers: map[string]func(rw http.ResponseWriter, r *http.Request){
+				"/projects/0/repository/compare": func(rw http.ResponseWriter, r *http.Request) {
+					fmt.Fprint(rw, `{"diffs": [], "compare_timeout": true}`)
+				},
+				"/projects/0/repository/commits": func(rw http.ResponseWriter, r *http.Request) {
+					assert.Equal(t, r.URL.Query().Get("ref_name"), "before..after")
+					fmt.Fprint(rw, `[{"id": "one"}, {"id": "two"}]`)
+				},
+				"/projects/0/repository/commits/one/diff": func(rw http.ResponseWriter, r *http.Request) {
+					fmt.Fprint(rw, `[{"new_path": "first.txt", "old_path": "first.txt"}]`)
+				},
+				"/projects/0/repository/commits/two/diff": func(rw http.ResponseWriter, r *http.Request) {
+					fmt.Fprint(rw, `[{"new_path": "first.txt", "old_path": "first.txt"}, {"new_path": "image.png", "old_path": "image.png"}]`)
+				},
+			},
+			want: []string{"first.txt", "image.png"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			fakeclient, mux, teardown := thelp.Setup(ctx, t)
+			defer teardown()
+			for path, handler := range tt.handlers {
+				mux.HandleFunc(path, handler)
+			}
+
+			v := &Provider{Client: fakeclient}
+			got, err := v.GetFiles(ctx, tt.event)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, got, tt.want)
+		})
+	}
+}
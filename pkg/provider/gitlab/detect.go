@@ -4,12 +4,37 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"regexp"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
 	"github.com/xanzy/go-gitlab"
 	"go.uber.org/zap"
 )
 
+// mergeTrainRefRe matches the refs of the merged result of a merge request in
+// a merge train, refs/merge-requests/<iid>/train. The merged results pipelines
+// on refs/merge-requests/<iid>/merge are created on the same merge request
+// updates the merge request hook is sent for, the pull_request runs are
+// already triggered by it.
+var mergeTrainRefRe = regexp.MustCompile(`^refs/merge-requests/\d+/train$`)
+
+// mergeTrainPipelineStatus is the status of the GitLab pipeline we act on,
+// the first one a pipeline goes through once created, so we run only once per
+// merged result while the pipeline hook is sent on every status change.
+const mergeTrainPipelineStatus = "pending"
+
+// isMergeTrainPipeline returns true if the pipeline event is for the merged
+// result of a merge request in a merge train we should run on.
+func isMergeTrainPipeline(event *gitlab.PipelineEvent) (bool, string) {
+	if event.ObjectAttributes.Source != "merge_request_event" || !mergeTrainRefRe.MatchString(event.ObjectAttributes.Ref) {
+		return false, fmt.Sprintf("not a merge train pipeline: %q", event.ObjectAttributes.Ref)
+	}
+	if event.ObjectAttributes.Status != mergeTrainPipelineStatus {
+		return false, fmt.Sprintf("merge train pipeline status is %q not %q", event.ObjectAttributes.Status, mergeTrainPipelineStatus)
+	}
+	return true, ""
+}
+
 // Detect processes event and detect if it is a gitlab event, whether to process or reject it
 // returns (if is a GL event, whether to process or reject, logger with event metadata,, error if any occurred)
 func (v *Provider) Detect(req *http.Request, payload string, logger *zap.SugaredLogger) (bool, bool, *zap.SugaredLogger, string, error) {
@@ -40,10 +65,16 @@ func (v *Provider) Detect(req *http.Request, payload string, logger *zap.Sugared
 		if provider.Valid(gitEvent.ObjectAttributes.Action, []string{"open", "update", "reopen"}) {
 			return setLoggerAndProceed(true, "", nil)
 		}
+		if provider.Valid(gitEvent.ObjectAttributes.Action, approvalActions) {
+			return setLoggerAndProceed(true, "", nil)
+		}
 		return setLoggerAndProceed(false, fmt.Sprintf("not a merge event we care about: \"%s\"",
 			gitEvent.ObjectAttributes.Action), nil)
 	case *gitlab.PushEvent:
 		return setLoggerAndProceed(true, "", nil)
+	case *gitlab.PipelineEvent:
+		process, reason := isMergeTrainPipeline(gitEvent)
+		return setLoggerAndProceed(process, reason, nil)
 	case *gitlab.MergeCommentEvent:
 		if gitEvent.MergeRequest.State == "opened" {
 			if provider.IsTestRetestComment(gitEvent.ObjectAttributes.Note) {
@@ -55,6 +86,9 @@ func (v *Provider) Detect(req *http.Request, payload string, logger *zap.Sugared
 			if provider.IsCancelComment(gitEvent.ObjectAttributes.Note) {
 				return setLoggerAndProceed(true, "", nil)
 			}
+			if provider.IsOverrideComment(gitEvent.ObjectAttributes.Note) {
+				return setLoggerAndProceed(true, "", nil)
+			}
 		}
 		return setLoggerAndProceed(false, "not a gitops style merge comment event", nil)
 	default:
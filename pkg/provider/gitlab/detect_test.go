@@ -56,6 +56,20 @@ func TestProvider_Detect(t *testing.T) {
 			isGL:       true,
 			processReq: true,
 		},
+		{
+			name:       "merge request approval Event",
+			event:      strings.Replace(sample.MREventAsJSON(), `"action": "open"`, `"action": "approved"`, 1),
+			eventType:  gitlab.EventTypeMergeRequest,
+			isGL:       true,
+			processReq: true,
+		},
+		{
+			name:       "merge request close Event",
+			event:      strings.Replace(sample.MREventAsJSON(), `"action": "open"`, `"action": "close"`, 1),
+			eventType:  gitlab.EventTypeMergeRequest,
+			isGL:       true,
+			processReq: false,
+		},
 		{
 			name:       "issue note event with no valid comment",
 			event:      sample.NoteEventAsJSON("abc"),
@@ -105,6 +119,37 @@ func TestProvider_Detect(t *testing.T) {
 			isGL:       true,
 			processReq: true,
 		},
+		{
+			name:       "merge train pipeline event",
+			event:      sample.PipelineEventAsJSON("refs/merge-requests/1/train", "pending"),
+			eventType:  gitlab.EventTypePipeline,
+			isGL:       true,
+			processReq: true,
+		},
+		{
+			name:       "merged result pipeline event already handled by the merge request event",
+			event:      sample.PipelineEventAsJSON("refs/merge-requests/1/merge", "pending"),
+			eventType:  gitlab.EventTypePipeline,
+			isGL:       true,
+			processReq: false,
+			wantReason: `not a merge train pipeline: "refs/merge-requests/1/merge"`,
+		},
+		{
+			name:       "merge train pipeline event status change",
+			event:      sample.PipelineEventAsJSON("refs/merge-requests/1/train", "running"),
+			eventType:  gitlab.EventTypePipeline,
+			isGL:       true,
+			processReq: false,
+			wantReason: `merge train pipeline status is "running" not "pending"`,
+		},
+		{
+			name:       "branch pipeline event",
+			event:      sample.PipelineEventAsJSON("main", "pending"),
+			eventType:  gitlab.EventTypePipeline,
+			isGL:       true,
+			processReq: false,
+			wantReason: `not a merge train pipeline: "main"`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
@@ -45,6 +46,9 @@ type Provider struct {
 	pathWithNamespace string
 	repoURL           string
 	apiURL            string
+	// onMergedResult is set when running on the merged result of a merge
+	// request, the .tekton files are then taken from the merge commit.
+	onMergedResult bool
 }
 
 // GetTaskURI TODO: Implement me
@@ -72,10 +76,10 @@ func (v *Provider) Validate(_ context.Context, _ *params.Run, event *info.Event)
 // attached projects. But this doesn't seem to show in the API, so we
 // are just doing it the path_with_namespace to get the "org".
 //
-// Note that "orgs/groups" may have subgroups, so we get the first parts
-// as Orgs and the last element as Repo It's just a detail to show for
-// UI, we actually don't use this field for access or other logical
-// stuff.
+// Note that "orgs/groups" may have subgroups of arbitrary depth, so we get
+// all the first parts as Orgs (ie: group-sub-sub2) and the last element as
+// Repo. It's just a detail to show for UI, we actually don't use this field
+// for access or other logical stuff, the project is always accessed by its ID.
 func getOrgRepo(pathWithNamespace string) (string, string) {
 	org := filepath.Dir(pathWithNamespace)
 	org = strings.ReplaceAll(org, "/", "-")
@@ -90,7 +94,7 @@ func (v *Provider) GetConfig() *info.ProviderConfig {
 	}
 }
 
-func (v *Provider) SetClient(_ context.Context, run *params.Run, runevent *info.Event) error {
+func (v *Provider) SetClient(ctx context.Context, run *params.Run, runevent *info.Event) error {
 	var err error
 	if runevent.Provider.Token == "" {
 		return fmt.Errorf("no git_provider.secret has been set in the repo crd")
@@ -103,7 +107,7 @@ func (v *Provider) SetClient(_ context.Context, run *params.Run, runevent *info.
 	case runevent.Provider.URL != "":
 		apiURL = runevent.Provider.URL
 	case v.repoURL != "" && !strings.HasPrefix(v.repoURL, apiPublicURL):
-		apiURL = strings.ReplaceAll(v.repoURL, v.pathWithNamespace, "")
+		apiURL = strings.TrimSuffix(formatting.NormalizeRepoURL(v.repoURL), "/"+v.pathWithNamespace)
 	case runevent.URL != "":
 		burl, err := url.Parse(runevent.URL)
 		if err != nil {
@@ -116,7 +120,12 @@ func (v *Provider) SetClient(_ context.Context, run *params.Run, runevent *info.
 	}
 	v.apiURL = apiURL
 
-	v.Client, err = gitlab.NewClient(runevent.Provider.Token, gitlab.WithBaseURL(apiURL))
+	opts := []gitlab.ClientOptionFunc{gitlab.WithBaseURL(apiURL)}
+	ctx = provider.WithAPIClientConfig(ctx, run, v.GetConfig().Name)
+	if httpClient := provider.HTTPClient(ctx); httpClient != nil {
+		opts = append(opts, gitlab.WithHTTPClient(httpClient))
+	}
+	v.Client, err = gitlab.NewClient(runevent.Provider.Token, opts...)
 	if err != nil {
 		return err
 	}
@@ -141,6 +150,33 @@ func (v *Provider) SetClient(_ context.Context, run *params.Run, runevent *info.
 	return nil
 }
 
+// SetCommitStatus sets a commit status on the commit of the event, without
+// the note of CreateStatus on the merge request.
+func (v *Provider) SetCommitStatus(_ context.Context, event *info.Event, pacOpts *info.PacOpts, status provider.StatusOpts) error {
+	if v.Client == nil {
+		return fmt.Errorf("no gitlab client has been initiliazed, " +
+			"exiting... (hint: did you forget setting a secret on your repo?)")
+	}
+	var state gitlab.BuildStateValue
+	switch status.Conclusion {
+	case "skipped", "neutral":
+		state = gitlab.Canceled
+	case "failure":
+		state = gitlab.Failed
+	case "pending":
+		state = gitlab.Running
+	default:
+		state = gitlab.Success
+	}
+	_, _, err := v.Client.Commits.SetCommitStatus(event.SourceProjectID, event.SHA, &gitlab.SetCommitStatusOptions{
+		State:       state,
+		Name:        gitlab.String(pacOpts.ApplicationName),
+		TargetURL:   gitlab.String(status.DetailsURL),
+		Description: gitlab.String(status.Title),
+	})
+	return err
+}
+
 func (v *Provider) CreateStatus(_ context.Context, _ versioned.Interface, event *info.Event, pacOpts *info.PacOpts,
 	statusOpts provider.StatusOpts,
 ) error {
@@ -149,6 +185,9 @@ func (v *Provider) CreateStatus(_ context.Context, _ versioned.Interface, event
 		return fmt.Errorf("no gitlab client has been initiliazed, " +
 			"exiting... (hint: did you forget setting a secret on your repo?)")
 	}
+	statusOpts = provider.BrandStatus(pacOpts, statusOpts)
+	marker := provider.CommentMarker(pacOpts, statusOpts)
+	newCommentAllowed := provider.NewCommentAllowed(statusOpts)
 	switch statusOpts.Conclusion {
 	case "skipped":
 		statusOpts.Conclusion = "canceled"
@@ -165,6 +204,9 @@ func (v *Provider) CreateStatus(_ context.Context, _ versioned.Interface, event
 	case "completed":
 		statusOpts.Conclusion = "success"
 		statusOpts.Title = "completed"
+	case provider.ConclusionOverridden:
+		statusOpts.Conclusion = "success"
+		statusOpts.Title = "been overridden"
 	case "pending":
 		statusOpts.Conclusion = "running"
 	}
@@ -173,11 +215,11 @@ func (v *Provider) CreateStatus(_ context.Context, _ versioned.Interface, event
 	}
 
 	onPr := ""
-	if statusOpts.OriginalPipelineRunName != "" {
-		onPr = "/" + statusOpts.OriginalPipelineRunName
+	if title := provider.PipelineRunTitle(statusOpts); title != "" {
+		onPr = "/" + title
 	}
 	body := fmt.Sprintf("**%s%s** has %s\n\n%s\n\n<small>Full log available [here](%s)</small>",
-		pacOpts.ApplicationName, onPr, statusOpts.Title, statusOpts.Text, detailsURL)
+		provider.ApplicationTitle(pacOpts, true), onPr, statusOpts.Title, statusOpts.Text, detailsURL)
 
 	// in case we have access set the commit status, typically on MR from
 	// another users we won't have it but it would work on push or MR from a
@@ -193,8 +235,15 @@ func (v *Provider) CreateStatus(_ context.Context, _ versioned.Interface, event
 	//nolint: dogsled
 	_, _, _ = v.Client.Commits.SetCommitStatus(event.SourceProjectID, event.SHA, opt)
 
-	// only add a note when we are on a MR
+	// only add a note when we are on a MR, once all the PipelineRuns are done
+	// when the statuses are grouped
+	if provider.IsGroupedStatus(statusOpts) && statusOpts.Status != "completed" {
+		return nil
+	}
 	if event.EventType == "pull_request" || event.EventType == "Merge_Request" {
+		if provider.IsCommentThrottled(event) {
+			return v.updateThrottledNote(event, fmt.Sprintf("%s\n\n%s", body, marker), marker, newCommentAllowed)
+		}
 		mopt := &gitlab.CreateMergeRequestNoteOptions{Body: gitlab.String(body)}
 		_, _, err := v.Client.Notes.CreateMergeRequestNote(event.TargetProjectID, event.PullRequestNumber, mopt)
 		return err
@@ -202,6 +251,66 @@ func (v *Provider) CreateStatus(_ context.Context, _ versioned.Interface, event
 	return nil
 }
 
+// updateThrottledNote edits the note of the PipelineRun on the merge request
+// found with its marker, a new note is only created when create is true.
+func (v *Provider) updateThrottledNote(event *info.Event, body, marker string, create bool) error {
+	opt := &gitlab.ListMergeRequestNotesOptions{ListOptions: gitlab.ListOptions{PerPage: 100}}
+	for {
+		notes, resp, err := v.Client.Notes.ListMergeRequestNotes(event.TargetProjectID, event.PullRequestNumber, opt)
+		if err != nil {
+			return err
+		}
+		for _, note := range notes {
+			if strings.Contains(note.Body, marker) {
+				_, _, err := v.Client.Notes.UpdateMergeRequestNote(event.TargetProjectID, event.PullRequestNumber, note.ID,
+					&gitlab.UpdateMergeRequestNoteOptions{Body: gitlab.String(body)})
+				return err
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	if !create {
+		return nil
+	}
+	_, _, err := v.Client.Notes.CreateMergeRequestNote(event.TargetProjectID, event.PullRequestNumber,
+		&gitlab.CreateMergeRequestNoteOptions{Body: gitlab.String(body)})
+	return err
+}
+
+// headRef returns the ref where to get the files of the event from.
+func (v *Provider) headRef(runevent *info.Event) string {
+	if v.onMergedResult {
+		return runevent.SHA
+	}
+	return runevent.HeadBranch
+}
+
+// HasTektonDir checks if the directory exists at the head of the event by
+// listing a single entry of its tree.
+func (v *Provider) HasTektonDir(_ context.Context, event *info.Event, path string) (bool, error) {
+	if v.Client == nil {
+		return false, fmt.Errorf("no gitlab client has been initiliazed, " +
+			"exiting... (hint: did you forget setting a secret on your repo?)")
+	}
+
+	opt := &gitlab.ListTreeOptions{
+		ListOptions: gitlab.ListOptions{PerPage: 1},
+		Path:        gitlab.String(path),
+		Ref:         gitlab.String(v.headRef(event)),
+	}
+	objects, resp, err := v.Client.Repositories.ListTree(v.sourceProjectID, opt)
+	if resp != nil && resp.Response.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to list %s dir: %w", path, err)
+	}
+	return len(objects) > 0, nil
+}
+
 func (v *Provider) GetTektonDir(_ context.Context, event *info.Event, path string) (string, error) {
 	if v.Client == nil {
 		return "", fmt.Errorf("no gitlab client has been initiliazed, " +
@@ -210,7 +319,7 @@ func (v *Provider) GetTektonDir(_ context.Context, event *info.Event, path strin
 
 	opt := &gitlab.ListTreeOptions{
 		Path:      gitlab.String(path),
-		Ref:       gitlab.String(event.HeadBranch),
+		Ref:       gitlab.String(v.headRef(event)),
 		Recursive: gitlab.Bool(true),
 	}
 
@@ -232,14 +341,15 @@ func (v *Provider) concatAllYamlFiles(objects []*gitlab.TreeNode, runevent *info
 	for _, value := range objects {
 		if strings.HasSuffix(value.Name, ".yaml") ||
 			strings.HasSuffix(value.Name, ".yml") {
-			data, err := v.getObject(value.Path, runevent.HeadBranch, v.sourceProjectID)
+			// the id of a tree entry is the SHA of the blob of the file
+			data, err := provider.CachedFileContent(runevent.URL, value.ID, "", func() (string, error) {
+				data, err := v.getObject(value.Path, v.headRef(runevent), v.sourceProjectID)
+				return string(data), err
+			})
 			if err != nil {
 				return "", err
 			}
-			if allTemplates != "" && !strings.HasPrefix(string(data), "---") {
-				allTemplates += "---"
-			}
-			allTemplates += "\n" + string(data) + "\n"
+			allTemplates = provider.AppendTektonFile(allTemplates, value.Path, data)
 		}
 	}
 
@@ -261,7 +371,7 @@ func (v *Provider) getObject(fname, branch string, pid int) ([]byte, error) {
 }
 
 func (v *Provider) GetFileInsideRepo(_ context.Context, runevent *info.Event, path, _ string) (string, error) {
-	getobj, err := v.getObject(path, runevent.HeadBranch, v.sourceProjectID)
+	getobj, err := v.getObject(path, v.headRef(runevent), v.sourceProjectID)
 	if err != nil {
 		return "", err
 	}
@@ -308,15 +418,7 @@ func (v *Provider) GetFiles(ctx context.Context, runevent *info.Event) ([]string
 	}
 
 	if runevent.TriggerTarget == "push" {
-		pushChanges, _, err := v.Client.Commits.GetCommitDiff(v.sourceProjectID, runevent.SHA, &gitlab.GetCommitDiffOptions{})
-		if err != nil {
-			return []string{}, err
-		}
-		result := []string{}
-		for _, change := range pushChanges {
-			result = append(result, change.NewPath)
-		}
-		return result, nil
+		return v.pushChangedFiles(runevent)
 	}
 	return []string{}, nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"strings"
@@ -13,6 +14,8 @@ import (
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
 	thelp "github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gitlab/test"
+	testgitlab "github.com/openshift-pipelines/pipelines-as-code/pkg/test/gitlab"
+	testhttp "github.com/openshift-pipelines/pipelines-as-code/pkg/test/http"
 	"github.com/xanzy/go-gitlab"
 	"gotest.tools/v3/assert"
 	rtesting "knative.dev/pkg/reconciler/testing"
@@ -174,6 +177,74 @@ func TestCreateStatus(t *testing.T) {
 	}
 }
 
+func TestCreateStatusThrottled(t *testing.T) {
+	tests := []struct {
+		name        string
+		statusOpts  provider.StatusOpts
+		existing    bool
+		wantCreated bool
+		wantUpdated bool
+	}{
+		{
+			name:       "no comment when running",
+			statusOpts: provider.StatusOpts{Status: "in_progress", Conclusion: "pending", OriginalPipelineRunName: "pr"},
+		},
+		{
+			name:       "no comment on success",
+			statusOpts: provider.StatusOpts{Status: "completed", Conclusion: "success", OriginalPipelineRunName: "pr"},
+		},
+		{
+			name:        "comment on failure",
+			statusOpts:  provider.StatusOpts{Status: "completed", Conclusion: "failure", OriginalPipelineRunName: "pr"},
+			wantCreated: true,
+		},
+		{
+			name:        "existing comment edited on success",
+			statusOpts:  provider.StatusOpts{Status: "completed", Conclusion: "success", OriginalPipelineRunName: "pr"},
+			existing:    true,
+			wantUpdated: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			client, mux, tearDown := thelp.Setup(ctx, t)
+			defer tearDown()
+			created, updated := false, false
+			mux.HandleFunc("/projects/10/merge_requests/5/notes", func(rw http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodPost {
+					created = true
+					body, _ := io.ReadAll(r.Body)
+					assert.Assert(t, strings.Contains(string(body), "pipelines-as-code-comment: Test me/pr"))
+					fmt.Fprint(rw, `{"id": 8}`)
+					return
+				}
+				if tt.existing {
+					fmt.Fprint(rw, `[{"id": 6, "body": "hello"}, {"id": 7, "body": "failed\n\n<!-- pipelines-as-code-comment: Test me/pr -->"}]`)
+					return
+				}
+				fmt.Fprint(rw, `[]`)
+			})
+			mux.HandleFunc("/projects/10/merge_requests/5/notes/7", func(rw http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, r.Method, http.MethodPut)
+				updated = true
+				fmt.Fprint(rw, `{"id": 7}`)
+			})
+
+			v := &Provider{Client: client}
+			event := info.NewEvent()
+			event.EventType = "pull_request"
+			event.TargetProjectID = 10
+			event.PullRequestNumber = 5
+			event.Provider.CommentStrategy = "throttled"
+			pacOpts := &info.PacOpts{Settings: &settings.Settings{ApplicationName: "Test me"}}
+			assert.NilError(t, v.CreateStatus(ctx, nil, event, pacOpts, tt.statusOpts))
+			assert.Equal(t, created, tt.wantCreated)
+			assert.Equal(t, updated, tt.wantUpdated)
+		})
+	}
+}
+
 func TestGetCommitInfo(t *testing.T) {
 	ctx, _ := rtesting.SetupFakeContext(t)
 	client, _, tearDown := thelp.Setup(ctx, t)
@@ -235,6 +306,12 @@ func TestSetClientDetectAPIURL(t *testing.T) {
 	v.pathWithNamespace = "hello-this-is-me-ze/anotherproject"
 	event.URL, event.Provider.URL = "", ""
 	assert.Equal(t, fakehost, v.apiURL)
+	v.repoURL = fmt.Sprintf("%s/group/sub/sub2/project/", fakehost)
+	v.pathWithNamespace = "group/sub/sub2/project"
+	v.apiURL = ""
+	err = v.SetClient(ctx, nil, event)
+	assert.NilError(t, err)
+	assert.Equal(t, fakehost, v.apiURL)
 }
 
 func TestGetTektonDir(t *testing.T) {
@@ -337,6 +414,35 @@ func TestGetTektonDir(t *testing.T) {
 	}
 }
 
+func TestHasTektonDir(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	client, mux, tearDown := thelp.Setup(ctx, t)
+	defer tearDown()
+	mux.HandleFunc("/projects/10/repository/tree", func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.URL.Query().Get("ref"), "main")
+		assert.Equal(t, r.URL.Query().Get("per_page"), "1")
+		if r.URL.Query().Get("path") != ".tekton" {
+			rw.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(rw, `{"message": "404 Tree Not Found"}`)
+			return
+		}
+		fmt.Fprint(rw, `[{"name": "pr.yaml", "path": ".tekton/pr.yaml"}]`)
+	})
+
+	v := &Provider{sourceProjectID: 10}
+	event := &info.Event{HeadBranch: "main"}
+	_, err := v.HasTektonDir(ctx, event, ".tekton")
+	assert.ErrorContains(t, err, "no gitlab client has been initiliazed")
+
+	v.Client = client
+	found, err := v.HasTektonDir(ctx, event, ".tekton")
+	assert.NilError(t, err)
+	assert.Assert(t, found)
+	found, err = v.HasTektonDir(ctx, event, "nothere")
+	assert.NilError(t, err)
+	assert.Assert(t, !found)
+}
+
 func TestGetFileInsideRepo(t *testing.T) {
 	content := "hello moto"
 	ctx, _ := rtesting.SetupFakeContext(t)
@@ -518,3 +624,78 @@ func TestGetFiles(t *testing.T) {
 		})
 	}
 }
+
+func TestFakeServer(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	fake := testgitlab.NewFakeServer(ctx, t, 10)
+	fake.ServeFiles(t, "branch", map[string]string{
+		".tekton/pr.yaml": "kind: PipelineRun",
+		"README.md":       "hello",
+	})
+	v := &Provider{Client: fake.Client, sourceProjectID: 10}
+	event := &info.Event{
+		HeadBranch:        "branch",
+		SHA:               "sha",
+		EventType:         "pull_request",
+		SourceProjectID:   10,
+		TargetProjectID:   10,
+		PullRequestNumber: 1,
+	}
+
+	got, err := v.GetTektonDir(ctx, event, ".tekton")
+	assert.NilError(t, err)
+	assert.Assert(t, strings.Contains(got, "kind: PipelineRun"), got)
+	assert.Assert(t, !strings.Contains(got, "hello"), got)
+
+	pacOpts := &info.PacOpts{Settings: &settings.Settings{ApplicationName: "Pipelines as Code CI"}}
+	err = v.CreateStatus(ctx, nil, event, pacOpts, provider.StatusOpts{Conclusion: "success", Text: "Happy as a bunny"})
+	assert.NilError(t, err)
+	statuses := fake.Statuses(t)
+	assert.Equal(t, len(statuses), 1)
+	assert.Equal(t, statuses[0].State, gitlab.Success)
+	comments := fake.Comments(t)
+	assert.Equal(t, len(comments), 1)
+	assert.Assert(t, strings.Contains(comments[0], "Happy as a bunny"), comments[0])
+}
+
+func TestReplayCommitInfo(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	ctx = provider.WithHTTPClient(ctx, testhttp.NewCassetteClient(t, "testdata/cassettes/commit_info.json"))
+	v := &Provider{}
+	event := info.NewEvent()
+	event.Organization = "owner"
+	event.Repository = "repo"
+	event.HeadBranch = "main"
+	event.Provider.Token = "token"
+	event.Provider.URL = "https://gitlab.example.com"
+
+	assert.NilError(t, v.SetClient(ctx, nil, event))
+	assert.Equal(t, event.SourceProjectID, 42)
+	assert.Equal(t, event.DefaultBranch, "main")
+
+	assert.NilError(t, v.GetCommitInfo(ctx, event))
+	assert.Equal(t, event.SHA, "6dcb09b5b57875f334f61aebed695e2e4193db5e")
+	assert.Equal(t, event.SHATitle, "Add the pipelines")
+}
+
+func TestSetCommitStatus(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	fake := testgitlab.NewFakeServer(ctx, t, 10)
+	v := &Provider{Client: fake.Client, sourceProjectID: 10}
+	event := &info.Event{SHA: "sha", EventType: "pull_request", SourceProjectID: 10, PullRequestNumber: 1}
+	pacOpts := &info.PacOpts{Settings: &settings.Settings{ApplicationName: "Pipelines as Code CI"}}
+
+	err := v.SetCommitStatus(ctx, event, pacOpts, provider.StatusOpts{
+		Conclusion: "failure",
+		Title:      "pull-request has failed",
+		DetailsURL: "https://console/pr",
+	})
+	assert.NilError(t, err)
+	statuses := fake.Statuses(t)
+	assert.Equal(t, len(statuses), 1)
+	assert.Equal(t, statuses[0].State, gitlab.Failed)
+	assert.Equal(t, *statuses[0].Description, "pull-request has failed")
+	assert.Equal(t, *statuses[0].TargetURL, "https://console/pr")
+	// only the commit status is set, without a note on the merge request
+	assert.Equal(t, len(fake.Comments(t)), 0)
+}
@@ -45,6 +45,9 @@ func (v *Provider) ParsePayload(_ context.Context, _ *params.Run, request *http.
 		processedEvent.BaseBranch = gitEvent.ObjectAttributes.TargetBranch
 		processedEvent.PullRequestNumber = gitEvent.ObjectAttributes.IID
 		processedEvent.PullRequestTitle = gitEvent.ObjectAttributes.Title
+		for _, label := range gitEvent.Labels {
+			processedEvent.PullRequestLabel = append(processedEvent.PullRequestLabel, label.Title)
+		}
 		v.targetProjectID = gitEvent.Project.ID
 		v.sourceProjectID = gitEvent.ObjectAttributes.SourceProjectID
 		v.userID = gitEvent.User.ID
@@ -54,6 +57,10 @@ func (v *Provider) ParsePayload(_ context.Context, _ *params.Run, request *http.
 		processedEvent.TriggerTarget = "pull_request"
 		processedEvent.SourceProjectID = gitEvent.ObjectAttributes.SourceProjectID
 		processedEvent.TargetProjectID = gitEvent.Project.ID
+		processedEvent.MergeRequestRef = fmt.Sprintf("refs/merge-requests/%d/merge", gitEvent.ObjectAttributes.IID)
+		if provider.Valid(gitEvent.ObjectAttributes.Action, approvalActions) {
+			processedEvent.CustomEventType = provider.PullRequestApprovedEventType
+		}
 	case *gitlab.PushEvent:
 		if len(gitEvent.Commits) == 0 {
 			return nil, fmt.Errorf("no commits attached to this push event")
@@ -63,6 +70,7 @@ func (v *Provider) ParsePayload(_ context.Context, _ *params.Run, request *http.
 		processedEvent.DefaultBranch = gitEvent.Project.DefaultBranch
 		processedEvent.URL = gitEvent.Project.WebURL
 		processedEvent.SHA = gitEvent.Commits[0].ID
+		processedEvent.BeforeSHA = gitEvent.Before
 		processedEvent.SHAURL = gitEvent.Commits[0].URL
 		processedEvent.SHATitle = gitEvent.Commits[0].Title
 		processedEvent.HeadBranch = gitEvent.Ref
@@ -87,12 +95,18 @@ func (v *Provider) ParsePayload(_ context.Context, _ *params.Run, request *http.
 		processedEvent.BaseBranch = gitEvent.MergeRequest.TargetBranch
 		processedEvent.HeadBranch = gitEvent.MergeRequest.SourceBranch
 		// if it is a /test or /retest comment with pipelinerun name figure out the pipelineRun name
+		processedEvent.TriggerComment = gitEvent.ObjectAttributes.Note
 		if provider.IsTestRetestComment(gitEvent.ObjectAttributes.Note) {
 			processedEvent.TargetTestPipelineRun = provider.GetPipelineRunFromTestComment(gitEvent.ObjectAttributes.Note)
+			processedEvent.TriggerCommentArgs = provider.GetArgsFromTestComment(gitEvent.ObjectAttributes.Note)
 		}
 		if provider.IsCancelComment(gitEvent.ObjectAttributes.Note) {
 			processedEvent.TargetCancelPipelineRun = provider.GetPipelineRunFromCancelComment(gitEvent.ObjectAttributes.Note)
 		}
+		if provider.IsOverrideComment(gitEvent.ObjectAttributes.Note) {
+			processedEvent.OverridePipelineRuns = true
+			processedEvent.TargetOverridePipelineRun, processedEvent.OverrideReason = provider.GetOverrideFromComment(gitEvent.ObjectAttributes.Note)
+		}
 
 		v.pathWithNamespace = gitEvent.Project.PathWithNamespace
 		processedEvent.Organization, processedEvent.Repository = getOrgRepo(v.pathWithNamespace)
@@ -104,6 +118,35 @@ func (v *Provider) ParsePayload(_ context.Context, _ *params.Run, request *http.
 		v.userID = gitEvent.User.ID
 		processedEvent.SourceProjectID = gitEvent.MergeRequest.SourceProjectID
 		processedEvent.TargetProjectID = gitEvent.MergeRequest.TargetProjectID
+		processedEvent.MergeRequestRef = fmt.Sprintf("refs/merge-requests/%d/merge", gitEvent.MergeRequest.IID)
+	case *gitlab.PipelineEvent:
+		// the pipeline of the merged result of a merge request in a merge
+		// train, the SHA is the one of the merge commit GitLab made on the
+		// target project and not the one of the merge request.
+		processedEvent = info.NewEvent()
+		processedEvent.Sender = gitEvent.User.Username
+		processedEvent.DefaultBranch = gitEvent.Project.DefaultBranch
+		processedEvent.URL = gitEvent.Project.WebURL
+		processedEvent.SHA = gitEvent.ObjectAttributes.SHA
+		processedEvent.SHAURL = gitEvent.Commit.URL
+		processedEvent.SHATitle = gitEvent.Commit.Title
+		processedEvent.HeadBranch = gitEvent.MergeRequest.SourceBranch
+		processedEvent.BaseBranch = gitEvent.MergeRequest.TargetBranch
+		processedEvent.PullRequestNumber = gitEvent.MergeRequest.IID
+		processedEvent.PullRequestTitle = gitEvent.MergeRequest.Title
+		processedEvent.MergeRequestRef = gitEvent.ObjectAttributes.Ref
+
+		v.pathWithNamespace = gitEvent.Project.PathWithNamespace
+		processedEvent.Organization, processedEvent.Repository = getOrgRepo(v.pathWithNamespace)
+		processedEvent.TriggerTarget = "pull_request"
+
+		// the merge commit only exists on the target project
+		v.targetProjectID = gitEvent.Project.ID
+		v.sourceProjectID = gitEvent.Project.ID
+		v.userID = gitEvent.User.ID
+		v.onMergedResult = true
+		processedEvent.SourceProjectID = gitEvent.Project.ID
+		processedEvent.TargetProjectID = gitEvent.Project.ID
 	default:
 		return nil, fmt.Errorf("event %s is not supported", event)
 	}
@@ -2,6 +2,7 @@ package gitlab
 
 import (
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
@@ -44,6 +45,9 @@ func TestParsePayload(t *testing.T) {
 		want       *info.Event
 		wantErr    bool
 		wantClient bool
+		// wantMergeRef is the ref of the merged result of the merge request
+		wantMergeRef       string
+		wantOnMergedResult bool
 	}{
 		{
 			name: "bad payload",
@@ -56,7 +60,7 @@ func TestParsePayload(t *testing.T) {
 		{
 			name: "event not supported",
 			args: args{
-				event:   gitlab.EventTypePipeline,
+				event:   gitlab.EventTypeTagPush,
 				payload: sample.MREventAsJSON(),
 			},
 			wantErr: true,
@@ -73,6 +77,37 @@ func TestParsePayload(t *testing.T) {
 				Organization:  "hello-this-is-me-ze",
 				Repository:    "project",
 			},
+			wantMergeRef: "refs/merge-requests/1/merge",
+		},
+		{
+			name: "merge request approval event",
+			args: args{
+				event:   gitlab.EventTypeMergeRequest,
+				payload: strings.Replace(sample.MREventAsJSON(), `"action": "open"`, `"action": "approval"`, 1),
+			},
+			want: &info.Event{
+				EventType:       "Merge Request",
+				TriggerTarget:   "pull_request",
+				CustomEventType: "pull_request_approved",
+				Organization:    "hello-this-is-me-ze",
+				Repository:      "project",
+			},
+			wantMergeRef: "refs/merge-requests/1/merge",
+		},
+		{
+			name: "merge train pipeline event",
+			args: args{
+				event:   gitlab.EventTypePipeline,
+				payload: sample.PipelineEventAsJSON("refs/merge-requests/1/train", "pending"),
+			},
+			want: &info.Event{
+				EventType:     "Pipeline",
+				TriggerTarget: "pull_request",
+				Organization:  "hello-this-is-me-ze",
+				Repository:    "project",
+			},
+			wantMergeRef:       "refs/merge-requests/1/train",
+			wantOnMergedResult: true,
 		},
 		{
 			name: "push event no commits",
@@ -182,12 +217,17 @@ func TestParsePayload(t *testing.T) {
 				assert.Equal(t, tt.want.EventType, got.EventType)
 				assert.Equal(t, tt.want.Organization, got.Organization)
 				assert.Equal(t, tt.want.Repository, got.Repository)
+				assert.Equal(t, tt.want.CustomEventType, got.CustomEventType)
 				if tt.want.TargetTestPipelineRun != "" {
 					assert.Equal(t, tt.want.TargetTestPipelineRun, got.TargetTestPipelineRun)
 				}
 				if tt.want.TargetCancelPipelineRun != "" {
 					assert.Equal(t, tt.want.TargetCancelPipelineRun, got.TargetCancelPipelineRun)
 				}
+				if tt.wantMergeRef != "" {
+					assert.Equal(t, tt.wantMergeRef, got.MergeRequestRef)
+				}
+				assert.Equal(t, tt.wantOnMergedResult, v.onMergedResult)
 			}
 		})
 	}
@@ -0,0 +1,42 @@
+package gitlab
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	"github.com/xanzy/go-gitlab"
+)
+
+// apiScope is the only scope allowing to set the commit status, to comment on
+// merge requests and to manage the webhooks of the project.
+const apiScope = "api"
+
+type tokenSelf struct {
+	Scopes []string `json:"scopes"`
+}
+
+// MissingTokenScopes checks the scopes of the personal, group or project
+// access token, GitLab versions older than 15.5 don't have the API to get them.
+// The api scope is needed to manage the webhook too, managing it doesn't need
+// another scope.
+func (v *Provider) MissingTokenScopes(ctx context.Context, _ bool) ([]string, error) {
+	req, err := v.Client.NewRequest(http.MethodGet, "personal_access_tokens/self", nil, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+	if err != nil {
+		return nil, err
+	}
+	token := &tokenSelf{}
+	resp, err := v.Client.Do(req, token)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return nil, provider.ErrTokenScopesNotAvailable
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, scope := range token.Scopes {
+		if scope == apiScope {
+			return nil, nil
+		}
+	}
+	return []string{apiScope}, nil
+}
@@ -0,0 +1,63 @@
+package gitlab
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	thelp "github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gitlab/test"
+	"gotest.tools/v3/assert"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestMissingTokenScopes(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		scopes      string
+		wantMissing []string
+		wantErr     error
+	}{
+		{
+			name:   "api scope",
+			status: http.StatusOK,
+			scopes: `["api", "read_user"]`,
+		},
+		{
+			name:        "read only token",
+			status:      http.StatusOK,
+			scopes:      `["read_api", "read_repository"]`,
+			wantMissing: []string{"api"},
+		},
+		{
+			name:    "older gitlab",
+			status:  http.StatusNotFound,
+			wantErr: provider.ErrTokenScopesNotAvailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			client, mux, tearDown := thelp.Setup(ctx, t)
+			defer tearDown()
+			mux.HandleFunc("/personal_access_tokens/self", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				if tt.status != http.StatusOK {
+					fmt.Fprint(w, `{"message": "404 Not Found"}`)
+					return
+				}
+				fmt.Fprintf(w, `{"id": 1, "scopes": %s}`, tt.scopes)
+			})
+			v := &Provider{Client: client}
+			missing, err := v.MissingTokenScopes(ctx, true)
+			if tt.wantErr != nil {
+				assert.Assert(t, errors.Is(err, tt.wantErr))
+				return
+			}
+			assert.NilError(t, err)
+			assert.DeepEqual(t, missing, tt.wantMissing)
+		})
+	}
+}
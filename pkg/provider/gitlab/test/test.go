@@ -198,3 +198,43 @@ func (t TEvent) MREventAsJSON() string {
 }`, t.UserID, t.Username, t.TargetProjectID, t.URL, t.DefaultBranch, t.MRID,
 		t.SourceProjectID, t.SHAtitle, t.Headbranch, t.Basebranch, t.SHA, t.SHAurl, t.PathWithNameSpace)
 }
+
+// PipelineEventAsJSON returns a pipeline event as sent for the merged result
+// of a merge request, ref is refs/merge-requests/<iid>/merge or
+// refs/merge-requests/<iid>/train for merge trains.
+func (t TEvent) PipelineEventAsJSON(ref, status string) string {
+	return fmt.Sprintf(`{
+    "object_kind": "pipeline",
+    "object_attributes": {
+        "ref": "%s",
+        "sha": "%s",
+        "source": "merge_request_event",
+        "status": "%s"
+    },
+    "merge_request": {
+        "iid": %d,
+        "title": "%s",
+        "source_branch": "%s",
+        "source_project_id": %d,
+        "target_branch": "%s",
+        "target_project_id": %d
+    },
+    "user": {
+        "id": %d,
+        "username": "%s"
+    },
+    "project": {
+        "id": %d,
+        "web_url": "%s",
+        "default_branch": "%s",
+        "path_with_namespace": "%s"
+    },
+    "commit": {
+        "id": "%s",
+        "title": "%s",
+        "url": "%s"
+    }
+}`, ref, t.SHA, status, t.MRID, t.SHAtitle, t.Headbranch, t.SourceProjectID, t.Basebranch, t.TargetProjectID,
+		t.UserID, t.Username, t.TargetProjectID, t.URL, t.DefaultBranch, t.PathWithNameSpace,
+		t.SHA, t.SHAtitle, t.SHAurl)
+}
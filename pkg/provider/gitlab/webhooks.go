@@ -0,0 +1,44 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	"github.com/xanzy/go-gitlab"
+)
+
+// DeleteWebhooks deletes the hooks of the project pointing to the controller
+// URL.
+func (v *Provider) DeleteWebhooks(ctx context.Context, repoURL, controllerURL string) (int, error) {
+	projectID, err := formatting.GetRepoOwnerFromURL(repoURL)
+	if err != nil {
+		return 0, err
+	}
+	hooks := []*gitlab.ProjectHook{}
+	opt := &gitlab.ListProjectHooksOptions{PerPage: 100}
+	for {
+		page, resp, err := v.Client.Projects.ListProjectHooks(projectID, opt, gitlab.WithContext(ctx))
+		if err != nil {
+			return 0, fmt.Errorf("cannot list the hooks of %s: %w", projectID, err)
+		}
+		hooks = append(hooks, page...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	deleted := 0
+	for _, hook := range hooks {
+		if !provider.IsControllerWebhook(hook.URL, controllerURL) {
+			continue
+		}
+		if _, err := v.Client.Projects.DeleteProjectHook(projectID, hook.ID, gitlab.WithContext(ctx)); err != nil {
+			return deleted, fmt.Errorf("cannot delete the hook %d of %s: %w", hook.ID, projectID, err)
+		}
+		deleted++
+	}
+	return deleted, nil
+}
@@ -0,0 +1,34 @@
+package gitlab
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	thelp "github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gitlab/test"
+	"gotest.tools/v3/assert"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestDeleteWebhooks(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	client, mux, tearDown := thelp.Setup(ctx, t)
+	defer tearDown()
+	mux.HandleFunc("/projects/group/project/hooks", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"id": 1, "url": "https://other.example.com"}, {"id": 2, "url": "https://pac.example.com/"}]`)
+	})
+	deleted := []int{}
+	for _, id := range []int{1, 2} {
+		id := id
+		mux.HandleFunc(fmt.Sprintf("/projects/group/project/hooks/%d", id), func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, r.Method, http.MethodDelete)
+			deleted = append(deleted, id)
+		})
+	}
+
+	v := &Provider{Client: client}
+	n, err := v.DeleteWebhooks(ctx, "https://gitlab.com/group/project", "https://pac.example.com")
+	assert.NilError(t, err)
+	assert.Equal(t, n, 1)
+	assert.DeepEqual(t, deleted, []int{2})
+}
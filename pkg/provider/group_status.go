@@ -0,0 +1,100 @@
+package provider
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	knativeapi "knative.dev/pkg/apis"
+)
+
+// GroupedStatusName is the PipelineRun name of the status grouping all the
+// PipelineRuns of a commit, the providers use it as the key of the status so
+// every update of the group updates the same status.
+const GroupedStatusName = "pipelineruns"
+
+// IsGroupedStatus returns true if the status groups all the PipelineRuns of a
+// commit.
+func IsGroupedStatus(statusOpts StatusOpts) bool {
+	return statusOpts.PipelineRunName == GroupedStatusName
+}
+
+// GroupStatus turns the status of a PipelineRun into the status of all the
+// PipelineRuns of its commit, for when the statuses are grouped by commit.
+// The group is in progress until all the PipelineRuns are done and failed as
+// soon as one of them didn't succeed, its text lists the state of every
+// PipelineRun. Only the latest PipelineRun of every PipelineRun name is
+// listed, the PipelineRun of the status replaces its copy in prs.
+func GroupStatus(statusOpts StatusOpts, prs []*tektonv1.PipelineRun, detailURL func(*tektonv1.PipelineRun) string) StatusOpts {
+	all := []*tektonv1.PipelineRun{}
+	reported := statusOpts.PipelineRun == nil
+	for _, pr := range prs {
+		if !reported && pr.GetName() == statusOpts.PipelineRun.GetName() {
+			pr, reported = statusOpts.PipelineRun, true
+		}
+		all = append(all, pr)
+	}
+	if !reported {
+		all = append(all, statusOpts.PipelineRun)
+	}
+
+	latest := map[string]*tektonv1.PipelineRun{}
+	for _, pr := range all {
+		name := pr.GetLabels()[keys.OriginalPRName]
+		cur, ok := latest[name]
+		if !ok {
+			latest[name] = pr
+			continue
+		}
+		created, curCreated := pr.GetCreationTimestamp(), cur.GetCreationTimestamp()
+		if curCreated.Before(&created) {
+			latest[name] = pr
+		}
+	}
+	names := make([]string, 0, len(latest))
+	for name := range latest {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	running, failed := false, false
+	rows := []string{"| PipelineRun | Status |", "| --- | --- |"}
+	for _, name := range names {
+		pr := latest[name]
+		state := formatting.ConditionEmoji(pr.Status.Conditions)
+		switch {
+		case pr.Spec.Status == tektonv1.PipelineRunSpecStatusPending && !pr.IsDone():
+			state = "⏳ Pending"
+			running = true
+		case !pr.IsDone():
+			state = "🏃 Running"
+			running = true
+		case !pr.Status.GetCondition(knativeapi.ConditionSucceeded).IsTrue():
+			failed = true
+		}
+		title := name
+		if displayName := formatting.PipelineRunDisplayName(pr); displayName != "" {
+			title = displayName
+		}
+		rows = append(rows, fmt.Sprintf("| [%s](%s) | %s |", title, detailURL(pr), state))
+	}
+
+	statusOpts.PipelineRunName = GroupedStatusName
+	statusOpts.OriginalPipelineRunName = ""
+	statusOpts.Text = strings.Join(rows, "\n")
+	switch {
+	case running:
+		statusOpts.Status = "in_progress"
+		statusOpts.Conclusion = "pending"
+	case failed:
+		statusOpts.Status = "completed"
+		statusOpts.Conclusion = "failure"
+	default:
+		statusOpts.Status = "completed"
+		statusOpts.Conclusion = "success"
+	}
+	return statusOpts
+}
@@ -0,0 +1,103 @@
+package provider
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	knativeapi "knative.dev/pkg/apis"
+	knativeduckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+func TestGroupStatus(t *testing.T) {
+	now := time.Now()
+	pipelineRun := func(name, original string, succeeded corev1.ConditionStatus, created time.Duration) *tektonv1.PipelineRun {
+		pr := &tektonv1.PipelineRun{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Labels:            map[string]string{keys.OriginalPRName: original},
+				CreationTimestamp: metav1.Time{Time: now.Add(-created)},
+			},
+		}
+		switch succeeded {
+		case "":
+			pr.Spec.Status = tektonv1.PipelineRunSpecStatusPending
+		default:
+			pr.Status.Status = knativeduckv1.Status{Conditions: knativeduckv1.Conditions{{
+				Type:   knativeapi.ConditionSucceeded,
+				Status: succeeded,
+			}}}
+		}
+		return pr
+	}
+	detailURL := func(pr *tektonv1.PipelineRun) string { return "https://console/" + pr.GetName() }
+
+	tests := []struct {
+		name           string
+		reported       *tektonv1.PipelineRun
+		prs            []*tektonv1.PipelineRun
+		wantStatus     string
+		wantConclusion string
+		wantText       string
+	}{
+		{
+			name:     "in progress while a pipelinerun is running",
+			reported: pipelineRun("lint-abcde", "lint", corev1.ConditionTrue, time.Minute),
+			prs: []*tektonv1.PipelineRun{
+				pipelineRun("lint-abcde", "lint", corev1.ConditionUnknown, time.Minute),
+				pipelineRun("build-fghij", "build", corev1.ConditionUnknown, time.Minute),
+				pipelineRun("deploy-klmno", "deploy", "", time.Minute),
+			},
+			wantStatus:     "in_progress",
+			wantConclusion: "pending",
+			wantText: "| PipelineRun | Status |\n| --- | --- |\n" +
+				"| [build](https://console/build-fghij) | 🏃 Running |\n" +
+				"| [deploy](https://console/deploy-klmno) | ⏳ Pending |\n" +
+				"| [lint](https://console/lint-abcde) | ✅ Succeeded |",
+		},
+		{
+			name:     "failed when a pipelinerun failed",
+			reported: pipelineRun("build-fghij", "build", corev1.ConditionFalse, time.Minute),
+			prs: []*tektonv1.PipelineRun{
+				pipelineRun("lint-abcde", "lint", corev1.ConditionTrue, time.Minute),
+			},
+			wantStatus:     "completed",
+			wantConclusion: "failure",
+			wantText: "| PipelineRun | Status |\n| --- | --- |\n" +
+				"| [build](https://console/build-fghij) | ❌ Failed |\n" +
+				"| [lint](https://console/lint-abcde) | ✅ Succeeded |",
+		},
+		{
+			name:     "succeeded with only the latest retest",
+			reported: pipelineRun("lint-pqrst", "lint", corev1.ConditionTrue, time.Second),
+			prs: []*tektonv1.PipelineRun{
+				pipelineRun("lint-abcde", "lint", corev1.ConditionFalse, time.Hour),
+				pipelineRun("lint-pqrst", "lint", corev1.ConditionUnknown, time.Second),
+			},
+			wantStatus:     "completed",
+			wantConclusion: "success",
+			wantText: "| PipelineRun | Status |\n| --- | --- |\n" +
+				"| [lint](https://console/lint-pqrst) | ✅ Succeeded |",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GroupStatus(StatusOpts{
+				PipelineRun:             tt.reported,
+				PipelineRunName:         tt.reported.GetName(),
+				OriginalPipelineRunName: tt.reported.GetLabels()[keys.OriginalPRName],
+				Status:                  "completed",
+				Text:                    "task statuses",
+			}, tt.prs, detailURL)
+			assert.Assert(t, IsGroupedStatus(got))
+			assert.Equal(t, got.OriginalPipelineRunName, "")
+			assert.Equal(t, got.Status, tt.wantStatus)
+			assert.Equal(t, got.Conclusion, tt.wantConclusion)
+			assert.Equal(t, got.Text, tt.wantText)
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package provider
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+
+	"golang.org/x/oauth2"
+)
+
+type httpClientKey struct{}
+
+// WithHTTPClient returns a context where the provider clients created by
+// SetClient talk to the provider API through client instead of the default
+// one, this is used by the tests to record or replay the API interactions.
+func WithHTTPClient(ctx context.Context, client *http.Client) context.Context {
+	ctx = context.WithValue(ctx, httpClientKey{}, client)
+	// the GitHub client is built with oauth2 which already knows how to get
+	// its base client from the context.
+	return context.WithValue(ctx, oauth2.HTTPClient, client)
+}
+
+// HTTPClient returns the http client set in the context by WithHTTPClient or
+// nil if there is none.
+func HTTPClient(ctx context.Context) *http.Client {
+	if ctx == nil {
+		return nil
+	}
+	client, _ := ctx.Value(httpClientKey{}).(*http.Client)
+	return client
+}
+
+// WithInsecureSkipTLSVerify returns a context where the provider clients do
+// not verify the TLS certificate of the provider API, unless a client has
+// already been set in the context by WithHTTPClient.
+func WithInsecureSkipTLSVerify(ctx context.Context) context.Context {
+	if HTTPClient(ctx) != nil {
+		return ctx
+	}
+	transport, _ := http.DefaultTransport.(*http.Transport)
+	transport = transport.Clone()
+	//nolint:gosec // the Repository explicitly opted in for it
+	transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	return WithHTTPClient(ctx, &http.Client{Transport: transport})
+}
@@ -0,0 +1,23 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestWithInsecureSkipTLSVerify(t *testing.T) {
+	ctx := WithInsecureSkipTLSVerify(context.Background())
+	client := HTTPClient(ctx)
+	assert.Assert(t, client != nil)
+	transport, ok := client.Transport.(*http.Transport)
+	assert.Assert(t, ok)
+	assert.Assert(t, transport.TLSClientConfig.InsecureSkipVerify)
+
+	// a client already set in the context, ie: by the tests, is kept
+	recorder := &http.Client{}
+	ctx = WithInsecureSkipTLSVerify(WithHTTPClient(context.Background(), recorder))
+	assert.Equal(t, HTTPClient(ctx), recorder)
+}
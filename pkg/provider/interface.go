@@ -40,3 +40,8 @@ type Interface interface {
 }
 
 const DefaultProviderAPIUser = "git"
+
+// ConclusionOverridden is the conclusion of a failed PipelineRun which has
+// been overridden with a /override comment, the providers report it as not
+// blocking the merge.
+const ConclusionOverridden = "overridden"
@@ -0,0 +1,43 @@
+package provider
+
+import "sync"
+
+// KeyedMutex serializes the operations on the same key, ie: the updates of the
+// comment of a pull request made by the goroutines starting the PipelineRuns
+// or by the workers of the watcher. The zero value is ready to use.
+type KeyedMutex struct {
+	mutex sync.Mutex
+	locks map[string]*keyedLock
+}
+
+type keyedLock struct {
+	sync.Mutex
+	waiters int
+}
+
+// Lock locks the key and returns the function unlocking it, the lock of a key
+// is dropped once nobody holds or waits for it.
+func (k *KeyedMutex) Lock(key string) func() {
+	k.mutex.Lock()
+	if k.locks == nil {
+		k.locks = map[string]*keyedLock{}
+	}
+	lock, ok := k.locks[key]
+	if !ok {
+		lock = &keyedLock{}
+		k.locks[key] = lock
+	}
+	lock.waiters++
+	k.mutex.Unlock()
+
+	lock.Lock()
+	return func() {
+		lock.Unlock()
+		k.mutex.Lock()
+		lock.waiters--
+		if lock.waiters == 0 {
+			delete(k.locks, key)
+		}
+		k.mutex.Unlock()
+	}
+}
@@ -0,0 +1,26 @@
+package provider
+
+import (
+	"sync"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestKeyedMutex(t *testing.T) {
+	var k KeyedMutex
+	counter := 0
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock := k.Lock("owner/repo#1")
+			defer unlock()
+			counter++
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, counter, 50)
+	assert.Equal(t, len(k.locks), 0, "the locks should be dropped once released")
+}
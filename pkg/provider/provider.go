@@ -9,9 +9,11 @@ import (
 var (
 	testRetestAllRegex    = regexp.MustCompile(`(?m)^(/retest|/test)\s*$`)
 	testRetestSingleRegex = regexp.MustCompile(`(?m)^(/test|/retest)[ \t]+\S+`)
+	retestFailedRegex     = regexp.MustCompile(`(?m)^/retest[ \t]+--failed[ \t]*$`)
 	oktotestRegex         = regexp.MustCompile(`(?m)^/ok-to-test\s*$`)
 	cancelAllRegex        = regexp.MustCompile(`(?m)^(/cancel)\s*$`)
 	cancelSingleRegex     = regexp.MustCompile(`(?m)^(/cancel)[ \t]+\S+`)
+	overrideRegex         = regexp.MustCompile(`(?m)^(/override|/skip)(?:[ \t]+(\S+)[ \t]*(.*?))?[ \t]*$`)
 )
 
 const (
@@ -37,6 +39,12 @@ func IsTestRetestComment(comment string) bool {
 	return testRetestSingleRegex.MatchString(comment) || testRetestAllRegex.MatchString(comment)
 }
 
+// IsRetestFailedComment returns true for the /retest --failed comment only
+// re-running the PipelineRuns which failed on the commit.
+func IsRetestFailedComment(comment string) bool {
+	return retestFailedRegex.MatchString(comment)
+}
+
 func IsOkToTestComment(comment string) bool {
 	return oktotestRegex.MatchString(comment)
 }
@@ -45,11 +53,50 @@ func IsCancelComment(comment string) bool {
 	return cancelAllRegex.MatchString(comment) || cancelSingleRegex.MatchString(comment)
 }
 
+// IsOverrideComment returns true for the /override (or /skip) comment
+// marking the failed PipelineRuns as overridden.
+func IsOverrideComment(comment string) bool {
+	return overrideRegex.MatchString(comment)
+}
+
+// GetOverrideFromComment returns the PipelineRun to override and the reason
+// from a /override comment, ie: /override pipelinerun flaky test. All the
+// failed PipelineRuns are overridden when no PipelineRun is set.
+func GetOverrideFromComment(comment string) (string, string) {
+	matches := overrideRegex.FindStringSubmatch(comment)
+	if matches == nil {
+		return "", ""
+	}
+	return matches[2], strings.TrimSpace(matches[3])
+}
+
 func GetPipelineRunFromTestComment(comment string) string {
+	name, _ := getNameAndArgsFromTestComment(comment)
+	return name
+}
+
+// GetArgsFromTestComment returns the arguments following the PipelineRun name
+// of a /test or /retest comment, ie: "staging" for "/test deploy staging".
+func GetArgsFromTestComment(comment string) string {
+	_, args := getNameAndArgsFromTestComment(comment)
+	return args
+}
+
+func getNameAndArgsFromTestComment(comment string) (string, string) {
+	// --failed is an option of /retest, not the name of a PipelineRun
+	if IsRetestFailedComment(comment) {
+		return "", ""
+	}
+	typeOfComment := retestComment
 	if strings.Contains(comment, testComment) {
-		return getNameFromComment(testComment, comment)
+		typeOfComment = testComment
+	}
+	line := getNameFromComment(typeOfComment, comment)
+	i := strings.IndexAny(line, " \t")
+	if i == -1 {
+		return line, ""
 	}
-	return getNameFromComment(retestComment, comment)
+	return line[:i], strings.TrimSpace(line[i:])
 }
 
 func GetPipelineRunFromCancelComment(comment string) string {
@@ -103,6 +103,59 @@ func TestCancelComment(t *testing.T) {
 	}
 }
 
+func TestOverrideComment(t *testing.T) {
+	tests := []struct {
+		name       string
+		comment    string
+		want       bool
+		wantPRName string
+		wantReason string
+	}{
+		{
+			name:    "override all",
+			comment: "/override",
+			want:    true,
+		},
+		{
+			name:    "skip all with trailing spaces",
+			comment: "/lgtm\n/skip  \n/approve",
+			want:    true,
+		},
+		{
+			name:       "override single pr",
+			comment:    "/override e2e",
+			want:       true,
+			wantPRName: "e2e",
+		},
+		{
+			name:       "override single pr with a reason",
+			comment:    "we know it's flaky\n/override e2e  known flaky test on arm64 \nthanks",
+			want:       true,
+			wantPRName: "e2e",
+			wantReason: "known flaky test on arm64",
+		},
+		{
+			name:    "not at the beginning of the line",
+			comment: "please /override",
+			want:    false,
+		},
+		{
+			name:    "other command",
+			comment: "/skipping",
+			want:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, IsOverrideComment(tt.comment), tt.want)
+			prName, reason := GetOverrideFromComment(tt.comment)
+			assert.Equal(t, prName, tt.wantPRName)
+			assert.Equal(t, reason, tt.wantReason)
+		})
+	}
+}
+
 func TestIsTestRetestComment(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -174,11 +227,51 @@ func TestIsTestRetestComment(t *testing.T) {
 	}
 }
 
-func TestGetPipelineRunFromComment(t *testing.T) {
+func TestIsRetestFailedComment(t *testing.T) {
 	tests := []struct {
 		name    string
 		comment string
-		want    string
+		want    bool
+	}{
+		{
+			name:    "retest failed",
+			comment: "/retest --failed",
+			want:    true,
+		},
+		{
+			name:    "retest failed with some string before and after",
+			comment: "/lgtm\n/retest --failed  \n/approve",
+			want:    true,
+		},
+		{
+			name:    "retest all",
+			comment: "/retest",
+			want:    false,
+		},
+		{
+			name:    "test failed",
+			comment: "/test --failed",
+			want:    false,
+		},
+		{
+			name:    "retest a pipelinerun",
+			comment: "/retest failed",
+			want:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, IsRetestFailedComment(tt.comment), tt.want)
+		})
+	}
+}
+
+func TestGetPipelineRunFromComment(t *testing.T) {
+	tests := []struct {
+		name     string
+		comment  string
+		want     string
+		wantArgs string
 	}{
 		{
 			name:    "test no pipelinerun",
@@ -230,12 +323,30 @@ func TestGetPipelineRunFromComment(t *testing.T) {
 			comment: "before \n /retest abc-01-pr \n after",
 			want:    "abc-01-pr",
 		},
+		{
+			name:     "test command with arguments",
+			comment:  "/test deploy staging  --force \n after",
+			want:     "deploy",
+			wantArgs: "staging  --force",
+		},
+		{
+			name:     "retest command with arguments separated by a tab",
+			comment:  "/retest deploy\tproduction",
+			want:     "deploy",
+			wantArgs: "production",
+		},
+		{
+			name:    "retest failed pipelineruns",
+			comment: "before \n/retest --failed \n after",
+			want:    "",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			got := GetPipelineRunFromTestComment(tt.comment)
 			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantArgs, GetArgsFromTestComment(tt.comment))
 		})
 	}
 }
@@ -318,3 +429,17 @@ func TestCompareHostOfURLS(t *testing.T) {
 		})
 	}
 }
+
+func TestChangedFiles(t *testing.T) {
+	changed := &ChangedFiles{}
+	assert.DeepEqual(t, changed.Paths(), []string{})
+	changed.Add("b", "", "a")
+	changed.Add("a", "c")
+	assert.DeepEqual(t, changed.Paths(), []string{"b", "a", "c"})
+}
+
+func TestIsZeroSHA(t *testing.T) {
+	assert.Assert(t, IsZeroSHA(""))
+	assert.Assert(t, IsZeroSHA("0000000000000000000000000000000000000000"))
+	assert.Assert(t, !IsZeroSHA("a0b0"))
+}
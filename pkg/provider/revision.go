@@ -0,0 +1,16 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+)
+
+// RevisionChecker is implemented by the providers able to check a commit is
+// part of the history of a branch, the commit pinned by the caller of an
+// incoming webhook is checked with it before its PipelineRuns are run.
+type RevisionChecker interface {
+	// IsRevisionOnBranch returns true if the commit is the head of the
+	// branch or one of its ancestors.
+	IsRevisionOnBranch(ctx context.Context, event *info.Event, sha, branch string) (bool, error)
+}
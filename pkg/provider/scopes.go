@@ -0,0 +1,19 @@
+package provider
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrTokenScopesNotAvailable is returned when the provider doesn't report the
+// scopes of the token, ie: fine-grained tokens or older provider versions.
+var ErrTokenScopesNotAvailable = errors.New("the scopes of the token are not reported by the provider")
+
+// TokenScopesChecker is implemented by the providers able to check the scopes
+// of the token set with SetClient.
+type TokenScopesChecker interface {
+	// MissingTokenScopes returns the scopes needed by Pipelines as Code that
+	// the token doesn't have, the scopes to manage the webhook are only
+	// checked when manageWebhook is set.
+	MissingTokenScopes(ctx context.Context, manageWebhook bool) ([]string, error)
+}
@@ -0,0 +1,33 @@
+package provider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+)
+
+// TektonFileMarker prefixes the comment recording the path of a file of the
+// .tekton directory in the multi documents yaml returned by GetTektonDir, the
+// documents following it come from that file.
+const TektonFileMarker = "# pipelinesascode.tekton.dev/source-file: "
+
+// TektonDirChecker is implemented by the providers able to check cheaply if
+// the .tekton directory exists at the revision of an event, before the
+// permissions of the sender are checked and the files of the directory are
+// listed and fetched.
+type TektonDirChecker interface {
+	// HasTektonDir returns true if the directory exists at the revision of
+	// the event.
+	HasTektonDir(ctx context.Context, event *info.Event, path string) (bool, error)
+}
+
+// AppendTektonFile appends a yaml file of the .tekton directory to the multi
+// documents yaml, after a document separator and the comment recording its
+// path.
+func AppendTektonFile(allTemplates, path, data string) string {
+	if allTemplates != "" && !strings.HasPrefix(data, "---") {
+		allTemplates += "---"
+	}
+	return allTemplates + "\n" + TektonFileMarker + path + "\n" + data + "\n"
+}
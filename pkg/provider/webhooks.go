@@ -0,0 +1,20 @@
+package provider
+
+import (
+	"context"
+	"strings"
+)
+
+// WebhookDeleter is implemented by the providers able to delete the webhooks
+// of a repository with the client set with SetClient.
+type WebhookDeleter interface {
+	// DeleteWebhooks deletes the webhooks of the repository sending the events
+	// to the controller URL and returns how many have been deleted.
+	DeleteWebhooks(ctx context.Context, repoURL, controllerURL string) (int, error)
+}
+
+// IsControllerWebhook returns true if the URL of a webhook points to the
+// controller URL, ignoring the trailing slash.
+func IsControllerWebhook(hookURL, controllerURL string) bool {
+	return controllerURL != "" && strings.TrimSuffix(hookURL, "/") == strings.TrimSuffix(controllerURL, "/")
+}
@@ -16,6 +16,7 @@ import (
 	tektonPipelineRunInformerv1 "github.com/tektoncd/pipeline/pkg/client/injection/informers/pipeline/v1/pipelinerun"
 	tektonPipelineRunReconcilerv1 "github.com/tektoncd/pipeline/pkg/client/injection/reconciler/pipeline/v1/pipelinerun"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
 	"knative.dev/pkg/kmeta"
@@ -50,6 +51,7 @@ func NewController() func(context.Context, configmap.Watcher) *controller.Impl {
 			log.Fatalf("Failed to create pipeline as code metrics recorder %v", err)
 		}
 
+		eventEmitter := events.NewEventEmitter(run.Clients.Kube, run.Clients.Log)
 		r := &Reconciler{
 			run:               run,
 			kinteract:         kinteract,
@@ -57,8 +59,13 @@ func NewController() func(context.Context, configmap.Watcher) *controller.Impl {
 			repoLister:        repository.Get(ctx).Lister(),
 			qm:                sync.NewQueueManager(run.Clients.Log),
 			metrics:           metrics,
-			eventEmitter:      events.NewEventEmitter(run.Clients.Kube, run.Clients.Log),
+			eventEmitter:      eventEmitter,
+			statusRetries: newStatusRetryQueue(run.Clients.Tekton, run.Clients.Log, eventEmitter,
+				workqueue.NewItemExponentialFailureRateLimiter(statusRetryBaseDelay, statusRetryMaxDelay)),
+			queuePositions: newQueuePositions(),
+			repoStatuses:   newRepoStatusBatcher(repoStatusBatchWindow),
 		}
+		go r.statusRetries.run(ctx)
 		impl := tektonPipelineRunReconcilerv1.NewImpl(ctx, r, ctrlOpts())
 
 		if err := r.qm.InitQueues(ctx, run.Clients.Tekton, run.Clients.PipelineAsCode); err != nil {
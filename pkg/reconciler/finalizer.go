@@ -39,9 +39,10 @@ func (r *Reconciler) FinalizeKind(ctx context.Context, pr *tektonv1.PipelineRun)
 		}
 
 		next := r.qm.RemoveFromQueue(repo, pr)
+		defer r.updateQueuedStatuses(ctx, logger, repo)
 		if next != "" {
 			key := strings.Split(next, "/")
-			pr, err := r.run.Clients.Tekton.TektonV1().PipelineRuns(key[0]).Get(ctx, key[1], metav1.GetOptions{})
+			pr, err := r.getPipelineRun(ctx, key[0], key[1])
 			if err != nil {
 				return err
 			}
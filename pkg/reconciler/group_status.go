@@ -0,0 +1,27 @@
+package reconciler
+
+import (
+	"fmt"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// groupStatus turns the status of a PipelineRun into the status of all the
+// PipelineRuns of its commit when the statuses are grouped by commit.
+func (r *Reconciler) groupStatus(pr *tektonv1.PipelineRun, status provider.StatusOpts) (provider.StatusOpts, error) {
+	if !r.run.Info.Pac.GroupStatusesByCommit {
+		return status, nil
+	}
+	selector := labels.SelectorFromSet(labels.Set{
+		keys.Repository: pr.GetLabels()[keys.Repository],
+		keys.SHA:        pr.GetLabels()[keys.SHA],
+	})
+	prs, err := r.pipelineRunLister.PipelineRuns(pr.GetNamespace()).List(selector)
+	if err != nil {
+		return status, fmt.Errorf("cannot list the PipelineRuns of the commit: %w", err)
+	}
+	return provider.GroupStatus(status, prs, r.run.Clients.ConsoleUI.DetailURL), nil
+}
@@ -0,0 +1,48 @@
+package reconciler
+
+import (
+	"context"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"github.com/tektoncd/pipeline/pkg/client/informers/externalversions"
+	tektonclient "github.com/tektoncd/pipeline/pkg/client/injection/client"
+	tektonfactory "github.com/tektoncd/pipeline/pkg/client/injection/informers/factory"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/injection"
+)
+
+func init() {
+	// registered after the informer factory of tekton, it replaces it in the
+	// context before the PipelineRun informer is created.
+	injection.Default.RegisterInformerFactory(withManagedPipelineRunsFactory)
+}
+
+// withManagedPipelineRunsFactory injects a tekton informer factory which only
+// lists and watches the PipelineRuns managed by Pipelines as Code, the ones
+// with a state label, so the watcher doesn't cache and reconcile every
+// PipelineRun of the cluster.
+func withManagedPipelineRunsFactory(ctx context.Context) context.Context {
+	opts := []externalversions.SharedInformerOption{
+		externalversions.WithTweakListOptions(func(o *metav1.ListOptions) {
+			o.LabelSelector = keys.State
+		}),
+	}
+	if injection.HasNamespaceScope(ctx) {
+		opts = append(opts, externalversions.WithNamespace(injection.GetNamespaceScope(ctx)))
+	}
+	return context.WithValue(ctx, tektonfactory.Key{},
+		externalversions.NewSharedInformerFactoryWithOptions(tektonclient.Get(ctx), controller.GetResyncPeriod(ctx), opts...))
+}
+
+// getPipelineRun gets the PipelineRun from the informer cache, and from the
+// API server when the cache has not seen it yet.
+func (r *Reconciler) getPipelineRun(ctx context.Context, namespace, name string) (*tektonv1.PipelineRun, error) {
+	pr, err := r.pipelineRunLister.PipelineRuns(namespace).Get(name)
+	if kerrors.IsNotFound(err) {
+		return r.run.Clients.Tekton.TektonV1().PipelineRuns(namespace).Get(ctx, name, metav1.GetOptions{})
+	}
+	return pr, err
+}
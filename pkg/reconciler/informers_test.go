@@ -0,0 +1,63 @@
+package reconciler
+
+import (
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	tektonfactory "github.com/tektoncd/pipeline/pkg/client/injection/informers/factory"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestManagedPipelineRunsFactory(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	_, _ = testclient.SeedTestData(t, ctx, testclient.Data{
+		PipelineRuns: []*tektonv1.PipelineRun{
+			{ObjectMeta: metav1.ObjectMeta{Name: "managed", Namespace: "ns", Labels: map[string]string{keys.State: "started"}}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "ns"}},
+		},
+	})
+
+	factory := tektonfactory.Get(withManagedPipelineRunsFactory(ctx))
+	informer := factory.Tekton().V1().PipelineRuns()
+	synced := informer.Informer().HasSynced
+	factory.Start(ctx.Done())
+	assert.Assert(t, cache.WaitForCacheSync(ctx.Done(), synced))
+
+	prs, err := informer.Lister().List(labels.Everything())
+	assert.NilError(t, err)
+	assert.Equal(t, len(prs), 1)
+	assert.Equal(t, prs[0].GetName(), "managed")
+}
+
+func TestGetPipelineRun(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	cached := &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "cached", Namespace: "ns"}}
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{PipelineRuns: []*tektonv1.PipelineRun{cached}})
+	r := &Reconciler{
+		run:               &params.Run{Clients: clients.Clients{Tekton: stdata.Pipeline}},
+		pipelineRunLister: stdata.PipelineLister,
+	}
+
+	pr, err := r.getPipelineRun(ctx, "ns", "cached")
+	assert.NilError(t, err)
+	assert.Equal(t, pr.GetName(), "cached")
+
+	// created after the cache has synced, the cache may not have seen it yet
+	_, err = stdata.Pipeline.TektonV1().PipelineRuns("ns").Create(ctx,
+		&tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "new", Namespace: "ns"}}, metav1.CreateOptions{})
+	assert.NilError(t, err)
+	pr, err = r.getPipelineRun(ctx, "ns", "new")
+	assert.NilError(t, err)
+	assert.Equal(t, pr.GetName(), "new")
+
+	_, err = r.getPipelineRun(ctx, "ns", "missing")
+	assert.ErrorContains(t, err, "not found")
+}
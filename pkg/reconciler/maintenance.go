@@ -0,0 +1,43 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"knative.dev/pkg/controller"
+)
+
+// maintenanceRecheckInterval is how often the PipelineRuns created during a
+// maintenance window check if the window has been ended or shortened in the
+// settings, they are checked again at the end of the window otherwise.
+const maintenanceRecheckInterval = time.Minute
+
+// releaseMaintenancePipelineRun starts a PipelineRun created during a
+// maintenance window once the window ended, the same way as a PipelineRun
+// waiting for the ones it runs after. It is reconciled again at the end of
+// the window while the maintenance is in progress.
+func (r *Reconciler) releaseMaintenancePipelineRun(ctx context.Context, logger *zap.SugaredLogger, pr *tektonv1.PipelineRun, now time.Time) error {
+	if end, inMaintenance := r.run.Info.Pac.MaintenanceEnd(now); inMaintenance {
+		wait := end.Sub(now)
+		if wait > maintenanceRecheckInterval {
+			wait = maintenanceRecheckInterval
+		}
+		return controller.NewRequeueAfter(wait)
+	}
+
+	repo, err := r.repoLister.Repositories(pr.GetNamespace()).Get(pr.GetLabels()[keys.Repository])
+	if err != nil {
+		// the PipelineRun is left pending when its Repository has been deleted
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("cannot get the repository of the PipelineRun %s: %w", pr.GetName(), err)
+	}
+	logger.Infof("the maintenance window ended, starting the PipelineRun %s/%s", pr.GetNamespace(), pr.GetName())
+	return r.startWaitingPipelineRun(ctx, logger, repo, pr)
+}
@@ -0,0 +1,118 @@
+package reconciler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/controller"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestReleaseMaintenancePipelineRun(t *testing.T) {
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	now := time.Date(2023, time.May, 20, 23, 0, 0, 0, time.UTC)
+	window := func(start, end time.Duration) []settings.MaintenanceWindow {
+		return []settings.MaintenanceWindow{{Start: now.Add(start), End: now.Add(end)}}
+	}
+	concurrency := 1
+
+	tests := []struct {
+		name        string
+		windows     []settings.MaintenanceWindow
+		concurrency *int
+		wantRequeue time.Duration
+		wantStatus  tektonv1.PipelineRunSpecStatus
+		wantState   string
+		wantOrder   string
+	}{
+		{
+			name:        "window in progress",
+			windows:     window(-time.Hour, time.Hour),
+			wantRequeue: maintenanceRecheckInterval,
+			wantStatus:  tektonv1.PipelineRunSpecStatusPending,
+			wantState:   kubeinteraction.StateMaintenance,
+		},
+		{
+			name:        "window ending before the next check",
+			windows:     window(-time.Hour, 10*time.Second),
+			wantRequeue: 10 * time.Second,
+			wantStatus:  tektonv1.PipelineRunSpecStatusPending,
+			wantState:   kubeinteraction.StateMaintenance,
+		},
+		{
+			name:      "window ended",
+			windows:   window(-time.Hour, -time.Minute),
+			wantState: kubeinteraction.StateStarted,
+		},
+		{
+			name:      "window removed from the settings",
+			wantState: kubeinteraction.StateStarted,
+		},
+		{
+			name:        "queued when the window ended with a concurrency limit",
+			concurrency: &concurrency,
+			wantStatus:  tektonv1.PipelineRunSpecStatusPending,
+			wantState:   kubeinteraction.StateQueued,
+			wantOrder:   "ns/deploy",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			pr := &tektonv1.PipelineRun{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "deploy",
+					Namespace: "ns",
+					Labels: map[string]string{
+						keys.Repository: "repo",
+						keys.State:      kubeinteraction.StateMaintenance,
+					},
+				},
+				Spec: tektonv1.PipelineRunSpec{Status: tektonv1.PipelineRunSpecStatusPending},
+			}
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+				PipelineRuns: []*tektonv1.PipelineRun{pr},
+				Repositories: []*v1alpha1.Repository{{
+					ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"},
+					Spec:       v1alpha1.RepositorySpec{ConcurrencyLimit: tt.concurrency},
+				}},
+			})
+			r := &Reconciler{
+				run: &params.Run{
+					Clients: clients.Clients{Tekton: stdata.Pipeline},
+					Info:    info.Info{Pac: &info.PacOpts{Settings: &settings.Settings{MaintenanceWindows: tt.windows}}},
+				},
+				repoLister: stdata.RepositoryLister,
+			}
+
+			err := r.releaseMaintenancePipelineRun(ctx, logger, pr, now)
+			if tt.wantRequeue != 0 {
+				requeue, wait := controller.IsRequeueKey(err)
+				assert.Assert(t, requeue, "expected a requeue, got %v", err)
+				assert.Equal(t, wait, tt.wantRequeue)
+			} else {
+				assert.NilError(t, err)
+			}
+
+			got, err := stdata.Pipeline.TektonV1().PipelineRuns("ns").Get(ctx, "deploy", metav1.GetOptions{})
+			assert.NilError(t, err)
+			assert.Equal(t, got.Spec.Status, tt.wantStatus)
+			assert.Equal(t, got.GetLabels()[keys.State], tt.wantState)
+			assert.Equal(t, got.GetAnnotations()[keys.ExecutionOrder], tt.wantOrder)
+		})
+	}
+}
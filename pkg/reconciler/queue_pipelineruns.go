@@ -45,14 +45,14 @@ func (r *Reconciler) queuePipelineRun(ctx context.Context, logger *zap.SugaredLo
 	}
 
 	orderedList := strings.Split(order, ",")
-	acquired, err := r.qm.AddListToQueue(repo, orderedList)
+	acquired, err := r.qm.AddPipelineRunListToQueue(repo, pr, orderedList)
 	if err != nil {
 		return fmt.Errorf("failed to add to queue: %s: %w", pr.GetName(), err)
 	}
 
 	for _, prKeys := range acquired {
 		nsName := strings.Split(prKeys, "/")
-		pr, err = r.run.Clients.Tekton.TektonV1().PipelineRuns(nsName[0]).Get(ctx, nsName[1], metav1.GetOptions{})
+		pr, err = r.getPipelineRun(ctx, nsName[0], nsName[1])
 		if err != nil {
 			logger.Info("failed to get pr with namespace and name: ", nsName[0], nsName[1])
 			return err
@@ -61,5 +61,6 @@ func (r *Reconciler) queuePipelineRun(ctx context.Context, logger *zap.SugaredLo
 			return fmt.Errorf("failed to update pipelineRun to in_progress: %w", err)
 		}
 	}
+	r.updateQueuedStatuses(ctx, logger, repo)
 	return nil
 }
@@ -0,0 +1,143 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	gosync "sync"
+	"time"
+
+	"github.com/hako/durafmt"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	"go.uber.org/zap"
+)
+
+// recentRunsForETA is the number of the last runs of a repository the start
+// of its queued PipelineRuns is estimated from.
+const recentRunsForETA = 10
+
+// queuePositions remembers the position reported for the queued PipelineRuns
+// to only update their status when it changes.
+type queuePositions struct {
+	lock      *gosync.Mutex
+	positions map[string]map[string]int
+}
+
+func newQueuePositions() *queuePositions {
+	return &queuePositions{lock: &gosync.Mutex{}, positions: map[string]map[string]int{}}
+}
+
+// update records the positions of the queued PipelineRuns of the repository
+// and returns the ones which changed since the last update.
+func (q *queuePositions) update(repoKey string, queued []string) map[string]int {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	previous := q.positions[repoKey]
+	current := map[string]int{}
+	changed := map[string]int{}
+	for i, key := range queued {
+		current[key] = i + 1
+		if previous[key] != i+1 {
+			changed[key] = i + 1
+		}
+	}
+	if len(current) == 0 {
+		delete(q.positions, repoKey)
+	} else {
+		q.positions[repoKey] = current
+	}
+	return changed
+}
+
+// averageRunDuration returns the average duration of the recent completed runs
+// of the repository, or 0 when there is none.
+func averageRunDuration(repo *v1alpha1.Repository) time.Duration {
+	var total time.Duration
+	count := 0
+	for i := len(repo.Status) - 1; i >= 0 && count < recentRunsForETA; i-- {
+		run := repo.Status[i]
+		if run.StartTime == nil || run.CompletionTime == nil {
+			continue
+		}
+		total += run.CompletionTime.Sub(run.StartTime.Time)
+		count++
+	}
+	if count == 0 {
+		return 0
+	}
+	return total / time.Duration(count)
+}
+
+// queueETA estimates when the PipelineRun at the position of the queue will
+// start, the queued PipelineRuns starting by batches of the concurrency limit.
+func queueETA(position, limit int, average time.Duration) time.Duration {
+	if limit <= 0 {
+		return 0
+	}
+	return time.Duration((position-1)/limit+1) * average
+}
+
+func queuePositionText(position, total int, eta time.Duration) string {
+	text := fmt.Sprintf("Position in the queue: <b>%d</b> of %d", position, total)
+	if eta > 0 {
+		text += fmt.Sprintf(", estimated to start in about %s", durafmt.ParseShort(eta.Round(time.Second)).String())
+	}
+	return text
+}
+
+// updateQueuedStatuses reports their position in the queue and an estimate of
+// their start on the queued PipelineRuns of the repository whose position
+// changed, ie: when a PipelineRun is queued or the queue drains. The positions
+// are not reported when the statuses are grouped by commit.
+func (r *Reconciler) updateQueuedStatuses(ctx context.Context, logger *zap.SugaredLogger, repo *v1alpha1.Repository) {
+	if r.queuePositions == nil || r.run.Info.Pac.GroupStatusesByCommit || repo.Spec.ConcurrencyLimit == nil {
+		return
+	}
+	queued := r.qm.QueuedPipelineRuns(repo)
+	changed := r.queuePositions.update(fmt.Sprintf("%s/%s", repo.GetNamespace(), repo.GetName()), queued)
+	if len(changed) == 0 {
+		return
+	}
+
+	average := averageRunDuration(repo)
+	for _, key := range queued {
+		position, ok := changed[key]
+		if !ok {
+			continue
+		}
+		eta := queueETA(position, *repo.Spec.ConcurrencyLimit, average)
+		if err := r.reportQueuePosition(ctx, logger, repo, key, queuePositionText(position, len(queued), eta)); err != nil {
+			logger.Warnf("cannot report the queue position of the PipelineRun %s: %v", key, err)
+		}
+	}
+}
+
+func (r *Reconciler) reportQueuePosition(ctx context.Context, logger *zap.SugaredLogger, repo *v1alpha1.Repository, key, positionText string) error {
+	nsName := strings.Split(key, "/")
+	pr, err := r.getPipelineRun(ctx, nsName[0], nsName[1])
+	if err != nil {
+		return err
+	}
+	p, event, err := r.detectProvider(ctx, logger, pr)
+	if err != nil {
+		return err
+	}
+	if err := r.setProviderClient(ctx, logger, repo, p, event); err != nil {
+		return err
+	}
+
+	status := provider.StatusOpts{
+		Status:                  "queued",
+		Conclusion:              "pending",
+		Text:                    fmt.Sprintf(params.QueuingPipelineRunText, pr.GetName(), repo.GetNamespace()) + positionText,
+		DetailsURL:              r.run.Clients.ConsoleUI.DetailURL(pr),
+		PipelineRunName:         pr.GetName(),
+		PipelineRun:             pr,
+		OriginalPipelineRunName: pr.GetLabels()[keys.OriginalPRName],
+	}
+	return p.CreateStatus(ctx, r.run.Clients.Tekton, event, r.run.Info.Pac, status)
+}
@@ -0,0 +1,103 @@
+package reconciler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestQueuePositionsUpdate(t *testing.T) {
+	q := newQueuePositions()
+	assert.DeepEqual(t, q.update("ns/repo", []string{"ns/a", "ns/b"}), map[string]int{"ns/a": 1, "ns/b": 2})
+	// nothing moved
+	assert.DeepEqual(t, q.update("ns/repo", []string{"ns/a", "ns/b"}), map[string]int{})
+	// a new one at the end of the queue
+	assert.DeepEqual(t, q.update("ns/repo", []string{"ns/a", "ns/b", "ns/c"}), map[string]int{"ns/c": 3})
+	// the queue drains
+	assert.DeepEqual(t, q.update("ns/repo", []string{"ns/b", "ns/c"}), map[string]int{"ns/b": 1, "ns/c": 2})
+	assert.DeepEqual(t, q.update("ns/repo", []string{}), map[string]int{})
+	_, ok := q.positions["ns/repo"]
+	assert.Assert(t, !ok)
+}
+
+func TestAverageRunDuration(t *testing.T) {
+	now := time.Now()
+	run := func(d time.Duration) v1alpha1.RepositoryRunStatus {
+		return v1alpha1.RepositoryRunStatus{
+			StartTime:      &metav1.Time{Time: now.Add(-d)},
+			CompletionTime: &metav1.Time{Time: now},
+		}
+	}
+	tests := []struct {
+		name   string
+		status []v1alpha1.RepositoryRunStatus
+		want   time.Duration
+	}{
+		{
+			name: "no run",
+			want: 0,
+		},
+		{
+			name:   "not completed",
+			status: []v1alpha1.RepositoryRunStatus{{StartTime: &metav1.Time{Time: now}}},
+			want:   0,
+		},
+		{
+			name:   "average",
+			status: []v1alpha1.RepositoryRunStatus{run(2 * time.Minute), run(4 * time.Minute)},
+			want:   3 * time.Minute,
+		},
+		{
+			name: "only the recent runs",
+			status: append([]v1alpha1.RepositoryRunStatus{run(time.Hour)},
+				run(time.Minute), run(time.Minute), run(time.Minute), run(time.Minute), run(time.Minute),
+				run(time.Minute), run(time.Minute), run(time.Minute), run(time.Minute), run(time.Minute)),
+			want: time.Minute,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &v1alpha1.Repository{Status: tt.status}
+			assert.Equal(t, averageRunDuration(repo), tt.want)
+		})
+	}
+}
+
+func TestQueuePositionText(t *testing.T) {
+	tests := []struct {
+		name     string
+		position int
+		limit    int
+		average  time.Duration
+		want     string
+	}{
+		{
+			name:     "no run history",
+			position: 1,
+			limit:    1,
+			want:     "Position in the queue: <b>1</b> of 3",
+		},
+		{
+			name:     "first batch",
+			position: 2,
+			limit:    2,
+			average:  5 * time.Minute,
+			want:     "Position in the queue: <b>2</b> of 3, estimated to start in about 5 minutes",
+		},
+		{
+			name:     "second batch",
+			position: 3,
+			limit:    2,
+			average:  5 * time.Minute,
+			want:     "Position in the queue: <b>3</b> of 3, estimated to start in about 10 minutes",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, queuePositionText(tt.position, 3, queueETA(tt.position, tt.limit, tt.average)), tt.want)
+		})
+	}
+}
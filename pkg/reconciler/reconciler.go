@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	gosync "sync"
+	"time"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/action"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
@@ -22,7 +24,6 @@ import (
 	pipelinerunreconciler "github.com/tektoncd/pipeline/pkg/client/injection/reconciler/pipeline/v1/pipelinerun"
 	tektonv1lister "github.com/tektoncd/pipeline/pkg/client/listers/pipeline/v1"
 	"go.uber.org/zap"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"knative.dev/pkg/logging"
 	pkgreconciler "knative.dev/pkg/reconciler"
 )
@@ -36,6 +37,11 @@ type Reconciler struct {
 	qm                *sync.QueueManager
 	metrics           *metrics.Recorder
 	eventEmitter      *events.EventEmitter
+	statusRetries     *statusRetryQueue
+	queuePositions    *queuePositions
+	repoStatuses      *repoStatusBatcher
+	// statusSinks tracks the statuses being reported to the status sinks
+	statusSinks gosync.WaitGroup
 }
 
 var (
@@ -66,6 +72,11 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, pr *tektonv1.PipelineRun
 		return r.queuePipelineRun(ctx, logger, pr)
 	}
 
+	// start the PipelineRuns created during a maintenance window once it ended
+	if state == kubeinteraction.StateMaintenance && pr.Spec.Status == tektonv1.PipelineRunSpecStatusPending {
+		return r.releaseMaintenancePipelineRun(ctx, logger, pr, time.Now())
+	}
+
 	if !pr.IsDone() {
 		return nil
 	}
@@ -99,25 +110,29 @@ func (r *Reconciler) reportFinalStatus(ctx context.Context, logger *zap.SugaredL
 		return nil, fmt.Errorf("reportFinalStatus: %w", err)
 	}
 
-	if event.InstallationID > 0 {
-		event.Provider.WebhookSecret, _ = pipelineascode.GetCurrentNSWebhookSecret(ctx, r.kinteract)
-	} else {
-		if err := pipelineascode.SecretFromRepository(ctx, r.run, r.kinteract, provider.GetConfig(), event, repo, logger); err != nil {
-			return repo, fmt.Errorf("cannot get secret from repository: %w", err)
-		}
+	if err := r.setProviderClient(ctx, logger, repo, provider, event); err != nil {
+		return repo, err
 	}
 
-	err = provider.SetClient(ctx, r.run, event)
-	if err != nil {
-		return repo, fmt.Errorf("cannot set client: %w", err)
+	// a retried PipelineRun has its status reported by its retry
+	if retried, err := r.retryPipelineRun(ctx, logger, repo, provider, event, pr); retried || err != nil {
+		return repo, err
 	}
 
 	if err := r.cleanupPipelineRuns(ctx, logger, repo, pr); err != nil {
 		return repo, fmt.Errorf("cannot clean prs: %w", err)
 	}
 
+	// the secret is deleted with the PipelineRun, detach it from the
+	// ServiceAccount it was attached to for the run
+	if sa := pr.GetAnnotations()[keys.GitAuthServiceAccount]; sa != "" {
+		if err := r.kinteract.DetachSecretFromServiceAccount(ctx, pr.GetNamespace(), sa, pr.GetAnnotations()[keys.GitAuthSecret]); err != nil {
+			logger.Warnf("cannot detach the git auth secret from service account %s: %v", sa, err)
+		}
+	}
+
 	finalState := kubeinteraction.StateCompleted
-	newPr, err := r.postFinalStatus(ctx, logger, provider, event, pr)
+	newPr, err := r.postFinalStatus(ctx, logger, repo, provider, event, pr)
 	if err != nil {
 		logger.Errorf("failed to post final status, moving on: %v", err)
 		finalState = kubeinteraction.StateFailed
@@ -131,15 +146,20 @@ func (r *Reconciler) reportFinalStatus(ctx context.Context, logger *zap.SugaredL
 		return repo, fmt.Errorf("cannot update state: %w", err)
 	}
 
+	if err := r.releaseWaitingPipelineRuns(ctx, logger, repo, pr); err != nil {
+		return repo, fmt.Errorf("cannot release the waiting PipelineRuns: %w", err)
+	}
+
 	if err := r.emitMetrics(pr); err != nil {
 		logger.Error("failed to emit metrics: ", err)
 	}
 
 	// remove pipelineRun from Queue and start the next one
 	next := r.qm.RemoveFromQueue(repo, pr)
+	defer r.updateQueuedStatuses(ctx, logger, repo)
 	if next != "" {
 		key := strings.Split(next, "/")
-		pr, err := r.run.Clients.Tekton.TektonV1().PipelineRuns(key[0]).Get(ctx, key[1], metav1.GetOptions{})
+		pr, err := r.getPipelineRun(ctx, key[0], key[1])
 		if err != nil {
 			return repo, fmt.Errorf("cannot get pipeline: %w", err)
 		}
@@ -152,6 +172,24 @@ func (r *Reconciler) reportFinalStatus(ctx context.Context, logger *zap.SugaredL
 	return repo, nil
 }
 
+// setProviderClient sets the client of the provider with the secrets of the
+// GitHub App or of the repository.
+func (r *Reconciler) setProviderClient(ctx context.Context, logger *zap.SugaredLogger, repo *v1alpha1.Repository, p provider.Interface, event *info.Event) error {
+	if event.InstallationID > 0 {
+		event.Provider.WebhookSecret, _ = pipelineascode.GetCurrentNSWebhookSecret(ctx, r.kinteract)
+	} else {
+		if err := pipelineascode.SecretFromRepository(ctx, r.run, r.kinteract, p.GetConfig(), event, repo, logger); err != nil {
+			return fmt.Errorf("cannot get secret from repository: %w", err)
+		}
+	}
+
+	event.Provider.CommentStrategy = repo.Spec.CommentStrategy
+	if err := p.SetClient(pipelineascode.WithRepositoryTLSConfig(ctx, repo, logger), r.run, event); err != nil {
+		return fmt.Errorf("cannot set client: %w", err)
+	}
+	return nil
+}
+
 func (r *Reconciler) updatePipelineRunToInProgress(ctx context.Context, logger *zap.SugaredLogger, repo *v1alpha1.Repository, pr *tektonv1.PipelineRun) error {
 	pr, err := r.updatePipelineRunState(ctx, logger, pr, kubeinteraction.StateStarted)
 	if err != nil {
@@ -164,17 +202,8 @@ func (r *Reconciler) updatePipelineRunToInProgress(ctx context.Context, logger *
 		return nil
 	}
 
-	if event.InstallationID > 0 {
-		event.Provider.WebhookSecret, _ = pipelineascode.GetCurrentNSWebhookSecret(ctx, r.kinteract)
-	} else {
-		if err := pipelineascode.SecretFromRepository(ctx, r.run, r.kinteract, p.GetConfig(), event, repo, logger); err != nil {
-			return fmt.Errorf("cannot get secret from repo: %w", err)
-		}
-	}
-
-	err = p.SetClient(ctx, r.run, event)
-	if err != nil {
-		return fmt.Errorf("cannot set client: %w", err)
+	if err := r.setProviderClient(ctx, logger, repo, p, event); err != nil {
+		return err
 	}
 
 	consoleURL := r.run.Clients.ConsoleUI.DetailURL(pr)
@@ -193,8 +222,14 @@ func (r *Reconciler) updatePipelineRunToInProgress(ctx context.Context, logger *
 		PipelineRun:             pr,
 		OriginalPipelineRunName: pr.GetLabels()[keys.OriginalPRName],
 	}
+	status, err = r.groupStatus(pr, status)
+	if err != nil {
+		return err
+	}
 
-	if err := createStatusWithRetry(ctx, logger, r.run.Clients.Tekton, p, event, r.run.Info.Pac, status); err != nil {
+	err = createStatusWithRetry(ctx, logger, r.run.Clients.Tekton, p, event, r.run.Info.Pac, status)
+	r.reportStatusSinks(ctx, logger, repo, event, status)
+	if err != nil {
 		// if failed to report status for running state, let the pipelineRun continue,
 		// pipelineRun is already started so we will try again once it completes
 		logger.Errorf("failed to report status to running on provider continuing! error: %v", err)
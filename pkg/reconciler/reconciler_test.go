@@ -148,12 +148,13 @@ func TestReconciler_ReconcileKind(t *testing.T) {
 				},
 			}
 
-			secretName := secrets.GenerateBasicAuthSecretName()
+			secretName := secrets.GenerateBasicAuthSecretName(settings.SecretAutoCreateNamePrefixDefaultValue)
 			pr.Annotations = map[string]string{
-				keys.GitAuthSecret:  secretName,
-				keys.State:          kubeinteraction.StateCompleted,
-				keys.InstallationID: "1234",
-				keys.RepoURL:        randomURL,
+				keys.GitAuthSecret:         secretName,
+				keys.GitAuthServiceAccount: "builder",
+				keys.State:                 kubeinteraction.StateCompleted,
+				keys.InstallationID:        "1234",
+				keys.RepoURL:               randomURL,
 			}
 			pr.Labels = map[string]string{
 				keys.Repository:     pr.GetName(),
@@ -201,6 +202,11 @@ func TestReconciler_ReconcileKind(t *testing.T) {
 				},
 			}
 			stdata, informers := testclient.SeedTestData(t, ctx, testData)
+			_, err := stdata.Kube.CoreV1().ServiceAccounts(pr.Namespace).Create(ctx, &corev1.ServiceAccount{
+				ObjectMeta: metav1.ObjectMeta{Namespace: pr.Namespace, Name: "builder"},
+				Secrets:    []corev1.ObjectReference{{Name: secretName}},
+			}, metav1.CreateOptions{})
+			assert.NilError(t, err)
 
 			metrics, err := metrics.NewRecorder()
 			assert.NilError(t, err)
@@ -246,6 +252,11 @@ func TestReconciler_ReconcileKind(t *testing.T) {
 
 			// state must be updated to completed
 			assert.Equal(t, got.Labels[keys.State], kubeinteraction.StateCompleted)
+
+			// the git auth secret is detached from the service account
+			sa, err := stdata.Kube.CoreV1().ServiceAccounts(pr.Namespace).Get(ctx, "builder", metav1.GetOptions{})
+			assert.NilError(t, err)
+			assert.Equal(t, len(sa.Secrets), 0)
 		})
 	}
 }
@@ -0,0 +1,74 @@
+package reconciler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	pacv1a1 "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+)
+
+// repoStatusBatchWindow is how long the run statuses of a Repository are
+// collected before they are patched together.
+const repoStatusBatchWindow = 500 * time.Millisecond
+
+// repoStatusBatcher batches the run statuses appended to a Repository in a
+// short window, ie: when many PipelineRuns finish together, so they are
+// written with one patch instead of racing each other on the resourceVersion
+// of the Repository. A nil batcher doesn't batch.
+type repoStatusBatcher struct {
+	window  time.Duration
+	mutex   sync.Mutex
+	batches map[string]*repoStatusBatch
+}
+
+// repoStatusBatch are the run statuses waiting to be written to a Repository,
+// done is closed once they have been with the error of the write in err.
+type repoStatusBatch struct {
+	statuses []pacv1a1.RepositoryRunStatus
+	done     chan struct{}
+	err      error
+}
+
+func newRepoStatusBatcher(window time.Duration) *repoStatusBatcher {
+	return &repoStatusBatcher{window: window, batches: map[string]*repoStatusBatch{}}
+}
+
+// add adds the run status to the batch of the Repository key and waits for
+// the batch to be written. The first caller of a batch waits for the window
+// and writes all the statuses added in the meantime with flush, the others
+// wait for its result.
+func (b *repoStatusBatcher) add(ctx context.Context, key string, status pacv1a1.RepositoryRunStatus,
+	flush func(context.Context, []pacv1a1.RepositoryRunStatus) error,
+) error {
+	if b == nil {
+		return flush(ctx, []pacv1a1.RepositoryRunStatus{status})
+	}
+
+	b.mutex.Lock()
+	batch, pending := b.batches[key]
+	if !pending {
+		batch = &repoStatusBatch{done: make(chan struct{})}
+		b.batches[key] = batch
+	}
+	batch.statuses = append(batch.statuses, status)
+	b.mutex.Unlock()
+
+	if pending {
+		<-batch.done
+		return batch.err
+	}
+
+	select {
+	case <-time.After(b.window):
+	case <-ctx.Done():
+	}
+	b.mutex.Lock()
+	delete(b.batches, key)
+	b.mutex.Unlock()
+
+	// the batch has left the map, nobody else appends to it
+	batch.err = flush(ctx, batch.statuses)
+	close(batch.done)
+	return batch.err
+}
@@ -0,0 +1,80 @@
+package reconciler
+
+import (
+	"context"
+	gosync "sync"
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestRepoStatusBatcher(t *testing.T) {
+	ctx := context.Background()
+	b := newRepoStatusBatcher(100 * time.Millisecond)
+	var mutex gosync.Mutex
+	flushed := [][]v1alpha1.RepositoryRunStatus{}
+	flush := func(_ context.Context, statuses []v1alpha1.RepositoryRunStatus) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+		flushed = append(flushed, statuses)
+		return nil
+	}
+
+	var wg gosync.WaitGroup
+	for _, name := range []string{"pr1", "pr2", "pr3"} {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			assert.NilError(t, b.add(ctx, "ns/repo", v1alpha1.RepositoryRunStatus{PipelineRunName: name}, flush))
+		}(name)
+	}
+	wg.Wait()
+	assert.Equal(t, len(flushed), 1)
+	assert.Equal(t, len(flushed[0]), 3)
+
+	// a new batch once the previous one has been written
+	assert.NilError(t, b.add(ctx, "ns/repo", v1alpha1.RepositoryRunStatus{PipelineRunName: "pr4"}, flush))
+	assert.Equal(t, len(flushed), 2)
+	assert.Equal(t, flushed[1][0].PipelineRunName, "pr4")
+
+	// a nil batcher writes right away
+	var nb *repoStatusBatcher
+	assert.NilError(t, nb.add(ctx, "ns/repo", v1alpha1.RepositoryRunStatus{PipelineRunName: "pr5"}, flush))
+	assert.Equal(t, len(flushed), 3)
+}
+
+func TestAppendRepoRunStatuses(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	repo := &v1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"},
+		Status: []v1alpha1.RepositoryRunStatus{
+			{PipelineRunName: "old1"}, {PipelineRunName: "old2"}, {PipelineRunName: "old3"},
+		},
+	}
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{Repositories: []*v1alpha1.Repository{repo}})
+	observer, logs := zapobserver.New(zap.InfoLevel)
+	r := &Reconciler{run: &params.Run{Clients: clients.Clients{PipelineAsCode: stdata.PipelineAsCode}}}
+
+	err := r.appendRepoRunStatuses(ctx, zap.New(observer).Sugar(), repo, []v1alpha1.RepositoryRunStatus{
+		{PipelineRunName: "new1"}, {PipelineRunName: "new2"}, {PipelineRunName: "new3"},
+	})
+	assert.NilError(t, err)
+
+	updated, err := stdata.PipelineAsCode.PipelinesascodeV1alpha1().Repositories("ns").Get(ctx, "repo", metav1.GetOptions{})
+	assert.NilError(t, err)
+	names := []string{}
+	for _, s := range updated.Status {
+		names = append(names, s.PipelineRunName)
+	}
+	assert.DeepEqual(t, names, []string{"old2", "old3", "new1", "new2", "new3"})
+	assert.Equal(t, logs.FilterMessage("Repository status of repo has been updated with 3 run(s)").Len(), 1)
+}
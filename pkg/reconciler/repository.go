@@ -0,0 +1,284 @@
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	gosync "sync"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/events"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/generated/injection/informers/pipelinesascode/v1alpha1/repository"
+	pipelinesascode "github.com/openshift-pipelines/pipelines-as-code/pkg/generated/listers/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/pipelineascode"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gitlab"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+)
+
+// tokenScopesRecheckInterval is how often the scopes of a token are checked
+// again, the scopes of a token can be changed on the provider without
+// changing the token.
+const tokenScopesRecheckInterval = time.Hour
+
+// NewRepositoryController returns the controller checking the Repositories,
+// it verifies the git provider token has the scopes needed by Pipelines as
+// Code and runs the schedules of the Repositories.
+func NewRepositoryController() func(context.Context, configmap.Watcher) *controller.Impl {
+	return func(ctx context.Context, cmw configmap.Watcher) *controller.Impl {
+		run := params.New()
+		if err := run.Clients.NewClients(ctx, &run.Info); err != nil {
+			log.Fatal("failed to init clients : ", err)
+		}
+
+		repositoryInformer := repository.Get(ctx)
+		r := &RepositoryReconciler{
+			run:          run,
+			repoLister:   repositoryInformer.Lister(),
+			eventEmitter: events.NewEventEmitter(run.Clients.Kube, run.Clients.Log),
+			checked:      map[string]tokenScopesCheck{},
+			mutex:        &gosync.Mutex{},
+		}
+		impl := controller.NewContext(ctx, r, controller.ControllerOptions{
+			WorkQueueName: "Repositories",
+			Logger:        logging.FromContext(ctx),
+		})
+		repositoryInformer.Informer().AddEventHandler(controller.HandleAll(impl.Enqueue))
+		return impl
+	}
+}
+
+// tokenScopesCheck is the last check of the token of a Repository.
+type tokenScopesCheck struct {
+	fingerprint string
+	at          time.Time
+}
+
+// RepositoryReconciler implements controller.Reconciler for Repository
+// resources.
+type RepositoryReconciler struct {
+	run          *params.Run
+	repoLister   pipelinesascode.RepositoryLister
+	eventEmitter *events.EventEmitter
+
+	// checked avoids to call the provider API every time the Repository is
+	// updated, ie: when its run status is updated.
+	checked map[string]tokenScopesCheck
+	mutex   *gosync.Mutex
+}
+
+var _ controller.Reconciler = (*RepositoryReconciler)(nil)
+
+// Reconcile starts the PipelineRuns of the schedules of the Repository which
+// are due, reports the capabilities of its git provider and whether its TLS
+// certificate is verified, checks the scopes of
+// the git provider token of the Repository and reflects the result in its
+// TokenScopes condition. The Repository is
+// requeued for its next schedule. A deleted Repository is cleaned up before
+// its finalizer is removed.
+func (r *RepositoryReconciler) Reconcile(ctx context.Context, key string) error {
+	logger := logging.FromContext(ctx)
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return controller.NewPermanentError(err)
+	}
+	repo, err := r.repoLister.Repositories(namespace).Get(name)
+	if kerrors.IsNotFound(err) {
+		r.mutex.Lock()
+		delete(r.checked, key)
+		r.mutex.Unlock()
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if repo.GetDeletionTimestamp() != nil {
+		return r.finalizeRepository(ctx, logger, repo)
+	}
+	if repo, err = r.ensureRepositoryFinalizer(ctx, repo); err != nil {
+		return err
+	}
+	if repo, err = r.updateCapabilitiesCondition(ctx, repo); err != nil {
+		return err
+	}
+	if repo, err = r.updateInsecureSkipTLSVerifyCondition(ctx, repo); err != nil {
+		return err
+	}
+
+	wait, err := r.runSchedules(ctx, logger, repo, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	if err := r.reconcileTokenScopes(ctx, logger, key, repo); err != nil {
+		return err
+	}
+	if wait > 0 {
+		return controller.NewRequeueAfter(wait)
+	}
+	return nil
+}
+
+// reconcileTokenScopes checks the scopes of the git provider token of the
+// Repository, unless they were checked recently with the same token.
+func (r *RepositoryReconciler) reconcileTokenScopes(ctx context.Context, logger *zap.SugaredLogger, key string, repo *v1alpha1.Repository) error {
+	namespace := repo.GetNamespace()
+	scopesProvider := repositoryProvider(repo)
+	if scopesProvider == nil || repo.Spec.GitProvider.Secret == nil {
+		return r.updateTokenScopesCondition(ctx, repo, nil)
+	}
+
+	secret, err := r.run.Clients.Kube.CoreV1().Secrets(namespace).Get(ctx, repo.Spec.GitProvider.Secret.Name, metav1.GetOptions{})
+	if err != nil {
+		cond := tokenScopesUnknownCondition(fmt.Errorf("cannot get secret %s: %w", repo.Spec.GitProvider.Secret.Name, err))
+		return r.updateTokenScopesCondition(ctx, repo, &cond)
+	}
+
+	fingerprint := fmt.Sprintf("%s|%s|%s|%t|%t|%s", repo.Spec.URL, repo.Spec.GitProvider.URL, repo.Spec.GitProvider.Secret.Key,
+		repo.Spec.GitProvider.InsecureSkipTLSVerify, repo.Spec.GitProvider.WebhookSecret != nil, secret.GetResourceVersion())
+	r.mutex.Lock()
+	last, ok := r.checked[key]
+	r.mutex.Unlock()
+	if ok && last.fingerprint == fingerprint && time.Since(last.at) < tokenScopesRecheckInterval {
+		return nil
+	}
+
+	cond := r.checkTokenScopes(ctx, logger, repo, secret, scopesProvider)
+	if err := r.updateTokenScopesCondition(ctx, repo, cond); err != nil {
+		return err
+	}
+	r.mutex.Lock()
+	r.checked[key] = tokenScopesCheck{fingerprint: fingerprint, at: time.Now()}
+	r.mutex.Unlock()
+	return nil
+}
+
+// checkTokenScopes returns the TokenScopes condition of the Repository or nil
+// if the provider doesn't report the scopes of the token.
+func (r *RepositoryReconciler) checkTokenScopes(ctx context.Context, logger *zap.SugaredLogger, repo *v1alpha1.Repository, secret *corev1.Secret, scopesProvider provider.Interface) *apis.Condition {
+	if err := r.setProviderClient(ctx, logger, repo, secret, scopesProvider); err != nil {
+		cond := tokenScopesUnknownCondition(err)
+		return &cond
+	}
+
+	// the webhook secret is set by tkn pac webhook add when it configures the
+	// webhook with the token, the token then needs to be able to manage it.
+	manageWebhook := repo.Spec.GitProvider.WebhookSecret != nil
+	missing, err := scopesProvider.(provider.TokenScopesChecker).MissingTokenScopes(ctx, manageWebhook)
+	if errors.Is(err, provider.ErrTokenScopesNotAvailable) {
+		return nil
+	}
+	if err != nil {
+		cond := tokenScopesUnknownCondition(err)
+		return &cond
+	}
+	if len(missing) > 0 {
+		impact := "Pipelines as Code will not be able to report the status"
+		if manageWebhook {
+			impact = "they are needed to report the status and to manage the webhook"
+		}
+		return &apis.Condition{
+			Type:   v1alpha1.RepositoryConditionTokenScopes,
+			Status: corev1.ConditionFalse,
+			Reason: "MissingTokenScopes",
+			Message: fmt.Sprintf("the token in secret %s is missing the scopes: %s, %s",
+				secret.GetName(), strings.Join(missing, ", "), impact),
+		}
+	}
+	return &apis.Condition{
+		Type:   v1alpha1.RepositoryConditionTokenScopes,
+		Status: corev1.ConditionTrue,
+	}
+}
+
+// setProviderClient sets the client of the provider of the Repository with
+// the git provider token in the secret.
+func (r *RepositoryReconciler) setProviderClient(ctx context.Context, logger *zap.SugaredLogger, repo *v1alpha1.Repository, secret *corev1.Secret, p provider.Interface) error {
+	secretKey := repo.Spec.GitProvider.Secret.Key
+	if secretKey == "" {
+		secretKey = pipelineascode.DefaultGitProviderSecretKey
+	}
+	token := strings.TrimSpace(string(secret.Data[secretKey]))
+	if token == "" {
+		return fmt.Errorf("cannot find key %s in secret %s", secretKey, secret.GetName())
+	}
+
+	event := info.NewEvent()
+	event.URL = repo.Spec.URL
+	event.Provider.Token = token
+	event.Provider.URL = repo.Spec.GitProvider.URL
+	if _, ok := p.(*github.Provider); ok && event.Provider.URL == "" {
+		event.Provider.URL = githubEnterpriseAPIURL(repo.Spec.URL)
+	}
+	p.SetLogger(logger)
+	return p.SetClient(pipelineascode.WithRepositoryTLSConfig(ctx, repo, logger), r.run, event)
+}
+
+func tokenScopesUnknownCondition(err error) apis.Condition {
+	return apis.Condition{
+		Type:    v1alpha1.RepositoryConditionTokenScopes,
+		Status:  corev1.ConditionUnknown,
+		Reason:  "TokenScopesCheckFailed",
+		Message: fmt.Sprintf("cannot check the scopes of the token: %v", err),
+	}
+}
+
+// updateTokenScopesCondition sets the TokenScopes condition on the
+// Repository or clears it if cond is nil, an Event is emitted when the token
+// starts missing scopes and the Repository is only updated on changes.
+func (r *RepositoryReconciler) updateTokenScopesCondition(ctx context.Context, repo *v1alpha1.Repository, cond *apis.Condition) error {
+	nrepo := repo.DeepCopy()
+	var changed bool
+	if cond == nil {
+		changed = nrepo.ClearCondition(v1alpha1.RepositoryConditionTokenScopes)
+	} else {
+		changed = nrepo.SetCondition(*cond)
+		if changed && cond.Status != corev1.ConditionTrue {
+			r.eventEmitter.EmitMessage(repo, zap.WarnLevel, "RepositoryTokenScopes", cond.Message)
+		}
+	}
+	if !changed {
+		return nil
+	}
+	_, err := r.run.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(repo.GetNamespace()).Update(ctx, nrepo, metav1.UpdateOptions{})
+	return err
+}
+
+// repositoryProvider returns the provider of the Repository if it can check
+// the scopes of the token and delete the webhooks.
+func repositoryProvider(repo *v1alpha1.Repository) provider.Interface {
+	if repo.Spec.GitProvider == nil {
+		return nil
+	}
+	switch provider.RepositoryProviderType(repo) {
+	case "github":
+		return github.New()
+	case "gitlab":
+		return &gitlab.Provider{}
+	}
+	return nil
+}
+
+// githubEnterpriseAPIURL returns the API URL of GitHub Enterprise, served on
+// /api/v3, or an empty string for the public GitHub.
+func githubEnterpriseAPIURL(repoURL string) string {
+	u, err := url.Parse(repoURL)
+	if err != nil || u.Host == "" || u.Host == "github.com" {
+		return ""
+	}
+	return fmt.Sprintf("%s://%s/api/v3", u.Scheme, u.Host)
+}
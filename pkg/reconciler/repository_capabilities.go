@@ -0,0 +1,35 @@
+package reconciler
+
+import (
+	"context"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+)
+
+// updateCapabilitiesCondition reports the capabilities of the git provider of
+// the Repository in its ProviderCapabilities condition, the condition is
+// cleared when the provider is unknown. It returns the updated Repository.
+func (r *RepositoryReconciler) updateCapabilitiesCondition(ctx context.Context, repo *v1alpha1.Repository) (*v1alpha1.Repository, error) {
+	nrepo := repo.DeepCopy()
+	var changed bool
+	providerType := provider.RepositoryProviderType(repo)
+	if provider.Capabilities(providerType) == nil {
+		changed = nrepo.ClearCondition(v1alpha1.RepositoryConditionProviderCapabilities)
+	} else {
+		changed = nrepo.SetCondition(apis.Condition{
+			Type:     v1alpha1.RepositoryConditionProviderCapabilities,
+			Status:   corev1.ConditionTrue,
+			Severity: apis.ConditionSeverityInfo,
+			Reason:   "ProviderCapabilities",
+			Message:  provider.CapabilitiesSummary(providerType),
+		})
+	}
+	if !changed {
+		return repo, nil
+	}
+	return r.run.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(repo.GetNamespace()).Update(ctx, nrepo, metav1.UpdateOptions{})
+}
@@ -0,0 +1,70 @@
+package reconciler
+
+import (
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestUpdateCapabilitiesCondition(t *testing.T) {
+	tests := []struct {
+		name        string
+		url         string
+		gitProvider *v1alpha1.GitProvider
+		conditions  apis.Conditions
+		wantMessage string
+	}{
+		{
+			name:        "github app",
+			url:         "https://github.com/owner/repo",
+			wantMessage: "github-app supports: checks, path-filtering, comment-commands; unsupported: comments",
+		},
+		{
+			name:        "bitbucket server",
+			url:         "https://bitbucket.example.com/owner/repo",
+			gitProvider: &v1alpha1.GitProvider{Type: "bitbucket-server"},
+			wantMessage: "bitbucket-server supports: comments, comment-commands; unsupported: checks, path-filtering",
+		},
+		{
+			name:        "unknown provider clears the condition",
+			url:         "https://git.example.com/owner/repo",
+			gitProvider: &v1alpha1.GitProvider{},
+			conditions:  apis.Conditions{{Type: v1alpha1.RepositoryConditionProviderCapabilities, Status: corev1.ConditionTrue}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			repo := &v1alpha1.Repository{
+				ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"},
+				Spec:       v1alpha1.RepositorySpec{URL: tt.url, GitProvider: tt.gitProvider},
+				Conditions: tt.conditions,
+			}
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{Repositories: []*v1alpha1.Repository{repo}})
+			r := &RepositoryReconciler{run: &params.Run{Clients: clients.Clients{PipelineAsCode: stdata.PipelineAsCode}}}
+
+			updated, err := r.updateCapabilitiesCondition(ctx, repo)
+			assert.NilError(t, err)
+			cond := updated.GetCondition(v1alpha1.RepositoryConditionProviderCapabilities)
+			if tt.wantMessage == "" {
+				assert.Assert(t, cond == nil)
+				return
+			}
+			assert.Assert(t, cond != nil)
+			assert.Equal(t, cond.Message, tt.wantMessage)
+
+			// the Repository is not updated again when nothing changed
+			again, err := r.updateCapabilitiesCondition(ctx, updated)
+			assert.NilError(t, err)
+			assert.Assert(t, again == updated)
+		})
+	}
+}
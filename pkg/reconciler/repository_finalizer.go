@@ -0,0 +1,190 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/action"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// repositoryFinalizer is set on the Repositories with something to clean up
+// when they are deleted.
+const repositoryFinalizer = pipelinesascode.GroupName + "/repository-cleanup"
+
+var cancelRunMergePatch = map[string]interface{}{
+	"spec": map[string]interface{}{
+		"status": tektonv1.PipelineRunSpecStatusCancelledRunFinally,
+	},
+}
+
+// needsCleanup returns true when the deletion of the Repository has to
+// cancel its PipelineRuns or may have webhooks and secrets to delete.
+func needsCleanup(repo *v1alpha1.Repository) bool {
+	return repo.Spec.CancelRunsOnDelete || (repo.Spec.GitProvider != nil && repo.Spec.GitProvider.Secret != nil)
+}
+
+func hasRepositoryFinalizer(repo *v1alpha1.Repository) bool {
+	for _, f := range repo.GetFinalizers() {
+		if f == repositoryFinalizer {
+			return true
+		}
+	}
+	return false
+}
+
+// ensureRepositoryFinalizer adds the finalizer to the Repository when its
+// deletion needs a clean up and returns the updated Repository.
+func (r *RepositoryReconciler) ensureRepositoryFinalizer(ctx context.Context, repo *v1alpha1.Repository) (*v1alpha1.Repository, error) {
+	if !needsCleanup(repo) || hasRepositoryFinalizer(repo) {
+		return repo, nil
+	}
+	nrepo := repo.DeepCopy()
+	nrepo.SetFinalizers(append(nrepo.GetFinalizers(), repositoryFinalizer))
+	return r.run.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(repo.GetNamespace()).Update(ctx, nrepo, metav1.UpdateOptions{})
+}
+
+// finalizeRepository cleans up after the deleted Repository and removes its
+// finalizer. The clean up is best effort, the failures are reported as
+// events on the Repository and don't block its deletion.
+func (r *RepositoryReconciler) finalizeRepository(ctx context.Context, logger *zap.SugaredLogger, repo *v1alpha1.Repository) error {
+	if !hasRepositoryFinalizer(repo) {
+		return nil
+	}
+
+	if repo.Spec.CancelRunsOnDelete {
+		if err := r.cancelRepositoryRuns(ctx, logger, repo); err != nil {
+			r.eventEmitter.EmitMessage(repo, zap.WarnLevel, "RepositoryCleanupFailed",
+				fmt.Sprintf("cannot cancel the PipelineRuns of the Repository: %v", err))
+		}
+	}
+	if repo.Spec.GitProvider != nil && repo.Spec.GitProvider.Secret != nil {
+		r.cleanupProvider(ctx, logger, repo)
+	}
+
+	nrepo := repo.DeepCopy()
+	finalizers := []string{}
+	for _, f := range nrepo.GetFinalizers() {
+		if f != repositoryFinalizer {
+			finalizers = append(finalizers, f)
+		}
+	}
+	nrepo.SetFinalizers(finalizers)
+	_, err := r.run.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(repo.GetNamespace()).Update(ctx, nrepo, metav1.UpdateOptions{})
+	if kerrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// cancelRepositoryRuns cancels the running and queued PipelineRuns of the
+// Repository.
+func (r *RepositoryReconciler) cancelRepositoryRuns(ctx context.Context, logger *zap.SugaredLogger, repo *v1alpha1.Repository) error {
+	prs, err := r.run.Clients.Tekton.TektonV1().PipelineRuns(repo.GetNamespace()).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{keys.Repository: repo.GetName()}).String(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list pipelineRuns : %w", err)
+	}
+	for i := range prs.Items {
+		pr := &prs.Items[i]
+		if pr.IsDone() || pr.IsCancelled() || pr.IsGracefullyCancelled() || pr.IsGracefullyStopped() {
+			continue
+		}
+		if _, err := action.PatchPipelineRun(ctx, logger, "cancel patch", r.run.Clients.Tekton, pr, cancelRunMergePatch); err != nil {
+			return fmt.Errorf("failed to cancel pipelineRun %s: %w", pr.GetName(), err)
+		}
+		logger.Infof("pipelineRun %s/%s has been cancelled since its repository has been deleted", pr.GetNamespace(), pr.GetName())
+	}
+	return nil
+}
+
+// cleanupProvider deletes the webhooks of the Repository pointing to the
+// controller and the secrets generated for it by tkn pac.
+func (r *RepositoryReconciler) cleanupProvider(ctx context.Context, logger *zap.SugaredLogger, repo *v1alpha1.Repository) {
+	secrets := r.run.Clients.Kube.CoreV1().Secrets(repo.GetNamespace())
+	secret, err := secrets.Get(ctx, repo.Spec.GitProvider.Secret.Name, metav1.GetOptions{})
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			r.eventEmitter.EmitMessage(repo, zap.WarnLevel, "RepositoryCleanupFailed",
+				fmt.Sprintf("cannot get secret %s, the webhooks of the Repository have not been deleted: %v", repo.Spec.GitProvider.Secret.Name, err))
+		}
+		return
+	}
+
+	if err := r.deleteWebhooks(ctx, logger, repo, secret); err != nil {
+		r.eventEmitter.EmitMessage(repo, zap.WarnLevel, "RepositoryCleanupFailed",
+			fmt.Sprintf("cannot delete the webhooks of the Repository: %v", err))
+	}
+
+	names := []string{secret.GetName()}
+	if ws := repo.Spec.GitProvider.WebhookSecret; ws != nil && ws.Name != secret.GetName() {
+		names = append(names, ws.Name)
+	}
+	for _, name := range names {
+		if err := r.deleteGeneratedSecret(ctx, logger, repo, name); err != nil {
+			r.eventEmitter.EmitMessage(repo, zap.WarnLevel, "RepositoryCleanupFailed",
+				fmt.Sprintf("cannot delete secret %s: %v", name, err))
+		}
+	}
+}
+
+// deleteWebhooks deletes the webhooks of the Repository pointing to the
+// controller URL of the pipelines-as-code-info configmap, nothing is deleted
+// when it is not set.
+func (r *RepositoryReconciler) deleteWebhooks(ctx context.Context, logger *zap.SugaredLogger, repo *v1alpha1.Repository, secret *corev1.Secret) error {
+	p := repositoryProvider(repo)
+	deleter, ok := p.(provider.WebhookDeleter)
+	if !ok {
+		return nil
+	}
+	pacInfo, err := info.GetPACInfo(ctx, r.run, os.Getenv("SYSTEM_NAMESPACE"))
+	if err != nil || pacInfo.ControllerURL == "" {
+		logger.Infof("the controller URL is not set in the pipelines-as-code-info configmap, the webhooks of repository %s/%s are not deleted",
+			repo.GetNamespace(), repo.GetName())
+		return nil
+	}
+	if err := r.setProviderClient(ctx, logger, repo, secret, p); err != nil {
+		return err
+	}
+	deleted, err := deleter.DeleteWebhooks(ctx, repo.Spec.URL, pacInfo.ControllerURL)
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		logger.Infof("%d webhook(s) of %s pointing to %s have been deleted", deleted, repo.Spec.URL, pacInfo.ControllerURL)
+	}
+	return nil
+}
+
+// deleteGeneratedSecret deletes the secret if it has been generated for the
+// Repository, the secrets created by the user are kept.
+func (r *RepositoryReconciler) deleteGeneratedSecret(ctx context.Context, logger *zap.SugaredLogger, repo *v1alpha1.Repository, name string) error {
+	secrets := r.run.Clients.Kube.CoreV1().Secrets(repo.GetNamespace())
+	secret, err := secrets.Get(ctx, name, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if secret.GetLabels()[keys.GeneratedFor] != repo.GetName() {
+		return nil
+	}
+	if err := secrets.Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !kerrors.IsNotFound(err) {
+		return err
+	}
+	logger.Infof("secret %s/%s generated for repository %s has been deleted", repo.GetNamespace(), name, repo.GetName())
+	return nil
+}
@@ -0,0 +1,171 @@
+package reconciler
+
+import (
+	"fmt"
+	"net/http"
+	gosync "sync"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/events"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	ghtesthelper "github.com/openshift-pipelines/pipelines-as-code/pkg/test/github"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestRepositoryFinalizer(t *testing.T) {
+	tests := []struct {
+		name               string
+		deleted            bool
+		noSecret           bool
+		cancelRunsOnDelete bool
+		generatedSecret    bool
+		wantFinalizer      bool
+		wantHookDeleted    bool
+		wantSecretDeleted  bool
+		wantCancelled      bool
+	}{
+		{
+			name:          "finalizer added to a repository with a secret",
+			wantFinalizer: true,
+		},
+		{
+			name:     "no finalizer without anything to clean up",
+			noSecret: true,
+		},
+		{
+			name:               "finalizer added to cancel the runs",
+			noSecret:           true,
+			cancelRunsOnDelete: true,
+			wantFinalizer:      true,
+		},
+		{
+			name:              "webhooks and generated secret deleted",
+			deleted:           true,
+			generatedSecret:   true,
+			wantHookDeleted:   true,
+			wantSecretDeleted: true,
+		},
+		{
+			name:            "secret created by the user is kept",
+			deleted:         true,
+			wantHookDeleted: true,
+		},
+		{
+			name:               "runs cancelled",
+			deleted:            true,
+			noSecret:           true,
+			cancelRunsOnDelete: true,
+			wantCancelled:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			t.Setenv("SYSTEM_NAMESPACE", "pipelines-as-code")
+			_, mux, serverURL, teardown := ghtesthelper.SetupGH()
+			defer teardown()
+			hookDeleted := false
+			mux.HandleFunc("/repos/owner/repo/hooks", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `[{"id": 1, "config": {"url": "https://other.example.com"}}, {"id": 2, "config": {"url": "https://pac.example.com/"}}]`)
+			})
+			mux.HandleFunc("/repos/owner/repo/hooks/2", func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, r.Method, http.MethodDelete)
+				hookDeleted = true
+				w.WriteHeader(http.StatusNoContent)
+			})
+			mux.HandleFunc("/repos/owner/repo/hooks/1", func(w http.ResponseWriter, r *http.Request) {
+				t.Error("the webhook not pointing to the controller has been deleted")
+			})
+
+			repo := &v1alpha1.Repository{
+				ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"},
+				Spec: v1alpha1.RepositorySpec{
+					URL:                "https://github.com/owner/repo",
+					CancelRunsOnDelete: tt.cancelRunsOnDelete,
+					GitProvider: &v1alpha1.GitProvider{
+						URL:           serverURL + "/api/v3",
+						Type:          "github",
+						Secret:        &v1alpha1.Secret{Name: "repo"},
+						WebhookSecret: &v1alpha1.Secret{Name: "repo"},
+					},
+				},
+			}
+			if tt.noSecret {
+				repo.Spec.GitProvider = nil
+			}
+			if tt.deleted {
+				repo.SetDeletionTimestamp(&metav1.Time{})
+				repo.SetFinalizers([]string{repositoryFinalizer})
+			}
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"},
+				Data:       map[string][]byte{"provider.token": []byte("secrettoken")},
+			}
+			if tt.generatedSecret {
+				secret.SetLabels(map[string]string{keys.GeneratedFor: "repo"})
+			}
+			succeeded := duckv1.Status{Conditions: duckv1.Conditions{{Type: apis.ConditionSucceeded, Status: corev1.ConditionTrue}}}
+			stdata, informers := testclient.SeedTestData(t, ctx, testclient.Data{
+				Repositories: []*v1alpha1.Repository{repo},
+				Secret:       []*corev1.Secret{secret},
+				ConfigMap: []*corev1.ConfigMap{{
+					ObjectMeta: metav1.ObjectMeta{Name: "pipelines-as-code-info", Namespace: "pipelines-as-code"},
+					Data:       map[string]string{"controller-url": "https://pac.example.com"},
+				}},
+				PipelineRuns: []*tektonv1.PipelineRun{
+					{ObjectMeta: metav1.ObjectMeta{Name: "running", Namespace: "ns", Labels: map[string]string{keys.Repository: "repo"}}},
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "done", Namespace: "ns", Labels: map[string]string{keys.Repository: "repo"}},
+						Status:     tektonv1.PipelineRunStatus{Status: succeeded},
+					},
+					{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "ns", Labels: map[string]string{keys.Repository: "other"}}},
+				},
+			})
+			observer, _ := zapobserver.New(zap.InfoLevel)
+			logger := zap.New(observer).Sugar()
+			r := &RepositoryReconciler{
+				run: &params.Run{Clients: clients.Clients{
+					Kube:           stdata.Kube,
+					PipelineAsCode: stdata.PipelineAsCode,
+					Tekton:         stdata.Pipeline,
+					Log:            logger,
+				}},
+				repoLister:   informers.Repository.Lister(),
+				eventEmitter: events.NewEventEmitter(stdata.Kube, logger),
+				checked:      map[string]tokenScopesCheck{},
+				mutex:        &gosync.Mutex{},
+			}
+			mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"login": "user"}`)
+			})
+			assert.NilError(t, r.Reconcile(ctx, "ns/repo"))
+
+			updated, err := stdata.PipelineAsCode.PipelinesascodeV1alpha1().Repositories("ns").Get(ctx, "repo", metav1.GetOptions{})
+			assert.NilError(t, err)
+			assert.Equal(t, hasRepositoryFinalizer(updated), tt.wantFinalizer)
+			assert.Equal(t, hookDeleted, tt.wantHookDeleted)
+
+			_, err = stdata.Kube.CoreV1().Secrets("ns").Get(ctx, "repo", metav1.GetOptions{})
+			assert.Equal(t, err != nil, tt.wantSecretDeleted)
+
+			for _, name := range []string{"running", "done", "other"} {
+				pr, err := stdata.Pipeline.TektonV1().PipelineRuns("ns").Get(ctx, name, metav1.GetOptions{})
+				assert.NilError(t, err)
+				wantCancelled := tt.wantCancelled && name == "running"
+				assert.Equal(t, pr.Spec.Status == tektonv1.PipelineRunSpecStatusCancelledRunFinally, wantCancelled, name)
+			}
+		})
+	}
+}
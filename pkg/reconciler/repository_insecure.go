@@ -0,0 +1,39 @@
+package reconciler
+
+import (
+	"context"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/pipelineascode"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+)
+
+// updateInsecureSkipTLSVerifyCondition reflects the
+// spec.git_provider.insecure_skip_tls_verify setting of the Repository in its
+// conditions, a warning event is emitted when it is opted in. It returns the
+// updated Repository.
+func (r *RepositoryReconciler) updateInsecureSkipTLSVerifyCondition(ctx context.Context, repo *v1alpha1.Repository) (*v1alpha1.Repository, error) {
+	nrepo := repo.DeepCopy()
+	var changed bool
+	if repo.InsecureSkipTLSVerify() {
+		changed = nrepo.SetCondition(apis.Condition{
+			Type:     v1alpha1.RepositoryConditionInsecureSkipTLSVerify,
+			Status:   corev1.ConditionTrue,
+			Severity: apis.ConditionSeverityWarning,
+			Reason:   "InsecureSkipTLSVerify",
+			Message:  pipelineascode.InsecureSkipTLSVerifyMessage(repo),
+		})
+		if changed {
+			r.eventEmitter.EmitMessage(repo, zap.WarnLevel, "RepositoryInsecureSkipTLSVerify", pipelineascode.InsecureSkipTLSVerifyMessage(repo))
+		}
+	} else {
+		changed = nrepo.ClearCondition(v1alpha1.RepositoryConditionInsecureSkipTLSVerify)
+	}
+	if !changed {
+		return repo, nil
+	}
+	return r.run.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(repo.GetNamespace()).Update(ctx, nrepo, metav1.UpdateOptions{})
+}
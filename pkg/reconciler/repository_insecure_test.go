@@ -0,0 +1,82 @@
+package reconciler
+
+import (
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/events"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/pipelineascode"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	"go.uber.org/zap"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestUpdateInsecureSkipTLSVerifyCondition(t *testing.T) {
+	tests := []struct {
+		name          string
+		insecure      bool
+		hasCondition  bool
+		wantCondition bool
+		wantEvents    int
+	}{
+		{
+			name:          "opted in",
+			insecure:      true,
+			wantCondition: true,
+			wantEvents:    1,
+		},
+		{
+			name:          "opted in with the condition already set",
+			insecure:      true,
+			hasCondition:  true,
+			wantCondition: true,
+		},
+		{
+			name:         "opted out after having been opted in",
+			hasCondition: true,
+		},
+		{
+			name: "not opted in",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			repo := &v1alpha1.Repository{
+				ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"},
+				Spec: v1alpha1.RepositorySpec{
+					URL:         "https://gitea.lab/org/repo",
+					GitProvider: &v1alpha1.GitProvider{InsecureSkipTLSVerify: tt.insecure},
+				},
+			}
+			if tt.hasCondition {
+				repo.SetCondition(apis.Condition{
+					Type:     v1alpha1.RepositoryConditionInsecureSkipTLSVerify,
+					Status:   corev1.ConditionTrue,
+					Severity: apis.ConditionSeverityWarning,
+					Reason:   "InsecureSkipTLSVerify",
+					Message:  pipelineascode.InsecureSkipTLSVerifyMessage(repo),
+				})
+			}
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{Repositories: []*v1alpha1.Repository{repo}})
+			r := &RepositoryReconciler{
+				run:          &params.Run{Clients: clients.Clients{PipelineAsCode: stdata.PipelineAsCode, Kube: stdata.Kube}},
+				eventEmitter: events.NewEventEmitter(stdata.Kube, zap.NewNop().Sugar()),
+			}
+
+			updated, err := r.updateInsecureSkipTLSVerifyCondition(ctx, repo)
+			assert.NilError(t, err)
+			assert.Equal(t, updated.GetCondition(v1alpha1.RepositoryConditionInsecureSkipTLSVerify) != nil, tt.wantCondition)
+
+			kevents, err := stdata.Kube.CoreV1().Events("ns").List(ctx, metav1.ListOptions{})
+			assert.NilError(t, err)
+			assert.Equal(t, len(kevents.Items), tt.wantEvents)
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package reconciler
+
+import (
+	"fmt"
+	"net/http"
+	gosync "sync"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/events"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	ghtesthelper "github.com/openshift-pipelines/pipelines-as-code/pkg/test/github"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestRepositoryReconcileTokenScopes(t *testing.T) {
+	tests := []struct {
+		name          string
+		scopes        string
+		noSecret      bool
+		webhookSecret bool
+		conditions    apis.Conditions
+		wantStatus    corev1.ConditionStatus
+		wantMessage   string
+		wantEvent     bool
+	}{
+		{
+			name:       "token with scopes",
+			scopes:     "repo, read:org",
+			wantStatus: corev1.ConditionTrue,
+		},
+		{
+			name:        "token missing scopes",
+			scopes:      "read:org",
+			wantStatus:  corev1.ConditionFalse,
+			wantMessage: "the token in secret token is missing the scopes: repo:status, Pipelines as Code will not be able to report the status",
+			wantEvent:   true,
+		},
+		{
+			name:          "token missing the webhook scope",
+			scopes:        "repo, read:org",
+			webhookSecret: true,
+			wantStatus:    corev1.ConditionFalse,
+			wantMessage:   "the token in secret token is missing the scopes: write:repo_hook, they are needed to report the status and to manage the webhook",
+			wantEvent:     true,
+		},
+		{
+			name:       "fine-grained token clears the condition",
+			conditions: apis.Conditions{{Type: v1alpha1.RepositoryConditionTokenScopes, Status: corev1.ConditionFalse}},
+		},
+		{
+			name:       "no secret clears the condition",
+			noSecret:   true,
+			conditions: apis.Conditions{{Type: v1alpha1.RepositoryConditionTokenScopes, Status: corev1.ConditionFalse}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			_, mux, serverURL, teardown := ghtesthelper.SetupGH()
+			defer teardown()
+			calls := 0
+			mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				assert.Equal(t, r.Header.Get("Authorization"), "Bearer secrettoken")
+				if tt.scopes != "" {
+					w.Header().Set("X-OAuth-Scopes", tt.scopes)
+				}
+				fmt.Fprint(w, `{"login": "user"}`)
+			})
+
+			repo := &v1alpha1.Repository{
+				ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"},
+				Spec: v1alpha1.RepositorySpec{
+					URL: "https://github.com/owner/repo",
+					GitProvider: &v1alpha1.GitProvider{
+						URL:    serverURL + "/api/v3",
+						Type:   "github",
+						Secret: &v1alpha1.Secret{Name: "token"},
+					},
+				},
+				Conditions: tt.conditions,
+			}
+			if tt.noSecret {
+				repo.Spec.GitProvider.Secret = nil
+			}
+			if tt.webhookSecret {
+				repo.Spec.GitProvider.WebhookSecret = &v1alpha1.Secret{Name: "token", Key: "webhook.secret"}
+			}
+			stdata, informers := testclient.SeedTestData(t, ctx, testclient.Data{
+				Repositories: []*v1alpha1.Repository{repo},
+				Secret: []*corev1.Secret{{
+					ObjectMeta: metav1.ObjectMeta{Name: "token", Namespace: "ns"},
+					Data:       map[string][]byte{"provider.token": []byte("secrettoken")},
+				}},
+			})
+			observer, _ := zapobserver.New(zap.InfoLevel)
+			logger := zap.New(observer).Sugar()
+			r := &RepositoryReconciler{
+				run: &params.Run{Clients: clients.Clients{
+					Kube:           stdata.Kube,
+					PipelineAsCode: stdata.PipelineAsCode,
+					Log:            logger,
+				}},
+				repoLister:   informers.Repository.Lister(),
+				eventEmitter: events.NewEventEmitter(stdata.Kube, logger),
+				checked:      map[string]tokenScopesCheck{},
+				mutex:        &gosync.Mutex{},
+			}
+			assert.NilError(t, r.Reconcile(ctx, "ns/repo"))
+
+			updated, err := stdata.PipelineAsCode.PipelinesascodeV1alpha1().Repositories("ns").Get(ctx, "repo", metav1.GetOptions{})
+			assert.NilError(t, err)
+			cond := updated.GetCondition(v1alpha1.RepositoryConditionTokenScopes)
+			if tt.wantStatus == "" {
+				assert.Assert(t, cond == nil)
+			} else {
+				assert.Assert(t, cond != nil)
+				assert.Equal(t, cond.Status, tt.wantStatus)
+				assert.Equal(t, cond.Message, tt.wantMessage)
+			}
+
+			evs, err := stdata.Kube.CoreV1().Events("ns").List(ctx, metav1.ListOptions{})
+			assert.NilError(t, err)
+			assert.Equal(t, len(evs.Items) == 1, tt.wantEvent)
+
+			// the provider is not called again when nothing changed
+			callsBefore := calls
+			assert.NilError(t, r.Reconcile(ctx, "ns/repo"))
+			assert.Equal(t, calls, callsBefore)
+		})
+	}
+}
+
+func TestRepositoryProvider(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		gitType  string
+		wantType string
+	}{
+		{name: "github from url", url: "https://github.com/owner/repo", wantType: "*github.Provider"},
+		{name: "gitlab from url", url: "https://gitlab.example.com/group/sub/repo", wantType: "*gitlab.Provider"},
+		{name: "github enterprise from type", url: "https://ghe.example.com/owner/repo", gitType: "github", wantType: "*github.Provider"},
+		{name: "gitea is not supported", url: "https://gitea.example.com/owner/repo", gitType: "gitea"},
+		{name: "unknown url", url: "https://git.example.com/owner/repo"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &v1alpha1.Repository{Spec: v1alpha1.RepositorySpec{
+				URL:         tt.url,
+				GitProvider: &v1alpha1.GitProvider{Type: tt.gitType},
+			}}
+			p := repositoryProvider(repo)
+			if tt.wantType == "" {
+				assert.Assert(t, p == nil)
+				return
+			}
+			assert.Equal(t, fmt.Sprintf("%T", p), tt.wantType)
+		})
+	}
+}
+
+func TestGithubEnterpriseAPIURL(t *testing.T) {
+	assert.Equal(t, githubEnterpriseAPIURL("https://github.com/owner/repo"), "")
+	assert.Equal(t, githubEnterpriseAPIURL("https://ghe.example.com/owner/repo"), "https://ghe.example.com/api/v3")
+}
@@ -0,0 +1,205 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/action"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.uber.org/zap"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// retryAttempts returns the attempt the PipelineRun is and the number of
+// attempts it has with its retries annotation, the number of attempts is 0
+// when the PipelineRun is not retried.
+func retryAttempts(pr *tektonv1.PipelineRun) (int, int, error) {
+	value, ok := pr.GetAnnotations()[keys.Retries]
+	if !ok {
+		return 0, 0, nil
+	}
+	retries, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || retries < 0 {
+		return 0, 0, fmt.Errorf("invalid value %q for the annotation %s, it must be a positive number", value, keys.Retries)
+	}
+	attempt := 1
+	if value, ok := pr.GetAnnotations()[keys.RetryAttempt]; ok {
+		if attempt, err = strconv.Atoi(value); err != nil {
+			return 0, 0, fmt.Errorf("invalid value %q for the annotation %s: %w", value, keys.RetryAttempt, err)
+		}
+	}
+	return attempt, retries + 1, nil
+}
+
+// retryAttemptText is the attempt of the PipelineRun as shown in its
+// statuses, an empty string when the PipelineRun is not retried.
+func retryAttemptText(pr *tektonv1.PipelineRun) string {
+	attempt, attempts, err := retryAttempts(pr)
+	if err != nil || attempts < 2 {
+		return ""
+	}
+	return fmt.Sprintf("<b>Attempt %d of %d</b>\n\n", attempt, attempts)
+}
+
+// retryPipelineRun re-creates the failed PipelineRun when it has retries
+// left. The new PipelineRun takes the place of the failed one: it updates
+// the same status on the git provider, uses the same git auth secret and
+// keeps its place in the concurrency queue. It returns true when the
+// PipelineRun has been retried and has no final status to report.
+func (r *Reconciler) retryPipelineRun(ctx context.Context, logger *zap.SugaredLogger, repo *v1alpha1.Repository, vcx provider.Interface, event *info.Event, pr *tektonv1.PipelineRun) (bool, error) {
+	attempt, attempts, err := retryAttempts(pr)
+	if err != nil {
+		logger.Warnf("not retrying PipelineRun %s: %v", pr.GetName(), err)
+		return false, nil
+	}
+	if attempt >= attempts || formatting.PipelineRunStatus(pr) != "failure" || isCancelled(pr) {
+		return false, nil
+	}
+
+	// a previous reconcile created the retry but failed to hand over the
+	// failed PipelineRun to it, the hand over is done again without creating
+	// another retry
+	if name := pr.GetAnnotations()[keys.RetriedBy]; name != "" {
+		retry, err := r.getPipelineRun(ctx, pr.GetNamespace(), name)
+		if kerrors.IsNotFound(err) {
+			logger.Warnf("the retry %s of PipelineRun %s has been deleted, reporting its failure", name, pr.GetName())
+			return false, nil
+		}
+		if err != nil {
+			return true, fmt.Errorf("cannot get the retry %s of PipelineRun %s: %w", name, pr.GetName(), err)
+		}
+		return true, r.handOverToRetry(ctx, logger, repo, vcx, event, pr, retry, attempt, attempts)
+	}
+
+	retry := &tektonv1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: pr.GetGenerateName(),
+			Namespace:    pr.GetNamespace(),
+			Labels:       map[string]string{},
+			Annotations:  map[string]string{},
+		},
+		Spec: *pr.Spec.DeepCopy(),
+	}
+	if retry.GenerateName == "" {
+		retry.GenerateName = pr.GetLabels()[keys.OriginalPRName] + "-"
+	}
+	for k, v := range pr.GetLabels() {
+		retry.Labels[k] = v
+	}
+	for k, v := range pr.GetAnnotations() {
+		retry.Annotations[k] = v
+	}
+	// the log url is the one of the failed attempt
+	delete(retry.Annotations, keys.LogURL)
+	delete(retry.Annotations, keys.RetriedBy)
+	retry.Labels[keys.State] = kubeinteraction.StateStarted
+	retry.Annotations[keys.RetryAttempt] = strconv.Itoa(attempt + 1)
+	retry.Spec.Status = ""
+
+	retry, err = r.run.Clients.Tekton.TektonV1().PipelineRuns(pr.GetNamespace()).Create(ctx, retry, metav1.CreateOptions{})
+	if err != nil {
+		return false, fmt.Errorf("cannot retry PipelineRun %s: %w", pr.GetName(), err)
+	}
+	logger.Infof("PipelineRun %s has failed, retrying it with PipelineRun %s, attempt %d of %d", pr.GetName(), retry.GetName(), attempt+1, attempts)
+
+	// recorded right away so a failure of the hand over doesn't create another
+	// retry when the failed PipelineRun is reconciled again
+	pr, err = action.PatchPipelineRun(ctx, logger, "retried by", r.run.Clients.Tekton, pr, map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{keys.RetriedBy: retry.GetName()},
+		},
+	})
+	if err != nil {
+		return true, err
+	}
+	return true, r.handOverToRetry(ctx, logger, repo, vcx, event, pr, retry, attempt, attempts)
+}
+
+// handOverToRetry gives the git auth secret, the place in the concurrency
+// queue and the status of the failed PipelineRun to its retry, and marks the
+// failed PipelineRun as completed.
+func (r *Reconciler) handOverToRetry(ctx context.Context, logger *zap.SugaredLogger, repo *v1alpha1.Repository, vcx provider.Interface, event *info.Event, pr, retry *tektonv1.PipelineRun, attempt, attempts int) error {
+	// the git auth secret is deleted with its owner, the retry owns it now
+	if secret := pr.GetAnnotations()[keys.GitAuthSecret]; secret != "" {
+		if err := r.kinteract.UpdateSecretWithOwnerRef(ctx, logger, pr.GetNamespace(), secret, retry); err != nil {
+			return err
+		}
+	}
+	r.qm.ReplaceRunning(repo, pr, retry)
+	if err := r.replaceRunAfter(ctx, logger, pr, retry); err != nil {
+		return err
+	}
+
+	if err := r.updateRepoRunStatus(ctx, logger, pr, repo, event); err != nil {
+		return fmt.Errorf("cannot update run status: %w", err)
+	}
+	status := provider.StatusOpts{
+		Status:                  "in_progress",
+		PipelineRun:             retry,
+		PipelineRunName:         retry.GetName(),
+		OriginalPipelineRunName: pr.GetLabels()[keys.OriginalPRName],
+		DetailsURL:              r.run.Clients.ConsoleUI.DetailURL(retry),
+		Text: fmt.Sprintf("Attempt %d of %d has failed, see the [logs](%s). Retrying with the PipelineRun %s, attempt %d of %d.",
+			attempt, attempts, r.run.Clients.ConsoleUI.DetailURL(pr), retry.GetName(), attempt+1, attempts),
+	}
+	if err := createStatusWithRetry(ctx, logger, r.run.Clients.Tekton, vcx, event, r.run.Info.Pac, status); err != nil {
+		logger.Errorf("cannot report the retry of PipelineRun %s: %v", pr.GetName(), err)
+	}
+
+	if _, err := r.updatePipelineRunState(ctx, logger, pr, kubeinteraction.StateCompleted); err != nil {
+		return fmt.Errorf("cannot update state: %w", err)
+	}
+	if err := r.emitMetrics(pr); err != nil {
+		logger.Error("failed to emit metrics: ", err)
+	}
+	return nil
+}
+
+// replaceRunAfter makes the PipelineRuns waiting for the retried PipelineRun
+// wait for its retry instead.
+func (r *Reconciler) replaceRunAfter(ctx context.Context, logger *zap.SugaredLogger, pr, retry *tektonv1.PipelineRun) error {
+	selector := labels.SelectorFromSet(labels.Set{
+		keys.Repository: pr.GetLabels()[keys.Repository],
+		keys.SHA:        pr.GetLabels()[keys.SHA],
+		keys.State:      kubeinteraction.StateWaiting,
+	})
+	waitingPRs, err := r.pipelineRunLister.PipelineRuns(pr.GetNamespace()).List(selector)
+	if err != nil {
+		return fmt.Errorf("cannot list the waiting PipelineRuns: %w", err)
+	}
+	for _, waiting := range waitingPRs {
+		names := strings.Split(waiting.GetAnnotations()[keys.RunAfterPipelineRuns], ",")
+		if !contains(names, pr.GetName()) {
+			continue
+		}
+		for i, name := range names {
+			if name == pr.GetName() {
+				names[i] = retry.GetName()
+			}
+		}
+		if _, err := action.PatchPipelineRun(ctx, logger, "run after retry", r.run.Clients.Tekton, waiting, map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"annotations": map[string]string{
+					keys.RunAfterPipelineRuns: strings.Join(names, ","),
+				},
+			},
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isCancelled(pr *tektonv1.PipelineRun) bool {
+	return pr.IsCancelled() || pr.IsGracefullyCancelled() || pr.IsGracefullyStopped()
+}
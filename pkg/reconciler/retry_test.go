@@ -0,0 +1,253 @@
+package reconciler
+
+import (
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/consoleui"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/metrics"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/sync"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/test/kubernetestint"
+	testprovider "github.com/openshift-pipelines/pipelines-as-code/pkg/test/provider"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ktesting "k8s.io/client-go/testing"
+	knativeapi "knative.dev/pkg/apis"
+	knativeduckv1 "knative.dev/pkg/apis/duck/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestRetryAttempts(t *testing.T) {
+	tests := []struct {
+		name         string
+		annotations  map[string]string
+		wantAttempt  int
+		wantAttempts int
+		wantErr      string
+	}{
+		{
+			name: "not retried",
+		},
+		{
+			name:         "first attempt",
+			annotations:  map[string]string{keys.Retries: "2"},
+			wantAttempt:  1,
+			wantAttempts: 3,
+		},
+		{
+			name:         "retry",
+			annotations:  map[string]string{keys.Retries: "2", keys.RetryAttempt: "3"},
+			wantAttempt:  3,
+			wantAttempts: 3,
+		},
+		{
+			name:        "invalid retries",
+			annotations: map[string]string{keys.Retries: "twice"},
+			wantErr:     `invalid value "twice" for the annotation pipelinesascode.tekton.dev/retries, it must be a positive number`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr := &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}}
+			attempt, attempts, err := retryAttempts(pr)
+			if tt.wantErr != "" {
+				assert.Error(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, attempt, tt.wantAttempt)
+			assert.Equal(t, attempts, tt.wantAttempts)
+		})
+	}
+}
+
+func TestRetryPipelineRun(t *testing.T) {
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	ns := "ns"
+
+	tests := []struct {
+		name        string
+		succeeded   corev1.ConditionStatus
+		specStatus  tektonv1.PipelineRunSpecStatus
+		annotations map[string]string
+		// the retry has already been created by a previous reconcile
+		existingRetry bool
+		wantRetried   bool
+	}{
+		{
+			name:        "failed with retries left",
+			succeeded:   corev1.ConditionFalse,
+			annotations: map[string]string{keys.Retries: "2", keys.RetryAttempt: "2"},
+			wantRetried: true,
+		},
+		{
+			name:          "retry already created",
+			succeeded:     corev1.ConditionFalse,
+			annotations:   map[string]string{keys.Retries: "2", keys.RetryAttempt: "2", keys.RetriedBy: "tests-fghij"},
+			existingRetry: true,
+			wantRetried:   true,
+		},
+		{
+			name:        "retry deleted",
+			succeeded:   corev1.ConditionFalse,
+			annotations: map[string]string{keys.Retries: "2", keys.RetryAttempt: "2", keys.RetriedBy: "tests-fghij"},
+		},
+		{
+			name:        "failed without retries left",
+			succeeded:   corev1.ConditionFalse,
+			annotations: map[string]string{keys.Retries: "2", keys.RetryAttempt: "3"},
+		},
+		{
+			name:        "succeeded",
+			succeeded:   corev1.ConditionTrue,
+			annotations: map[string]string{keys.Retries: "2"},
+		},
+		{
+			name:        "cancelled",
+			succeeded:   corev1.ConditionFalse,
+			specStatus:  tektonv1.PipelineRunSpecStatusCancelled,
+			annotations: map[string]string{keys.Retries: "2"},
+		},
+		{
+			name:      "without retries",
+			succeeded: corev1.ConditionFalse,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			pr := &tektonv1.PipelineRun{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:         "tests-abcde",
+					GenerateName: "tests-",
+					Namespace:    ns,
+					Labels: map[string]string{
+						keys.Repository:     "repo",
+						keys.SHA:            "sha",
+						keys.State:          kubeinteraction.StateStarted,
+						keys.OriginalPRName: "tests",
+					},
+					Annotations: tt.annotations,
+				},
+				Spec: tektonv1.PipelineRunSpec{
+					PipelineRef: &tektonv1.PipelineRef{Name: "tests"},
+					Status:      tt.specStatus,
+				},
+				Status: tektonv1.PipelineRunStatus{Status: knativeduckv1.Status{Conditions: knativeduckv1.Conditions{{
+					Type:   knativeapi.ConditionSucceeded,
+					Status: tt.succeeded,
+				}}}},
+			}
+			waiting := &tektonv1.PipelineRun{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "deploy",
+					Namespace: ns,
+					Labels: map[string]string{
+						keys.Repository: "repo",
+						keys.SHA:        "sha",
+						keys.State:      kubeinteraction.StateWaiting,
+					},
+					Annotations: map[string]string{keys.RunAfterPipelineRuns: "lint,tests-abcde"},
+				},
+			}
+			concurrency := 1
+			repo := &v1alpha1.Repository{
+				ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: ns},
+				Spec:       v1alpha1.RepositorySpec{ConcurrencyLimit: &concurrency},
+			}
+			prs := []*tektonv1.PipelineRun{pr, waiting}
+			if tt.existingRetry {
+				existing := pr.DeepCopy()
+				existing.Name = "tests-fghij"
+				existing.Annotations = map[string]string{keys.Retries: "2", keys.RetryAttempt: "3"}
+				existing.Status = tektonv1.PipelineRunStatus{}
+				prs = append(prs, existing)
+			}
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+				PipelineRuns: prs,
+				Repositories: []*v1alpha1.Repository{repo},
+			})
+			// the fake client doesn't generate the names
+			stdata.Pipeline.PrependReactor("create", "pipelineruns", func(action ktesting.Action) (bool, runtime.Object, error) {
+				created, _ := action.(ktesting.CreateAction).GetObject().(*tektonv1.PipelineRun)
+				assert.Assert(t, !tt.existingRetry, "the retry has been created again")
+				if created.GetName() == "" {
+					created.SetName(created.GetGenerateName() + "fghij")
+				}
+				return false, nil, nil
+			})
+			recorder, err := metrics.NewRecorder()
+			assert.NilError(t, err)
+			qm := sync.NewQueueManager(logger)
+			_, err = qm.AddListToQueue(repo, []string{ns + "/tests-abcde"})
+			assert.NilError(t, err)
+
+			r := &Reconciler{
+				run: &params.Run{
+					Clients: clients.Clients{
+						PipelineAsCode: stdata.PipelineAsCode,
+						Tekton:         stdata.Pipeline,
+						ConsoleUI:      consoleui.FallBackConsole{},
+					},
+					Info: info.Info{Pac: &info.PacOpts{Settings: &settings.Settings{}}},
+				},
+				pipelineRunLister: stdata.PipelineLister,
+				kinteract:         &kubernetestint.KinterfaceTest{},
+				qm:                qm,
+				metrics:           recorder,
+			}
+			retried, err := r.retryPipelineRun(ctx, logger, repo, &testprovider.TestProviderImp{}, &info.Event{SHA: "sha"}, pr)
+			assert.NilError(t, err)
+			assert.Equal(t, retried, tt.wantRetried)
+
+			gotWaiting, err := stdata.Pipeline.TektonV1().PipelineRuns(ns).Get(ctx, "deploy", metav1.GetOptions{})
+			assert.NilError(t, err)
+			if !tt.wantRetried {
+				_, err := stdata.Pipeline.TektonV1().PipelineRuns(ns).Get(ctx, "tests-fghij", metav1.GetOptions{})
+				assert.Assert(t, err != nil)
+				assert.Equal(t, gotWaiting.GetAnnotations()[keys.RunAfterPipelineRuns], "lint,tests-abcde")
+				return
+			}
+
+			retry, err := stdata.Pipeline.TektonV1().PipelineRuns(ns).Get(ctx, "tests-fghij", metav1.GetOptions{})
+			assert.NilError(t, err)
+			assert.Equal(t, retry.GetAnnotations()[keys.RetryAttempt], "3")
+			assert.Equal(t, retry.GetLabels()[keys.State], kubeinteraction.StateStarted)
+			assert.Equal(t, retry.Spec.PipelineRef.Name, "tests")
+
+			failed, err := stdata.Pipeline.TektonV1().PipelineRuns(ns).Get(ctx, "tests-abcde", metav1.GetOptions{})
+			assert.NilError(t, err)
+			assert.Equal(t, failed.GetLabels()[keys.State], kubeinteraction.StateCompleted)
+			assert.Equal(t, failed.GetAnnotations()[keys.RetriedBy], "tests-fghij")
+
+			assert.DeepEqual(t, qm.RunningPipelineRuns(repo), []string{ns + "/tests-fghij"})
+			assert.Equal(t, gotWaiting.GetAnnotations()[keys.RunAfterPipelineRuns], "lint,tests-fghij")
+
+			gotRepo, err := stdata.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(ns).Get(ctx, "repo", metav1.GetOptions{})
+			assert.NilError(t, err)
+			assert.Equal(t, len(gotRepo.Status), 1)
+			assert.Equal(t, gotRepo.Status[0].PipelineRunName, "tests-abcde")
+		})
+	}
+}
+
+func TestRetryAttemptText(t *testing.T) {
+	pr := &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+		keys.Retries: "1", keys.RetryAttempt: "2",
+	}}}
+	assert.Equal(t, retryAttemptText(pr), "<b>Attempt 2 of 2</b>\n\n")
+	assert.Equal(t, retryAttemptText(&tektonv1.PipelineRun{}), "")
+}
@@ -0,0 +1,138 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/action"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	knativeapi "knative.dev/pkg/apis"
+)
+
+// releaseWaitingPipelineRuns handles the PipelineRuns of the same event
+// waiting for the done PipelineRun with the run-after annotation: they are
+// started once all the PipelineRuns they wait for succeeded, or cancelled as
+// soon as one of them didn't succeed.
+func (r *Reconciler) releaseWaitingPipelineRuns(ctx context.Context, logger *zap.SugaredLogger, repo *v1alpha1.Repository, pr *tektonv1.PipelineRun) error {
+	selector := labels.SelectorFromSet(labels.Set{
+		keys.Repository: pr.GetLabels()[keys.Repository],
+		keys.SHA:        pr.GetLabels()[keys.SHA],
+		keys.State:      kubeinteraction.StateWaiting,
+	})
+	waitingPRs, err := r.pipelineRunLister.PipelineRuns(pr.GetNamespace()).List(selector)
+	if err != nil {
+		return fmt.Errorf("cannot list the waiting PipelineRuns: %w", err)
+	}
+
+	for _, waiting := range waitingPRs {
+		if waiting.IsDone() || waiting.Spec.Status != tektonv1.PipelineRunSpecStatusPending {
+			continue
+		}
+		names := strings.Split(waiting.GetAnnotations()[keys.RunAfterPipelineRuns], ",")
+		if !contains(names, pr.GetName()) {
+			continue
+		}
+
+		ready, failed, err := r.runAfterState(pr, names)
+		if err != nil {
+			return err
+		}
+		switch {
+		case failed != "":
+			msg := fmt.Sprintf("PipelineRun %s has been cancelled since %s, which it runs after, did not succeed", waiting.GetName(), failed)
+			r.eventEmitter.EmitMessage(repo, zap.InfoLevel, "RepositoryPipelineRunRunAfter", msg)
+			if _, err := action.PatchPipelineRun(ctx, logger, "cancel waiting", r.run.Clients.Tekton, waiting, map[string]interface{}{
+				"spec": map[string]interface{}{
+					"status": tektonv1.PipelineRunSpecStatusCancelled,
+				},
+			}); err != nil {
+				return err
+			}
+		case ready:
+			if err := r.startWaitingPipelineRun(ctx, logger, repo, waiting); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// runAfterState returns whether all the PipelineRuns a PipelineRun waits for
+// succeeded or the name of one which didn't.
+func (r *Reconciler) runAfterState(done *tektonv1.PipelineRun, names []string) (bool, string, error) {
+	ready := true
+	for _, name := range names {
+		dep := done
+		if name != done.GetName() {
+			var err error
+			dep, err = r.pipelineRunLister.PipelineRuns(done.GetNamespace()).Get(name)
+			if errors.IsNotFound(err) {
+				return false, name, nil
+			}
+			if err != nil {
+				return false, "", fmt.Errorf("cannot get PipelineRun %s: %w", name, err)
+			}
+		}
+		if !dep.IsDone() {
+			ready = false
+			continue
+		}
+		if !dep.Status.GetCondition(knativeapi.ConditionSucceeded).IsTrue() {
+			return false, name, nil
+		}
+	}
+	return ready, "", nil
+}
+
+// startWaitingPipelineRun starts a PipelineRun once the PipelineRuns it waits
+// for succeeded or its maintenance window ended, it goes through the
+// concurrency queue of the Repository when it has a concurrency limit. During
+// a maintenance window it is moved to the maintenance state instead, its
+// reconciliation requeues it until the window ends.
+func (r *Reconciler) startWaitingPipelineRun(ctx context.Context, logger *zap.SugaredLogger, repo *v1alpha1.Repository, pr *tektonv1.PipelineRun) error {
+	if r.run.Info.Pac != nil && r.run.Info.Pac.Settings != nil {
+		if end, inMaintenance := r.run.Info.Pac.MaintenanceEnd(time.Now()); inMaintenance {
+			logger.Infof("a maintenance window is in progress until %s, the PipelineRun %s/%s will be started once it ended",
+				end.Format(time.RFC3339), pr.GetNamespace(), pr.GetName())
+			_, err := action.PatchPipelineRun(ctx, logger, "maintenance waiting", r.run.Clients.Tekton, pr, map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"labels": map[string]string{
+						keys.State: kubeinteraction.StateMaintenance,
+					},
+				},
+			})
+			return err
+		}
+	}
+	if repo.Spec.ConcurrencyLimit != nil && *repo.Spec.ConcurrencyLimit != 0 {
+		_, err := action.PatchPipelineRun(ctx, logger, "queue waiting", r.run.Clients.Tekton, pr, map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"labels": map[string]string{
+					keys.State: kubeinteraction.StateQueued,
+				},
+				"annotations": map[string]string{
+					keys.ExecutionOrder: fmt.Sprintf("%s/%s", pr.GetNamespace(), pr.GetName()),
+				},
+			},
+		})
+		return err
+	}
+	return r.updatePipelineRunToInProgress(ctx, logger, repo, pr)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,147 @@
+package reconciler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/events"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	knativeapi "knative.dev/pkg/apis"
+	knativeduckv1 "knative.dev/pkg/apis/duck/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestReleaseWaitingPipelineRuns(t *testing.T) {
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	ns := "ns"
+	pipelineRun := func(name, state string, succeeded corev1.ConditionStatus, runAfter string) *tektonv1.PipelineRun {
+		pr := &tektonv1.PipelineRun{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: ns,
+				Labels: map[string]string{
+					keys.Repository: "repo",
+					keys.SHA:        "sha",
+					keys.State:      state,
+				},
+				Annotations: map[string]string{},
+			},
+		}
+		if runAfter != "" {
+			pr.Annotations[keys.RunAfterPipelineRuns] = runAfter
+			pr.Spec.Status = tektonv1.PipelineRunSpecStatusPending
+		}
+		if succeeded != "" {
+			pr.Status.Status = knativeduckv1.Status{Conditions: knativeduckv1.Conditions{{
+				Type:   knativeapi.ConditionSucceeded,
+				Status: succeeded,
+			}}}
+		}
+		return pr
+	}
+	concurrency := 1
+
+	tests := []struct {
+		name        string
+		done        *tektonv1.PipelineRun
+		others      []*tektonv1.PipelineRun
+		concurrency *int
+		windows     []settings.MaintenanceWindow
+		wantStatus  tektonv1.PipelineRunSpecStatus
+		wantState   string
+		wantOrder   string
+	}{
+		{
+			name: "waiting for another running pipelinerun",
+			done: pipelineRun("tests", kubeinteraction.StateCompleted, corev1.ConditionTrue, ""),
+			others: []*tektonv1.PipelineRun{
+				pipelineRun("lint", kubeinteraction.StateStarted, corev1.ConditionUnknown, ""),
+				pipelineRun("deploy", kubeinteraction.StateWaiting, "", "tests,lint"),
+			},
+			wantStatus: tektonv1.PipelineRunSpecStatusPending,
+			wantState:  kubeinteraction.StateWaiting,
+		},
+		{
+			name: "cancelled when a pipelinerun failed",
+			done: pipelineRun("tests", kubeinteraction.StateCompleted, corev1.ConditionFalse, ""),
+			others: []*tektonv1.PipelineRun{
+				pipelineRun("deploy", kubeinteraction.StateWaiting, "", "tests"),
+			},
+			wantStatus: tektonv1.PipelineRunSpecStatusCancelled,
+			wantState:  kubeinteraction.StateWaiting,
+		},
+		{
+			name: "queued when all succeeded with a concurrency limit",
+			done: pipelineRun("tests", kubeinteraction.StateCompleted, corev1.ConditionTrue, ""),
+			others: []*tektonv1.PipelineRun{
+				pipelineRun("lint", kubeinteraction.StateCompleted, corev1.ConditionTrue, ""),
+				pipelineRun("deploy", kubeinteraction.StateWaiting, "", "tests,lint"),
+			},
+			concurrency: &concurrency,
+			wantStatus:  tektonv1.PipelineRunSpecStatusPending,
+			wantState:   kubeinteraction.StateQueued,
+			wantOrder:   "ns/deploy",
+		},
+		{
+			name: "moved to maintenance when all succeeded during a maintenance window",
+			done: pipelineRun("tests", kubeinteraction.StateCompleted, corev1.ConditionTrue, ""),
+			others: []*tektonv1.PipelineRun{
+				pipelineRun("deploy", kubeinteraction.StateWaiting, "", "tests"),
+			},
+			concurrency: &concurrency,
+			windows:     []settings.MaintenanceWindow{{Start: time.Now().Add(-time.Hour), End: time.Now().Add(time.Hour)}},
+			wantStatus:  tektonv1.PipelineRunSpecStatusPending,
+			wantState:   kubeinteraction.StateMaintenance,
+		},
+		{
+			name: "not waiting for this pipelinerun",
+			done: pipelineRun("tests", kubeinteraction.StateCompleted, corev1.ConditionFalse, ""),
+			others: []*tektonv1.PipelineRun{
+				pipelineRun("deploy", kubeinteraction.StateWaiting, "", "lint"),
+			},
+			wantStatus: tektonv1.PipelineRunSpecStatusPending,
+			wantState:  kubeinteraction.StateWaiting,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			repo := &v1alpha1.Repository{
+				ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: ns},
+				Spec:       v1alpha1.RepositorySpec{ConcurrencyLimit: tt.concurrency},
+			}
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+				PipelineRuns: append([]*tektonv1.PipelineRun{tt.done}, tt.others...),
+			})
+			r := &Reconciler{
+				run: &params.Run{
+					Clients: clients.Clients{Tekton: stdata.Pipeline},
+					Info:    info.Info{Pac: &info.PacOpts{Settings: &settings.Settings{MaintenanceWindows: tt.windows}}},
+				},
+				pipelineRunLister: stdata.PipelineLister,
+				eventEmitter:      events.NewEventEmitter(stdata.Kube, logger),
+			}
+			assert.NilError(t, r.releaseWaitingPipelineRuns(ctx, logger, repo, tt.done))
+
+			got, err := stdata.Pipeline.TektonV1().PipelineRuns(ns).Get(ctx, "deploy", metav1.GetOptions{})
+			assert.NilError(t, err)
+			assert.Equal(t, got.Spec.Status, tt.wantStatus)
+			assert.Equal(t, got.GetLabels()[keys.State], tt.wantState)
+			assert.Equal(t, got.GetAnnotations()[keys.ExecutionOrder], tt.wantOrder)
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cron"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/pipelineascode"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// runSchedules starts the PipelineRuns of the schedules of the Repository
+// which are due and returns how long to wait for the next one, 0 if the
+// Repository has no schedule. A schedule missed while the controller was down
+// only runs once.
+func (r *RepositoryReconciler) runSchedules(ctx context.Context, logger *zap.SugaredLogger, repo *v1alpha1.Repository, now time.Time) (time.Duration, error) {
+	var wait time.Duration
+	for _, schedule := range repo.Spec.Schedules {
+		cronSchedule, err := cron.Parse(schedule.Cron)
+		if err != nil {
+			r.eventEmitter.EmitMessage(repo, zap.ErrorLevel, "RepositoryScheduleInvalid",
+				fmt.Sprintf("schedule %s is invalid: %v", schedule.Name, err))
+			continue
+		}
+		last, err := r.lastScheduledAt(ctx, repo, schedule.Name)
+		if err != nil {
+			return 0, err
+		}
+		next := cronSchedule.Next(last)
+		if next.IsZero() {
+			continue
+		}
+		if !next.After(now) {
+			// only the last of the missed times runs
+			for n := cronSchedule.Next(next); !n.IsZero() && !n.After(now); n = cronSchedule.Next(n) {
+				next = n
+			}
+			pr, err := r.startScheduledPipelineRun(ctx, repo, schedule, next)
+			if err != nil {
+				r.eventEmitter.EmitMessage(repo, zap.ErrorLevel, "RepositoryScheduleFailed",
+					fmt.Sprintf("cannot start the PipelineRun of schedule %s: %v", schedule.Name, err))
+				return 0, err
+			}
+			logger.Infof("started PipelineRun %s/%s of schedule %s", pr.GetNamespace(), pr.GetName(), schedule.Name)
+			if next = cronSchedule.Next(now); next.IsZero() {
+				continue
+			}
+		}
+		if until := next.Sub(now); wait == 0 || until < wait {
+			wait = until
+		}
+	}
+	return wait, nil
+}
+
+// lastScheduledAt returns the time the last PipelineRun of the schedule was
+// scheduled at, or the creation time of the Repository when the schedule
+// never ran.
+func (r *RepositoryReconciler) lastScheduledAt(ctx context.Context, repo *v1alpha1.Repository, name string) (time.Time, error) {
+	selector := labels.SelectorFromSet(labels.Set{
+		keys.Repository: repo.GetName(),
+		keys.Schedule:   name,
+	}).String()
+	prs, err := r.run.Clients.Tekton.TektonV1().PipelineRuns(repo.GetNamespace()).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return time.Time{}, err
+	}
+	last := repo.GetCreationTimestamp().UTC()
+	for _, pr := range prs.Items {
+		at, err := time.Parse(time.RFC3339, pr.GetAnnotations()[keys.ScheduledAt])
+		if err != nil {
+			at = pr.GetCreationTimestamp().UTC()
+		}
+		if at.After(last) {
+			last = at
+		}
+	}
+	return last, nil
+}
+
+// startScheduledPipelineRun creates the PipelineRun of the Pipeline of the
+// schedule. It doesn't have a state label, the PipelineRun reconciler has
+// nothing to report to the git provider for it.
+func (r *RepositoryReconciler) startScheduledPipelineRun(ctx context.Context, repo *v1alpha1.Repository, schedule v1alpha1.Schedule, at time.Time) (*tektonv1.PipelineRun, error) {
+	names := make([]string, 0, len(schedule.Params))
+	for name := range schedule.Params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	params := make([]tektonv1.Param, 0, len(names))
+	for _, name := range names {
+		params = append(params, tektonv1.Param{Name: name, Value: *tektonv1.NewStructuredValues(schedule.Params[name])})
+	}
+
+	pr := &tektonv1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: fmt.Sprintf("%s-%s-", repo.GetName(), schedule.Name),
+			Namespace:    repo.GetNamespace(),
+			Labels: map[string]string{
+				keys.Repository: repo.GetName(),
+				keys.Schedule:   schedule.Name,
+			},
+			Annotations: map[string]string{
+				keys.ScheduledAt: at.UTC().Format(time.RFC3339),
+			},
+		},
+		Spec: tektonv1.PipelineRunSpec{
+			PipelineRef: &tektonv1.PipelineRef{Name: schedule.Pipeline},
+			Params:      params,
+		},
+	}
+	if schedule.ServiceAccount != "" {
+		pr.Spec.TaskRunTemplate.ServiceAccountName = schedule.ServiceAccount
+	}
+	pipelineascode.InjectRepositoryEnv(pr, repo.Spec.Env)
+	return r.run.Clients.Tekton.TektonV1().PipelineRuns(repo.GetNamespace()).Create(ctx, pr, metav1.CreateOptions{})
+}
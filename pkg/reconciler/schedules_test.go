@@ -0,0 +1,142 @@
+package reconciler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/events"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestRunSchedules(t *testing.T) {
+	created := time.Date(2023, 3, 14, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2023, 3, 15, 10, 30, 0, 0, time.UTC)
+	nightly := v1alpha1.Schedule{
+		Name:           "prune",
+		Cron:           "0 2 * * *",
+		Pipeline:       "prune-previews",
+		Params:         map[string]string{"selector": "preview=true", "age": "24h"},
+		ServiceAccount: "pruner",
+	}
+	scheduledRun := func(schedule, at string) *tektonv1.PipelineRun {
+		return &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{
+			Name:        "repo-" + schedule + "-last",
+			Namespace:   "ns",
+			Labels:      map[string]string{keys.Repository: "repo", keys.Schedule: schedule},
+			Annotations: map[string]string{keys.ScheduledAt: at},
+		}}
+	}
+
+	tests := []struct {
+		name        string
+		schedules   []v1alpha1.Schedule
+		runs        []*tektonv1.PipelineRun
+		wantWait    time.Duration
+		wantStarted string
+		wantEvent   bool
+	}{
+		{
+			name: "no schedule",
+		},
+		{
+			name:        "missed schedule runs once",
+			schedules:   []v1alpha1.Schedule{nightly},
+			wantWait:    15*time.Hour + 30*time.Minute,
+			wantStarted: "2023-03-15T02:00:00Z",
+		},
+		{
+			name:      "schedule already ran",
+			schedules: []v1alpha1.Schedule{nightly},
+			runs:      []*tektonv1.PipelineRun{scheduledRun("prune", "2023-03-15T02:00:00Z")},
+			wantWait:  15*time.Hour + 30*time.Minute,
+		},
+		{
+			name: "waits for the closest schedule",
+			schedules: []v1alpha1.Schedule{
+				nightly,
+				{Name: "hourly", Cron: "@hourly", Pipeline: "report"},
+			},
+			runs: []*tektonv1.PipelineRun{
+				scheduledRun("prune", "2023-03-15T02:00:00Z"),
+				scheduledRun("hourly", "2023-03-15T10:00:00Z"),
+			},
+			wantWait: 30 * time.Minute,
+		},
+		{
+			name:      "invalid cron is skipped",
+			schedules: []v1alpha1.Schedule{{Name: "prune", Cron: "0 2 * *", Pipeline: "prune-previews"}},
+			wantEvent: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			repo := &v1alpha1.Repository{
+				ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns", CreationTimestamp: metav1.NewTime(created)},
+				Spec: v1alpha1.RepositorySpec{
+					URL:       "https://github.com/owner/repo",
+					Schedules: tt.schedules,
+				},
+			}
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+				Repositories: []*v1alpha1.Repository{repo},
+				PipelineRuns: tt.runs,
+			})
+			observer, _ := zapobserver.New(zap.InfoLevel)
+			logger := zap.New(observer).Sugar()
+			r := &RepositoryReconciler{
+				run: &params.Run{Clients: clients.Clients{
+					Kube:   stdata.Kube,
+					Tekton: stdata.Pipeline,
+					Log:    logger,
+				}},
+				eventEmitter: events.NewEventEmitter(stdata.Kube, logger),
+			}
+
+			wait, err := r.runSchedules(ctx, logger, repo, now)
+			assert.NilError(t, err)
+			assert.Equal(t, wait, tt.wantWait)
+
+			prs, err := stdata.Pipeline.TektonV1().PipelineRuns("ns").List(ctx, metav1.ListOptions{})
+			assert.NilError(t, err)
+			if tt.wantStarted == "" {
+				assert.Equal(t, len(prs.Items), len(tt.runs))
+			} else {
+				assert.Equal(t, len(prs.Items), 1)
+				pr := prs.Items[0]
+				assert.Equal(t, pr.GetGenerateName(), "repo-prune-")
+				assert.Equal(t, pr.GetLabels()[keys.Schedule], "prune")
+				assert.Equal(t, pr.GetAnnotations()[keys.ScheduledAt], tt.wantStarted)
+				_, hasState := pr.GetLabels()[keys.State]
+				assert.Assert(t, !hasState)
+				assert.Equal(t, pr.Spec.PipelineRef.Name, "prune-previews")
+				assert.Equal(t, pr.Spec.TaskRunTemplate.ServiceAccountName, "pruner")
+				assert.Equal(t, len(pr.Spec.Params), 2)
+				assert.Equal(t, pr.Spec.Params[0].Name, "age")
+				assert.Equal(t, pr.Spec.Params[1].Value.StringVal, "preview=true")
+
+				// the schedule doesn't run again until its next time
+				wait, err = r.runSchedules(ctx, logger, repo, now)
+				assert.NilError(t, err)
+				assert.Equal(t, wait, tt.wantWait)
+				prs, err = stdata.Pipeline.TektonV1().PipelineRuns("ns").List(ctx, metav1.ListOptions{})
+				assert.NilError(t, err)
+				assert.Equal(t, len(prs.Items), 1)
+			}
+
+			evs, err := stdata.Kube.CoreV1().Events("ns").List(ctx, metav1.ListOptions{})
+			assert.NilError(t, err)
+			assert.Equal(t, len(evs.Items) == 1, tt.wantEvent)
+		})
+	}
+}
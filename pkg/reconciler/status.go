@@ -2,6 +2,7 @@ package reconciler
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -9,6 +10,7 @@ import (
 	"github.com/google/go-github/v49/github"
 	apipac "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
 	pacv1a1 "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/consoleui"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
 	kstatus "github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction/status"
@@ -19,7 +21,10 @@ import (
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	"github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
 	"go.uber.org/zap"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
 	"knative.dev/pkg/apis"
 )
 
@@ -40,45 +45,63 @@ func (r *Reconciler) updateRepoRunStatus(ctx context.Context, logger *zap.Sugare
 	repoStatus := pacv1a1.RepositoryRunStatus{
 		Status:          pr.Status.Status,
 		PipelineRunName: pr.Name,
+		DisplayName:     formatting.PipelineRunDisplayName(pr),
 		StartTime:       pr.Status.StartTime,
 		CompletionTime:  pr.Status.CompletionTime,
 		SHA:             &event.SHA,
 		SHAURL:          &event.SHAURL,
 		Title:           &event.SHATitle,
-		LogURL:          github.String(r.run.Clients.ConsoleUI.DetailURL(pr)),
+		LogURL:          github.String(consoleui.RunLogURL(r.run.Clients.ConsoleUI, r.run.Info.Pac.LogArchiveURL, pr)),
 		EventType:       &event.EventType,
 		TargetBranch:    &refsanitized,
+		TaskRuns:        kstatus.CollectTaskRunsSummary(ctx, r.run, pr),
+		Results:         kstatus.CollectResults(pr),
 	}
 
-	// Get repository again in case it was updated while we were running the CI
-	// we try multiple time until we get right in case of conflicts.
-	// that's what the error message tell us anyway, so i guess we listen.
-	maxRun := 10
-	for i := 0; i < maxRun; i++ {
-		lastrepo, err := r.run.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(
-			pr.GetNamespace()).Get(ctx, repo.Name, metav1.GetOptions{})
+	return r.repoStatuses.add(ctx, repo.GetNamespace()+"/"+repo.GetName(), repoStatus,
+		func(ctx context.Context, statuses []pacv1a1.RepositoryRunStatus) error {
+			return r.appendRepoRunStatuses(ctx, logger, repo, statuses)
+		})
+}
+
+// appendRepoRunStatuses appends the run statuses to the Repository with a
+// merge patch, keeping only the last maxPipelineRunStatusRun of them. The
+// resourceVersion in the patch makes it fail on a concurrent update of the
+// Repository, it is then retried on its latest version.
+func (r *Reconciler) appendRepoRunStatuses(ctx context.Context, logger *zap.SugaredLogger, repo *pacv1a1.Repository, statuses []pacv1a1.RepositoryRunStatus) error {
+	repositories := r.run.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(repo.GetNamespace())
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		lastrepo, err := repositories.Get(ctx, repo.GetName(), metav1.GetOptions{})
 		if err != nil {
 			return err
 		}
 
-		// Append PipelineRun status files to the repo status
-		if len(lastrepo.Status) >= maxPipelineRunStatusRun {
-			copy(lastrepo.Status, lastrepo.Status[len(lastrepo.Status)-maxPipelineRunStatusRun+1:])
-			lastrepo.Status = lastrepo.Status[:maxPipelineRunStatusRun-1]
+		runs := append(lastrepo.Status, statuses...)
+		if len(runs) > maxPipelineRunStatusRun {
+			runs = runs[len(runs)-maxPipelineRunStatusRun:]
 		}
-
-		lastrepo.Status = append(lastrepo.Status, repoStatus)
-		nrepo, err := r.run.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(lastrepo.Namespace).Update(
-			ctx, lastrepo, metav1.UpdateOptions{})
+		patch, err := json.Marshal(map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"resourceVersion": lastrepo.GetResourceVersion(),
+			},
+			"pipelinerun_status": runs,
+		})
 		if err != nil {
-			logger.Infof("Could not update repo %s, retrying %d/%d: %s", lastrepo.Namespace, i, maxRun, err.Error())
-			continue
+			return err
+		}
+		if _, err := repositories.Patch(ctx, lastrepo.GetName(), types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+			if kerrors.IsConflict(err) {
+				logger.Infof("Repository %s/%s has been updated concurrently, retrying the update of its status", lastrepo.GetNamespace(), lastrepo.GetName())
+			}
+			return err
 		}
-		logger.Infof("Repository status of %s has been updated", nrepo.Name)
 		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("cannot update the status of the Repository %s: %w", repo.GetName(), err)
 	}
-
-	return fmt.Errorf("cannot update %s", repo.Name)
+	logger.Infof("Repository status of %s has been updated with %d run(s)", repo.GetName(), len(statuses))
+	return nil
 }
 
 func (r *Reconciler) getFailureSnippet(ctx context.Context, pr *tektonv1.PipelineRun) string {
@@ -98,7 +121,7 @@ func (r *Reconciler) getFailureSnippet(ctx context.Context, pr *tektonv1.Pipelin
 	return fmt.Sprintf("task <b>%s</b> has the status <b>\"%s\"</b>:\n<pre>%s</pre>", sortedTaskInfos[0].Name, sortedTaskInfos[0].Reason, text)
 }
 
-func (r *Reconciler) postFinalStatus(ctx context.Context, logger *zap.SugaredLogger, vcx provider.Interface, event *info.Event, createdPR *tektonv1.PipelineRun) (*tektonv1.PipelineRun, error) {
+func (r *Reconciler) postFinalStatus(ctx context.Context, logger *zap.SugaredLogger, repo *pacv1a1.Repository, vcx provider.Interface, event *info.Event, createdPR *tektonv1.PipelineRun) (*tektonv1.PipelineRun, error) {
 	pr, err := r.run.Clients.Tekton.TektonV1().PipelineRuns(createdPR.GetNamespace()).Get(
 		ctx, createdPR.GetName(), metav1.GetOptions{},
 	)
@@ -131,14 +154,34 @@ func (r *Reconciler) postFinalStatus(ctx context.Context, logger *zap.SugaredLog
 		Status:                  "completed",
 		PipelineRun:             pr,
 		Conclusion:              formatting.PipelineRunStatus(pr),
-		Text:                    taskStatusText,
+		Text:                    retryAttemptText(pr) + taskStatusText,
 		PipelineRunName:         pr.Name,
 		DetailsURL:              r.run.Clients.ConsoleUI.DetailURL(pr),
 		OriginalPipelineRunName: pr.GetLabels()[apipac.OriginalPRName],
 	}
 
-	err = createStatusWithRetry(ctx, logger, r.run.Clients.Tekton, vcx, event, r.run.Info.Pac, status)
 	logger.Infof("pipelinerun %s has a status of '%s'", pr.Name, status.Conclusion)
+	status, err = r.groupStatus(pr, status)
+	if err != nil {
+		return pr, err
+	}
+	err = createStatusWithRetry(ctx, logger, r.run.Clients.Tekton, vcx, event, r.run.Info.Pac, status)
+	r.reportStatusSinks(ctx, logger, repo, event, status)
+	if err != nil {
+		// the provider may be having an outage, keep retrying in the background
+		// and mark the PipelineRun as completed once it is reported
+		r.statusRetries.add(&statusRetry{
+			repo:     repo,
+			provider: vcx,
+			event:    event,
+			pacopts:  r.run.Info.Pac,
+			status:   status,
+			onSuccess: func(ctx context.Context) error {
+				_, err := r.updatePipelineRunState(ctx, logger, pr, kubeinteraction.StateCompleted)
+				return err
+			},
+		})
+	}
 	return pr, err
 }
 
@@ -0,0 +1,114 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/events"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	"github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
+	"go.uber.org/zap"
+	"k8s.io/client-go/util/workqueue"
+)
+
+const (
+	// statusRetryBaseDelay and statusRetryMaxDelay bound the exponential
+	// backoff between two retries of a status update, with
+	// statusRetryMaxAttempts it keeps retrying for about an hour.
+	statusRetryBaseDelay   = 10 * time.Second
+	statusRetryMaxDelay    = 5 * time.Minute
+	statusRetryMaxAttempts = 16
+)
+
+// statusRetry is a final status update which failed to be reported to the
+// provider.
+type statusRetry struct {
+	repo     *v1alpha1.Repository
+	provider provider.Interface
+	event    *info.Event
+	pacopts  *info.PacOpts
+	status   provider.StatusOpts
+	// onSuccess is called when the status has been reported.
+	onSuccess func(context.Context) error
+}
+
+// statusRetryQueue retries in the background the final status updates which
+// failed, so a provider outage longer than the retries done straight away
+// doesn't leave the pull requests showing a pending status forever.
+type statusRetryQueue struct {
+	queue        workqueue.RateLimitingInterface
+	maxAttempts  int
+	tekton       versioned.Interface
+	logger       *zap.SugaredLogger
+	eventEmitter *events.EventEmitter
+}
+
+func newStatusRetryQueue(tekton versioned.Interface, logger *zap.SugaredLogger, eventEmitter *events.EventEmitter, rateLimiter workqueue.RateLimiter) *statusRetryQueue {
+	return &statusRetryQueue{
+		queue:        workqueue.NewNamedRateLimitingQueue(rateLimiter, "StatusRetries"),
+		maxAttempts:  statusRetryMaxAttempts,
+		tekton:       tekton,
+		logger:       logger,
+		eventEmitter: eventEmitter,
+	}
+}
+
+// add queues the status update to be retried after the backoff, it is a no-op
+// on a nil queue.
+func (q *statusRetryQueue) add(item *statusRetry) {
+	if q == nil {
+		return
+	}
+	q.queue.AddRateLimited(item)
+}
+
+// run processes the status updates until the context is done.
+func (q *statusRetryQueue) run(ctx context.Context) {
+	go func() {
+		<-ctx.Done()
+		q.queue.ShutDown()
+	}()
+	for q.processNextItem(ctx) {
+	}
+}
+
+func (q *statusRetryQueue) processNextItem(ctx context.Context) bool {
+	obj, shutdown := q.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer q.queue.Done(obj)
+
+	item, ok := obj.(*statusRetry)
+	if !ok {
+		q.queue.Forget(obj)
+		return true
+	}
+	// the item has been added with the rate limiter in the first place, the
+	// number of requeues is the number of this attempt
+	attempt := q.queue.NumRequeues(item)
+	if err := item.provider.CreateStatus(ctx, q.tekton, item.event, item.pacopts, item.status); err != nil {
+		if attempt < q.maxAttempts {
+			q.logger.Infof("failed to report status of pipelinerun %s on attempt %d, retrying later: %v",
+				item.status.PipelineRunName, attempt, err)
+			q.queue.AddRateLimited(item)
+			return true
+		}
+		q.queue.Forget(item)
+		q.eventEmitter.EmitMessage(item.repo, zap.ErrorLevel, "RepositoryReportFinalStatus",
+			fmt.Sprintf("giving up reporting the status of pipelinerun %s to the provider: %v", item.status.PipelineRunName, err))
+		return true
+	}
+
+	q.queue.Forget(item)
+	q.logger.Infof("status of pipelinerun %s has been reported on attempt %d", item.status.PipelineRunName, attempt)
+	if item.onSuccess != nil {
+		if err := item.onSuccess(ctx); err != nil {
+			q.logger.Errorf("failed to update pipelinerun %s after reporting its status: %v", item.status.PipelineRunName, err)
+		}
+	}
+	return true
+}
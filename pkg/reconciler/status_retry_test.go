@@ -0,0 +1,103 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/events"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	tprovider "github.com/openshift-pipelines/pipelines-as-code/pkg/test/provider"
+	"github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+// flakyProvider fails to create the status until it has been called failures
+// times.
+type flakyProvider struct {
+	tprovider.TestProviderImp
+	failures int
+	calls    int
+}
+
+func (v *flakyProvider) CreateStatus(_ context.Context, _ versioned.Interface, _ *info.Event, _ *info.PacOpts, _ provider.StatusOpts) error {
+	v.calls++
+	if v.calls <= v.failures {
+		return fmt.Errorf("502 bad gateway")
+	}
+	return nil
+}
+
+func TestStatusRetryQueue(t *testing.T) {
+	tests := []struct {
+		name          string
+		failures      int
+		wantCalls     int
+		wantSucceeded bool
+		wantEvent     bool
+	}{
+		{
+			name:          "reported after a few retries",
+			failures:      3,
+			wantCalls:     4,
+			wantSucceeded: true,
+		},
+		{
+			name:      "give up after the max attempts",
+			failures:  100,
+			wantCalls: 5,
+			wantEvent: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{})
+			observer, _ := zapobserver.New(zap.InfoLevel)
+			logger := zap.New(observer).Sugar()
+
+			q := newStatusRetryQueue(stdata.Pipeline, logger, events.NewEventEmitter(stdata.Kube, logger),
+				workqueue.NewItemExponentialFailureRateLimiter(0, 0))
+			q.maxAttempts = 5
+			vcx := &flakyProvider{failures: tt.failures}
+			succeeded := false
+			q.add(&statusRetry{
+				repo:     &v1alpha1.Repository{ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"}},
+				provider: vcx,
+				event:    info.NewEvent(),
+				pacopts:  &info.PacOpts{},
+				status:   provider.StatusOpts{PipelineRunName: "pr1", Conclusion: "success"},
+				onSuccess: func(context.Context) error {
+					succeeded = true
+					return nil
+				},
+			})
+			for i := 0; i < tt.wantCalls; i++ {
+				assert.Assert(t, q.processNextItem(ctx))
+			}
+			assert.Equal(t, vcx.calls, tt.wantCalls)
+			assert.Equal(t, succeeded, tt.wantSucceeded)
+			assert.Equal(t, q.queue.Len(), 0)
+
+			evs, err := stdata.Kube.CoreV1().Events("ns").List(ctx, metav1.ListOptions{})
+			assert.NilError(t, err)
+			assert.Equal(t, len(evs.Items) == 1, tt.wantEvent)
+
+			q.queue.ShutDown()
+			assert.Assert(t, !q.processNextItem(ctx))
+		})
+	}
+}
+
+func TestStatusRetryQueueNil(t *testing.T) {
+	var q *statusRetryQueue
+	q.add(&statusRetry{})
+}
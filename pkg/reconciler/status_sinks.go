@@ -0,0 +1,211 @@
+package reconciler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/pipelineascode"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gitea"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gitlab"
+	ktypes "github.com/openshift-pipelines/pipelines-as-code/pkg/secrets/types"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/templates"
+	"go.uber.org/zap"
+)
+
+// statusSinkPayload is the status posted as JSON to a webhook status sink.
+type statusSinkPayload struct {
+	Repository          string `json:"repository"`
+	Namespace           string `json:"namespace"`
+	URL                 string `json:"url"`
+	SHA                 string `json:"sha"`
+	EventType           string `json:"event_type"`
+	Branch              string `json:"branch"`
+	PullRequestNumber   int    `json:"pull_request_number,omitempty"`
+	PipelineRun         string `json:"pipelinerun"`
+	OriginalPipelineRun string `json:"original_pipelinerun"`
+	Status              string `json:"status"`
+	Conclusion          string `json:"conclusion"`
+	Description         string `json:"description"`
+	DetailsURL          string `json:"details_url"`
+}
+
+// statusSinkTimeout is how long a status sink has to answer, the sinks are
+// set by the authors of the Repositories and may never answer.
+var statusSinkTimeout = 30 * time.Second
+
+// reportStatusSinks reports the status of a PipelineRun to the status sinks
+// of the Repository. The status has already been reported to the git
+// provider of the Repository, the sinks are reported in the background so a
+// slow sink doesn't hold the worker, the errors are emitted as events of the
+// Repository and don't fail the PipelineRun.
+func (r *Reconciler) reportStatusSinks(ctx context.Context, logger *zap.SugaredLogger, repo *v1alpha1.Repository, event *info.Event, status provider.StatusOpts) {
+	if repo == nil {
+		return
+	}
+	for _, sink := range repo.Spec.StatusSinks {
+		r.statusSinks.Add(1)
+		go func(sink v1alpha1.StatusSink) {
+			defer r.statusSinks.Done()
+			ctx, cancel := context.WithTimeout(ctx, statusSinkTimeout)
+			defer cancel()
+			if err := r.reportStatusSink(ctx, logger, repo, sink, event, status); err != nil {
+				r.eventEmitter.EmitMessage(repo, zap.WarnLevel, "RepositoryStatusSinkFailed",
+					fmt.Sprintf("cannot report the status of pipelinerun %s to the status sink %s: %v", status.PipelineRunName, sink.Name, err))
+			}
+		}(sink)
+	}
+}
+
+func (r *Reconciler) reportStatusSink(ctx context.Context, logger *zap.SugaredLogger, repo *v1alpha1.Repository, sink v1alpha1.StatusSink, event *info.Event, status provider.StatusOpts) error {
+	token := ""
+	if sink.Secret != nil && sink.Secret.Name != "" {
+		key := sink.Secret.Key
+		if key == "" {
+			key = pipelineascode.DefaultGitProviderSecretKey
+		}
+		var err error
+		token, err = r.kinteract.GetSecret(ctx, ktypes.GetSecretOpt{Namespace: repo.GetNamespace(), Name: sink.Secret.Name, Key: key})
+		if err != nil {
+			return fmt.Errorf("cannot get the secret %s: %w", sink.Secret.Name, err)
+		}
+	}
+	status.Title = statusSinkDescription(sink, repo, event, status)
+
+	if sink.Type == v1alpha1.StatusSinkTypeWebhook {
+		return r.postStatusSinkWebhook(ctx, sink, token, repo, event, status)
+	}
+	return r.setStatusSinkMirror(ctx, logger, sink, token, event, status)
+}
+
+// statusSinkDescription returns the description of the status reported to
+// the sink, its template is expanded with the dynamic variables and the
+// variables of the status.
+func statusSinkDescription(sink v1alpha1.StatusSink, repo *v1alpha1.Repository, event *info.Event, status provider.StatusOpts) string {
+	if sink.Template == "" {
+		if status.Status == "in_progress" {
+			return fmt.Sprintf("%s is running", status.OriginalPipelineRunName)
+		}
+		return fmt.Sprintf("%s has finished with the %s conclusion", status.OriginalPipelineRunName, status.Conclusion)
+	}
+	description := templates.Process(event, repo, sink.Template)
+	return templates.ReplacePlaceHoldersVariables(description, map[string]string{
+		"pipelinerun":      status.OriginalPipelineRunName,
+		"pipelinerun_name": status.PipelineRunName,
+		"status":           status.Status,
+		"conclusion":       status.Conclusion,
+		"details_url":      status.DetailsURL,
+	})
+}
+
+func (r *Reconciler) postStatusSinkWebhook(ctx context.Context, sink v1alpha1.StatusSink, token string, repo *v1alpha1.Repository, event *info.Event, status provider.StatusOpts) error {
+	body, err := json.Marshal(statusSinkPayload{
+		Repository:          repo.GetName(),
+		Namespace:           repo.GetNamespace(),
+		URL:                 event.URL,
+		SHA:                 event.SHA,
+		EventType:           event.EventType,
+		Branch:              event.BaseBranch,
+		PullRequestNumber:   event.PullRequestNumber,
+		PipelineRun:         status.PipelineRunName,
+		OriginalPipelineRun: status.OriginalPipelineRunName,
+		Status:              status.Status,
+		Conclusion:          status.Conclusion,
+		Description:         status.Title,
+		DetailsURL:          status.DetailsURL,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sink.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	res, err := r.run.Clients.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("the webhook has answered with the status %d", res.StatusCode)
+	}
+	return nil
+}
+
+// setStatusSinkMirror sets a commit status on the same commit of the mirror
+// of the repository, the mirror is updated as if it was a push.
+func (r *Reconciler) setStatusSinkMirror(ctx context.Context, logger *zap.SugaredLogger, sink v1alpha1.StatusSink, token string, event *info.Event, status provider.StatusOpts) error {
+	var p provider.Interface
+	switch sink.Type {
+	case "github":
+		p = github.New()
+	case "gitlab":
+		p = &gitlab.Provider{}
+	case "gitea":
+		p = &gitea.Provider{}
+	default:
+		return fmt.Errorf("unsupported status sink type %s", sink.Type)
+	}
+	setter, ok := p.(provider.CommitStatusSetter)
+	if !ok {
+		return fmt.Errorf("cannot set a commit status on a %s mirror", sink.Type)
+	}
+	apiURL, err := statusSinkAPIURL(sink)
+	if err != nil {
+		return err
+	}
+	org, repoName, err := formatting.GetRepoOwnerSplitted(sink.URL)
+	if err != nil {
+		return err
+	}
+
+	mirror := info.NewEvent()
+	mirror.URL = sink.URL
+	mirror.Organization = org
+	mirror.Repository = repoName
+	mirror.SHA = event.SHA
+	mirror.EventType = "push"
+	mirror.TriggerTarget = "push"
+	mirror.BaseBranch = event.BaseBranch
+	mirror.HeadBranch = event.BaseBranch
+	mirror.Provider.URL = apiURL
+	mirror.Provider.Token = token
+
+	p.SetLogger(logger)
+	if err := p.SetClient(ctx, r.run, mirror); err != nil {
+		return err
+	}
+	return setter.SetCommitStatus(ctx, mirror, r.run.Info.Pac, status)
+}
+
+// statusSinkAPIURL returns the API URL of the git provider of a mirror, the
+// public API is used for github.com and gitlab guesses it from the URL of
+// the mirror.
+func statusSinkAPIURL(sink v1alpha1.StatusSink) (string, error) {
+	if sink.APIURL != "" {
+		return sink.APIURL, nil
+	}
+	u, err := url.Parse(sink.URL)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case sink.Type == "gitlab", sink.Type == "github" && u.Host == "github.com":
+		return "", nil
+	default:
+		return fmt.Sprintf("%s://%s", u.Scheme, u.Host), nil
+	}
+}
@@ -0,0 +1,202 @@
+package reconciler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/events"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/test/kubernetestint"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestReportStatusSinks(t *testing.T) {
+	ns := "ns"
+	sha := "0123456789abcdef0123456789abcdef01234567"
+	status := provider.StatusOpts{
+		Status:                  "completed",
+		Conclusion:              "failure",
+		PipelineRunName:         "pull-request-abcde",
+		OriginalPipelineRunName: "pull-request",
+		DetailsURL:              "https://console/pull-request-abcde",
+	}
+
+	ctx, _ := rtesting.SetupFakeContext(t)
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+		Namespaces: []*corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: ns}}},
+	})
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+
+	var webhookPayload statusSinkPayload
+	var webhookAuth string
+	var mirrorStatus map[string]string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		webhookAuth = r.Header.Get("Authorization")
+		assert.NilError(t, json.NewDecoder(r.Body).Decode(&webhookPayload))
+	})
+	mux.HandleFunc("/broken", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	mux.HandleFunc(fmt.Sprintf("/api/v3/repos/owner/mirror/statuses/%s", sha), func(w http.ResponseWriter, r *http.Request) {
+		assert.NilError(t, json.NewDecoder(r.Body).Decode(&mirrorStatus))
+		fmt.Fprint(w, `{}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	repo := &v1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: ns},
+		Spec: v1alpha1.RepositorySpec{
+			URL: "https://forge.example.com/owner/repo",
+			StatusSinks: []v1alpha1.StatusSink{
+				{
+					Name:   "status-api",
+					Type:   v1alpha1.StatusSinkTypeWebhook,
+					URL:    server.URL + "/status",
+					Secret: &v1alpha1.Secret{Name: "status-api", Key: "token"},
+				},
+				{
+					Name:     "mirror",
+					Type:     "github",
+					URL:      "https://ghe.example.com/owner/mirror",
+					APIURL:   server.URL,
+					Secret:   &v1alpha1.Secret{Name: "mirror"},
+					Template: "{{ pipelinerun }} on {{ revision | sha_short }}: {{ conclusion }}",
+				},
+				{
+					Name: "broken",
+					Type: v1alpha1.StatusSinkTypeWebhook,
+					URL:  server.URL + "/broken",
+				},
+			},
+		},
+	}
+	r := &Reconciler{
+		run: &params.Run{
+			Clients: clients.Clients{Kube: stdata.Kube, HTTP: *server.Client()},
+			Info:    info.Info{Pac: &info.PacOpts{Settings: &settings.Settings{ApplicationName: "Pipelines as Code CI"}}},
+		},
+		kinteract: &kubernetestint.KinterfaceTest{
+			GetSecretResult: map[string]string{"status-api": "api-token", "mirror": "mirror-token"},
+		},
+		eventEmitter: events.NewEventEmitter(stdata.Kube, logger),
+	}
+	event := &info.Event{URL: repo.Spec.URL, SHA: sha, EventType: "push", BaseBranch: "main"}
+	r.reportStatusSinks(ctx, logger, repo, event, status)
+	r.statusSinks.Wait()
+
+	assert.Equal(t, webhookAuth, "Bearer api-token")
+	assert.DeepEqual(t, webhookPayload, statusSinkPayload{
+		Repository:          "repo",
+		Namespace:           ns,
+		URL:                 "https://forge.example.com/owner/repo",
+		SHA:                 sha,
+		EventType:           "push",
+		Branch:              "main",
+		PipelineRun:         "pull-request-abcde",
+		OriginalPipelineRun: "pull-request",
+		Status:              "completed",
+		Conclusion:          "failure",
+		Description:         "pull-request has finished with the failure conclusion",
+		DetailsURL:          "https://console/pull-request-abcde",
+	})
+
+	assert.Equal(t, mirrorStatus["state"], "failure")
+	assert.Equal(t, mirrorStatus["description"], "pull-request on 0123456: failure")
+	assert.Equal(t, mirrorStatus["context"], "Pipelines as Code CI / pull-request")
+	assert.Equal(t, mirrorStatus["target_url"], "https://console/pull-request-abcde")
+
+	kevents, err := stdata.Kube.CoreV1().Events(ns).List(ctx, metav1.ListOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(kevents.Items), 1)
+	assert.Equal(t, kevents.Items[0].Reason, "RepositoryStatusSinkFailed")
+	assert.Equal(t, kevents.Items[0].Message,
+		"cannot report the status of pipelinerun pull-request-abcde to the status sink broken: the webhook has answered with the status 500")
+}
+
+func TestReportStatusSinksTimeout(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	ns := "ns"
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{})
+
+	defer func(timeout time.Duration) { statusSinkTimeout = timeout }(statusSinkTimeout)
+	statusSinkTimeout = 100 * time.Millisecond
+	// the sink never answers until the test ends
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	repo := &v1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: ns},
+		Spec: v1alpha1.RepositorySpec{
+			URL: "https://forge.example.com/owner/repo",
+			StatusSinks: []v1alpha1.StatusSink{{
+				Name: "black-hole",
+				Type: v1alpha1.StatusSinkTypeWebhook,
+				URL:  server.URL + "/status",
+			}},
+		},
+	}
+	r := &Reconciler{
+		run: &params.Run{
+			Clients: clients.Clients{Kube: stdata.Kube, HTTP: *server.Client()},
+			Info:    info.Info{Pac: &info.PacOpts{Settings: &settings.Settings{}}},
+		},
+		kinteract:    &kubernetestint.KinterfaceTest{},
+		eventEmitter: events.NewEventEmitter(stdata.Kube, logger),
+	}
+	event := &info.Event{URL: repo.Spec.URL, SHA: "sha", EventType: "push", BaseBranch: "main"}
+
+	start := time.Now()
+	r.reportStatusSinks(ctx, logger, repo, event, provider.StatusOpts{PipelineRunName: "pr-abcde", Status: "in_progress"})
+	assert.Assert(t, time.Since(start) < statusSinkTimeout, "the status sinks should be reported in the background")
+	r.statusSinks.Wait()
+
+	kevents, err := stdata.Kube.CoreV1().Events(ns).List(ctx, metav1.ListOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(kevents.Items), 1)
+	assert.Assert(t, strings.Contains(kevents.Items[0].Message, "context deadline exceeded"), kevents.Items[0].Message)
+}
+
+func TestStatusSinkAPIURL(t *testing.T) {
+	tests := []struct {
+		sink v1alpha1.StatusSink
+		want string
+	}{
+		{sink: v1alpha1.StatusSink{Type: "github", URL: "https://github.com/owner/repo"}, want: ""},
+		{sink: v1alpha1.StatusSink{Type: "github", URL: "https://ghe.example.com/owner/repo"}, want: "https://ghe.example.com"},
+		{sink: v1alpha1.StatusSink{Type: "gitlab", URL: "https://gitlab.example.com/group/repo"}, want: ""},
+		{sink: v1alpha1.StatusSink{Type: "gitea", URL: "https://gitea.example.com/owner/repo"}, want: "https://gitea.example.com"},
+		{sink: v1alpha1.StatusSink{Type: "gitea", URL: "https://gitea.example.com/owner/repo", APIURL: "https://api.example.com"}, want: "https://api.example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sink.URL, func(t *testing.T) {
+			got, err := statusSinkAPIURL(tt.sink)
+			assert.NilError(t, err)
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
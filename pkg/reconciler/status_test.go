@@ -64,6 +64,6 @@ func TestPostFinalStatus(t *testing.T) {
 	r := &Reconciler{
 		run: run,
 	}
-	_, err := r.postFinalStatus(ctx, fakelogger, vcx, info.NewEvent(), pr1)
+	_, err := r.postFinalStatus(ctx, fakelogger, nil, vcx, info.NewEvent(), pr1)
 	assert.NilError(t, err)
 }
@@ -0,0 +1,124 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+)
+
+// dockerHubRegistry is the host serving the registry API of Docker Hub, the
+// images are named with index.docker.io.
+const dockerHubRegistry = "registry-1.docker.io"
+
+// manifestMediaTypes are the manifests accepted, the indexes first so the
+// digest of a multi-architecture image is the one of its index.
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// Digest returns the digest of the manifest a tag of an image points to, ie:
+// sha256:4f2a..., by asking the registry. Only the registries allowing to pull
+// anonymously are supported, the anonymous token is requested when the
+// registry asks for one.
+func Digest(ctx context.Context, client *http.Client, tag name.Tag) (string, error) {
+	host := tag.RegistryStr()
+	if host == name.DefaultRegistry {
+		host = dockerHubRegistry
+	}
+	u := fmt.Sprintf("%s://%s/v2/%s/manifests/%s", tag.Registry.Scheme(), host, tag.RepositoryStr(), tag.TagStr())
+
+	res, err := headManifest(ctx, client, u, "")
+	if err != nil {
+		return "", err
+	}
+	if res.StatusCode == http.StatusUnauthorized {
+		token, err := anonymousToken(ctx, client, res.Header.Get("WWW-Authenticate"), tag.RepositoryStr())
+		if err != nil {
+			return "", fmt.Errorf("cannot authenticate to %s: %w", tag.RegistryStr(), err)
+		}
+		if res, err = headManifest(ctx, client, u, token); err != nil {
+			return "", err
+		}
+	}
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("cannot get the manifest of %s: %s", tag.String(), res.Status)
+	}
+	digest := res.Header.Get("Docker-Content-Digest")
+	if digest == "" {
+		return "", fmt.Errorf("the registry %s didn't return the digest of %s", tag.RegistryStr(), tag.String())
+	}
+	return digest, nil
+}
+
+func headManifest(ctx context.Context, client *http.Client, u, token string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	res.Body.Close()
+	return res, nil
+}
+
+// anonymousToken gets a token to pull the repository from the realm of the
+// bearer challenge of the registry, ie:
+//
+//	Bearer realm="https://auth.docker.io/token",service="registry.docker.io"
+func anonymousToken(ctx context.Context, client *http.Client, challenge, repository string) (string, error) {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	if !strings.EqualFold(scheme, "bearer") {
+		return "", fmt.Errorf("unsupported authentication challenge %q", challenge)
+	}
+	values := map[string]string{}
+	for _, param := range strings.Split(params, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+		values[strings.ToLower(key)] = strings.Trim(value, `"`)
+	}
+	if values["realm"] == "" {
+		return "", fmt.Errorf("no realm in the authentication challenge %q", challenge)
+	}
+
+	query := url.Values{}
+	if values["service"] != "" {
+		query.Set("service", values["service"])
+	}
+	query.Set("scope", fmt.Sprintf("repository:%s:pull", repository))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, values["realm"]+"?"+query.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("cannot get a token: %s", res.Status)
+	}
+	token := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := json.NewDecoder(res.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("cannot decode the token: %w", err)
+	}
+	if token.Token != "" {
+		return token.Token, nil
+	}
+	return token.AccessToken, nil
+}
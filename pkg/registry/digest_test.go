@@ -0,0 +1,77 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"gotest.tools/v3/assert"
+)
+
+func TestDigest(t *testing.T) {
+	digest := "sha256:4f2a1d3e5b6c7d8e9f0a1b2c3d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0c1d2e"
+	tests := []struct {
+		name      string
+		image     string
+		withToken bool
+		want      string
+		wantErr   string
+	}{
+		{
+			name:  "anonymous registry",
+			image: "library/golang:1.20",
+			want:  digest,
+		},
+		{
+			name:      "anonymous token asked",
+			image:     "library/golang:1.20",
+			withToken: true,
+			want:      digest,
+		},
+		{
+			name:    "unknown tag",
+			image:   "library/golang:0.1",
+			wantErr: "/library/golang:0.1: 404 Not Found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			server := httptest.NewServer(mux)
+			defer server.Close()
+			mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, r.URL.Query().Get("service"), "registry.test")
+				assert.Equal(t, r.URL.Query().Get("scope"), "repository:library/golang:pull")
+				fmt.Fprint(w, `{"token": "anonymous"}`)
+			})
+			mux.HandleFunc("/v2/library/golang/manifests/", func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, r.Method, http.MethodHead)
+				assert.Assert(t, strings.Contains(r.Header.Get("Accept"), "application/vnd.oci.image.index.v1+json"))
+				if tt.withToken && r.Header.Get("Authorization") != "Bearer anonymous" {
+					w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registry.test"`, server.URL))
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				if !strings.HasSuffix(r.URL.Path, "/1.20") {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				w.Header().Set("Docker-Content-Digest", digest)
+			})
+
+			tag, err := name.NewTag(strings.TrimPrefix(server.URL, "http://") + "/" + tt.image)
+			assert.NilError(t, err)
+			got, err := Digest(context.Background(), server.Client(), tag)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package resolve
+
+import (
+	"fmt"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	// GitCloneTaskName is the name of the task cloning the repository
+	// prepended to the PipelineRuns.
+	GitCloneTaskName = "pac-git-clone"
+	// GitCloneDefaultWorkspace is the workspace the repository is cloned in
+	// when the Repository doesn't set one.
+	GitCloneDefaultWorkspace = "source"
+	// GitCloneDefaultImage is the image of the step cloning the repository
+	// when the Repository doesn't set one.
+	GitCloneDefaultImage = "gcr.io/tekton-releases/github.com/tektoncd/pipeline/cmd/git-init:v0.40.2"
+
+	// gitCloneAuthWorkspace is the workspace of the Pipeline bound to the
+	// git-auth secret.
+	gitCloneAuthWorkspace = "pac-git-auth"
+	// gitCloneWorkspaceSize is the size of the volume claim template bound
+	// to the workspace when the PipelineRun doesn't bind it.
+	gitCloneWorkspaceSize = "1Gi"
+)
+
+// gitCloneScript clones the revision in the output workspace, with the
+// credentials of the basic-auth workspace when it is bound.
+const gitCloneScript = `#!/usr/bin/env sh
+set -eu
+if [ "$(workspaces.basic-auth.bound)" = "true" ]; then
+  cp "$(workspaces.basic-auth.path)/.git-credentials" "${HOME}/.git-credentials"
+  cp "$(workspaces.basic-auth.path)/.gitconfig" "${HOME}/.gitconfig"
+  chmod 400 "${HOME}/.git-credentials"
+fi
+git config --global --add safe.directory "$(workspaces.output.path)"
+cd "$(workspaces.output.path)"
+git init -q .
+git remote remove origin 2>/dev/null || true
+git remote add origin "${PARAM_URL}"
+git fetch -q --depth 1 origin "${PARAM_REVISION}"
+git checkout -q -f FETCH_HEAD
+`
+
+// GitCloneInjection is the task cloning the repository of the event
+// prepended to the PipelineRuns.
+type GitCloneInjection struct {
+	Workspace string
+	Image     string
+	// AuthSecret is the git-auth secret bound to the task to clone the
+	// private repositories, no secret is bound when it is empty.
+	AuthSecret string
+}
+
+// injectGitClone prepends the task cloning the repository at the revision of
+// the event to the tasks of the PipelineSpec of the PipelineRun, the tasks
+// which don't run after another one run after it. The workspace is declared
+// on the Pipeline and bound to a volume claim template when the PipelineRun
+// doesn't bind it already.
+func injectGitClone(pipelinerun *tektonv1.PipelineRun, event *info.Event, gitClone *GitCloneInjection) error {
+	if gitClone == nil || pipelinerun.Spec.PipelineSpec == nil {
+		return nil
+	}
+	// the spec may be the one of a Pipeline shared with other PipelineRuns
+	spec := pipelinerun.Spec.PipelineSpec.DeepCopy()
+	for _, task := range spec.Tasks {
+		if task.Name == GitCloneTaskName {
+			return fmt.Errorf("the pipelinerun %s already has a task named %s", pipelinerun.GetName(), GitCloneTaskName)
+		}
+	}
+	workspace := gitClone.Workspace
+	if workspace == "" {
+		workspace = GitCloneDefaultWorkspace
+	}
+	image := gitClone.Image
+	if image == "" {
+		image = GitCloneDefaultImage
+	}
+	repoURL := event.URL
+	if event.CloneURL != "" {
+		repoURL = event.CloneURL
+	}
+
+	task := tektonv1.PipelineTask{
+		Name: GitCloneTaskName,
+		TaskSpec: &tektonv1.EmbeddedTask{TaskSpec: tektonv1.TaskSpec{
+			Params: []tektonv1.ParamSpec{
+				{Name: "url", Type: tektonv1.ParamTypeString},
+				{Name: "revision", Type: tektonv1.ParamTypeString},
+			},
+			Workspaces: []tektonv1.WorkspaceDeclaration{
+				{Name: "output"},
+				{Name: "basic-auth", Optional: true},
+			},
+			Steps: []tektonv1.Step{{
+				Name:  "clone",
+				Image: image,
+				Env: []corev1.EnvVar{
+					{Name: "PARAM_URL", Value: "$(params.url)"},
+					{Name: "PARAM_REVISION", Value: "$(params.revision)"},
+				},
+				Script: gitCloneScript,
+			}},
+		}},
+		Params: []tektonv1.Param{
+			{Name: "url", Value: *tektonv1.NewStructuredValues(repoURL)},
+			{Name: "revision", Value: *tektonv1.NewStructuredValues(event.SHA)},
+		},
+		Workspaces: []tektonv1.WorkspacePipelineTaskBinding{{Name: "output", Workspace: workspace}},
+	}
+	if gitClone.AuthSecret != "" {
+		task.Workspaces = append(task.Workspaces, tektonv1.WorkspacePipelineTaskBinding{Name: "basic-auth", Workspace: gitCloneAuthWorkspace})
+		spec.Workspaces = append(spec.Workspaces, tektonv1.PipelineWorkspaceDeclaration{Name: gitCloneAuthWorkspace})
+		pipelinerun.Spec.Workspaces = append(pipelinerun.Spec.Workspaces, tektonv1.WorkspaceBinding{
+			Name:   gitCloneAuthWorkspace,
+			Secret: &corev1.SecretVolumeSource{SecretName: gitClone.AuthSecret},
+		})
+	}
+
+	for i := range spec.Tasks {
+		if len(spec.Tasks[i].RunAfter) == 0 {
+			spec.Tasks[i].RunAfter = []string{GitCloneTaskName}
+		}
+	}
+	spec.Tasks = append([]tektonv1.PipelineTask{task}, spec.Tasks...)
+
+	declared := false
+	for _, ws := range spec.Workspaces {
+		if ws.Name == workspace {
+			declared = true
+		}
+	}
+	if !declared {
+		spec.Workspaces = append(spec.Workspaces, tektonv1.PipelineWorkspaceDeclaration{Name: workspace})
+	}
+	bound := false
+	for _, ws := range pipelinerun.Spec.Workspaces {
+		if ws.Name == workspace {
+			bound = true
+		}
+	}
+	if !bound {
+		pipelinerun.Spec.Workspaces = append(pipelinerun.Spec.Workspaces, tektonv1.WorkspaceBinding{
+			Name: workspace,
+			VolumeClaimTemplate: &corev1.PersistentVolumeClaim{Spec: corev1.PersistentVolumeClaimSpec{
+				AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+					corev1.ResourceStorage: resource.MustParse(gitCloneWorkspaceSize),
+				}},
+			}},
+		})
+	}
+	pipelinerun.Spec.PipelineSpec = spec
+	return nil
+}
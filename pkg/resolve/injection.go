@@ -0,0 +1,64 @@
+package resolve
+
+import (
+	"fmt"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+)
+
+// injectPipelineRun adds the injected steps and sidecars to every task
+// embedded in the PipelineSpec of the PipelineRun. The tasks still referenced
+// (bundles, resolvers or cluster tasks) are not resolved by Pipelines as Code
+// and cannot be injected. A step or a sidecar of a task having the name of an
+// injected one is an error rather than being shadowed.
+func injectPipelineRun(pipelinerun *tektonv1.PipelineRun, injection *settings.PipelineRunInjection) error {
+	if injection == nil || pipelinerun.Spec.PipelineSpec == nil {
+		return nil
+	}
+	// the spec may be the one of a Pipeline shared with other PipelineRuns
+	spec := pipelinerun.Spec.PipelineSpec.DeepCopy()
+	for _, tasks := range [][]tektonv1.PipelineTask{spec.Tasks, spec.Finally} {
+		for i := range tasks {
+			if tasks[i].TaskSpec == nil {
+				continue
+			}
+			if err := injectTask(&tasks[i].TaskSpec.TaskSpec, injection); err != nil {
+				return fmt.Errorf("cannot inject in the task %s of the pipelinerun %s: %w", tasks[i].Name, pipelinerun.GetName(), err)
+			}
+		}
+	}
+	pipelinerun.Spec.PipelineSpec = spec
+	return nil
+}
+
+func injectTask(spec *tektonv1.TaskSpec, injection *settings.PipelineRunInjection) error {
+	for _, injected := range append(append([]tektonv1.Step{}, injection.StepsBefore...), injection.StepsAfter...) {
+		for _, step := range spec.Steps {
+			if step.Name == injected.Name {
+				return fmt.Errorf("the task already has a step named %s", step.Name)
+			}
+		}
+	}
+	for _, injected := range injection.Sidecars {
+		for _, sidecar := range spec.Sidecars {
+			if sidecar.Name == injected.Name {
+				return fmt.Errorf("the task already has a sidecar named %s", sidecar.Name)
+			}
+		}
+	}
+
+	steps := make([]tektonv1.Step, 0, len(injection.StepsBefore)+len(spec.Steps)+len(injection.StepsAfter))
+	for _, step := range injection.StepsBefore {
+		steps = append(steps, *step.DeepCopy())
+	}
+	steps = append(steps, spec.Steps...)
+	for _, step := range injection.StepsAfter {
+		steps = append(steps, *step.DeepCopy())
+	}
+	spec.Steps = steps
+	for _, sidecar := range injection.Sidecars {
+		spec.Sidecars = append(spec.Sidecars, *sidecar.DeepCopy())
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package resolve
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	apipac "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/registry"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.uber.org/zap"
+)
+
+// digestFunc returns the digest of the manifest an image tag points to.
+type digestFunc func(ctx context.Context, tag name.Tag) (string, error)
+
+// imagePinner pins the step images to the digests of their tags, the digest
+// of an image is only asked once to the registry for all the PipelineRuns.
+type imagePinner struct {
+	logger  *zap.SugaredLogger
+	digest  digestFunc
+	digests map[string]string
+}
+
+func newImagePinner(logger *zap.SugaredLogger, client *http.Client) *imagePinner {
+	return &imagePinner{
+		logger: logger,
+		digest: func(ctx context.Context, tag name.Tag) (string, error) {
+			return registry.Digest(ctx, client, tag)
+		},
+		digests: map[string]string{},
+	}
+}
+
+// pinPipelineRun replaces the floating tags of the step images of the tasks
+// embedded in the PipelineRun by their digests and records the mapping of the
+// images to their digests in an annotation. The images already pinned, using
+// a variable or whose digest cannot be resolved are left as is, a registry
+// error doesn't prevent the PipelineRun from running.
+func (ip *imagePinner) pinPipelineRun(ctx context.Context, pipelinerun *tektonv1.PipelineRun) {
+	if pipelinerun.Spec.PipelineSpec == nil {
+		return
+	}
+	// the spec may be the one of a Pipeline shared with other PipelineRuns
+	spec := pipelinerun.Spec.PipelineSpec.DeepCopy()
+	pinned := map[string]string{}
+	for _, tasks := range [][]tektonv1.PipelineTask{spec.Tasks, spec.Finally} {
+		for i := range tasks {
+			if tasks[i].TaskSpec == nil {
+				continue
+			}
+			steps := tasks[i].TaskSpec.Steps
+			for j := range steps {
+				digest := ip.imageDigest(ctx, steps[j].Image)
+				if digest == "" {
+					continue
+				}
+				pinned[steps[j].Image] = digest
+				steps[j].Image = steps[j].Image + "@" + digest
+			}
+		}
+	}
+	pipelinerun.Spec.PipelineSpec = spec
+	if len(pinned) == 0 {
+		return
+	}
+	// a map of strings cannot fail to marshal
+	annotation, _ := json.Marshal(pinned)
+	if pipelinerun.Annotations == nil {
+		pipelinerun.Annotations = map[string]string{}
+	}
+	pipelinerun.Annotations[apipac.PinnedImages] = string(annotation)
+}
+
+// imageDigest returns the digest of the image tag or an empty string when the
+// image is not a tag or its digest cannot be resolved.
+func (ip *imagePinner) imageDigest(ctx context.Context, image string) string {
+	if digest, ok := ip.digests[image]; ok {
+		return digest
+	}
+	ip.digests[image] = ""
+	// the images using a parameter or already pinned are not a valid tag
+	tag, err := name.NewTag(image)
+	if err != nil {
+		return ""
+	}
+	digest, err := ip.digest(ctx, tag)
+	if err != nil {
+		ip.logger.Warnf("cannot pin the image %s to its digest: %v", image, err)
+		return ""
+	}
+	ip.digests[image] = digest
+	return digest
+}
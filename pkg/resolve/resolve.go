@@ -5,11 +5,13 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"sync"
 
 	apipac "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/matcher"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
@@ -24,13 +26,58 @@ type Types struct {
 	Tasks        []*tektonv1.Task
 }
 
-var yamlDocSeparatorRe = regexp.MustCompile(`(?m)^---\s*$`)
+var yamlDocSeparatorRe = regexp.MustCompile(`^---\s*$`)
+
+// yamlDocument is a document of a multi documents yaml, with the file of the
+// .tekton directory it comes from and its first and last lines in that file
+// when the yaml records them.
+type yamlDocument struct {
+	content            string
+	source             string
+	startLine, endLine int
+}
+
+// splitDocuments splits a multi documents yaml, following the comments
+// recording the files of the .tekton directory the documents come from.
+func splitDocuments(data string) []yamlDocument {
+	docs := []yamlDocument{}
+	current := yamlDocument{}
+	var lines []string
+	source, sourceStart := "", 0
+	flush := func() {
+		current.content = strings.Join(lines, "\n")
+		docs = append(docs, current)
+		current, lines = yamlDocument{}, nil
+	}
+	for i, line := range strings.Split(data, "\n") {
+		if yamlDocSeparatorRe.MatchString(line) {
+			flush()
+			continue
+		}
+		lines = append(lines, line)
+		if strings.HasPrefix(line, provider.TektonFileMarker) {
+			source, sourceStart = strings.TrimSpace(strings.TrimPrefix(line, provider.TektonFileMarker)), i
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+		if source == "" || trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if current.source == "" {
+			current.source, current.startLine = source, i-sourceStart
+		}
+		current.endLine = i - sourceStart
+	}
+	flush()
+	return docs
+}
 
 func readTypes(ctx context.Context, log *zap.SugaredLogger, data string) (Types, error) {
 	types := Types{}
 	decoder := k8scheme.Codecs.UniversalDeserializer()
 
-	for _, doc := range yamlDocSeparatorRe.Split(data, -1) {
+	for _, yamlDoc := range splitDocuments(data) {
+		doc := yamlDoc.content
 		if strings.TrimSpace(doc) == "" {
 			continue
 		}
@@ -70,6 +117,7 @@ func readTypes(ctx context.Context, log *zap.SugaredLogger, data string) (Types,
 			if err := o.ConvertTo(ctx, c); err != nil {
 				return types, fmt.Errorf("pipelinerun v1beta1 %s cannot be converted as v1: err: %w", o.GetName(), err)
 			}
+			setSourceAnnotations(c, yamlDoc)
 			types.PipelineRuns = append(types.PipelineRuns, c)
 		case *tektonv1beta1.Task:
 			c := &tektonv1.Task{}
@@ -86,6 +134,7 @@ func readTypes(ctx context.Context, log *zap.SugaredLogger, data string) (Types,
 			}
 			types.Tasks = append(types.Tasks, c)
 		case *tektonv1.PipelineRun:
+			setSourceAnnotations(o, yamlDoc)
 			types.PipelineRuns = append(types.PipelineRuns, o)
 		case *tektonv1.Pipeline:
 			types.Pipelines = append(types.Pipelines, o)
@@ -99,6 +148,20 @@ func readTypes(ctx context.Context, log *zap.SugaredLogger, data string) (Types,
 	return types, nil
 }
 
+// setSourceAnnotations records on the PipelineRun the file of the .tekton
+// directory and the lines it is defined at, for the editors and the CLI to
+// go from a PipelineRun to its definition.
+func setSourceAnnotations(pr *tektonv1.PipelineRun, doc yamlDocument) {
+	if doc.source == "" {
+		return
+	}
+	if pr.Annotations == nil {
+		pr.Annotations = map[string]string{}
+	}
+	pr.Annotations[apipac.SourceFile] = doc.source
+	pr.Annotations[apipac.SourceLines] = fmt.Sprintf("%d-%d", doc.startLine, doc.endLine)
+}
+
 func getTaskByName(name string, tasks []*tektonv1.Task) (*tektonv1.Task, error) {
 	for _, value := range tasks {
 		if value.Name == name {
@@ -117,6 +180,21 @@ func getPipelineByName(name string, tasks []*tektonv1.Pipeline) (*tektonv1.Pipel
 	return &tektonv1.Pipeline{}, fmt.Errorf("cannot find referenced pipeline %s. for a remote pipeline make sure to add it in the annotation", name)
 }
 
+// IsClusterPipeline returns true if the pipelineRef of the PipelineRun is a
+// Pipeline installed in its namespace.
+func IsClusterPipeline(pipelinerun *tektonv1.PipelineRun) bool {
+	return pipelinerun.GetAnnotations()[apipac.ClusterPipeline] == "true"
+}
+
+func verifyClusterPipelineRef(pipelinerun *tektonv1.PipelineRun) error {
+	ref := pipelinerun.Spec.PipelineRef
+	if ref == nil || ref.Name == "" || ref.Resolver != "" {
+		return fmt.Errorf("pipelinerun %s has the %s annotation but no pipelineRef to a Pipeline by its name",
+			pipelinerun.GetName(), apipac.ClusterPipeline)
+	}
+	return nil
+}
+
 func skippingTask(taskName string, skippedTasks []string) bool {
 	for _, value := range skippedTasks {
 		if value == taskName {
@@ -138,6 +216,12 @@ func inlineTasks(tasks []tektonv1.PipelineTask, ropt *Opts, types Types) ([]tekt
 			isTektonAPIVersion(task.TaskRef.APIVersion) &&
 			string(task.TaskRef.Kind) != "ClusterTask" &&
 			!skippingTask(task.TaskRef.Name, ropt.SkipInlining) {
+			// a task already on the cluster is referenced instead of inlined
+			if kind, ok := ropt.ClusterTasks[task.TaskRef.Name]; ok {
+				task.TaskRef.Kind = kind
+				pipelineTasks = append(pipelineTasks, task)
+				continue
+			}
 			taskResolved, err := getTaskByName(task.TaskRef.Name, types.Tasks)
 			if err != nil {
 				return nil, err
@@ -150,11 +234,85 @@ func inlineTasks(tasks []tektonv1.PipelineTask, ropt *Opts, types Types) ([]tekt
 	return pipelineTasks, nil
 }
 
+// PipelineRuns returns all the PipelineRuns found in a multi documents yaml
+// string as is, without resolving any of their references.
+func PipelineRuns(ctx context.Context, logger *zap.SugaredLogger, data string) ([]*tektonv1.PipelineRun, error) {
+	types, err := readTypes(ctx, logger, data)
+	if err != nil {
+		return nil, err
+	}
+	return types.PipelineRuns, nil
+}
+
+// maxConcurrentRemoteFetches is the maximum number of PipelineRuns having
+// their remote tasks and pipelines fetched at the same time.
+const maxConcurrentRemoteFetches = 8
+
+// getRemoteAnnotations fetches the remote tasks and pipelines referenced in
+// the annotations of the PipelineRuns. The PipelineRuns are fetched
+// concurrently, the results are merged in the order of the PipelineRuns and the
+// error of the first PipelineRun failing is returned.
+func getRemoteAnnotations(ctx context.Context, cs *params.Run, logger *zap.SugaredLogger, providerintf provider.Interface, event *info.Event, pipelineruns []*tektonv1.PipelineRun) ([]*tektonv1.Task, []*tektonv1.Pipeline, error) {
+	type remoteResult struct {
+		tasks     []*tektonv1.Task
+		pipelines []*tektonv1.Pipeline
+		err       error
+	}
+	rt := matcher.RemoteTasks{
+		Run:               cs,
+		Event:             event,
+		ProviderInterface: providerintf,
+		Logger:            logger,
+	}
+	results := make([]remoteResult, len(pipelineruns))
+	sem := make(chan struct{}, maxConcurrentRemoteFetches)
+	var wg sync.WaitGroup
+	for i, pipelinerun := range pipelineruns {
+		annotations := pipelinerun.GetObjectMeta().GetAnnotations()
+		if annotations == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, annotations map[string]string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			result := &results[i]
+			if result.tasks, result.err = rt.GetTaskFromAnnotations(ctx, annotations); result.err != nil {
+				return
+			}
+			result.pipelines, result.err = rt.GetPipelineFromAnnotations(ctx, annotations)
+		}(i, annotations)
+	}
+	wg.Wait()
+
+	tasks := []*tektonv1.Task{}
+	pipelines := []*tektonv1.Pipeline{}
+	for _, result := range results {
+		if result.err != nil {
+			return nil, nil, result.err
+		}
+		tasks = append(tasks, result.tasks...)
+		pipelines = append(pipelines, result.pipelines...)
+	}
+	return tasks, pipelines, nil
+}
+
 type Opts struct {
 	GenerateName  bool     // whether to GenerateName
 	RemoteTasks   bool     // whether to parse annotation to fetch tasks from remote
 	SkipInlining  []string // task to skip inlining
 	ProviderToken string
+	// Injection are the steps and sidecars injected in the embedded tasks
+	Injection *settings.PipelineRunInjection
+	// PinImages pins the step images of the embedded tasks to their digests
+	PinImages bool
+	// ClusterTasks are the tasks present on the cluster by name with their
+	// kind, the references to them are kept instead of being inlined
+	ClusterTasks map[string]tektonv1.TaskKind
+	// GitClone is the task cloning the repository prepended to the
+	// PipelineRuns, nothing is prepended when it is nil
+	GitClone *GitCloneInjection
 }
 
 // Resolve gets a large string which is a yaml multi documents containing
@@ -171,27 +329,14 @@ func Resolve(ctx context.Context, cs *params.Run, logger *zap.SugaredLogger, pro
 	}
 
 	// First resolve Annotations Tasks
-	for _, pipelinerun := range types.PipelineRuns {
-		if ropt.RemoteTasks && pipelinerun.GetObjectMeta().GetAnnotations() != nil {
-			rt := matcher.RemoteTasks{
-				Run:               cs,
-				Event:             event,
-				ProviderInterface: providerintf,
-				Logger:            logger,
-			}
-			remoteTasks, err := rt.GetTaskFromAnnotations(ctx, pipelinerun.GetObjectMeta().GetAnnotations())
-			if err != nil {
-				return []*tektonv1.PipelineRun{}, err
-			}
-			// Merge remote tasks with local tasks
-			types.Tasks = append(types.Tasks, remoteTasks...)
-
-			remotePipelines, err := rt.GetPipelineFromAnnotations(ctx, pipelinerun.GetObjectMeta().GetAnnotations())
-			if err != nil {
-				return []*tektonv1.PipelineRun{}, err
-			}
-			types.Pipelines = append(types.Pipelines, remotePipelines...)
+	if ropt.RemoteTasks {
+		remoteTasks, remotePipelines, err := getRemoteAnnotations(ctx, cs, logger, providerintf, event, types.PipelineRuns)
+		if err != nil {
+			return []*tektonv1.PipelineRun{}, err
 		}
+		// Merge remote tasks with local tasks
+		types.Tasks = append(types.Tasks, remoteTasks...)
+		types.Pipelines = append(types.Pipelines, remotePipelines...)
 	}
 
 	// Resolve {Finally/Task}Ref inside Pipeline
@@ -209,6 +354,10 @@ func Resolve(ctx context.Context, cs *params.Run, logger *zap.SugaredLogger, pro
 		pipeline.Spec.Finally = finallyTasks
 	}
 
+	var pinner *imagePinner
+	if ropt.PinImages {
+		pinner = newImagePinner(logger, &cs.Clients.HTTP)
+	}
 	for _, pipelinerun := range types.PipelineRuns {
 		// Resolve {Finally/Task}Ref inside PipelineSpec inside PipelineRun
 		if pipelinerun.Spec.PipelineSpec != nil {
@@ -225,8 +374,13 @@ func Resolve(ctx context.Context, cs *params.Run, logger *zap.SugaredLogger, pro
 			pipelinerun.Spec.PipelineSpec.Finally = fruns
 		}
 
-		// Resolve PipelineRef inside PipelineRef
-		if pipelinerun.Spec.PipelineRef != nil && pipelinerun.Spec.PipelineRef.Resolver == "" {
+		// Resolve PipelineRef inside PipelineRef, the reference to a Pipeline
+		// installed on the cluster is kept for Tekton to resolve it
+		if IsClusterPipeline(pipelinerun) {
+			if err := verifyClusterPipelineRef(pipelinerun); err != nil {
+				return []*tektonv1.PipelineRun{}, err
+			}
+		} else if pipelinerun.Spec.PipelineRef != nil && pipelinerun.Spec.PipelineRef.Resolver == "" {
 			pipelineResolved, err := getPipelineByName(pipelinerun.Spec.PipelineRef.Name, types.Pipelines)
 			if err != nil {
 				return []*tektonv1.PipelineRun{}, err
@@ -235,6 +389,16 @@ func Resolve(ctx context.Context, cs *params.Run, logger *zap.SugaredLogger, pro
 			pipelinerun.Spec.PipelineSpec = &pipelineResolved.Spec
 		}
 
+		if err := injectPipelineRun(pipelinerun, ropt.Injection); err != nil {
+			return []*tektonv1.PipelineRun{}, err
+		}
+		if err := injectGitClone(pipelinerun, event, ropt.GitClone); err != nil {
+			return []*tektonv1.PipelineRun{}, err
+		}
+		if pinner != nil {
+			pinner.pinPipelineRun(ctx, pipelinerun)
+		}
+
 		var originPipelinerunName string
 
 		originPipelinerunName = pipelinerun.Name
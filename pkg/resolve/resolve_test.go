@@ -1,19 +1,27 @@
 package resolve
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"os"
 	"strings"
 	"testing"
 
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	httptesthelper "github.com/openshift-pipelines/pipelines-as-code/pkg/test/http"
 	testprovider "github.com/openshift-pipelines/pipelines-as-code/pkg/test/provider"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	"go.uber.org/zap"
 	zapobserver "go.uber.org/zap/zaptest/observer"
 	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8scheme "k8s.io/client-go/kubernetes/scheme"
 	rtesting "knative.dev/pkg/reconciler/testing"
 )
@@ -118,6 +126,26 @@ func TestClusterTasksSkipped(t *testing.T) {
 	assert.Equal(t, string(resolved.Spec.PipelineSpec.Tasks[0].TaskRef.Kind), "ClusterTask")
 }
 
+func TestClusterTasksReferenced(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	data, err := os.ReadFile("testdata/pipelinerun-pipeline-task.yaml")
+	assert.NilError(t, err)
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	ropt := &Opts{ClusterTasks: map[string]tektonv1.TaskKind{
+		"task-test2": tektonv1.NamespacedTaskKind,
+		"task-test3": tektonv1.TaskKind("ClusterTask"),
+	}}
+	resolved, err := Resolve(ctx, &params.Run{}, zap.New(observer).Sugar(), &testprovider.TestProviderImp{}, &info.Event{}, string(data), ropt)
+	assert.NilError(t, err)
+	tasks := resolved[0].Spec.PipelineSpec.Tasks
+	assert.Equal(t, tasks[0].TaskSpec.Steps[0].Name, "first-step")
+	assert.Assert(t, tasks[1].TaskSpec == nil)
+	assert.Equal(t, tasks[1].TaskRef.Name, "task-test2")
+	assert.Equal(t, tasks[1].TaskRef.Kind, tektonv1.NamespacedTaskKind)
+	assert.Assert(t, tasks[2].TaskSpec == nil)
+	assert.Equal(t, tasks[2].TaskRef.Kind, tektonv1.TaskKind("ClusterTask"))
+}
+
 func TestCustomTasksSkipped(t *testing.T) {
 	resolved, _, err := readTDfile(t, "pipelinerun-with-a-customtask", false, true)
 	assert.NilError(t, err)
@@ -248,3 +276,379 @@ func TestPipelinev1Beta1InvalidConversion(t *testing.T) {
 	_, _, err := readTDfile(t, "pipeline-invalid-conversion", false, true)
 	assert.ErrorContains(t, err, "cannot be validated")
 }
+
+func TestSourceAnnotations(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+
+	pipelineRun := func(name string) string {
+		return "apiVersion: tekton.dev/v1\nkind: PipelineRun\nmetadata:\n  name: " + name + "\nspec:\n  pipelineSpec:\n    tasks: []\n"
+	}
+	data := provider.AppendTektonFile("", ".tekton/pr.yaml", "# the pull request pipeline\n"+pipelineRun("pr"))
+	data = provider.AppendTektonFile(data, ".tekton/push.yaml", "---\n"+pipelineRun("push")+"---\n\n"+pipelineRun("push-nightly"))
+
+	prs, err := PipelineRuns(ctx, logger, data)
+	assert.NilError(t, err)
+	assert.Equal(t, len(prs), 3)
+	for i, want := range []struct{ file, lines string }{
+		{file: ".tekton/pr.yaml", lines: "2-8"},
+		{file: ".tekton/push.yaml", lines: "2-8"},
+		{file: ".tekton/push.yaml", lines: "11-17"},
+	} {
+		assert.Equal(t, prs[i].GetAnnotations()[keys.SourceFile], want.file)
+		assert.Equal(t, prs[i].GetAnnotations()[keys.SourceLines], want.lines)
+	}
+
+	// the yaml not recording the files doesn't get the annotations
+	prs, err = PipelineRuns(ctx, logger, pipelineRun("pr"))
+	assert.NilError(t, err)
+	_, ok := prs[0].GetAnnotations()[keys.SourceFile]
+	assert.Assert(t, !ok)
+}
+
+func TestRemoteTasksOfSeveralPipelineRuns(t *testing.T) {
+	task := func(name string) string {
+		return "apiVersion: tekton.dev/v1\nkind: Task\nmetadata:\n  name: " + name + "\nspec:\n  steps:\n    - name: " + name + "-step\n      image: image\n"
+	}
+	pipelineRun := func(name, task string) string {
+		return "---\napiVersion: tekton.dev/v1\nkind: PipelineRun\nmetadata:\n  name: " + name +
+			"\n  annotations:\n    pipelinesascode.tekton.dev/task: \"https://remote/" + task + ".yaml\"\nspec:\n  pipelineSpec:\n    tasks:\n      - name: " + task +
+			"\n        taskRef:\n          name: " + task + "\n"
+	}
+	names := []string{"build", "lint", "test", "deploy", "e2e", "docs", "release", "scan", "bench", "fmt"}
+
+	tests := []struct {
+		name    string
+		missing string
+		wantErr string
+	}{
+		{
+			name: "every pipelinerun gets its remote task",
+		},
+		{
+			name:    "a remote task failing fails the resolution",
+			missing: "scan",
+			wantErr: "error getting remote task \"https://remote/scan.yaml\"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			observer, _ := zapobserver.New(zap.InfoLevel)
+			logger := zap.New(observer).Sugar()
+
+			data := ""
+			remotes := map[string]map[string]string{}
+			for _, name := range names {
+				data += pipelineRun(name+"-pr", name)
+				remotes["https://remote/"+name+".yaml"] = map[string]string{"body": task(name), "code": "200"}
+			}
+			if tt.missing != "" {
+				remotes["https://remote/"+tt.missing+".yaml"] = map[string]string{"code": "404"}
+			}
+			cs := &params.Run{
+				Clients: clients.Clients{HTTP: *httptesthelper.MakeHTTPTestClient(t, remotes)},
+				Info:    info.Info{},
+			}
+			resolved, err := Resolve(ctx, cs, logger, &testprovider.TestProviderImp{}, &info.Event{}, data, &Opts{RemoteTasks: true})
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, len(resolved), len(names))
+			for i, name := range names {
+				assert.Equal(t, resolved[i].GetName(), name+"-pr")
+				assert.Equal(t, resolved[i].Spec.PipelineSpec.Tasks[0].TaskSpec.Steps[0].Name, name+"-step")
+			}
+		})
+	}
+}
+
+func TestInjection(t *testing.T) {
+	pipeline := `---
+apiVersion: tekton.dev/v1
+kind: Pipeline
+metadata:
+  name: pipeline
+spec:
+  tasks:
+    - name: build
+      taskSpec:
+        steps:
+          - name: build
+            image: golang
+  finally:
+    - name: notify
+      taskRef:
+        name: notify
+---
+apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: notify
+spec:
+  steps:
+    - name: notify
+      image: curl
+`
+	pipelineRun := func(name string) string {
+		return "---\napiVersion: tekton.dev/v1\nkind: PipelineRun\nmetadata:\n  name: " + name + "\nspec:\n  pipelineRef:\n    name: pipeline\n"
+	}
+	tests := []struct {
+		name      string
+		injection string
+		wantErr   string
+	}{
+		{
+			name:      "steps and sidecars injected in every task",
+			injection: "stepsBefore:\n  - name: warm-cache\n    image: cache\nstepsAfter:\n  - name: scan\n    image: scanner\nsidecars:\n  - name: proxy\n    image: proxy\n",
+		},
+		{
+			name:      "injected step conflicting with a task step",
+			injection: "stepsAfter:\n  - name: notify\n    image: scanner\n",
+			wantErr:   "cannot inject in the task notify of the pipelinerun pr: the task already has a step named notify",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			observer, _ := zapobserver.New(zap.InfoLevel)
+			logger := zap.New(observer).Sugar()
+			injection, err := settings.ParsePipelineRunInjection(tt.injection)
+			assert.NilError(t, err)
+
+			cs := &params.Run{Clients: clients.Clients{}, Info: info.Info{}}
+			resolved, err := Resolve(ctx, cs, logger, &testprovider.TestProviderImp{}, &info.Event{},
+				pipeline+pipelineRun("pr")+pipelineRun("push"), &Opts{Injection: injection})
+			if tt.wantErr != "" {
+				assert.Error(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			// the PipelineRuns sharing the Pipeline are both injected once
+			assert.Equal(t, len(resolved), 2)
+			for _, pr := range resolved {
+				for _, task := range append(pr.Spec.PipelineSpec.Tasks, pr.Spec.PipelineSpec.Finally...) {
+					steps := []string{}
+					for _, step := range task.TaskSpec.Steps {
+						steps = append(steps, step.Name)
+					}
+					assert.DeepEqual(t, steps, []string{"warm-cache", task.Name, "scan"})
+					assert.Equal(t, len(task.TaskSpec.Sidecars), 1)
+					assert.Equal(t, task.TaskSpec.Sidecars[0].Name, "proxy")
+				}
+			}
+		})
+	}
+}
+
+func TestGitClone(t *testing.T) {
+	pipeline := `---
+apiVersion: tekton.dev/v1
+kind: Pipeline
+metadata:
+  name: pipeline
+spec:
+  workspaces:
+    - name: source
+  tasks:
+    - name: build
+      taskSpec:
+        steps:
+          - name: build
+            image: golang
+    - name: push
+      runAfter: [build]
+      taskSpec:
+        steps:
+          - name: push
+            image: skopeo
+`
+	pipelineRun := func(name, workspaces string) string {
+		return "---\napiVersion: tekton.dev/v1\nkind: PipelineRun\nmetadata:\n  name: " + name +
+			"\nspec:\n  pipelineRef:\n    name: pipeline\n" + workspaces
+	}
+	tests := []struct {
+		name          string
+		gitClone      *GitCloneInjection
+		workspaces    string
+		wantWorkspace string
+		wantImage     string
+		wantAuth      bool
+		wantClaim     bool
+	}{
+		{
+			name:          "defaults",
+			gitClone:      &GitCloneInjection{},
+			wantWorkspace: "source",
+			wantImage:     GitCloneDefaultImage,
+			wantClaim:     true,
+		},
+		{
+			name:          "workspace bound by the pipelinerun",
+			gitClone:      &GitCloneInjection{Image: "alpine/git"},
+			workspaces:    "  workspaces:\n    - name: source\n      emptyDir: {}\n",
+			wantWorkspace: "source",
+			wantImage:     "alpine/git",
+		},
+		{
+			name:          "custom workspace with the git-auth secret",
+			gitClone:      &GitCloneInjection{Workspace: "checkout", AuthSecret: "{{ git_auth_secret }}"},
+			wantWorkspace: "checkout",
+			wantImage:     GitCloneDefaultImage,
+			wantAuth:      true,
+			wantClaim:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			observer, _ := zapobserver.New(zap.InfoLevel)
+			logger := zap.New(observer).Sugar()
+			cs := &params.Run{Clients: clients.Clients{}, Info: info.Info{}}
+			event := &info.Event{URL: "https://forge.example.com/owner/repo", SHA: "abcdef"}
+			resolved, err := Resolve(ctx, cs, logger, &testprovider.TestProviderImp{}, event,
+				pipeline+pipelineRun("pr", tt.workspaces)+pipelineRun("push", tt.workspaces), &Opts{GitClone: tt.gitClone})
+			assert.NilError(t, err)
+			// the PipelineRuns sharing the Pipeline both get the task once
+			assert.Equal(t, len(resolved), 2)
+			for _, pr := range resolved {
+				tasks := pr.Spec.PipelineSpec.Tasks
+				assert.Equal(t, len(tasks), 3)
+				clone := tasks[0]
+				assert.Equal(t, clone.Name, GitCloneTaskName)
+				assert.Equal(t, clone.TaskSpec.Steps[0].Image, tt.wantImage)
+				assert.Equal(t, clone.Params[0].Value.StringVal, "https://forge.example.com/owner/repo")
+				assert.Equal(t, clone.Params[1].Value.StringVal, "abcdef")
+				assert.Equal(t, clone.Workspaces[0].Workspace, tt.wantWorkspace)
+				assert.DeepEqual(t, tasks[1].RunAfter, []string{GitCloneTaskName})
+				assert.DeepEqual(t, tasks[2].RunAfter, []string{"build"})
+
+				declared := map[string]bool{}
+				for _, ws := range pr.Spec.PipelineSpec.Workspaces {
+					declared[ws.Name] = true
+				}
+				assert.Assert(t, declared[tt.wantWorkspace])
+				bindings := map[string]tektonv1.WorkspaceBinding{}
+				for _, ws := range pr.Spec.Workspaces {
+					bindings[ws.Name] = ws
+				}
+				assert.Equal(t, bindings[tt.wantWorkspace].VolumeClaimTemplate != nil, tt.wantClaim)
+				assert.Equal(t, len(clone.Workspaces) == 2, tt.wantAuth)
+				if tt.wantAuth {
+					assert.Equal(t, bindings["pac-git-auth"].Secret.SecretName, "{{ git_auth_secret }}")
+				}
+			}
+		})
+	}
+}
+
+func TestPinImages(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	observer, log := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+
+	pipelinerun := func() *tektonv1.PipelineRun {
+		return &tektonv1.PipelineRun{
+			ObjectMeta: metav1.ObjectMeta{Name: "pr"},
+			Spec: tektonv1.PipelineRunSpec{PipelineSpec: &tektonv1.PipelineSpec{
+				Tasks: []tektonv1.PipelineTask{{
+					Name: "build",
+					TaskSpec: &tektonv1.EmbeddedTask{TaskSpec: tektonv1.TaskSpec{Steps: []tektonv1.Step{
+						{Name: "build", Image: "golang:1.20"},
+						{Name: "pinned", Image: "alpine@sha256:4f2a1d3e5b6c7d8e9f0a1b2c3d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0c1d2e"},
+						{Name: "param", Image: "$(params.image)"},
+						{Name: "private", Image: "registry.example.com/private/tool"},
+					}}},
+				}},
+				Finally: []tektonv1.PipelineTask{{
+					Name:    "referenced",
+					TaskRef: &tektonv1.TaskRef{Name: "notify"},
+				}},
+			}},
+		}
+	}
+
+	asked := map[string]int{}
+	pinner := &imagePinner{
+		logger: logger,
+		digest: func(ctx context.Context, tag name.Tag) (string, error) {
+			asked[tag.String()]++
+			if tag.RegistryStr() == "registry.example.com" {
+				return "", fmt.Errorf("cannot get the manifest: 401 Unauthorized")
+			}
+			return "sha256:0123", nil
+		},
+		digests: map[string]string{},
+	}
+
+	for i := 0; i < 2; i++ {
+		pr := pipelinerun()
+		pinner.pinPipelineRun(ctx, pr)
+		images := []string{}
+		for _, step := range pr.Spec.PipelineSpec.Tasks[0].TaskSpec.Steps {
+			images = append(images, step.Image)
+		}
+		assert.DeepEqual(t, images, []string{
+			"golang:1.20@sha256:0123",
+			"alpine@sha256:4f2a1d3e5b6c7d8e9f0a1b2c3d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0c1d2e",
+			"$(params.image)",
+			"registry.example.com/private/tool",
+		})
+		assert.Equal(t, pr.GetAnnotations()[keys.PinnedImages], `{"golang:1.20":"sha256:0123"}`)
+	}
+	// the digests are only asked once for all the PipelineRuns
+	assert.DeepEqual(t, asked, map[string]int{
+		"golang:1.20":                       1,
+		"registry.example.com/private/tool": 1,
+	})
+	assert.Equal(t, log.FilterMessageSnippet("cannot pin the image registry.example.com/private/tool to its digest").Len(), 1)
+}
+
+func TestClusterPipeline(t *testing.T) {
+	pipelineRun := func(pipelineRef string) string {
+		return fmt.Sprintf(`---
+apiVersion: tekton.dev/v1
+kind: PipelineRun
+metadata:
+  name: pr
+  annotations:
+    pipelinesascode.tekton.dev/cluster-pipeline: "true"
+spec:
+  pipelineRef:
+%s
+`, pipelineRef)
+	}
+	tests := []struct {
+		name        string
+		pipelineRef string
+		wantErr     string
+	}{
+		{
+			name:        "pipelineRef by name",
+			pipelineRef: "    name: build",
+		},
+		{
+			name:        "pipelineRef with a resolver",
+			pipelineRef: "    resolver: hub",
+			wantErr:     "pipelinerun pr has the pipelinesascode.tekton.dev/cluster-pipeline annotation but no pipelineRef to a Pipeline by its name",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			observer, _ := zapobserver.New(zap.InfoLevel)
+			resolved, err := Resolve(ctx, &params.Run{}, zap.New(observer).Sugar(), &testprovider.TestProviderImp{},
+				&info.Event{}, pipelineRun(tt.pipelineRef), &Opts{GitClone: &GitCloneInjection{}})
+			if tt.wantErr != "" {
+				assert.Error(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, resolved[0].Spec.PipelineRef.Name, "build")
+			assert.Assert(t, resolved[0].Spec.PipelineSpec == nil)
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/random"
@@ -20,11 +21,15 @@ const (
 	helper=store
 	`
 	//nolint:gosec
-	basicAuthSecretName = `pac-gitauth-%s`
+	basicAuthSecretName = `%s-%s`
+	// tektonGitAnnotation is the annotation telling Tekton which git host a
+	// basic-auth secret is for.
+	tektonGitAnnotation = "tekton.dev/git-0"
 )
 
-// MakeBasicAuthSecret Make a secret for git-clone basic-auth workspace
-func MakeBasicAuthSecret(runevent *info.Event, secretName string) (*corev1.Secret, error) {
+// gitAuth returns the clone URL of the event, the user and the escaped token
+// to authenticate to it.
+func gitAuth(runevent *info.Event) (string, *url.URL, string, string, error) {
 	// Bitbucket Server have a different Clone URL than it's Repository URL, so we
 	// have to separate them 👨‍🏭
 	cloneURL := runevent.URL
@@ -34,7 +39,7 @@ func MakeBasicAuthSecret(runevent *info.Event, secretName string) (*corev1.Secre
 
 	repoURL, err := url.Parse(cloneURL)
 	if err != nil {
-		return nil, fmt.Errorf("cannot parse url %s: %w", cloneURL, err)
+		return "", nil, "", "", fmt.Errorf("cannot parse url %s: %w", cloneURL, err)
 	}
 
 	gitUser := provider.DefaultProviderAPIUser
@@ -52,16 +57,12 @@ func MakeBasicAuthSecret(runevent *info.Event, secretName string) (*corev1.Secre
 	// maybe we could patch the git-clone task too but that probably be a pain
 	// in the *** to do it in shell.
 	token := url.QueryEscape(runevent.Provider.Token)
+	return cloneURL, repoURL, gitUser, token, nil
+}
 
-	urlWithToken := fmt.Sprintf("%s://%s:%s@%s%s", repoURL.Scheme, gitUser, token, repoURL.Host, repoURL.Path)
-	secretData := map[string]string{
-		".gitconfig":       fmt.Sprintf(basicAuthGitConfigData, cloneURL),
-		".git-credentials": urlWithToken,
-		// With the GitHub APP method the token is available for 8h if you have
-		// the user to server token expiration.  the token is scoped to the
-		// installation ID
-		"git-provider-token": token,
-	}
+// gitAuthSecret makes the secret with the labels and annotations of the
+// git-auth secrets.
+func gitAuthSecret(runevent *info.Event, secretName, cloneURL string, secretType corev1.SecretType, data map[string]string) *corev1.Secret {
 	annotations := map[string]string{
 		"pipelinesascode.tekton.dev/url": cloneURL,
 		"pipelinesascode.tekton.dev/sha": runevent.SHA,
@@ -69,8 +70,8 @@ func MakeBasicAuthSecret(runevent *info.Event, secretName string) (*corev1.Secre
 
 	labels := map[string]string{
 		"app.kubernetes.io/managed-by": pipelinesascode.GroupName,
-		keys.URLOrg:                    runevent.Organization,
-		keys.URLRepository:             runevent.Repository,
+		keys.URLOrg:                    formatting.K8LabelsCleanup(runevent.Organization),
+		keys.URLRepository:             formatting.K8LabelsCleanup(runevent.Repository),
 	}
 
 	return &corev1.Secret{
@@ -83,11 +84,50 @@ func MakeBasicAuthSecret(runevent *info.Event, secretName string) (*corev1.Secre
 			Labels:      labels,
 			Annotations: annotations,
 		},
-		StringData: secretData,
-	}, nil
+		Type:       secretType,
+		StringData: data,
+	}
+}
+
+// MakeBasicAuthSecret Make a secret for git-clone basic-auth workspace
+func MakeBasicAuthSecret(runevent *info.Event, secretName string) (*corev1.Secret, error) {
+	cloneURL, repoURL, gitUser, token, err := gitAuth(runevent)
+	if err != nil {
+		return nil, err
+	}
+
+	urlWithToken := fmt.Sprintf("%s://%s:%s@%s%s", repoURL.Scheme, gitUser, token, repoURL.Host, repoURL.Path)
+	secretData := map[string]string{
+		".gitconfig":       fmt.Sprintf(basicAuthGitConfigData, cloneURL),
+		".git-credentials": urlWithToken,
+		// With the GitHub APP method the token is available for 8h if you have
+		// the user to server token expiration.  the token is scoped to the
+		// installation ID
+		"git-provider-token": token,
+	}
+	return gitAuthSecret(runevent, secretName, cloneURL, "", secretData), nil
+}
+
+// MakeGitConfigSecret makes a Tekton basic-auth secret for the host of the
+// repository, once attached to the ServiceAccount of a PipelineRun Tekton
+// writes the .gitconfig and .git-credentials in the home of every step.
+func MakeGitConfigSecret(runevent *info.Event, secretName string) (*corev1.Secret, error) {
+	cloneURL, repoURL, gitUser, token, err := gitAuth(runevent)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := gitAuthSecret(runevent, secretName, cloneURL, corev1.SecretTypeBasicAuth, map[string]string{
+		corev1.BasicAuthUsernameKey: gitUser,
+		corev1.BasicAuthPasswordKey: runevent.Provider.Token,
+		"git-provider-token":        token,
+	})
+	secret.Annotations[tektonGitAnnotation] = fmt.Sprintf("%s://%s", repoURL.Scheme, repoURL.Host)
+	return secret, nil
 }
 
-func GenerateBasicAuthSecretName() string {
+// GenerateBasicAuthSecretName generates a random secret name with the prefix.
+func GenerateBasicAuthSecretName(prefix string) string {
 	return strings.ToLower(
-		fmt.Sprintf(basicAuthSecretName, random.AlphaString(4)))
+		fmt.Sprintf(basicAuthSecretName, prefix, random.AlphaString(4)))
 }
@@ -0,0 +1,76 @@
+package secrets
+
+import (
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// GitAuthPolicy is how the git-auth secret of the PipelineRuns of a
+// Repository is created.
+type GitAuthPolicy struct {
+	AutoCreate     bool
+	NamePrefix     string
+	ServiceAccount string
+	Mode           string
+}
+
+// GitAuthPolicyFor returns the git-auth secret policy of the Repository, the
+// fields the Repository doesn't override come from the settings.
+func GitAuthPolicyFor(pacOpts *info.PacOpts, repo *v1alpha1.Repository) GitAuthPolicy {
+	policy := GitAuthPolicy{
+		AutoCreate:     pacOpts.SecretAutoCreation,
+		NamePrefix:     pacOpts.SecretAutoCreateNamePrefix,
+		ServiceAccount: pacOpts.SecretAutoCreateServiceAccount,
+		Mode:           pacOpts.SecretAutoCreateMode,
+	}
+	if repo != nil && repo.Spec.GitAuthSecret != nil {
+		override := repo.Spec.GitAuthSecret
+		if override.AutoCreate != nil {
+			policy.AutoCreate = *override.AutoCreate
+		}
+		if override.NamePrefix != "" {
+			policy.NamePrefix = override.NamePrefix
+		}
+		if override.ServiceAccount != "" {
+			policy.ServiceAccount = override.ServiceAccount
+		}
+		if override.Mode != "" {
+			policy.Mode = override.Mode
+		}
+	}
+	if policy.NamePrefix == "" {
+		policy.NamePrefix = settings.SecretAutoCreateNamePrefixDefaultValue
+	}
+	if policy.Mode == "" {
+		policy.Mode = settings.SecretAutoCreateModeWorkspace
+	}
+	return policy
+}
+
+// MakeSecret makes the git-auth secret as exposed by the policy.
+func (p GitAuthPolicy) MakeSecret(runevent *info.Event, secretName string) (*corev1.Secret, error) {
+	if p.Mode == settings.SecretAutoCreateModeGitConfig {
+		return MakeGitConfigSecret(runevent, secretName)
+	}
+	return MakeBasicAuthSecret(runevent, secretName)
+}
+
+// ServiceAccountFor returns the ServiceAccount the git-auth secret is attached
+// to for a PipelineRun running with the given ServiceAccount, empty when the
+// secret isn't attached to any. The secret is attached when the policy has a
+// ServiceAccount or in the gitconfig mode, which needs it on the
+// ServiceAccount the PipelineRun runs with.
+func (p GitAuthPolicy) ServiceAccountFor(prServiceAccount string) string {
+	if p.ServiceAccount == "" && p.Mode != settings.SecretAutoCreateModeGitConfig {
+		return ""
+	}
+	if prServiceAccount != "" {
+		return prServiceAccount
+	}
+	if p.ServiceAccount != "" {
+		return p.ServiceAccount
+	}
+	return "default"
+}
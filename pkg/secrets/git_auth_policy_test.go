@@ -0,0 +1,69 @@
+package secrets
+
+import (
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"gotest.tools/v3/assert"
+)
+
+func TestGitAuthPolicyFor(t *testing.T) {
+	disabled := false
+	tests := []struct {
+		name     string
+		settings settings.Settings
+		repo     *v1alpha1.Repository
+		want     GitAuthPolicy
+	}{
+		{
+			name:     "defaults",
+			settings: settings.Settings{SecretAutoCreation: true},
+			want:     GitAuthPolicy{AutoCreate: true, NamePrefix: "pac-gitauth", Mode: settings.SecretAutoCreateModeWorkspace},
+		},
+		{
+			name: "from the settings",
+			settings: settings.Settings{
+				SecretAutoCreation:             true,
+				SecretAutoCreateNamePrefix:     "ci-gitauth",
+				SecretAutoCreateServiceAccount: "pipeline",
+				SecretAutoCreateMode:           settings.SecretAutoCreateModeGitConfig,
+			},
+			repo: &v1alpha1.Repository{},
+			want: GitAuthPolicy{AutoCreate: true, NamePrefix: "ci-gitauth", ServiceAccount: "pipeline", Mode: settings.SecretAutoCreateModeGitConfig},
+		},
+		{
+			name: "overridden by the repository",
+			settings: settings.Settings{
+				SecretAutoCreation:             true,
+				SecretAutoCreateServiceAccount: "pipeline",
+			},
+			repo: &v1alpha1.Repository{Spec: v1alpha1.RepositorySpec{GitAuthSecret: &v1alpha1.GitAuthSecret{
+				AutoCreate:     &disabled,
+				NamePrefix:     "repo-gitauth",
+				ServiceAccount: "builder",
+			}}},
+			want: GitAuthPolicy{NamePrefix: "repo-gitauth", ServiceAccount: "builder", Mode: settings.SecretAutoCreateModeWorkspace},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GitAuthPolicyFor(&info.PacOpts{Settings: &tt.settings}, tt.repo)
+			assert.DeepEqual(t, got, tt.want)
+		})
+	}
+}
+
+func TestGitAuthPolicyServiceAccountFor(t *testing.T) {
+	workspace := GitAuthPolicy{Mode: settings.SecretAutoCreateModeWorkspace}
+	assert.Equal(t, workspace.ServiceAccountFor("custom"), "")
+
+	gitconfig := GitAuthPolicy{Mode: settings.SecretAutoCreateModeGitConfig}
+	assert.Equal(t, gitconfig.ServiceAccountFor(""), "default")
+	assert.Equal(t, gitconfig.ServiceAccountFor("custom"), "custom")
+
+	withSA := GitAuthPolicy{Mode: settings.SecretAutoCreateModeWorkspace, ServiceAccount: "builder"}
+	assert.Equal(t, withSA.ServiceAccountFor(""), "builder")
+	assert.Equal(t, withSA.ServiceAccountFor("custom"), "custom")
+}
@@ -9,8 +9,9 @@ type Semaphore interface {
 	acquireLatest() string
 	tryAcquire(string) (bool, string)
 	release(string) bool
+	replace(string, string) bool
 	resize(int) bool
-	addToQueue(string, time.Time) bool
+	addToQueue(string, time.Time, bool) bool
 	removeFromQueue(string)
 	getName() string
 	getLimit() int
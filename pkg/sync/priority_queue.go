@@ -11,7 +11,9 @@ type (
 type item struct {
 	key      string
 	priority int64
-	index    int
+	// high priority items are before the others whatever their priority
+	high  bool
+	index int
 }
 
 type priorityQueue struct {
@@ -27,10 +29,18 @@ func (pq *priorityQueue) isPending(key key) bool {
 }
 
 func (pq *priorityQueue) add(key key, priority int64) {
-	if _, ok := pq.itemByKey[key]; ok {
+	pq.addItem(&item{key: key, priority: priority})
+}
+
+func (pq *priorityQueue) addHigh(key key, priority int64) {
+	pq.addItem(&item{key: key, priority: priority, high: true})
+}
+
+func (pq *priorityQueue) addItem(item *item) {
+	if _, ok := pq.itemByKey[item.key]; ok {
 		return
 	}
-	heap.Push(pq, &item{key: key, priority: priority})
+	heap.Push(pq, item)
 }
 
 func (pq *priorityQueue) remove(key key) {
@@ -52,6 +62,9 @@ func (pq *priorityQueue) peek() *item {
 func (pq priorityQueue) Len() int { return len(pq.items) }
 
 func (pq priorityQueue) Less(i, j int) bool {
+	if pq.items[i].high != pq.items[j].high {
+		return pq.items[i].high
+	}
 	return pq.items[i].priority < pq.items[j].priority
 }
 
@@ -51,3 +51,20 @@ func TestPriorityQueue(t *testing.T) {
 	// check the top most
 	assert.Equal(t, pq.peek().key, "item-c")
 }
+
+func TestPriorityQueueHighPriority(t *testing.T) {
+	pq := &priorityQueue{itemByKey: make(map[string]*item)}
+
+	// the high priority items are before the others whatever their
+	// creation time and in the order of their creation time between them
+	pq.add("pull-request-a", 1)
+	pq.addHigh("push-b", 5)
+	pq.add("pull-request-c", 2)
+	pq.addHigh("push-a", 3)
+
+	got := []string{}
+	for pq.Len() > 0 {
+		got = append(got, pq.pop().key)
+	}
+	assert.DeepEqual(t, got, []string{"push-a", "push-b", "pull-request-a", "pull-request-c"})
+}
@@ -77,6 +77,21 @@ func (qm *QueueManager) checkAndUpdateSemaphoreSize(repo *v1alpha1.Repository, s
 // then move it to running queue
 // This adds the pipelineRuns in the same order as in the list
 func (qm *QueueManager) AddListToQueue(repo *v1alpha1.Repository, list []string) ([]string, error) {
+	return qm.addListToQueue(repo, list, false)
+}
+
+// AddPipelineRunListToQueue is like AddListToQueue but with the priority of
+// the pipelineRun, the high priority pipelineRuns are started before the
+// other waiting ones of the repository
+func (qm *QueueManager) AddPipelineRunListToQueue(repo *v1alpha1.Repository, pr *tektonv1.PipelineRun, list []string) ([]string, error) {
+	return qm.addListToQueue(repo, list, hasHighPriority(pr))
+}
+
+func hasHighPriority(pr *tektonv1.PipelineRun) bool {
+	return pr.GetAnnotations()[keys.QueuePriority] == kubeinteraction.QueuePriorityHigh
+}
+
+func (qm *QueueManager) addListToQueue(repo *v1alpha1.Repository, list []string, high bool) ([]string, error) {
 	qm.lock.Lock()
 	defer qm.lock.Unlock()
 
@@ -86,7 +101,7 @@ func (qm *QueueManager) AddListToQueue(repo *v1alpha1.Repository, list []string)
 	}
 
 	for _, pr := range list {
-		if sema.addToQueue(pr, time.Now()) {
+		if sema.addToQueue(pr, time.Now(), high) {
 			qm.logger.Infof("added pipelineRun (%s) to queue for repository (%s)", pr, repoKey(repo))
 		}
 	}
@@ -128,6 +143,25 @@ func (qm *QueueManager) RemoveFromQueue(repo *v1alpha1.Repository, run *tektonv1
 	return ""
 }
 
+// ReplaceRunning makes a new pipelineRun take the place of a running one
+// in the queue of the repository, ie: when a failed pipelineRun is retried,
+// without starting the next waiting pipelineRun
+func (qm *QueueManager) ReplaceRunning(repo *v1alpha1.Repository, run, newRun *tektonv1.PipelineRun) bool {
+	qm.lock.Lock()
+	defer qm.lock.Unlock()
+
+	repoKey := repoKey(repo)
+	sema, found := qm.queueMap[repoKey]
+	if !found {
+		return false
+	}
+	if !sema.replace(getQueueKey(run), getQueueKey(newRun)) {
+		return false
+	}
+	qm.logger.Infof("replaced (%s) by (%s) in running for repository (%s)", getQueueKey(run), getQueueKey(newRun), repoKey)
+	return true
+}
+
 func getQueueKey(run *tektonv1.PipelineRun) string {
 	return fmt.Sprintf("%s/%s", run.Namespace, run.Name)
 }
@@ -169,7 +203,7 @@ func (qm *QueueManager) InitQueues(ctx context.Context, tekton versioned2.Interf
 				return nil
 			}
 			orderedList := strings.Split(order, ",")
-			_, err = qm.AddListToQueue(&repo, orderedList)
+			_, err = qm.AddPipelineRunListToQueue(&repo, pr, orderedList)
 			if err != nil {
 				qm.logger.Error("failed to init queue for repo: ", repo.GetName())
 			}
@@ -196,7 +230,7 @@ func (qm *QueueManager) InitQueues(ctx context.Context, tekton versioned2.Interf
 			}
 			orderedList := strings.Split(order, ",")
 
-			_, err = qm.AddListToQueue(&repo, orderedList)
+			_, err = qm.AddPipelineRunListToQueue(&repo, pr, orderedList)
 			if err != nil {
 				qm.logger.Error("failed to init queue for repo: ", repo.GetName())
 			}
@@ -214,6 +248,8 @@ func (qm *QueueManager) RemoveRepository(repo *v1alpha1.Repository) {
 	delete(qm.queueMap, repoKey)
 }
 
+// QueuedPipelineRuns returns the waiting pipelineRuns of the repository in the
+// order they will be started
 func (qm *QueueManager) QueuedPipelineRuns(repo *v1alpha1.Repository) []string {
 	qm.lock.Lock()
 	defer qm.lock.Unlock()
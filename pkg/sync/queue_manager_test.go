@@ -73,6 +73,56 @@ func TestNewQueueManagerForList(t *testing.T) {
 	assert.Equal(t, started[0], getQueueKey(prFourth))
 }
 
+func TestQueueManagerReplaceRunning(t *testing.T) {
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+
+	qm := NewQueueManager(logger)
+	repo := newTestRepo("test", 1)
+
+	prFirst := newTestPR("first", time.Now(), nil, nil)
+	prSecond := newTestPR("second", time.Now().Add(1*time.Second), nil, nil)
+	started, err := qm.AddListToQueue(repo, []string{getQueueKey(prFirst), getQueueKey(prSecond)})
+	assert.NilError(t, err)
+	assert.Equal(t, len(started), 1)
+
+	// the retry of the first takes its place, the second keeps waiting
+	prRetry := newTestPR("first-retry", time.Now().Add(2*time.Second), nil, nil)
+	assert.Assert(t, qm.ReplaceRunning(repo, prFirst, prRetry))
+	assert.DeepEqual(t, qm.RunningPipelineRuns(repo), []string{getQueueKey(prRetry)})
+	assert.DeepEqual(t, qm.QueuedPipelineRuns(repo), []string{getQueueKey(prSecond)})
+
+	// only a running pipelineRun can be replaced
+	assert.Assert(t, !qm.ReplaceRunning(repo, prSecond, prRetry))
+	assert.Assert(t, !qm.ReplaceRunning(newTestRepo("other", 1), prRetry, prFirst))
+
+	assert.Equal(t, qm.RemoveFromQueue(repo, prRetry), getQueueKey(prSecond))
+}
+
+func TestQueueManagerHighPriority(t *testing.T) {
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+
+	qm := NewQueueManager(logger)
+	repo := newTestRepo("test", 1)
+
+	prRunning := newTestPR("running", time.Now(), nil, nil)
+	prPullRequest := newTestPR("pull-request", time.Now().Add(1*time.Second), nil, nil)
+	prPush := newTestPR("push", time.Now().Add(2*time.Second), nil, map[string]string{
+		keys.QueuePriority: kubeinteraction.QueuePriorityHigh,
+	})
+	_, err := qm.AddPipelineRunListToQueue(repo, prRunning, []string{getQueueKey(prRunning)})
+	assert.NilError(t, err)
+	_, err = qm.AddPipelineRunListToQueue(repo, prPullRequest, []string{getQueueKey(prPullRequest)})
+	assert.NilError(t, err)
+	_, err = qm.AddPipelineRunListToQueue(repo, prPush, []string{getQueueKey(prPush)})
+	assert.NilError(t, err)
+
+	// the push waiting after the pull request is started first
+	assert.DeepEqual(t, qm.QueuedPipelineRuns(repo), []string{getQueueKey(prPush), getQueueKey(prPullRequest)})
+	assert.Equal(t, qm.RemoveFromQueue(repo, prRunning), getQueueKey(prPush))
+}
+
 func TestNewQueueManagerReListing(t *testing.T) {
 	observer, _ := zapobserver.New(zap.InfoLevel)
 	logger := zap.New(observer).Sugar()
@@ -2,6 +2,7 @@ package sync
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -38,9 +39,13 @@ func (s *prioritySemaphore) getLimit() int {
 	return s.limit
 }
 
+// getCurrentPending returns the waiting items in the order they will be
+// started
 func (s *prioritySemaphore) getCurrentPending() []string {
+	pending := priorityQueue{items: append([]*item{}, s.pending.items...)}
+	sort.SliceStable(pending.items, pending.Less)
 	keys := []string{}
-	for _, item := range s.pending.items {
+	for _, item := range pending.items {
 		keys = append(keys, item.key)
 	}
 	return keys
@@ -116,7 +121,20 @@ func (s *prioritySemaphore) release(key string) bool {
 	return true
 }
 
-func (s *prioritySemaphore) addToQueue(key string, creationTime time.Time) bool {
+// replace hands over the lock held by a running key to another key.
+func (s *prioritySemaphore) replace(key, newKey string) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.running[key]; !ok {
+		return false
+	}
+	delete(s.running, key)
+	s.running[newKey] = true
+	return true
+}
+
+func (s *prioritySemaphore) addToQueue(key string, creationTime time.Time, high bool) bool {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
@@ -126,7 +144,11 @@ func (s *prioritySemaphore) addToQueue(key string, creationTime time.Time) bool
 	if s.pending.isPending(key) {
 		return false
 	}
-	s.pending.add(key, creationTime.UnixNano())
+	if high {
+		s.pending.addHigh(key, creationTime.UnixNano())
+	} else {
+		s.pending.add(key, creationTime.UnixNano())
+	}
 	return true
 }
 
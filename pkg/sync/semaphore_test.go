@@ -18,9 +18,9 @@ func TestNewSemaphore(t *testing.T) {
 	// add elements
 	// randomly adding elements, the element with the less priority
 	// must execute first
-	assert.Equal(t, repo.addToQueue("C", cw.Now().Add(5*time.Second)), true)
-	assert.Equal(t, repo.addToQueue("A", cw.Now()), true)
-	assert.Equal(t, repo.addToQueue("B", cw.Now().Add(1*time.Second)), true)
+	assert.Equal(t, repo.addToQueue("C", cw.Now().Add(5*time.Second), false), true)
+	assert.Equal(t, repo.addToQueue("A", cw.Now(), false), true)
+	assert.Equal(t, repo.addToQueue("B", cw.Now().Add(1*time.Second), false), true)
 
 	// start the topmost, which would be A
 	acquired, msg := repo.tryAcquire("A")
@@ -44,7 +44,7 @@ func TestNewSemaphore(t *testing.T) {
 
 	// adding element to Queue which is running
 	// nothing should happen
-	assert.Equal(t, repo.addToQueue("A", cw.Now().Add(5*time.Second)), false)
+	assert.Equal(t, repo.addToQueue("A", cw.Now().Add(5*time.Second), false), false)
 
 	// A is done
 	repo.release("A")
@@ -67,9 +67,9 @@ func TestNewSemaphore(t *testing.T) {
 	repo.resize(2)
 
 	// now add new elements
-	assert.Equal(t, repo.addToQueue("D", cw.Now().Add(8*time.Second)), true)
-	assert.Equal(t, repo.addToQueue("E", cw.Now().Add(6*time.Second)), true)
-	assert.Equal(t, repo.addToQueue("F", cw.Now().Add(7*time.Second)), true)
+	assert.Equal(t, repo.addToQueue("D", cw.Now().Add(8*time.Second), false), true)
+	assert.Equal(t, repo.addToQueue("E", cw.Now().Add(6*time.Second), false), true)
+	assert.Equal(t, repo.addToQueue("F", cw.Now().Add(7*time.Second), false), true)
 
 	// queue already have C in it
 	// now the queue must have C > E > F > D
@@ -108,3 +108,13 @@ func TestNewSemaphore(t *testing.T) {
 
 	assert.Equal(t, repo.acquireLatest(), "")
 }
+
+func TestSemaphorePendingOrder(t *testing.T) {
+	repo := newSemaphore("test", 1)
+	cw := clockwork.NewFakeClock()
+
+	for i, key := range []string{"E", "D", "C", "B", "A"} {
+		repo.addToQueue(key, cw.Now().Add(time.Duration(5-i)*time.Second), key == "C")
+	}
+	assert.DeepEqual(t, repo.getCurrentPending(), []string{"C", "A", "B", "D", "E"})
+}
@@ -0,0 +1,123 @@
+package templates
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+)
+
+// templateFunc transforms the value of a placeholder with the arguments
+// given after its name.
+type templateFunc struct {
+	args int
+	fn   func(value string, args []string) (string, error)
+}
+
+// templateFuncs are the functions which can be piped after the variable of a
+// placeholder, ie: {{ source_branch | replace "/" "-" | trunc 20 }}.
+var templateFuncs = map[string]templateFunc{
+	"lower": {fn: func(value string, _ []string) (string, error) {
+		return strings.ToLower(value), nil
+	}},
+	"upper": {fn: func(value string, _ []string) (string, error) {
+		return strings.ToUpper(value), nil
+	}},
+	"trunc": {args: 1, fn: func(value string, args []string) (string, error) {
+		length, err := strconv.Atoi(args[0])
+		if err != nil || length < 0 {
+			return "", fmt.Errorf("trunc needs a positive length: %s", args[0])
+		}
+		if runes := []rune(value); len(runes) > length {
+			return string(runes[:length]), nil
+		}
+		return value, nil
+	}},
+	"replace": {args: 2, fn: func(value string, args []string) (string, error) {
+		return strings.ReplaceAll(value, args[0], args[1]), nil
+	}},
+	"sha_short": {fn: func(value string, _ []string) (string, error) {
+		return formatting.ShortSHA(value), nil
+	}},
+}
+
+// evaluate returns the value of the expression of a placeholder, the
+// variable followed by the functions it is piped to. It returns false when
+// the variable is not known or a function is not valid.
+func evaluate(expr string, dico map[string]string) (string, bool) {
+	pipeline, err := splitOutsideQuotes(expr, '|')
+	if err != nil {
+		return "", false
+	}
+	value, ok := dico[strings.TrimSpace(pipeline[0])]
+	if !ok {
+		return "", false
+	}
+	for _, command := range pipeline[1:] {
+		fields, err := splitOutsideQuotes(command, ' ')
+		if err != nil {
+			return "", false
+		}
+		words := []string{}
+		for _, field := range fields {
+			if field != "" {
+				words = append(words, field)
+			}
+		}
+		if len(words) == 0 {
+			return "", false
+		}
+		f, ok := templateFuncs[words[0]]
+		if !ok || len(words)-1 != f.args {
+			return "", false
+		}
+		args := make([]string, 0, f.args)
+		for _, word := range words[1:] {
+			arg, err := unquote(word)
+			if err != nil {
+				return "", false
+			}
+			args = append(args, arg)
+		}
+		if value, err = f.fn(value, args); err != nil {
+			return "", false
+		}
+	}
+	return value, true
+}
+
+// splitOutsideQuotes splits s on the separators which are not inside double
+// quotes.
+func splitOutsideQuotes(s string, sep rune) ([]string, error) {
+	fields := []string{}
+	var current strings.Builder
+	inQuotes, escaped := false, false
+	for _, r := range s {
+		switch {
+		case escaped:
+			escaped = false
+		case inQuotes && r == '\\':
+			escaped = true
+		case r == '"':
+			inQuotes = !inQuotes
+		case !inQuotes && r == sep:
+			fields = append(fields, current.String())
+			current.Reset()
+			continue
+		}
+		current.WriteRune(r)
+	}
+	if inQuotes {
+		return nil, fmt.Errorf("unterminated quote in %s", s)
+	}
+	return append(fields, current.String()), nil
+}
+
+// unquote returns the value of a quoted argument or the argument as is.
+func unquote(arg string) (string, error) {
+	if strings.HasPrefix(arg, `"`) {
+		return strconv.Unquote(arg)
+	}
+	return arg, nil
+}
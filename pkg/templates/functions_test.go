@@ -0,0 +1,96 @@
+package templates
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestReplacePlaceHoldersFunctions(t *testing.T) {
+	dico := map[string]string{
+		"revision":      "0123456789abcdef",
+		"source_branch": "Feature/Login-Page",
+		"repo_name":     "pipelines-as-code",
+	}
+	tests := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{
+			name:     "lower",
+			template: "{{ source_branch | lower }}",
+			expected: "feature/login-page",
+		},
+		{
+			name:     "upper",
+			template: "{{repo_name|upper}}",
+			expected: "PIPELINES-AS-CODE",
+		},
+		{
+			name:     "trunc",
+			template: "{{ repo_name | trunc 9 }}",
+			expected: "pipelines",
+		},
+		{
+			name:     "trunc longer than the value",
+			template: "{{ repo_name | trunc 100 }}",
+			expected: "pipelines-as-code",
+		},
+		{
+			name:     "replace",
+			template: `{{ source_branch | replace "/" "-" }}`,
+			expected: "Feature-Login-Page",
+		},
+		{
+			name:     "replace with spaces and pipes in the arguments",
+			template: `{{ repo_name | replace "-" " | " }}`,
+			expected: "pipelines | as | code",
+		},
+		{
+			name:     "sha_short",
+			template: "image:{{ revision | sha_short }}",
+			expected: "image:0123456",
+		},
+		{
+			name:     "chained",
+			template: `{{ source_branch | replace "/" "-" | lower | trunc 13 }}`,
+			expected: "feature-login",
+		},
+		{
+			name:     "unknown function",
+			template: "{{ repo_name | title }}",
+			expected: "{{ repo_name | title }}",
+		},
+		{
+			name:     "missing argument",
+			template: "{{ repo_name | trunc }}",
+			expected: "{{ repo_name | trunc }}",
+		},
+		{
+			name:     "invalid length",
+			template: "{{ repo_name | trunc -1 }}",
+			expected: "{{ repo_name | trunc -1 }}",
+		},
+		{
+			name:     "unterminated quote",
+			template: `{{ repo_name | replace "- "" }}`,
+			expected: `{{ repo_name | replace "- "" }}`,
+		},
+		{
+			name:     "unknown variable",
+			template: "{{ foo | lower }}",
+			expected: "{{ foo | lower }}",
+		},
+		{
+			name:     "empty function",
+			template: "{{ repo_name | }}",
+			expected: "{{ repo_name | }}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, ReplacePlaceHoldersVariables(tt.template, dico), tt.expected)
+		})
+	}
+}
@@ -12,15 +12,17 @@ import (
 
 var reTemplate = regexp.MustCompile(`{{([^}]{2,})}}`)
 
-// ReplacePlaceHoldersVariables Replace those {{var}} placeholders to the runinfo variable
+// ReplacePlaceHoldersVariables Replace those {{var}} placeholders to the runinfo variable,
+// the variable can be piped to the template functions, ie: {{ revision | sha_short }}.
+// The placeholders with an unknown variable or an invalid function are kept as is.
 func ReplacePlaceHoldersVariables(template string, dico map[string]string) string {
 	return reTemplate.ReplaceAllStringFunc(template, func(s string) string {
 		parts := reTemplate.FindStringSubmatch(s)
-		key := strings.TrimSpace(parts[1])
-		if _, ok := dico[key]; !ok {
+		value, ok := evaluate(parts[1], dico)
+		if !ok {
 			return s
 		}
-		return dico[key]
+		return value
 	})
 }
 
@@ -47,5 +49,24 @@ func Process(event *info.Event, repo *v1alpha1.Repository, template string) stri
 	if event.PullRequestNumber != 0 {
 		maptemplate["pull_request_number"] = fmt.Sprintf("%d", event.PullRequestNumber)
 	}
+	if len(event.PullRequestLabel) > 0 {
+		maptemplate["pull_request_labels"] = strings.Join(event.PullRequestLabel, ",")
+	}
+	if event.DefaultBranch != "" {
+		maptemplate["default_branch"] = formatting.SanitizeBranch(event.DefaultBranch)
+	}
+	if event.MergeRequestRef != "" {
+		maptemplate["merge_ref"] = event.MergeRequestRef
+	}
+	if env := repo.EnvironmentForBranch(event.BaseBranch); env != "" {
+		maptemplate["environment"] = env
+	}
+	if event.TriggerComment != "" {
+		// the newlines are escaped to keep the comment on a single line of
+		// the PipelineRun yaml
+		maptemplate["trigger_comment"] = strings.NewReplacer("\r\n", "\\n", "\n", "\\n").Replace(event.TriggerComment)
+		maptemplate["trigger_comment_args"] = event.TriggerCommentArgs
+		maptemplate["trigger_comment_author"] = event.Sender
+	}
 	return ReplacePlaceHoldersVariables(template, maptemplate)
 }
@@ -77,12 +77,82 @@ func TestProcessTemplates(t *testing.T) {
 			template: `{{ pull_request_number }}`,
 			expected: "666",
 		},
+		{
+			name: "process pull request labels",
+			event: &info.Event{
+				PullRequestLabel: []string{"bug", "team/infra"},
+			},
+			template: `{{ pull_request_labels }}`,
+			expected: "bug,team/infra",
+		},
+		{
+			name: "process merge request ref",
+			event: &info.Event{
+				MergeRequestRef: "refs/merge-requests/1/train",
+			},
+			template: `{{ merge_ref }}`,
+			expected: "refs/merge-requests/1/train",
+		},
+		{
+			name: "process default branch",
+			event: &info.Event{
+				DefaultBranch: "trunk",
+			},
+			template: `on-target-branch: "[{{ default_branch }}]"`,
+			expected: `on-target-branch: "[trunk]"`,
+		},
+		{
+			name:     "no default branch",
+			event:    &info.Event{},
+			template: `{{ default_branch }}`,
+			expected: `{{ default_branch }}`,
+		},
+		{
+			name: "process trigger comment",
+			event: &info.Event{
+				Sender: "Apollo",
+				State: info.State{
+					TriggerComment:     "/test deploy staging\r\nplease",
+					TriggerCommentArgs: "staging",
+				},
+			},
+			template: `{{ trigger_comment }} {{ trigger_comment_args }} {{ trigger_comment_author }}`,
+			expected: `/test deploy staging\nplease staging Apollo`,
+		},
+		{
+			name:     "no trigger comment",
+			event:    &info.Event{},
+			template: `{{ trigger_comment }}`,
+			expected: `{{ trigger_comment }}`,
+		},
 		{
 			name:     "no pull request no nothing",
 			event:    &info.Event{},
 			template: `{{ pull_request_number }}`,
 			expected: `{{ pull_request_number }}`,
 		},
+		{
+			name: "process environment from target branch",
+			event: &info.Event{
+				BaseBranch: "refs/heads/release-1.0",
+			},
+			template: `{{ environment }}`,
+			expected: "prod",
+			repository: &v1alpha1.Repository{
+				Spec: v1alpha1.RepositorySpec{
+					Environments: []v1alpha1.Environment{
+						{Name: "staging", Branches: []string{"main"}},
+						{Name: "prod", Branches: []string{"release-*"}},
+					},
+				},
+			},
+		},
+		{
+			name:     "no environment matching",
+			event:    &info.Event{BaseBranch: "main"},
+			template: `{{ environment }}`,
+			expected: `{{ environment }}`,
+		},
 		{
 			name: "test process templates lowering owner and repository",
 			event: &info.Event{
@@ -0,0 +1,128 @@
+package bitbucketcloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ktrysmt/go-bitbucket"
+	bbcloudtest "github.com/openshift-pipelines/pipelines-as-code/pkg/provider/bitbucketcloud/test"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/bitbucketcloud/types"
+	testhttp "github.com/openshift-pipelines/pipelines-as-code/pkg/test/http"
+	"gotest.tools/v3/assert"
+)
+
+// FakeServer is a fake Bitbucket Cloud API for a repository, it records the
+// commit statuses and the pull request comments sent to it and can serve the
+// files of the repository.
+type FakeServer struct {
+	Client     *bitbucket.Client
+	Mux        *http.ServeMux
+	Workspace  string
+	Repository string
+
+	statuses *testhttp.Recorder
+	comments *testhttp.Recorder
+}
+
+// NewFakeServer starts a fake Bitbucket Cloud API server for the repository
+// workspace/repository, the server is closed at the end of the test.
+func NewFakeServer(t *testing.T, workspace, repository string) *FakeServer {
+	client, mux, teardown := bbcloudtest.SetupBBCloudClient(t)
+	t.Cleanup(teardown)
+
+	f := &FakeServer{
+		Client:     client,
+		Mux:        mux,
+		Workspace:  workspace,
+		Repository: repository,
+		statuses:   &testhttp.Recorder{},
+		comments:   &testhttp.Recorder{},
+	}
+	statusesHandler := f.statuses.Handler(t, "{}")
+	mux.HandleFunc(f.repoPath("/commit/"), func(rw http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/statuses/build") {
+			http.NotFound(rw, r)
+			return
+		}
+		statusesHandler(rw, r)
+	})
+	commentsHandler := f.comments.Handler(t, "{}")
+	mux.HandleFunc(f.repoPath("/pullrequests/"), func(rw http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/comments") {
+			http.NotFound(rw, r)
+			return
+		}
+		commentsHandler(rw, r)
+	})
+	return f
+}
+
+func (f *FakeServer) repoPath(path string) string {
+	return fmt.Sprintf("/repositories/%s/%s%s", f.Workspace, f.Repository, path)
+}
+
+// ServeFiles serves the files (path as key and content as value) of the
+// repository at the commit sha, through the src API listing the directories
+// and getting the file blobs.
+func (f *FakeServer) ServeFiles(t *testing.T, sha string, files map[string]string) {
+	dirs := map[string][]bitbucket.RepositoryFile{}
+	seen := map[string]bool{}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		dirs[filepath.Dir(name)] = append(dirs[filepath.Dir(name)], bitbucket.RepositoryFile{Path: name, Type: "commit_file"})
+		// add every parent directory to its own parent
+		for dir := filepath.Dir(name); filepath.Dir(dir) != "." && !seen[dir]; dir = filepath.Dir(dir) {
+			seen[dir] = true
+			dirs[filepath.Dir(dir)] = append(dirs[filepath.Dir(dir)], bitbucket.RepositoryFile{Path: dir, Type: "commit_directory"})
+		}
+	}
+
+	prefix := f.repoPath(fmt.Sprintf("/src/%s/", sha))
+	f.Mux.HandleFunc(prefix, func(rw http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(r.URL.Path, prefix)
+		if content, ok := files[name]; ok {
+			fmt.Fprint(rw, content)
+			return
+		}
+		values, ok := dirs[strings.TrimSuffix(name, "/")]
+		if !ok {
+			http.NotFound(rw, r)
+			return
+		}
+		b, err := json.Marshal(map[string][]bitbucket.RepositoryFile{"values": values})
+		assert.NilError(t, err)
+		fmt.Fprint(rw, string(b))
+	})
+}
+
+// Statuses returns the commit statuses sent to the server.
+func (f *FakeServer) Statuses(t *testing.T) []bitbucket.CommitStatusOptions {
+	statuses := []bitbucket.CommitStatusOptions{}
+	for _, body := range f.statuses.Bodies() {
+		status := bitbucket.CommitStatusOptions{}
+		assert.NilError(t, json.Unmarshal(body, &status))
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// Comments returns the raw content of the pull request comments sent to the
+// server.
+func (f *FakeServer) Comments(t *testing.T) []string {
+	comments := []string{}
+	for _, body := range f.comments.Bodies() {
+		comment := types.Comment{}
+		assert.NilError(t, json.Unmarshal(body, &comment))
+		comments = append(comments, comment.Content.Raw)
+	}
+	return comments
+}
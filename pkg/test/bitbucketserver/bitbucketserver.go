@@ -0,0 +1,124 @@
+package bitbucketserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+
+	bbv1 "github.com/gfleury/go-bitbucket-v1"
+	bbstest "github.com/openshift-pipelines/pipelines-as-code/pkg/provider/bitbucketserver/test"
+	testhttp "github.com/openshift-pipelines/pipelines-as-code/pkg/test/http"
+	"gotest.tools/v3/assert"
+)
+
+// FakeServer is a fake Bitbucket Server API for a repository, it records the
+// build statuses and the pull request comments sent to it and can serve the
+// files of the repository.
+type FakeServer struct {
+	Client     *bbv1.APIClient
+	Mux        *http.ServeMux
+	Project    string
+	Repository string
+
+	statuses *testhttp.Recorder
+	comments *testhttp.Recorder
+}
+
+// NewFakeServer starts a fake Bitbucket Server API server for the repository
+// in project, the server is closed at the end of the test.
+func NewFakeServer(ctx context.Context, t *testing.T, project, repository string) *FakeServer {
+	client, mux, teardown := bbstest.SetupBBServerClient(ctx, t)
+	t.Cleanup(teardown)
+
+	f := &FakeServer{
+		Client:     client,
+		Mux:        mux,
+		Project:    project,
+		Repository: repository,
+		statuses:   &testhttp.Recorder{},
+		comments:   &testhttp.Recorder{},
+	}
+	mux.HandleFunc("/commits/", f.statuses.Handler(t, "{}"))
+	commentsHandler := f.comments.Handler(t, "{}")
+	mux.HandleFunc(f.repoPath("/pull-requests/"), func(rw http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/comments") {
+			http.NotFound(rw, r)
+			return
+		}
+		commentsHandler(rw, r)
+	})
+	return f
+}
+
+func (f *FakeServer) repoPath(path string) string {
+	return fmt.Sprintf("/projects/%s/repos/%s%s", f.Project, f.Repository, path)
+}
+
+// ServeFiles serves the files (path as key and content as value) of the
+// repository, through the files listing and the raw files API.
+func (f *FakeServer) ServeFiles(t *testing.T, files map[string]string) {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	filesPrefix := f.repoPath("/files/")
+	f.Mux.HandleFunc(filesPrefix, func(rw http.ResponseWriter, r *http.Request) {
+		dir := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, filesPrefix), "/")
+		values := []string{}
+		for _, name := range names {
+			if strings.HasPrefix(name, dir+"/") {
+				values = append(values, strings.TrimPrefix(name, dir+"/"))
+			}
+		}
+		if len(values) == 0 {
+			http.NotFound(rw, r)
+			return
+		}
+		b, err := json.Marshal(map[string]interface{}{
+			"start":      0,
+			"size":       len(values),
+			"isLastPage": true,
+			"values":     values,
+		})
+		assert.NilError(t, err)
+		fmt.Fprint(rw, string(b))
+	})
+
+	rawPrefix := f.repoPath("/raw/")
+	f.Mux.HandleFunc(rawPrefix, func(rw http.ResponseWriter, r *http.Request) {
+		content, ok := files[strings.TrimPrefix(r.URL.Path, rawPrefix)]
+		if !ok {
+			http.NotFound(rw, r)
+			return
+		}
+		fmt.Fprint(rw, content)
+	})
+}
+
+// Statuses returns the build statuses sent to the server.
+func (f *FakeServer) Statuses(t *testing.T) []bbv1.BuildStatus {
+	statuses := []bbv1.BuildStatus{}
+	for _, body := range f.statuses.Bodies() {
+		status := bbv1.BuildStatus{}
+		assert.NilError(t, json.Unmarshal(body, &status))
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// Comments returns the text of the pull request comments sent to the server.
+func (f *FakeServer) Comments(t *testing.T) []string {
+	comments := []string{}
+	for _, body := range f.comments.Bodies() {
+		comment := bbv1.Comment{}
+		assert.NilError(t, json.Unmarshal(body, &comment))
+		comments = append(comments, comment.Text)
+	}
+	return comments
+}
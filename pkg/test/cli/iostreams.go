@@ -3,8 +3,11 @@ package cli
 import (
 	"bytes"
 	"io"
+	"strings"
+	"testing"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"gotest.tools/v3/golden"
 )
 
 // NewIOStream return a fake iostreams
@@ -18,3 +21,11 @@ func NewIOStream() (*cli.IOStreams, *bytes.Buffer) {
 		ErrOut: errOut,
 	}, out
 }
+
+// AssertGolden compares the output to the golden file named after the test and
+// its subtests, it only relies on the test so the tests comparing their output
+// to golden files can run in parallel.
+func AssertGolden(t *testing.T, actual string) {
+	t.Helper()
+	golden.Assert(t, actual, strings.ReplaceAll(t.Name()+".golden", "/", "-"))
+}
@@ -45,6 +45,7 @@ type Data struct {
 	Secret       []*corev1.Secret
 	Events       []*corev1.Event
 	ConfigMap    []*corev1.ConfigMap
+	Pods         []*corev1.Pod
 }
 
 // SeedTestData returns Clients and Informers populated with the
@@ -112,6 +113,12 @@ func SeedTestData(t *testing.T, ctx context.Context, d Data) (Clients, Informers
 		}
 	}
 
+	for _, pod := range d.Pods {
+		if _, err := c.Kube.CoreV1().Pods(pod.Namespace).Create(ctx, pod, metav1.CreateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
 	c.PipelineAsCode.ClearActions()
 	c.Pipeline.ClearActions()
 	c.Kube.ClearActions()
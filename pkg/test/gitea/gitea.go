@@ -0,0 +1,169 @@
+package gitea
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"code.gitea.io/sdk/gitea"
+	giteatest "github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gitea/test"
+	testhttp "github.com/openshift-pipelines/pipelines-as-code/pkg/test/http"
+	"gotest.tools/v3/assert"
+)
+
+// FakeServer is a fake Gitea API for a repository, it records the commit
+// statuses and the issue comments sent to it and can serve the files of the
+// repository.
+type FakeServer struct {
+	Client       *gitea.Client
+	Mux          *http.ServeMux
+	Organization string
+	Repository   string
+
+	statuses *testhttp.Recorder
+	comments *testhttp.Recorder
+}
+
+// NewFakeServer starts a fake Gitea API server for the repository
+// organization/repository, the server is closed at the end of the test.
+func NewFakeServer(t *testing.T, organization, repository string) *FakeServer {
+	client, mux, teardown := giteatest.Setup(t)
+	t.Cleanup(teardown)
+
+	f := &FakeServer{
+		Client:       client,
+		Mux:          mux,
+		Organization: organization,
+		Repository:   repository,
+		statuses:     &testhttp.Recorder{},
+		comments:     &testhttp.Recorder{},
+	}
+	mux.HandleFunc(f.repoPath("/statuses/"), f.statuses.Handler(t, "{}"))
+	commentsHandler := f.comments.Handler(t, "{}")
+	mux.HandleFunc(f.repoPath("/issues/"), func(rw http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/comments") {
+			http.NotFound(rw, r)
+			return
+		}
+		commentsHandler(rw, r)
+	})
+	return f
+}
+
+func (f *FakeServer) repoPath(path string) string {
+	return fmt.Sprintf("/repos/%s/%s%s", f.Organization, f.Repository, path)
+}
+
+func objectSHA(path string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(path)))
+}
+
+// ServeFiles serves the files (path as key and content as value) of the
+// repository at the commit sha, through the git trees, blobs and contents API.
+func (f *FakeServer) ServeFiles(t *testing.T, sha string, files map[string]string) {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// the root tree list the top level directories and files, every
+	// directory tree is then listed recursively from its sha.
+	rootEntries := []gitea.GitEntry{}
+	dirEntries := map[string][]gitea.GitEntry{}
+	for _, name := range names {
+		dir, _, found := strings.Cut(name, "/")
+		if !found {
+			rootEntries = append(rootEntries, gitea.GitEntry{Path: name, Type: "blob", SHA: objectSHA(name)})
+			continue
+		}
+		if _, ok := dirEntries[objectSHA(dir)]; !ok {
+			rootEntries = append(rootEntries, gitea.GitEntry{Path: dir, Type: "tree", SHA: objectSHA(dir)})
+		}
+		dirEntries[objectSHA(dir)] = append(dirEntries[objectSHA(dir)], gitea.GitEntry{
+			Path: strings.TrimPrefix(name, dir+"/"),
+			Type: "blob",
+			SHA:  objectSHA(name),
+		})
+	}
+
+	f.Mux.HandleFunc(f.repoPath("/git/trees/"), func(rw http.ResponseWriter, r *http.Request) {
+		entries, ok := dirEntries[filepath.Base(r.URL.Path)]
+		if filepath.Base(r.URL.Path) == sha {
+			entries, ok = rootEntries, true
+		}
+		if !ok {
+			http.NotFound(rw, r)
+			return
+		}
+		b, err := json.Marshal(gitea.GitTreeResponse{SHA: filepath.Base(r.URL.Path), Entries: entries})
+		assert.NilError(t, err)
+		fmt.Fprint(rw, string(b))
+	})
+
+	f.Mux.HandleFunc(f.repoPath("/git/blobs/"), func(rw http.ResponseWriter, r *http.Request) {
+		for _, name := range names {
+			if objectSHA(name) == filepath.Base(r.URL.Path) {
+				b, err := json.Marshal(gitea.GitBlobResponse{
+					SHA:      objectSHA(name),
+					Encoding: "base64",
+					Content:  base64.StdEncoding.EncodeToString([]byte(files[name])),
+				})
+				assert.NilError(t, err)
+				fmt.Fprint(rw, string(b))
+				return
+			}
+		}
+		http.NotFound(rw, r)
+	})
+
+	prefix := f.repoPath("/contents/")
+	f.Mux.HandleFunc(prefix, func(rw http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(r.URL.Path, prefix)
+		content, ok := files[name]
+		if !ok {
+			http.NotFound(rw, r)
+			return
+		}
+		encoding := "base64"
+		encoded := base64.StdEncoding.EncodeToString([]byte(content))
+		b, err := json.Marshal(gitea.ContentsResponse{
+			Name:     filepath.Base(name),
+			Path:     name,
+			SHA:      objectSHA(name),
+			Type:     "file",
+			Encoding: &encoding,
+			Content:  &encoded,
+		})
+		assert.NilError(t, err)
+		fmt.Fprint(rw, string(b))
+	})
+}
+
+// Statuses returns the commit statuses sent to the server.
+func (f *FakeServer) Statuses(t *testing.T) []gitea.CreateStatusOption {
+	statuses := []gitea.CreateStatusOption{}
+	for _, body := range f.statuses.Bodies() {
+		status := gitea.CreateStatusOption{}
+		assert.NilError(t, json.Unmarshal(body, &status))
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// Comments returns the body of the issue comments sent to the server.
+func (f *FakeServer) Comments(t *testing.T) []string {
+	comments := []string{}
+	for _, body := range f.comments.Bodies() {
+		comment := gitea.CreateIssueCommentOption{}
+		assert.NilError(t, json.Unmarshal(body, &comment))
+		comments = append(comments, comment.Body)
+	}
+	return comments
+}
@@ -0,0 +1,116 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	gltest "github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gitlab/test"
+	testhttp "github.com/openshift-pipelines/pipelines-as-code/pkg/test/http"
+	"github.com/xanzy/go-gitlab"
+	"gotest.tools/v3/assert"
+)
+
+// FakeServer is a fake GitLab API for a project, it records the commit
+// statuses and the merge request notes sent to it and can serve the files of
+// the repository.
+type FakeServer struct {
+	Client    *gitlab.Client
+	Mux       *http.ServeMux
+	ProjectID int
+
+	statuses *testhttp.Recorder
+	notes    *testhttp.Recorder
+}
+
+// NewFakeServer starts a fake GitLab API server for the project projectID,
+// the server is closed at the end of the test.
+func NewFakeServer(ctx context.Context, t *testing.T, projectID int) *FakeServer {
+	client, mux, teardown := gltest.Setup(ctx, t)
+	t.Cleanup(teardown)
+
+	f := &FakeServer{
+		Client:    client,
+		Mux:       mux,
+		ProjectID: projectID,
+		statuses:  &testhttp.Recorder{},
+		notes:     &testhttp.Recorder{},
+	}
+	mux.HandleFunc(fmt.Sprintf("/projects/%d/statuses/", projectID), f.statuses.Handler(t, "{}"))
+	notesHandler := f.notes.Handler(t, "{}")
+	mux.HandleFunc(fmt.Sprintf("/projects/%d/merge_requests/", projectID), func(rw http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/notes") {
+			http.NotFound(rw, r)
+			return
+		}
+		notesHandler(rw, r)
+	})
+	return f
+}
+
+// ServeFiles serves the files (path as key and content as value) of the
+// repository on the ref branch, through the tree and the raw files API.
+func (f *FakeServer) ServeFiles(t *testing.T, ref string, files map[string]string) {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	f.Mux.HandleFunc(fmt.Sprintf("/projects/%d/repository/tree", f.ProjectID), func(rw http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("ref") != ref {
+			http.NotFound(rw, r)
+			return
+		}
+		path := r.URL.Query().Get("path")
+		nodes := []*gitlab.TreeNode{}
+		for _, name := range names {
+			if path != "" && !strings.HasPrefix(name, path+"/") {
+				continue
+			}
+			nodes = append(nodes, &gitlab.TreeNode{Name: filepath.Base(name), Path: name, Type: "blob"})
+		}
+		b, err := json.Marshal(nodes)
+		assert.NilError(t, err)
+		fmt.Fprint(rw, string(b))
+	})
+
+	prefix := fmt.Sprintf("/projects/%d/repository/files/", f.ProjectID)
+	f.Mux.HandleFunc(prefix, func(rw http.ResponseWriter, r *http.Request) {
+		name := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, prefix), "/raw")
+		content, ok := files[name]
+		if !ok || r.URL.Query().Get("ref") != ref {
+			http.NotFound(rw, r)
+			return
+		}
+		fmt.Fprint(rw, content)
+	})
+}
+
+// Statuses returns the commit statuses sent to the server.
+func (f *FakeServer) Statuses(t *testing.T) []gitlab.SetCommitStatusOptions {
+	statuses := []gitlab.SetCommitStatusOptions{}
+	for _, body := range f.statuses.Bodies() {
+		status := gitlab.SetCommitStatusOptions{}
+		assert.NilError(t, json.Unmarshal(body, &status))
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// Comments returns the body of the merge request notes sent to the server.
+func (f *FakeServer) Comments(t *testing.T) []string {
+	comments := []string{}
+	for _, body := range f.notes.Bodies() {
+		note := gitlab.CreateMergeRequestNoteOptions{}
+		assert.NilError(t, json.Unmarshal(body, &note))
+		assert.Assert(t, note.Body != nil, "note has no body: %s", string(body))
+		comments = append(comments, *note.Body)
+	}
+	return comments
+}
@@ -0,0 +1,187 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+// RecordEnvVar is the environment variable switching the cassettes from
+// replay to record mode, ie: when running the e2e tests against the live
+// provider APIs to capture their interactions.
+const RecordEnvVar = "PAC_TEST_RECORD_CASSETTES"
+
+// CassetteMode is how a cassette handle the requests.
+type CassetteMode int
+
+const (
+	// ModeReplay replays the interactions previously recorded in the
+	// cassette file and never touch the network.
+	ModeReplay CassetteMode = iota
+	// ModeRecord sends the requests to the real API and save the
+	// interactions to the cassette file at the end of the test.
+	ModeRecord
+)
+
+// headers not saved to the cassette, they are either credentials or vary on
+// every request.
+var skippedHeaders = []string{"Authorization", "Private-Token", "Set-Cookie", "Date"}
+
+// CassetteRequest is a recorded request.
+type CassetteRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+// CassetteResponse is a recorded response.
+type CassetteResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Interaction is a request and the response the API gave to it.
+type Interaction struct {
+	Request  CassetteRequest  `json:"request"`
+	Response CassetteResponse `json:"response"`
+}
+
+// Cassette is a http.RoundTripper recording the interactions with an API to
+// a file or replaying them from it, so the interactions of the provider
+// clients can be captured once from the e2e tests and then replayed in unit
+// tests without being subject to the API flakiness or rate limits.
+type Cassette struct {
+	Path         string
+	Mode         CassetteMode
+	Interactions []Interaction
+
+	mu        sync.Mutex
+	used      []bool
+	transport http.RoundTripper
+}
+
+// NewCassette loads the cassette at path when replaying, or prepare a new one
+// to be saved at path at the end of the test when recording.
+func NewCassette(t *testing.T, path string, mode CassetteMode) *Cassette {
+	c := &Cassette{Path: path, Mode: mode, transport: http.DefaultTransport}
+	switch mode {
+	case ModeRecord:
+		t.Cleanup(func() {
+			assert.NilError(t, c.Save())
+		})
+	case ModeReplay:
+		b, err := os.ReadFile(path)
+		assert.NilError(t, err, "cannot read cassette, record it with %s=true", RecordEnvVar)
+		assert.NilError(t, json.Unmarshal(b, &c.Interactions))
+		c.used = make([]bool, len(c.Interactions))
+	}
+	return c
+}
+
+// NewCassetteClient returns a http client going through the cassette at
+// path, the cassette is recorded when the RecordEnvVar environment variable
+// is set to true and replayed otherwise.
+func NewCassetteClient(t *testing.T, path string) *http.Client {
+	mode := ModeReplay
+	if os.Getenv(RecordEnvVar) == "true" {
+		mode = ModeRecord
+	}
+	return &http.Client{Transport: NewCassette(t, path, mode)}
+}
+
+// RoundTrip records or replays the request.
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	body := []byte{}
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	recorded := CassetteRequest{Method: req.Method, URL: req.URL.String(), Body: string(body)}
+
+	if c.Mode == ModeRecord {
+		return c.record(req, recorded)
+	}
+	return c.replay(req, recorded)
+}
+
+func (c *Cassette) record(req *http.Request, recorded CassetteRequest) (*http.Response, error) {
+	resp, err := c.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	header := resp.Header.Clone()
+	for _, h := range skippedHeaders {
+		header.Del(h)
+	}
+	c.mu.Lock()
+	c.Interactions = append(c.Interactions, Interaction{
+		Request:  recorded,
+		Response: CassetteResponse{StatusCode: resp.StatusCode, Header: header, Body: string(respBody)},
+	})
+	c.mu.Unlock()
+	return resp, nil
+}
+
+// replay returns the response of the first interaction not replayed yet
+// matching the method, url and body of the request, so the same request can
+// get different responses over the course of a test.
+func (c *Cassette) replay(req *http.Request, recorded CassetteRequest) (*http.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, interaction := range c.Interactions {
+		if c.used[i] || interaction.Request != recorded {
+			continue
+		}
+		c.used[i] = true
+		header := interaction.Response.Header.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewBufferString(interaction.Response.Body)),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("no interaction recorded in cassette %s for %s %s", c.Path, recorded.Method, recorded.URL)
+}
+
+// Save writes the recorded interactions to the cassette file.
+func (c *Cassette) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	b, err := json.MarshalIndent(c.Interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.Path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(c.Path, append(b, '\n'), 0o600)
+}
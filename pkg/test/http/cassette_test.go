@@ -0,0 +1,59 @@
+package http
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestCassetteRecordAndReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := io.ReadAll(r.Body)
+		fmt.Fprintf(rw, "%s %s %d", r.Method, string(body), calls)
+	}))
+	defer server.Close()
+
+	get := func(client *http.Client, method, body string) string {
+		req, err := http.NewRequest(method, server.URL+"/path", strings.NewReader(body))
+		assert.NilError(t, err)
+		req.Header.Set("Authorization", "Bearer secret")
+		resp, err := client.Do(req)
+		assert.NilError(t, err)
+		defer resp.Body.Close()
+		b, err := io.ReadAll(resp.Body)
+		assert.NilError(t, err)
+		return string(b)
+	}
+
+	t.Run("record", func(t *testing.T) {
+		client := &http.Client{Transport: NewCassette(t, path, ModeRecord)}
+		assert.Equal(t, get(client, http.MethodGet, ""), "GET  1")
+		assert.Equal(t, get(client, http.MethodPost, "hello"), "POST hello 2")
+		assert.Equal(t, get(client, http.MethodGet, ""), "GET  3")
+	})
+
+	t.Run("replay", func(t *testing.T) {
+		cassette := NewCassette(t, path, ModeReplay)
+		assert.Equal(t, len(cassette.Interactions), 3)
+		for _, interaction := range cassette.Interactions {
+			assert.Equal(t, interaction.Response.Header.Get("Authorization"), "")
+		}
+		client := &http.Client{Transport: cassette}
+		assert.Equal(t, get(client, http.MethodPost, "hello"), "POST hello 2")
+		assert.Equal(t, get(client, http.MethodGet, ""), "GET  1")
+		assert.Equal(t, get(client, http.MethodGet, ""), "GET  3")
+		assert.Equal(t, calls, 3)
+
+		_, err := client.Get(server.URL + "/path")
+		assert.ErrorContains(t, err, "no interaction recorded in cassette")
+	})
+}
@@ -0,0 +1,49 @@
+package http
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+// Recorder records the body of the requests sent to a fake API server, so a
+// test can check what a provider has sent to it (ie: statuses or comments).
+type Recorder struct {
+	mu     sync.Mutex
+	bodies [][]byte
+}
+
+// Handler returns a handler recording the body of every POST or PUT request
+// and replying with reply.
+func (r *Recorder) Handler(t *testing.T, reply string) http.HandlerFunc {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost && req.Method != http.MethodPut {
+			http.Error(rw, fmt.Sprintf("method %s is not supported", req.Method), http.StatusMethodNotAllowed)
+			return
+		}
+		body, err := io.ReadAll(req.Body)
+		assert.NilError(t, err)
+		r.mu.Lock()
+		r.bodies = append(r.bodies, body)
+		r.mu.Unlock()
+		fmt.Fprint(rw, reply)
+	}
+}
+
+// Len returns the number of recorded requests.
+func (r *Recorder) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.bodies)
+}
+
+// Bodies returns a copy of the recorded bodies.
+func (r *Recorder) Bodies() [][]byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([][]byte{}, r.bodies...)
+}
@@ -18,6 +18,10 @@ type KinterfaceTest struct {
 	ExpectedNumberofCleanups int
 	GetSecretResult          map[string]string
 	GetPodLogsOutput         map[string]string
+	// AttachedSecrets are the ServiceAccounts the secrets are attached to.
+	AttachedSecrets map[string]string
+	// CreatedSecrets are the names of the secrets created and not deleted.
+	CreatedSecrets []string
 }
 
 var _ kubeinteraction.Interface = (*KinterfaceTest)(nil)
@@ -40,6 +44,19 @@ func (k *KinterfaceTest) UpdateSecretWithOwnerRef(_ context.Context, _ *zap.Suga
 	return nil
 }
 
+func (k *KinterfaceTest) AttachSecretToServiceAccount(_ context.Context, _, serviceAccount, secretName string) error {
+	if k.AttachedSecrets == nil {
+		k.AttachedSecrets = map[string]string{}
+	}
+	k.AttachedSecrets[secretName] = serviceAccount
+	return nil
+}
+
+func (k *KinterfaceTest) DetachSecretFromServiceAccount(_ context.Context, _, _, secretName string) error {
+	delete(k.AttachedSecrets, secretName)
+	return nil
+}
+
 func (k *KinterfaceTest) GetSecret(ctx context.Context, secret ktypes.GetSecretOpt) (string, error) {
 	// check if secret exist in k.GetSecretResult
 	if k.GetSecretResult[secret.Name] == "" {
@@ -57,10 +74,17 @@ func (k *KinterfaceTest) CleanupPipelines(_ context.Context, _ *zap.SugaredLogge
 	return nil
 }
 
-func (k *KinterfaceTest) CreateSecret(_ context.Context, _ string, _ *corev1.Secret) error {
+func (k *KinterfaceTest) CreateSecret(_ context.Context, _ string, secret *corev1.Secret) error {
+	k.CreatedSecrets = append(k.CreatedSecrets, secret.GetName())
 	return nil
 }
 
-func (k *KinterfaceTest) DeleteSecret(_ context.Context, _ *zap.SugaredLogger, _, _ string) error {
+func (k *KinterfaceTest) DeleteSecret(_ context.Context, _ *zap.SugaredLogger, _, secretName string) error {
+	for i, name := range k.CreatedSecrets {
+		if name == secretName {
+			k.CreatedSecrets = append(k.CreatedSecrets[:i], k.CreatedSecrets[i+1:]...)
+			break
+		}
+	}
 	return nil
 }
@@ -3,13 +3,21 @@ package webhook
 import (
 	"context"
 	"fmt"
+	"net"
+	"net/url"
+	"strings"
 
+	"github.com/gobwas/glob"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cron"
 	pac "github.com/openshift-pipelines/pipelines-as-code/pkg/generated/listers/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
 	v1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"knative.dev/pkg/webhook"
 )
 
@@ -28,33 +36,257 @@ func (ac *reconciler) Admit(_ context.Context, request *v1.AdmissionRequest) *v1
 		return webhook.MakeErrorStatus("validation failed: %v", err)
 	}
 
-	exist, err := checkIfRepoExist(ac.pacLister, &repo, "")
+	if err := validateRouting(repo.Spec.Routing); err != nil {
+		return webhook.MakeErrorStatus("%v", err)
+	}
+
+	if repo.GetNamespace() == "" {
+		repo.SetNamespace(request.Namespace)
+	}
+	existing, err := checkIfRepoExist(ac.pacLister, &repo, "")
 	if err != nil {
 		return webhook.MakeErrorStatus("validation failed: %v", err)
 	}
 
-	if exist {
-		return webhook.MakeErrorStatus(fmt.Sprintf("repository already exist with url: %s", repo.Spec.URL))
+	if existing != nil {
+		// only the Repositories of a namespace can share a URL with a routing
+		if !repo.HasRouting() || existing.GetNamespace() != repo.GetNamespace() {
+			return webhook.MakeErrorStatus(fmt.Sprintf("repository already exist with url: %s", repo.Spec.URL))
+		}
+		return webhook.MakeErrorStatus(fmt.Sprintf("repository %s/%s already exist with url: %s and the same routing",
+			existing.GetNamespace(), existing.GetName(), repo.Spec.URL))
 	}
 
 	if repo.Spec.ConcurrencyLimit != nil && *repo.Spec.ConcurrencyLimit == 0 {
 		return webhook.MakeErrorStatus("concurrency limit must be greater than 0")
 	}
 
-	return &v1.AdmissionResponse{Allowed: true}
+	if err := validateEnvironments(repo.Spec.Environments); err != nil {
+		return webhook.MakeErrorStatus("%v", err)
+	}
+
+	if err := validateGitAuthSecret(repo.Spec.GitAuthSecret); err != nil {
+		return webhook.MakeErrorStatus("%v", err)
+	}
+
+	if err := validateIncomings(repo.Spec.Incomings); err != nil {
+		return webhook.MakeErrorStatus("%v", err)
+	}
+
+	if err := validateSchedules(repo.Spec.Schedules); err != nil {
+		return webhook.MakeErrorStatus("%v", err)
+	}
+
+	if err := validateEnv(repo.Spec.Env); err != nil {
+		return webhook.MakeErrorStatus("%v", err)
+	}
+
+	if err := validateStatusSinks(repo.Spec.StatusSinks); err != nil {
+		return webhook.MakeErrorStatus("%v", err)
+	}
+
+	if err := validateServiceAccounts(repo.Spec.ServiceAccounts); err != nil {
+		return webhook.MakeErrorStatus("%v", err)
+	}
+
+	switch repo.Spec.AnnotationPolicy {
+	case "", v1alpha1.AnnotationPolicyLenient, v1alpha1.AnnotationPolicyStrict:
+	default:
+		return webhook.MakeErrorStatus("annotation_policy must be %s or %s", v1alpha1.AnnotationPolicyLenient, v1alpha1.AnnotationPolicyStrict)
+	}
+
+	switch repo.Spec.CommentStrategy {
+	case "", v1alpha1.CommentStrategyThrottled:
+	default:
+		return webhook.MakeErrorStatus("comment_strategy must be empty or %s", v1alpha1.CommentStrategyThrottled)
+	}
+
+	response := &v1.AdmissionResponse{Allowed: true}
+	if repo.InsecureSkipTLSVerify() {
+		response.Warnings = append(response.Warnings,
+			"spec.git_provider.insecure_skip_tls_verify is set, the TLS certificate of the git provider will not be verified, this is insecure and should only be used for testing")
+	}
+	return response
+}
+
+// validateEnvironments checks the environments have a unique name and valid
+// branch globs.
+func validateEnvironments(environments []v1alpha1.Environment) error {
+	names := map[string]bool{}
+	for _, env := range environments {
+		if env.Name == "" {
+			return fmt.Errorf("environment name cannot be empty")
+		}
+		if names[env.Name] {
+			return fmt.Errorf("environment %s is defined more than once", env.Name)
+		}
+		names[env.Name] = true
+		for _, branch := range env.Branches {
+			if _, err := glob.Compile(strings.TrimPrefix(branch, "refs/heads/")); err != nil {
+				return fmt.Errorf("environment %s has an invalid branch pattern %q: %w", env.Name, branch, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateServiceAccounts checks the ServiceAccount mappings have a valid
+// ServiceAccount name and valid branch globs.
+func validateServiceAccounts(mappings []v1alpha1.ServiceAccountMapping) error {
+	for _, mapping := range mappings {
+		if mapping.ServiceAccount == "" {
+			return fmt.Errorf("service_accounts: the service_account cannot be empty")
+		}
+		if err := settings.ValidateServiceAccountName(mapping.ServiceAccount); err != nil {
+			return fmt.Errorf("service_accounts: %w", err)
+		}
+		for _, branch := range mapping.Branches {
+			if _, err := glob.Compile(strings.TrimPrefix(branch, "refs/heads/")); err != nil {
+				return fmt.Errorf("service_accounts: %s has an invalid branch pattern %q: %w", mapping.ServiceAccount, branch, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateEnv checks the environment variables have a unique valid name and
+// either a value or a source.
+func validateEnv(env []corev1.EnvVar) error {
+	names := map[string]bool{}
+	for _, e := range env {
+		if len(validation.IsEnvVarName(e.Name)) > 0 {
+			return fmt.Errorf("invalid environment variable name %q, it must only have letters, digits, '_', '-' or '.' and not start with a digit", e.Name)
+		}
+		if names[e.Name] {
+			return fmt.Errorf("environment variable %s is defined more than once", e.Name)
+		}
+		names[e.Name] = true
+		if e.Value != "" && e.ValueFrom != nil {
+			return fmt.Errorf("environment variable %s cannot have both a value and a valueFrom", e.Name)
+		}
+	}
+	return nil
+}
+
+// validateIncomings checks the source networks of the incoming webhooks are
+// valid CIDRs.
+func validateIncomings(incomings *[]v1alpha1.Incoming) error {
+	if incomings == nil {
+		return nil
+	}
+	for _, incoming := range *incomings {
+		for _, cidr := range incoming.SourceCIDRs {
+			if _, _, err := net.ParseCIDR(cidr); err != nil {
+				return fmt.Errorf("incoming webhook has an invalid source cidr %s: %w", cidr, err)
+			}
+		}
+	}
+	return nil
 }
 
-func checkIfRepoExist(pac pac.RepositoryLister, repo *v1alpha1.Repository, ns string) (bool, error) {
+// validateStatusSinks checks the status sinks have a unique name, a supported
+// type and an absolute URL, the mirrors need a secret with their token.
+func validateStatusSinks(sinks []v1alpha1.StatusSink) error {
+	names := map[string]bool{}
+	for _, sink := range sinks {
+		if sink.Name == "" {
+			return fmt.Errorf("status sink name cannot be empty")
+		}
+		if names[sink.Name] {
+			return fmt.Errorf("status sink %s is defined more than once", sink.Name)
+		}
+		names[sink.Name] = true
+		switch sink.Type {
+		case v1alpha1.StatusSinkTypeWebhook:
+		case "github", "gitlab", "gitea":
+			if sink.Secret == nil || sink.Secret.Name == "" {
+				return fmt.Errorf("status sink %s needs a secret with the token of its git provider", sink.Name)
+			}
+		default:
+			return fmt.Errorf("status sink %s has an unsupported type %q, it needs to be webhook, github, gitlab or gitea", sink.Name, sink.Type)
+		}
+		if u, err := url.Parse(sink.URL); err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("status sink %s has an invalid url %q", sink.Name, sink.URL)
+		}
+	}
+	return nil
+}
+
+// validateSchedules checks the schedules have a unique name usable in a
+// label, a valid cron expression and a Pipeline.
+func validateSchedules(schedules []v1alpha1.Schedule) error {
+	names := map[string]bool{}
+	for _, schedule := range schedules {
+		if errs := validation.IsDNS1123Label(schedule.Name); len(errs) > 0 {
+			return fmt.Errorf("schedule name %q is invalid, it needs to be made of lowercase alphanumeric characters or '-'", schedule.Name)
+		}
+		if names[schedule.Name] {
+			return fmt.Errorf("schedule %s is defined more than once", schedule.Name)
+		}
+		names[schedule.Name] = true
+		if _, err := cron.Parse(schedule.Cron); err != nil {
+			return fmt.Errorf("schedule %s: %w", schedule.Name, err)
+		}
+		if schedule.Pipeline == "" {
+			return fmt.Errorf("schedule %s has no pipeline", schedule.Name)
+		}
+	}
+	return nil
+}
+
+// validateGitAuthSecret checks the git-auth secret policy gives valid secret
+// and ServiceAccount names.
+func validateGitAuthSecret(policy *v1alpha1.GitAuthSecret) error {
+	if policy == nil {
+		return nil
+	}
+	if policy.NamePrefix != "" {
+		if err := settings.ValidateSecretNamePrefix(policy.NamePrefix); err != nil {
+			return fmt.Errorf("git_auth_secret: %w", err)
+		}
+	}
+	if policy.ServiceAccount != "" {
+		if err := settings.ValidateServiceAccountName(policy.ServiceAccount); err != nil {
+			return fmt.Errorf("git_auth_secret: %w", err)
+		}
+	}
+	if policy.Mode != "" {
+		if err := settings.ValidateSecretMode(policy.Mode); err != nil {
+			return fmt.Errorf("git_auth_secret: invalid mode %s, %w", policy.Mode, err)
+		}
+	}
+	return nil
+}
+
+// checkIfRepoExist returns another Repository declaring the same URL in another
+// namespace or with the same routing, the Repositories declaring the same URL
+// need to be in the same namespace and to have different routings for the
+// events to be routed to one of them.
+func checkIfRepoExist(pac pac.RepositoryLister, repo *v1alpha1.Repository, ns string) (*v1alpha1.Repository, error) {
 	repositories, err := pac.Repositories(ns).List(labels.NewSelector())
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 	for i := len(repositories) - 1; i >= 0; i-- {
 		repoFromCluster := repositories[i]
 		if repoFromCluster.Spec.URL == repo.Spec.URL &&
-			(repoFromCluster.Name != repo.Name || repoFromCluster.Namespace != repo.Namespace) {
-			return true, nil
+			(repoFromCluster.Name != repo.Name || repoFromCluster.Namespace != repo.Namespace) &&
+			(repoFromCluster.Namespace != repo.Namespace || v1alpha1.SameRouting(repoFromCluster, repo)) {
+			return repoFromCluster, nil
+		}
+	}
+	return nil, nil
+}
+
+// validateRouting checks the routing has valid branch globs.
+func validateRouting(routing *v1alpha1.Routing) error {
+	if routing == nil {
+		return nil
+	}
+	for _, branch := range routing.Branches {
+		if _, err := glob.Compile(strings.TrimPrefix(branch, "refs/heads/")); err != nil {
+			return fmt.Errorf("routing has an invalid branch pattern %q: %w", branch, err)
 		}
 	}
-	return false, nil
+	return nil
 }
@@ -9,16 +9,18 @@ import (
 	testnewrepo "github.com/openshift-pipelines/pipelines-as-code/pkg/test/repository"
 	"gotest.tools/v3/assert"
 	v1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	rtesting "knative.dev/pkg/reconciler/testing"
 )
 
 func TestReconciler_Admit(t *testing.T) {
 	tests := []struct {
-		name    string
-		repo    *v1alpha1.Repository
-		allowed bool
-		result  string
+		name     string
+		repo     *v1alpha1.Repository
+		allowed  bool
+		result   string
+		warnings int
 	}{
 		{
 			name: "allow",
@@ -59,6 +61,333 @@ func TestReconciler_Admit(t *testing.T) {
 			allowed: false,
 			result:  "repository already exist with url: https://pac.test/already/installed",
 		},
+		{
+			name: "allow with a warning on insecure tls",
+			repo: func() *v1alpha1.Repository {
+				repo := testnewrepo.NewRepo(testnewrepo.RepoTestcreationOpts{
+					Name:             "test-run",
+					InstallNamespace: "namespace",
+					URL:              "https://gitea.lab/org/repo",
+				})
+				repo.Spec.GitProvider = &v1alpha1.GitProvider{InsecureSkipTLSVerify: true}
+				return repo
+			}(),
+			allowed:  true,
+			warnings: 1,
+		},
+		{
+			name: "reject duplicate environments",
+			repo: func() *v1alpha1.Repository {
+				repo := testnewrepo.NewRepo(testnewrepo.RepoTestcreationOpts{
+					Name:             "test-run",
+					InstallNamespace: "namespace",
+					URL:              "https://github.com/owner/repo",
+				})
+				repo.Spec.Environments = []v1alpha1.Environment{
+					{Name: "prod", Branches: []string{"main"}},
+					{Name: "prod", Branches: []string{"release-*"}},
+				}
+				return repo
+			}(),
+			allowed: false,
+			result:  "environment prod is defined more than once",
+		},
+		{
+			name: "reject invalid environment branch pattern",
+			repo: func() *v1alpha1.Repository {
+				repo := testnewrepo.NewRepo(testnewrepo.RepoTestcreationOpts{
+					Name:             "test-run",
+					InstallNamespace: "namespace",
+					URL:              "https://github.com/owner/repo",
+				})
+				repo.Spec.Environments = []v1alpha1.Environment{
+					{Name: "prod", Branches: []string{"[release-"}},
+				}
+				return repo
+			}(),
+			allowed: false,
+			result:  `environment prod has an invalid branch pattern "[release-": unexpected end of input`,
+		},
+		{
+			name: "reject invalid git auth secret mode",
+			repo: func() *v1alpha1.Repository {
+				repo := testnewrepo.NewRepo(testnewrepo.RepoTestcreationOpts{
+					Name:             "test-run",
+					InstallNamespace: "namespace",
+					URL:              "https://github.com/owner/repo",
+				})
+				repo.Spec.GitAuthSecret = &v1alpha1.GitAuthSecret{ServiceAccount: "builder", Mode: "env"}
+				return repo
+			}(),
+			allowed: false,
+			result:  "git_auth_secret: invalid mode env, acceptable values: workspace or gitconfig",
+		},
+		{
+			name: "reject invalid incoming source cidr",
+			repo: func() *v1alpha1.Repository {
+				repo := testnewrepo.NewRepo(testnewrepo.RepoTestcreationOpts{
+					Name:             "test-run",
+					InstallNamespace: "namespace",
+					URL:              "https://github.com/owner/repo",
+				})
+				repo.Spec.Incomings = &[]v1alpha1.Incoming{
+					{Targets: []string{"main"}, SourceCIDRs: []string{"10.0.0.1"}},
+				}
+				return repo
+			}(),
+			allowed: false,
+			result:  "incoming webhook has an invalid source cidr 10.0.0.1: invalid CIDR address: 10.0.0.1",
+		},
+		{
+			name: "reject invalid schedule cron",
+			repo: func() *v1alpha1.Repository {
+				repo := testnewrepo.NewRepo(testnewrepo.RepoTestcreationOpts{
+					Name:             "test-run",
+					InstallNamespace: "namespace",
+					URL:              "https://github.com/owner/repo",
+				})
+				repo.Spec.Schedules = []v1alpha1.Schedule{
+					{Name: "prune", Cron: "0 25 * * *", Pipeline: "prune-previews"},
+				}
+				return repo
+			}(),
+			allowed: false,
+			result:  `schedule prune: invalid cron expression "0 25 * * *": invalid hour "25", it needs to be between 0 and 23`,
+		},
+		{
+			name: "reject schedule defined twice",
+			repo: func() *v1alpha1.Repository {
+				repo := testnewrepo.NewRepo(testnewrepo.RepoTestcreationOpts{
+					Name:             "test-run",
+					InstallNamespace: "namespace",
+					URL:              "https://github.com/owner/repo",
+				})
+				repo.Spec.Schedules = []v1alpha1.Schedule{
+					{Name: "prune", Cron: "@daily", Pipeline: "prune-previews"},
+					{Name: "prune", Cron: "@weekly", Pipeline: "prune-previews"},
+				}
+				return repo
+			}(),
+			allowed: false,
+			result:  "schedule prune is defined more than once",
+		},
+		{
+			name: "reject duplicate environment variables",
+			repo: func() *v1alpha1.Repository {
+				repo := testnewrepo.NewRepo(testnewrepo.RepoTestcreationOpts{
+					Name:             "test-run",
+					InstallNamespace: "namespace",
+					URL:              "https://github.com/owner/repo",
+				})
+				repo.Spec.Env = []corev1.EnvVar{
+					{Name: "HTTPS_PROXY", Value: "http://proxy:3128"},
+					{Name: "HTTPS_PROXY", Value: "http://other:3128"},
+				}
+				return repo
+			}(),
+			allowed: false,
+			result:  "environment variable HTTPS_PROXY is defined more than once",
+		},
+		{
+			name: "reject invalid environment variable name",
+			repo: func() *v1alpha1.Repository {
+				repo := testnewrepo.NewRepo(testnewrepo.RepoTestcreationOpts{
+					Name:             "test-run",
+					InstallNamespace: "namespace",
+					URL:              "https://github.com/owner/repo",
+				})
+				repo.Spec.Env = []corev1.EnvVar{{Name: "1PROXY", Value: "http://proxy:3128"}}
+				return repo
+			}(),
+			allowed: false,
+			result:  `invalid environment variable name "1PROXY", it must only have letters, digits, '_', '-' or '.' and not start with a digit`,
+		},
+		{
+			name: "reject unknown annotation policy",
+			repo: func() *v1alpha1.Repository {
+				repo := testnewrepo.NewRepo(testnewrepo.RepoTestcreationOpts{
+					Name:             "test-run",
+					InstallNamespace: "namespace",
+					URL:              "https://github.com/owner/repo",
+				})
+				repo.Spec.AnnotationPolicy = "paranoid"
+				return repo
+			}(),
+			allowed: false,
+			result:  "annotation_policy must be lenient or strict",
+		},
+		{
+			name: "reject unknown comment strategy",
+			repo: func() *v1alpha1.Repository {
+				repo := testnewrepo.NewRepo(testnewrepo.RepoTestcreationOpts{
+					Name:             "test-run",
+					InstallNamespace: "namespace",
+					URL:              "https://github.com/owner/repo",
+				})
+				repo.Spec.CommentStrategy = "quiet"
+				return repo
+			}(),
+			allowed: false,
+			result:  "comment_strategy must be empty or throttled",
+		},
+		{
+			name: "allow status sinks",
+			repo: func() *v1alpha1.Repository {
+				repo := testnewrepo.NewRepo(testnewrepo.RepoTestcreationOpts{
+					Name:             "test-run",
+					InstallNamespace: "namespace",
+					URL:              "https://github.com/owner/repo",
+				})
+				repo.Spec.StatusSinks = []v1alpha1.StatusSink{
+					{Name: "status-api", Type: "webhook", URL: "https://status.example.com/api"},
+					{Name: "mirror", Type: "gitlab", URL: "https://gitlab.com/owner/repo", Secret: &v1alpha1.Secret{Name: "gitlab-token"}},
+				}
+				return repo
+			}(),
+			allowed: true,
+			result:  "",
+		},
+		{
+			name: "reject status sink mirror without a secret",
+			repo: func() *v1alpha1.Repository {
+				repo := testnewrepo.NewRepo(testnewrepo.RepoTestcreationOpts{
+					Name:             "test-run",
+					InstallNamespace: "namespace",
+					URL:              "https://github.com/owner/repo",
+				})
+				repo.Spec.StatusSinks = []v1alpha1.StatusSink{{Name: "mirror", Type: "gitlab", URL: "https://gitlab.com/owner/repo"}}
+				return repo
+			}(),
+			allowed: false,
+			result:  "status sink mirror needs a secret with the token of its git provider",
+		},
+		{
+			name: "reject status sink with an unsupported type",
+			repo: func() *v1alpha1.Repository {
+				repo := testnewrepo.NewRepo(testnewrepo.RepoTestcreationOpts{
+					Name:             "test-run",
+					InstallNamespace: "namespace",
+					URL:              "https://github.com/owner/repo",
+				})
+				repo.Spec.StatusSinks = []v1alpha1.StatusSink{{Name: "mirror", Type: "bitbucket-cloud", URL: "https://bitbucket.org/owner/repo"}}
+				return repo
+			}(),
+			allowed: false,
+			result:  `status sink mirror has an unsupported type "bitbucket-cloud", it needs to be webhook, github, gitlab or gitea`,
+		},
+		{
+			name: "reject status sink with a relative url",
+			repo: func() *v1alpha1.Repository {
+				repo := testnewrepo.NewRepo(testnewrepo.RepoTestcreationOpts{
+					Name:             "test-run",
+					InstallNamespace: "namespace",
+					URL:              "https://github.com/owner/repo",
+				})
+				repo.Spec.StatusSinks = []v1alpha1.StatusSink{{Name: "status-api", Type: "webhook", URL: "/api"}}
+				return repo
+			}(),
+			allowed: false,
+			result:  `status sink status-api has an invalid url "/api"`,
+		},
+		{
+			name: "allow service account mappings",
+			repo: func() *v1alpha1.Repository {
+				repo := testnewrepo.NewRepo(testnewrepo.RepoTestcreationOpts{
+					Name:             "test-run",
+					InstallNamespace: "namespace",
+					URL:              "https://github.com/owner/repo",
+				})
+				repo.Spec.ServiceAccounts = []v1alpha1.ServiceAccountMapping{
+					{EventType: "push", Branches: []string{"main"}, ServiceAccount: "deploy"},
+					{EventType: "pull_request", ServiceAccount: "restricted"},
+				}
+				return repo
+			}(),
+			allowed: true,
+		},
+		{
+			name: "reject invalid service account name",
+			repo: func() *v1alpha1.Repository {
+				repo := testnewrepo.NewRepo(testnewrepo.RepoTestcreationOpts{
+					Name:             "test-run",
+					InstallNamespace: "namespace",
+					URL:              "https://github.com/owner/repo",
+				})
+				repo.Spec.ServiceAccounts = []v1alpha1.ServiceAccountMapping{{EventType: "push", ServiceAccount: "Deploy SA"}}
+				return repo
+			}(),
+			allowed: false,
+			result:  "service_accounts: invalid service account name Deploy SA, it needs to be made of lowercase alphanumeric characters, '-' or '.'",
+		},
+		{
+			name: "reject invalid service account branch pattern",
+			repo: func() *v1alpha1.Repository {
+				repo := testnewrepo.NewRepo(testnewrepo.RepoTestcreationOpts{
+					Name:             "test-run",
+					InstallNamespace: "namespace",
+					URL:              "https://github.com/owner/repo",
+				})
+				repo.Spec.ServiceAccounts = []v1alpha1.ServiceAccountMapping{{Branches: []string{"[release-"}, ServiceAccount: "deploy"}}
+				return repo
+			}(),
+			allowed: false,
+			result:  `service_accounts: deploy has an invalid branch pattern "[release-": unexpected end of input`,
+		},
+		{
+			name: "allow a routed repo on the url of an existing repo",
+			repo: func() *v1alpha1.Repository {
+				repo := testnewrepo.NewRepo(testnewrepo.RepoTestcreationOpts{
+					Name:             "test-run",
+					InstallNamespace: "namespace",
+					URL:              "https://pac.test/already/installed",
+				})
+				repo.Spec.Routing = &v1alpha1.Routing{Branches: []string{"release-*"}}
+				return repo
+			}(),
+			allowed: true,
+		},
+		{
+			name: "reject a routed repo on the url of a repo of another namespace",
+			repo: func() *v1alpha1.Repository {
+				repo := testnewrepo.NewRepo(testnewrepo.RepoTestcreationOpts{
+					Name:             "test-run",
+					InstallNamespace: "other",
+					URL:              "https://pac.test/already/installed",
+				})
+				repo.Spec.Routing = &v1alpha1.Routing{Branches: []string{"*"}}
+				return repo
+			}(),
+			allowed: false,
+			result:  "repository already exist with url: https://pac.test/already/installed",
+		},
+		{
+			name: "reject a repo with the same routing on the same url",
+			repo: func() *v1alpha1.Repository {
+				repo := testnewrepo.NewRepo(testnewrepo.RepoTestcreationOpts{
+					Name:             "test-run",
+					InstallNamespace: "namespace",
+					URL:              "https://pac.test/already/routed",
+				})
+				repo.Spec.Routing = &v1alpha1.Routing{EventTypes: []string{"push"}, Branches: []string{"main"}}
+				return repo
+			}(),
+			allowed: false,
+			result:  "repository namespace/test-repo-already-routed already exist with url: https://pac.test/already/routed and the same routing",
+		},
+		{
+			name: "reject invalid routing branch pattern",
+			repo: func() *v1alpha1.Repository {
+				repo := testnewrepo.NewRepo(testnewrepo.RepoTestcreationOpts{
+					Name:             "test-run",
+					InstallNamespace: "namespace",
+					URL:              "https://github.com/owner/repo",
+				})
+				repo.Spec.Routing = &v1alpha1.Routing{Branches: []string{"[release-"}}
+				return repo
+			}(),
+			allowed: false,
+			result:  `routing has an invalid branch pattern "[release-": unexpected end of input`,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -69,7 +398,13 @@ func TestReconciler_Admit(t *testing.T) {
 				InstallNamespace: "namespace",
 				URL:              "https://pac.test/already/installed",
 			})
-			tdata := testclient.Data{Repositories: []*v1alpha1.Repository{alreadyInstalledRepo}}
+			alreadyRoutedRepo := testnewrepo.NewRepo(testnewrepo.RepoTestcreationOpts{
+				Name:             "test-repo-already-routed",
+				InstallNamespace: "namespace",
+				URL:              "https://pac.test/already/routed",
+			})
+			alreadyRoutedRepo.Spec.Routing = &v1alpha1.Routing{EventTypes: []string{"push"}, Branches: []string{"main"}}
+			tdata := testclient.Data{Repositories: []*v1alpha1.Repository{alreadyInstalledRepo, alreadyRoutedRepo}}
 			stdata, _ := testclient.SeedTestData(t, ctx, tdata)
 
 			r := reconciler{
@@ -82,6 +417,7 @@ func TestReconciler_Admit(t *testing.T) {
 			res := r.Admit(ctx, req)
 
 			assert.Equal(t, res.Allowed, tt.allowed)
+			assert.Equal(t, len(res.Warnings), tt.warnings)
 			if !res.Allowed {
 				assert.Equal(t, res.Result.Message, tt.result)
 			}